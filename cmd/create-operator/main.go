@@ -0,0 +1,78 @@
+// Command create-operator creates a back-office operator account, which can't be created through the API.
+//
+// Usage:
+//
+//	OPERATOR_SECRET=<secret> create-operator -name "Seymour Skinner" -cpf 999.999.999-99 -role admin
+//
+// The secret is read from the standard input when OPERATOR_SECRET is not set.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/infraestructure/logging"
+)
+
+func main() {
+	name := flag.String("name", "", "operator name")
+	cpf := flag.String("cpf", "", "operator CPF, used to login")
+	role := flag.String("role", string(model.RoleOperator), "operator role: operator or admin")
+	flag.Parse()
+
+	conf := config.ReadConfig("config/.env")
+
+	logging.InitZeroLog(conf.Log.Level, conf.Log.Encoding)
+
+	secret, err := readSecret()
+	if err != nil {
+		log.Fatal().Stack().Err(err).Msg("error reading operator secret")
+	}
+
+	dbPool, err := postgres.ConnectPool(conf.Postgres)
+	if err != nil {
+		log.Fatal().Stack().Err(err).Msg("error connecting to db")
+	}
+	defer dbPool.Close()
+
+	accUC := usecase.NewAccountUseCase(postgres.NewAccountRepository(dbPool), postgres.NewLedgerRepository(dbPool))
+
+	output, err := accUC.Create(log.Logger.WithContext(context.Background()), usecase.AccountCreateInput{
+		Name:   *name,
+		CPF:    *cpf,
+		Secret: secret,
+		Roles:  []model.Role{model.Role(*role)},
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("error creating operator")
+		dbPool.Close()
+		os.Exit(1)
+	}
+
+	fmt.Printf("operator %s created with id %s and role %s\n", output.Name, output.ID, *role)
+}
+
+func readSecret() (string, error) {
+	if secret, ok := os.LookupEnv("OPERATOR_SECRET"); ok {
+		return secret, nil
+	}
+
+	fmt.Fprint(os.Stderr, "secret: ")
+	secret, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	return strings.TrimRight(secret, "\r\n"), nil
+}
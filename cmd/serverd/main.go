@@ -3,9 +3,12 @@
 // @description GO Springfield Bank API simulates a digital bank where you can create and fetch accounts, login with your account and transfer money to other accounts.
 // @description ### Authorization
 // @description You can get the access_token returned from `/login`, click the **Authorize** button and input this format `Bearer <access_token>`. After this, the `Authorization` header will be sent along in your next requests.
-// @description The JWT access token has short expiration, so maybe you have to log in again to get a new `access_token`.
+// @description The JWT access token has short expiration, so use the `refresh_token` at `/token/refresh` to get a new `access_token`.
+// @description The public keys to verify the access tokens are available at `/.well-known/jwks.json`.
 // @description ### X-Idempotency-Key
 // @description If you send the `X-Idempotency-Key` header along with a request, that request's response will be cached. So, if you send the same request with the same `X-Idempotency-Key` again, the server will respond the cached response, so no processing will be done twice.
+// @description ### Amounts
+// @description Amounts are accepted as decimal strings, like `"1234.56"`, or as JSON numbers, with at most 2 decimal places. Send the `Accept: application/vnd.springfield-bank.v2+json` header to receive the amounts as decimal strings too. Amounts returned as JSON numbers are deprecated.
 
 // @contact.name Helder Alves
 // @contact.url https://github.com/helder-jaspion/go-springfield-bank/
@@ -20,16 +23,25 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/helder-jaspion/go-springfield-bank/api"
 	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
 	redisGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/redis"
 	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/worker"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/infraestructure/logging"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/infraestructure/monitoring"
 )
@@ -56,9 +68,64 @@ func main() {
 
 	go monitoring.RunServer(conf.Monitoring.Port, dbPool, redisClient)
 
+	limitPolicy, err := newTransferLimitPolicy(conf.TransferLimits)
+	if err != nil {
+		log.Fatal().Stack().Err(err).Msg("error reading transfer limits")
+	}
+
+	interestPolicy, err := newOverdraftInterestPolicy(conf.Overdraft)
+	if err != nil {
+		log.Fatal().Stack().Err(err).Msg("error reading overdraft interest")
+	}
+	if interestPolicy.RevenueAccountID != "" {
+		_, err = postgres.NewAccountRepository(dbPool).GetBalance(context.Background(), interestPolicy.RevenueAccountID)
+		if err != nil {
+			log.Fatal().Stack().Err(err).Str("accountID", string(interestPolicy.RevenueAccountID)).Msg("error getting overdraft revenue account")
+		}
+	}
+
+	feePolicy, err := newFeePolicy(conf.Fees)
+	if err != nil {
+		log.Fatal().Stack().Err(err).Msg("error reading fees")
+	}
+	if feePolicy.RevenueAccountID != "" {
+		_, err = postgres.NewAccountRepository(dbPool).GetBalance(context.Background(), feePolicy.RevenueAccountID)
+		if err != nil {
+			log.Fatal().Stack().Err(err).Str("accountID", string(feePolicy.RevenueAccountID)).Msg("error getting fees revenue account")
+		}
+	}
+
+	savingsPolicy, err := newSavingsPolicy(conf.Savings)
+	if err != nil {
+		log.Fatal().Stack().Err(err).Msg("error reading savings interest")
+	}
+	if savingsPolicy.TreasuryAccountID != "" {
+		_, err = postgres.NewAccountRepository(dbPool).GetBalance(context.Background(), savingsPolicy.TreasuryAccountID)
+		if err != nil {
+			log.Fatal().Stack().Err(err).Str("accountID", string(savingsPolicy.TreasuryAccountID)).Msg("error getting savings treasury account")
+		}
+	}
+
+	if conf.Scheduler.Enabled {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		go worker.GetScheduledTransferExecutor(dbPool, conf.Scheduler, limitPolicy, feePolicy).Run(ctx)
+		go worker.GetStandingOrderExecutor(dbPool, conf.Scheduler, limitPolicy, feePolicy).Run(ctx)
+		go worker.GetOverdraftInterestExecutor(dbPool, conf.Scheduler, interestPolicy).Run(ctx)
+		go worker.GetPaymentRequestExecutor(dbPool, conf.Scheduler, limitPolicy, feePolicy).Run(ctx)
+		go worker.GetMaintenanceFeeExecutor(dbPool, conf.Scheduler, feePolicy).Run(ctx)
+		go worker.GetSavingsInterestExecutor(dbPool, conf.Scheduler, savingsPolicy).Run(ctx)
+	}
+
 	api.SwaggerInfo.Host = conf.API.Host
 
-	handler := httpGateway.GetHTTPHandler(dbPool, redisClient, conf.Auth)
+	handler := httpGateway.GetHTTPHandler(dbPool, redisClient, conf.Auth, httpGateway.HandlerOptions{
+		FX:             conf.FX,
+		TransferLimits: limitPolicy,
+		Fees:           feePolicy,
+		Savings:        savingsPolicy,
+	})
 	server := &http.Server{
 		Addr:         ":" + conf.API.Port,
 		Handler:      handler,
@@ -69,3 +136,170 @@ func main() {
 
 	httpGateway.StartServer(server)
 }
+
+// newTransferLimitPolicy parses the default transfer limits and the time zone of their periods.
+func newTransferLimitPolicy(limitsConf config.ConfTransferLimits) (usecase.TransferLimitPolicy, error) {
+	var defaults model.TransferLimits
+	for _, limit := range []struct {
+		value string
+		money *model.Money
+	}{
+		{limitsConf.PerTransaction, &defaults.PerTransaction},
+		{limitsConf.Daily, &defaults.Daily},
+		{limitsConf.Monthly, &defaults.Monthly},
+		{limitsConf.NightPerTransaction, &defaults.NightPerTransaction},
+		{limitsConf.NightTotal, &defaults.NightTotal},
+	} {
+		money, err := model.ParseMoney(limit.value)
+		if err != nil {
+			return usecase.TransferLimitPolicy{}, fmt.Errorf("invalid transfer limit %q: %w", limit.value, err)
+		}
+		if money < 0 {
+			return usecase.TransferLimitPolicy{}, fmt.Errorf("invalid transfer limit %q: %w", limit.value, usecase.ErrTransferLimitNegative)
+		}
+		*limit.money = money
+	}
+
+	if limitsConf.NightStartHour < 0 || limitsConf.NightStartHour > 23 || limitsConf.NightEndHour < 0 || limitsConf.NightEndHour > 23 {
+		return usecase.TransferLimitPolicy{}, fmt.Errorf("invalid night hours %d-%d, they must be between 0 and 23", limitsConf.NightStartHour, limitsConf.NightEndHour)
+	}
+
+	location, err := time.LoadLocation(limitsConf.TimeZone)
+	if err != nil {
+		return usecase.TransferLimitPolicy{}, err
+	}
+
+	return usecase.TransferLimitPolicy{
+		Defaults: defaults,
+		Calendar: model.TransferLimitCalendar{
+			Location:       location,
+			NightStartHour: limitsConf.NightStartHour,
+			NightEndHour:   limitsConf.NightEndHour,
+		},
+	}, nil
+}
+
+// newOverdraftInterestPolicy parses the overdraft interest rate, its revenue account and the time zone of its days.
+func newOverdraftInterestPolicy(overdraftConf config.ConfOverdraft) (usecase.OverdraftInterestPolicy, error) {
+	rate, err := model.ParseInterestRate(overdraftConf.MonthlyInterestRate)
+	if err != nil {
+		return usecase.OverdraftInterestPolicy{}, fmt.Errorf("invalid overdraft interest rate %q: %w", overdraftConf.MonthlyInterestRate, err)
+	}
+
+	location, err := time.LoadLocation(overdraftConf.TimeZone)
+	if err != nil {
+		return usecase.OverdraftInterestPolicy{}, err
+	}
+
+	return usecase.OverdraftInterestPolicy{
+		MonthlyRate:      rate,
+		RevenueAccountID: model.AccountID(strings.TrimSpace(overdraftConf.RevenueAccountID)),
+		Location:         location,
+	}, nil
+}
+
+// newFeePolicy parses the fees, the tiers exempt from them and the time zone of their months.
+func newFeePolicy(feesConf config.ConfFees) (usecase.FeePolicy, error) {
+	var transferRule model.TransferFeeRule
+	var maintenanceRule model.MaintenanceFeeRule
+	for _, fee := range []struct {
+		value string
+		money *model.Money
+	}{
+		{feesConf.TransferFlat, &transferRule.Flat},
+		{feesConf.TransferMin, &transferRule.Min},
+		{feesConf.TransferMax, &transferRule.Max},
+		{feesConf.MaintenanceMonthly, &maintenanceRule.Monthly},
+	} {
+		money, err := model.ParseMoney(fee.value)
+		if err != nil || money < 0 {
+			return usecase.FeePolicy{}, fmt.Errorf("invalid fee %q, it must be a non-negative amount", fee.value)
+		}
+		*fee.money = money
+	}
+
+	rate, err := model.ParseInterestRate(feesConf.TransferRate)
+	if err != nil {
+		return usecase.FeePolicy{}, fmt.Errorf("invalid transfer fee rate %q: %w", feesConf.TransferRate, err)
+	}
+	transferRule.Rate = rate
+
+	if transferRule.Max > 0 && transferRule.Max < transferRule.Min {
+		return usecase.FeePolicy{}, fmt.Errorf("invalid transfer fee bounds %s-%s, the max must not be less than the min", transferRule.Min, transferRule.Max)
+	}
+	if feesConf.TransferFreePerMonth < 0 {
+		return usecase.FeePolicy{}, fmt.Errorf("invalid free transfers per month %d, it must not be negative", feesConf.TransferFreePerMonth)
+	}
+	transferRule.FreePerMonth = feesConf.TransferFreePerMonth
+
+	transferRule.ExemptTiers, err = parseAccountTiers(feesConf.TransferExemptTiers)
+	if err != nil {
+		return usecase.FeePolicy{}, err
+	}
+	maintenanceRule.ExemptTiers, err = parseAccountTiers(feesConf.MaintenanceExemptTiers)
+	if err != nil {
+		return usecase.FeePolicy{}, err
+	}
+
+	location, err := time.LoadLocation(feesConf.TimeZone)
+	if err != nil {
+		return usecase.FeePolicy{}, err
+	}
+
+	return usecase.FeePolicy{
+		RevenueAccountID: model.AccountID(strings.TrimSpace(feesConf.RevenueAccountID)),
+		Transfer:         transferRule,
+		Maintenance:      maintenanceRule,
+		Location:         location,
+	}, nil
+}
+
+// newSavingsPolicy parses the savings interest rate, the holidays and the time zone of its days.
+func newSavingsPolicy(savingsConf config.ConfSavings) (usecase.SavingsPolicy, error) {
+	rate, err := model.ParseInterestRate(savingsConf.AnnualRate)
+	if err != nil {
+		return usecase.SavingsPolicy{}, fmt.Errorf("invalid savings interest rate %q: %w", savingsConf.AnnualRate, err)
+	}
+
+	var holidays []time.Time
+	for _, value := range savingsConf.Holidays {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		holiday, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return usecase.SavingsPolicy{}, fmt.Errorf("invalid savings holiday %q, it must be a date (YYYY-MM-DD)", value)
+		}
+		holidays = append(holidays, holiday)
+	}
+
+	location, err := time.LoadLocation(savingsConf.TimeZone)
+	if err != nil {
+		return usecase.SavingsPolicy{}, err
+	}
+
+	return usecase.SavingsPolicy{
+		TreasuryAccountID: model.AccountID(strings.TrimSpace(savingsConf.TreasuryAccountID)),
+		AnnualRate:        rate,
+		Holidays:          holidays,
+		Location:          location,
+	}, nil
+}
+
+// parseAccountTiers parses the tiers, ignoring the empty ones.
+func parseAccountTiers(values []string) ([]model.AccountTier, error) {
+	var tiers []model.AccountTier
+	for _, value := range values {
+		tier := model.AccountTier(strings.ToLower(strings.TrimSpace(value)))
+		if tier == "" {
+			continue
+		}
+		if !tier.IsValid() {
+			return nil, fmt.Errorf("invalid account tier %q: %w", value, usecase.ErrAccountTierInvalid)
+		}
+		tiers = append(tiers, tier)
+	}
+
+	return tiers, nil
+}
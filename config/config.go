@@ -11,12 +11,18 @@ import (
 
 // Config the base config structure.
 type Config struct {
-	Log        ConfLog
-	API        ConfAPI
-	Monitoring ConfMonitoring
-	Postgres   ConfPostgres
-	Redis      ConfRedis
-	Auth       ConfAuth
+	Log            ConfLog
+	API            ConfAPI
+	Monitoring     ConfMonitoring
+	Postgres       ConfPostgres
+	Redis          ConfRedis
+	Auth           ConfAuth
+	Scheduler      ConfScheduler
+	TransferLimits ConfTransferLimits
+	Overdraft      ConfOverdraft
+	FX             ConfFX
+	Fees           ConfFees
+	Savings        ConfSavings
 }
 
 // ConfLog logging related configurations.
@@ -57,8 +63,89 @@ type ConfRedis struct {
 
 // ConfAuth Authentication related configurations.
 type ConfAuth struct {
-	SecretKey      string        `env:"AUTH_SECRET_KEY" env-default:"YOU-SHOULD-CHANGE-ME"`
-	AccessTokenDur time.Duration `env:"AUTH_ACCESS_TOKEN_DURATION" env-default:"15m"`
+	SecretKey       string            `env:"AUTH_SECRET_KEY" env-default:"YOU-SHOULD-CHANGE-ME"`
+	HS256Disabled   bool              `env:"AUTH_HS256_DISABLED" env-default:"false"`
+	KeyFiles        map[string]string `env:"AUTH_KEY_FILES"`
+	SigningKeyID    string            `env:"AUTH_SIGNING_KEY_ID" env-default:""`
+	AccessTokenDur  time.Duration     `env:"AUTH_ACCESS_TOKEN_DURATION" env-default:"15m"`
+	RefreshTokenDur time.Duration     `env:"AUTH_REFRESH_TOKEN_DURATION" env-default:"720h"`
+
+	LoginAttemptsStore     string        `env:"AUTH_LOGIN_ATTEMPTS_STORE" env-default:"redis"`
+	LoginMaxFailuresPerCPF int           `env:"AUTH_LOGIN_MAX_FAILURES_PER_CPF" env-default:"5"`
+	LoginMaxFailuresPerIP  int           `env:"AUTH_LOGIN_MAX_FAILURES_PER_IP" env-default:"20"`
+	LoginBaseLockout       time.Duration `env:"AUTH_LOGIN_BASE_LOCKOUT" env-default:"1m"`
+	LoginMaxLockout        time.Duration `env:"AUTH_LOGIN_MAX_LOCKOUT" env-default:"1h"`
+	LoginFailuresWindow    time.Duration `env:"AUTH_LOGIN_FAILURES_WINDOW" env-default:"24h"`
+}
+
+// ConfScheduler scheduled transfers, standing orders, overdraft interest, payment requests, maintenance fees and savings interest executors related configurations.
+type ConfScheduler struct {
+	Enabled            bool          `env:"SCHEDULER_ENABLED" env-default:"true"`
+	Interval           time.Duration `env:"SCHEDULER_INTERVAL" env-default:"1m"`
+	BatchSize          int           `env:"SCHEDULER_BATCH_SIZE" env-default:"100"`
+	StandingOrderRetry ConfStandingOrderRetry
+}
+
+// ConfStandingOrderRetry failed standing order occurrences retry related configurations.
+type ConfStandingOrderRetry struct {
+	MaxRetries int           `env:"STANDING_ORDER_MAX_RETRIES" env-default:"3"`
+	Interval   time.Duration `env:"STANDING_ORDER_RETRY_INTERVAL" env-default:"4h"`
+}
+
+// ConfTransferLimits default transfer limits related configurations.
+// The amounts are decimal strings, like "1000.00", and 0 means no limit.
+type ConfTransferLimits struct {
+	PerTransaction      string `env:"TRANSFER_LIMIT_PER_TRANSACTION" env-default:"5000.00"`
+	Daily               string `env:"TRANSFER_LIMIT_DAILY" env-default:"10000.00"`
+	Monthly             string `env:"TRANSFER_LIMIT_MONTHLY" env-default:"50000.00"`
+	NightPerTransaction string `env:"TRANSFER_LIMIT_NIGHT_PER_TRANSACTION" env-default:"1000.00"`
+	NightTotal          string `env:"TRANSFER_LIMIT_NIGHT_TOTAL" env-default:"1000.00"`
+	NightStartHour      int    `env:"TRANSFER_LIMIT_NIGHT_START_HOUR" env-default:"20"`
+	NightEndHour        int    `env:"TRANSFER_LIMIT_NIGHT_END_HOUR" env-default:"6"`
+	TimeZone            string `env:"TRANSFER_LIMIT_TIMEZONE" env-default:"America/Sao_Paulo"`
+}
+
+// ConfOverdraft overdraft interest related configurations.
+// The interest is only charged when RevenueAccountID is informed.
+type ConfOverdraft struct {
+	RevenueAccountID    string `env:"OVERDRAFT_REVENUE_ACCOUNT_ID" env-default:""`
+	MonthlyInterestRate string `env:"OVERDRAFT_MONTHLY_INTEREST_RATE" env-default:"8.00"`
+	TimeZone            string `env:"OVERDRAFT_TIMEZONE" env-default:"America/Sao_Paulo"`
+}
+
+// ConfFX currency conversion related configurations.
+// The rates are decimal strings keyed by currency pair, like USD/BRL:5.00 for 5.00 BRL per USD. When RatesFile is
+// informed, the rates are read from the JSON object in it instead.
+type ConfFX struct {
+	Rates     map[string]string `env:"FX_RATES" env-default:"USD/BRL:5.00,EUR/BRL:5.50,EUR/USD:1.10"`
+	RatesFile string            `env:"FX_RATES_FILE" env-default:""`
+	QuoteTTL  time.Duration     `env:"FX_QUOTE_TTL" env-default:"30s"`
+}
+
+// ConfFees bank fees related configurations.
+// The amounts are decimal strings, like "1.50", the rate is a percentage, like "0.50", and 0 means no fee.
+// The fees are only charged when RevenueAccountID is informed.
+type ConfFees struct {
+	RevenueAccountID       string   `env:"FEES_REVENUE_ACCOUNT_ID" env-default:""`
+	TransferFlat           string   `env:"FEES_TRANSFER_FLAT" env-default:"0.00"`
+	TransferRate           string   `env:"FEES_TRANSFER_RATE" env-default:"0.00"`
+	TransferMin            string   `env:"FEES_TRANSFER_MIN" env-default:"0.00"`
+	TransferMax            string   `env:"FEES_TRANSFER_MAX" env-default:"0.00"`
+	TransferFreePerMonth   int      `env:"FEES_TRANSFER_FREE_PER_MONTH" env-default:"0"`
+	TransferExemptTiers    []string `env:"FEES_TRANSFER_EXEMPT_TIERS" env-default:"premium"`
+	MaintenanceMonthly     string   `env:"FEES_MAINTENANCE_MONTHLY" env-default:"0.00"`
+	MaintenanceExemptTiers []string `env:"FEES_MAINTENANCE_EXEMPT_TIERS" env-default:"premium"`
+	TimeZone               string   `env:"FEES_TIMEZONE" env-default:"America/Sao_Paulo"`
+}
+
+// ConfSavings savings interest related configurations.
+// The rate is an annual percentage, like "13.65", compounded over 252 business days, and 0 means no interest.
+// The interest is only accrued when TreasuryAccountID is informed. The holidays are dates, like "2021-12-25".
+type ConfSavings struct {
+	TreasuryAccountID string   `env:"SAVINGS_TREASURY_ACCOUNT_ID" env-default:""`
+	AnnualRate        string   `env:"SAVINGS_ANNUAL_RATE" env-default:"0.00"`
+	Holidays          []string `env:"SAVINGS_HOLIDAYS" env-default:""`
+	TimeZone          string   `env:"SAVINGS_TIMEZONE" env-default:"America/Sao_Paulo"`
 }
 
 // GetDSN returns the database DSN, also known as Keyword/Value Connection String.
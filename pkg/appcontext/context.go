@@ -2,21 +2,35 @@ package appcontext
 
 import (
 	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 )
 
 type contextKey int
 
 const (
-	authSubjectKey contextKey = iota
+	principalKey contextKey = iota
+	authTokenIDKey
 )
 
-// WithAuthSubject adds the Authorization JWT subject to the context.
-func WithAuthSubject(ctx context.Context, subject string) context.Context {
-	return context.WithValue(ctx, authSubjectKey, subject)
+// WithPrincipal adds the principal authenticated by the Authorization JWT to the context.
+func WithPrincipal(ctx context.Context, principal model.Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// GetPrincipal gets the authenticated principal from the context.
+func GetPrincipal(ctx context.Context) (model.Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(model.Principal)
+	return principal, ok
+}
+
+// WithAuthTokenID adds the Authorization JWT ID (jti) to the context.
+func WithAuthTokenID(ctx context.Context, tokenID string) context.Context {
+	return context.WithValue(ctx, authTokenIDKey, tokenID)
 }
 
-// GetAuthSubject gets the Authorization JWT subject from the context.
-func GetAuthSubject(ctx context.Context) (string, bool) {
-	tokenStr, ok := ctx.Value(authSubjectKey).(string)
-	return tokenStr, ok
+// GetAuthTokenID gets the Authorization JWT ID (jti) from the context.
+func GetAuthTokenID(ctx context.Context) (string, bool) {
+	tokenID, ok := ctx.Value(authTokenIDKey).(string)
+	return tokenID, ok
 }
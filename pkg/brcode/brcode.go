@@ -0,0 +1,301 @@
+// Package brcode builds and parses the Pix BR Codes, the EMV QR Code payloads used by the Brazilian instant payments.
+//
+// A payload is a sequence of TLV fields: a 2-digit ID, a 2-digit length and the value, some of them made of TLV
+// fields too. It ends with the CRC16-CCITT checksum of the whole payload, in field 63.
+package brcode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrMalformed happens when the payload is not a sequence of TLV fields.
+	ErrMalformed = errors.New("malformed BR Code")
+	// ErrChecksum happens when the checksum of the payload doesn't match its content.
+	ErrChecksum = errors.New("BR Code checksum does not match")
+	// ErrNotPix happens when the payload is a valid BR Code, but not a Pix payment in BRL.
+	ErrNotPix = errors.New("BR Code is not a Pix payment")
+	// ErrKeyOrURLRequired happens when building a payload without a key or URL, or with both.
+	ErrKeyOrURLRequired = errors.New("BR Code must have either a key or a URL")
+	// ErrAmountInvalid happens when building a payload with a negative amount.
+	ErrAmountInvalid = errors.New("BR Code amount must not be negative")
+	// ErrTxIDInvalid happens when the txid is not up to 25 letters and digits.
+	ErrTxIDInvalid = errors.New("BR Code txid must be up to 25 letters and digits")
+	// ErrMerchantRequired happens when building a payload without the merchant name or city.
+	ErrMerchantRequired = errors.New("BR Code merchant name and city are required")
+	// ErrTooLong happens when the key, the URL and the description don't fit the merchant account field.
+	ErrTooLong = errors.New("BR Code key, URL and description are too long")
+)
+
+// The IDs of the fields.
+const (
+	idPayloadFormat     = "00"
+	idPointOfInitiation = "01"
+	idMerchantAccount   = "26"
+	idMerchantCategory  = "52"
+	idCurrency          = "53"
+	idAmount            = "54"
+	idCountry           = "58"
+	idMerchantName      = "59"
+	idMerchantCity      = "60"
+	idAdditionalData    = "62"
+	idCRC               = "63"
+
+	// the fields of the merchant account
+	idGUI         = "00"
+	idKey         = "01"
+	idDescription = "02"
+	idURL         = "25"
+
+	// the fields of the additional data
+	idTxID = "05"
+)
+
+const (
+	pixGUI                   = "br.gov.bcb.pix"
+	currencyBRL              = "986"
+	maxNameLength            = 25
+	maxCityLength            = 15
+	maxTxIDLength            = 25
+	noTxID                   = "***"
+	pointOfInitiationDynamic = "12"
+)
+
+// Payload is the content of a Pix BR Code.
+//
+// The static payloads carry the Key of the receiver and can be paid many times. The dynamic ones carry the URL where
+// the payment details are served by the receiver's institution, and are paid once.
+type Payload struct {
+	Key         string
+	URL         string
+	Description string
+	// MerchantName and MerchantCity are the receiver's. They are truncated to 25 and 15 characters, without accents.
+	MerchantName string
+	MerchantCity string
+	// Amount is in cents. Zero means the payer chooses it.
+	Amount int64
+	// TxID identifies the payment for the receiver. It's empty when not informed.
+	TxID string
+}
+
+// IsDynamic checks whether the payload details are served by the URL.
+func (p Payload) IsDynamic() bool {
+	return p.URL != ""
+}
+
+// Encode builds the BR Code of the payload.
+func Encode(p Payload) (string, error) {
+	if (p.Key == "") == (p.URL == "") {
+		return "", ErrKeyOrURLRequired
+	}
+	if p.Amount < 0 {
+		return "", ErrAmountInvalid
+	}
+	if !isTxID(p.TxID) {
+		return "", ErrTxIDInvalid
+	}
+
+	account := field(idGUI, pixGUI)
+	if p.IsDynamic() {
+		account += field(idURL, p.URL)
+	} else {
+		account += field(idKey, p.Key)
+	}
+	if p.Description != "" {
+		account += field(idDescription, sanitize(p.Description, 99))
+	}
+	if len(account) > 99 {
+		return "", ErrTooLong
+	}
+
+	name, city := sanitize(p.MerchantName, maxNameLength), sanitize(p.MerchantCity, maxCityLength)
+	if name == "" || city == "" {
+		return "", ErrMerchantRequired
+	}
+
+	txID := p.TxID
+	if txID == "" {
+		txID = noTxID
+	}
+
+	var b strings.Builder
+	b.WriteString(field(idPayloadFormat, "01"))
+	if p.IsDynamic() {
+		b.WriteString(field(idPointOfInitiation, pointOfInitiationDynamic))
+	}
+	b.WriteString(field(idMerchantAccount, account))
+	b.WriteString(field(idMerchantCategory, "0000"))
+	b.WriteString(field(idCurrency, currencyBRL))
+	if p.Amount > 0 {
+		b.WriteString(field(idAmount, fmt.Sprintf("%d.%02d", p.Amount/100, p.Amount%100)))
+	}
+	b.WriteString(field(idCountry, "BR"))
+	b.WriteString(field(idMerchantName, name))
+	b.WriteString(field(idMerchantCity, city))
+	b.WriteString(field(idAdditionalData, field(idTxID, txID)))
+	b.WriteString(idCRC + "04")
+
+	return b.String() + fmt.Sprintf("%04X", CRC16([]byte(b.String()))), nil
+}
+
+// Parse reads the Pix payload of the BR Code, checking its checksum.
+func Parse(code string) (*Payload, error) {
+	code = strings.TrimSpace(code)
+	if len(code) < 8 || code[len(code)-8:len(code)-4] != idCRC+"04" {
+		return nil, ErrMalformed
+	}
+
+	fields, err := parseFields(code[:len(code)-8])
+	if err != nil {
+		return nil, err
+	}
+
+	crc, err := strconv.ParseUint(code[len(code)-4:], 16, 16)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	if uint16(crc) != CRC16([]byte(code[:len(code)-4])) {
+		return nil, ErrChecksum
+	}
+
+	if fields[idPayloadFormat] != "01" || fields[idCurrency] != currencyBRL {
+		return nil, ErrNotPix
+	}
+
+	account, err := parseFields(fields[idMerchantAccount])
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(account[idGUI], pixGUI) || (account[idKey] == "") == (account[idURL] == "") {
+		return nil, ErrNotPix
+	}
+
+	p := &Payload{
+		Key:          account[idKey],
+		URL:          account[idURL],
+		Description:  account[idDescription],
+		MerchantName: fields[idMerchantName],
+		MerchantCity: fields[idMerchantCity],
+	}
+
+	if amount, ok := fields[idAmount]; ok {
+		p.Amount, err = parseAmount(amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if additionalData, ok := fields[idAdditionalData]; ok {
+		additional, err := parseFields(additionalData)
+		if err != nil {
+			return nil, err
+		}
+		if txID := additional[idTxID]; txID != noTxID {
+			p.TxID = txID
+		}
+	}
+
+	return p, nil
+}
+
+// CRC16 returns the CRC16-CCITT checksum of the data, with polynomial 0x1021 and initial value 0xFFFF.
+func CRC16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
+
+func field(id string, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// parseFields reads the TLV fields of the data by their IDs.
+func parseFields(data string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, ErrMalformed
+		}
+
+		length, err := strconv.Atoi(data[2:4])
+		if err != nil || length < 1 || len(data) < 4+length {
+			return nil, ErrMalformed
+		}
+
+		fields[data[:2]] = data[4 : 4+length]
+		data = data[4+length:]
+	}
+
+	return fields, nil
+}
+
+// parseAmount reads a decimal amount with up to 2 decimal places, like "10.5", in cents.
+func parseAmount(value string) (int64, error) {
+	units, cents := value, "00"
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		units, cents = value[:i], (value[i+1:] + "00")[:2]
+		if len(value[i+1:]) > 2 {
+			return 0, ErrMalformed
+		}
+	}
+
+	u, err := strconv.ParseUint(units, 10, 40)
+	if err != nil {
+		return 0, ErrMalformed
+	}
+	c, err := strconv.ParseUint(cents, 10, 8)
+	if err != nil {
+		return 0, ErrMalformed
+	}
+
+	return int64(u*100 + c), nil
+}
+
+func isTxID(txID string) bool {
+	if len(txID) > maxTxIDLength {
+		return false
+	}
+	for _, r := range txID {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+var accentReplacer = strings.NewReplacer( //nolint:gochecknoglobals
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a", "é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i", "ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u", "ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A", "É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I", "Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U", "Ç", "C", "Ñ", "N",
+)
+
+// sanitize removes the accents and the characters the readers may not support, truncating the text.
+func sanitize(text string, maxLength int) string {
+	text = accentReplacer.Replace(strings.TrimSpace(text))
+
+	var b strings.Builder
+	for _, r := range text {
+		if r >= ' ' && r <= '~' && b.Len() < maxLength {
+			b.WriteRune(r)
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
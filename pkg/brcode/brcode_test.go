@@ -0,0 +1,232 @@
+package brcode
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// bcbExample is the static BR Code example of the Pix manual of the Brazilian central bank.
+const bcbExample = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func TestCRC16(t *testing.T) {
+	t.Parallel()
+
+	if got := CRC16([]byte("123456789")); got != 0x29B1 {
+		t.Errorf("CRC16() = %04X, want %04X", got, 0x29B1)
+	}
+	if got := CRC16([]byte(bcbExample[:len(bcbExample)-4])); got != 0x1D3D {
+		t.Errorf("CRC16() = %04X, want %04X", got, 0x1D3D)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload Payload
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "central bank example",
+			payload: Payload{Key: "123e4567-e12b-12d1-a456-426655440000", MerchantName: "Fulano de Tal", MerchantCity: "BRASILIA"},
+			want:    bcbExample,
+		},
+		{
+			name: "amount, txid and description",
+			payload: Payload{
+				Key:          "homer@springfield.com",
+				Description:  "Donuts",
+				MerchantName: "Homer Jay Simpson",
+				MerchantCity: "Springfield",
+				Amount:       1050,
+				TxID:         "DONUTS42",
+			},
+			want: "00020126530014br.gov.bcb.pix0121homer@springfield.com0206Donuts5204000053039865405" +
+				"10.505802BR5917Homer Jay Simpson6011Springfield62120508DONUTS426304",
+		},
+		{
+			name:    "name and city are truncated without accents",
+			payload: Payload{Key: "+5511987654321", MerchantName: "José Antônio da Conceição Júnior", MerchantCity: "São José dos Campos"},
+			want: "00020126360014br.gov.bcb.pix0114+551198765432152040000530398658" +
+				"02BR5925Jose Antonio da Conceicao6015Sao Jose dos Ca62070503***6304",
+		},
+		{
+			name:    "dynamic",
+			payload: Payload{URL: "pix.springfield.com/qr/v2/9d36b84f", MerchantName: "Springfield Bank", MerchantCity: "Springfield"},
+			want: "00020101021226560014br.gov.bcb.pix2534pix.springfield.com/qr/v2/9d36b84f5204000053039865802BR" +
+				"5916Springfield Bank6011Springfield62070503***6304",
+		},
+		{
+			name:    "without key or URL should fail",
+			payload: Payload{MerchantName: "Homer", MerchantCity: "Springfield"},
+			wantErr: ErrKeyOrURLRequired,
+		},
+		{
+			name:    "with key and URL should fail",
+			payload: Payload{Key: "homer@springfield.com", URL: "pix.springfield.com/qr", MerchantName: "Homer", MerchantCity: "Springfield"},
+			wantErr: ErrKeyOrURLRequired,
+		},
+		{
+			name:    "negative amount should fail",
+			payload: Payload{Key: "homer@springfield.com", MerchantName: "Homer", MerchantCity: "Springfield", Amount: -1},
+			wantErr: ErrAmountInvalid,
+		},
+		{
+			name:    "txid with symbols should fail",
+			payload: Payload{Key: "homer@springfield.com", MerchantName: "Homer", MerchantCity: "Springfield", TxID: "DONUTS-42"},
+			wantErr: ErrTxIDInvalid,
+		},
+		{
+			name:    "without city should fail",
+			payload: Payload{Key: "homer@springfield.com", MerchantName: "Homer", MerchantCity: "東京"},
+			wantErr: ErrMerchantRequired,
+		},
+		{
+			name:    "too long description should fail",
+			payload: Payload{Key: "homer@springfield.com", Description: strings.Repeat("a", 60), MerchantName: "Homer", MerchantCity: "Springfield"},
+			wantErr: ErrTooLong,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Encode(tt.payload)
+			if err != tt.wantErr {
+				t.Errorf("Encode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			// the checksums are checked by parsing, except for the central bank example
+			if tt.want != bcbExample {
+				if !strings.HasPrefix(got, tt.want) || len(got) != len(tt.want)+4 {
+					t.Errorf("Encode() got = %v, want %vXXXX", got, tt.want)
+				}
+				if _, err := Parse(got); err != nil {
+					t.Errorf("Parse() of encoded error = %v", err)
+				}
+			} else if got != tt.want {
+				t.Errorf("Encode() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	withAmount, err := Encode(Payload{
+		Key:          "homer@springfield.com",
+		Description:  "Donuts",
+		MerchantName: "Homer Jay Simpson",
+		MerchantCity: "Springfield",
+		Amount:       1050,
+		TxID:         "DONUTS42",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dynamic, err := Encode(Payload{URL: "pix.springfield.com/qr/v2/9d36b84f", MerchantName: "Springfield Bank", MerchantCity: "Springfield"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		code    string
+		want    *Payload
+		wantErr error
+	}{
+		{
+			name: "central bank example",
+			code: " " + bcbExample + "\n",
+			want: &Payload{Key: "123e4567-e12b-12d1-a456-426655440000", MerchantName: "Fulano de Tal", MerchantCity: "BRASILIA"},
+		},
+		{
+			name: "amount, txid and description",
+			code: withAmount,
+			want: &Payload{
+				Key:          "homer@springfield.com",
+				Description:  "Donuts",
+				MerchantName: "Homer Jay Simpson",
+				MerchantCity: "Springfield",
+				Amount:       1050,
+				TxID:         "DONUTS42",
+			},
+		},
+		{
+			name: "dynamic",
+			code: dynamic,
+			want: &Payload{URL: "pix.springfield.com/qr/v2/9d36b84f", MerchantName: "Springfield Bank", MerchantCity: "Springfield"},
+		},
+		{
+			name:    "changed content should fail",
+			code:    strings.Replace(bcbExample, "Fulano", "Beltra", 1),
+			wantErr: ErrChecksum,
+		},
+		{
+			name: "lowercase checksum is accepted",
+			code: bcbExample[:len(bcbExample)-4] + "1d3d",
+			want: &Payload{Key: "123e4567-e12b-12d1-a456-426655440000", MerchantName: "Fulano de Tal", MerchantCity: "BRASILIA"},
+		},
+		{
+			name:    "truncated field should fail",
+			code:    "0005016304ABCD",
+			wantErr: ErrMalformed,
+		},
+		{
+			name:    "without checksum should fail",
+			code:    bcbExample[:len(bcbExample)-8],
+			wantErr: ErrMalformed,
+		},
+		{
+			name:    "not a BR Code should fail",
+			code:    "homer@springfield.com",
+			wantErr: ErrMalformed,
+		},
+		{
+			name:    "other arrangement should fail",
+			code:    withChecksum("00020126250014br.gov.bcb.foo0103abc5204000053039865802BR5905Homer6011Springfield"),
+			wantErr: ErrNotPix,
+		},
+		{
+			name:    "other currency should fail",
+			code:    withChecksum("00020126250014br.gov.bcb.pix0103abc5204000053038405802BR5905Homer6011Springfield"),
+			wantErr: ErrNotPix,
+		},
+		{
+			name:    "amount with 3 decimal places should fail",
+			code:    withChecksum("00020126250014br.gov.bcb.pix0103abc52040000530398654051.0005802BR5905Homer6011Springfield"),
+			wantErr: ErrMalformed,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.code)
+			if err != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// withChecksum appends the checksum field to the fields.
+func withChecksum(fields string) string {
+	data := fields + "6304"
+	return data + fmt.Sprintf("%04X", CRC16([]byte(data)))
+}
@@ -30,6 +30,17 @@ func Format(cpf string) string {
 	return expr.ReplaceAllString(cpf, "$1.$2.$3-$4")
 }
 
+// Mask returns a formatted CPF showing only its middle digits (***.000.000-**).
+// If it's not 11 digits long, all the digits are hidden.
+func Mask(cpf string) string {
+	cpf = Clean(cpf)
+	if len(cpf) != 11 {
+		return "***.***.***-**"
+	}
+
+	return "***." + cpf[3:6] + "." + cpf[6:9] + "-**"
+}
+
 // IsValid returns true if it is a valid CPF.
 func IsValid(cpf string) bool {
 	cpf = Clean(cpf)
@@ -98,6 +98,44 @@ func TestFormat(t *testing.T) {
 	}
 }
 
+func TestMask(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cpf  string
+		want string
+	}{
+		{
+			name: "valid masked CPF",
+			cpf:  "854.725.670-92",
+			want: "***.725.670-**",
+		},
+		{
+			name: "valid unmasked CPF",
+			cpf:  "94640164009",
+			want: "***.401.640-**",
+		},
+		{
+			name: "incomplete CPF, hide all digits",
+			cpf:  "854.725.670-9",
+			want: "***.***.***-**",
+		},
+		{
+			name: "too much digits CPF, hide all digits",
+			cpf:  "854725670999",
+			want: "***.***.***-**",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mask(tt.cpf); got != tt.want {
+				t.Errorf("Mask() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsValid(t *testing.T) {
 	t.Parallel()
 
@@ -16,28 +16,96 @@ func NewAccountID() AccountID {
 	return AccountID(uuid.NewString())
 }
 
+// AccountStatus tells whether an account can move money.
+type AccountStatus string
+
+const (
+	// AccountStatusActive is the status of the accounts that can send and receive money.
+	AccountStatusActive AccountStatus = "active"
+	// AccountStatusBlocked is the status of the frozen accounts, like compromised ones. They can be unblocked.
+	AccountStatusBlocked AccountStatus = "blocked"
+	// AccountStatusClosed is the final status of the accounts.
+	AccountStatusClosed AccountStatus = "closed"
+)
+
+// AccountTier tells which fees an account pays, see TransferFeeRule and MaintenanceFeeRule.
+type AccountTier string
+
+const (
+	// AccountTierStandard is the tier of the new accounts.
+	AccountTierStandard AccountTier = "standard"
+	// AccountTierPremium is the tier of the accounts with a premium package, set by the back-office.
+	AccountTierPremium AccountTier = "premium"
+)
+
+// IsValid checks whether it's a known tier.
+func (t AccountTier) IsValid() bool {
+	return t == AccountTierStandard || t == AccountTierPremium
+}
+
 // Account represents a bank account.
+// Its balance, credit limit and the amounts it sends are in its Currency.
 type Account struct {
-	ID        AccountID
-	Name      string
-	CPF       CPF
-	Secret    string
-	Balance   Money
-	CreatedAt time.Time
+	ID              AccountID
+	Name            string
+	CPF             CPF
+	Secret          string
+	Currency        Currency
+	Balance         Money
+	CreditLimit     Money
+	Tier            AccountTier
+	Roles           []Role
+	Status          AccountStatus
+	StatusReason    string
+	StatusChangedAt time.Time
+	CreatedAt       time.Time
 }
 
 // NewAccount returns a new Account filled with the corresponding arguments with generated values for id and createdAt.
-func NewAccount(name string, cpf string, secret string, balance float64) *Account {
+func NewAccount(name string, cpf string, secret string, balance Money) *Account {
 	return &Account{
 		ID:        NewAccountID(),
 		Name:      strings.TrimSpace(name),
 		CPF:       NewCPF(cpf),
 		Secret:    secret,
-		Balance:   Float64ToMoney(balance),
+		Currency:  DefaultCurrency,
+		Balance:   balance,
+		Tier:      AccountTierStandard,
+		Status:    AccountStatusActive,
 		CreatedAt: time.Now(),
 	}
 }
 
+// IsActive checks whether the account can send and receive money.
+func (a *Account) IsActive() bool {
+	return a.Status == AccountStatusActive
+}
+
+// AvailableBalance returns how much the account can spend: its balance plus the overdraft allowed by its credit limit.
+func (a *Account) AvailableBalance() Money {
+	return a.Balance + a.CreditLimit
+}
+
+// CanChangeStatusTo checks whether the account can go from its current status to the given one.
+// Active and blocked accounts can be blocked, unblocked or closed, closed accounts can't change.
+func (a *Account) CanChangeStatusTo(status AccountStatus) bool {
+	switch a.Status {
+	case AccountStatusActive:
+		return status == AccountStatusBlocked || status == AccountStatusClosed
+	case AccountStatusBlocked:
+		return status == AccountStatusActive || status == AccountStatusClosed
+	default:
+		return false
+	}
+}
+
+// ChangeStatus sets the new status, its reason and when it changed.
+func (a *Account) ChangeStatus(status AccountStatus, reason string) {
+	a.Status = status
+	a.StatusReason = reason
+	a.StatusChangedAt = time.Now()
+}
+
 // HashSecret hashes secret with bcrypt.
 func (a *Account) HashSecret() error {
 	hashedSecret, err := bcrypt.GenerateFromPassword([]byte(a.Secret), bcrypt.DefaultCost)
@@ -55,3 +123,38 @@ func (a *Account) CompareSecrets(secret string) error {
 	}
 	return nil
 }
+
+// AccountSort tells how the accounts are sorted when fetched. The ID breaks the ties.
+type AccountSort string
+
+const (
+	// AccountSortCreatedAt sorts the accounts by creation time, oldest first.
+	AccountSortCreatedAt AccountSort = "created_at"
+	// AccountSortName sorts the accounts by name, in alphabetical order.
+	AccountSortName AccountSort = "name"
+)
+
+// IsValid checks whether it's a known sort.
+func (s AccountSort) IsValid() bool {
+	return s == AccountSortCreatedAt || s == AccountSortName
+}
+
+// AccountCursor points to the last Account of a page, so the next page starts right after it.
+// Only the field of the sort is used, besides the ID.
+type AccountCursor struct {
+	ID        AccountID
+	Name      string
+	CreatedAt time.Time
+}
+
+// AccountFilter represents the criteria to fetch the accounts. Zero values don't filter.
+//
+// Name matches the accounts whose name starts with it or is similar to it, both case-insensitive.
+type AccountFilter struct {
+	Name       string
+	CPF        CPF
+	Sort       AccountSort
+	Descending bool
+	After      *AccountCursor
+	Limit      int
+}
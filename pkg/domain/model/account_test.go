@@ -65,7 +65,7 @@ func TestNewAccount(t *testing.T) {
 		name    string
 		cpf     string
 		secret  string
-		balance float64
+		balance Money
 	}
 	tests := []struct {
 		name string
@@ -85,7 +85,10 @@ func TestNewAccount(t *testing.T) {
 				Name:      "Bart Simpson",
 				CPF:       "12345678911",
 				Secret:    "123456",
+				Currency:  CurrencyBRL,
 				Balance:   0,
+				Tier:      AccountTierStandard,
+				Status:    AccountStatusActive,
 				CreatedAt: time.Time{},
 			},
 		},
@@ -102,7 +105,10 @@ func TestNewAccount(t *testing.T) {
 				Name:      "Bart Simpson",
 				CPF:       "12345678911",
 				Secret:    "123456",
+				Currency:  CurrencyBRL,
 				Balance:   0,
+				Tier:      AccountTierStandard,
+				Status:    AccountStatusActive,
 				CreatedAt: time.Time{},
 			},
 		},
@@ -119,7 +125,10 @@ func TestNewAccount(t *testing.T) {
 				Name:      "Bart Simpson",
 				CPF:       "12345678911",
 				Secret:    "123456",
+				Currency:  CurrencyBRL,
 				Balance:   0,
+				Tier:      AccountTierStandard,
+				Status:    AccountStatusActive,
 				CreatedAt: time.Time{},
 			},
 		},
@@ -129,14 +138,17 @@ func TestNewAccount(t *testing.T) {
 				name:    "Bart Simpson",
 				cpf:     "12345678911",
 				secret:  "123456",
-				balance: -1.9,
+				balance: -190,
 			},
 			want: &Account{
 				ID:        "",
 				Name:      "Bart Simpson",
 				CPF:       "12345678911",
 				Secret:    "123456",
+				Currency:  CurrencyBRL,
 				Balance:   -190,
+				Tier:      AccountTierStandard,
+				Status:    AccountStatusActive,
 				CreatedAt: time.Time{},
 			},
 		},
@@ -146,14 +158,17 @@ func TestNewAccount(t *testing.T) {
 				name:    "Bart Simpson",
 				cpf:     "12345678911",
 				secret:  "123456",
-				balance: 1.9,
+				balance: 190,
 			},
 			want: &Account{
 				ID:        "",
 				Name:      "Bart Simpson",
 				CPF:       "12345678911",
 				Secret:    "123456",
+				Currency:  CurrencyBRL,
 				Balance:   190,
+				Tier:      AccountTierStandard,
+				Status:    AccountStatusActive,
 				CreatedAt: time.Time{},
 			},
 		},
@@ -232,3 +247,78 @@ func TestAccount_CompareSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestAccount_CanChangeStatusTo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		from AccountStatus
+		to   AccountStatus
+		want bool
+	}{
+		{name: "active to blocked", from: AccountStatusActive, to: AccountStatusBlocked, want: true},
+		{name: "active to closed", from: AccountStatusActive, to: AccountStatusClosed, want: true},
+		{name: "active to active", from: AccountStatusActive, to: AccountStatusActive, want: false},
+		{name: "blocked to active", from: AccountStatusBlocked, to: AccountStatusActive, want: true},
+		{name: "blocked to closed", from: AccountStatusBlocked, to: AccountStatusClosed, want: true},
+		{name: "blocked to blocked", from: AccountStatusBlocked, to: AccountStatusBlocked, want: false},
+		{name: "closed to active", from: AccountStatusClosed, to: AccountStatusActive, want: false},
+		{name: "closed to blocked", from: AccountStatusClosed, to: AccountStatusBlocked, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := &Account{Status: tt.from}
+			if got := a.CanChangeStatusTo(tt.to); got != tt.want {
+				t.Errorf("CanChangeStatusTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccount_ChangeStatus(t *testing.T) {
+	t.Parallel()
+
+	a := &Account{Status: AccountStatusActive}
+	a.ChangeStatus(AccountStatusBlocked, "suspected fraud")
+
+	if a.Status != AccountStatusBlocked || a.StatusReason != "suspected fraud" {
+		t.Errorf("ChangeStatus() got = %v, want blocked with reason", a)
+	}
+	if a.StatusChangedAt.Before(time.Now().Add(-5 * time.Second)) {
+		t.Errorf("ChangeStatus() got = %v, want StatusChangedAt in the last 5 seconds", a)
+	}
+	if a.IsActive() {
+		t.Errorf("IsActive() = true, want false")
+	}
+}
+
+func TestAccount_AvailableBalance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		balance     Money
+		creditLimit Money
+		want        Money
+	}{
+		{name: "no credit limit", balance: 1000, creditLimit: 0, want: 1000},
+		{name: "positive balance with credit limit", balance: 1000, creditLimit: 5000, want: 6000},
+		{name: "overdrawn within the credit limit", balance: -1000, creditLimit: 5000, want: 4000},
+		{name: "overdrawn beyond the credit limit", balance: -6000, creditLimit: 5000, want: -1000},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := &Account{Balance: tt.balance, CreditLimit: tt.creditLimit}
+			if got := a.AvailableBalance(); got != tt.want {
+				t.Errorf("AvailableBalance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
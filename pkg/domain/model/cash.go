@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DepositID represents a Deposit ID as uuid.
+type DepositID string
+
+// NewDepositID returns a new DepositID with value generated by uuid.New().
+func NewDepositID() DepositID {
+	return DepositID(uuid.NewString())
+}
+
+// Deposit represents cash entering the bank, credited to an account by an operator.
+type Deposit struct {
+	ID         DepositID
+	AccountID  AccountID
+	OperatorID AccountID
+	Amount     Money
+	CreatedAt  time.Time
+}
+
+// NewDeposit returns a new Deposit filled with the corresponding arguments with generated values for id and createdAt.
+func NewDeposit(accountID, operatorID AccountID, amount Money) *Deposit {
+	return &Deposit{
+		ID:         NewDepositID(),
+		AccountID:  accountID,
+		OperatorID: operatorID,
+		Amount:     amount,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// WithdrawalID represents a Withdrawal ID as uuid.
+type WithdrawalID string
+
+// NewWithdrawalID returns a new WithdrawalID with value generated by uuid.New().
+func NewWithdrawalID() WithdrawalID {
+	return WithdrawalID(uuid.NewString())
+}
+
+// Withdrawal represents cash leaving the bank, debited from an account by its holder.
+type Withdrawal struct {
+	ID        WithdrawalID
+	AccountID AccountID
+	Amount    Money
+	CreatedAt time.Time
+}
+
+// NewWithdrawal returns a new Withdrawal filled with the corresponding arguments with generated values for id and createdAt.
+func NewWithdrawal(accountID AccountID, amount Money) *Withdrawal {
+	return &Withdrawal{
+		ID:        NewWithdrawalID(),
+		AccountID: accountID,
+		Amount:    amount,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewDeposit(t *testing.T) {
+	t.Parallel()
+
+	got := NewDeposit("uuid-1", "operator-uuid", 1000)
+
+	if len(got.ID) <= 0 {
+		t.Errorf("NewDeposit() = %v, ID should not be empty", got)
+	}
+	got.ID = ""
+
+	if got.CreatedAt.Before(time.Now().Add(-5 * time.Second)) {
+		t.Errorf("NewDeposit() got = %v, want CreatedAt in the last 5 seconds", got)
+	}
+	got.CreatedAt = time.Time{}
+
+	want := &Deposit{AccountID: "uuid-1", OperatorID: "operator-uuid", Amount: 1000}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewDeposit() = %v, want %v", got, want)
+	}
+}
+
+func TestNewWithdrawal(t *testing.T) {
+	t.Parallel()
+
+	got := NewWithdrawal("uuid-1", 1000)
+
+	if len(got.ID) <= 0 {
+		t.Errorf("NewWithdrawal() = %v, ID should not be empty", got)
+	}
+	got.ID = ""
+
+	if got.CreatedAt.Before(time.Now().Add(-5 * time.Second)) {
+		t.Errorf("NewWithdrawal() got = %v, want CreatedAt in the last 5 seconds", got)
+	}
+	got.CreatedAt = time.Time{}
+
+	want := &Withdrawal{AccountID: "uuid-1", Amount: 1000}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewWithdrawal() = %v, want %v", got, want)
+	}
+}
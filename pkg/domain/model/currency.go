@@ -0,0 +1,49 @@
+package model
+
+// Currency represents an ISO 4217 alphabetic currency code, like "BRL".
+type Currency string
+
+const (
+	// CurrencyBRL is the Brazilian real.
+	CurrencyBRL Currency = "BRL"
+	// CurrencyUSD is the United States dollar.
+	CurrencyUSD Currency = "USD"
+	// CurrencyEUR is the euro.
+	CurrencyEUR Currency = "EUR"
+	// CurrencyJPY is the Japanese yen, which has no minor unit.
+	CurrencyJPY Currency = "JPY"
+	// CurrencyKWD is the Kuwaiti dinar, whose minor unit is the fils, a thousandth of it.
+	CurrencyKWD Currency = "KWD"
+)
+
+// DefaultCurrency is the currency of the accounts opened without one, and of all the accounts opened before
+// the currencies were introduced.
+const DefaultCurrency = CurrencyBRL
+
+// MaxMinorUnits is the largest number of decimal places of the supported currencies.
+const MaxMinorUnits = 3
+
+// currencyMinorUnits are the ISO 4217 minor units, the number of decimal places, of the supported currencies.
+var currencyMinorUnits = map[Currency]int{
+	CurrencyBRL: 2,
+	CurrencyUSD: 2,
+	CurrencyEUR: 2,
+	CurrencyJPY: 0,
+	CurrencyKWD: 3,
+}
+
+// IsValid checks whether it's a supported currency.
+func (c Currency) IsValid() bool {
+	_, ok := currencyMinorUnits[c]
+	return ok
+}
+
+// MinorUnits returns the number of decimal places of the currency, as defined by ISO 4217, or the ones of the
+// DefaultCurrency if it's not supported. Money in the currency is an integer number of its minor unit, like cents.
+func (c Currency) MinorUnits() int {
+	if places, ok := currencyMinorUnits[c]; ok {
+		return places
+	}
+
+	return currencyMinorUnits[DefaultCurrency]
+}
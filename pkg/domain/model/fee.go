@@ -0,0 +1,111 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransferFeeRule defines the fee charged on each transfer sent by an account, on top of its amount.
+//
+// The fee is the Flat part plus the Rate of the amount, the percentage part bounded by Min and Max. Zero means no
+// bound. The first FreePerMonth transfers of each month and the accounts of the ExemptTiers are not charged.
+type TransferFeeRule struct {
+	Flat         Money
+	Rate         InterestRate
+	Min          Money
+	Max          Money
+	FreePerMonth int
+	ExemptTiers  []AccountTier
+}
+
+// IsZero checks whether the rule charges nothing.
+func (r TransferFeeRule) IsZero() bool {
+	return r.Flat <= 0 && r.Rate <= 0
+}
+
+// Fee returns the fee of a transfer of the amount, not considering the free transfers nor the exempt tiers.
+// The percentage part is rounded half up to the cent.
+func (r TransferFeeRule) Fee(amount Money) Money {
+	if r.Rate <= 0 {
+		return r.Flat
+	}
+
+	const divisor = 10000
+	percentage := Money((int64(amount)*int64(r.Rate) + divisor/2) / divisor)
+	if percentage < r.Min {
+		percentage = r.Min
+	}
+	if r.Max > 0 && percentage > r.Max {
+		percentage = r.Max
+	}
+
+	return r.Flat + percentage
+}
+
+// IsExempt checks whether the accounts of the tier are not charged.
+func (r TransferFeeRule) IsExempt(tier AccountTier) bool {
+	return containsTier(r.ExemptTiers, tier)
+}
+
+// MaintenanceFeeRule defines the fee charged every month on each account, except on the ones of the ExemptTiers.
+type MaintenanceFeeRule struct {
+	Monthly     Money
+	ExemptTiers []AccountTier
+}
+
+// IsExempt checks whether the accounts of the tier are not charged.
+func (r MaintenanceFeeRule) IsExempt(tier AccountTier) bool {
+	return containsTier(r.ExemptTiers, tier)
+}
+
+func containsTier(tiers []AccountTier, tier AccountTier) bool {
+	for _, t := range tiers {
+		if t == tier {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MaintenanceFeeChargeID represents a MaintenanceFeeCharge ID as uuid.
+type MaintenanceFeeChargeID string
+
+// NewMaintenanceFeeChargeID returns a new MaintenanceFeeChargeID with value generated by uuid.New().
+func NewMaintenanceFeeChargeID() MaintenanceFeeChargeID {
+	return MaintenanceFeeChargeID(uuid.NewString())
+}
+
+// MaintenanceFeeCharge represents the maintenance fee of a month charged on an account.
+// Each account is charged at most once a month, Month being the midnight that starts it.
+type MaintenanceFeeCharge struct {
+	ID        MaintenanceFeeChargeID
+	AccountID AccountID
+	Month     time.Time
+	Amount    Money
+	CreatedAt time.Time
+}
+
+// NewMaintenanceFeeCharge returns a new MaintenanceFeeCharge of the amount on the account for the month.
+func NewMaintenanceFeeCharge(accountID AccountID, month time.Time, amount Money) *MaintenanceFeeCharge {
+	return &MaintenanceFeeCharge{
+		ID:        NewMaintenanceFeeChargeID(),
+		AccountID: accountID,
+		Month:     month,
+		Amount:    amount,
+		CreatedAt: time.Now(),
+	}
+}
+
+// MaintenanceFeeFilter selects the accounts that owe the maintenance fee of the Month: the ones in the Currency of the
+// revenue account, created before the month ended, not closed nor of the ExemptTiers. The revenue account itself
+// doesn't owe it. After is the last account ID already walked, empty to start from the first one.
+type MaintenanceFeeFilter struct {
+	Month            time.Time
+	MonthEnd         time.Time
+	Currency         Currency
+	ExemptTiers      []AccountTier
+	RevenueAccountID AccountID
+	After            AccountID
+}
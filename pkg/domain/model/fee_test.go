@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestTransferFeeRule_Fee(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		rule   TransferFeeRule
+		amount Money
+		want   Money
+	}{
+		{name: "zero rule charges nothing", rule: TransferFeeRule{}, amount: 100000, want: 0},
+		{name: "flat only", rule: TransferFeeRule{Flat: 150}, amount: 100000, want: 150},
+		{name: "percentage rounds half up", rule: TransferFeeRule{Rate: 50}, amount: 12345, want: 62},
+		{name: "percentage below the min", rule: TransferFeeRule{Rate: 50, Min: 100}, amount: 1000, want: 100},
+		{name: "percentage above the max", rule: TransferFeeRule{Rate: 50, Max: 1000}, amount: 1000000, want: 1000},
+		{name: "zero max does not bound", rule: TransferFeeRule{Rate: 50}, amount: 1000000, want: 5000},
+		{name: "flat plus bounded percentage", rule: TransferFeeRule{Flat: 100, Rate: 50, Min: 100, Max: 1000}, amount: 1000, want: 200},
+		{name: "min without rate is ignored", rule: TransferFeeRule{Flat: 100, Min: 500}, amount: 1000, want: 100},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.rule.Fee(tt.amount); got != tt.want {
+				t.Errorf("Fee() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransferFeeRule_IsExempt(t *testing.T) {
+	t.Parallel()
+
+	rule := TransferFeeRule{Flat: 100, ExemptTiers: []AccountTier{AccountTierPremium}}
+	if !rule.IsExempt(AccountTierPremium) {
+		t.Errorf("IsExempt(%v) = false, want true", AccountTierPremium)
+	}
+	if rule.IsExempt(AccountTierStandard) {
+		t.Errorf("IsExempt(%v) = true, want false", AccountTierStandard)
+	}
+}
+
+func TestMaintenanceFeeRule_IsExempt(t *testing.T) {
+	t.Parallel()
+
+	rule := MaintenanceFeeRule{Monthly: 990}
+	if rule.IsExempt(AccountTierStandard) || rule.IsExempt(AccountTierPremium) {
+		t.Errorf("IsExempt() = true, want false without exempt tiers")
+	}
+}
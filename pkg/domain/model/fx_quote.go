@@ -0,0 +1,125 @@
+package model
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrFXRateInvalid happens when an exchange rate is not a positive decimal string with at most 8 decimal places.
+	ErrFXRateInvalid = errors.New("exchange rates must be positive decimal numbers with at most 8 decimal places")
+)
+
+// FXRateDecimals is the number of decimal places of the exchange rates.
+const FXRateDecimals = 8
+
+// fxRateScale is 10^FXRateDecimals.
+const fxRateScale = 100_000_000
+
+// FXRate represents how many units of a currency are worth one unit of another currency, like 5.1234 BRL for 1 USD.
+// It is an integer, scaled by 10^FXRateDecimals, to prevent floating point math problems.
+type FXRate int64
+
+// ParseFXRate converts a positive decimal string, like "5.1234", to FXRate without loss of precision.
+func ParseFXRate(s string) (FXRate, error) {
+	rate, err := parseFixedPoint(s, FXRateDecimals)
+	if err != nil || rate <= 0 {
+		return 0, ErrFXRateInvalid
+	}
+
+	return FXRate(rate), nil
+}
+
+// String formats FXRate as a decimal string without trailing zeros, like "5.1234".
+func (r FXRate) String() string {
+	units := strconv.FormatInt(int64(r)/fxRateScale, 10)
+	decimals := strconv.FormatInt(int64(r)%fxRateScale, 10)
+	decimals = strings.TrimRight(strings.Repeat("0", FXRateDecimals-len(decimals))+decimals, "0")
+	if decimals == "" {
+		return units
+	}
+
+	return units + "." + decimals
+}
+
+// Inverse returns the rate of the opposite direction, like 0.19518289 USD for 1 BRL from 5.1234 BRL for 1 USD,
+// rounded to the nearest 10^-FXRateDecimals.
+func (r FXRate) Inverse() FXRate {
+	return FXRate(divRound(big.NewInt(fxRateScale*fxRateScale), big.NewInt(int64(r))))
+}
+
+// Convert returns the amount of the from currency worth in the to currency, by the rate, rounded half away from zero
+// to the minor unit of the to currency.
+func (r FXRate) Convert(amount Money, from, to Currency) Money {
+	numerator := new(big.Int).Mul(big.NewInt(int64(amount)), big.NewInt(int64(r)))
+	numerator.Mul(numerator, big.NewInt(pow10(to.MinorUnits())))
+	denominator := new(big.Int).Mul(big.NewInt(fxRateScale), big.NewInt(pow10(from.MinorUnits())))
+
+	return Money(divRound(numerator, denominator))
+}
+
+// divRound divides n by the positive d, rounding half away from zero.
+func divRound(n, d *big.Int) int64 {
+	quotient, remainder := new(big.Int).QuoRem(n, d, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(d) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(n.Sign())))
+	}
+
+	return quotient.Int64()
+}
+
+// FXQuoteID represents a FXQuote ID as uuid.
+type FXQuoteID string
+
+// NewFXQuoteID returns a new FXQuoteID with value generated by uuid.New().
+func NewFXQuoteID() FXQuoteID {
+	return FXQuoteID(uuid.NewString())
+}
+
+// FXQuote represents the conversion of an amount of the currency of an account to another currency, by a rate that
+// holds until ExpiresAt. It is used by one transfer at most, the TransferID.
+type FXQuote struct {
+	ID             FXQuoteID
+	AccountID      AccountID
+	SourceCurrency Currency
+	TargetCurrency Currency
+	Rate           FXRate
+	SourceAmount   Money
+	TargetAmount   Money
+	TransferID     TransferID
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+}
+
+// NewFXQuote returns a new FXQuote converting the source amount by the rate, valid for the ttl, with generated values
+// for id and createdAt.
+func NewFXQuote(accountID AccountID, source, target Currency, rate FXRate, sourceAmount Money, ttl time.Duration) *FXQuote {
+	now := time.Now()
+
+	return &FXQuote{
+		ID:             NewFXQuoteID(),
+		AccountID:      accountID,
+		SourceCurrency: source,
+		TargetCurrency: target,
+		Rate:           rate,
+		SourceAmount:   sourceAmount,
+		TargetAmount:   rate.Convert(sourceAmount, source, target),
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(ttl),
+	}
+}
+
+// IsExpired checks whether the rate of the quote no longer holds at the time.
+func (q *FXQuote) IsExpired(now time.Time) bool {
+	return !now.Before(q.ExpiresAt)
+}
+
+// IsUsed checks whether a transfer was already made by the quote.
+func (q *FXQuote) IsUsed() bool {
+	return q.TransferID != ""
+}
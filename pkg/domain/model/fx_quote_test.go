@@ -0,0 +1,166 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFXRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    FXRate
+		wantErr bool
+	}{
+		{name: "decimal rate", s: "5.1234", want: 512340000},
+		{name: "integer rate", s: "5", want: 500000000},
+		{name: "8 decimal places", s: "0.19518289", want: 19518289},
+		{name: "zero should fail", s: "0", wantErr: true},
+		{name: "negative should fail", s: "-5.1", wantErr: true},
+		{name: "9 decimal places should fail", s: "0.195182891", wantErr: true},
+		{name: "not a number should fail", s: "five", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseFXRate(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFXRate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseFXRate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFXRate_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		rate FXRate
+		want string
+	}{
+		{rate: 512340000, want: "5.1234"},
+		{rate: 500000000, want: "5"},
+		{rate: 19518289, want: "0.19518289"},
+		{rate: 1, want: "0.00000001"},
+	}
+	for _, tt := range tests {
+		if got := tt.rate.String(); got != tt.want {
+			t.Errorf("String() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestFXRate_Inverse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		rate FXRate
+		want FXRate
+	}{
+		{name: "rounds down below half", rate: 512340000, want: 19518289},
+		{name: "rounds half up", rate: 300000000, want: 33333333},
+		{name: "exact", rate: 400000000, want: 25000000},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.rate.Inverse(); got != tt.want {
+				t.Errorf("Inverse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFXRate_Convert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		rate   FXRate
+		amount Money
+		from   Currency
+		to     Currency
+		want   Money
+	}{
+		{name: "to a weaker currency", rate: 512340000, amount: 10000, from: CurrencyUSD, to: CurrencyBRL, want: 51234},
+		{name: "to a stronger currency", rate: 19518289, amount: 51234, from: CurrencyBRL, to: CurrencyUSD, want: 10000},
+		{name: "rounds half away from zero", rate: 50000000, amount: 1, from: CurrencyEUR, to: CurrencyUSD, want: 1},
+		{name: "rounds down below half", rate: 49999999, amount: 1, from: CurrencyEUR, to: CurrencyUSD, want: 0},
+		{name: "negative amount", rate: 50000000, amount: -1, from: CurrencyEUR, to: CurrencyUSD, want: -1},
+		{name: "does not overflow", rate: 100000000000, amount: 9000000000000000, from: CurrencyUSD, to: CurrencyBRL, want: 9000000000000000 * 1000},
+		{name: "to a currency without minor unit", rate: 15012345678, amount: 10000, from: CurrencyUSD, to: CurrencyJPY, want: 15012},
+		{name: "from a currency without minor unit", rate: 666118, amount: 15012, from: CurrencyJPY, to: CurrencyUSD, want: 10000},
+		{name: "from a currency with 3 decimal places", rate: 1650000000, amount: 1234, from: CurrencyKWD, to: CurrencyBRL, want: 2036},
+		{name: "to a currency with 3 decimal places", rate: 6060606, amount: 2036, from: CurrencyBRL, to: CurrencyKWD, want: 1234},
+		{name: "between 0 and 3 decimal places", rate: 205000, amount: 1000, from: CurrencyJPY, to: CurrencyKWD, want: 2050},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.rate.Convert(tt.amount, tt.from, tt.to); got != tt.want {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFXQuote(t *testing.T) {
+	t.Parallel()
+
+	got := NewFXQuote("uuid-1", CurrencyUSD, CurrencyBRL, 512340000, 10000, time.Minute)
+
+	if len(got.ID) <= 0 {
+		t.Errorf("NewFXQuote() = %v, ID should not be empty", got)
+	}
+	got.ID = ""
+
+	if got.CreatedAt.Before(time.Now().Add(-5*time.Second)) || !got.ExpiresAt.Equal(got.CreatedAt.Add(time.Minute)) {
+		t.Errorf("NewFXQuote() got = %v, want CreatedAt in the last 5 seconds and ExpiresAt a minute later", got)
+	}
+	got.CreatedAt = time.Time{}
+	got.ExpiresAt = time.Time{}
+
+	want := &FXQuote{
+		AccountID:      "uuid-1",
+		SourceCurrency: CurrencyUSD,
+		TargetCurrency: CurrencyBRL,
+		Rate:           512340000,
+		SourceAmount:   10000,
+		TargetAmount:   51234,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewFXQuote() = %v, want %v", got, want)
+	}
+
+	if got.IsUsed() {
+		t.Errorf("IsUsed() = true, want false")
+	}
+}
+
+func TestFXQuote_IsExpired(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Date(2021, 3, 14, 12, 0, 0, 0, time.UTC)
+	quote := &FXQuote{ExpiresAt: expiresAt}
+
+	if quote.IsExpired(expiresAt.Add(-time.Nanosecond)) {
+		t.Errorf("IsExpired() = true before ExpiresAt, want false")
+	}
+	if !quote.IsExpired(expiresAt) {
+		t.Errorf("IsExpired() = false at ExpiresAt, want true")
+	}
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LedgerExternalAccountID is the ledger counterpart of the money that enters or leaves the bank,
+// like the initial balance given when an account is created, deposits and withdrawals.
+const LedgerExternalAccountID AccountID = "00000000-0000-0000-0000-000000000000"
+
+// LedgerFXAccountID is the ledger counterpart of the cross-currency transfers: the bank buys the amount debited in
+// the origin currency and sells the converted amount credited in the destination currency. Its entries are in both
+// currencies, so it holds one position per currency.
+const LedgerFXAccountID AccountID = "00000000-0000-0000-0000-000000000001"
+
+// IsBankLedgerAccount checks whether the ID is one of the ledger counterparts of the bank itself, which are not accounts.
+func IsBankLedgerAccount(id AccountID) bool {
+	return id == LedgerExternalAccountID || id == LedgerFXAccountID
+}
+
+// LedgerPostingID represents a LedgerPosting ID as uuid.
+type LedgerPostingID string
+
+// NewLedgerPostingID returns a new LedgerPostingID with value generated by uuid.New().
+func NewLedgerPostingID() LedgerPostingID {
+	return LedgerPostingID(uuid.NewString())
+}
+
+// LedgerEntryID represents a LedgerEntry ID as uuid.
+type LedgerEntryID string
+
+// NewLedgerEntryID returns a new LedgerEntryID with value generated by uuid.New().
+func NewLedgerEntryID() LedgerEntryID {
+	return LedgerEntryID(uuid.NewString())
+}
+
+// LedgerEntryType tells if a LedgerEntry takes money from (debit) or gives money to (credit) an account.
+type LedgerEntryType string
+
+const (
+	// LedgerEntryDebit decreases the account balance.
+	LedgerEntryDebit LedgerEntryType = "debit"
+	// LedgerEntryCredit increases the account balance.
+	LedgerEntryCredit LedgerEntryType = "credit"
+)
+
+// LedgerPostingKind tells what originated a LedgerPosting.
+type LedgerPostingKind string
+
+const (
+	// LedgerPostingOpeningBalance is the balance an account already had when the ledger was introduced.
+	LedgerPostingOpeningBalance LedgerPostingKind = "opening_balance"
+	// LedgerPostingInitialBalance is the balance given to an account on its creation.
+	LedgerPostingInitialBalance LedgerPostingKind = "initial_balance"
+	// LedgerPostingTransfer is a transfer between two accounts.
+	LedgerPostingTransfer LedgerPostingKind = "transfer"
+	// LedgerPostingCorrection is a manual adjustment of balances.
+	LedgerPostingCorrection LedgerPostingKind = "correction"
+	// LedgerPostingAccountClosure is the remaining balance of a closed account swept to another account.
+	LedgerPostingAccountClosure LedgerPostingKind = "account_closure"
+	// LedgerPostingDeposit is cash deposited into an account.
+	LedgerPostingDeposit LedgerPostingKind = "deposit"
+	// LedgerPostingWithdrawal is cash withdrawn from an account.
+	LedgerPostingWithdrawal LedgerPostingKind = "withdrawal"
+	// LedgerPostingRefund is a transfer given back by its recipient.
+	LedgerPostingRefund LedgerPostingKind = "refund"
+	// LedgerPostingReversal is a mistaken transfer undone by the back-office.
+	LedgerPostingReversal LedgerPostingKind = "reversal"
+	// LedgerPostingOverdraftInterest is the interest charged on a negative balance.
+	LedgerPostingOverdraftInterest LedgerPostingKind = "overdraft_interest"
+	// LedgerPostingTransferFee is the fee charged on a transfer, credited to the bank revenue account.
+	LedgerPostingTransferFee LedgerPostingKind = "transfer_fee"
+	// LedgerPostingMaintenanceFee is the monthly maintenance fee of an account, credited to the bank revenue account.
+	LedgerPostingMaintenanceFee LedgerPostingKind = "maintenance_fee"
+	// LedgerPostingSavingsInterest is the monthly interest on the positive balance of an account, debited from the bank
+	// treasury account.
+	LedgerPostingSavingsInterest LedgerPostingKind = "savings_interest"
+)
+
+// LedgerPosting represents a movement of money between two accounts.
+// It is recorded as one debit LedgerEntry and one credit LedgerEntry with the same amount in the same currency.
+type LedgerPosting struct {
+	ID              LedgerPostingID
+	Kind            LedgerPostingKind
+	ReferenceID     string
+	DebitAccountID  AccountID
+	CreditAccountID AccountID
+	Amount          Money
+	Currency        Currency
+	CreatedAt       time.Time
+}
+
+// LedgerEntry represents one immutable side of a LedgerPosting.
+type LedgerEntry struct {
+	ID          LedgerEntryID
+	PostingID   LedgerPostingID
+	AccountID   AccountID
+	Type        LedgerEntryType
+	Amount      Money
+	Currency    Currency
+	Kind        LedgerPostingKind
+	ReferenceID string
+	CreatedAt   time.Time
+}
+
+// NewLedgerPosting returns a new LedgerPosting filled with the corresponding arguments with generated values for id and createdAt.
+func NewLedgerPosting(kind LedgerPostingKind, referenceID string, debitAccountID, creditAccountID AccountID, amount Money, currency Currency) *LedgerPosting {
+	return &LedgerPosting{
+		ID:              NewLedgerPostingID(),
+		Kind:            kind,
+		ReferenceID:     referenceID,
+		DebitAccountID:  debitAccountID,
+		CreditAccountID: creditAccountID,
+		Amount:          amount,
+		Currency:        currency,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// Entries returns the debit and the credit entries of the posting.
+func (p *LedgerPosting) Entries() []LedgerEntry {
+	return []LedgerEntry{
+		p.newEntry(p.DebitAccountID, LedgerEntryDebit),
+		p.newEntry(p.CreditAccountID, LedgerEntryCredit),
+	}
+}
+
+func (p *LedgerPosting) newEntry(accountID AccountID, entryType LedgerEntryType) LedgerEntry {
+	return LedgerEntry{
+		ID:          NewLedgerEntryID(),
+		PostingID:   p.ID,
+		AccountID:   accountID,
+		Type:        entryType,
+		Amount:      p.Amount,
+		Currency:    p.Currency,
+		Kind:        p.Kind,
+		ReferenceID: p.ReferenceID,
+		CreatedAt:   p.CreatedAt,
+	}
+}
+
+// SignedAmount returns the entry amount as seen by the account balance: negative for debits and positive for credits.
+func (e LedgerEntry) SignedAmount() Money {
+	if e.Type == LedgerEntryDebit {
+		return -e.Amount
+	}
+
+	return e.Amount
+}
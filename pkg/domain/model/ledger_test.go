@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewLedgerPosting(t *testing.T) {
+	t.Parallel()
+
+	got := NewLedgerPosting(LedgerPostingTransfer, "trf-uuid-1", "uuid-1", "uuid-2", 1000, CurrencyBRL)
+
+	if len(got.ID) <= 0 {
+		t.Errorf("NewLedgerPosting() = %v, ID should not be empty", got)
+	}
+
+	if got.CreatedAt.Before(time.Now().Add(-5 * time.Second)) {
+		t.Errorf("NewLedgerPosting() got = %v, want CreatedAt in the last 5 seconds", got)
+	}
+
+	if got.Kind != LedgerPostingTransfer || got.ReferenceID != "trf-uuid-1" ||
+		got.DebitAccountID != "uuid-1" || got.CreditAccountID != "uuid-2" || got.Amount != 1000 || got.Currency != CurrencyBRL {
+		t.Errorf("NewLedgerPosting() = %v, fields do not match the arguments", got)
+	}
+}
+
+func TestLedgerPosting_Entries(t *testing.T) {
+	t.Parallel()
+
+	posting := NewLedgerPosting(LedgerPostingTransfer, "trf-uuid-1", "uuid-1", "uuid-2", 1000, CurrencyBRL)
+
+	got := posting.Entries()
+	if len(got) != 2 {
+		t.Fatalf("Entries() got %v entries, want 2", len(got))
+	}
+
+	debit, credit := got[0], got[1]
+	if debit.Type != LedgerEntryDebit || debit.AccountID != "uuid-1" {
+		t.Errorf("Entries() debit = %v, want debit from uuid-1", debit)
+	}
+	if credit.Type != LedgerEntryCredit || credit.AccountID != "uuid-2" {
+		t.Errorf("Entries() credit = %v, want credit to uuid-2", credit)
+	}
+	if debit.ID == credit.ID {
+		t.Errorf("Entries() entries should have different IDs")
+	}
+
+	for _, entry := range got {
+		if entry.PostingID != posting.ID || entry.Amount != posting.Amount || entry.Kind != posting.Kind ||
+			entry.ReferenceID != posting.ReferenceID || entry.CreatedAt != posting.CreatedAt {
+			t.Errorf("Entries() entry = %v, should share the posting fields %v", entry, posting)
+		}
+	}
+
+	if sum := debit.SignedAmount() + credit.SignedAmount(); sum != 0 {
+		t.Errorf("SignedAmount() debit + credit = %v, want 0", sum)
+	}
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// LoginAttempts represents the failed login attempts made for a CPF or from a client IP.
+type LoginAttempts struct {
+	Failures    int
+	LockedUntil time.Time
+}
+
+// IsLocked checks whether new login attempts must be rejected at the given time.
+func (la LoginAttempts) IsLocked(now time.Time) bool {
+	return now.Before(la.LockedUntil)
+}
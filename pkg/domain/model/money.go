@@ -1,6 +1,24 @@
 package model
 
-// Money represents monetary amount.
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrMoneyInvalid happens when a monetary amount could not be parsed from a decimal string.
+	ErrMoneyInvalid = errors.New("invalid monetary amount")
+	// ErrMoneySubCentPrecision happens when a monetary amount has more than 2 decimal places.
+	ErrMoneySubCentPrecision = errors.New("monetary amount must not have more than 2 decimal places")
+	// ErrMoneyMinorUnitPrecision happens when a monetary amount has more decimal places than the minor unit of its
+	// currency.
+	ErrMoneyMinorUnitPrecision = errors.New("monetary amount must not have more decimal places than its currency")
+)
+
+// Money represents monetary amount in the minor unit of its currency, like cents. The functions and methods without
+// a currency take it in cents, with 2 decimal places.
 // It is an integer to prevent floating point math problems.
 type Money int64
 
@@ -9,12 +27,164 @@ func (m Money) Float64() float64 {
 	return float64(m) / 100
 }
 
+// Float64In converts Money in the minor unit of the currency to float64, like 1.234 for 1234 fils of KWD.
+func (m Money) Float64In(currency Currency) float64 {
+	return float64(m) / float64(pow10(currency.MinorUnits()))
+}
+
 // Int64 converts Money to int64
 func (m Money) Int64() int64 {
 	return int64(m)
 }
 
-// Float64ToMoney converts float64 to Money
+// String formats Money as a decimal string with 2 decimal places, like "-1234.56".
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return sign + strconv.FormatInt(cents/100, 10) + "." + leftPad2(cents%100)
+}
+
+// Format formats Money in the minor unit of the currency as a decimal string with its decimal places, like "1234"
+// for JPY or "-1.234" for KWD.
+func (m Money) Format(currency Currency) string {
+	places := currency.MinorUnits()
+	if places == 0 {
+		return strconv.FormatInt(int64(m), 10)
+	}
+
+	return formatScaled(int64(m), pow10(places), places)
+}
+
+func leftPad2(n int64) string {
+	if n < 10 {
+		return "0" + strconv.FormatInt(n, 10)
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// Float64ToMoney converts float64 to Money, rounding to the nearest cent.
 func Float64ToMoney(f float64) Money {
-	return Money(f * 100)
+	return Money(math.Round(f * 100))
+}
+
+// ParseMoney converts a decimal string, like "1234.56", "-0.5" or "10", to Money without loss of precision.
+// It returns ErrMoneySubCentPrecision if the string has more than 2 decimal places.
+func ParseMoney(s string) (Money, error) {
+	cents, err := parseFixedPoint(s, 2)
+	if err != nil {
+		if err == errFixedPointPrecision {
+			return 0, ErrMoneySubCentPrecision
+		}
+		return 0, ErrMoneyInvalid
+	}
+
+	return Money(cents), nil
+}
+
+// ParseMoneyIn converts a decimal string, like "1234.56", "-0.5" or "10", to Money in the minor unit of the currency
+// without loss of precision. It returns ErrMoneyMinorUnitPrecision if the string has more decimal places than the
+// currency.
+func ParseMoneyIn(s string, currency Currency) (Money, error) {
+	value, err := parseFixedPoint(s, currency.MinorUnits())
+	if err != nil {
+		if err == errFixedPointPrecision {
+			return 0, ErrMoneyMinorUnitPrecision
+		}
+		return 0, ErrMoneyInvalid
+	}
+
+	return Money(value), nil
+}
+
+// ParseMoneyAwayFromZero converts a decimal string with up to MaxMinorUnits decimal places to Money in cents, rounding
+// the sub-cent precision away from zero, so a non-zero amount stays non-zero and keeps its sign.
+func ParseMoneyAwayFromZero(s string) (Money, error) {
+	value, err := parseFixedPoint(s, MaxMinorUnits)
+	if err != nil {
+		if err == errFixedPointPrecision {
+			return 0, ErrMoneyMinorUnitPrecision
+		}
+		return 0, ErrMoneyInvalid
+	}
+
+	scale := pow10(MaxMinorUnits - 2)
+	cents := value / scale
+	switch {
+	case value%scale > 0:
+		cents++
+	case value%scale < 0:
+		cents--
+	}
+
+	return Money(cents), nil
+}
+
+// pow10 returns 10^n for the small n of the decimal places.
+func pow10(n int) int64 {
+	result := int64(1)
+	for i := 0; i < n; i++ {
+		result *= 10
+	}
+
+	return result
+}
+
+var (
+	errFixedPointInvalid   = errors.New("invalid decimal number")
+	errFixedPointPrecision = errors.New("too many decimal places")
+)
+
+// parseFixedPoint converts a decimal string to an integer scaled by 10^places, without loss of precision.
+// It returns errFixedPointPrecision if the string has more than places decimal places.
+func parseFixedPoint(s string, places int) (int64, error) {
+	negative := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		negative = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	units, decimals := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		units, decimals = s[:i], s[i+1:]
+		if len(decimals) == 0 {
+			return 0, errFixedPointInvalid
+		}
+	}
+	if len(units) == 0 || !isDigits(units) || !isDigits(decimals) {
+		return 0, errFixedPointInvalid
+	}
+
+	decimals = strings.TrimRight(decimals, "0")
+	if len(decimals) > places {
+		return 0, errFixedPointPrecision
+	}
+	decimals += strings.Repeat("0", places-len(decimals))
+
+	value, err := strconv.ParseInt(units+decimals, 10, 64)
+	if err != nil {
+		return 0, errFixedPointInvalid
+	}
+
+	if negative {
+		value = -value
+	}
+
+	return value, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
 }
@@ -130,3 +130,333 @@ func TestMoney_Int64(t *testing.T) {
 		})
 	}
 }
+
+func TestFloat64ToMoney_rounding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		f    float64
+		want Money
+	}{
+		{
+			name: "0.29 should not be truncated",
+			f:    0.29,
+			want: 29,
+		},
+		{
+			name: "1.15 should not be truncated",
+			f:    1.15,
+			want: 115,
+		},
+		{
+			name: "negative 0.29 should not be truncated",
+			f:    -0.29,
+			want: -29,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Float64ToMoney(tt.f); got != tt.want {
+				t.Errorf("Float64ToMoney() = %v, want %v", int64(got), int64(tt.want))
+			}
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		m    Money
+		want string
+	}{
+		{
+			name: "zero",
+			m:    0,
+			want: "0.00",
+		},
+		{
+			name: "one cent",
+			m:    1,
+			want: "0.01",
+		},
+		{
+			name: "negative one cent",
+			m:    -1,
+			want: "-0.01",
+		},
+		{
+			name: "positive 112345295",
+			m:    112345295,
+			want: "1123452.95",
+		},
+		{
+			name: "negative 112345290",
+			m:    -112345290,
+			want: "-1123452.90",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    Money
+		wantErr error
+	}{
+		{
+			name: "integer",
+			s:    "10",
+			want: 1000,
+		},
+		{
+			name: "one decimal place",
+			s:    "10.5",
+			want: 1050,
+		},
+		{
+			name: "0.29 should be exact",
+			s:    "0.29",
+			want: 29,
+		},
+		{
+			name: "negative",
+			s:    "-1123452.95",
+			want: -112345295,
+		},
+		{
+			name: "explicit positive sign",
+			s:    "+1.01",
+			want: 101,
+		},
+		{
+			name: "trailing zeros beyond cents are not precision",
+			s:    "1.2300",
+			want: 123,
+		},
+		{
+			name:    "sub-cent precision should return error",
+			s:       "0.291",
+			wantErr: ErrMoneySubCentPrecision,
+		},
+		{
+			name:    "empty should return error",
+			s:       "",
+			wantErr: ErrMoneyInvalid,
+		},
+		{
+			name:    "missing decimals should return error",
+			s:       "1.",
+			wantErr: ErrMoneyInvalid,
+		},
+		{
+			name:    "missing units should return error",
+			s:       ".5",
+			wantErr: ErrMoneyInvalid,
+		},
+		{
+			name:    "exponent should return error",
+			s:       "1e2",
+			wantErr: ErrMoneyInvalid,
+		},
+		{
+			name:    "comma separator should return error",
+			s:       "1,50",
+			wantErr: ErrMoneyInvalid,
+		},
+		{
+			name:    "overflow should return error",
+			s:       "999999999999999999999",
+			wantErr: ErrMoneyInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.s)
+			if err != tt.wantErr {
+				t.Errorf("ParseMoney() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoney() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMoneyIn(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		s        string
+		currency Currency
+		want     Money
+		wantErr  error
+	}{
+		{
+			name:     "cents",
+			s:        "1234.56",
+			currency: CurrencyBRL,
+			want:     123456,
+		},
+		{
+			name:     "currency without minor unit",
+			s:        "1234",
+			currency: CurrencyJPY,
+			want:     1234,
+		},
+		{
+			name:     "trailing zeros are not precision",
+			s:        "1234.00",
+			currency: CurrencyJPY,
+			want:     1234,
+		},
+		{
+			name:     "decimals of a currency without minor unit should return error",
+			s:        "1234.5",
+			currency: CurrencyJPY,
+			wantErr:  ErrMoneyMinorUnitPrecision,
+		},
+		{
+			name:     "currency with 3 decimal places",
+			s:        "-1.234",
+			currency: CurrencyKWD,
+			want:     -1234,
+		},
+		{
+			name:     "fewer decimal places are padded",
+			s:        "1.5",
+			currency: CurrencyKWD,
+			want:     1500,
+		},
+		{
+			name:     "3 decimal places of a 2 decimal places currency should return error",
+			s:        "1.234",
+			currency: CurrencyUSD,
+			wantErr:  ErrMoneyMinorUnitPrecision,
+		},
+		{
+			name:     "invalid should return error",
+			s:        "1,5",
+			currency: CurrencyKWD,
+			wantErr:  ErrMoneyInvalid,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseMoneyIn(tt.s, tt.currency)
+			if err != tt.wantErr {
+				t.Errorf("ParseMoneyIn() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoneyIn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Format_Float64In(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		m           Money
+		currency    Currency
+		want        string
+		wantFloat64 float64
+	}{
+		{name: "cents", m: -123456, currency: CurrencyEUR, want: "-1234.56", wantFloat64: -1234.56},
+		{name: "currency without minor unit", m: 1234, currency: CurrencyJPY, want: "1234", wantFloat64: 1234},
+		{name: "negative currency without minor unit", m: -5, currency: CurrencyJPY, want: "-5", wantFloat64: -5},
+		{name: "currency with 3 decimal places", m: 1234, currency: CurrencyKWD, want: "1.234", wantFloat64: 1.234},
+		{name: "leading zeros of 3 decimal places", m: -5, currency: CurrencyKWD, want: "-0.005", wantFloat64: -0.005},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.m.Format(tt.currency); got != tt.want {
+				t.Errorf("Format() = %v, want %v", got, tt.want)
+			}
+			if got := tt.m.Float64In(tt.currency); got != tt.wantFloat64 {
+				t.Errorf("Float64In() = %v, want %v", got, tt.wantFloat64)
+			}
+		})
+	}
+}
+
+func TestCurrency_MinorUnits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		currency Currency
+		want     int
+	}{
+		{currency: CurrencyBRL, want: 2},
+		{currency: CurrencyUSD, want: 2},
+		{currency: CurrencyEUR, want: 2},
+		{currency: CurrencyJPY, want: 0},
+		{currency: CurrencyKWD, want: 3},
+		{currency: "XXX", want: 2},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(string(tt.currency), func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.currency.MinorUnits(); got != tt.want {
+				t.Errorf("MinorUnits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMoneyAwayFromZero(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    Money
+		wantErr error
+	}{
+		{name: "cents are exact", s: "1234.56", want: 123456},
+		{name: "sub-cent rounds up", s: "0.001", want: 1},
+		{name: "negative sub-cent rounds down", s: "-1.231", want: -124},
+		{name: "too many decimal places should return error", s: "0.0001", wantErr: ErrMoneyMinorUnitPrecision},
+		{name: "invalid should return error", s: "1e3", wantErr: ErrMoneyInvalid},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseMoneyAwayFromZero(tt.s)
+			if err != tt.wantErr {
+				t.Errorf("ParseMoneyAwayFromZero() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoneyAwayFromZero() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationID represents a Notification ID as uuid.
+type NotificationID string
+
+// NewNotificationID returns a new NotificationID with value generated by uuid.New().
+func NewNotificationID() NotificationID {
+	return NotificationID(uuid.NewString())
+}
+
+// NotificationType tells what happened to the subject of a notification.
+type NotificationType string
+
+const (
+	// NotificationStandingOrderRetrying tells the occurrence of a standing order failed and will be retried.
+	NotificationStandingOrderRetrying NotificationType = "standing_order_retrying"
+	// NotificationStandingOrderFailed tells the occurrence of a standing order failed for good and was skipped.
+	NotificationStandingOrderFailed NotificationType = "standing_order_failed"
+)
+
+// Notification represents a message to the holder of an account about something that happened without their action,
+// like a standing order that failed.
+type Notification struct {
+	ID          NotificationID
+	AccountID   AccountID
+	Type        NotificationType
+	ReferenceID string
+	Message     string
+	CreatedAt   time.Time
+}
+
+// NewNotification returns a new Notification filled with the corresponding arguments with generated values for id and createdAt.
+func NewNotification(accountID AccountID, notificationType NotificationType, referenceID string, message string) *Notification {
+	return &Notification{
+		ID:          NewNotificationID(),
+		AccountID:   accountID,
+		Type:        notificationType,
+		ReferenceID: referenceID,
+		Message:     message,
+		CreatedAt:   time.Now(),
+	}
+}
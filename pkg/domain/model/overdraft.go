@@ -0,0 +1,87 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInterestRateInvalid happens when an interest rate could not be parsed from a percentage decimal string.
+	ErrInterestRateInvalid = errors.New("invalid interest rate")
+)
+
+// InterestRate represents a percentage rate in basis points (hundredths of a percent), like 799 for 7.99%.
+// It is an integer to prevent floating point math problems.
+type InterestRate int64
+
+// ParseInterestRate converts a non-negative percentage decimal string, like "7.99" or "8", to InterestRate.
+func ParseInterestRate(s string) (InterestRate, error) {
+	// a percentage with 2 decimal places has the same format of a monetary amount
+	bps, err := ParseMoney(s)
+	if err != nil || bps < 0 {
+		return 0, ErrInterestRateInvalid
+	}
+
+	return InterestRate(bps), nil
+}
+
+// String formats InterestRate as a percentage, like "7.99%".
+func (r InterestRate) String() string {
+	return Money(r).String() + "%"
+}
+
+// OverdraftInterest returns the interest of one day on the negative balance at the monthly rate, pro rata over
+// 30 days, rounded half up to the cent. Zero or positive balances don't accrue interest.
+func OverdraftInterest(balance Money, monthlyRate InterestRate) Money {
+	if balance >= 0 {
+		return 0
+	}
+
+	const divisor = 10000 * 30
+	return Money((int64(-balance)*int64(monthlyRate) + divisor/2) / divisor)
+}
+
+// OverdraftInterestChargeID represents an OverdraftInterestCharge ID as uuid.
+type OverdraftInterestChargeID string
+
+// NewOverdraftInterestChargeID returns a new OverdraftInterestChargeID with value generated by uuid.New().
+func NewOverdraftInterestChargeID() OverdraftInterestChargeID {
+	return OverdraftInterestChargeID(uuid.NewString())
+}
+
+// OverdraftInterestCharge represents the interest accrued on the negative balance of an account for a day.
+// Each account is charged at most once a day. Amount can be zero, when the balance is too small to accrue a cent.
+type OverdraftInterestCharge struct {
+	ID        OverdraftInterestChargeID
+	AccountID AccountID
+	Day       time.Time
+	Balance   Money
+	Rate      InterestRate
+	Amount    Money
+	CreatedAt time.Time
+}
+
+// NewOverdraftInterestCharge returns a new OverdraftInterestCharge of the day on the closing balance of the account
+// at the monthly rate.
+func NewOverdraftInterestCharge(accountID AccountID, day time.Time, balance Money, monthlyRate InterestRate) *OverdraftInterestCharge {
+	return &OverdraftInterestCharge{
+		ID:        NewOverdraftInterestChargeID(),
+		AccountID: accountID,
+		Day:       day,
+		Balance:   balance,
+		Rate:      monthlyRate,
+		Amount:    OverdraftInterest(balance, monthlyRate),
+		CreatedAt: time.Now(),
+	}
+}
+
+// OverdraftInterestFilter selects the accounts charged the overdraft interest of the Day: the ones in the Currency of
+// the revenue account that were overdrawn at DayEnd. The revenue account itself is not charged.
+type OverdraftInterestFilter struct {
+	Day              time.Time
+	DayEnd           time.Time
+	Currency         Currency
+	RevenueAccountID AccountID
+}
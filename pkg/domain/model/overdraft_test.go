@@ -0,0 +1,104 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseInterestRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    InterestRate
+		wantErr bool
+	}{
+		{name: "decimal percentage", s: "7.99", want: 799},
+		{name: "integer percentage", s: "8", want: 800},
+		{name: "zero", s: "0", want: 0},
+		{name: "negative should fail", s: "-1.5", wantErr: true},
+		{name: "sub basis point should fail", s: "7.995", wantErr: true},
+		{name: "not a number should fail", s: "eight", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseInterestRate(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseInterestRate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseInterestRate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterestRate_String(t *testing.T) {
+	t.Parallel()
+
+	if got := InterestRate(799).String(); got != "7.99%" {
+		t.Errorf("String() = %v, want %v", got, "7.99%")
+	}
+}
+
+func TestOverdraftInterest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		balance     Money
+		monthlyRate InterestRate
+		want        Money
+	}{
+		{name: "positive balance accrues nothing", balance: 100000, monthlyRate: 800, want: 0},
+		{name: "zero balance accrues nothing", balance: 0, monthlyRate: 800, want: 0},
+		{name: "a month of 30 days", balance: -300000, monthlyRate: 1000, want: 1000},
+		{name: "rounds half up", balance: -150, monthlyRate: 1000, want: 1},
+		{name: "rounds down below half", balance: -149, monthlyRate: 1000, want: 0},
+		{name: "zero rate accrues nothing", balance: -100000, monthlyRate: 0, want: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := OverdraftInterest(tt.balance, tt.monthlyRate); got != tt.want {
+				t.Errorf("OverdraftInterest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewOverdraftInterestCharge(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)
+	got := NewOverdraftInterestCharge("uuid-1", day, -300000, 1000)
+
+	if len(got.ID) <= 0 {
+		t.Errorf("NewOverdraftInterestCharge() = %v, ID should not be empty", got)
+	}
+	got.ID = ""
+
+	if got.CreatedAt.Before(time.Now().Add(-5 * time.Second)) {
+		t.Errorf("NewOverdraftInterestCharge() got = %v, want CreatedAt in the last 5 seconds", got)
+	}
+	got.CreatedAt = time.Time{}
+
+	want := &OverdraftInterestCharge{
+		AccountID: "uuid-1",
+		Day:       day,
+		Balance:   -300000,
+		Rate:      1000,
+		Amount:    1000,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewOverdraftInterestCharge() = %v, want %v", got, want)
+	}
+}
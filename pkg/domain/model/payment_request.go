@@ -0,0 +1,115 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentRequestID represents a PaymentRequest ID as uuid.
+type PaymentRequestID string
+
+// NewPaymentRequestID returns a new PaymentRequestID with value generated by uuid.New().
+func NewPaymentRequestID() PaymentRequestID {
+	return PaymentRequestID(uuid.NewString())
+}
+
+// PaymentRequestStatus tells whether a payment request is still open or how it was closed.
+type PaymentRequestStatus string
+
+const (
+	// PaymentRequestStatusPending is the status of the requests waiting for the payer.
+	PaymentRequestStatusPending PaymentRequestStatus = "pending"
+	// PaymentRequestStatusPaid is the status of the requests approved by the payer, whose transfer was made.
+	PaymentRequestStatusPaid PaymentRequestStatus = "paid"
+	// PaymentRequestStatusDeclined is the status of the requests declined by the payer.
+	PaymentRequestStatusDeclined PaymentRequestStatus = "declined"
+	// PaymentRequestStatusExpired is the status of the requests not paid until their expiration.
+	PaymentRequestStatusExpired PaymentRequestStatus = "expired"
+)
+
+// IsValid checks whether it's a known status.
+func (s PaymentRequestStatus) IsValid() bool {
+	switch s {
+	case PaymentRequestStatusPending, PaymentRequestStatusPaid, PaymentRequestStatusDeclined, PaymentRequestStatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// PaymentRequest represents a charge the requester account asks to be paid.
+//
+// It's aimed at the PayerID account or, when it's empty, shared by link so any other account can pay it.
+// Once paid, it has the ID of the made transfer and the account that paid it.
+// Its Amount is in the Currency of the requester, which is not stored with it but read from the account.
+type PaymentRequest struct {
+	ID          PaymentRequestID
+	RequesterID AccountID
+	PayerID     AccountID
+	Amount      Money
+	Currency    Currency
+	Description string
+	ExpiresAt   time.Time
+	Status      PaymentRequestStatus
+	TransferID  TransferID
+	CreatedAt   time.Time
+	ClosedAt    time.Time
+}
+
+// NewPaymentRequest returns a new PaymentRequest filled with the corresponding arguments with generated values for id and createdAt.
+func NewPaymentRequest(requesterID, payerID AccountID, amount Money, description string, expiresAt time.Time) *PaymentRequest {
+	return &PaymentRequest{
+		ID:          NewPaymentRequestID(),
+		RequesterID: requesterID,
+		PayerID:     payerID,
+		Amount:      amount,
+		Description: description,
+		ExpiresAt:   expiresAt,
+		Status:      PaymentRequestStatusPending,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// IsShared checks whether the request is shared by link instead of aimed at a payer.
+func (pr *PaymentRequest) IsShared() bool {
+	return pr.PayerID == ""
+}
+
+// IsPending checks whether the request is still open.
+func (pr *PaymentRequest) IsPending() bool {
+	return pr.Status == PaymentRequestStatusPending
+}
+
+// IsExpired checks whether the request expiration has passed, even if it was not closed yet.
+func (pr *PaymentRequest) IsExpired(now time.Time) bool {
+	return !now.Before(pr.ExpiresAt)
+}
+
+// CanBeSeenBy checks whether the account is the requester, the payer or, for the shared requests, anyone.
+func (pr *PaymentRequest) CanBeSeenBy(accountID AccountID) bool {
+	return pr.IsShared() || accountID == pr.RequesterID || accountID == pr.PayerID
+}
+
+// CanBePaidBy checks whether the account is the payer or, for the shared requests, any account but the requester.
+func (pr *PaymentRequest) CanBePaidBy(accountID AccountID) bool {
+	if pr.IsShared() {
+		return accountID != pr.RequesterID
+	}
+
+	return accountID == pr.PayerID
+}
+
+// Paid records the transfer made by the payer.
+func (pr *PaymentRequest) Paid(payerID AccountID, transferID TransferID) {
+	pr.Status = PaymentRequestStatusPaid
+	pr.PayerID = payerID
+	pr.TransferID = transferID
+	pr.ClosedAt = time.Now()
+}
+
+// Declined records the payer declined the request.
+func (pr *PaymentRequest) Declined() {
+	pr.Status = PaymentRequestStatusDeclined
+	pr.ClosedAt = time.Now()
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPaymentRequest_access(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Now().Add(time.Hour)
+	aimed := NewPaymentRequest("uuid-1", "uuid-2", 1000, "Donuts", expiresAt)
+	shared := NewPaymentRequest("uuid-1", "", 1000, "Donuts", expiresAt)
+
+	tests := []struct {
+		name        string
+		request     *PaymentRequest
+		accountID   AccountID
+		wantSeen    bool
+		wantPayable bool
+	}{
+		{name: "requester of aimed request", request: aimed, accountID: "uuid-1", wantSeen: true, wantPayable: false},
+		{name: "payer of aimed request", request: aimed, accountID: "uuid-2", wantSeen: true, wantPayable: true},
+		{name: "other account of aimed request", request: aimed, accountID: "uuid-3", wantSeen: false, wantPayable: false},
+		{name: "requester of shared request", request: shared, accountID: "uuid-1", wantSeen: true, wantPayable: false},
+		{name: "any account of shared request", request: shared, accountID: "uuid-3", wantSeen: true, wantPayable: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.request.CanBeSeenBy(tt.accountID); got != tt.wantSeen {
+				t.Errorf("CanBeSeenBy() = %v, want %v", got, tt.wantSeen)
+			}
+			if got := tt.request.CanBePaidBy(tt.accountID); got != tt.wantPayable {
+				t.Errorf("CanBePaidBy() = %v, want %v", got, tt.wantPayable)
+			}
+		})
+	}
+}
+
+func TestPaymentRequest_outcomes(t *testing.T) {
+	t.Parallel()
+
+	request := NewPaymentRequest("uuid-1", "", 1000, "Donuts", time.Now().Add(time.Hour))
+	if !request.IsPending() || !request.IsShared() {
+		t.Fatalf("NewPaymentRequest() = %v, want pending and shared", request)
+	}
+
+	request.Paid("uuid-3", "transfer-uuid")
+	if request.IsPending() || request.Status != PaymentRequestStatusPaid || request.PayerID != "uuid-3" || request.TransferID != "transfer-uuid" {
+		t.Errorf("Paid() got = %v, want paid by uuid-3 with the transfer ID", request)
+	}
+	if request.ClosedAt.IsZero() {
+		t.Errorf("Paid() got = %v, want ClosedAt", request)
+	}
+
+	request = NewPaymentRequest("uuid-1", "uuid-2", 1000, "Donuts", time.Now().Add(time.Hour))
+	request.Declined()
+	if request.IsPending() || request.Status != PaymentRequestStatusDeclined || request.ClosedAt.IsZero() {
+		t.Errorf("Declined() got = %v, want declined with ClosedAt", request)
+	}
+}
+
+func TestPaymentRequest_IsExpired(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Date(2021, 1, 31, 9, 0, 0, 0, time.UTC)
+	request := NewPaymentRequest("uuid-1", "uuid-2", 1000, "Donuts", expiresAt)
+
+	if request.IsExpired(expiresAt.Add(-time.Nanosecond)) {
+		t.Errorf("IsExpired() = true, want false before the expiration")
+	}
+	if !request.IsExpired(expiresAt) {
+		t.Errorf("IsExpired() = false, want true at the expiration")
+	}
+}
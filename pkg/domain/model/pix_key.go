@@ -0,0 +1,219 @@
+package model
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/cpfutil"
+)
+
+var (
+	// ErrPixKeyInvalid happens when a value is not a valid key of its type.
+	ErrPixKeyInvalid = errors.New("invalid pix key")
+)
+
+const (
+	// MaxPixKeysPerAccount is how many keys an account can have, including the ones pending verification.
+	// The pix_keys table enforces it too, so it must be changed along with its slot check.
+	MaxPixKeysPerAccount = 5
+	// PixKeyVerificationTTL is how long a verification code can be used.
+	PixKeyVerificationTTL = 15 * time.Minute
+	// PixKeyMaxVerificationAttempts is how many wrong codes are accepted before the verification expires.
+	PixKeyMaxVerificationAttempts = 3
+)
+
+// emailRegexp is a simplified version of the HTML5 email validation, applied to lowercase addresses.
+var emailRegexp = regexp.MustCompile(`^[a-z0-9.!#$%&'*+/=?^_{|}~-]+@[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?(?:\.[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?)+$`) //nolint:gochecknoglobals
+
+// phoneRegexp matches the Brazilian phone numbers in the E.164 format, like +5511987654321.
+var phoneRegexp = regexp.MustCompile(`^\+55[1-9][0-9]{9,10}$`) //nolint:gochecknoglobals
+
+// PixKeyID represents a PixKey ID as uuid.
+type PixKeyID string
+
+// NewPixKeyID returns a new PixKeyID with value generated by uuid.New().
+func NewPixKeyID() PixKeyID {
+	return PixKeyID(uuid.NewString())
+}
+
+// PixKeyType tells what a key is made of.
+type PixKeyType string
+
+const (
+	// PixKeyTypeCPF is the CPF of the account holder, only digits.
+	PixKeyTypeCPF PixKeyType = "cpf"
+	// PixKeyTypeEmail is an email address of the account holder, lowercase. It must be verified.
+	PixKeyTypeEmail PixKeyType = "email"
+	// PixKeyTypePhone is a phone number of the account holder, in the E.164 format.
+	PixKeyTypePhone PixKeyType = "phone"
+	// PixKeyTypeRandom is a uuid generated for the account, for holders who don't want to share their data.
+	PixKeyTypeRandom PixKeyType = "random"
+)
+
+// IsValid checks whether it's a known key type.
+func (t PixKeyType) IsValid() bool {
+	switch t {
+	case PixKeyTypeCPF, PixKeyTypeEmail, PixKeyTypePhone, PixKeyTypeRandom:
+		return true
+	default:
+		return false
+	}
+}
+
+// NormalizePixKey returns the value in the format its keys are saved, or ErrPixKeyInvalid if it's not a valid key of the type.
+// CPFs and phones can have separators, like 123.456.789-09 and +55 (11) 98765-4321.
+func NormalizePixKey(keyType PixKeyType, value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	switch keyType {
+	case PixKeyTypeCPF:
+		if !cpfutil.IsValid(value) {
+			return "", ErrPixKeyInvalid
+		}
+		return cpfutil.Clean(value), nil
+	case PixKeyTypeEmail:
+		value = strings.ToLower(value)
+		if len(value) > 77 || !emailRegexp.MatchString(value) {
+			return "", ErrPixKeyInvalid
+		}
+		return value, nil
+	case PixKeyTypePhone:
+		value = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(value)
+		if !phoneRegexp.MatchString(value) {
+			return "", ErrPixKeyInvalid
+		}
+		return value, nil
+	case PixKeyTypeRandom:
+		id, err := uuid.Parse(value)
+		if err != nil || len(value) != 36 {
+			return "", ErrPixKeyInvalid
+		}
+		return id.String(), nil
+	default:
+		return "", ErrPixKeyInvalid
+	}
+}
+
+// ParsePixKey infers the type of the key from its value and normalizes it.
+// Emails have an @, phones start with + and random keys are uuids, otherwise it must be a CPF.
+func ParsePixKey(value string) (PixKeyType, string, error) {
+	value = strings.TrimSpace(value)
+
+	var keyType PixKeyType
+	switch {
+	case strings.Contains(value, "@"):
+		keyType = PixKeyTypeEmail
+	case strings.HasPrefix(value, "+"):
+		keyType = PixKeyTypePhone
+	case len(value) == 36:
+		keyType = PixKeyTypeRandom
+	default:
+		keyType = PixKeyTypeCPF
+	}
+
+	value, err := NormalizePixKey(keyType, value)
+	if err != nil {
+		return "", "", err
+	}
+
+	return keyType, value, nil
+}
+
+// PixKeyStatus tells whether a key can receive transfers.
+type PixKeyStatus string
+
+const (
+	// PixKeyStatusPending is the status of the keys waiting for the holder to confirm they own them.
+	PixKeyStatusPending PixKeyStatus = "pending"
+	// PixKeyStatusActive is the status of the keys that can be looked up and receive transfers.
+	PixKeyStatusActive PixKeyStatus = "active"
+)
+
+// PixKey represents a key addressing an account in transfers, like the Brazilian Pix keys.
+// Each key belongs to a single account.
+//
+// The email keys start pending, with a verification code sent to the address. VerificationCode is its bcrypt hash.
+type PixKey struct {
+	ID                    PixKeyID
+	AccountID             AccountID
+	Type                  PixKeyType
+	Value                 string
+	Status                PixKeyStatus
+	VerificationCode      string
+	VerificationExpiresAt time.Time
+	VerificationAttempts  int
+	CreatedAt             time.Time
+}
+
+// NewPixKey returns a new PixKey of the account with generated values for id and createdAt.
+// The value must be normalized. The email keys are pending, the others are active.
+func NewPixKey(accountID AccountID, keyType PixKeyType, value string) *PixKey {
+	status := PixKeyStatusActive
+	if keyType == PixKeyTypeEmail {
+		status = PixKeyStatusPending
+	}
+
+	return &PixKey{
+		ID:        NewPixKeyID(),
+		AccountID: accountID,
+		Type:      keyType,
+		Value:     value,
+		Status:    status,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NewRandomPixKeyValue returns a new value for a key of type PixKeyTypeRandom.
+func NewRandomPixKeyValue() string {
+	return uuid.NewString()
+}
+
+// IsActive checks whether the key can be looked up and receive transfers.
+func (k *PixKey) IsActive() bool {
+	return k.Status == PixKeyStatusActive
+}
+
+// StartVerification generates a new 6-digit verification code, keeping its hash, and returns it to be sent.
+func (k *PixKey) StartVerification() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	hashedCode, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	k.VerificationCode = string(hashedCode)
+	k.VerificationExpiresAt = time.Now().Add(PixKeyVerificationTTL)
+	k.VerificationAttempts = 0
+	return code, nil
+}
+
+// IsVerificationExpired checks whether the verification code can't be used anymore, because of its age or
+// the wrong codes tried.
+func (k *PixKey) IsVerificationExpired() bool {
+	return k.VerificationAttempts >= PixKeyMaxVerificationAttempts || !time.Now().Before(k.VerificationExpiresAt)
+}
+
+// Verify activates the key if the code is right, otherwise it counts the attempt and returns false.
+func (k *PixKey) Verify(code string) bool {
+	if bcrypt.CompareHashAndPassword([]byte(k.VerificationCode), []byte(code)) != nil {
+		k.VerificationAttempts++
+		return false
+	}
+
+	k.Status = PixKeyStatusActive
+	k.VerificationCode = ""
+	return true
+}
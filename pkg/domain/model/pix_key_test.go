@@ -0,0 +1,145 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizePixKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		keyType PixKeyType
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "formatted cpf", keyType: PixKeyTypeCPF, value: "599.513.320-99", want: "59951332099"},
+		{name: "invalid cpf should fail", keyType: PixKeyTypeCPF, value: "599.513.320-98", wantErr: true},
+		{name: "email is lowercased", keyType: PixKeyTypeEmail, value: " Homer@Springfield.com ", want: "homer@springfield.com"},
+		{name: "email without domain should fail", keyType: PixKeyTypeEmail, value: "homer@", wantErr: true},
+		{name: "too long email should fail", keyType: PixKeyTypeEmail, value: strings.Repeat("a", 70) + "@springfield.com", wantErr: true},
+		{name: "formatted phone", keyType: PixKeyTypePhone, value: "+55 (11) 98765-4321", want: "+5511987654321"},
+		{name: "landline phone", keyType: PixKeyTypePhone, value: "+551133334444", want: "+551133334444"},
+		{name: "phone without country code should fail", keyType: PixKeyTypePhone, value: "11987654321", wantErr: true},
+		{name: "foreign phone should fail", keyType: PixKeyTypePhone, value: "+14155552671", wantErr: true},
+		{name: "random", keyType: PixKeyTypeRandom, value: "1A2B3C4D-5E6F-4A8B-9C0D-1E2F3A4B5C6D", want: "1a2b3c4d-5e6f-4a8b-9c0d-1e2f3a4b5c6d"},
+		{name: "random without dashes should fail", keyType: PixKeyTypeRandom, value: "1a2b3c4d5e6f4a8b9c0d1e2f3a4b5c6d", wantErr: true},
+		{name: "unknown type should fail", keyType: "iban", value: "homer@springfield.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NormalizePixKey(tt.keyType, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NormalizePixKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NormalizePixKey() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePixKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		value     string
+		wantType  PixKeyType
+		wantValue string
+		wantErr   bool
+	}{
+		{name: "email", value: "Homer@Springfield.com", wantType: PixKeyTypeEmail, wantValue: "homer@springfield.com"},
+		{name: "phone", value: "+5511987654321", wantType: PixKeyTypePhone, wantValue: "+5511987654321"},
+		{name: "random", value: "1a2b3c4d-5e6f-4a8b-9c0d-1e2f3a4b5c6d", wantType: PixKeyTypeRandom, wantValue: "1a2b3c4d-5e6f-4a8b-9c0d-1e2f3a4b5c6d"},
+		{name: "cpf", value: "599.513.320-99", wantType: PixKeyTypeCPF, wantValue: "59951332099"},
+		{name: "anything else should fail", value: "homer", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotType, gotValue, err := ParsePixKey(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePixKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotType != tt.wantType || gotValue != tt.wantValue {
+				t.Errorf("ParsePixKey() got = %v, %v, want %v, %v", gotType, gotValue, tt.wantType, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestNewPixKey(t *testing.T) {
+	t.Parallel()
+
+	if key := NewPixKey("uuid-1", PixKeyTypeEmail, "homer@springfield.com"); key.IsActive() {
+		t.Errorf("NewPixKey() email status = %v, want %v", key.Status, PixKeyStatusPending)
+	}
+	if key := NewPixKey("uuid-1", PixKeyTypePhone, "+5511987654321"); !key.IsActive() {
+		t.Errorf("NewPixKey() phone status = %v, want %v", key.Status, PixKeyStatusActive)
+	}
+	if value := NewRandomPixKeyValue(); len(value) != 36 {
+		t.Errorf("NewRandomPixKeyValue() = %v, want a uuid", value)
+	}
+}
+
+func TestPixKey_Verify(t *testing.T) {
+	t.Parallel()
+
+	key := NewPixKey("uuid-1", PixKeyTypeEmail, "homer@springfield.com")
+	code, err := key.StartVerification()
+	if err != nil {
+		t.Fatalf("StartVerification() error = %v", err)
+	}
+	if len(code) != 6 || key.VerificationCode == code {
+		t.Fatalf("StartVerification() code = %v, hash = %v, want 6 digits kept hashed", code, key.VerificationCode)
+	}
+	if key.IsVerificationExpired() || key.VerificationExpiresAt.Before(time.Now().Add(PixKeyVerificationTTL-time.Minute)) {
+		t.Fatalf("StartVerification() expiresAt = %v, want %v from now", key.VerificationExpiresAt, PixKeyVerificationTTL)
+	}
+
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "111111"
+	}
+	if key.Verify(wrongCode) || key.IsActive() || key.VerificationAttempts != 1 {
+		t.Fatalf("Verify() wrong code = %+v, want the attempt counted", key)
+	}
+	if !key.Verify(code) || !key.IsActive() || key.VerificationCode != "" {
+		t.Fatalf("Verify() right code = %+v, want the key active", key)
+	}
+}
+
+func TestPixKey_IsVerificationExpired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		key  PixKey
+		want bool
+	}{
+		{name: "fresh", key: PixKey{VerificationExpiresAt: time.Now().Add(time.Minute)}, want: false},
+		{name: "too old", key: PixKey{VerificationExpiresAt: time.Now().Add(-time.Second)}, want: true},
+		{name: "too many attempts", key: PixKey{VerificationExpiresAt: time.Now().Add(time.Minute), VerificationAttempts: PixKeyMaxVerificationAttempts}, want: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.key.IsVerificationExpired(); got != tt.want {
+				t.Errorf("IsVerificationExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
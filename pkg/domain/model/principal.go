@@ -0,0 +1,35 @@
+package model
+
+// Principal represents the authenticated account performing an operation.
+type Principal struct {
+	AccountID AccountID
+	Roles     []Role
+	Scopes    []Scope
+}
+
+// HasRole checks whether the principal was granted the role.
+func (p Principal) HasRole(role Role) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasScope checks whether the principal was granted the scope.
+func (p Principal) HasScope(scope Scope) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Owns checks whether the principal is the owner of the account.
+func (p Principal) Owns(accountID AccountID) bool {
+	return p.AccountID != "" && p.AccountID == accountID
+}
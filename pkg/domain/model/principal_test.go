@@ -0,0 +1,83 @@
+package model
+
+import "testing"
+
+func TestPrincipal_HasScope(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		principal Principal
+		scope     Scope
+		want      bool
+	}{
+		{
+			name:      "no scopes",
+			principal: Principal{AccountID: "uuid-1"},
+			scope:     ScopeAccountsRead,
+			want:      false,
+		},
+		{
+			name:      "other scope",
+			principal: Principal{AccountID: "uuid-1", Scopes: []Scope{"transfers:read"}},
+			scope:     ScopeAccountsRead,
+			want:      false,
+		},
+		{
+			name:      "granted scope",
+			principal: Principal{AccountID: "uuid-1", Scopes: []Scope{"transfers:read", ScopeAccountsRead}},
+			scope:     ScopeAccountsRead,
+			want:      true,
+		},
+		{
+			name:      "role without the scope",
+			principal: Principal{AccountID: "uuid-1", Roles: []Role{RoleAdmin}},
+			scope:     ScopeAccountsRead,
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.principal.HasScope(tt.scope); got != tt.want {
+				t.Errorf("HasScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrincipal_Owns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		principal Principal
+		accountID AccountID
+		want      bool
+	}{
+		{
+			name:      "same account",
+			principal: Principal{AccountID: "uuid-1"},
+			accountID: "uuid-1",
+			want:      true,
+		},
+		{
+			name:      "other account",
+			principal: Principal{AccountID: "uuid-1"},
+			accountID: "uuid-2",
+			want:      false,
+		},
+		{
+			name:      "anonymous principal",
+			principal: Principal{},
+			accountID: "",
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.principal.Owns(tt.accountID); got != tt.want {
+				t.Errorf("Owns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
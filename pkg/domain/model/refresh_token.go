@@ -0,0 +1,50 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenFamilyID identifies all the refresh tokens rotated from the same login.
+type RefreshTokenFamilyID string
+
+// NewRefreshTokenFamilyID returns a new RefreshTokenFamilyID with value generated by uuid.New().
+func NewRefreshTokenFamilyID() RefreshTokenFamilyID {
+	return RefreshTokenFamilyID(uuid.NewString())
+}
+
+// RefreshToken represents an opaque token used to issue new access tokens without logging in again.
+type RefreshToken struct {
+	Token     string
+	FamilyID  RefreshTokenFamilyID
+	AccountID AccountID
+	ExpiresAt time.Time
+}
+
+// NewRefreshToken returns a new RefreshToken with a random token value, valid for the given duration.
+// If familyID is empty, a new family is started.
+func NewRefreshToken(accountID AccountID, familyID RefreshTokenFamilyID, duration time.Duration) (*RefreshToken, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	if familyID == "" {
+		familyID = NewRefreshTokenFamilyID()
+	}
+
+	return &RefreshToken{
+		Token:     base64.RawURLEncoding.EncodeToString(b),
+		FamilyID:  familyID,
+		AccountID: accountID,
+		ExpiresAt: time.Now().Add(duration),
+	}, nil
+}
+
+// IsExpired checks whether the refresh token is no longer valid.
+func (rt *RefreshToken) IsExpired() bool {
+	return !time.Now().Before(rt.ExpiresAt)
+}
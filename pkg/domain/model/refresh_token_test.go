@@ -0,0 +1,74 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		accountID AccountID
+		familyID  RefreshTokenFamilyID
+		duration  time.Duration
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantFamilyID RefreshTokenFamilyID
+		wantExpired  bool
+	}{
+		{
+			name: "empty family should start a new family",
+			args: args{
+				accountID: "uuid-1",
+				familyID:  "",
+				duration:  time.Hour,
+			},
+			wantFamilyID: "",
+			wantExpired:  false,
+		},
+		{
+			name: "existing family should be kept",
+			args: args{
+				accountID: "uuid-1",
+				familyID:  "family-1",
+				duration:  time.Hour,
+			},
+			wantFamilyID: "family-1",
+			wantExpired:  false,
+		},
+		{
+			name: "zero duration should be expired",
+			args: args{
+				accountID: "uuid-1",
+				familyID:  "family-1",
+				duration:  0,
+			},
+			wantFamilyID: "family-1",
+			wantExpired:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRefreshToken(tt.args.accountID, tt.args.familyID, tt.args.duration)
+			if err != nil {
+				t.Errorf("NewRefreshToken() error = %v", err)
+				return
+			}
+			if len(got.Token) < 43 {
+				t.Errorf("NewRefreshToken() Token = %v, should have at least 256 bits", got.Token)
+			}
+			if got.FamilyID == "" || (tt.wantFamilyID != "" && got.FamilyID != tt.wantFamilyID) {
+				t.Errorf("NewRefreshToken() FamilyID = %v, want %v", got.FamilyID, tt.wantFamilyID)
+			}
+			if got.AccountID != tt.args.accountID {
+				t.Errorf("NewRefreshToken() AccountID = %v, want %v", got.AccountID, tt.args.accountID)
+			}
+			if got.IsExpired() != tt.wantExpired {
+				t.Errorf("IsExpired() = %v, want %v", got.IsExpired(), tt.wantExpired)
+			}
+		})
+	}
+}
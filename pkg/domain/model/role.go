@@ -0,0 +1,56 @@
+package model
+
+// Role represents an authorization role granted to an account.
+// Customer accounts have no role.
+type Role string
+
+const (
+	// RoleAdmin is granted to the back-office administrators.
+	RoleAdmin Role = "admin"
+	// RoleOperator is granted to the back-office operators.
+	RoleOperator Role = "operator"
+)
+
+// Scope represents a permission granted by the roles, carried in the access token.
+type Scope string
+
+const (
+	// ScopeAccountsRead allows reading the data of every account, like the full CPF and the balance.
+	ScopeAccountsRead Scope = "accounts:read"
+	// ScopeAccountsWrite allows changing the status of every account, like blocking or closing it.
+	ScopeAccountsWrite Scope = "accounts:write"
+	// ScopeCashDeposit allows depositing cash into every account.
+	ScopeCashDeposit Scope = "cash:deposit"
+	// ScopeTransfersReverse allows reversing the transfers of every account.
+	ScopeTransfersReverse Scope = "transfers:reverse"
+	// ScopeAccountsCredit allows setting the credit limit of every account, how far below zero its balance can go.
+	ScopeAccountsCredit Scope = "accounts:credit"
+)
+
+// roleScopes holds the scopes granted by each role.
+var roleScopes = map[Role][]Scope{ //nolint:gochecknoglobals
+	RoleAdmin:    {ScopeAccountsRead, ScopeAccountsWrite, ScopeCashDeposit, ScopeTransfersReverse, ScopeAccountsCredit},
+	RoleOperator: {ScopeAccountsRead, ScopeCashDeposit, ScopeTransfersReverse, ScopeAccountsCredit},
+}
+
+// IsValid checks whether it's a known role.
+func (r Role) IsValid() bool {
+	_, ok := roleScopes[r]
+	return ok
+}
+
+// ScopesOf returns the scopes granted by the roles, without duplicates.
+func ScopesOf(roles []Role) []Scope {
+	var scopes []Scope
+	granted := make(map[Scope]bool)
+	for _, role := range roles {
+		for _, scope := range roleScopes[role] {
+			if !granted[scope] {
+				granted[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	return scopes
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRole_IsValid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		role Role
+		want bool
+	}{
+		{
+			name: "admin",
+			role: RoleAdmin,
+			want: true,
+		},
+		{
+			name: "operator",
+			role: RoleOperator,
+			want: true,
+		},
+		{
+			name: "unknown",
+			role: "root",
+			want: false,
+		},
+		{
+			name: "empty",
+			role: "",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.role.IsValid(); got != tt.want {
+				t.Errorf("IsValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopesOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		roles []Role
+		want  []Scope
+	}{
+		{
+			name:  "customer has no scopes",
+			roles: nil,
+			want:  nil,
+		},
+		{
+			name:  "unknown role has no scopes",
+			roles: []Role{"root"},
+			want:  nil,
+		},
+		{
+			name:  "operator",
+			roles: []Role{RoleOperator},
+			want:  []Scope{ScopeAccountsRead, ScopeCashDeposit, ScopeTransfersReverse, ScopeAccountsCredit},
+		},
+		{
+			name:  "admin",
+			roles: []Role{RoleAdmin},
+			want:  []Scope{ScopeAccountsRead, ScopeAccountsWrite, ScopeCashDeposit, ScopeTransfersReverse, ScopeAccountsCredit},
+		},
+		{
+			name:  "roles sharing scopes should not duplicate them",
+			roles: []Role{RoleOperator, RoleAdmin},
+			want:  []Scope{ScopeAccountsRead, ScopeCashDeposit, ScopeTransfersReverse, ScopeAccountsCredit, ScopeAccountsWrite},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScopesOf(tt.roles); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ScopesOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
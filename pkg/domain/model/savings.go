@@ -0,0 +1,203 @@
+package model
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavingsRateDecimals is the number of decimal places of the daily savings rates, like the DI daily factor.
+const SavingsRateDecimals = 8
+
+// savingsRateScale is 10^SavingsRateDecimals.
+const savingsRateScale = 100_000_000
+
+// BusinessDaysPerYear is the number of business days the annual savings rate is compounded over.
+const BusinessDaysPerYear = 252
+
+// SavingsDailyRate represents the rate of one business day, like 0.00050788 for 13.65% a year.
+// It is an integer, scaled by 10^SavingsRateDecimals, to prevent floating point math problems.
+type SavingsDailyRate int64
+
+// NewSavingsDailyRate returns the business day rate equivalent to the annual rate compounded over BusinessDaysPerYear,
+// (1 + annualRate)^(1/252) - 1, rounded half up to SavingsRateDecimals decimal places.
+//
+// The root is searched in integers instead of computed in floating point: the rate is the largest one whose factor
+// compounded over the year doesn't exceed the annual factor, plus one when the factor half a unit above doesn't either.
+func NewSavingsDailyRate(annualRate InterestRate) SavingsDailyRate {
+	if annualRate <= 0 {
+		return 0
+	}
+
+	// notAbove checks whether (num/den)^252 <= 1 + annualRate, as 10000 * num^252 <= (10000 + annualRate) * den^252
+	notAbove := func(num, den int64) bool {
+		days := big.NewInt(BusinessDaysPerYear)
+		lhs := new(big.Int).Exp(big.NewInt(num), days, nil)
+		lhs.Mul(lhs, big.NewInt(10000))
+		rhs := new(big.Int).Exp(big.NewInt(den), days, nil)
+		rhs.Mul(rhs, big.NewInt(10000+int64(annualRate)))
+		return lhs.Cmp(rhs) <= 0
+	}
+
+	// (1 + d)^252 > 1 + 252d, so the rate is below annualRate/252
+	low, high := int64(0), int64(annualRate)*savingsRateScale/10000/BusinessDaysPerYear+1
+	for low+1 < high {
+		mid := (low + high) / 2
+		if notAbove(savingsRateScale+mid, savingsRateScale) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	if notAbove(2*(savingsRateScale+low)+1, 2*savingsRateScale) {
+		low++
+	}
+
+	return SavingsDailyRate(low)
+}
+
+// String formats SavingsDailyRate as a decimal string with SavingsRateDecimals decimal places, like "0.00050788".
+func (r SavingsDailyRate) String() string {
+	return formatScaled(int64(r), savingsRateScale, SavingsRateDecimals)
+}
+
+// AccruedInterest represents an amount of savings interest in the minor unit of its currency, scaled by
+// 10^SavingsRateDecimals, so the interest of the days is summed without rounding.
+type AccruedInterest int64
+
+// SavingsInterest returns the exact interest of one business day on the positive balance at the daily rate.
+// Zero or negative balances don't accrue interest.
+func SavingsInterest(balance Money, dailyRate SavingsDailyRate) AccruedInterest {
+	if balance <= 0 {
+		return 0
+	}
+
+	return AccruedInterest(int64(balance) * int64(dailyRate))
+}
+
+// Truncate returns the whole minor units of the interest, discarding the fraction.
+func (i AccruedInterest) Truncate() Money {
+	return Money(int64(i) / savingsRateScale)
+}
+
+// String formats AccruedInterest as a decimal string with all its decimal places, like "5.0788000000" for 5.0788
+// units of a currency with 2 minor units.
+func (i AccruedInterest) String() string {
+	return formatScaled(int64(i), 100*savingsRateScale, 2+SavingsRateDecimals)
+}
+
+// formatScaled formats the value scaled by scale, which is 10^places, as a decimal string with places decimal places.
+func formatScaled(value, scale int64, places int) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	decimals := strconv.FormatInt(value%scale, 10)
+	return sign + strconv.FormatInt(value/scale, 10) + "." + strings.Repeat("0", places-len(decimals)) + decimals
+}
+
+// SavingsInterestAccrualID represents a SavingsInterestAccrual ID as uuid.
+type SavingsInterestAccrualID string
+
+// NewSavingsInterestAccrualID returns a new SavingsInterestAccrualID with value generated by uuid.New().
+func NewSavingsInterestAccrualID() SavingsInterestAccrualID {
+	return SavingsInterestAccrualID(uuid.NewString())
+}
+
+// SavingsInterestAccrual represents the interest of a business day on the closing balance of an account.
+// Each account accrues at most once a day. Amount is zero when the balance was not positive at the end of the day.
+type SavingsInterestAccrual struct {
+	ID        SavingsInterestAccrualID
+	AccountID AccountID
+	Day       time.Time
+	Balance   Money
+	Rate      SavingsDailyRate
+	Amount    AccruedInterest
+	CreatedAt time.Time
+}
+
+// NewSavingsInterestAccrual returns a new SavingsInterestAccrual of the day on the account closing balance at the
+// daily rate.
+func NewSavingsInterestAccrual(accountID AccountID, day time.Time, balance Money, dailyRate SavingsDailyRate) *SavingsInterestAccrual {
+	return &SavingsInterestAccrual{
+		ID:        NewSavingsInterestAccrualID(),
+		AccountID: accountID,
+		Day:       day,
+		Balance:   balance,
+		Rate:      dailyRate,
+		Amount:    SavingsInterest(balance, dailyRate),
+		CreatedAt: time.Now(),
+	}
+}
+
+// SavingsInterestPaymentID represents a SavingsInterestPayment ID as uuid.
+type SavingsInterestPaymentID string
+
+// NewSavingsInterestPaymentID returns a new SavingsInterestPaymentID with value generated by uuid.New().
+func NewSavingsInterestPaymentID() SavingsInterestPaymentID {
+	return SavingsInterestPaymentID(uuid.NewString())
+}
+
+// SavingsInterestPayment represents the interest of a month credited to an account.
+// Each account is paid at most once a month, Month being the midnight that starts it. Amount can be zero, when the
+// interest accrued so far doesn't reach a whole minor unit.
+type SavingsInterestPayment struct {
+	ID        SavingsInterestPaymentID
+	AccountID AccountID
+	Month     time.Time
+	Amount    Money
+	CreatedAt time.Time
+}
+
+// NewSavingsInterestPayment returns a new SavingsInterestPayment of the amount to the account for the month.
+func NewSavingsInterestPayment(accountID AccountID, month time.Time, amount Money) *SavingsInterestPayment {
+	return &SavingsInterestPayment{
+		ID:        NewSavingsInterestPaymentID(),
+		AccountID: accountID,
+		Month:     month,
+		Amount:    amount,
+		CreatedAt: time.Now(),
+	}
+}
+
+// SavingsInterestTotals represents all the interest accrued by an account up to a time and all that was paid to it.
+type SavingsInterestTotals struct {
+	Accrued AccruedInterest
+	Paid    Money
+}
+
+// Unpaid returns the whole minor units of the accrued interest not paid yet. The fractions are never paid alone,
+// they're carried until they add up to a whole minor unit, so the payments never drift from the accruals.
+func (t SavingsInterestTotals) Unpaid() Money {
+	unpaid := t.Accrued.Truncate() - t.Paid
+	if unpaid < 0 {
+		return 0
+	}
+
+	return unpaid
+}
+
+// SavingsAccrualFilter selects the accounts that accrue the interest of the Day: the ones in the Currency of the
+// treasury account, created before the day ended, not closed and with a positive balance at DayEnd. The treasury
+// account itself doesn't accrue it.
+type SavingsAccrualFilter struct {
+	Day               time.Time
+	DayEnd            time.Time
+	Currency          Currency
+	TreasuryAccountID AccountID
+}
+
+// SavingsPaymentFilter selects the accounts that are owed the interest of the Month: the ones in the Currency of the
+// treasury account that accrued interest in it and are not closed. The treasury account itself is never owed it.
+type SavingsPaymentFilter struct {
+	Month             time.Time
+	MonthEnd          time.Time
+	Currency          Currency
+	TreasuryAccountID AccountID
+}
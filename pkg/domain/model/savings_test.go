@@ -0,0 +1,156 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewSavingsDailyRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		annualRate InterestRate
+		want       SavingsDailyRate
+	}{
+		{name: "CDI at 13.65% a year", annualRate: 1365, want: 50788},
+		{name: "CDI at 13.75% a year", annualRate: 1375, want: 51137},
+		{name: "CDI at 10.65% a year", annualRate: 1065, want: 40168},
+		{name: "CDI at 6.50% a year", annualRate: 650, want: 24993},
+		{name: "CDI at 2.00% a year", annualRate: 200, want: 7858},
+		{name: "rounds up above half", annualRate: 1000, want: 37829},
+		{name: "CDI at 15.25% a year", annualRate: 1525, want: 56339},
+		{name: "rounds down below half", annualRate: 1, want: 40},
+		{name: "doubles in a year", annualRate: 10000, want: 275437},
+		{name: "high rate", annualRate: 99999, want: 956084},
+		{name: "zero rate", annualRate: 0, want: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := NewSavingsDailyRate(tt.annualRate); got != tt.want {
+				t.Errorf("NewSavingsDailyRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSavingsDailyRate_String(t *testing.T) {
+	t.Parallel()
+
+	if got := SavingsDailyRate(50788).String(); got != "0.00050788" {
+		t.Errorf("String() = %v, want %v", got, "0.00050788")
+	}
+}
+
+func TestSavingsInterest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		balance   Money
+		dailyRate SavingsDailyRate
+		want      AccruedInterest
+	}{
+		{name: "positive balance", balance: 1000000, dailyRate: 50788, want: 50788000000},
+		{name: "keeps the fraction of a cent", balance: 1, dailyRate: 50788, want: 50788},
+		{name: "zero balance accrues nothing", balance: 0, dailyRate: 50788, want: 0},
+		{name: "negative balance accrues nothing", balance: -100000, dailyRate: 50788, want: 0},
+		{name: "zero rate accrues nothing", balance: 100000, dailyRate: 0, want: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := SavingsInterest(tt.balance, tt.dailyRate); got != tt.want {
+				t.Errorf("SavingsInterest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccruedInterest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		interest     AccruedInterest
+		wantTruncate Money
+		wantString   string
+	}{
+		{name: "whole cents", interest: 50800000000, wantTruncate: 508, wantString: "5.0800000000"},
+		{name: "fraction of a cent", interest: 50788000000 + 99999999, wantTruncate: 508, wantString: "5.0887999999"},
+		{name: "less than a cent", interest: 50788, wantTruncate: 0, wantString: "0.0000050788"},
+		{name: "zero", interest: 0, wantTruncate: 0, wantString: "0.0000000000"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.interest.Truncate(); got != tt.wantTruncate {
+				t.Errorf("Truncate() = %v, want %v", got, tt.wantTruncate)
+			}
+			if got := tt.interest.String(); got != tt.wantString {
+				t.Errorf("String() = %v, want %v", got, tt.wantString)
+			}
+		})
+	}
+}
+
+func TestSavingsInterestTotals_Unpaid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		totals SavingsInterestTotals
+		want   Money
+	}{
+		{name: "nothing paid yet", totals: SavingsInterestTotals{Accrued: 250_000_000}, want: 2},
+		{name: "carries the fraction of a cent", totals: SavingsInterestTotals{Accrued: 350_000_000, Paid: 2}, want: 1},
+		{name: "fraction alone is not paid", totals: SavingsInterestTotals{Accrued: 299_999_999, Paid: 2}, want: 0},
+		{name: "never negative", totals: SavingsInterestTotals{Accrued: 100_000_000, Paid: 2}, want: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.totals.Unpaid(); got != tt.want {
+				t.Errorf("Unpaid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSavingsInterestAccrual(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2021, 3, 15, 0, 0, 0, 0, time.UTC)
+	got := NewSavingsInterestAccrual("uuid-1", day, 1000000, 50788)
+
+	if len(got.ID) <= 0 {
+		t.Errorf("NewSavingsInterestAccrual() = %v, ID should not be empty", got)
+	}
+	got.ID = ""
+
+	if got.CreatedAt.Before(time.Now().Add(-5 * time.Second)) {
+		t.Errorf("NewSavingsInterestAccrual() got = %v, want CreatedAt in the last 5 seconds", got)
+	}
+	got.CreatedAt = time.Time{}
+
+	want := &SavingsInterestAccrual{
+		AccountID: "uuid-1",
+		Day:       day,
+		Balance:   1000000,
+		Rate:      50788,
+		Amount:    50788000000,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewSavingsInterestAccrual() = %v, want %v", got, want)
+	}
+}
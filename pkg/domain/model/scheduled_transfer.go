@@ -0,0 +1,95 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledTransferID represents a ScheduledTransfer ID as uuid.
+type ScheduledTransferID string
+
+// NewScheduledTransferID returns a new ScheduledTransferID with value generated by uuid.New().
+func NewScheduledTransferID() ScheduledTransferID {
+	return ScheduledTransferID(uuid.NewString())
+}
+
+// ScheduledTransferStatus tells whether a scheduled transfer is still pending or its outcome.
+type ScheduledTransferStatus string
+
+const (
+	// ScheduledTransferStatusScheduled is the status of the schedules waiting for their date.
+	ScheduledTransferStatusScheduled ScheduledTransferStatus = "scheduled"
+	// ScheduledTransferStatusExecuted is the status of the schedules whose transfer was made.
+	ScheduledTransferStatusExecuted ScheduledTransferStatus = "executed"
+	// ScheduledTransferStatusFailed is the status of the schedules whose transfer was rejected, like for insufficient balance.
+	ScheduledTransferStatusFailed ScheduledTransferStatus = "failed"
+	// ScheduledTransferStatusCancelled is the status of the schedules cancelled by the origin account before their date.
+	ScheduledTransferStatusCancelled ScheduledTransferStatus = "cancelled"
+)
+
+// IsValid checks whether it's a known status.
+func (s ScheduledTransferStatus) IsValid() bool {
+	switch s {
+	case ScheduledTransferStatusScheduled, ScheduledTransferStatusExecuted, ScheduledTransferStatusFailed, ScheduledTransferStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// ScheduledTransfer represents a transfer to be made at a future time.
+//
+// Once processed, it has the ID of the made transfer or the reason it failed.
+// Its Amount is in the Currency of the origin account, which is not stored with it but read from the account.
+type ScheduledTransfer struct {
+	ID                   ScheduledTransferID
+	AccountOriginID      AccountID
+	AccountDestinationID AccountID
+	Amount               Money
+	Currency             Currency
+	ScheduledFor         time.Time
+	Status               ScheduledTransferStatus
+	TransferID           TransferID
+	FailureReason        string
+	CreatedAt            time.Time
+	ProcessedAt          time.Time
+}
+
+// NewScheduledTransfer returns a new ScheduledTransfer filled with the corresponding arguments with generated values for id and createdAt.
+func NewScheduledTransfer(originID, destinationID AccountID, amount Money, scheduledFor time.Time) *ScheduledTransfer {
+	return &ScheduledTransfer{
+		ID:                   NewScheduledTransferID(),
+		AccountOriginID:      originID,
+		AccountDestinationID: destinationID,
+		Amount:               amount,
+		ScheduledFor:         scheduledFor,
+		Status:               ScheduledTransferStatusScheduled,
+		CreatedAt:            time.Now(),
+	}
+}
+
+// IsPending checks whether the schedule was not processed nor cancelled yet.
+func (s *ScheduledTransfer) IsPending() bool {
+	return s.Status == ScheduledTransferStatusScheduled
+}
+
+// Executed records the transfer made for the schedule.
+func (s *ScheduledTransfer) Executed(transferID TransferID) {
+	s.Status = ScheduledTransferStatusExecuted
+	s.TransferID = transferID
+	s.ProcessedAt = time.Now()
+}
+
+// Failed records why the transfer of the schedule was rejected.
+func (s *ScheduledTransfer) Failed(reason string) {
+	s.Status = ScheduledTransferStatusFailed
+	s.FailureReason = reason
+	s.ProcessedAt = time.Now()
+}
+
+// Cancelled records the schedule was cancelled.
+func (s *ScheduledTransfer) Cancelled() {
+	s.Status = ScheduledTransferStatusCancelled
+	s.ProcessedAt = time.Now()
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewScheduledTransfer(t *testing.T) {
+	t.Parallel()
+
+	scheduledFor := time.Date(2021, 1, 31, 9, 0, 0, 0, time.UTC)
+	got := NewScheduledTransfer("uuid-1", "uuid-2", 1000, scheduledFor)
+
+	if len(got.ID) <= 0 {
+		t.Errorf("NewScheduledTransfer() = %v, ID should not be empty", got)
+	}
+	got.ID = ""
+
+	if got.CreatedAt.Before(time.Now().Add(-5 * time.Second)) {
+		t.Errorf("NewScheduledTransfer() got = %v, want CreatedAt in the last 5 seconds", got)
+	}
+	got.CreatedAt = time.Time{}
+
+	want := &ScheduledTransfer{
+		AccountOriginID:      "uuid-1",
+		AccountDestinationID: "uuid-2",
+		Amount:               1000,
+		ScheduledFor:         scheduledFor,
+		Status:               ScheduledTransferStatusScheduled,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewScheduledTransfer() = %v, want %v", got, want)
+	}
+}
+
+func TestScheduledTransfer_outcomes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		process           func(s *ScheduledTransfer)
+		wantStatus        ScheduledTransferStatus
+		wantTransferID    TransferID
+		wantFailureReason string
+	}{
+		{
+			name:           "executed should keep the transfer ID",
+			process:        func(s *ScheduledTransfer) { s.Executed("transfer-uuid") },
+			wantStatus:     ScheduledTransferStatusExecuted,
+			wantTransferID: "transfer-uuid",
+		},
+		{
+			name:              "failed should keep the reason",
+			process:           func(s *ScheduledTransfer) { s.Failed("current account balance is insufficient") },
+			wantStatus:        ScheduledTransferStatusFailed,
+			wantFailureReason: "current account balance is insufficient",
+		},
+		{
+			name:       "cancelled",
+			process:    func(s *ScheduledTransfer) { s.Cancelled() },
+			wantStatus: ScheduledTransferStatusCancelled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := NewScheduledTransfer("uuid-1", "uuid-2", 1000, time.Now())
+			if !schedule.IsPending() {
+				t.Errorf("IsPending() = false, want true before processed")
+			}
+
+			tt.process(schedule)
+
+			if schedule.IsPending() {
+				t.Errorf("IsPending() = true, want false after processed")
+			}
+			if schedule.Status != tt.wantStatus || schedule.TransferID != tt.wantTransferID || schedule.FailureReason != tt.wantFailureReason {
+				t.Errorf("got = %v, want status %v, transfer ID %q and failure reason %q", schedule, tt.wantStatus, tt.wantTransferID, tt.wantFailureReason)
+			}
+			if schedule.ProcessedAt.IsZero() {
+				t.Errorf("ProcessedAt should not be zero")
+			}
+		})
+	}
+}
+
+func TestScheduledTransferStatus_IsValid(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range []ScheduledTransferStatus{ScheduledTransferStatusScheduled, ScheduledTransferStatusExecuted, ScheduledTransferStatusFailed, ScheduledTransferStatusCancelled} {
+		if !status.IsValid() {
+			t.Errorf("IsValid() = false, want true for %q", status)
+		}
+	}
+	if ScheduledTransferStatus("pending").IsValid() {
+		t.Errorf("IsValid() = true, want false for unknown status")
+	}
+}
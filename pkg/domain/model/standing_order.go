@@ -0,0 +1,247 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StandingOrderID represents a StandingOrder ID as uuid.
+type StandingOrderID string
+
+// NewStandingOrderID returns a new StandingOrderID with value generated by uuid.New().
+func NewStandingOrderID() StandingOrderID {
+	return StandingOrderID(uuid.NewString())
+}
+
+// StandingOrderFrequency tells how often a standing order recurs.
+type StandingOrderFrequency string
+
+const (
+	// StandingOrderFrequencyWeekly recurs every week, on the weekday of the start date.
+	StandingOrderFrequencyWeekly StandingOrderFrequency = "weekly"
+	// StandingOrderFrequencyMonthly recurs every month on a given day, or on the last day of the shorter months.
+	StandingOrderFrequencyMonthly StandingOrderFrequency = "monthly"
+	// StandingOrderFrequencyLastBusinessDay recurs on the last weekday (Monday to Friday) of every month.
+	StandingOrderFrequencyLastBusinessDay StandingOrderFrequency = "last_business_day"
+)
+
+// IsValid checks whether it's a known frequency.
+func (f StandingOrderFrequency) IsValid() bool {
+	switch f {
+	case StandingOrderFrequencyWeekly, StandingOrderFrequencyMonthly, StandingOrderFrequencyLastBusinessDay:
+		return true
+	default:
+		return false
+	}
+}
+
+// StandingOrderStatus tells whether a standing order is still running.
+type StandingOrderStatus string
+
+const (
+	// StandingOrderStatusActive is the status of the standing orders whose occurrences are executed.
+	StandingOrderStatusActive StandingOrderStatus = "active"
+	// StandingOrderStatusPaused is the status of the standing orders paused by the holder. Their occurrences are skipped.
+	StandingOrderStatusPaused StandingOrderStatus = "paused"
+	// StandingOrderStatusFinished is the status of the standing orders that reached their end date or occurrences count.
+	StandingOrderStatusFinished StandingOrderStatus = "finished"
+	// StandingOrderStatusCancelled is the status of the standing orders cancelled by the holder.
+	StandingOrderStatusCancelled StandingOrderStatus = "cancelled"
+)
+
+// StandingOrder represents a transfer repeated on a recurrence rule, like a monthly rent payment.
+//
+// The occurrences keep the time of day and the UTC offset of StartsAt. Each occurrence is executed at NextRunAt,
+// which is NextDueAt itself or, after a failure, the time of the retry. An occurrence counts once it's executed or
+// it fails for good, and the standing order finishes after EndsAt or MaxOccurrences, when they're set.
+// Its Amount is in the Currency of the origin account, which is not stored with it but read from the account.
+type StandingOrder struct {
+	ID                   StandingOrderID
+	AccountOriginID      AccountID
+	AccountDestinationID AccountID
+	Amount               Money
+	Currency             Currency
+	Frequency            StandingOrderFrequency
+	DayOfMonth           int
+	StartsAt             time.Time
+	EndsAt               time.Time
+	MaxOccurrences       int
+	Occurrences          int
+	NextDueAt            time.Time
+	NextRunAt            time.Time
+	Retries              int
+	Status               StandingOrderStatus
+	LastTransferID       TransferID
+	LastFailureReason    string
+	LastRunAt            time.Time
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// NewStandingOrder returns a new active StandingOrder filled with the corresponding arguments with generated values for
+// id and createdAt. Its first occurrence is the first one matching the recurrence from startsAt on.
+func NewStandingOrder(
+	originID, destinationID AccountID,
+	amount Money,
+	frequency StandingOrderFrequency,
+	dayOfMonth int,
+	startsAt time.Time,
+	endsAt time.Time,
+	maxOccurrences int,
+) *StandingOrder {
+	now := time.Now()
+	order := &StandingOrder{
+		ID:                   NewStandingOrderID(),
+		AccountOriginID:      originID,
+		AccountDestinationID: destinationID,
+		Amount:               amount,
+		Frequency:            frequency,
+		DayOfMonth:           dayOfMonth,
+		StartsAt:             startsAt,
+		EndsAt:               endsAt,
+		MaxOccurrences:       maxOccurrences,
+		Status:               StandingOrderStatusActive,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	order.schedule(order.OccurrenceAfter(startsAt.Add(-time.Nanosecond)))
+
+	return order
+}
+
+// OccurrenceAfter returns the first occurrence of the recurrence strictly after t, never before StartsAt.
+func (so *StandingOrder) OccurrenceAfter(t time.Time) time.Time {
+	if so.Frequency == StandingOrderFrequencyWeekly {
+		next := so.StartsAt
+		if t.After(next) {
+			// jumps close to t, the loop adjusts the rest
+			weeks := int(t.Sub(next).Hours() / (7 * 24))
+			next = next.AddDate(0, 0, 7*weeks)
+		}
+		for !next.After(t) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next
+	}
+
+	from := so.StartsAt
+	if t.After(from) {
+		from = t
+	}
+	from = from.In(so.StartsAt.Location())
+	for month := from.Month(); ; month++ {
+		next := so.monthOccurrence(from.Year(), month)
+		if next.After(t) && !next.Before(so.StartsAt) {
+			return next
+		}
+	}
+}
+
+// monthOccurrence returns the occurrence in the month of a monthly or last business day recurrence.
+// The month is normalized like time.Date does, so month 13 is January of the next year.
+func (so *StandingOrder) monthOccurrence(year int, month time.Month) time.Time {
+	start := so.StartsAt
+	first := time.Date(year, month, 1, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	last := first.AddDate(0, 1, -1)
+
+	if so.Frequency == StandingOrderFrequencyLastBusinessDay {
+		for last.Weekday() == time.Saturday || last.Weekday() == time.Sunday {
+			last = last.AddDate(0, 0, -1)
+		}
+		return last
+	}
+
+	if so.DayOfMonth < last.Day() {
+		return first.AddDate(0, 0, so.DayOfMonth-1)
+	}
+	return last
+}
+
+// IsEndedBy checks whether the occurrence is past the end of the standing order.
+func (so *StandingOrder) IsEndedBy(occurrence time.Time) bool {
+	if so.MaxOccurrences > 0 && so.Occurrences >= so.MaxOccurrences {
+		return true
+	}
+
+	return !so.EndsAt.IsZero() && occurrence.After(so.EndsAt)
+}
+
+// schedule makes the occurrence the next one to run, or finishes the standing order if it's past the end.
+func (so *StandingOrder) schedule(occurrence time.Time) {
+	so.Retries = 0
+	if so.IsEndedBy(occurrence) {
+		so.Status = StandingOrderStatusFinished
+		return
+	}
+
+	so.NextDueAt = occurrence
+	so.NextRunAt = occurrence
+}
+
+// Executed records the transfer made for the current occurrence and moves to the next one.
+func (so *StandingOrder) Executed(transferID TransferID) {
+	now := time.Now()
+	so.LastTransferID = transferID
+	so.LastFailureReason = ""
+	so.LastRunAt = now
+	so.UpdatedAt = now
+	so.Occurrences++
+	so.schedule(so.OccurrenceAfter(so.NextDueAt))
+}
+
+// Failed records why the transfer of the current occurrence was rejected.
+//
+// While there are retries left, the occurrence runs again after retryInterval, unless that would reach the next
+// occurrence. Otherwise the occurrence is given up and the standing order moves to the next one.
+// It returns whether the occurrence will be retried.
+func (so *StandingOrder) Failed(reason string, maxRetries int, retryInterval time.Duration) bool {
+	now := time.Now()
+	so.LastTransferID = ""
+	so.LastFailureReason = reason
+	so.LastRunAt = now
+	so.UpdatedAt = now
+
+	next := so.OccurrenceAfter(so.NextDueAt)
+	retryAt := now.Add(retryInterval)
+	if so.Retries < maxRetries && retryAt.Before(next) {
+		so.Retries++
+		so.NextRunAt = retryAt
+		return true
+	}
+
+	so.Occurrences++
+	so.schedule(next)
+	return false
+}
+
+// CanChangeStatusTo checks whether the holder can move the standing order to the status.
+// Active ones can be paused, paused ones can be resumed, and both can be cancelled.
+func (so *StandingOrder) CanChangeStatusTo(status StandingOrderStatus) bool {
+	switch status {
+	case StandingOrderStatusPaused:
+		return so.Status == StandingOrderStatusActive
+	case StandingOrderStatusActive:
+		return so.Status == StandingOrderStatusPaused
+	case StandingOrderStatusCancelled:
+		return so.Status == StandingOrderStatusActive || so.Status == StandingOrderStatusPaused
+	default:
+		return false
+	}
+}
+
+// ChangeStatus sets the new status. When resumed, the occurrences missed while paused are skipped,
+// so the next one is the first from now on.
+func (so *StandingOrder) ChangeStatus(status StandingOrderStatus) {
+	now := time.Now()
+	so.Status = status
+	so.UpdatedAt = now
+
+	if status == StandingOrderStatusActive {
+		next := so.NextDueAt
+		if next.Before(now) {
+			next = so.OccurrenceAfter(now)
+		}
+		so.schedule(next)
+	}
+}
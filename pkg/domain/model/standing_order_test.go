@@ -0,0 +1,212 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStandingOrder_OccurrenceAfter(t *testing.T) {
+	t.Parallel()
+
+	brt := time.FixedZone("BRT", -3*60*60)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 0, 0, 0, brt)
+	}
+
+	tests := []struct {
+		name       string
+		frequency  StandingOrderFrequency
+		dayOfMonth int
+		startsAt   time.Time
+		after      time.Time
+		want       time.Time
+	}{
+		{
+			name:      "weekly first occurrence should be the start",
+			frequency: StandingOrderFrequencyWeekly,
+			startsAt:  date(2021, 1, 4),
+			after:     date(2021, 1, 1),
+			want:      date(2021, 1, 4),
+		},
+		{
+			name:      "weekly should keep the weekday",
+			frequency: StandingOrderFrequencyWeekly,
+			startsAt:  date(2021, 1, 4),
+			after:     date(2021, 1, 20),
+			want:      date(2021, 1, 25),
+		},
+		{
+			name:      "weekly should skip the occurrence at the given time",
+			frequency: StandingOrderFrequencyWeekly,
+			startsAt:  date(2021, 1, 4),
+			after:     date(2021, 3, 1),
+			want:      date(2021, 3, 8),
+		},
+		{
+			name:       "monthly should wait for the day in the start month",
+			frequency:  StandingOrderFrequencyMonthly,
+			dayOfMonth: 31,
+			startsAt:   date(2021, 1, 10),
+			after:      date(2021, 1, 9),
+			want:       date(2021, 1, 31),
+		},
+		{
+			name:       "monthly should go to the next month when the day is before the start",
+			frequency:  StandingOrderFrequencyMonthly,
+			dayOfMonth: 5,
+			startsAt:   date(2021, 1, 10),
+			after:      date(2021, 1, 9),
+			want:       date(2021, 2, 5),
+		},
+		{
+			name:       "monthly should use the last day of shorter months",
+			frequency:  StandingOrderFrequencyMonthly,
+			dayOfMonth: 31,
+			startsAt:   date(2021, 1, 10),
+			after:      date(2021, 1, 31),
+			want:       date(2021, 2, 28),
+		},
+		{
+			name:       "monthly should go back to the day after a shorter month",
+			frequency:  StandingOrderFrequencyMonthly,
+			dayOfMonth: 31,
+			startsAt:   date(2021, 1, 10),
+			after:      date(2021, 2, 28),
+			want:       date(2021, 3, 31),
+		},
+		{
+			name:       "monthly should cross the year",
+			frequency:  StandingOrderFrequencyMonthly,
+			dayOfMonth: 15,
+			startsAt:   date(2021, 1, 10),
+			after:      date(2021, 12, 15),
+			want:       date(2022, 1, 15),
+		},
+		{
+			name:      "last business day should skip the weekend",
+			frequency: StandingOrderFrequencyLastBusinessDay,
+			startsAt:  date(2021, 7, 1),
+			after:     date(2021, 6, 30),
+			want:      date(2021, 7, 30),
+		},
+		{
+			name:      "last business day on a weekday",
+			frequency: StandingOrderFrequencyLastBusinessDay,
+			startsAt:  date(2021, 7, 1),
+			after:     date(2021, 7, 30),
+			want:      date(2021, 8, 31),
+		},
+		{
+			name:      "last business day should be in the location of the start",
+			frequency: StandingOrderFrequencyLastBusinessDay,
+			startsAt:  date(2021, 7, 1),
+			after:     date(2021, 10, 1).UTC(),
+			want:      date(2021, 10, 29),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			so := &StandingOrder{Frequency: tt.frequency, DayOfMonth: tt.dayOfMonth, StartsAt: tt.startsAt}
+			if got := so.OccurrenceAfter(tt.after); !got.Equal(tt.want) {
+				t.Errorf("OccurrenceAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewStandingOrder(t *testing.T) {
+	t.Parallel()
+
+	startsAt := time.Date(2021, 1, 10, 9, 0, 0, 0, time.UTC)
+	got := NewStandingOrder("uuid-1", "uuid-2", 1000, StandingOrderFrequencyMonthly, 15, startsAt, time.Time{}, 0)
+
+	if len(got.ID) <= 0 {
+		t.Errorf("NewStandingOrder() = %v, ID should not be empty", got)
+	}
+	if got.Status != StandingOrderStatusActive {
+		t.Errorf("NewStandingOrder() Status = %v, want %v", got.Status, StandingOrderStatusActive)
+	}
+
+	want := time.Date(2021, 1, 15, 9, 0, 0, 0, time.UTC)
+	if !got.NextDueAt.Equal(want) || !got.NextRunAt.Equal(want) {
+		t.Errorf("NewStandingOrder() NextDueAt = %v, NextRunAt = %v, want %v", got.NextDueAt, got.NextRunAt, want)
+	}
+}
+
+func TestStandingOrder_Executed(t *testing.T) {
+	t.Parallel()
+
+	startsAt := time.Now().Add(-time.Hour)
+	so := NewStandingOrder("uuid-1", "uuid-2", 1000, StandingOrderFrequencyWeekly, 0, startsAt, time.Time{}, 2)
+
+	so.Executed("transfer-1")
+	if so.Status != StandingOrderStatusActive || so.Occurrences != 1 || so.LastTransferID != "transfer-1" {
+		t.Fatalf("Executed() = %v, want active with 1 occurrence", so)
+	}
+	if want := startsAt.AddDate(0, 0, 7); !so.NextDueAt.Equal(want) || !so.NextRunAt.Equal(want) {
+		t.Errorf("Executed() NextDueAt = %v, NextRunAt = %v, want %v", so.NextDueAt, so.NextRunAt, want)
+	}
+
+	so.Executed("transfer-2")
+	if so.Status != StandingOrderStatusFinished || so.Occurrences != 2 {
+		t.Errorf("Executed() = %v, want finished after the max occurrences", so)
+	}
+}
+
+func TestStandingOrder_Failed(t *testing.T) {
+	t.Parallel()
+
+	startsAt := time.Now().Add(-time.Hour)
+	endsAt := startsAt.AddDate(0, 0, 10)
+	so := NewStandingOrder("uuid-1", "uuid-2", 1000, StandingOrderFrequencyWeekly, 0, startsAt, endsAt, 0)
+
+	if retrying := so.Failed("current account balance is insufficient", 1, time.Hour); !retrying {
+		t.Fatalf("Failed() = false, want retrying")
+	}
+	if so.Retries != 1 || so.Occurrences != 0 || !so.NextDueAt.Equal(startsAt) || !so.NextRunAt.After(time.Now().Add(59*time.Minute)) {
+		t.Errorf("Failed() = %v, want the same occurrence retried in one hour", so)
+	}
+
+	if retrying := so.Failed("current account balance is insufficient", 1, time.Hour); retrying {
+		t.Fatalf("Failed() = true, want no retries left")
+	}
+	if so.Retries != 0 || so.Occurrences != 1 || !so.NextDueAt.Equal(startsAt.AddDate(0, 0, 7)) || so.LastFailureReason == "" {
+		t.Errorf("Failed() = %v, want moved to the next occurrence", so)
+	}
+
+	if retrying := so.Failed("current account balance is insufficient", 1, 14*24*time.Hour); retrying {
+		t.Fatalf("Failed() = true, want no retry reaching the next occurrence")
+	}
+	if so.Status != StandingOrderStatusFinished {
+		t.Errorf("Failed() = %v, want finished after the end date", so)
+	}
+}
+
+func TestStandingOrder_ChangeStatus(t *testing.T) {
+	t.Parallel()
+
+	startsAt := time.Now().AddDate(0, 0, -20)
+	so := NewStandingOrder("uuid-1", "uuid-2", 1000, StandingOrderFrequencyWeekly, 0, startsAt, time.Time{}, 0)
+
+	if so.CanChangeStatusTo(StandingOrderStatusActive) || !so.CanChangeStatusTo(StandingOrderStatusPaused) {
+		t.Fatalf("CanChangeStatusTo() should only allow pausing an active standing order")
+	}
+	so.ChangeStatus(StandingOrderStatusPaused)
+
+	if !so.CanChangeStatusTo(StandingOrderStatusActive) || !so.CanChangeStatusTo(StandingOrderStatusCancelled) {
+		t.Fatalf("CanChangeStatusTo() should allow resuming and cancelling a paused standing order")
+	}
+	so.ChangeStatus(StandingOrderStatusActive)
+
+	if want := startsAt.AddDate(0, 0, 21); so.Status != StandingOrderStatusActive || !so.NextDueAt.Equal(want) {
+		t.Errorf("ChangeStatus() NextDueAt = %v, want the missed occurrences skipped to %v", so.NextDueAt, want)
+	}
+
+	so.ChangeStatus(StandingOrderStatusCancelled)
+	if so.CanChangeStatusTo(StandingOrderStatusActive) || so.CanChangeStatusTo(StandingOrderStatusPaused) {
+		t.Errorf("CanChangeStatusTo() should not allow changing a cancelled standing order")
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Statement represents the movements of an account in a period, with the balance after each one.
+type Statement struct {
+	AccountID      AccountID
+	Currency       Currency
+	From           time.Time
+	To             time.Time
+	OpeningBalance Money
+	ClosingBalance Money
+	Entries        []StatementEntry
+}
+
+// StatementEntry represents a LedgerEntry of the account with its counterpart and the running balance.
+type StatementEntry struct {
+	LedgerEntry
+	CounterpartID   AccountID
+	CounterpartName string
+	BalanceAfter    Money
+}
+
+// HasExternalCounterpart checks whether the money came from or went to outside the bank, like deposits and withdrawals,
+// or was converted to another currency by the bank.
+func (e StatementEntry) HasExternalCounterpart() bool {
+	return IsBankLedgerAccount(e.CounterpartID)
+}
@@ -14,22 +14,145 @@ func NewTransferID() TransferID {
 	return TransferID(uuid.NewString())
 }
 
+// TransferKind tells whether a Transfer was made by the holder or gives back the money of another one.
+type TransferKind string
+
+const (
+	// TransferKindTransfer is a transfer made by the holder of the origin account.
+	TransferKindTransfer TransferKind = "transfer"
+	// TransferKindRefund gives back, fully or partially, the money of a transfer, by its recipient.
+	TransferKindRefund TransferKind = "refund"
+	// TransferKindReversal undoes a mistaken transfer, by the back-office.
+	TransferKindReversal TransferKind = "reversal"
+)
+
+// LedgerPostingKind returns the kind of the posting that moves the money of the transfers of this kind.
+func (k TransferKind) LedgerPostingKind() LedgerPostingKind {
+	switch k {
+	case TransferKindRefund:
+		return LedgerPostingRefund
+	case TransferKindReversal:
+		return LedgerPostingReversal
+	default:
+		return LedgerPostingTransfer
+	}
+}
+
 // Transfer represents a bank transfer between two accounts.
+//
+// Refunds and reversals are transfers in the opposite direction of the OriginalTransferID, whose RefundedAmount
+// sums them, so it never exceeds its Amount.
+//
+// The Amount is in the Currency of the origin account. When the destination account has another currency, the transfer
+// is a conversion by the FXQuoteID and the destination is credited the DestinationAmount in the DestinationCurrency.
+//
+// The Fee is charged on the origin account on top of the Amount, in its Currency, and it's not given back by refunds
+// nor reversals.
 type Transfer struct {
 	ID                   TransferID
+	Kind                 TransferKind
+	OriginalTransferID   TransferID
 	AccountOriginID      AccountID
 	AccountDestinationID AccountID
+	Currency             Currency
 	Amount               Money
+	RefundedAmount       Money
+	FXQuoteID            FXQuoteID
+	FXRate               FXRate
+	DestinationCurrency  Currency
+	DestinationAmount    Money
+	Fee                  Money
 	CreatedAt            time.Time
 }
 
 // NewTransfer returns a new Transfer filled with the corresponding arguments with generated values for id and createdAt.
-func NewTransfer(accountOriginID, accountDestinationID string, amount float64) *Transfer {
+func NewTransfer(accountOriginID, accountDestinationID string, amount Money) *Transfer {
 	return &Transfer{
 		ID:                   NewTransferID(),
+		Kind:                 TransferKindTransfer,
 		AccountOriginID:      AccountID(accountOriginID),
 		AccountDestinationID: AccountID(accountDestinationID),
-		Amount:               Float64ToMoney(amount),
+		Amount:               amount,
 		CreatedAt:            time.Now(),
 	}
 }
+
+// InCurrency sets the currency of the transfer, the one of both accounts.
+func (t *Transfer) InCurrency(currency Currency) {
+	t.Currency = currency
+	t.DestinationCurrency = currency
+	t.DestinationAmount = t.Amount
+}
+
+// ConvertBy sets the currencies, the rate and the destination amount of the quote.
+func (t *Transfer) ConvertBy(quote *FXQuote) {
+	t.Currency = quote.SourceCurrency
+	t.FXQuoteID = quote.ID
+	t.FXRate = quote.Rate
+	t.DestinationCurrency = quote.TargetCurrency
+	t.DestinationAmount = quote.TargetAmount
+}
+
+// IsConversion checks whether the transfer converts the amount to the currency of the destination account.
+func (t *Transfer) IsConversion() bool {
+	return t.FXQuoteID != ""
+}
+
+// IsRefundable checks whether the transfer can be refunded or reversed. Refunds and reversals can't.
+func (t *Transfer) IsRefundable() bool {
+	return t.Kind == TransferKindTransfer
+}
+
+// RefundableAmount returns how much of the transfer was not refunded or reversed yet.
+func (t *Transfer) RefundableAmount() Money {
+	return t.Amount - t.RefundedAmount
+}
+
+// NewRefundOf returns a new Transfer giving back the amount of the original transfer, from its destination to its
+// origin. The kind must be TransferKindRefund or TransferKindReversal.
+func (t *Transfer) NewRefundOf(kind TransferKind, amount Money) *Transfer {
+	return &Transfer{
+		ID:                   NewTransferID(),
+		Kind:                 kind,
+		OriginalTransferID:   t.ID,
+		AccountOriginID:      t.AccountDestinationID,
+		AccountDestinationID: t.AccountOriginID,
+		Amount:               amount,
+		CreatedAt:            time.Now(),
+	}
+}
+
+// TransferDirection tells if a Transfer was sent or received by an account.
+type TransferDirection string
+
+const (
+	// TransferDirectionSent are the transfers from the account.
+	TransferDirectionSent TransferDirection = "sent"
+	// TransferDirectionReceived are the transfers to the account.
+	TransferDirectionReceived TransferDirection = "received"
+)
+
+// IsValid checks whether it's a known direction.
+func (d TransferDirection) IsValid() bool {
+	return d == TransferDirectionSent || d == TransferDirectionReceived
+}
+
+// TransferCursor points to the last Transfer of a page, so the next page starts right after it.
+type TransferCursor struct {
+	CreatedAt time.Time
+	ID        TransferID
+}
+
+// TransferFilter represents the criteria to fetch the transfers of an account, newest first.
+// Zero values don't filter.
+type TransferFilter struct {
+	AccountID     AccountID
+	Direction     TransferDirection
+	CounterpartID AccountID
+	CreatedFrom   time.Time
+	CreatedTo     time.Time
+	MinAmount     Money
+	MaxAmount     Money
+	After         *TransferCursor
+	Limit         int
+}
@@ -0,0 +1,171 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransferBatchID represents a TransferBatch ID as uuid.
+type TransferBatchID string
+
+// NewTransferBatchID returns a new TransferBatchID with value generated by uuid.New().
+func NewTransferBatchID() TransferBatchID {
+	return TransferBatchID(uuid.NewString())
+}
+
+// TransferBatchMode tells what happens to the other items of a batch when one of them is rejected.
+type TransferBatchMode string
+
+const (
+	// TransferBatchModeAtomic makes all the items of the batch or none of them.
+	TransferBatchModeAtomic TransferBatchMode = "atomic"
+	// TransferBatchModeBestEffort makes each item of the batch on its own, so the rejected ones don't stop the others.
+	TransferBatchModeBestEffort TransferBatchMode = "best_effort"
+)
+
+// IsValid checks whether it's a known mode.
+func (m TransferBatchMode) IsValid() bool {
+	switch m {
+	case TransferBatchModeAtomic, TransferBatchModeBestEffort:
+		return true
+	default:
+		return false
+	}
+}
+
+// TransferBatchStatus tells whether a batch is still running or its outcome.
+type TransferBatchStatus string
+
+const (
+	// TransferBatchStatusProcessing is the status of the batches whose items are being made.
+	// A batch stays on it when its run was interrupted.
+	TransferBatchStatusProcessing TransferBatchStatus = "processing"
+	// TransferBatchStatusCompleted is the status of the batches whose items were all made.
+	TransferBatchStatusCompleted TransferBatchStatus = "completed"
+	// TransferBatchStatusPartiallyCompleted is the status of the best-effort batches with some items rejected.
+	TransferBatchStatusPartiallyCompleted TransferBatchStatus = "partially_completed"
+	// TransferBatchStatusFailed is the status of the batches without any item made.
+	TransferBatchStatusFailed TransferBatchStatus = "failed"
+)
+
+// TransferBatchItemStatus tells the outcome of an item of a batch.
+type TransferBatchItemStatus string
+
+const (
+	// TransferBatchItemStatusExecuted is the status of the items whose transfer was made.
+	TransferBatchItemStatusExecuted TransferBatchItemStatus = "executed"
+	// TransferBatchItemStatusDuplicate is the status of the items whose ItemID was executed by an earlier batch of
+	// the same origin account. They have the transfer made then and are not made again.
+	TransferBatchItemStatusDuplicate TransferBatchItemStatus = "duplicate"
+	// TransferBatchItemStatusRejected is the status of the items whose transfer was rejected, like for insufficient balance.
+	TransferBatchItemStatusRejected TransferBatchItemStatus = "rejected"
+	// TransferBatchItemStatusSkipped is the status of the items of an atomic batch not made because another one was rejected.
+	TransferBatchItemStatusSkipped TransferBatchItemStatus = "skipped"
+)
+
+// TransferBatchItem represents one of the transfers of a batch.
+//
+// ItemID is chosen by the client and identifies the item among all the batches of the origin account.
+type TransferBatchItem struct {
+	ItemID               string
+	AccountDestinationID AccountID
+	Amount               Money
+	Status               TransferBatchItemStatus
+	TransferID           TransferID
+	FailureReason        string
+}
+
+// IsMade checks whether the transfer of the item was made, by this batch or an earlier one.
+func (i *TransferBatchItem) IsMade() bool {
+	return i.Status == TransferBatchItemStatusExecuted || i.Status == TransferBatchItemStatusDuplicate
+}
+
+// Executed records the transfer made for the item.
+func (i *TransferBatchItem) Executed(transferID TransferID) {
+	i.Status = TransferBatchItemStatusExecuted
+	i.TransferID = transferID
+	i.FailureReason = ""
+}
+
+// Duplicate records the item was already executed with the transfer.
+func (i *TransferBatchItem) Duplicate(transferID TransferID) {
+	i.Status = TransferBatchItemStatusDuplicate
+	i.TransferID = transferID
+	i.FailureReason = ""
+}
+
+// Rejected records why the transfer of the item was rejected.
+func (i *TransferBatchItem) Rejected(reason string) {
+	i.Status = TransferBatchItemStatusRejected
+	i.TransferID = ""
+	i.FailureReason = reason
+}
+
+// Skipped records the transfer of the item was not made because of another item.
+func (i *TransferBatchItem) Skipped() {
+	i.Status = TransferBatchItemStatusSkipped
+	i.TransferID = ""
+	i.FailureReason = ""
+}
+
+// TransferBatch represents many transfers from the same origin account requested at once.
+// The item amounts are in the Currency of the origin account, which is not stored with it but read from the account.
+type TransferBatch struct {
+	ID              TransferBatchID
+	AccountOriginID AccountID
+	Currency        Currency
+	Mode            TransferBatchMode
+	Status          TransferBatchStatus
+	Items           []TransferBatchItem
+	CreatedAt       time.Time
+	CompletedAt     time.Time
+}
+
+// NewTransferBatch returns a new processing TransferBatch filled with the corresponding arguments with generated values for id and createdAt.
+func NewTransferBatch(originID AccountID, mode TransferBatchMode, items []TransferBatchItem) *TransferBatch {
+	return &TransferBatch{
+		ID:              NewTransferBatchID(),
+		AccountOriginID: originID,
+		Mode:            mode,
+		Status:          TransferBatchStatusProcessing,
+		Items:           items,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// Completed sets the status of the batch from the outcome of its items.
+func (b *TransferBatch) Completed() {
+	made := 0
+	for _, item := range b.Items {
+		if item.IsMade() {
+			made++
+		}
+	}
+
+	switch made {
+	case len(b.Items):
+		b.Status = TransferBatchStatusCompleted
+	case 0:
+		b.Status = TransferBatchStatusFailed
+	default:
+		b.Status = TransferBatchStatusPartiallyCompleted
+	}
+	b.CompletedAt = time.Now()
+}
+
+// Rollback records the atomic batch failed because the item at index was rejected for the reason.
+// The items already executed by earlier batches keep their outcome.
+func (b *TransferBatch) Rollback(index int, reason string) {
+	for i := range b.Items {
+		switch {
+		case i == index:
+			b.Items[i].Rejected(reason)
+		case b.Items[i].Status != TransferBatchItemStatusDuplicate:
+			b.Items[i].Skipped()
+		}
+	}
+
+	b.Status = TransferBatchStatusFailed
+	b.CompletedAt = time.Now()
+}
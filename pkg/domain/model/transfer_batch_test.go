@@ -0,0 +1,76 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestTransferBatch_Completed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		statuses []TransferBatchItemStatus
+		want     TransferBatchStatus
+	}{
+		{
+			name:     "all executed or duplicate",
+			statuses: []TransferBatchItemStatus{TransferBatchItemStatusExecuted, TransferBatchItemStatusDuplicate},
+			want:     TransferBatchStatusCompleted,
+		},
+		{
+			name:     "some rejected",
+			statuses: []TransferBatchItemStatus{TransferBatchItemStatusExecuted, TransferBatchItemStatusRejected},
+			want:     TransferBatchStatusPartiallyCompleted,
+		},
+		{
+			name:     "all rejected",
+			statuses: []TransferBatchItemStatus{TransferBatchItemStatusRejected, TransferBatchItemStatusRejected},
+			want:     TransferBatchStatusFailed,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			items := make([]TransferBatchItem, 0, len(tt.statuses))
+			for _, status := range tt.statuses {
+				items = append(items, TransferBatchItem{Status: status})
+			}
+
+			batch := NewTransferBatch("uuid-1", TransferBatchModeBestEffort, items)
+			batch.Completed()
+			if batch.Status != tt.want || batch.CompletedAt.IsZero() {
+				t.Errorf("Completed() got = %v, want status %v", batch, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransferBatch_Rollback(t *testing.T) {
+	t.Parallel()
+
+	batch := NewTransferBatch("uuid-1", TransferBatchModeAtomic, []TransferBatchItem{
+		{ItemID: "1", Status: TransferBatchItemStatusExecuted, TransferID: "transfer-1"},
+		{ItemID: "2", Status: TransferBatchItemStatusDuplicate, TransferID: "transfer-0"},
+		{ItemID: "3"},
+		{ItemID: "4"},
+	})
+
+	batch.Rollback(2, "current account balance is insufficient")
+
+	want := []TransferBatchItem{
+		{ItemID: "1", Status: TransferBatchItemStatusSkipped},
+		{ItemID: "2", Status: TransferBatchItemStatusDuplicate, TransferID: "transfer-0"},
+		{ItemID: "3", Status: TransferBatchItemStatusRejected, FailureReason: "current account balance is insufficient"},
+		{ItemID: "4", Status: TransferBatchItemStatusSkipped},
+	}
+	for i, item := range batch.Items {
+		if item != want[i] {
+			t.Errorf("Rollback() item %d = %v, want %v", i, item, want[i])
+		}
+	}
+	if batch.Status != TransferBatchStatusFailed {
+		t.Errorf("Rollback() status = %v, want %v", batch.Status, TransferBatchStatusFailed)
+	}
+}
@@ -0,0 +1,161 @@
+package model
+
+import (
+	"time"
+)
+
+// TransferLimit names one of the TransferLimits.
+type TransferLimit string
+
+const (
+	// TransferLimitPerTransaction limits the amount of each transfer.
+	TransferLimitPerTransaction TransferLimit = "per_transaction"
+	// TransferLimitDaily limits the sum of the transfers sent since the start of the day.
+	TransferLimitDaily TransferLimit = "daily"
+	// TransferLimitMonthly limits the sum of the transfers sent since the start of the month.
+	TransferLimitMonthly TransferLimit = "monthly"
+	// TransferLimitNightPerTransaction limits the amount of each transfer sent at night.
+	TransferLimitNightPerTransaction TransferLimit = "night_per_transaction"
+	// TransferLimitNightTotal limits the sum of the transfers sent since the start of the night.
+	TransferLimitNightTotal TransferLimit = "night_total"
+)
+
+// TransferLimits are the maximum amounts an account can send in transfers. Zero means no limit.
+//
+// The night limits apply on top of the others, during the night of the TransferLimitCalendar.
+type TransferLimits struct {
+	PerTransaction      Money
+	Daily               Money
+	Monthly             Money
+	NightPerTransaction Money
+	NightTotal          Money
+}
+
+// With returns the limits replaced by the ones set for the account.
+func (l TransferLimits) With(accountLimits *AccountTransferLimits) TransferLimits {
+	override := func(limit *Money, accountLimit *Money) {
+		if accountLimit != nil {
+			*limit = *accountLimit
+		}
+	}
+
+	override(&l.PerTransaction, accountLimits.PerTransaction)
+	override(&l.Daily, accountLimits.Daily)
+	override(&l.Monthly, accountLimits.Monthly)
+	override(&l.NightPerTransaction, accountLimits.NightPerTransaction)
+	override(&l.NightTotal, accountLimits.NightTotal)
+
+	return l
+}
+
+// IsUnlimited checks whether none of the limits is set.
+func (l TransferLimits) IsUnlimited() bool {
+	return l == TransferLimits{}
+}
+
+// TransferAllowance is how much an account can still send in one transfer, and the limit that bounds it.
+type TransferAllowance struct {
+	Limit     TransferLimit
+	Remaining Money
+}
+
+// Allowance returns the lowest allowance left by the limits, given the totals already sent in the periods.
+// ok is false when no limit applies.
+func (l TransferLimits) Allowance(totals TransferTotals, atNight bool) (allowance TransferAllowance, ok bool) {
+	consider := func(limit TransferLimit, max Money, used Money) {
+		if max <= 0 {
+			return
+		}
+
+		remaining := max - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		if !ok || remaining < allowance.Remaining {
+			allowance = TransferAllowance{Limit: limit, Remaining: remaining}
+			ok = true
+		}
+	}
+
+	consider(TransferLimitPerTransaction, l.PerTransaction, 0)
+	consider(TransferLimitDaily, l.Daily, totals.Daily)
+	consider(TransferLimitMonthly, l.Monthly, totals.Monthly)
+	if atNight {
+		consider(TransferLimitNightPerTransaction, l.NightPerTransaction, 0)
+		consider(TransferLimitNightTotal, l.NightTotal, totals.Night)
+	}
+
+	return allowance, ok
+}
+
+// AccountTransferLimits are the limits set for an account, replacing the default ones.
+// The nil ones keep the default.
+type AccountTransferLimits struct {
+	AccountID           AccountID
+	PerTransaction      *Money
+	Daily               *Money
+	Monthly             *Money
+	NightPerTransaction *Money
+	NightTotal          *Money
+	UpdatedAt           time.Time
+}
+
+// TransferTotals are the amounts sent by an account in each of the TransferLimitPeriods.
+type TransferTotals struct {
+	Daily   Money
+	Monthly Money
+	Night   Money
+}
+
+// TransferLimitPeriods are the starts of the periods counted by the TransferLimits.
+// NightStart is zero when it's not night.
+type TransferLimitPeriods struct {
+	DayStart   time.Time
+	MonthStart time.Time
+	NightStart time.Time
+}
+
+// IsNight checks whether the periods were taken at night.
+func (p TransferLimitPeriods) IsNight() bool {
+	return !p.NightStart.IsZero()
+}
+
+// TransferLimitCalendar tells when the days, the months and the nights of the TransferLimits start.
+//
+// The night goes from NightStartHour to NightEndHour, like 20h to 6h, and there's no night when they're equal.
+// A nil Location is UTC.
+type TransferLimitCalendar struct {
+	Location       *time.Location
+	NightStartHour int
+	NightEndHour   int
+}
+
+// PeriodsAt returns the starts of the periods that contain t.
+func (c TransferLimitCalendar) PeriodsAt(t time.Time) TransferLimitPeriods {
+	location := c.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	local := t.In(location)
+	year, month, day := local.Date()
+	periods := TransferLimitPeriods{
+		DayStart:   time.Date(year, month, day, 0, 0, 0, 0, location),
+		MonthStart: time.Date(year, month, 1, 0, 0, 0, 0, location),
+	}
+
+	hour := local.Hour()
+	switch {
+	case c.NightStartHour == c.NightEndHour:
+	case c.NightStartHour < c.NightEndHour:
+		if hour >= c.NightStartHour && hour < c.NightEndHour {
+			periods.NightStart = time.Date(year, month, day, c.NightStartHour, 0, 0, 0, location)
+		}
+	case hour >= c.NightStartHour:
+		periods.NightStart = time.Date(year, month, day, c.NightStartHour, 0, 0, 0, location)
+	case hour < c.NightEndHour:
+		periods.NightStart = time.Date(year, month, day-1, c.NightStartHour, 0, 0, 0, location)
+	}
+
+	return periods
+}
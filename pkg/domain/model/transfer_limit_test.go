@@ -0,0 +1,195 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTransferLimits_With(t *testing.T) {
+	t.Parallel()
+
+	zero := Money(0)
+	daily := Money(300)
+	defaults := TransferLimits{PerTransaction: 100, Daily: 200, Monthly: 1000, NightPerTransaction: 50, NightTotal: 80}
+
+	got := defaults.With(&AccountTransferLimits{Daily: &daily, NightTotal: &zero})
+	want := TransferLimits{PerTransaction: 100, Daily: 300, Monthly: 1000, NightPerTransaction: 50, NightTotal: 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("With() = %v, want %v", got, want)
+	}
+	if defaults.Daily != 200 {
+		t.Errorf("With() should not change the defaults")
+	}
+}
+
+func TestTransferLimits_Allowance(t *testing.T) {
+	t.Parallel()
+
+	limits := TransferLimits{PerTransaction: 500, Daily: 1000, Monthly: 5000, NightPerTransaction: 100, NightTotal: 300}
+
+	tests := []struct {
+		name    string
+		limits  TransferLimits
+		totals  TransferTotals
+		atNight bool
+		want    TransferAllowance
+		wantOk  bool
+	}{
+		{
+			name:   "no limits should not be limited",
+			limits: TransferLimits{},
+			totals: TransferTotals{Daily: 1000},
+			wantOk: false,
+		},
+		{
+			name:   "should be the per transaction limit when nothing was sent",
+			limits: limits,
+			want:   TransferAllowance{Limit: TransferLimitPerTransaction, Remaining: 500},
+			wantOk: true,
+		},
+		{
+			name:   "should be what's left of the daily limit",
+			limits: limits,
+			totals: TransferTotals{Daily: 800, Monthly: 800},
+			want:   TransferAllowance{Limit: TransferLimitDaily, Remaining: 200},
+			wantOk: true,
+		},
+		{
+			name:   "should be what's left of the monthly limit",
+			limits: limits,
+			totals: TransferTotals{Daily: 0, Monthly: 4900},
+			want:   TransferAllowance{Limit: TransferLimitMonthly, Remaining: 100},
+			wantOk: true,
+		},
+		{
+			name:   "should not be negative",
+			limits: limits,
+			totals: TransferTotals{Daily: 1200, Monthly: 1200},
+			want:   TransferAllowance{Limit: TransferLimitDaily, Remaining: 0},
+			wantOk: true,
+		},
+		{
+			name:   "night limits should not apply by day",
+			limits: limits,
+			totals: TransferTotals{Night: 300},
+			want:   TransferAllowance{Limit: TransferLimitPerTransaction, Remaining: 500},
+			wantOk: true,
+		},
+		{
+			name:    "should be the night per transaction limit at night",
+			limits:  limits,
+			atNight: true,
+			want:    TransferAllowance{Limit: TransferLimitNightPerTransaction, Remaining: 100},
+			wantOk:  true,
+		},
+		{
+			name:    "should be what's left of the night total",
+			limits:  limits,
+			totals:  TransferTotals{Daily: 250, Monthly: 250, Night: 250},
+			atNight: true,
+			want:    TransferAllowance{Limit: TransferLimitNightTotal, Remaining: 50},
+			wantOk:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, gotOk := tt.limits.Allowance(tt.totals, tt.atNight)
+			if gotOk != tt.wantOk {
+				t.Fatalf("Allowance() ok = %v, want %v", gotOk, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Errorf("Allowance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransferLimitCalendar_PeriodsAt(t *testing.T) {
+	t.Parallel()
+
+	brt := time.FixedZone("BRT", -3*60*60)
+	night := TransferLimitCalendar{Location: brt, NightStartHour: 20, NightEndHour: 6}
+
+	tests := []struct {
+		name     string
+		calendar TransferLimitCalendar
+		at       time.Time
+		want     TransferLimitPeriods
+	}{
+		{
+			name:     "by day should not be night",
+			calendar: night,
+			at:       time.Date(2021, 3, 15, 12, 0, 0, 0, brt),
+			want: TransferLimitPeriods{
+				DayStart:   time.Date(2021, 3, 15, 0, 0, 0, 0, brt),
+				MonthStart: time.Date(2021, 3, 1, 0, 0, 0, 0, brt),
+			},
+		},
+		{
+			name:     "should be night after the start",
+			calendar: night,
+			at:       time.Date(2021, 3, 15, 21, 0, 0, 0, brt),
+			want: TransferLimitPeriods{
+				DayStart:   time.Date(2021, 3, 15, 0, 0, 0, 0, brt),
+				MonthStart: time.Date(2021, 3, 1, 0, 0, 0, 0, brt),
+				NightStart: time.Date(2021, 3, 15, 20, 0, 0, 0, brt),
+			},
+		},
+		{
+			name:     "night should start on the day before, in the month before",
+			calendar: night,
+			at:       time.Date(2021, 3, 1, 2, 0, 0, 0, brt),
+			want: TransferLimitPeriods{
+				DayStart:   time.Date(2021, 3, 1, 0, 0, 0, 0, brt),
+				MonthStart: time.Date(2021, 3, 1, 0, 0, 0, 0, brt),
+				NightStart: time.Date(2021, 2, 28, 20, 0, 0, 0, brt),
+			},
+		},
+		{
+			name:     "should not be night at the end",
+			calendar: night,
+			at:       time.Date(2021, 3, 15, 6, 0, 0, 0, brt),
+			want: TransferLimitPeriods{
+				DayStart:   time.Date(2021, 3, 15, 0, 0, 0, 0, brt),
+				MonthStart: time.Date(2021, 3, 1, 0, 0, 0, 0, brt),
+			},
+		},
+		{
+			name:     "night within the day",
+			calendar: TransferLimitCalendar{Location: brt, NightStartHour: 0, NightEndHour: 5},
+			at:       time.Date(2021, 3, 15, 4, 59, 0, 0, brt),
+			want: TransferLimitPeriods{
+				DayStart:   time.Date(2021, 3, 15, 0, 0, 0, 0, brt),
+				MonthStart: time.Date(2021, 3, 1, 0, 0, 0, 0, brt),
+				NightStart: time.Date(2021, 3, 15, 0, 0, 0, 0, brt),
+			},
+		},
+		{
+			name:     "no night and no location should be UTC days",
+			calendar: TransferLimitCalendar{},
+			at:       time.Date(2021, 3, 15, 1, 0, 0, 0, brt),
+			want: TransferLimitPeriods{
+				DayStart:   time.Date(2021, 3, 15, 0, 0, 0, 0, time.UTC),
+				MonthStart: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.calendar.PeriodsAt(tt.at)
+			if !got.DayStart.Equal(tt.want.DayStart) || !got.MonthStart.Equal(tt.want.MonthStart) || !got.NightStart.Equal(tt.want.NightStart) {
+				t.Errorf("PeriodsAt() = %v, want %v", got, tt.want)
+			}
+			if got.IsNight() != !tt.want.NightStart.IsZero() {
+				t.Errorf("PeriodsAt() IsNight = %v, want %v", got.IsNight(), !tt.want.NightStart.IsZero())
+			}
+		})
+	}
+}
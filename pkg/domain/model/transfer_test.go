@@ -12,7 +12,7 @@ func TestNewTransfer(t *testing.T) {
 	type args struct {
 		accountOriginID      string
 		accountDestinationID string
-		amount               float64
+		amount               Money
 	}
 	tests := []struct {
 		name string
@@ -24,10 +24,11 @@ func TestNewTransfer(t *testing.T) {
 			args: args{
 				accountOriginID:      "uuid-1",
 				accountDestinationID: "uuid-2",
-				amount:               10,
+				amount:               1000,
 			},
 			want: &Transfer{
 				ID:                   "",
+				Kind:                 TransferKindTransfer,
 				AccountOriginID:      "uuid-1",
 				AccountDestinationID: "uuid-2",
 				Amount:               1000,
@@ -39,10 +40,11 @@ func TestNewTransfer(t *testing.T) {
 			args: args{
 				accountOriginID:      "uuid-1",
 				accountDestinationID: "uuid-2",
-				amount:               -10.9,
+				amount:               -1090,
 			},
 			want: &Transfer{
 				ID:                   "",
+				Kind:                 TransferKindTransfer,
 				AccountOriginID:      "uuid-1",
 				AccountDestinationID: "uuid-2",
 				Amount:               -1090,
@@ -69,3 +71,60 @@ func TestNewTransfer(t *testing.T) {
 		})
 	}
 }
+
+func TestTransfer_NewRefundOf(t *testing.T) {
+	t.Parallel()
+
+	original := &Transfer{
+		ID:                   "transfer-1",
+		Kind:                 TransferKindTransfer,
+		AccountOriginID:      "uuid-1",
+		AccountDestinationID: "uuid-2",
+		Amount:               1000,
+		RefundedAmount:       300,
+	}
+	if !original.IsRefundable() || original.RefundableAmount() != 700 {
+		t.Fatalf("IsRefundable() = %v, RefundableAmount() = %v, want refundable 700", original.IsRefundable(), original.RefundableAmount())
+	}
+
+	got := original.NewRefundOf(TransferKindRefund, 200)
+	if len(got.ID) <= 0 || got.ID == original.ID {
+		t.Errorf("NewRefundOf() = %v, ID should be new", got)
+	}
+	got.ID = ""
+	got.CreatedAt = time.Time{}
+
+	want := &Transfer{
+		Kind:                 TransferKindRefund,
+		OriginalTransferID:   "transfer-1",
+		AccountOriginID:      "uuid-2",
+		AccountDestinationID: "uuid-1",
+		Amount:               200,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewRefundOf() = %v, want %v", got, want)
+	}
+	if got.IsRefundable() {
+		t.Errorf("IsRefundable() = true, a refund should not be refundable")
+	}
+	if got.Kind.LedgerPostingKind() != LedgerPostingRefund {
+		t.Errorf("LedgerPostingKind() = %v, want %v", got.Kind.LedgerPostingKind(), LedgerPostingRefund)
+	}
+}
+
+func TestTransfer_currencies(t *testing.T) {
+	t.Parallel()
+
+	transfer := NewTransfer("uuid-1", "uuid-2", 10000)
+	transfer.InCurrency(CurrencyUSD)
+	if transfer.IsConversion() || transfer.DestinationCurrency != CurrencyUSD || transfer.DestinationAmount != 10000 {
+		t.Errorf("InCurrency() = %v, want a USD transfer without conversion", transfer)
+	}
+
+	quote := NewFXQuote("uuid-1", CurrencyUSD, CurrencyBRL, 512340000, 10000, time.Minute)
+	transfer.ConvertBy(quote)
+	if !transfer.IsConversion() || transfer.FXQuoteID != quote.ID || transfer.FXRate != 512340000 ||
+		transfer.Currency != CurrencyUSD || transfer.DestinationCurrency != CurrencyBRL || transfer.DestinationAmount != 51234 {
+		t.Errorf("ConvertBy() = %v, want the conversion of the quote %v", transfer, quote)
+	}
+}
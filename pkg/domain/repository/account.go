@@ -17,7 +17,18 @@ type AccountRepository interface {
 	Create(ctx context.Context, account *model.Account) error
 	ExistsByCPF(ctx context.Context, cpf model.CPF) (bool, error)
 	GetByCPF(ctx context.Context, cpf model.CPF) (*model.Account, error)
-	Fetch(ctx context.Context) ([]model.Account, error)
+	GetByID(ctx context.Context, id model.AccountID) (*model.Account, error)
+	// Fetch returns up to filter.Limit accounts matching the filter, sorted by filter.Sort.
+	// The accounts have only their ID, name, CPF, balance, status and creation time, never the secret.
+	Fetch(ctx context.Context, filter model.AccountFilter) ([]model.Account, error)
+	// GetBalance returns the account with only its ID, currency, balance, credit limit, tier and status.
 	GetBalance(ctx context.Context, id model.AccountID) (*model.Account, error)
-	UpdateBalance(ctx context.Context, id model.AccountID, balance model.Money) error
+	// GetBalanceForUpdate works like GetBalance, but locks the account row until the current transaction ends.
+	GetBalanceForUpdate(ctx context.Context, id model.AccountID) (*model.Account, error)
+	// UpdateStatus saves the account status, its reason and when it changed.
+	UpdateStatus(ctx context.Context, account *model.Account) error
+	// UpdateCreditLimit saves the account credit limit.
+	UpdateCreditLimit(ctx context.Context, account *model.Account) error
+	// UpdateTier saves the account tier.
+	UpdateTier(ctx context.Context, account *model.Account) error
 }
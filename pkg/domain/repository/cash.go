@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+// CashRepository is the interface that wraps deposit and withdrawal datasource methods.
+type CashRepository interface {
+	Transaction
+	CreateDeposit(ctx context.Context, deposit *model.Deposit) error
+	CreateWithdrawal(ctx context.Context, withdrawal *model.Withdrawal) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+// FeeRepository is the interface that wraps the maintenance fee datasource methods.
+type FeeRepository interface {
+	Transaction
+	// LockNextMaintenanceDue returns the next account, in ID order, matching the filter and not charged the
+	// maintenance fee of the month yet, or nil if there's none, and locks its row until the current transaction ends.
+	// The rows locked by other transactions are skipped, so concurrent callers never get the same account.
+	// The account has only its ID, currency, balance, credit limit, tier and status.
+	LockNextMaintenanceDue(ctx context.Context, filter model.MaintenanceFeeFilter) (*model.Account, error)
+	// CreateMaintenanceCharge saves the maintenance fee charged on the account for the month.
+	CreateMaintenanceCharge(ctx context.Context, charge *model.MaintenanceFeeCharge) error
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+var (
+	// ErrFXQuoteNotFound happens when the FX quote was not found based on search params.
+	ErrFXQuoteNotFound = errors.New("fx quote not found")
+	// ErrFXQuoteUsed happens when a transfer was already made by the FX quote.
+	ErrFXQuoteUsed = errors.New("fx quote was already used")
+	// ErrFXRateNotFound happens when the rate provider has no exchange rate between the currencies.
+	ErrFXRateNotFound = errors.New("exchange rate not found")
+)
+
+// FXQuoteRepository is the interface that wraps FX quote datasource methods.
+type FXQuoteRepository interface {
+	Transaction
+	// Create saves the quote.
+	Create(ctx context.Context, quote *model.FXQuote) error
+	// GetByIDForUpdate returns the quote, with the transfer made by it if any, and locks its row until the current
+	// transaction ends.
+	GetByIDForUpdate(ctx context.Context, id model.FXQuoteID) (*model.FXQuote, error)
+}
+
+// FXRateProvider is the interface that wraps the source of the exchange rates.
+type FXRateProvider interface {
+	// GetRate returns how many units of the target currency are worth one unit of the source currency,
+	// or ErrFXRateNotFound when there is no rate between them.
+	GetRate(ctx context.Context, source, target model.Currency) (model.FXRate, error)
+}
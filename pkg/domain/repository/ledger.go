@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+// LedgerRepository is the interface that wraps ledger datasource methods.
+//
+// The ledger entries are immutable: balances change only by posting new entries. The balances are rebuilt from the
+// entries in one currency, so the bank ledger accounts, which have entries in many, are read one position at a time.
+type LedgerRepository interface {
+	Transaction
+	// Post records the posting entries and applies them to the accounts balances.
+	// It should be called within a transaction, after locking the accounts involved.
+	Post(ctx context.Context, posting *model.LedgerPosting) error
+	// GetBalance rebuilds the account balance in the currency from its ledger entries.
+	GetBalance(ctx context.Context, accountID model.AccountID, currency model.Currency) (model.Money, error)
+	// GetBalanceAt rebuilds the account balance in the currency at the time from its ledger entries created before it.
+	GetBalanceAt(ctx context.Context, accountID model.AccountID, currency model.Currency, at time.Time) (model.Money, error)
+	FetchEntries(ctx context.Context, accountID model.AccountID) ([]model.LedgerEntry, error)
+	// GetStatement returns the account entries in the currency created in [from, to), with their counterparts and
+	// running balances.
+	GetStatement(ctx context.Context, accountID model.AccountID, currency model.Currency, from, to time.Time) (*model.Statement, error)
+}
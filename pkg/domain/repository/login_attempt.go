@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+// LoginAttemptRepository is the interface that wraps failed login attempts datasource methods.
+type LoginAttemptRepository interface {
+	// Get returns the failed login attempts of the key, or zero attempts if there is none.
+	Get(ctx context.Context, key string) (*model.LoginAttempts, error)
+	// RegisterFailure increments the failures of the key and returns the new count.
+	// The failures are forgotten after window without new failures.
+	RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	// Lock rejects the key login attempts until the given time.
+	Lock(ctx context.Context, key string, until time.Time) error
+	// Reset forgets the failures and the lock of the key.
+	Reset(ctx context.Context, key string) error
+}
@@ -9,12 +9,16 @@ import (
 
 // AccountRepository mocks an AccountRepository.
 type AccountRepository struct {
-	OnCreate        func(ctx context.Context, account *model.Account) error
-	OnExistsByCPF   func(ctx context.Context, cpf model.CPF) (bool, error)
-	OnGetByCPF      func(ctx context.Context, cpf model.CPF) (*model.Account, error)
-	OnFetch         func(ctx context.Context) ([]model.Account, error)
-	OnGetBalance    func(ctx context.Context, id model.AccountID) (*model.Account, error)
-	OnUpdateBalance func(ctx context.Context, id model.AccountID, balance model.Money) error
+	OnCreate              func(ctx context.Context, account *model.Account) error
+	OnExistsByCPF         func(ctx context.Context, cpf model.CPF) (bool, error)
+	OnGetByCPF            func(ctx context.Context, cpf model.CPF) (*model.Account, error)
+	OnGetByID             func(ctx context.Context, id model.AccountID) (*model.Account, error)
+	OnFetch               func(ctx context.Context, filter model.AccountFilter) ([]model.Account, error)
+	OnGetBalance          func(ctx context.Context, id model.AccountID) (*model.Account, error)
+	OnGetBalanceForUpdate func(ctx context.Context, id model.AccountID) (*model.Account, error)
+	OnUpdateStatus        func(ctx context.Context, account *model.Account) error
+	OnUpdateCreditLimit   func(ctx context.Context, account *model.Account) error
+	OnUpdateTier          func(ctx context.Context, account *model.Account) error
 }
 
 var _ repository.AccountRepository = (*AccountRepository)(nil)
@@ -34,9 +38,14 @@ func (mAccRepo AccountRepository) GetByCPF(ctx context.Context, cpf model.CPF) (
 	return mAccRepo.OnGetByCPF(ctx, cpf)
 }
 
+// GetByID executes OnGetByID.
+func (mAccRepo AccountRepository) GetByID(ctx context.Context, id model.AccountID) (*model.Account, error) {
+	return mAccRepo.OnGetByID(ctx, id)
+}
+
 // Fetch executes OnFetch.
-func (mAccRepo AccountRepository) Fetch(ctx context.Context) ([]model.Account, error) {
-	return mAccRepo.OnFetch(ctx)
+func (mAccRepo AccountRepository) Fetch(ctx context.Context, filter model.AccountFilter) ([]model.Account, error) {
+	return mAccRepo.OnFetch(ctx, filter)
 }
 
 // GetBalance executes OnGetBalance.
@@ -44,7 +53,22 @@ func (mAccRepo AccountRepository) GetBalance(ctx context.Context, id model.Accou
 	return mAccRepo.OnGetBalance(ctx, id)
 }
 
-// UpdateBalance executes OnUpdateBalance.
-func (mAccRepo AccountRepository) UpdateBalance(ctx context.Context, id model.AccountID, balance model.Money) error {
-	return mAccRepo.OnUpdateBalance(ctx, id, balance)
+// GetBalanceForUpdate executes OnGetBalanceForUpdate.
+func (mAccRepo AccountRepository) GetBalanceForUpdate(ctx context.Context, id model.AccountID) (*model.Account, error) {
+	return mAccRepo.OnGetBalanceForUpdate(ctx, id)
+}
+
+// UpdateStatus executes OnUpdateStatus.
+func (mAccRepo AccountRepository) UpdateStatus(ctx context.Context, account *model.Account) error {
+	return mAccRepo.OnUpdateStatus(ctx, account)
+}
+
+// UpdateCreditLimit executes OnUpdateCreditLimit.
+func (mAccRepo AccountRepository) UpdateCreditLimit(ctx context.Context, account *model.Account) error {
+	return mAccRepo.OnUpdateCreditLimit(ctx, account)
+}
+
+// UpdateTier executes OnUpdateTier.
+func (mAccRepo AccountRepository) UpdateTier(ctx context.Context, account *model.Account) error {
+	return mAccRepo.OnUpdateTier(ctx, account)
 }
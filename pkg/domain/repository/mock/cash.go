@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// CashRepository mocks a CashRepository.
+type CashRepository struct {
+	OnCreateDeposit     func(ctx context.Context, deposit *model.Deposit) error
+	OnCreateWithdrawal  func(ctx context.Context, withdrawal *model.Withdrawal) error
+	OnWithinTransaction func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
+}
+
+var _ repository.CashRepository = (*CashRepository)(nil)
+
+// CreateDeposit executes OnCreateDeposit.
+func (mCashRepo CashRepository) CreateDeposit(ctx context.Context, deposit *model.Deposit) error {
+	return mCashRepo.OnCreateDeposit(ctx, deposit)
+}
+
+// CreateWithdrawal executes OnCreateWithdrawal.
+func (mCashRepo CashRepository) CreateWithdrawal(ctx context.Context, withdrawal *model.Withdrawal) error {
+	return mCashRepo.OnCreateWithdrawal(ctx, withdrawal)
+}
+
+// WithinTransaction executes OnWithinTransaction.
+func (mCashRepo CashRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return mCashRepo.OnWithinTransaction(ctx, txFunc)
+}
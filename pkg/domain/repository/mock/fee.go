@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// FeeRepository mocks a FeeRepository.
+type FeeRepository struct {
+	OnLockNextMaintenanceDue  func(ctx context.Context, filter model.MaintenanceFeeFilter) (*model.Account, error)
+	OnCreateMaintenanceCharge func(ctx context.Context, charge *model.MaintenanceFeeCharge) error
+	OnWithinTransaction       func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
+}
+
+var _ repository.FeeRepository = (*FeeRepository)(nil)
+
+// LockNextMaintenanceDue executes OnLockNextMaintenanceDue.
+func (mFeeRepo FeeRepository) LockNextMaintenanceDue(ctx context.Context, filter model.MaintenanceFeeFilter) (*model.Account, error) {
+	return mFeeRepo.OnLockNextMaintenanceDue(ctx, filter)
+}
+
+// CreateMaintenanceCharge executes OnCreateMaintenanceCharge.
+func (mFeeRepo FeeRepository) CreateMaintenanceCharge(ctx context.Context, charge *model.MaintenanceFeeCharge) error {
+	return mFeeRepo.OnCreateMaintenanceCharge(ctx, charge)
+}
+
+// WithinTransaction executes OnWithinTransaction.
+func (mFeeRepo FeeRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return mFeeRepo.OnWithinTransaction(ctx, txFunc)
+}
@@ -0,0 +1,44 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// FXQuoteRepository mocks a FXQuoteRepository.
+type FXQuoteRepository struct {
+	OnCreate            func(ctx context.Context, quote *model.FXQuote) error
+	OnGetByIDForUpdate  func(ctx context.Context, id model.FXQuoteID) (*model.FXQuote, error)
+	OnWithinTransaction func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
+}
+
+var _ repository.FXQuoteRepository = (*FXQuoteRepository)(nil)
+
+// Create executes OnCreate.
+func (mQuoteRepo FXQuoteRepository) Create(ctx context.Context, quote *model.FXQuote) error {
+	return mQuoteRepo.OnCreate(ctx, quote)
+}
+
+// GetByIDForUpdate executes OnGetByIDForUpdate.
+func (mQuoteRepo FXQuoteRepository) GetByIDForUpdate(ctx context.Context, id model.FXQuoteID) (*model.FXQuote, error) {
+	return mQuoteRepo.OnGetByIDForUpdate(ctx, id)
+}
+
+// WithinTransaction executes OnWithinTransaction.
+func (mQuoteRepo FXQuoteRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return mQuoteRepo.OnWithinTransaction(ctx, txFunc)
+}
+
+// FXRateProvider mocks a FXRateProvider.
+type FXRateProvider struct {
+	OnGetRate func(ctx context.Context, source, target model.Currency) (model.FXRate, error)
+}
+
+var _ repository.FXRateProvider = (*FXRateProvider)(nil)
+
+// GetRate executes OnGetRate.
+func (mProvider FXRateProvider) GetRate(ctx context.Context, source, target model.Currency) (model.FXRate, error) {
+	return mProvider.OnGetRate(ctx, source, target)
+}
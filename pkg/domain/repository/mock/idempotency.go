@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// IdempotencyRepository mocks an IdempotencyRepository.
+type IdempotencyRepository struct {
+	OnGet func(ctx context.Context, key string) ([]byte, error)
+	OnSet func(ctx context.Context, key string, value []byte, duration time.Duration) error
+}
+
+var _ repository.IdempotencyRepository = (*IdempotencyRepository)(nil)
+
+// Get executes OnGet.
+func (mIdpRepo IdempotencyRepository) Get(ctx context.Context, key string) ([]byte, error) {
+	return mIdpRepo.OnGet(ctx, key)
+}
+
+// Set executes OnSet.
+func (mIdpRepo IdempotencyRepository) Set(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	return mIdpRepo.OnSet(ctx, key, value, duration)
+}
@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// LedgerRepository mocks a LedgerRepository.
+type LedgerRepository struct {
+	OnPost              func(ctx context.Context, posting *model.LedgerPosting) error
+	OnGetBalance        func(ctx context.Context, accountID model.AccountID, currency model.Currency) (model.Money, error)
+	OnGetBalanceAt      func(ctx context.Context, accountID model.AccountID, currency model.Currency, at time.Time) (model.Money, error)
+	OnFetchEntries      func(ctx context.Context, accountID model.AccountID) ([]model.LedgerEntry, error)
+	OnGetStatement      func(ctx context.Context, accountID model.AccountID, currency model.Currency, from, to time.Time) (*model.Statement, error)
+	OnWithinTransaction func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
+}
+
+var _ repository.LedgerRepository = (*LedgerRepository)(nil)
+
+// Post executes OnPost.
+func (mLdgRepo LedgerRepository) Post(ctx context.Context, posting *model.LedgerPosting) error {
+	return mLdgRepo.OnPost(ctx, posting)
+}
+
+// GetBalance executes OnGetBalance.
+func (mLdgRepo LedgerRepository) GetBalance(ctx context.Context, accountID model.AccountID, currency model.Currency) (model.Money, error) {
+	return mLdgRepo.OnGetBalance(ctx, accountID, currency)
+}
+
+// GetBalanceAt executes OnGetBalanceAt.
+func (mLdgRepo LedgerRepository) GetBalanceAt(ctx context.Context, accountID model.AccountID, currency model.Currency, at time.Time) (model.Money, error) {
+	return mLdgRepo.OnGetBalanceAt(ctx, accountID, currency, at)
+}
+
+// FetchEntries executes OnFetchEntries.
+func (mLdgRepo LedgerRepository) FetchEntries(ctx context.Context, accountID model.AccountID) ([]model.LedgerEntry, error) {
+	return mLdgRepo.OnFetchEntries(ctx, accountID)
+}
+
+// GetStatement executes OnGetStatement.
+func (mLdgRepo LedgerRepository) GetStatement(ctx context.Context, accountID model.AccountID, currency model.Currency, from, to time.Time) (*model.Statement, error) {
+	return mLdgRepo.OnGetStatement(ctx, accountID, currency, from, to)
+}
+
+// WithinTransaction executes OnWithinTransaction.
+func (mLdgRepo LedgerRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return mLdgRepo.OnWithinTransaction(ctx, txFunc)
+}
@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// LoginAttemptRepository mocks a LoginAttemptRepository.
+type LoginAttemptRepository struct {
+	OnGet             func(ctx context.Context, key string) (*model.LoginAttempts, error)
+	OnRegisterFailure func(ctx context.Context, key string, window time.Duration) (int, error)
+	OnLock            func(ctx context.Context, key string, until time.Time) error
+	OnReset           func(ctx context.Context, key string) error
+}
+
+var _ repository.LoginAttemptRepository = (*LoginAttemptRepository)(nil)
+
+// Get executes OnGet.
+func (mAttRepo LoginAttemptRepository) Get(ctx context.Context, key string) (*model.LoginAttempts, error) {
+	return mAttRepo.OnGet(ctx, key)
+}
+
+// RegisterFailure executes OnRegisterFailure.
+func (mAttRepo LoginAttemptRepository) RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	return mAttRepo.OnRegisterFailure(ctx, key, window)
+}
+
+// Lock executes OnLock.
+func (mAttRepo LoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	return mAttRepo.OnLock(ctx, key, until)
+}
+
+// Reset executes OnReset.
+func (mAttRepo LoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	return mAttRepo.OnReset(ctx, key)
+}
@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// NotificationRepository mocks a NotificationRepository.
+type NotificationRepository struct {
+	OnCreate func(ctx context.Context, notification *model.Notification) error
+	OnFetch  func(ctx context.Context, accountID model.AccountID, limit int) ([]model.Notification, error)
+}
+
+var _ repository.NotificationRepository = (*NotificationRepository)(nil)
+
+// Create executes OnCreate.
+func (mNtfRepo NotificationRepository) Create(ctx context.Context, notification *model.Notification) error {
+	return mNtfRepo.OnCreate(ctx, notification)
+}
+
+// Fetch executes OnFetch.
+func (mNtfRepo NotificationRepository) Fetch(ctx context.Context, accountID model.AccountID, limit int) ([]model.Notification, error) {
+	return mNtfRepo.OnFetch(ctx, accountID, limit)
+}
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// OverdraftRepository mocks an OverdraftRepository.
+type OverdraftRepository struct {
+	OnLockNextOverdrawn    func(ctx context.Context, filter model.OverdraftInterestFilter) (*model.Account, error)
+	OnCreateInterestCharge func(ctx context.Context, charge *model.OverdraftInterestCharge) error
+	OnWithinTransaction    func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
+}
+
+var _ repository.OverdraftRepository = (*OverdraftRepository)(nil)
+
+// LockNextOverdrawn executes OnLockNextOverdrawn.
+func (mOdRepo OverdraftRepository) LockNextOverdrawn(ctx context.Context, filter model.OverdraftInterestFilter) (*model.Account, error) {
+	return mOdRepo.OnLockNextOverdrawn(ctx, filter)
+}
+
+// CreateInterestCharge executes OnCreateInterestCharge.
+func (mOdRepo OverdraftRepository) CreateInterestCharge(ctx context.Context, charge *model.OverdraftInterestCharge) error {
+	return mOdRepo.OnCreateInterestCharge(ctx, charge)
+}
+
+// WithinTransaction executes OnWithinTransaction.
+func (mOdRepo OverdraftRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return mOdRepo.OnWithinTransaction(ctx, txFunc)
+}
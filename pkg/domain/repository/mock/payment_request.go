@@ -0,0 +1,63 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// PaymentRequestRepository mocks a PaymentRequestRepository.
+type PaymentRequestRepository struct {
+	OnCreate            func(ctx context.Context, request *model.PaymentRequest) error
+	OnFetchByPayer      func(ctx context.Context, payerID model.AccountID, status model.PaymentRequestStatus) ([]model.PaymentRequest, error)
+	OnFetchByRequester  func(ctx context.Context, requesterID model.AccountID, status model.PaymentRequestStatus) ([]model.PaymentRequest, error)
+	OnGetByID           func(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error)
+	OnGetByIDForUpdate  func(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error)
+	OnUpdateStatus      func(ctx context.Context, request *model.PaymentRequest) error
+	OnExpireDue         func(ctx context.Context, now time.Time, limit int) (int, error)
+	OnWithinTransaction func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
+}
+
+var _ repository.PaymentRequestRepository = (*PaymentRequestRepository)(nil)
+
+// Create executes OnCreate.
+func (mPrRepo PaymentRequestRepository) Create(ctx context.Context, request *model.PaymentRequest) error {
+	return mPrRepo.OnCreate(ctx, request)
+}
+
+// FetchByPayer executes OnFetchByPayer.
+func (mPrRepo PaymentRequestRepository) FetchByPayer(ctx context.Context, payerID model.AccountID, status model.PaymentRequestStatus) ([]model.PaymentRequest, error) {
+	return mPrRepo.OnFetchByPayer(ctx, payerID, status)
+}
+
+// FetchByRequester executes OnFetchByRequester.
+func (mPrRepo PaymentRequestRepository) FetchByRequester(ctx context.Context, requesterID model.AccountID, status model.PaymentRequestStatus) ([]model.PaymentRequest, error) {
+	return mPrRepo.OnFetchByRequester(ctx, requesterID, status)
+}
+
+// GetByID executes OnGetByID.
+func (mPrRepo PaymentRequestRepository) GetByID(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error) {
+	return mPrRepo.OnGetByID(ctx, id)
+}
+
+// GetByIDForUpdate executes OnGetByIDForUpdate.
+func (mPrRepo PaymentRequestRepository) GetByIDForUpdate(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error) {
+	return mPrRepo.OnGetByIDForUpdate(ctx, id)
+}
+
+// UpdateStatus executes OnUpdateStatus.
+func (mPrRepo PaymentRequestRepository) UpdateStatus(ctx context.Context, request *model.PaymentRequest) error {
+	return mPrRepo.OnUpdateStatus(ctx, request)
+}
+
+// ExpireDue executes OnExpireDue.
+func (mPrRepo PaymentRequestRepository) ExpireDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	return mPrRepo.OnExpireDue(ctx, now, limit)
+}
+
+// WithinTransaction executes OnWithinTransaction.
+func (mPrRepo PaymentRequestRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return mPrRepo.OnWithinTransaction(ctx, txFunc)
+}
@@ -0,0 +1,74 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// PixKeyRepository mocks a PixKeyRepository.
+type PixKeyRepository struct {
+	OnCreate                     func(ctx context.Context, key *model.PixKey) error
+	OnFetch                      func(ctx context.Context, accountID model.AccountID) ([]model.PixKey, error)
+	OnGetByValue                 func(ctx context.Context, value string) (*model.PixKey, error)
+	OnGetByAccountValue          func(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error)
+	OnGetByAccountValueForUpdate func(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error)
+	OnUpdate                     func(ctx context.Context, key *model.PixKey) error
+	OnDelete                     func(ctx context.Context, id model.PixKeyID) error
+	OnWithinTransaction          func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
+}
+
+var _ repository.PixKeyRepository = (*PixKeyRepository)(nil)
+
+// Create executes OnCreate.
+func (mKeyRepo PixKeyRepository) Create(ctx context.Context, key *model.PixKey) error {
+	return mKeyRepo.OnCreate(ctx, key)
+}
+
+// Fetch executes OnFetch.
+func (mKeyRepo PixKeyRepository) Fetch(ctx context.Context, accountID model.AccountID) ([]model.PixKey, error) {
+	return mKeyRepo.OnFetch(ctx, accountID)
+}
+
+// GetByValue executes OnGetByValue.
+func (mKeyRepo PixKeyRepository) GetByValue(ctx context.Context, value string) (*model.PixKey, error) {
+	return mKeyRepo.OnGetByValue(ctx, value)
+}
+
+// GetByAccountValue executes OnGetByAccountValue.
+func (mKeyRepo PixKeyRepository) GetByAccountValue(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error) {
+	return mKeyRepo.OnGetByAccountValue(ctx, accountID, value)
+}
+
+// GetByAccountValueForUpdate executes OnGetByAccountValueForUpdate.
+func (mKeyRepo PixKeyRepository) GetByAccountValueForUpdate(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error) {
+	return mKeyRepo.OnGetByAccountValueForUpdate(ctx, accountID, value)
+}
+
+// Update executes OnUpdate.
+func (mKeyRepo PixKeyRepository) Update(ctx context.Context, key *model.PixKey) error {
+	return mKeyRepo.OnUpdate(ctx, key)
+}
+
+// Delete executes OnDelete.
+func (mKeyRepo PixKeyRepository) Delete(ctx context.Context, id model.PixKeyID) error {
+	return mKeyRepo.OnDelete(ctx, id)
+}
+
+// WithinTransaction executes OnWithinTransaction.
+func (mKeyRepo PixKeyRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return mKeyRepo.OnWithinTransaction(ctx, txFunc)
+}
+
+// PixKeyVerificationSender mocks a PixKeyVerificationSender.
+type PixKeyVerificationSender struct {
+	OnSendVerificationCode func(ctx context.Context, key *model.PixKey, code string) error
+}
+
+var _ repository.PixKeyVerificationSender = (*PixKeyVerificationSender)(nil)
+
+// SendVerificationCode executes OnSendVerificationCode.
+func (mSender PixKeyVerificationSender) SendVerificationCode(ctx context.Context, key *model.PixKey, code string) error {
+	return mSender.OnSendVerificationCode(ctx, key, code)
+}
@@ -0,0 +1,63 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// SavingsRepository mocks a SavingsRepository.
+type SavingsRepository struct {
+	OnLockNextAccrualDue func(ctx context.Context, filter model.SavingsAccrualFilter) (*model.Account, error)
+	OnCreateAccrual      func(ctx context.Context, accrual *model.SavingsInterestAccrual) error
+	OnLockNextPaymentDue func(ctx context.Context, filter model.SavingsPaymentFilter) (*model.Account, error)
+	OnGetTotals          func(ctx context.Context, accountID model.AccountID, end time.Time) (*model.SavingsInterestTotals, error)
+	OnCreatePayment      func(ctx context.Context, payment *model.SavingsInterestPayment) error
+	OnFetchAccruals      func(ctx context.Context, accountID model.AccountID, from, to time.Time) ([]model.SavingsInterestAccrual, error)
+	OnFetchPayments      func(ctx context.Context, accountID model.AccountID, from, to time.Time) ([]model.SavingsInterestPayment, error)
+	OnWithinTransaction  func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
+}
+
+var _ repository.SavingsRepository = (*SavingsRepository)(nil)
+
+// LockNextAccrualDue executes OnLockNextAccrualDue.
+func (mSavRepo SavingsRepository) LockNextAccrualDue(ctx context.Context, filter model.SavingsAccrualFilter) (*model.Account, error) {
+	return mSavRepo.OnLockNextAccrualDue(ctx, filter)
+}
+
+// CreateAccrual executes OnCreateAccrual.
+func (mSavRepo SavingsRepository) CreateAccrual(ctx context.Context, accrual *model.SavingsInterestAccrual) error {
+	return mSavRepo.OnCreateAccrual(ctx, accrual)
+}
+
+// LockNextPaymentDue executes OnLockNextPaymentDue.
+func (mSavRepo SavingsRepository) LockNextPaymentDue(ctx context.Context, filter model.SavingsPaymentFilter) (*model.Account, error) {
+	return mSavRepo.OnLockNextPaymentDue(ctx, filter)
+}
+
+// GetTotals executes OnGetTotals.
+func (mSavRepo SavingsRepository) GetTotals(ctx context.Context, accountID model.AccountID, end time.Time) (*model.SavingsInterestTotals, error) {
+	return mSavRepo.OnGetTotals(ctx, accountID, end)
+}
+
+// CreatePayment executes OnCreatePayment.
+func (mSavRepo SavingsRepository) CreatePayment(ctx context.Context, payment *model.SavingsInterestPayment) error {
+	return mSavRepo.OnCreatePayment(ctx, payment)
+}
+
+// FetchAccruals executes OnFetchAccruals.
+func (mSavRepo SavingsRepository) FetchAccruals(ctx context.Context, accountID model.AccountID, from, to time.Time) ([]model.SavingsInterestAccrual, error) {
+	return mSavRepo.OnFetchAccruals(ctx, accountID, from, to)
+}
+
+// FetchPayments executes OnFetchPayments.
+func (mSavRepo SavingsRepository) FetchPayments(ctx context.Context, accountID model.AccountID, from, to time.Time) ([]model.SavingsInterestPayment, error) {
+	return mSavRepo.OnFetchPayments(ctx, accountID, from, to)
+}
+
+// WithinTransaction executes OnWithinTransaction.
+func (mSavRepo SavingsRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return mSavRepo.OnWithinTransaction(ctx, txFunc)
+}
@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// ScheduledTransferRepository mocks a ScheduledTransferRepository.
+type ScheduledTransferRepository struct {
+	OnCreate            func(ctx context.Context, schedule *model.ScheduledTransfer) error
+	OnFetch             func(ctx context.Context, originID model.AccountID, status model.ScheduledTransferStatus) ([]model.ScheduledTransfer, error)
+	OnGetByIDForUpdate  func(ctx context.Context, id model.ScheduledTransferID) (*model.ScheduledTransfer, error)
+	OnLockNextDue       func(ctx context.Context, now time.Time, skip []model.ScheduledTransferID) (*model.ScheduledTransfer, error)
+	OnUpdateStatus      func(ctx context.Context, schedule *model.ScheduledTransfer) error
+	OnWithinTransaction func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
+}
+
+var _ repository.ScheduledTransferRepository = (*ScheduledTransferRepository)(nil)
+
+// Create executes OnCreate.
+func (mSchRepo ScheduledTransferRepository) Create(ctx context.Context, schedule *model.ScheduledTransfer) error {
+	return mSchRepo.OnCreate(ctx, schedule)
+}
+
+// Fetch executes OnFetch.
+func (mSchRepo ScheduledTransferRepository) Fetch(ctx context.Context, originID model.AccountID, status model.ScheduledTransferStatus) ([]model.ScheduledTransfer, error) {
+	return mSchRepo.OnFetch(ctx, originID, status)
+}
+
+// GetByIDForUpdate executes OnGetByIDForUpdate.
+func (mSchRepo ScheduledTransferRepository) GetByIDForUpdate(ctx context.Context, id model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+	return mSchRepo.OnGetByIDForUpdate(ctx, id)
+}
+
+// LockNextDue executes OnLockNextDue.
+func (mSchRepo ScheduledTransferRepository) LockNextDue(ctx context.Context, now time.Time, skip []model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+	return mSchRepo.OnLockNextDue(ctx, now, skip)
+}
+
+// UpdateStatus executes OnUpdateStatus.
+func (mSchRepo ScheduledTransferRepository) UpdateStatus(ctx context.Context, schedule *model.ScheduledTransfer) error {
+	return mSchRepo.OnUpdateStatus(ctx, schedule)
+}
+
+// WithinTransaction executes OnWithinTransaction.
+func (mSchRepo ScheduledTransferRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return mSchRepo.OnWithinTransaction(ctx, txFunc)
+}
@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// StandingOrderRepository mocks a StandingOrderRepository.
+type StandingOrderRepository struct {
+	OnCreate            func(ctx context.Context, order *model.StandingOrder) error
+	OnFetch             func(ctx context.Context, originID model.AccountID) ([]model.StandingOrder, error)
+	OnGetByIDForUpdate  func(ctx context.Context, id model.StandingOrderID) (*model.StandingOrder, error)
+	OnLockNextDue       func(ctx context.Context, now time.Time) (*model.StandingOrder, error)
+	OnUpdate            func(ctx context.Context, order *model.StandingOrder) error
+	OnWithinTransaction func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
+}
+
+var _ repository.StandingOrderRepository = (*StandingOrderRepository)(nil)
+
+// Create executes OnCreate.
+func (mSoRepo StandingOrderRepository) Create(ctx context.Context, order *model.StandingOrder) error {
+	return mSoRepo.OnCreate(ctx, order)
+}
+
+// Fetch executes OnFetch.
+func (mSoRepo StandingOrderRepository) Fetch(ctx context.Context, originID model.AccountID) ([]model.StandingOrder, error) {
+	return mSoRepo.OnFetch(ctx, originID)
+}
+
+// GetByIDForUpdate executes OnGetByIDForUpdate.
+func (mSoRepo StandingOrderRepository) GetByIDForUpdate(ctx context.Context, id model.StandingOrderID) (*model.StandingOrder, error) {
+	return mSoRepo.OnGetByIDForUpdate(ctx, id)
+}
+
+// LockNextDue executes OnLockNextDue.
+func (mSoRepo StandingOrderRepository) LockNextDue(ctx context.Context, now time.Time) (*model.StandingOrder, error) {
+	return mSoRepo.OnLockNextDue(ctx, now)
+}
+
+// Update executes OnUpdate.
+func (mSoRepo StandingOrderRepository) Update(ctx context.Context, order *model.StandingOrder) error {
+	return mSoRepo.OnUpdate(ctx, order)
+}
+
+// WithinTransaction executes OnWithinTransaction.
+func (mSoRepo StandingOrderRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return mSoRepo.OnWithinTransaction(ctx, txFunc)
+}
@@ -0,0 +1,57 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// TokenRepository mocks a TokenRepository.
+type TokenRepository struct {
+	OnCreateRefreshToken          func(ctx context.Context, refreshToken *model.RefreshToken) error
+	OnGetRefreshToken             func(ctx context.Context, token string) (*model.RefreshToken, error)
+	OnUseRefreshToken             func(ctx context.Context, refreshToken *model.RefreshToken) (bool, error)
+	OnRevokeRefreshTokenFamily    func(ctx context.Context, familyID model.RefreshTokenFamilyID, duration time.Duration) error
+	OnIsRefreshTokenFamilyRevoked func(ctx context.Context, familyID model.RefreshTokenFamilyID) (bool, error)
+	OnDenyAccessToken             func(ctx context.Context, tokenID string, duration time.Duration) error
+	OnIsAccessTokenDenied         func(ctx context.Context, tokenID string) (bool, error)
+}
+
+var _ repository.TokenRepository = (*TokenRepository)(nil)
+
+// CreateRefreshToken executes OnCreateRefreshToken.
+func (mTknRepo TokenRepository) CreateRefreshToken(ctx context.Context, refreshToken *model.RefreshToken) error {
+	return mTknRepo.OnCreateRefreshToken(ctx, refreshToken)
+}
+
+// GetRefreshToken executes OnGetRefreshToken.
+func (mTknRepo TokenRepository) GetRefreshToken(ctx context.Context, token string) (*model.RefreshToken, error) {
+	return mTknRepo.OnGetRefreshToken(ctx, token)
+}
+
+// UseRefreshToken executes OnUseRefreshToken.
+func (mTknRepo TokenRepository) UseRefreshToken(ctx context.Context, refreshToken *model.RefreshToken) (bool, error) {
+	return mTknRepo.OnUseRefreshToken(ctx, refreshToken)
+}
+
+// RevokeRefreshTokenFamily executes OnRevokeRefreshTokenFamily.
+func (mTknRepo TokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID model.RefreshTokenFamilyID, duration time.Duration) error {
+	return mTknRepo.OnRevokeRefreshTokenFamily(ctx, familyID, duration)
+}
+
+// IsRefreshTokenFamilyRevoked executes OnIsRefreshTokenFamilyRevoked.
+func (mTknRepo TokenRepository) IsRefreshTokenFamilyRevoked(ctx context.Context, familyID model.RefreshTokenFamilyID) (bool, error) {
+	return mTknRepo.OnIsRefreshTokenFamilyRevoked(ctx, familyID)
+}
+
+// DenyAccessToken executes OnDenyAccessToken.
+func (mTknRepo TokenRepository) DenyAccessToken(ctx context.Context, tokenID string, duration time.Duration) error {
+	return mTknRepo.OnDenyAccessToken(ctx, tokenID, duration)
+}
+
+// IsAccessTokenDenied executes OnIsAccessTokenDenied.
+func (mTknRepo TokenRepository) IsAccessTokenDenied(ctx context.Context, tokenID string) (bool, error) {
+	return mTknRepo.OnIsAccessTokenDenied(ctx, tokenID)
+}
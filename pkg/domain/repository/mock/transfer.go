@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"time"
 
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
@@ -9,9 +10,13 @@ import (
 
 // TransferRepository mocks an TransferRepository.
 type TransferRepository struct {
-	OnCreate            func(ctx context.Context, transfer *model.Transfer) error
-	OnFetch             func(ctx context.Context, accountID model.AccountID) ([]model.Transfer, error)
-	OnWithinTransaction func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
+	OnCreate               func(ctx context.Context, transfer *model.Transfer) error
+	OnFetch                func(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error)
+	OnGetByIDForUpdate     func(ctx context.Context, id model.TransferID) (*model.Transfer, error)
+	OnUpdateRefundedAmount func(ctx context.Context, transfer *model.Transfer) error
+	OnGetSentTotals        func(ctx context.Context, accountID model.AccountID, periods model.TransferLimitPeriods) (*model.TransferTotals, error)
+	OnCountSent            func(ctx context.Context, accountID model.AccountID, since time.Time) (int, error)
+	OnWithinTransaction    func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
 }
 
 var _ repository.TransferRepository = (*TransferRepository)(nil)
@@ -22,8 +27,28 @@ func (mTrfRepo TransferRepository) Create(ctx context.Context, transfer *model.T
 }
 
 // Fetch executes OnFetch.
-func (mTrfRepo TransferRepository) Fetch(ctx context.Context, accountID model.AccountID) ([]model.Transfer, error) {
-	return mTrfRepo.OnFetch(ctx, accountID)
+func (mTrfRepo TransferRepository) Fetch(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error) {
+	return mTrfRepo.OnFetch(ctx, filter)
+}
+
+// GetByIDForUpdate executes OnGetByIDForUpdate.
+func (mTrfRepo TransferRepository) GetByIDForUpdate(ctx context.Context, id model.TransferID) (*model.Transfer, error) {
+	return mTrfRepo.OnGetByIDForUpdate(ctx, id)
+}
+
+// UpdateRefundedAmount executes OnUpdateRefundedAmount.
+func (mTrfRepo TransferRepository) UpdateRefundedAmount(ctx context.Context, transfer *model.Transfer) error {
+	return mTrfRepo.OnUpdateRefundedAmount(ctx, transfer)
+}
+
+// GetSentTotals executes OnGetSentTotals.
+func (mTrfRepo TransferRepository) GetSentTotals(ctx context.Context, accountID model.AccountID, periods model.TransferLimitPeriods) (*model.TransferTotals, error) {
+	return mTrfRepo.OnGetSentTotals(ctx, accountID, periods)
+}
+
+// CountSent executes OnCountSent.
+func (mTrfRepo TransferRepository) CountSent(ctx context.Context, accountID model.AccountID, since time.Time) (int, error) {
+	return mTrfRepo.OnCountSent(ctx, accountID, since)
 }
 
 // WithinTransaction executes OnWithinTransaction.
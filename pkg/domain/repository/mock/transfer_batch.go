@@ -0,0 +1,50 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// TransferBatchRepository mocks a TransferBatchRepository.
+type TransferBatchRepository struct {
+	OnCreate             func(ctx context.Context, batch *model.TransferBatch) error
+	OnSaveItem           func(ctx context.Context, batch *model.TransferBatch, position int) error
+	OnUpdateStatus       func(ctx context.Context, batch *model.TransferBatch) error
+	OnGetByID            func(ctx context.Context, id model.TransferBatchID) (*model.TransferBatch, error)
+	OnFetchExecutedItems func(ctx context.Context, originID model.AccountID, itemIDs []string) ([]model.TransferBatchItem, error)
+	OnWithinTransaction  func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error)
+}
+
+var _ repository.TransferBatchRepository = (*TransferBatchRepository)(nil)
+
+// Create executes OnCreate.
+func (mBatchRepo TransferBatchRepository) Create(ctx context.Context, batch *model.TransferBatch) error {
+	return mBatchRepo.OnCreate(ctx, batch)
+}
+
+// SaveItem executes OnSaveItem.
+func (mBatchRepo TransferBatchRepository) SaveItem(ctx context.Context, batch *model.TransferBatch, position int) error {
+	return mBatchRepo.OnSaveItem(ctx, batch, position)
+}
+
+// UpdateStatus executes OnUpdateStatus.
+func (mBatchRepo TransferBatchRepository) UpdateStatus(ctx context.Context, batch *model.TransferBatch) error {
+	return mBatchRepo.OnUpdateStatus(ctx, batch)
+}
+
+// GetByID executes OnGetByID.
+func (mBatchRepo TransferBatchRepository) GetByID(ctx context.Context, id model.TransferBatchID) (*model.TransferBatch, error) {
+	return mBatchRepo.OnGetByID(ctx, id)
+}
+
+// FetchExecutedItems executes OnFetchExecutedItems.
+func (mBatchRepo TransferBatchRepository) FetchExecutedItems(ctx context.Context, originID model.AccountID, itemIDs []string) ([]model.TransferBatchItem, error) {
+	return mBatchRepo.OnFetchExecutedItems(ctx, originID, itemIDs)
+}
+
+// WithinTransaction executes OnWithinTransaction.
+func (mBatchRepo TransferBatchRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return mBatchRepo.OnWithinTransaction(ctx, txFunc)
+}
@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// TransferLimitRepository mocks a TransferLimitRepository.
+type TransferLimitRepository struct {
+	OnGet  func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error)
+	OnSave func(ctx context.Context, limits *model.AccountTransferLimits) error
+}
+
+var _ repository.TransferLimitRepository = (*TransferLimitRepository)(nil)
+
+// Get executes OnGet.
+func (mLimitRepo TransferLimitRepository) Get(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+	return mLimitRepo.OnGet(ctx, accountID)
+}
+
+// Save executes OnSave.
+func (mLimitRepo TransferLimitRepository) Save(ctx context.Context, limits *model.AccountTransferLimits) error {
+	return mLimitRepo.OnSave(ctx, limits)
+}
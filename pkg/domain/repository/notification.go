@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+// NotificationRepository is the interface that wraps notification datasource methods.
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *model.Notification) error
+	// Fetch returns up to limit notifications of the account, the newest first.
+	Fetch(ctx context.Context, accountID model.AccountID, limit int) ([]model.Notification, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+// OverdraftRepository is the interface that wraps the overdraft interest datasource methods.
+type OverdraftRepository interface {
+	Transaction
+	// LockNextOverdrawn returns the next account of the filter with a negative ledger balance at its DayEnd and no
+	// interest charged for its Day, or nil if there's none, and locks its row until the current transaction ends.
+	// The rows locked by other transactions are skipped, so concurrent callers never get the same account.
+	// The account has only its ID, balance, credit limit and status, the balance being the current one.
+	LockNextOverdrawn(ctx context.Context, filter model.OverdraftInterestFilter) (*model.Account, error)
+	// CreateInterestCharge saves the interest charged on the account for the day.
+	CreateInterestCharge(ctx context.Context, charge *model.OverdraftInterestCharge) error
+}
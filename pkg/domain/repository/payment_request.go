@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+var (
+	// ErrPaymentRequestNotFound happens when the payment request was not found based on search params.
+	ErrPaymentRequestNotFound = errors.New("payment request not found")
+)
+
+// PaymentRequestRepository is the interface that wraps payment request datasource methods.
+type PaymentRequestRepository interface {
+	Transaction
+	Create(ctx context.Context, request *model.PaymentRequest) error
+	// FetchByPayer returns the requests aimed at the payer account, the newest first.
+	// An empty status returns them all.
+	FetchByPayer(ctx context.Context, payerID model.AccountID, status model.PaymentRequestStatus) ([]model.PaymentRequest, error)
+	// FetchByRequester returns the requests of the requester account, the newest first.
+	// An empty status returns them all.
+	FetchByRequester(ctx context.Context, requesterID model.AccountID, status model.PaymentRequestStatus) ([]model.PaymentRequest, error)
+	GetByID(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error)
+	// GetByIDForUpdate returns the request and locks its row until the current transaction ends.
+	GetByIDForUpdate(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error)
+	// UpdateStatus saves the request status and its outcome.
+	UpdateStatus(ctx context.Context, request *model.PaymentRequest) error
+	// ExpireDue closes as expired up to limit pending requests whose expiration is not after now, returning how many
+	// were closed. The rows locked by other transactions, like a request being paid, are skipped.
+	ExpireDue(ctx context.Context, now time.Time, limit int) (int, error)
+}
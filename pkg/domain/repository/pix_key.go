@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+var (
+	// ErrPixKeyNotFound happens when the pix key was not found based on search params.
+	ErrPixKeyNotFound = errors.New("pix key not found")
+	// ErrPixKeyAlreadyExists happens when the key value is already registered to an active key of any account, or to
+	// any key of the same account.
+	ErrPixKeyAlreadyExists = errors.New("pix key is already registered")
+	// ErrPixKeyLimitReached happens when the account already has model.MaxPixKeysPerAccount keys.
+	ErrPixKeyLimitReached = errors.New("account reached the maximum number of pix keys")
+)
+
+// PixKeyRepository is the interface that wraps pix key datasource methods.
+type PixKeyRepository interface {
+	Transaction
+	// Create saves the key, returning ErrPixKeyAlreadyExists if its value is taken or ErrPixKeyLimitReached if the
+	// account has no room for it.
+	// Only the active keys take the value: the pending ones are claims, several accounts can have one for the same
+	// value. The keys of closed accounts and the expired pending keys don't take the value, they are replaced.
+	Create(ctx context.Context, key *model.PixKey) error
+	// Fetch returns the keys of the account, the oldest first.
+	Fetch(ctx context.Context, accountID model.AccountID) ([]model.PixKey, error)
+	// GetByValue returns the active key with the normalized value.
+	GetByValue(ctx context.Context, value string) (*model.PixKey, error)
+	// GetByAccountValue returns the key of the account with the normalized value, whatever its status.
+	GetByAccountValue(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error)
+	// GetByAccountValueForUpdate works like GetByAccountValue, but locks the key row until the current transaction ends.
+	GetByAccountValueForUpdate(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error)
+	// Update saves the key status and verification. Activating the key returns ErrPixKeyAlreadyExists if another
+	// account's key with the value was activated first, or drops the pending keys of the other accounts with it.
+	Update(ctx context.Context, key *model.PixKey) error
+	// Delete removes the key.
+	Delete(ctx context.Context, id model.PixKeyID) error
+}
+
+// PixKeyVerificationSender is the interface that wraps the delivery of the pix key verification codes.
+type PixKeyVerificationSender interface {
+	// SendVerificationCode sends the code to the key, like an email to its address, so the holder proves they own it.
+	SendVerificationCode(ctx context.Context, key *model.PixKey, code string) error
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+// SavingsRepository is the interface that wraps the savings interest datasource methods.
+type SavingsRepository interface {
+	Transaction
+	// LockNextAccrualDue returns the next account matching the filter and not accrued the interest of the day yet,
+	// or nil if there's none, and locks its row until the current transaction ends. The rows locked by other
+	// transactions are skipped, so concurrent callers never get the same account.
+	// The account has only its ID, currency, balance, credit limit, tier and status.
+	LockNextAccrualDue(ctx context.Context, filter model.SavingsAccrualFilter) (*model.Account, error)
+	// CreateAccrual saves the interest accrued on the account for the day.
+	CreateAccrual(ctx context.Context, accrual *model.SavingsInterestAccrual) error
+	// LockNextPaymentDue returns the next account matching the filter and not paid the interest of the month yet,
+	// or nil if there's none, and locks its row until the current transaction ends, like LockNextAccrualDue.
+	LockNextPaymentDue(ctx context.Context, filter model.SavingsPaymentFilter) (*model.Account, error)
+	// GetTotals returns the interest accrued on the account on the days before end and all the interest paid to it.
+	GetTotals(ctx context.Context, accountID model.AccountID, end time.Time) (*model.SavingsInterestTotals, error)
+	// CreatePayment saves the interest paid to the account for the month.
+	CreatePayment(ctx context.Context, payment *model.SavingsInterestPayment) error
+	// FetchAccruals returns the interest accrued on the account on the days in [from, to), oldest first.
+	FetchAccruals(ctx context.Context, accountID model.AccountID, from, to time.Time) ([]model.SavingsInterestAccrual, error)
+	// FetchPayments returns the interest paid to the account for the months starting in [from, to), oldest first.
+	FetchPayments(ctx context.Context, accountID model.AccountID, from, to time.Time) ([]model.SavingsInterestPayment, error)
+}
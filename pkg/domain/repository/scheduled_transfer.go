@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+var (
+	// ErrScheduledTransferNotFound happens when the scheduled transfer was not found based on search params.
+	ErrScheduledTransferNotFound = errors.New("scheduled transfer not found")
+)
+
+// ScheduledTransferRepository is the interface that wraps scheduled transfer datasource methods.
+type ScheduledTransferRepository interface {
+	Transaction
+	Create(ctx context.Context, schedule *model.ScheduledTransfer) error
+	// Fetch returns the schedules of the origin account, the next ones first.
+	// An empty status returns them all.
+	Fetch(ctx context.Context, originID model.AccountID, status model.ScheduledTransferStatus) ([]model.ScheduledTransfer, error)
+	// GetByIDForUpdate returns the schedule and locks its row until the current transaction ends.
+	GetByIDForUpdate(ctx context.Context, id model.ScheduledTransferID) (*model.ScheduledTransfer, error)
+	// LockNextDue returns the pending schedule due the longest, or nil if there's none, and locks its row until
+	// the current transaction ends. The rows locked by other transactions are skipped, so concurrent callers
+	// never get the same schedule, and so are the schedules of the skip IDs.
+	LockNextDue(ctx context.Context, now time.Time, skip []model.ScheduledTransferID) (*model.ScheduledTransfer, error)
+	// UpdateStatus saves the schedule status and its outcome.
+	UpdateStatus(ctx context.Context, schedule *model.ScheduledTransfer) error
+}
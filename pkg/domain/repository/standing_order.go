@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+var (
+	// ErrStandingOrderNotFound happens when the standing order was not found based on search params.
+	ErrStandingOrderNotFound = errors.New("standing order not found")
+)
+
+// StandingOrderRepository is the interface that wraps standing order datasource methods.
+type StandingOrderRepository interface {
+	Transaction
+	Create(ctx context.Context, order *model.StandingOrder) error
+	// Fetch returns the standing orders of the origin account, the newest first.
+	Fetch(ctx context.Context, originID model.AccountID) ([]model.StandingOrder, error)
+	// GetByIDForUpdate returns the standing order and locks its row until the current transaction ends.
+	GetByIDForUpdate(ctx context.Context, id model.StandingOrderID) (*model.StandingOrder, error)
+	// LockNextDue returns the active standing order whose next run is due the longest, or nil if there's none,
+	// and locks its row until the current transaction ends. The rows locked by other transactions are skipped,
+	// so concurrent callers never get the same standing order.
+	LockNextDue(ctx context.Context, now time.Time) (*model.StandingOrder, error)
+	// Update saves all the mutable fields of the standing order.
+	Update(ctx context.Context, order *model.StandingOrder) error
+}
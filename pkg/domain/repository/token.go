@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+var (
+	// ErrRefreshTokenNotFound happens when the refresh token was not found or has already expired.
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+)
+
+// TokenRepository is the interface that wraps auth token datasource methods.
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, refreshToken *model.RefreshToken) error
+	GetRefreshToken(ctx context.Context, token string) (*model.RefreshToken, error)
+	// UseRefreshToken atomically marks the refresh token as used.
+	// It returns false if the token had already been used before.
+	UseRefreshToken(ctx context.Context, refreshToken *model.RefreshToken) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID model.RefreshTokenFamilyID, duration time.Duration) error
+	IsRefreshTokenFamilyRevoked(ctx context.Context, familyID model.RefreshTokenFamilyID) (bool, error)
+	DenyAccessToken(ctx context.Context, tokenID string, duration time.Duration) error
+	IsAccessTokenDenied(ctx context.Context, tokenID string) (bool, error)
+}
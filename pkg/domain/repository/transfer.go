@@ -2,13 +2,30 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 )
 
+var (
+	// ErrTransferNotFound happens when the transfer was not found based on search params.
+	ErrTransferNotFound = errors.New("transfer not found")
+)
+
 // TransferRepository is the interface that wraps transfer datasource methods.
 type TransferRepository interface {
 	Transaction
 	Create(ctx context.Context, transfer *model.Transfer) error
-	Fetch(ctx context.Context, accountID model.AccountID) ([]model.Transfer, error)
+	// Fetch returns up to filter.Limit transfers of the account matching the filter, newest first.
+	Fetch(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error)
+	// GetByIDForUpdate returns the transfer and locks its row until the current transaction ends.
+	GetByIDForUpdate(ctx context.Context, id model.TransferID) (*model.Transfer, error)
+	// UpdateRefundedAmount saves the sum of the refunds and reversals of the transfer.
+	UpdateRefundedAmount(ctx context.Context, transfer *model.Transfer) error
+	// GetSentTotals sums the transfers of kind model.TransferKindTransfer sent by the account and its cash withdrawals
+	// in each of the periods.
+	GetSentTotals(ctx context.Context, accountID model.AccountID, periods model.TransferLimitPeriods) (*model.TransferTotals, error)
+	// CountSent counts the transfers of kind model.TransferKindTransfer sent by the account since the given time.
+	CountSent(ctx context.Context, accountID model.AccountID, since time.Time) (int, error)
 }
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+var (
+	// ErrTransferBatchNotFound happens when the transfer batch was not found based on search params.
+	ErrTransferBatchNotFound = errors.New("transfer batch not found")
+	// ErrTransferBatchItemExecuted happens when saving an executed item whose ID was already executed by the origin account.
+	ErrTransferBatchItemExecuted = errors.New("transfer batch item already executed")
+)
+
+// TransferBatchRepository is the interface that wraps transfer batch datasource methods.
+type TransferBatchRepository interface {
+	Transaction
+	// Create saves the batch without its items.
+	Create(ctx context.Context, batch *model.TransferBatch) error
+	// SaveItem saves the outcome of the item at the position of the batch.
+	// It returns ErrTransferBatchItemExecuted when the item is executed and its ID was already executed by another
+	// batch of the origin account.
+	SaveItem(ctx context.Context, batch *model.TransferBatch, position int) error
+	// UpdateStatus saves the batch status.
+	UpdateStatus(ctx context.Context, batch *model.TransferBatch) error
+	// GetByID returns the batch with its items, in the requested order.
+	GetByID(ctx context.Context, id model.TransferBatchID) (*model.TransferBatch, error)
+	// FetchExecutedItems returns the items with one of the IDs executed by any batch of the origin account.
+	FetchExecutedItems(ctx context.Context, originID model.AccountID, itemIDs []string) ([]model.TransferBatchItem, error)
+}
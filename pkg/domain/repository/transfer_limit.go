@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+// TransferLimitRepository is the interface that wraps the account transfer limits datasource methods.
+type TransferLimitRepository interface {
+	// Get returns the limits set for the account. The limits are all nil when none was set.
+	Get(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error)
+	// Save creates or replaces the limits set for the account.
+	Save(ctx context.Context, limits *model.AccountTransferLimits) error
+}
@@ -10,17 +10,25 @@ import (
 // AccountUseCase is the interface that wraps all business logic methods related to the accounts.
 type AccountUseCase interface {
 	Create(ctx context.Context, accountInput AccountCreateInput) (*AccountCreateOutput, error)
-	Fetch(ctx context.Context) ([]AccountFetchOutput, error)
-	GetBalance(ctx context.Context, id model.AccountID) (*AccountBalanceOutput, error)
+	Fetch(ctx context.Context, caller model.Principal, fetchInput AccountFetchInput) (*AccountFetchPageOutput, error)
+	GetBalance(ctx context.Context, caller model.Principal, id model.AccountID) (*AccountBalanceOutput, error)
+	GetStatement(ctx context.Context, caller model.Principal, statementInput AccountStatementInput) (*AccountStatementOutput, error)
+	Block(ctx context.Context, caller model.Principal, statusInput AccountStatusInput) (*AccountStatusOutput, error)
+	Unblock(ctx context.Context, caller model.Principal, statusInput AccountStatusInput) (*AccountStatusOutput, error)
+	Close(ctx context.Context, caller model.Principal, closeInput AccountCloseInput) (*AccountStatusOutput, error)
+	SetCreditLimit(ctx context.Context, caller model.Principal, creditLimitInput AccountCreditLimitInput) (*AccountBalanceOutput, error)
+	SetTier(ctx context.Context, caller model.Principal, tierInput AccountTierInput) (*AccountTierOutput, error)
 }
 
 type accountUseCase struct {
-	accRepo repository.AccountRepository
+	accRepo    repository.AccountRepository
+	ledgerRepo repository.LedgerRepository
 }
 
 // NewAccountUseCase instantiates a new AccountUseCase.
-func NewAccountUseCase(accRepo repository.AccountRepository) AccountUseCase {
+func NewAccountUseCase(accRepo repository.AccountRepository, ledgerRepo repository.LedgerRepository) AccountUseCase {
 	return &accountUseCase{
-		accRepo: accRepo,
+		accRepo:    accRepo,
+		ledgerRepo: ledgerRepo,
 	}
 }
@@ -21,6 +21,10 @@ var (
 	ErrAccountBalanceNegative = errors.New("'balance' must be greater than or equal to zero")
 	// ErrAccountCPFInvalid happens when the Account CPF is not valid.
 	ErrAccountCPFInvalid = errors.New("'cpf' is invalid")
+	// ErrAccountCurrencyInvalid happens when the Account Currency is not a supported ISO 4217 code.
+	ErrAccountCurrencyInvalid = errors.New("'currency' must be one of BRL, EUR and USD")
+	// ErrAccountRoleInvalid happens when the Account Roles has an unknown role.
+	ErrAccountRoleInvalid = errors.New("'roles' has an invalid role")
 	// ErrAccountCPFAlreadyExists happens when one tries to create an account with a CPF that is already in use by another account.
 	ErrAccountCPFAlreadyExists = errors.New("an account with this CPF already exists")
 	// ErrAccountCreate happens when an error occurred and the account was not created.
@@ -28,11 +32,15 @@ var (
 )
 
 // AccountCreateInput represents the expected input data when creating an account.
+// The account is in BRL when the currency is not informed.
 type AccountCreateInput struct {
-	Name    string  `json:"name" example:"Bart Simpson"`
-	CPF     string  `json:"cpf" example:"999.999.999-99"`
-	Secret  string  `json:"secret" example:"S3cr3t"`
-	Balance float64 `json:"balance" example:"9999.99" default:"0"`
+	Name     string `json:"name" example:"Bart Simpson"`
+	CPF      string `json:"cpf" example:"999.999.999-99"`
+	Secret   string `json:"secret" example:"S3cr3t"`
+	Currency string `json:"currency,omitempty" example:"BRL" enums:"BRL,EUR,JPY,KWD,USD" default:"BRL"`
+	Balance  Amount `json:"balance" swaggertype:"number" example:"9999.99" default:"0"`
+	// Roles can't be set through the API, only when creating operators.
+	Roles []model.Role `json:"-"`
 }
 
 // Validate validates the AccountCreateInput fields.
@@ -50,10 +58,27 @@ func (input *AccountCreateInput) Validate() error {
 		return ErrAccountSecretWrongLength
 	}
 
-	if input.Balance < 0 {
+	input.Currency = strings.ToUpper(strings.TrimSpace(input.Currency))
+	if input.Currency == "" {
+		input.Currency = string(model.DefaultCurrency)
+	}
+	if !model.Currency(input.Currency).IsValid() {
+		return ErrAccountCurrencyInvalid
+	}
+
+	if _, err := input.Balance.In(model.Currency(input.Currency)); err != nil {
+		return err
+	}
+	if input.Balance.Money < 0 {
 		return ErrAccountBalanceNegative
 	}
 
+	for _, role := range input.Roles {
+		if !role.IsValid() {
+			return ErrAccountRoleInvalid
+		}
+	}
+
 	return nil
 }
 
@@ -62,7 +87,8 @@ type AccountCreateOutput struct {
 	ID        string    `json:"id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
 	Name      string    `json:"name" example:"Bart Simpson"`
 	CPF       string    `json:"cpf" example:"999.999.999-99"`
-	Balance   float64   `json:"balance" example:"9999.99"`
+	Currency  string    `json:"currency" example:"BRL"`
+	Balance   Amount    `json:"balance" swaggertype:"number" example:"9999.99"`
 	CreatedAt time.Time `json:"created_at" example:"2020-12-31T23:59:59.999999-03:00"`
 }
 
@@ -71,7 +97,8 @@ func newAccountCreateOutput(account *model.Account) *AccountCreateOutput {
 		ID:        string(account.ID),
 		Name:      account.Name,
 		CPF:       account.CPF.String(),
-		Balance:   account.Balance.Float64(),
+		Currency:  string(account.Currency),
+		Balance:   NewAmountIn(account.Balance, account.Currency),
 		CreatedAt: account.CreatedAt,
 	}
 }
@@ -88,7 +115,9 @@ func (accUC accountUseCase) Create(ctx context.Context, accountInput AccountCrea
 		return nil, err
 	}
 
-	account := model.NewAccount(accountInput.Name, accountInput.CPF, accountInput.Secret, accountInput.Balance)
+	account := model.NewAccount(accountInput.Name, accountInput.CPF, accountInput.Secret, accountInput.Balance.Money)
+	account.Currency = model.Currency(accountInput.Currency)
+	account.Roles = accountInput.Roles
 
 	err = account.HashSecret()
 	if err != nil {
@@ -105,7 +134,9 @@ func (accUC accountUseCase) Create(ctx context.Context, accountInput AccountCrea
 		return nil, ErrAccountCPFAlreadyExists
 	}
 
-	err = accUC.accRepo.Create(ctx, account)
+	_, err = accUC.ledgerRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		return nil, accUC.createWithInitialBalance(txCtx, account)
+	})
 	if err != nil {
 		log.Ctx(ctx).Error().Stack().Err(err).Interface("account", account).Msg("error persisting new account")
 		return nil, ErrAccountCreate
@@ -113,3 +144,33 @@ func (accUC accountUseCase) Create(ctx context.Context, accountInput AccountCrea
 
 	return newAccountCreateOutput(account), nil
 }
+
+// createWithInitialBalance persists the account with zero balance and then posts its initial balance to the ledger,
+// so the balance can be rebuilt from the ledger entries.
+func (accUC accountUseCase) createWithInitialBalance(ctx context.Context, account *model.Account) error {
+	initialBalance := account.Balance
+	account.Balance = 0
+
+	err := accUC.accRepo.Create(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	if initialBalance > 0 {
+		posting := model.NewLedgerPosting(
+			model.LedgerPostingInitialBalance,
+			string(account.ID),
+			model.LedgerExternalAccountID,
+			account.ID,
+			initialBalance,
+			account.Currency)
+
+		err = accUC.ledgerRepo.Post(ctx, posting)
+		if err != nil {
+			return err
+		}
+	}
+
+	account.Balance = initialBalance
+	return nil
+}
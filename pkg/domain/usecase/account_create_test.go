@@ -17,10 +17,12 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 	t.Parallel()
 
 	type fields struct {
-		Name    string
-		CPF     string
-		Secret  string
-		Balance float64
+		Name     string
+		CPF      string
+		Secret   string
+		Currency string
+		Balance  Amount
+		Roles    []model.Role
 	}
 	tests := []struct {
 		name    string
@@ -33,7 +35,7 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 				Name:    "",
 				CPF:     "",
 				Secret:  "",
-				Balance: 0,
+				Balance: NewAmount(0),
 			},
 			wantErr: ErrAccountNameWrongLength,
 		},
@@ -43,7 +45,7 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 				Name:    "A",
 				CPF:     "",
 				Secret:  "",
-				Balance: 0,
+				Balance: NewAmount(0),
 			},
 			wantErr: ErrAccountNameWrongLength,
 		},
@@ -53,7 +55,7 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 				Name:    strings.Repeat("A", 101),
 				CPF:     "",
 				Secret:  "",
-				Balance: 0,
+				Balance: NewAmount(0),
 			},
 			wantErr: ErrAccountNameWrongLength,
 		},
@@ -63,7 +65,7 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 				Name:    "Jon Snow",
 				CPF:     "",
 				Secret:  "",
-				Balance: 0,
+				Balance: NewAmount(0),
 			},
 			wantErr: ErrAccountCPFInvalid,
 		},
@@ -73,7 +75,7 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 				Name:    "Jon Snow",
 				CPF:     "1234567890",
 				Secret:  "",
-				Balance: 0,
+				Balance: NewAmount(0),
 			},
 			wantErr: ErrAccountCPFInvalid,
 		},
@@ -83,7 +85,7 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 				Name:    "Jon Snow",
 				CPF:     "123456789012",
 				Secret:  "",
-				Balance: 0,
+				Balance: NewAmount(0),
 			},
 			wantErr: ErrAccountCPFInvalid,
 		},
@@ -93,7 +95,7 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 				Name:    "Jon Snow",
 				CPF:     "12345678901",
 				Secret:  "",
-				Balance: 0,
+				Balance: NewAmount(0),
 			},
 			wantErr: ErrAccountCPFInvalid,
 		},
@@ -103,7 +105,7 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 				Name:    "Jon Snow",
 				CPF:     "599.513.320-99",
 				Secret:  "",
-				Balance: 0,
+				Balance: NewAmount(0),
 			},
 			wantErr: ErrAccountSecretWrongLength,
 		},
@@ -113,7 +115,7 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 				Name:    "Jon Snow",
 				CPF:     "599.513.320-99",
 				Secret:  "12345",
-				Balance: 0,
+				Balance: NewAmount(0),
 			},
 			wantErr: ErrAccountSecretWrongLength,
 		},
@@ -123,7 +125,7 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 				Name:    "Jon Snow",
 				CPF:     "599.513.320-99",
 				Secret:  strings.Repeat("A", 101),
-				Balance: 0,
+				Balance: NewAmount(0),
 			},
 			wantErr: ErrAccountSecretWrongLength,
 		},
@@ -133,17 +135,57 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 				Name:    "Jon Snow",
 				CPF:     "599.513.320-99",
 				Secret:  "IAmNotSnow",
-				Balance: -1,
+				Balance: NewAmount(-100),
 			},
 			wantErr: ErrAccountBalanceNegative,
 		},
+		{
+			name: "unknown currency should return error",
+			fields: fields{
+				Name:     "Jon Snow",
+				CPF:      "599.513.320-99",
+				Secret:   "IAmNotSnow",
+				Currency: "GBP",
+			},
+			wantErr: ErrAccountCurrencyInvalid,
+		},
+		{
+			name: "lowercase currency should success",
+			fields: fields{
+				Name:     "Jon Snow",
+				CPF:      "599.513.320-99",
+				Secret:   "IAmNotSnow",
+				Currency: " usd ",
+			},
+			wantErr: nil,
+		},
 		{
 			name: "positive decimal balance should success",
 			fields: fields{
 				Name:    "Jon Snow",
 				CPF:     "599.513.320-99",
 				Secret:  "IAmNotSnow",
-				Balance: 0.1,
+				Balance: NewAmount(10),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "unknown role should return error",
+			fields: fields{
+				Name:   "Seymour Skinner",
+				CPF:    "599.513.320-99",
+				Secret: "Ag3ntSk1nn3r",
+				Roles:  []model.Role{model.RoleOperator, "root"},
+			},
+			wantErr: ErrAccountRoleInvalid,
+		},
+		{
+			name: "known roles should success",
+			fields: fields{
+				Name:   "Seymour Skinner",
+				CPF:    "599.513.320-99",
+				Secret: "Ag3ntSk1nn3r",
+				Roles:  []model.Role{model.RoleOperator, model.RoleAdmin},
 			},
 			wantErr: nil,
 		},
@@ -151,10 +193,12 @@ func TestAccountCreateInput_Validate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			input := AccountCreateInput{
-				Name:    tt.fields.Name,
-				CPF:     tt.fields.CPF,
-				Secret:  tt.fields.Secret,
-				Balance: tt.fields.Balance,
+				Name:     tt.fields.Name,
+				CPF:      tt.fields.CPF,
+				Secret:   tt.fields.Secret,
+				Currency: tt.fields.Currency,
+				Balance:  tt.fields.Balance,
+				Roles:    tt.fields.Roles,
 			}
 			err := input.Validate()
 			if !reflect.DeepEqual(err, tt.wantErr) {
@@ -170,12 +214,22 @@ func Test_accountUseCase_Create(t *testing.T) {
 	backgroundCtx := context.Background()
 
 	type fields struct {
-		accRepo repository.AccountRepository
+		accRepo    repository.AccountRepository
+		ledgerRepo repository.LedgerRepository
 	}
 	type args struct {
 		ctx          context.Context
 		accountInput AccountCreateInput
 	}
+
+	ledgerRepo := mock.LedgerRepository{
+		OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+			return txFunc(ctx)
+		},
+		OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+			return nil
+		},
+	}
 	tests := []struct {
 		name    string
 		fields  fields
@@ -186,6 +240,7 @@ func Test_accountUseCase_Create(t *testing.T) {
 		{
 			name: "repo create error should return error",
 			fields: fields{
+				ledgerRepo: ledgerRepo,
 				accRepo: mock.AccountRepository{
 					OnExistsByCPF: func(ctx context.Context, cpf model.CPF) (bool, error) {
 						return false, nil
@@ -201,7 +256,7 @@ func Test_accountUseCase_Create(t *testing.T) {
 					Name:    "Jon Snow",
 					CPF:     "599.513.320-99",
 					Secret:  "IAmNotSnow",
-					Balance: 0,
+					Balance: NewAmount(0),
 				},
 			},
 			want:    nil,
@@ -210,7 +265,8 @@ func Test_accountUseCase_Create(t *testing.T) {
 		{
 			name: "input name empty should return error",
 			fields: fields{
-				accRepo: mock.AccountRepository{},
+				ledgerRepo: ledgerRepo,
+				accRepo:    mock.AccountRepository{},
 			},
 			args: args{
 				ctx: backgroundCtx,
@@ -218,7 +274,7 @@ func Test_accountUseCase_Create(t *testing.T) {
 					Name:    "",
 					CPF:     "599.513.320-99",
 					Secret:  "IAmNotSnow",
-					Balance: 0,
+					Balance: NewAmount(0),
 				},
 			},
 			want:    nil,
@@ -227,6 +283,7 @@ func Test_accountUseCase_Create(t *testing.T) {
 		{
 			name: "nonformatted CPF should return formatted",
 			fields: fields{
+				ledgerRepo: ledgerRepo,
 				accRepo: mock.AccountRepository{
 					OnExistsByCPF: func(ctx context.Context, cpf model.CPF) (bool, error) {
 						return false, nil
@@ -242,19 +299,98 @@ func Test_accountUseCase_Create(t *testing.T) {
 					Name:    "Jon Snow",
 					CPF:     "59951332099",
 					Secret:  "IAmNotSnow",
-					Balance: 0,
+					Balance: NewAmount(0),
 				},
 			},
 			want: &AccountCreateOutput{
-				Name:    "Jon Snow",
-				CPF:     "599.513.320-99",
-				Balance: 0,
+				Name:     "Jon Snow",
+				CPF:      "599.513.320-99",
+				Currency: "BRL",
+				Balance:  NewAmountIn(0, model.CurrencyBRL),
 			},
 			wantErr: false,
 		},
+		{
+			name: "positive balance should be posted to the ledger",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnExistsByCPF: func(ctx context.Context, cpf model.CPF) (bool, error) {
+						return false, nil
+					},
+					OnCreate: func(ctx context.Context, account *model.Account) error {
+						if account.Balance != 0 {
+							return errors.New("account should be persisted with zero balance")
+						}
+						return nil
+					},
+				},
+				ledgerRepo: mock.LedgerRepository{
+					OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+						return txFunc(ctx)
+					},
+					OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+						if posting.Kind != model.LedgerPostingInitialBalance ||
+							posting.DebitAccountID != model.LedgerExternalAccountID ||
+							posting.Amount != 1050 {
+							return errors.New("unexpected posting")
+						}
+						return nil
+					},
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				accountInput: AccountCreateInput{
+					Name:    "Jon Snow",
+					CPF:     "59951332099",
+					Secret:  "IAmNotSnow",
+					Balance: NewAmount(1050),
+				},
+			},
+			want: &AccountCreateOutput{
+				Name:     "Jon Snow",
+				CPF:      "599.513.320-99",
+				Currency: "BRL",
+				Balance:  NewAmountIn(1050, model.CurrencyBRL),
+			},
+			wantErr: false,
+		},
+		{
+			name: "ledger post error should return error",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnExistsByCPF: func(ctx context.Context, cpf model.CPF) (bool, error) {
+						return false, nil
+					},
+					OnCreate: func(ctx context.Context, account *model.Account) error {
+						return nil
+					},
+				},
+				ledgerRepo: mock.LedgerRepository{
+					OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+						return txFunc(ctx)
+					},
+					OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+						return errors.New("any database error")
+					},
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				accountInput: AccountCreateInput{
+					Name:    "Jon Snow",
+					CPF:     "59951332099",
+					Secret:  "IAmNotSnow",
+					Balance: NewAmount(1050),
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "repo existsByCPF error should return error",
 			fields: fields{
+				ledgerRepo: ledgerRepo,
 				accRepo: mock.AccountRepository{
 					OnExistsByCPF: func(ctx context.Context, cpf model.CPF) (bool, error) {
 						return false, errors.New("any database error")
@@ -267,7 +403,7 @@ func Test_accountUseCase_Create(t *testing.T) {
 					Name:    "Jon Snow",
 					CPF:     "599.513.320-99",
 					Secret:  "IAmNotSnow",
-					Balance: 0,
+					Balance: NewAmount(0),
 				},
 			},
 			want:    nil,
@@ -276,6 +412,7 @@ func Test_accountUseCase_Create(t *testing.T) {
 		{
 			name: "existsByCPF true should return error",
 			fields: fields{
+				ledgerRepo: ledgerRepo,
 				accRepo: mock.AccountRepository{
 					OnExistsByCPF: func(ctx context.Context, cpf model.CPF) (bool, error) {
 						return true, nil
@@ -288,7 +425,7 @@ func Test_accountUseCase_Create(t *testing.T) {
 					Name:    "Jon Snow",
 					CPF:     "599.513.320-99",
 					Secret:  "IAmNotSnow",
-					Balance: 0,
+					Balance: NewAmount(0),
 				},
 			},
 			want:    nil,
@@ -297,7 +434,7 @@ func Test_accountUseCase_Create(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			accountUC := NewAccountUseCase(tt.fields.accRepo)
+			accountUC := NewAccountUseCase(tt.fields.accRepo, tt.fields.ledgerRepo)
 
 			got, err := accountUC.Create(tt.args.ctx, tt.args.accountInput)
 			if (err != nil) != tt.wantErr {
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrAccountCreditLimitNegative happens when the credit limit is less than zero.
+	ErrAccountCreditLimitNegative = errors.New("'credit_limit' must be greater than or equal to zero")
+	// ErrAccountCreditLimitClosed happens when setting the credit limit of a closed account.
+	ErrAccountCreditLimitClosed = errors.New("closed accounts can't have a credit limit")
+	// ErrAccountSetCreditLimit happens when an error occurred and the credit limit was not set.
+	ErrAccountSetCreditLimit = errors.New("could not set account credit limit")
+)
+
+// AccountCreditLimitInput represents the expected input data when setting the credit limit of an account.
+type AccountCreditLimitInput struct {
+	AccountID   string `json:"-"`
+	CreditLimit Amount `json:"credit_limit" swaggertype:"number" example:"500"`
+}
+
+// Validate validates the AccountCreditLimitInput fields.
+func (input *AccountCreditLimitInput) Validate() error {
+	if input.CreditLimit.Money < 0 {
+		return ErrAccountCreditLimitNegative
+	}
+
+	return nil
+}
+
+// SetCreditLimit sets how far below zero the account balance can go, returning its new balances.
+//
+// A limit lower than what the account already owes is allowed: the account just can't be debited until its
+// balance is back above the limit. Closed accounts can't have a credit limit.
+// Only callers with the model.ScopeAccountsCredit can set credit limits, otherwise it returns ErrAuthForbidden.
+func (accUC accountUseCase) SetCreditLimit(ctx context.Context, caller model.Principal, creditLimitInput AccountCreditLimitInput) (*AccountBalanceOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if !caller.HasScope(model.ScopeAccountsCredit) {
+		return nil, ErrAuthForbidden
+	}
+
+	err := creditLimitInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", creditLimitInput).Msg("account credit limit input is not valid")
+		return nil, err
+	}
+
+	data, err := accUC.ledgerRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		account, err := accUC.accRepo.GetBalanceForUpdate(txCtx, model.AccountID(creditLimitInput.AccountID))
+		if err != nil {
+			return nil, err
+		}
+
+		creditLimit, err := creditLimitInput.CreditLimit.In(account.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if account.Status == model.AccountStatusClosed && creditLimit > 0 {
+			return nil, ErrAccountCreditLimitClosed
+		}
+
+		account.CreditLimit = creditLimit
+		return account, accUC.accRepo.UpdateCreditLimit(txCtx, account)
+	})
+	if err != nil {
+		if err == repository.ErrAccountNotFound || err == ErrAccountCreditLimitClosed || err == ErrAmountInvalid {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("input", creditLimitInput).Msg("error setting account credit limit")
+		return nil, ErrAccountSetCreditLimit
+	}
+
+	account, _ := data.(*model.Account)
+	log.Ctx(ctx).Info().Str("accountID", string(account.ID)).Int64("creditLimit", int64(account.CreditLimit)).Str("by", string(caller.AccountID)).Msg("account credit limit set")
+
+	return newAccountBalanceOutput(account), nil
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_accountUseCase_SetCreditLimit(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	operator := model.Principal{AccountID: "operator-uuid", Scopes: []model.Scope{model.ScopeAccountsCredit}}
+
+	ledgerRepo := mock.LedgerRepository{
+		OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+			return txFunc(ctx)
+		},
+	}
+	accountWith := func(balance model.Money, status model.AccountStatus) func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+		return func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			return &model.Account{ID: id, Balance: balance, CreditLimit: 10000, Status: status}, nil
+		}
+	}
+	updateCreditLimitOK := func(ctx context.Context, account *model.Account) error {
+		return nil
+	}
+
+	type fields struct {
+		accRepo repository.AccountRepository
+	}
+	type args struct {
+		ctx              context.Context
+		caller           model.Principal
+		creditLimitInput AccountCreditLimitInput
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *AccountBalanceOutput
+		wantErr error
+	}{
+		{
+			name: "caller without scope should return forbidden",
+			fields: fields{
+				accRepo: mock.AccountRepository{},
+			},
+			args: args{
+				ctx:              backgroundCtx,
+				caller:           model.Principal{AccountID: "uuid-1", Scopes: []model.Scope{model.ScopeAccountsRead, model.ScopeAccountsWrite}},
+				creditLimitInput: AccountCreditLimitInput{AccountID: "uuid-1", CreditLimit: NewAmount(50000)},
+			},
+			wantErr: ErrAuthForbidden,
+		},
+		{
+			name: "negative credit limit should return error",
+			fields: fields{
+				accRepo: mock.AccountRepository{},
+			},
+			args: args{
+				ctx:              backgroundCtx,
+				caller:           operator,
+				creditLimitInput: AccountCreditLimitInput{AccountID: "uuid-1", CreditLimit: NewAmount(-1)},
+			},
+			wantErr: ErrAccountCreditLimitNegative,
+		},
+		{
+			name: "not found account should return not found error",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+			},
+			args: args{
+				ctx:              backgroundCtx,
+				caller:           operator,
+				creditLimitInput: AccountCreditLimitInput{AccountID: "uuid-1", CreditLimit: NewAmount(50000)},
+			},
+			wantErr: repository.ErrAccountNotFound,
+		},
+		{
+			name: "closed account should not get a credit limit",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalanceForUpdate: accountWith(0, model.AccountStatusClosed),
+				},
+			},
+			args: args{
+				ctx:              backgroundCtx,
+				caller:           operator,
+				creditLimitInput: AccountCreditLimitInput{AccountID: "uuid-1", CreditLimit: NewAmount(50000)},
+			},
+			wantErr: ErrAccountCreditLimitClosed,
+		},
+		{
+			name: "closed account credit limit can be removed",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalanceForUpdate: accountWith(0, model.AccountStatusClosed),
+					OnUpdateCreditLimit:   updateCreditLimitOK,
+				},
+			},
+			args: args{
+				ctx:              backgroundCtx,
+				caller:           operator,
+				creditLimitInput: AccountCreditLimitInput{AccountID: "uuid-1", CreditLimit: NewAmount(0)},
+			},
+			want: &AccountBalanceOutput{
+				ID:               "uuid-1",
+				Balance:          NewAmount(0),
+				CreditLimit:      NewAmount(0),
+				AvailableBalance: NewAmount(0),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "repo update error should return error",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalanceForUpdate: accountWith(0, model.AccountStatusActive),
+					OnUpdateCreditLimit: func(ctx context.Context, account *model.Account) error {
+						return errors.New("any database error")
+					},
+				},
+			},
+			args: args{
+				ctx:              backgroundCtx,
+				caller:           operator,
+				creditLimitInput: AccountCreditLimitInput{AccountID: "uuid-1", CreditLimit: NewAmount(50000)},
+			},
+			wantErr: ErrAccountSetCreditLimit,
+		},
+		{
+			name: "limit lower than the overdraft should be set",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalanceForUpdate: accountWith(-8000, model.AccountStatusActive),
+					OnUpdateCreditLimit: func(ctx context.Context, account *model.Account) error {
+						if account.ID != "uuid-1" || account.CreditLimit != 5000 {
+							return errors.New("should update the account with the new credit limit")
+						}
+						return nil
+					},
+				},
+			},
+			args: args{
+				ctx:              backgroundCtx,
+				caller:           operator,
+				creditLimitInput: AccountCreditLimitInput{AccountID: "uuid-1", CreditLimit: NewAmount(5000)},
+			},
+			want: &AccountBalanceOutput{
+				ID:               "uuid-1",
+				Balance:          NewAmount(-8000),
+				CreditLimit:      NewAmount(5000),
+				AvailableBalance: NewAmount(-3000),
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			accUC := NewAccountUseCase(tt.fields.accRepo, ledgerRepo)
+
+			got, err := accUC.SetCreditLimit(tt.args.ctx, tt.args.caller, tt.args.creditLimitInput)
+			if err != tt.wantErr {
+				t.Errorf("SetCreditLimit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetCreditLimit() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
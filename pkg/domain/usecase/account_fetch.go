@@ -2,43 +2,237 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/cpfutil"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 )
 
+const (
+	// AccountFetchDefaultLimit is the page size when the limit is not informed.
+	AccountFetchDefaultLimit = 20
+	// AccountFetchMaxLimit is the biggest page size.
+	AccountFetchMaxLimit = 100
+)
+
 var (
 	// ErrAccountFetch happens when an error occurred while fetching the accounts.
 	ErrAccountFetch = errors.New("could not fetch accounts")
+	// ErrAccountFetchCursorInvalid happens when the cursor was not returned by a previous fetch with the same sort.
+	ErrAccountFetchCursorInvalid = errors.New("'cursor' is not valid")
+	// ErrAccountFetchLimitInvalid happens when the page size is out of bounds.
+	ErrAccountFetchLimitInvalid = errors.New("'limit' must be between 1 and 100")
+	// ErrAccountFetchSortInvalid happens when the sort is not known.
+	ErrAccountFetchSortInvalid = errors.New("'sort' must be 'created_at', '-created_at', 'name' or '-name'")
 )
 
-// AccountFetchOutput represents the output data of the fetch method.
+// AccountFetchInput represents the expected input data when fetching the accounts.
+//
+// Sort is the field to sort by, prefixed with "-" for descending order. By default, the oldest accounts come first.
+// Name searches the accounts whose name starts with it or is similar to it. CPF searches the account with exactly that CPF.
+type AccountFetchInput struct {
+	Cursor string
+	Limit  int
+	Sort   string
+	Name   string
+	CPF    string
+}
+
+// filter validates the AccountFetchInput fields and returns the corresponding model.AccountFilter.
+// It asks one account more than the page size, to know if there's a next page.
+func (input AccountFetchInput) filter() (model.AccountFilter, error) {
+	filter := model.AccountFilter{
+		Name:  strings.TrimSpace(input.Name),
+		Sort:  model.AccountSortCreatedAt,
+		Limit: input.Limit,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = AccountFetchDefaultLimit
+	}
+	if filter.Limit < 1 || filter.Limit > AccountFetchMaxLimit {
+		return filter, ErrAccountFetchLimitInvalid
+	}
+	filter.Limit++
+
+	if input.Sort != "" {
+		filter.Descending = strings.HasPrefix(input.Sort, "-")
+		filter.Sort = model.AccountSort(strings.TrimPrefix(input.Sort, "-"))
+		if !filter.Sort.IsValid() {
+			return filter, ErrAccountFetchSortInvalid
+		}
+	}
+
+	if input.CPF != "" {
+		if !cpfutil.IsValid(input.CPF) {
+			return filter, ErrAccountCPFInvalid
+		}
+		filter.CPF = model.NewCPF(input.CPF)
+	}
+
+	if input.Cursor != "" {
+		cursor, err := decodeAccountCursor(input.Cursor, filter.Sort)
+		if err != nil {
+			return filter, ErrAccountFetchCursorInvalid
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
+
+// accountCursor is the content of the opaque account cursors. It carries the sort, so it's not used with another one.
+type accountCursor struct {
+	Sort  model.AccountSort `json:"s"`
+	Value string            `json:"v"`
+	ID    string            `json:"id"`
+}
+
+// encodeAccountCursor returns an opaque cursor to fetch the accounts after the given one.
+func encodeAccountCursor(account model.Account, sort model.AccountSort) string {
+	cursor := accountCursor{
+		Sort:  sort,
+		Value: account.CreatedAt.UTC().Format(time.RFC3339Nano),
+		ID:    string(account.ID),
+	}
+	if sort == model.AccountSortName {
+		cursor.Value = account.Name
+	}
+
+	content, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+func decodeAccountCursor(encoded string, sort model.AccountSort) (*model.AccountCursor, error) {
+	content, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor accountCursor
+	if err := json.Unmarshal(content, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.Sort != sort {
+		return nil, ErrAccountFetchCursorInvalid
+	}
+
+	id, err := uuid.Parse(cursor.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	after := &model.AccountCursor{ID: model.AccountID(id.String())}
+	if sort == model.AccountSortName {
+		after.Name = cursor.Value
+		return after, nil
+	}
+
+	after.CreatedAt, err = time.Parse(time.RFC3339Nano, cursor.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+// AccountFetchOutput represents an account of the fetch method output.
+//
+// Only callers with the model.ScopeAccountsRead get the full CPF, the balance and the status, the others get the public projection.
 type AccountFetchOutput struct {
-	AccountCreateOutput
+	ID        string    `json:"id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	Name      string    `json:"name" example:"Bart Simpson"`
+	CPF       string    `json:"cpf" example:"***.999.999-**"`
+	Currency  string    `json:"currency" example:"BRL"`
+	Balance   *Amount   `json:"balance,omitempty" swaggertype:"number" example:"9999.99"`
+	Status    string    `json:"status,omitempty" example:"active"`
+	CreatedAt time.Time `json:"created_at" example:"2020-12-31T23:59:59.999999-03:00"`
 }
 
-func newAccountFetchOutputList(accounts []model.Account) []AccountFetchOutput {
-	var outputs = make([]AccountFetchOutput, 0)
+func newAccountFetchOutput(account *model.Account) AccountFetchOutput {
+	balance := NewAmountIn(account.Balance, account.Currency)
+
+	return AccountFetchOutput{
+		ID:        string(account.ID),
+		Name:      account.Name,
+		CPF:       account.CPF.String(),
+		Currency:  string(account.Currency),
+		Balance:   &balance,
+		Status:    string(account.Status),
+		CreatedAt: account.CreatedAt,
+	}
+}
+
+// newAccountFetchPublicOutput hides the balance and masks the CPF.
+func newAccountFetchPublicOutput(account *model.Account) AccountFetchOutput {
+	return AccountFetchOutput{
+		ID:        string(account.ID),
+		Name:      account.Name,
+		CPF:       cpfutil.Mask(string(account.CPF)),
+		Currency:  string(account.Currency),
+		CreatedAt: account.CreatedAt,
+	}
+}
+
+// AccountFetchPageOutput represents the output data of the fetch method.
+// NextCursor is null on the last page.
+type AccountFetchPageOutput struct {
+	Accounts   []AccountFetchOutput `json:"accounts"`
+	NextCursor *string              `json:"next_cursor" example:"eyJzIjoibmFtZSIsInYiOiJCYXJ0IFNpbXBzb24iLCJpZCI6IjE2YjFkODYwLTQzZDMtNDk3MC1iYjU0LWVjMzk1OTA4NTk5YSJ9"`
+}
+
+func newAccountFetchPageOutput(accounts []model.Account, filter model.AccountFilter, caller model.Principal) *AccountFetchPageOutput {
+	output := &AccountFetchPageOutput{
+		Accounts: make([]AccountFetchOutput, 0, len(accounts)),
+	}
+
+	limit := filter.Limit - 1
+	if len(accounts) > limit {
+		accounts = accounts[:limit]
+		nextCursor := encodeAccountCursor(accounts[limit-1], filter.Sort)
+		output.NextCursor = &nextCursor
+	}
 
 	for _, account := range accounts {
-		outputs = append(outputs, AccountFetchOutput{
-			AccountCreateOutput: *newAccountCreateOutput(&account),
-		})
+		if caller.HasScope(model.ScopeAccountsRead) {
+			output.Accounts = append(output.Accounts, newAccountFetchOutput(&account))
+		} else {
+			output.Accounts = append(output.Accounts, newAccountFetchPublicOutput(&account))
+		}
 	}
 
-	return outputs
+	return output
 }
 
-// Fetch returns all the accounts from repository.AccountRepository.
-func (accUC *accountUseCase) Fetch(ctx context.Context) ([]AccountFetchOutput, error) {
+// Fetch returns a page of the accounts from repository.AccountRepository, with the cursor of the next page, if any.
+// Callers without the model.ScopeAccountsRead get the accounts public projection and can't search by CPF,
+// otherwise it returns ErrAuthForbidden.
+func (accUC *accountUseCase) Fetch(ctx context.Context, caller model.Principal, fetchInput AccountFetchInput) (*AccountFetchPageOutput, error) {
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	accounts, err := accUC.accRepo.Fetch(ctx)
+	if fetchInput.CPF != "" && !caller.HasScope(model.ScopeAccountsRead) {
+		return nil, ErrAuthForbidden
+	}
+
+	filter, err := fetchInput.filter()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", fetchInput).Msg("account fetch input is not valid")
+		return nil, err
+	}
+
+	accounts, err := accUC.accRepo.Fetch(ctx, filter)
 	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error fetching accounts")
 		return nil, ErrAccountFetch
 	}
 
-	return newAccountFetchOutputList(accounts), nil
+	return newAccountFetchPageOutput(accounts, filter, caller), nil
 }
@@ -16,55 +16,67 @@ func Test_accountUseCase_Fetch(t *testing.T) {
 	t.Parallel()
 
 	backgroundCtx := context.Background()
+	adminCaller := model.Principal{AccountID: "admin-uuid", Scopes: []model.Scope{model.ScopeAccountsRead}}
+
+	createdAt := time.Date(2021, 1, 31, 12, 0, 0, 123456000, time.UTC)
+	twoAccounts := []model.Account{
+		{ID: "any-uuid-1", Name: "Bart Simpson", CPF: "59951332099", CreatedAt: createdAt},
+		{ID: "any-uuid-2", Name: "Lisa Simpson", CPF: "84352262048", CreatedAt: createdAt},
+	}
+	nameCursor := encodeAccountCursor(model.Account{ID: "e7df94ba-6e93-4b72-82f1-9d2a43b6f6ea", Name: "Bart Simpson"}, model.AccountSortName)
 
 	type fields struct {
 		accRepo repository.AccountRepository
 	}
 	type args struct {
-		ctx context.Context
+		ctx        context.Context
+		caller     model.Principal
+		fetchInput AccountFetchInput
 	}
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
-		want    []AccountFetchOutput
-		wantErr bool
+		want    *AccountFetchPageOutput
+		wantErr error
 	}{
 		{
 			name: "repo fetch error should return error",
 			fields: fields{
 				accRepo: mock.AccountRepository{
-					OnFetch: func(ctx context.Context) ([]model.Account, error) {
+					OnFetch: func(ctx context.Context, filter model.AccountFilter) ([]model.Account, error) {
 						return nil, errors.New("any database error")
 					},
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "admin-uuid", Scopes: []model.Scope{model.ScopeAccountsRead}},
 			},
 			want:    nil,
-			wantErr: true,
+			wantErr: ErrAccountFetch,
 		},
 		{
 			name: "repo empty result should return empty result",
 			fields: fields{
 				accRepo: mock.AccountRepository{
-					OnFetch: func(ctx context.Context) ([]model.Account, error) {
+					OnFetch: func(ctx context.Context, filter model.AccountFilter) ([]model.Account, error) {
 						return []model.Account{}, nil
 					},
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "admin-uuid", Scopes: []model.Scope{model.ScopeAccountsRead}},
 			},
-			want:    []AccountFetchOutput{},
-			wantErr: false,
+			want:    &AccountFetchPageOutput{Accounts: []AccountFetchOutput{}},
+			wantErr: nil,
 		},
 		{
 			name: "repo one result should return one result",
 			fields: fields{
 				accRepo: mock.AccountRepository{
-					OnFetch: func(ctx context.Context) ([]model.Account, error) {
+					OnFetch: func(ctx context.Context, filter model.AccountFilter) ([]model.Account, error) {
 						return []model.Account{
 							{
 								ID:        "any-uuid-1",
@@ -79,24 +91,25 @@ func Test_accountUseCase_Fetch(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "admin-uuid", Scopes: []model.Scope{model.ScopeAccountsRead}},
 			},
-			want: []AccountFetchOutput{
-				{AccountCreateOutput: AccountCreateOutput{
+			want: &AccountFetchPageOutput{Accounts: []AccountFetchOutput{
+				{
 					ID:        "any-uuid-1",
 					Name:      "Jon Snow",
 					CPF:       "599.513.320-99",
-					Balance:   0,
+					Balance:   newTestAmountPtr(0),
 					CreatedAt: time.Time{},
-				}},
-			},
-			wantErr: false,
+				},
+			}},
+			wantErr: nil,
 		},
 		{
 			name: "repo two results should return two results",
 			fields: fields{
 				accRepo: mock.AccountRepository{
-					OnFetch: func(ctx context.Context) ([]model.Account, error) {
+					OnFetch: func(ctx context.Context, filter model.AccountFilter) ([]model.Account, error) {
 						return []model.Account{
 							{
 								ID:        "any-uuid-1",
@@ -119,37 +132,189 @@ func Test_accountUseCase_Fetch(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "admin-uuid", Scopes: []model.Scope{model.ScopeAccountsRead}},
 			},
-			want: []AccountFetchOutput{
+			want: &AccountFetchPageOutput{Accounts: []AccountFetchOutput{
+				{
+					ID:        "any-uuid-1",
+					Name:      "Homer Simpson",
+					CPF:       "599.513.320-99",
+					Balance:   newTestAmountPtr(-240),
+					CreatedAt: time.Time{},
+				},
 				{
-					AccountCreateOutput: AccountCreateOutput{
-						ID:        "any-uuid-1",
-						Name:      "Homer Simpson",
-						CPF:       "599.513.320-99",
-						Balance:   -2.4,
-						CreatedAt: time.Time{},
+					ID:        "any-uuid-2",
+					Name:      "Marge Simpson",
+					CPF:       "843.522.620-48",
+					Balance:   newTestAmountPtr(55),
+					CreatedAt: time.Time{},
+				},
+			}},
+			wantErr: nil,
+		},
+		{
+			name: "caller without scope should get the public projection",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnFetch: func(ctx context.Context, filter model.AccountFilter) ([]model.Account, error) {
+						return []model.Account{
+							{
+								ID:        "any-uuid-1",
+								Name:      "Homer Simpson",
+								CPF:       "59951332099",
+								Secret:    "Donuts",
+								Balance:   -240,
+								CreatedAt: time.Time{},
+							},
+						}, nil
 					},
 				},
+			},
+			args: args{
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "any-uuid-1", Roles: []model.Role{model.RoleAdmin}},
+			},
+			want: &AccountFetchPageOutput{Accounts: []AccountFetchOutput{
 				{
-					AccountCreateOutput: AccountCreateOutput{
-						ID:        "any-uuid-2",
-						Name:      "Marge Simpson",
-						CPF:       "843.522.620-48",
-						Balance:   0.55,
-						CreatedAt: time.Time{},
+					ID:        "any-uuid-1",
+					Name:      "Homer Simpson",
+					CPF:       "***.513.320-**",
+					Balance:   nil,
+					CreatedAt: time.Time{},
+				},
+			}},
+			wantErr: nil,
+		},
+		{
+			name: "repo results over the limit should return the next cursor",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnFetch: func(ctx context.Context, filter model.AccountFilter) ([]model.Account, error) {
+						if filter.Limit != 2 {
+							return nil, errors.New("should fetch the page size plus one")
+						}
+						return twoAccounts, nil
 					},
 				},
 			},
-			wantErr: false,
+			args: args{
+				ctx:        backgroundCtx,
+				caller:     model.Principal{AccountID: "any-uuid-1"},
+				fetchInput: AccountFetchInput{Limit: 1},
+			},
+			want: &AccountFetchPageOutput{
+				Accounts: []AccountFetchOutput{
+					{ID: "any-uuid-1", Name: "Bart Simpson", CPF: "***.513.320-**", CreatedAt: createdAt},
+				},
+				NextCursor: func() *string {
+					cursor := encodeAccountCursor(twoAccounts[0], model.AccountSortCreatedAt)
+					return &cursor
+				}(),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should pass the sort, the search and the cursor to the repo",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnFetch: func(ctx context.Context, filter model.AccountFilter) ([]model.Account, error) {
+						want := model.AccountFilter{
+							Name:       "bart",
+							CPF:        "59951332099",
+							Sort:       model.AccountSortName,
+							Descending: true,
+							After:      &model.AccountCursor{ID: "e7df94ba-6e93-4b72-82f1-9d2a43b6f6ea", Name: "Bart Simpson"},
+							Limit:      AccountFetchDefaultLimit + 1,
+						}
+						if !reflect.DeepEqual(filter, want) {
+							return nil, errors.New("unexpected filter")
+						}
+						return []model.Account{}, nil
+					},
+				},
+			},
+			args: args{
+				ctx:        backgroundCtx,
+				caller:     adminCaller,
+				fetchInput: AccountFetchInput{Cursor: nameCursor, Sort: "-name", Name: " bart ", CPF: "599.513.320-99"},
+			},
+			want:    &AccountFetchPageOutput{Accounts: []AccountFetchOutput{}},
+			wantErr: nil,
+		},
+		{
+			name:   "caller without scope searching by cpf should return forbidden error",
+			fields: fields{accRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				caller:     model.Principal{AccountID: "any-uuid-1"},
+				fetchInput: AccountFetchInput{CPF: "599.513.320-99"},
+			},
+			want:    nil,
+			wantErr: ErrAuthForbidden,
+		},
+		{
+			name:   "invalid cpf should return cpf invalid error",
+			fields: fields{accRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				caller:     adminCaller,
+				fetchInput: AccountFetchInput{CPF: "599.513.320-00"},
+			},
+			want:    nil,
+			wantErr: ErrAccountCPFInvalid,
+		},
+		{
+			name:   "limit over the max should return limit invalid error",
+			fields: fields{accRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				caller:     adminCaller,
+				fetchInput: AccountFetchInput{Limit: AccountFetchMaxLimit + 1},
+			},
+			want:    nil,
+			wantErr: ErrAccountFetchLimitInvalid,
+		},
+		{
+			name:   "unknown sort should return sort invalid error",
+			fields: fields{accRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				caller:     adminCaller,
+				fetchInput: AccountFetchInput{Sort: "balance"},
+			},
+			want:    nil,
+			wantErr: ErrAccountFetchSortInvalid,
+		},
+		{
+			name:   "cursor of another sort should return cursor invalid error",
+			fields: fields{accRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				caller:     adminCaller,
+				fetchInput: AccountFetchInput{Cursor: nameCursor, Sort: "created_at"},
+			},
+			want:    nil,
+			wantErr: ErrAccountFetchCursorInvalid,
+		},
+		{
+			name:   "unknown cursor should return cursor invalid error",
+			fields: fields{accRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				caller:     adminCaller,
+				fetchInput: AccountFetchInput{Cursor: "any-cursor"},
+			},
+			want:    nil,
+			wantErr: ErrAccountFetchCursorInvalid,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			accountUC := NewAccountUseCase(tt.fields.accRepo)
+			accountUC := NewAccountUseCase(tt.fields.accRepo, nil)
 
-			got, err := accountUC.Fetch(tt.args.ctx)
-			if (err != nil) != tt.wantErr {
+			got, err := accountUC.Fetch(tt.args.ctx, tt.args.caller, tt.args.fetchInput)
+			if err != tt.wantErr {
 				t.Errorf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
@@ -160,3 +325,34 @@ func Test_accountUseCase_Fetch(t *testing.T) {
 		})
 	}
 }
+
+func Test_accountCursor(t *testing.T) {
+	t.Parallel()
+
+	account := model.Account{
+		ID:        "e7df94ba-6e93-4b72-82f1-9d2a43b6f6ea",
+		Name:      "Bart Simpson",
+		CreatedAt: time.Date(2021, 1, 31, 9, 0, 0, 123456000, time.FixedZone("BRT", -3*60*60)),
+	}
+
+	got, err := decodeAccountCursor(encodeAccountCursor(account, model.AccountSortCreatedAt), model.AccountSortCreatedAt)
+	if err != nil {
+		t.Fatalf("decodeAccountCursor() error = %v", err)
+	}
+	if got.ID != account.ID || !got.CreatedAt.Equal(account.CreatedAt) {
+		t.Errorf("decodeAccountCursor() got = %v, want %v", got, account)
+	}
+
+	got, err = decodeAccountCursor(encodeAccountCursor(account, model.AccountSortName), model.AccountSortName)
+	if err != nil {
+		t.Fatalf("decodeAccountCursor() error = %v", err)
+	}
+	if got.ID != account.ID || got.Name != account.Name {
+		t.Errorf("decodeAccountCursor() got = %v, want %v", got, account)
+	}
+}
+
+func newTestAmountPtr(money model.Money) *Amount {
+	amount := NewAmount(money)
+	return &amount
+}
@@ -15,23 +15,36 @@ var (
 )
 
 // AccountBalanceOutput represents the output data of the GetBalance method.
+// Balance is the ledger balance, negative when overdrawn, and AvailableBalance is how much can be spent,
+// including the credit limit. They are all in the Currency of the account.
 type AccountBalanceOutput struct {
-	ID      string  `json:"id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
-	Balance float64 `json:"balance" example:"9999.99"`
+	ID               string `json:"id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	Currency         string `json:"currency" example:"BRL"`
+	Balance          Amount `json:"balance" swaggertype:"number" example:"-100"`
+	CreditLimit      Amount `json:"credit_limit" swaggertype:"number" example:"500"`
+	AvailableBalance Amount `json:"available_balance" swaggertype:"number" example:"400"`
 }
 
 func newAccountBalanceOutput(account *model.Account) *AccountBalanceOutput {
 	return &AccountBalanceOutput{
-		ID:      string(account.ID),
-		Balance: account.Balance.Float64(),
+		ID:               string(account.ID),
+		Currency:         string(account.Currency),
+		Balance:          NewAmountIn(account.Balance, account.Currency),
+		CreditLimit:      NewAmountIn(account.CreditLimit, account.Currency),
+		AvailableBalance: NewAmountIn(account.AvailableBalance(), account.Currency),
 	}
 }
 
 // GetBalance returns the AccountBalanceOutput with matching ID from repository.AccountRepository.
-func (accUC *accountUseCase) GetBalance(ctx context.Context, id model.AccountID) (*AccountBalanceOutput, error) {
+// Only the account owner can read its balance, otherwise it returns ErrAuthForbidden.
+func (accUC *accountUseCase) GetBalance(ctx context.Context, caller model.Principal, id model.AccountID) (*AccountBalanceOutput, error) {
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	if !caller.Owns(id) {
+		return nil, ErrAuthForbidden
+	}
+
 	account, err := accUC.accRepo.GetBalance(ctx, id)
 	if err != nil {
 		if err == repository.ErrAccountNotFound {
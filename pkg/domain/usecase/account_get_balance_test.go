@@ -21,8 +21,9 @@ func Test_accountUseCase_GetBalance(t *testing.T) {
 		accountRepo repository.AccountRepository
 	}
 	type args struct {
-		ctx context.Context
-		id  model.AccountID
+		ctx    context.Context
+		caller model.Principal
+		id     model.AccountID
 	}
 	tests := []struct {
 		name    string
@@ -41,8 +42,9 @@ func Test_accountUseCase_GetBalance(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
-				id:  "any-uuid-1",
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "any-uuid-1"},
+				id:     "any-uuid-1",
 			},
 			want:    nil,
 			wantErr: ErrAccountGetBalance,
@@ -57,8 +59,9 @@ func Test_accountUseCase_GetBalance(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
-				id:  "any-uuid-1",
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "any-uuid-1"},
+				id:     "any-uuid-1",
 			},
 			want:    nil,
 			wantErr: repository.ErrAccountNotFound,
@@ -80,12 +83,14 @@ func Test_accountUseCase_GetBalance(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
-				id:  "any-uuid-1",
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "any-uuid-1"},
+				id:     "any-uuid-1",
 			},
 			want: &AccountBalanceOutput{
-				ID:      "any-uuid-1",
-				Balance: 0,
+				ID:               "any-uuid-1",
+				Balance:          NewAmount(0),
+				AvailableBalance: NewAmount(0),
 			},
 			wantErr: nil,
 		},
@@ -106,12 +111,14 @@ func Test_accountUseCase_GetBalance(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
-				id:  "any-uuid-1",
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "any-uuid-1"},
+				id:     "any-uuid-1",
 			},
 			want: &AccountBalanceOutput{
-				ID:      "any-uuid-1",
-				Balance: 10,
+				ID:               "any-uuid-1",
+				Balance:          NewAmount(1000),
+				AvailableBalance: NewAmount(1000),
 			},
 			wantErr: nil,
 		},
@@ -132,12 +139,14 @@ func Test_accountUseCase_GetBalance(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
-				id:  "any-uuid-1",
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "any-uuid-1"},
+				id:     "any-uuid-1",
 			},
 			want: &AccountBalanceOutput{
-				ID:      "any-uuid-1",
-				Balance: 199,
+				ID:               "any-uuid-1",
+				Balance:          NewAmount(19900),
+				AvailableBalance: NewAmount(19900),
 			},
 			wantErr: nil,
 		},
@@ -158,12 +167,14 @@ func Test_accountUseCase_GetBalance(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
-				id:  "any-uuid-1",
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "any-uuid-1"},
+				id:     "any-uuid-1",
 			},
 			want: &AccountBalanceOutput{
-				ID:      "any-uuid-1",
-				Balance: 0.55,
+				ID:               "any-uuid-1",
+				Balance:          NewAmount(55),
+				AvailableBalance: NewAmount(55),
 			},
 			wantErr: nil,
 		},
@@ -184,21 +195,79 @@ func Test_accountUseCase_GetBalance(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
-				id:  "any-uuid-1",
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "any-uuid-1"},
+				id:     "any-uuid-1",
 			},
 			want: &AccountBalanceOutput{
-				ID:      "any-uuid-1",
-				Balance: -1.55,
+				ID:               "any-uuid-1",
+				Balance:          NewAmount(-155),
+				AvailableBalance: NewAmount(-155),
 			},
 			wantErr: nil,
 		},
+		{
+			name: "overdrawn balance should be available down to the credit limit",
+			fields: fields{
+				accountRepo: mock.AccountRepository{
+					OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return &model.Account{
+							ID:          "any-uuid-1",
+							Balance:     -15000,
+							CreditLimit: 50000,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "any-uuid-1"},
+				id:     "any-uuid-1",
+			},
+			want: &AccountBalanceOutput{
+				ID:               "any-uuid-1",
+				Balance:          NewAmount(-15000),
+				CreditLimit:      NewAmount(50000),
+				AvailableBalance: NewAmount(35000),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "other account should return forbidden error",
+			fields: fields{
+				accountRepo: mock.AccountRepository{
+					OnGetBalance: nil,
+				},
+			},
+			args: args{
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "any-uuid-2"},
+				id:     "any-uuid-1",
+			},
+			want:    nil,
+			wantErr: ErrAuthForbidden,
+		},
+		{
+			name: "admin of other account should return forbidden error",
+			fields: fields{
+				accountRepo: mock.AccountRepository{
+					OnGetBalance: nil,
+				},
+			},
+			args: args{
+				ctx:    backgroundCtx,
+				caller: model.Principal{AccountID: "any-uuid-2", Roles: []model.Role{model.RoleAdmin}, Scopes: []model.Scope{model.ScopeAccountsRead}},
+				id:     "any-uuid-1",
+			},
+			want:    nil,
+			wantErr: ErrAuthForbidden,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			accountUC := NewAccountUseCase(tt.fields.accountRepo)
+			accountUC := NewAccountUseCase(tt.fields.accountRepo, nil)
 
-			got, err := accountUC.GetBalance(tt.args.ctx, tt.args.id)
+			got, err := accountUC.GetBalance(tt.args.ctx, tt.args.caller, tt.args.id)
 			if err != tt.wantErr {
 				t.Errorf("GetBalance() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// AccountStatementDefaultPeriod is the statement period when 'from' is not informed.
+const AccountStatementDefaultPeriod = 30 * 24 * time.Hour
+
+var (
+	// ErrAccountStatementPeriodInvalid happens when the statement period doesn't end after it starts.
+	ErrAccountStatementPeriodInvalid = errors.New("'from' must be before 'to'")
+	// ErrAccountGetStatement happens when an error occurred while getting the account statement.
+	ErrAccountGetStatement = errors.New("could not get account statement")
+)
+
+// AccountStatementInput represents the expected input data when getting an account statement.
+// The period includes From and excludes To. When To is zero it's now, when From is zero it's AccountStatementDefaultPeriod before To.
+type AccountStatementInput struct {
+	AccountID model.AccountID
+	From      time.Time
+	To        time.Time
+}
+
+// Validate fills the period defaults and validates the AccountStatementInput fields.
+func (input *AccountStatementInput) Validate() error {
+	if input.To.IsZero() {
+		input.To = time.Now()
+	}
+	if input.From.IsZero() {
+		input.From = input.To.Add(-AccountStatementDefaultPeriod)
+	}
+
+	if !input.From.Before(input.To) {
+		return ErrAccountStatementPeriodInvalid
+	}
+
+	return nil
+}
+
+// AccountStatementCounterpartOutput represents the other account of a statement entry.
+type AccountStatementCounterpartOutput struct {
+	ID   string `json:"id" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
+	Name string `json:"name" example:"Bart Simpson"`
+}
+
+// AccountStatementEntryOutput represents a movement of the account statement.
+type AccountStatementEntryOutput struct {
+	ID           string                             `json:"id" example:"5c1f3d9e-1b8a-4a7e-9d3c-6f0e2b7a4c18"`
+	Direction    string                             `json:"direction" enums:"credit,debit" example:"debit"`
+	Kind         string                             `json:"kind" example:"transfer"`
+	ReferenceID  string                             `json:"reference_id" example:"f0b1e8a4-7c2d-4e5f-8a9b-0c1d2e3f4a5b"`
+	Amount       Amount                             `json:"amount" swaggertype:"number" example:"9999.99"`
+	BalanceAfter Amount                             `json:"balance_after" swaggertype:"number" example:"9999.99"`
+	Counterpart  *AccountStatementCounterpartOutput `json:"counterpart,omitempty"`
+	CreatedAt    time.Time                          `json:"created_at" example:"2020-12-31T23:59:59.999999-03:00"`
+}
+
+// AccountStatementOutput represents the output data of the GetStatement method.
+type AccountStatementOutput struct {
+	AccountID      string                        `json:"account_id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	From           time.Time                     `json:"from" example:"2020-12-01T00:00:00Z"`
+	To             time.Time                     `json:"to" example:"2021-01-01T00:00:00Z"`
+	OpeningBalance Amount                        `json:"opening_balance" swaggertype:"number" example:"9999.99"`
+	ClosingBalance Amount                        `json:"closing_balance" swaggertype:"number" example:"9999.99"`
+	Entries        []AccountStatementEntryOutput `json:"entries"`
+}
+
+func newAccountStatementOutput(statement *model.Statement) *AccountStatementOutput {
+	output := &AccountStatementOutput{
+		AccountID:      string(statement.AccountID),
+		From:           statement.From,
+		To:             statement.To,
+		OpeningBalance: NewAmountIn(statement.OpeningBalance, statement.Currency),
+		ClosingBalance: NewAmountIn(statement.ClosingBalance, statement.Currency),
+		Entries:        make([]AccountStatementEntryOutput, 0, len(statement.Entries)),
+	}
+
+	for _, entry := range statement.Entries {
+		entryOutput := AccountStatementEntryOutput{
+			ID:           string(entry.ID),
+			Direction:    string(entry.Type),
+			Kind:         string(entry.Kind),
+			ReferenceID:  entry.ReferenceID,
+			Amount:       NewAmountIn(entry.Amount, statement.Currency),
+			BalanceAfter: NewAmountIn(entry.BalanceAfter, statement.Currency),
+			CreatedAt:    entry.CreatedAt,
+		}
+		if !entry.HasExternalCounterpart() {
+			entryOutput.Counterpart = &AccountStatementCounterpartOutput{
+				ID:   string(entry.CounterpartID),
+				Name: entry.CounterpartName,
+			}
+		}
+
+		output.Entries = append(output.Entries, entryOutput)
+	}
+
+	return output
+}
+
+// GetStatement returns the account movements in the period, with the opening, running and closing balances.
+// Only the account owner can get it, otherwise it returns ErrAuthForbidden.
+func (accUC *accountUseCase) GetStatement(ctx context.Context, caller model.Principal, statementInput AccountStatementInput) (*AccountStatementOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if !caller.Owns(statementInput.AccountID) {
+		return nil, ErrAuthForbidden
+	}
+
+	err := statementInput.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := accUC.accRepo.GetBalance(ctx, statementInput.AccountID)
+	if err != nil {
+		if err == repository.ErrAccountNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(statementInput.AccountID)).Msg("error getting statement account")
+		return nil, ErrAccountGetStatement
+	}
+
+	statement, err := accUC.ledgerRepo.GetStatement(ctx, statementInput.AccountID, account.Currency, statementInput.From, statementInput.To)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(statementInput.AccountID)).Msg("error getting account statement")
+		return nil, ErrAccountGetStatement
+	}
+
+	return newAccountStatementOutput(statement), nil
+}
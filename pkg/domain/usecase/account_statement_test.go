@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_accountUseCase_GetStatement(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	existingAccountRepo := mock.AccountRepository{
+		OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			return &model.Account{ID: id, Currency: model.CurrencyUSD, Status: model.AccountStatusActive}, nil
+		},
+	}
+
+	type fields struct {
+		accountRepo repository.AccountRepository
+		ledgerRepo  repository.LedgerRepository
+	}
+	type args struct {
+		ctx            context.Context
+		caller         model.Principal
+		statementInput AccountStatementInput
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *AccountStatementOutput
+		wantErr error
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				accountRepo: existingAccountRepo,
+				ledgerRepo: mock.LedgerRepository{
+					OnGetStatement: func(ctx context.Context, accountID model.AccountID, currency model.Currency, from, to time.Time) (*model.Statement, error) {
+						if currency != model.CurrencyUSD {
+							return nil, errors.New("should get the statement in the currency of the account")
+						}
+						return &model.Statement{
+							AccountID:      accountID,
+							Currency:       currency,
+							From:           from,
+							To:             to,
+							OpeningBalance: 1000,
+							ClosingBalance: 650,
+							Entries: []model.StatementEntry{
+								{
+									LedgerEntry:     model.LedgerEntry{ID: "entry-1", Type: model.LedgerEntryDebit, Amount: 300, Kind: model.LedgerPostingTransfer, ReferenceID: "transfer-1", CreatedAt: from},
+									CounterpartID:   "any-uuid-2",
+									CounterpartName: "Bart Simpson",
+									BalanceAfter:    700,
+								},
+								{
+									LedgerEntry:   model.LedgerEntry{ID: "entry-2", Type: model.LedgerEntryDebit, Amount: 50, Kind: model.LedgerPostingWithdrawal, ReferenceID: "withdrawal-1", CreatedAt: from},
+									CounterpartID: model.LedgerExternalAccountID,
+									BalanceAfter:  650,
+								},
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				ctx:            backgroundCtx,
+				caller:         model.Principal{AccountID: "any-uuid-1"},
+				statementInput: AccountStatementInput{AccountID: "any-uuid-1", From: from, To: to},
+			},
+			want: &AccountStatementOutput{
+				AccountID:      "any-uuid-1",
+				From:           from,
+				To:             to,
+				OpeningBalance: NewAmountIn(1000, model.CurrencyUSD),
+				ClosingBalance: NewAmountIn(650, model.CurrencyUSD),
+				Entries: []AccountStatementEntryOutput{
+					{
+						ID:           "entry-1",
+						Direction:    "debit",
+						Kind:         "transfer",
+						ReferenceID:  "transfer-1",
+						Amount:       NewAmountIn(300, model.CurrencyUSD),
+						BalanceAfter: NewAmountIn(700, model.CurrencyUSD),
+						Counterpart:  &AccountStatementCounterpartOutput{ID: "any-uuid-2", Name: "Bart Simpson"},
+						CreatedAt:    from,
+					},
+					{
+						ID:           "entry-2",
+						Direction:    "debit",
+						Kind:         "withdrawal",
+						ReferenceID:  "withdrawal-1",
+						Amount:       NewAmountIn(50, model.CurrencyUSD),
+						BalanceAfter: NewAmountIn(650, model.CurrencyUSD),
+						Counterpart:  nil,
+						CreatedAt:    from,
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "missing period should default to the last days",
+			fields: fields{
+				accountRepo: existingAccountRepo,
+				ledgerRepo: mock.LedgerRepository{
+					OnGetStatement: func(ctx context.Context, accountID model.AccountID, currency model.Currency, from, to time.Time) (*model.Statement, error) {
+						if to.Sub(from) != AccountStatementDefaultPeriod || time.Since(to) > time.Minute {
+							return nil, errors.New("should default to the last days")
+						}
+
+						return &model.Statement{AccountID: accountID, From: from, To: to, Entries: []model.StatementEntry{}}, nil
+					},
+				},
+			},
+			args: args{
+				ctx:            backgroundCtx,
+				caller:         model.Principal{AccountID: "any-uuid-1"},
+				statementInput: AccountStatementInput{AccountID: "any-uuid-1"},
+			},
+			want:    nil,
+			wantErr: nil,
+		},
+		{
+			name: "'from' after 'to' should return period invalid error",
+			fields: fields{
+				accountRepo: nil,
+				ledgerRepo:  nil,
+			},
+			args: args{
+				ctx:            backgroundCtx,
+				caller:         model.Principal{AccountID: "any-uuid-1"},
+				statementInput: AccountStatementInput{AccountID: "any-uuid-1", From: to, To: from},
+			},
+			want:    nil,
+			wantErr: ErrAccountStatementPeriodInvalid,
+		},
+		{
+			name: "other account should return forbidden error",
+			fields: fields{
+				accountRepo: nil,
+				ledgerRepo:  nil,
+			},
+			args: args{
+				ctx:            backgroundCtx,
+				caller:         model.Principal{AccountID: "any-uuid-2", Roles: []model.Role{model.RoleAdmin}, Scopes: []model.Scope{model.ScopeAccountsRead}},
+				statementInput: AccountStatementInput{AccountID: "any-uuid-1", From: from, To: to},
+			},
+			want:    nil,
+			wantErr: ErrAuthForbidden,
+		},
+		{
+			name: "account not found should return not found error",
+			fields: fields{
+				accountRepo: mock.AccountRepository{
+					OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+				ledgerRepo: nil,
+			},
+			args: args{
+				ctx:            backgroundCtx,
+				caller:         model.Principal{AccountID: "any-uuid-1"},
+				statementInput: AccountStatementInput{AccountID: "any-uuid-1", From: from, To: to},
+			},
+			want:    nil,
+			wantErr: repository.ErrAccountNotFound,
+		},
+		{
+			name: "ledger repo error should return error",
+			fields: fields{
+				accountRepo: existingAccountRepo,
+				ledgerRepo: mock.LedgerRepository{
+					OnGetStatement: func(ctx context.Context, accountID model.AccountID, currency model.Currency, from, to time.Time) (*model.Statement, error) {
+						return nil, errors.New("any database error")
+					},
+				},
+			},
+			args: args{
+				ctx:            backgroundCtx,
+				caller:         model.Principal{AccountID: "any-uuid-1"},
+				statementInput: AccountStatementInput{AccountID: "any-uuid-1", From: from, To: to},
+			},
+			want:    nil,
+			wantErr: ErrAccountGetStatement,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accountUC := NewAccountUseCase(tt.fields.accountRepo, tt.fields.ledgerRepo)
+
+			got, err := accountUC.GetStatement(tt.args.ctx, tt.args.caller, tt.args.statementInput)
+			if err != tt.wantErr {
+				t.Errorf("GetStatement() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetStatement() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrAccountStatusReasonRequired happens when the reason of the status change is blank.
+	ErrAccountStatusReasonRequired = errors.New("'reason' is required")
+	// ErrAccountStatusChangeInvalid happens when the account can't go from its current status to the requested one,
+	// like unblocking an active account or changing a closed account.
+	ErrAccountStatusChangeInvalid = errors.New("account status can't be changed")
+	// ErrAccountCloseBalanceNotZero happens when closing an account with a positive balance without a sweep account.
+	ErrAccountCloseBalanceNotZero = errors.New("account balance must be zero or 'sweep_account_id' must be informed")
+	// ErrAccountCloseBalanceNegative happens when closing an account with a negative balance.
+	ErrAccountCloseBalanceNegative = errors.New("account with negative balance can't be closed")
+	// ErrAccountSweepAccountInvalid happens when the sweep account is the closing account itself, doesn't exist, is not active
+	// or has another currency.
+	ErrAccountSweepAccountInvalid = errors.New("'sweep_account_id' must be another active account of the same currency")
+	// ErrAccountChangeStatus happens when an error occurred and the account status was not changed.
+	ErrAccountChangeStatus = errors.New("could not change account status")
+)
+
+// AccountStatusInput represents the expected input data when blocking or unblocking an account.
+type AccountStatusInput struct {
+	AccountID string `json:"-"`
+	Reason    string `json:"reason" example:"suspected fraud"`
+}
+
+// Validate validates the AccountStatusInput fields.
+func (input *AccountStatusInput) Validate() error {
+	input.Reason = strings.TrimSpace(input.Reason)
+	if len(input.Reason) < 1 {
+		return ErrAccountStatusReasonRequired
+	}
+
+	return nil
+}
+
+// AccountCloseInput represents the expected input data when closing an account.
+type AccountCloseInput struct {
+	AccountID      string `json:"-"`
+	Reason         string `json:"reason" example:"requested by the holder"`
+	SweepAccountID string `json:"sweep_account_id,omitempty" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
+}
+
+// Validate validates the AccountCloseInput fields.
+func (input *AccountCloseInput) Validate() error {
+	input.Reason = strings.TrimSpace(input.Reason)
+	if len(input.Reason) < 1 {
+		return ErrAccountStatusReasonRequired
+	}
+
+	input.SweepAccountID = strings.TrimSpace(input.SweepAccountID)
+	if input.SweepAccountID != "" && input.SweepAccountID == input.AccountID {
+		return ErrAccountSweepAccountInvalid
+	}
+
+	return nil
+}
+
+// AccountStatusOutput represents the output data of the status change methods.
+type AccountStatusOutput struct {
+	ID              string    `json:"id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	Status          string    `json:"status" example:"blocked"`
+	StatusReason    string    `json:"status_reason" example:"suspected fraud"`
+	StatusChangedAt time.Time `json:"status_changed_at" example:"2020-12-31T23:59:59.999999-03:00"`
+	SweptAmount     *Amount   `json:"swept_amount,omitempty" swaggertype:"number" example:"9999.99"`
+}
+
+func newAccountStatusOutput(account *model.Account, sweptAmount model.Money) *AccountStatusOutput {
+	output := &AccountStatusOutput{
+		ID:              string(account.ID),
+		Status:          string(account.Status),
+		StatusReason:    account.StatusReason,
+		StatusChangedAt: account.StatusChangedAt,
+	}
+	if sweptAmount > 0 {
+		amount := NewAmountIn(sweptAmount, account.Currency)
+		output.SweptAmount = &amount
+	}
+
+	return output
+}
+
+// Block blocks an active account, so it can't send nor receive money until it's unblocked.
+// Only callers with the model.ScopeAccountsWrite can block accounts, otherwise it returns ErrAuthForbidden.
+func (accUC accountUseCase) Block(ctx context.Context, caller model.Principal, statusInput AccountStatusInput) (*AccountStatusOutput, error) {
+	return accUC.changeStatus(ctx, caller, statusInput, model.AccountStatusBlocked)
+}
+
+// Unblock makes a blocked account active again.
+// Only callers with the model.ScopeAccountsWrite can unblock accounts, otherwise it returns ErrAuthForbidden.
+func (accUC accountUseCase) Unblock(ctx context.Context, caller model.Principal, statusInput AccountStatusInput) (*AccountStatusOutput, error) {
+	return accUC.changeStatus(ctx, caller, statusInput, model.AccountStatusActive)
+}
+
+func (accUC accountUseCase) changeStatus(ctx context.Context, caller model.Principal, statusInput AccountStatusInput, status model.AccountStatus) (*AccountStatusOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if !caller.HasScope(model.ScopeAccountsWrite) {
+		return nil, ErrAuthForbidden
+	}
+
+	err := statusInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", statusInput).Msg("account status input is not valid")
+		return nil, err
+	}
+
+	data, err := accUC.ledgerRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		account, err := accUC.accRepo.GetBalanceForUpdate(txCtx, model.AccountID(statusInput.AccountID))
+		if err != nil {
+			return nil, err
+		}
+
+		return account, accUC.updateStatus(txCtx, account, status, statusInput.Reason)
+	})
+	if err != nil {
+		if err == repository.ErrAccountNotFound || err == ErrAccountStatusChangeInvalid {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("input", statusInput).Str("status", string(status)).Msg("error changing account status")
+		return nil, ErrAccountChangeStatus
+	}
+
+	account, _ := data.(*model.Account)
+	log.Ctx(ctx).Info().Str("accountID", string(account.ID)).Str("status", string(status)).Str("by", string(caller.AccountID)).Msg("account status changed")
+
+	return newAccountStatusOutput(account, 0), nil
+}
+
+// Close closes an active or blocked account for good.
+//
+// The account balance must be zero, unless a sweep account is informed: then the remaining balance is
+// transferred to it, which must be active. Accounts with negative balance can't be closed.
+// Only callers with the model.ScopeAccountsWrite can close accounts, otherwise it returns ErrAuthForbidden.
+func (accUC accountUseCase) Close(ctx context.Context, caller model.Principal, closeInput AccountCloseInput) (*AccountStatusOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if !caller.HasScope(model.ScopeAccountsWrite) {
+		return nil, ErrAuthForbidden
+	}
+
+	err := closeInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", closeInput).Msg("account close input is not valid")
+		return nil, err
+	}
+
+	// the accounts are never deleted, so the sweep account existence can be checked before locking
+	if closeInput.SweepAccountID != "" {
+		_, err = accUC.accRepo.GetBalance(ctx, model.AccountID(closeInput.SweepAccountID))
+		if err != nil {
+			if err == repository.ErrAccountNotFound {
+				return nil, ErrAccountSweepAccountInvalid
+			}
+			log.Ctx(ctx).Error().Stack().Err(err).Interface("input", closeInput).Msg("error getting sweep account")
+			return nil, ErrAccountChangeStatus
+		}
+	}
+
+	var sweptAmount model.Money
+	data, err := accUC.ledgerRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		account, sweepAccount, err := accUC.lockClosingAccounts(txCtx, closeInput)
+		if err != nil {
+			return nil, err
+		}
+
+		if !account.CanChangeStatusTo(model.AccountStatusClosed) {
+			return nil, ErrAccountStatusChangeInvalid
+		}
+
+		sweptAmount, err = accUC.sweepBalance(txCtx, account, sweepAccount)
+		if err != nil {
+			return nil, err
+		}
+
+		return account, accUC.updateStatus(txCtx, account, model.AccountStatusClosed, closeInput.Reason)
+	})
+	if err != nil {
+		switch err {
+		case repository.ErrAccountNotFound, ErrAccountStatusChangeInvalid, ErrAccountCloseBalanceNotZero,
+			ErrAccountCloseBalanceNegative, ErrAccountSweepAccountInvalid:
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("input", closeInput).Msg("error closing account")
+		return nil, ErrAccountChangeStatus
+	}
+
+	account, _ := data.(*model.Account)
+	log.Ctx(ctx).Info().Str("accountID", string(account.ID)).Str("sweepAccountID", closeInput.SweepAccountID).Int64("sweptAmount", int64(sweptAmount)).Str("by", string(caller.AccountID)).Msg("account closed")
+
+	return newAccountStatusOutput(account, sweptAmount), nil
+}
+
+// lockClosingAccounts locks the closing account and, if informed, the sweep account.
+func (accUC accountUseCase) lockClosingAccounts(ctx context.Context, closeInput AccountCloseInput) (account *model.Account, sweepAccount *model.Account, err error) {
+	if closeInput.SweepAccountID == "" {
+		account, err = accUC.accRepo.GetBalanceForUpdate(ctx, model.AccountID(closeInput.AccountID))
+		return account, nil, err
+	}
+
+	return lockAccountPair(ctx, accUC.accRepo, model.AccountID(closeInput.AccountID), model.AccountID(closeInput.SweepAccountID))
+}
+
+// sweepBalance posts the remaining balance of the closing account to the sweep account, returning the swept amount.
+func (accUC accountUseCase) sweepBalance(ctx context.Context, account *model.Account, sweepAccount *model.Account) (model.Money, error) {
+	if account.Balance < 0 {
+		return 0, ErrAccountCloseBalanceNegative
+	}
+	if account.Balance == 0 {
+		return 0, nil
+	}
+	if sweepAccount == nil {
+		return 0, ErrAccountCloseBalanceNotZero
+	}
+	if !sweepAccount.IsActive() || sweepAccount.Currency != account.Currency {
+		return 0, ErrAccountSweepAccountInvalid
+	}
+
+	posting := model.NewLedgerPosting(
+		model.LedgerPostingAccountClosure,
+		string(account.ID),
+		account.ID,
+		sweepAccount.ID,
+		account.Balance,
+		account.Currency)
+
+	err := accUC.ledgerRepo.Post(ctx, posting)
+	if err != nil {
+		return 0, err
+	}
+
+	sweptAmount := account.Balance
+	account.Balance = 0
+
+	return sweptAmount, nil
+}
+
+// updateStatus checks the account can go to the new status and saves it.
+func (accUC accountUseCase) updateStatus(ctx context.Context, account *model.Account, status model.AccountStatus, reason string) error {
+	if !account.CanChangeStatusTo(status) {
+		return ErrAccountStatusChangeInvalid
+	}
+
+	account.ChangeStatus(status, reason)
+
+	return accUC.accRepo.UpdateStatus(ctx, account)
+}
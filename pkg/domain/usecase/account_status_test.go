@@ -0,0 +1,417 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_accountUseCase_Block(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	admin := model.Principal{AccountID: "admin-uuid", Scopes: []model.Scope{model.ScopeAccountsWrite}}
+
+	ledgerRepo := mock.LedgerRepository{
+		OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+			return txFunc(ctx)
+		},
+	}
+	accountWithStatus := func(status model.AccountStatus) func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+		return func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			return &model.Account{ID: id, Status: status}, nil
+		}
+	}
+	updateStatusOK := func(ctx context.Context, account *model.Account) error {
+		return nil
+	}
+
+	type fields struct {
+		accRepo repository.AccountRepository
+	}
+	type args struct {
+		ctx         context.Context
+		caller      model.Principal
+		statusInput AccountStatusInput
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus model.AccountStatus
+		wantErr    error
+	}{
+		{
+			name: "caller without scope should return forbidden",
+			fields: fields{
+				accRepo: mock.AccountRepository{},
+			},
+			args: args{
+				ctx:         backgroundCtx,
+				caller:      model.Principal{AccountID: "uuid-1", Scopes: []model.Scope{model.ScopeAccountsRead}},
+				statusInput: AccountStatusInput{AccountID: "uuid-1", Reason: "any reason"},
+			},
+			wantErr: ErrAuthForbidden,
+		},
+		{
+			name: "blank reason should return error",
+			fields: fields{
+				accRepo: mock.AccountRepository{},
+			},
+			args: args{
+				ctx:         backgroundCtx,
+				caller:      admin,
+				statusInput: AccountStatusInput{AccountID: "uuid-1", Reason: "  "},
+			},
+			wantErr: ErrAccountStatusReasonRequired,
+		},
+		{
+			name: "not found account should return not found error",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+			},
+			args: args{
+				ctx:         backgroundCtx,
+				caller:      admin,
+				statusInput: AccountStatusInput{AccountID: "uuid-1", Reason: "suspected fraud"},
+			},
+			wantErr: repository.ErrAccountNotFound,
+		},
+		{
+			name: "closed account should return invalid status change",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalanceForUpdate: accountWithStatus(model.AccountStatusClosed),
+				},
+			},
+			args: args{
+				ctx:         backgroundCtx,
+				caller:      admin,
+				statusInput: AccountStatusInput{AccountID: "uuid-1", Reason: "suspected fraud"},
+			},
+			wantErr: ErrAccountStatusChangeInvalid,
+		},
+		{
+			name: "repo update error should return error",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalanceForUpdate: accountWithStatus(model.AccountStatusActive),
+					OnUpdateStatus: func(ctx context.Context, account *model.Account) error {
+						return errors.New("any database error")
+					},
+				},
+			},
+			args: args{
+				ctx:         backgroundCtx,
+				caller:      admin,
+				statusInput: AccountStatusInput{AccountID: "uuid-1", Reason: "suspected fraud"},
+			},
+			wantErr: ErrAccountChangeStatus,
+		},
+		{
+			name: "active account should be blocked",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalanceForUpdate: accountWithStatus(model.AccountStatusActive),
+					OnUpdateStatus:        updateStatusOK,
+				},
+			},
+			args: args{
+				ctx:         backgroundCtx,
+				caller:      admin,
+				statusInput: AccountStatusInput{AccountID: "uuid-1", Reason: " suspected fraud "},
+			},
+			wantStatus: model.AccountStatusBlocked,
+			wantErr:    nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			accUC := NewAccountUseCase(tt.fields.accRepo, ledgerRepo)
+			got, err := accUC.Block(tt.args.ctx, tt.args.caller, tt.args.statusInput)
+			if err != tt.wantErr {
+				t.Errorf("Block() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if got.ID != tt.args.statusInput.AccountID || got.Status != string(tt.wantStatus) || got.StatusReason != "suspected fraud" {
+				t.Errorf("Block() got = %v, want status %v", got, tt.wantStatus)
+			}
+			if got.StatusChangedAt.Before(time.Now().Add(-5 * time.Second)) {
+				t.Errorf("Block() got = %v, want StatusChangedAt in the last 5 seconds", got)
+			}
+		})
+	}
+}
+
+func Test_accountUseCase_Unblock(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	admin := model.Principal{AccountID: "admin-uuid", Scopes: []model.Scope{model.ScopeAccountsWrite}}
+
+	ledgerRepo := mock.LedgerRepository{
+		OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+			return txFunc(ctx)
+		},
+	}
+
+	tests := []struct {
+		name       string
+		status     model.AccountStatus
+		wantStatus model.AccountStatus
+		wantErr    error
+	}{
+		{
+			name:       "blocked account should be active",
+			status:     model.AccountStatusBlocked,
+			wantStatus: model.AccountStatusActive,
+			wantErr:    nil,
+		},
+		{
+			name:    "active account should return invalid status change",
+			status:  model.AccountStatusActive,
+			wantErr: ErrAccountStatusChangeInvalid,
+		},
+		{
+			name:    "closed account should return invalid status change",
+			status:  model.AccountStatusClosed,
+			wantErr: ErrAccountStatusChangeInvalid,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			accRepo := mock.AccountRepository{
+				OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+					return &model.Account{ID: id, Status: tt.status}, nil
+				},
+				OnUpdateStatus: func(ctx context.Context, account *model.Account) error {
+					return nil
+				},
+			}
+
+			accUC := NewAccountUseCase(accRepo, ledgerRepo)
+			got, err := accUC.Unblock(backgroundCtx, admin, AccountStatusInput{AccountID: "uuid-1", Reason: "fraud cleared"})
+			if err != tt.wantErr {
+				t.Errorf("Unblock() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Status != string(tt.wantStatus) {
+				t.Errorf("Unblock() got = %v, want status %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_accountUseCase_Close(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	admin := model.Principal{AccountID: "admin-uuid", Scopes: []model.Scope{model.ScopeAccountsWrite}}
+
+	accounts := func(closing *model.Account, sweep *model.Account) mock.AccountRepository {
+		find := func(id model.AccountID) (*model.Account, error) {
+			if closing != nil && id == closing.ID {
+				return closing, nil
+			}
+			if sweep != nil && id == sweep.ID {
+				return sweep, nil
+			}
+
+			return nil, repository.ErrAccountNotFound
+		}
+
+		return mock.AccountRepository{
+			OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+				return find(id)
+			},
+			OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+				return find(id)
+			},
+			OnUpdateStatus: func(ctx context.Context, account *model.Account) error {
+				return nil
+			},
+		}
+	}
+
+	type args struct {
+		caller     model.Principal
+		closeInput AccountCloseInput
+	}
+	tests := []struct {
+		name            string
+		accRepo         mock.AccountRepository
+		args            args
+		wantSweptAmount model.Money
+		wantPosting     bool
+		wantErr         error
+	}{
+		{
+			name:    "caller without scope should return forbidden",
+			accRepo: mock.AccountRepository{},
+			args: args{
+				caller:     model.Principal{AccountID: "uuid-1"},
+				closeInput: AccountCloseInput{AccountID: "uuid-1", Reason: "any reason"},
+			},
+			wantErr: ErrAuthForbidden,
+		},
+		{
+			name:    "sweep to itself should return error",
+			accRepo: mock.AccountRepository{},
+			args: args{
+				caller:     admin,
+				closeInput: AccountCloseInput{AccountID: "uuid-1", Reason: "any reason", SweepAccountID: "uuid-1"},
+			},
+			wantErr: ErrAccountSweepAccountInvalid,
+		},
+		{
+			name:    "zero balance should be closed",
+			accRepo: accounts(&model.Account{ID: "uuid-1", Status: model.AccountStatusActive}, nil),
+			args: args{
+				caller:     admin,
+				closeInput: AccountCloseInput{AccountID: "uuid-1", Reason: "requested by the holder"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "blocked account should be closed",
+			accRepo: accounts(&model.Account{ID: "uuid-1", Status: model.AccountStatusBlocked}, nil),
+			args: args{
+				caller:     admin,
+				closeInput: AccountCloseInput{AccountID: "uuid-1", Reason: "fraud confirmed"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "closed account should return invalid status change",
+			accRepo: accounts(&model.Account{ID: "uuid-1", Status: model.AccountStatusClosed}, nil),
+			args: args{
+				caller:     admin,
+				closeInput: AccountCloseInput{AccountID: "uuid-1", Reason: "requested by the holder"},
+			},
+			wantErr: ErrAccountStatusChangeInvalid,
+		},
+		{
+			name:    "positive balance without sweep account should return error",
+			accRepo: accounts(&model.Account{ID: "uuid-1", Balance: 100, Status: model.AccountStatusActive}, nil),
+			args: args{
+				caller:     admin,
+				closeInput: AccountCloseInput{AccountID: "uuid-1", Reason: "requested by the holder"},
+			},
+			wantErr: ErrAccountCloseBalanceNotZero,
+		},
+		{
+			name:    "negative balance should return error",
+			accRepo: accounts(&model.Account{ID: "uuid-1", Balance: -100, Status: model.AccountStatusActive}, &model.Account{ID: "uuid-2", Status: model.AccountStatusActive}),
+			args: args{
+				caller:     admin,
+				closeInput: AccountCloseInput{AccountID: "uuid-1", Reason: "requested by the holder", SweepAccountID: "uuid-2"},
+			},
+			wantErr: ErrAccountCloseBalanceNegative,
+		},
+		{
+			name:    "not found sweep account should return error",
+			accRepo: accounts(&model.Account{ID: "uuid-1", Balance: 100, Status: model.AccountStatusActive}, nil),
+			args: args{
+				caller:     admin,
+				closeInput: AccountCloseInput{AccountID: "uuid-1", Reason: "requested by the holder", SweepAccountID: "uuid-2"},
+			},
+			wantErr: ErrAccountSweepAccountInvalid,
+		},
+		{
+			name:    "sweep account of another currency should return error",
+			accRepo: accounts(&model.Account{ID: "uuid-1", Currency: model.CurrencyBRL, Balance: 100, Status: model.AccountStatusActive}, &model.Account{ID: "uuid-2", Currency: model.CurrencyUSD, Status: model.AccountStatusActive}),
+			args: args{
+				caller:     admin,
+				closeInput: AccountCloseInput{AccountID: "uuid-1", Reason: "requested by the holder", SweepAccountID: "uuid-2"},
+			},
+			wantErr: ErrAccountSweepAccountInvalid,
+		},
+		{
+			name:    "blocked sweep account should return error",
+			accRepo: accounts(&model.Account{ID: "uuid-1", Balance: 100, Status: model.AccountStatusActive}, &model.Account{ID: "uuid-2", Status: model.AccountStatusBlocked}),
+			args: args{
+				caller:     admin,
+				closeInput: AccountCloseInput{AccountID: "uuid-1", Reason: "requested by the holder", SweepAccountID: "uuid-2"},
+			},
+			wantErr: ErrAccountSweepAccountInvalid,
+		},
+		{
+			name:    "positive balance should be swept to the sweep account",
+			accRepo: accounts(&model.Account{ID: "uuid-1", Balance: 100, Status: model.AccountStatusActive}, &model.Account{ID: "uuid-2", Status: model.AccountStatusActive}),
+			args: args{
+				caller:     admin,
+				closeInput: AccountCloseInput{AccountID: "uuid-1", Reason: "requested by the holder", SweepAccountID: "uuid-2"},
+			},
+			wantSweptAmount: 100,
+			wantPosting:     true,
+			wantErr:         nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotPosting *model.LedgerPosting
+			ledgerRepo := mock.LedgerRepository{
+				OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+					return txFunc(ctx)
+				},
+				OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+					gotPosting = posting
+					return nil
+				},
+			}
+
+			accUC := NewAccountUseCase(tt.accRepo, ledgerRepo)
+			got, err := accUC.Close(backgroundCtx, tt.args.caller, tt.args.closeInput)
+			if err != tt.wantErr {
+				t.Errorf("Close() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Status != string(model.AccountStatusClosed) {
+				t.Errorf("Close() got = %v, want closed", got)
+			}
+
+			if tt.wantSweptAmount == 0 && got.SweptAmount != nil {
+				t.Errorf("Close() got swept amount = %v, want none", got.SweptAmount)
+			}
+			if tt.wantSweptAmount != 0 && (got.SweptAmount == nil || got.SweptAmount.Money != tt.wantSweptAmount) {
+				t.Errorf("Close() got swept amount = %v, want %v", got.SweptAmount, tt.wantSweptAmount)
+			}
+
+			if (gotPosting != nil) != tt.wantPosting {
+				t.Errorf("Close() posting = %v, wantPosting %v", gotPosting, tt.wantPosting)
+			}
+			if gotPosting != nil {
+				if gotPosting.Kind != model.LedgerPostingAccountClosure || gotPosting.DebitAccountID != "uuid-1" ||
+					gotPosting.CreditAccountID != "uuid-2" || gotPosting.Amount != tt.wantSweptAmount {
+					t.Errorf("Close() posting = %v, want closure from uuid-1 to uuid-2", gotPosting)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrAccountTierInvalid happens when the tier is not one of model.AccountTier.
+	ErrAccountTierInvalid = errors.New("'tier' must be one of standard and premium")
+	// ErrAccountSetTier happens when an error occurred and the tier was not set.
+	ErrAccountSetTier = errors.New("could not set account tier")
+)
+
+// AccountTierInput represents the expected input data when setting the tier of an account.
+type AccountTierInput struct {
+	AccountID string `json:"-"`
+	Tier      string `json:"tier" example:"premium" enums:"standard,premium"`
+}
+
+// Validate validates the AccountTierInput fields, normalizing the tier.
+func (input *AccountTierInput) Validate() error {
+	input.Tier = strings.ToLower(strings.TrimSpace(input.Tier))
+	if !model.AccountTier(input.Tier).IsValid() {
+		return ErrAccountTierInvalid
+	}
+
+	return nil
+}
+
+// AccountTierOutput represents the output data of the SetTier method.
+type AccountTierOutput struct {
+	ID   string `json:"id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	Tier string `json:"tier" example:"premium"`
+}
+
+// SetTier sets the tier of the account, which tells the fees it pays from then on.
+// Only callers with the model.ScopeAccountsWrite can set tiers, otherwise it returns ErrAuthForbidden.
+func (accUC accountUseCase) SetTier(ctx context.Context, caller model.Principal, tierInput AccountTierInput) (*AccountTierOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if !caller.HasScope(model.ScopeAccountsWrite) {
+		return nil, ErrAuthForbidden
+	}
+
+	err := tierInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", tierInput).Msg("account tier input is not valid")
+		return nil, err
+	}
+
+	data, err := accUC.ledgerRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		account, err := accUC.accRepo.GetBalanceForUpdate(txCtx, model.AccountID(tierInput.AccountID))
+		if err != nil {
+			return nil, err
+		}
+
+		account.Tier = model.AccountTier(tierInput.Tier)
+		return account, accUC.accRepo.UpdateTier(txCtx, account)
+	})
+	if err != nil {
+		if err == repository.ErrAccountNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("input", tierInput).Msg("error setting account tier")
+		return nil, ErrAccountSetTier
+	}
+
+	account, _ := data.(*model.Account)
+	log.Ctx(ctx).Info().Str("accountID", string(account.ID)).Str("tier", string(account.Tier)).Str("by", string(caller.AccountID)).Msg("account tier set")
+
+	return &AccountTierOutput{
+		ID:   string(account.ID),
+		Tier: string(account.Tier),
+	}, nil
+}
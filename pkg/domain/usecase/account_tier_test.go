@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_accountUseCase_SetTier(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	admin := model.Principal{AccountID: "admin-uuid", Scopes: []model.Scope{model.ScopeAccountsWrite}}
+
+	ledgerRepo := mock.LedgerRepository{
+		OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+			return txFunc(ctx)
+		},
+	}
+	getAccountOK := func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+		return &model.Account{ID: id, Tier: model.AccountTierStandard, Status: model.AccountStatusActive}, nil
+	}
+
+	tests := []struct {
+		name      string
+		accRepo   repository.AccountRepository
+		caller    model.Principal
+		tierInput AccountTierInput
+		want      *AccountTierOutput
+		wantErr   error
+	}{
+		{
+			name:      "caller without scope should return forbidden",
+			accRepo:   mock.AccountRepository{},
+			caller:    model.Principal{AccountID: "uuid-1", Scopes: []model.Scope{model.ScopeAccountsRead}},
+			tierInput: AccountTierInput{AccountID: "uuid-1", Tier: "premium"},
+			wantErr:   ErrAuthForbidden,
+		},
+		{
+			name:      "unknown tier should return error",
+			accRepo:   mock.AccountRepository{},
+			caller:    admin,
+			tierInput: AccountTierInput{AccountID: "uuid-1", Tier: "gold"},
+			wantErr:   ErrAccountTierInvalid,
+		},
+		{
+			name: "not found account should return not found error",
+			accRepo: mock.AccountRepository{
+				OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+					return nil, repository.ErrAccountNotFound
+				},
+			},
+			caller:    admin,
+			tierInput: AccountTierInput{AccountID: "uuid-9", Tier: "premium"},
+			wantErr:   repository.ErrAccountNotFound,
+		},
+		{
+			name: "repo update error should return error",
+			accRepo: mock.AccountRepository{
+				OnGetBalanceForUpdate: getAccountOK,
+				OnUpdateTier: func(ctx context.Context, account *model.Account) error {
+					return errors.New("any database error")
+				},
+			},
+			caller:    admin,
+			tierInput: AccountTierInput{AccountID: "uuid-1", Tier: "premium"},
+			wantErr:   ErrAccountSetTier,
+		},
+		{
+			name: "success with uppercase tier",
+			accRepo: mock.AccountRepository{
+				OnGetBalanceForUpdate: getAccountOK,
+				OnUpdateTier: func(ctx context.Context, account *model.Account) error {
+					if account.ID != "uuid-1" || account.Tier != model.AccountTierPremium {
+						return errors.New("should update the account with the new tier")
+					}
+					return nil
+				},
+			},
+			caller:    admin,
+			tierInput: AccountTierInput{AccountID: "uuid-1", Tier: " PREMIUM "},
+			want:      &AccountTierOutput{ID: "uuid-1", Tier: "premium"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			accUC := NewAccountUseCase(tt.accRepo, ledgerRepo)
+
+			got, err := accUC.SetTier(backgroundCtx, tt.caller, tt.tierInput)
+			if err != tt.wantErr {
+				t.Errorf("SetTier() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetTier() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
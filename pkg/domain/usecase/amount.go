@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+var (
+	// ErrAmountInvalid happens when an input amount is not a decimal number with at most the decimal places of its
+	// currency.
+	ErrAmountInvalid = errors.New("amounts must be decimal numbers or strings with at most the decimal places of their currency, like \"1234.56\"")
+)
+
+// Amount represents a monetary amount in the inputs and outputs, in the minor unit of its currency.
+//
+// It is read from a decimal string, like "1234.56", or from a JSON number (deprecated) without loss of precision,
+// with up to model.MaxMinorUnits decimal places. The currency is only known later, so it's read in cents, rounded
+// away from zero to keep its sign, until In rescales it to the minor unit of the currency.
+// It is written as a JSON number (deprecated), unless UseDecimalString is called, then it is written as a decimal string,
+// with the decimal places of its currency, or of model.DefaultCurrency when it has none.
+type Amount struct {
+	model.Money
+	currency      model.Currency
+	decimal       string
+	decimalString bool
+}
+
+// NewAmount returns a new Amount with the value of money, in cents.
+func NewAmount(money model.Money) Amount {
+	return Amount{Money: money}
+}
+
+// NewAmountIn returns a new Amount with the value of money, in the minor unit of the currency.
+func NewAmountIn(money model.Money, currency model.Currency) Amount {
+	return Amount{Money: money, currency: currency}
+}
+
+// ParseAmount returns the Amount of the decimal string, like "1234.56", read like the JSON ones.
+func ParseAmount(s string) (Amount, error) {
+	money, err := model.ParseMoneyAwayFromZero(s)
+	if err != nil {
+		return Amount{}, ErrAmountInvalid
+	}
+
+	return Amount{Money: money, decimal: s}, nil
+}
+
+// In rescales the amount read from the input to the minor unit of the currency and returns it, returning
+// ErrAmountInvalid if it has more decimal places than the currency. The amount is then written in the currency too.
+// The amounts not read from an input are already in the minor unit of the currency.
+func (a *Amount) In(currency model.Currency) (model.Money, error) {
+	if a.decimal != "" {
+		money, err := model.ParseMoneyIn(a.decimal, currency)
+		if err != nil {
+			return 0, ErrAmountInvalid
+		}
+		a.Money = money
+	}
+
+	a.currency = currency
+	return a.Money, nil
+}
+
+// UseDecimalString makes the Amount be written as a decimal string.
+func (a *Amount) UseDecimalString() {
+	a.decimalString = true
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	currency := a.currency
+	if currency == "" {
+		currency = model.DefaultCurrency
+	}
+
+	if a.decimalString {
+		return []byte(strconv.Quote(a.Money.Format(currency))), nil
+	}
+
+	return []byte(strconv.FormatFloat(a.Money.Float64In(currency), 'f', -1, 64)), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	value := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		var err error
+		value, err = strconv.Unquote(value)
+		if err != nil {
+			return ErrAmountInvalid
+		}
+	}
+
+	amount, err := ParseAmount(value)
+	if err != nil {
+		return err
+	}
+
+	*a = amount
+	return nil
+}
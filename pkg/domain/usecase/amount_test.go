@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+func TestAmount_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		want    model.Money
+		wantErr error
+	}{
+		{
+			name: "number should be exact",
+			data: `0.29`,
+			want: 29,
+		},
+		{
+			name: "integer number",
+			data: `10`,
+			want: 1000,
+		},
+		{
+			name: "decimal string",
+			data: `"1123452.95"`,
+			want: 112345295,
+		},
+		{
+			name: "negative decimal string",
+			data: `"-0.5"`,
+			want: -50,
+		},
+		{
+			name: "null should be zero",
+			data: `null`,
+			want: 0,
+		},
+		{
+			name: "sub-cent number should keep its sign",
+			data: `0.001`,
+			want: 1,
+		},
+		{
+			name: "negative sub-cent string should keep its sign",
+			data: `"-0.001"`,
+			want: -1,
+		},
+		{
+			name:    "more decimal places than any currency should return error",
+			data:    `0.0001`,
+			wantErr: ErrAmountInvalid,
+		},
+		{
+			name:    "non numeric string should return error",
+			data:    `"ten"`,
+			wantErr: ErrAmountInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Amount
+			err := got.UnmarshalJSON([]byte(tt.data))
+			if err != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.Money != tt.want {
+				t.Errorf("UnmarshalJSON() = %v, want %v", got.Money, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmount_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		amount        Amount
+		decimalString bool
+		want          string
+	}{
+		{
+			name:   "number",
+			amount: NewAmount(29),
+			want:   `0.29`,
+		},
+		{
+			name:   "integer number",
+			amount: NewAmount(1000),
+			want:   `10`,
+		},
+		{
+			name:          "decimal string",
+			amount:        NewAmount(1000),
+			decimalString: true,
+			want:          `"10.00"`,
+		},
+		{
+			name:          "negative decimal string",
+			amount:        NewAmount(-29),
+			decimalString: true,
+			want:          `"-0.29"`,
+		},
+		{
+			name:   "number of a currency without minor unit",
+			amount: NewAmountIn(1234, model.CurrencyJPY),
+			want:   `1234`,
+		},
+		{
+			name:          "decimal string of a currency without minor unit",
+			amount:        NewAmountIn(1234, model.CurrencyJPY),
+			decimalString: true,
+			want:          `"1234"`,
+		},
+		{
+			name:   "number of a currency with 3 decimal places",
+			amount: NewAmountIn(1234, model.CurrencyKWD),
+			want:   `1.234`,
+		},
+		{
+			name:          "decimal string of a currency with 3 decimal places",
+			amount:        NewAmountIn(1230, model.CurrencyKWD),
+			decimalString: true,
+			want:          `"1.230"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.decimalString {
+				tt.amount.UseDecimalString()
+			}
+
+			got, err := json.Marshal(tt.amount)
+			if err != nil {
+				t.Errorf("MarshalJSON() error = %v", err)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// amountPtrIn returns the optional amount of an output, in the currency.
+func amountPtrIn(money model.Money, currency model.Currency) *Amount {
+	amount := NewAmountIn(money, currency)
+	return &amount
+}
+
+func TestAmount_In(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		data     string
+		amount   Amount
+		currency model.Currency
+		want     model.Money
+		wantJSON string
+		wantErr  error
+	}{
+		{
+			name:     "cents",
+			data:     `"1234.56"`,
+			currency: model.CurrencyUSD,
+			want:     123456,
+			wantJSON: `1234.56`,
+		},
+		{
+			name:     "currency without minor unit",
+			data:     `1234`,
+			currency: model.CurrencyJPY,
+			want:     1234,
+			wantJSON: `1234`,
+		},
+		{
+			name:     "decimal places of a currency without minor unit should return error",
+			data:     `"1234.5"`,
+			currency: model.CurrencyJPY,
+			wantErr:  ErrAmountInvalid,
+		},
+		{
+			name:     "currency with 3 decimal places",
+			data:     `"0.001"`,
+			currency: model.CurrencyKWD,
+			want:     1,
+			wantJSON: `0.001`,
+		},
+		{
+			name:     "sub-cent of a currency with 2 decimal places should return error",
+			data:     `0.001`,
+			currency: model.CurrencyBRL,
+			wantErr:  ErrAmountInvalid,
+		},
+		{
+			name:     "amount not read from an input should be kept",
+			amount:   NewAmount(1234),
+			currency: model.CurrencyKWD,
+			want:     1234,
+			wantJSON: `1.234`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			amount := tt.amount
+			if tt.data != "" {
+				if err := json.Unmarshal([]byte(tt.data), &amount); err != nil {
+					t.Fatalf("UnmarshalJSON() error = %v", err)
+				}
+			}
+
+			got, err := amount.In(tt.currency)
+			if err != tt.wantErr {
+				t.Errorf("In() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want || amount.Money != tt.want {
+				t.Errorf("In() = %v and Money %v, want %v", got, amount.Money, tt.want)
+			}
+
+			gotJSON, err := json.Marshal(amount)
+			if err != nil || string(gotJSON) != tt.wantJSON {
+				t.Errorf("MarshalJSON() = %s, error = %v, want %s", gotJSON, err, tt.wantJSON)
+			}
+		})
+	}
+}
@@ -4,32 +4,50 @@ import (
 	"context"
 	"time"
 
-	"github.com/golang-jwt/jwt/v4"
-
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
 )
 
 // AuthUseCase is the interface that wraps all business logic methods related to authentication.
 type AuthUseCase interface {
 	Login(ctx context.Context, loginInput AuthLoginInput) (*AuthTokenOutput, error)
-	Authorize(ctx context.Context, accessToken string) (*jwt.RegisteredClaims, error)
+	Refresh(ctx context.Context, refreshInput AuthRefreshInput) (*AuthTokenOutput, error)
+	Logout(ctx context.Context, logoutInput AuthLogoutInput) error
+	Authorize(ctx context.Context, accessToken string) (*AuthClaims, error)
+	JWKS() *JWKSOutput
 }
 
 type authUseCase struct {
-	secretKey      string
-	accessTokenDur time.Duration
-	accRepo        repository.AccountRepository
+	keySet          *AuthKeySet
+	accessTokenDur  time.Duration
+	refreshTokenDur time.Duration
+	lockoutPolicy   LoginLockoutPolicy
+	accRepo         repository.AccountRepository
+	tknRepo         repository.TokenRepository
+	attRepo         repository.LoginAttemptRepository
 }
 
 // NewAuthUseCase instantiates a new AuthUseCase.
 func NewAuthUseCase(
-	secretKey string,
+	keySet *AuthKeySet,
 	accessTokenDur time.Duration,
+	refreshTokenDur time.Duration,
+	lockoutPolicy LoginLockoutPolicy,
 	accRepo repository.AccountRepository,
+	tknRepo repository.TokenRepository,
+	attRepo repository.LoginAttemptRepository,
 ) AuthUseCase {
 	return &authUseCase{
-		secretKey:      secretKey,
-		accessTokenDur: accessTokenDur,
-		accRepo:        accRepo,
+		keySet:          keySet,
+		accessTokenDur:  accessTokenDur,
+		refreshTokenDur: refreshTokenDur,
+		lockoutPolicy:   lockoutPolicy,
+		accRepo:         accRepo,
+		tknRepo:         tknRepo,
+		attRepo:         attRepo,
 	}
 }
+
+// JWKS returns the public keys used to verify the access tokens.
+func (authUC authUseCase) JWKS() *JWKSOutput {
+	return authUC.keySet.JWKS()
+}
@@ -3,43 +3,91 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 )
 
 var (
 	// ErrAuthInvalidAccessToken happens when the JWT token is invalid or expired.
 	ErrAuthInvalidAccessToken = errors.New("invalid access token")
+	// ErrAuthAuthorize happens when an error occurred while checking if the access token was revoked.
+	ErrAuthAuthorize = errors.New("could not authorize")
+	// ErrAuthForbidden happens when the authenticated account is not allowed to perform the operation.
+	ErrAuthForbidden = errors.New("forbidden")
 )
 
-// Authorize parses and verifies JWT token.
-func (authUC authUseCase) Authorize(ctx context.Context, accessToken string) (*jwt.RegisteredClaims, error) {
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
+// AuthClaims represents the access token claims.
+// Scope holds the space-separated scopes granted by the roles, like the OAuth 2.0 `scope` claim.
+type AuthClaims struct {
+	jwt.RegisteredClaims
+	Roles []model.Role `json:"roles,omitempty"`
+	Scope string       `json:"scope,omitempty"`
+}
 
-	token, err := jwt.ParseWithClaims(
-		accessToken,
-		&jwt.RegisteredClaims{},
-		func(token *jwt.Token) (interface{}, error) {
-			_, ok := token.Method.(*jwt.SigningMethodHMAC)
-			if !ok {
-				return nil, errors.New("unexpected token signing method")
-			}
+func newAuthClaims(account *model.Account, tokenID string, issuedAt time.Time, expiresAt time.Time) AuthClaims {
+	scopes := make([]string, 0)
+	for _, scope := range model.ScopesOf(account.Roles) {
+		scopes = append(scopes, string(scope))
+	}
 
-			return []byte(authUC.secretKey), nil
+	return AuthClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   string(account.ID),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
-	)
+		Roles: account.Roles,
+		Scope: strings.Join(scopes, " "),
+	}
+}
+
+// Principal returns the account authenticated by the claims.
+func (c *AuthClaims) Principal() model.Principal {
+	var scopes []model.Scope
+	for _, scope := range strings.Fields(c.Scope) {
+		scopes = append(scopes, model.Scope(scope))
+	}
+
+	return model.Principal{
+		AccountID: model.AccountID(c.Subject),
+		Roles:     c.Roles,
+		Scopes:    scopes,
+	}
+}
+
+// Authorize parses and verifies JWT token, rejecting tokens revoked by logout.
+func (authUC authUseCase) Authorize(ctx context.Context, accessToken string) (*AuthClaims, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	token, err := jwt.ParseWithClaims(accessToken, &AuthClaims{}, authUC.keySet.verificationKey)
 	if err != nil {
 		log.Ctx(ctx).Err(err).Str("accessToken", accessToken).Msg("error parsing access token")
 		return nil, ErrAuthInvalidAccessToken
 	}
 
-	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	claims, ok := token.Claims.(*AuthClaims)
 	if !ok {
 		return nil, ErrAuthInvalidAccessToken
 	}
 
+	// tokens without ID were issued before logout existed and can't be revoked
+	if claims.ID != "" {
+		denied, err := authUC.tknRepo.IsAccessTokenDenied(ctx, claims.ID)
+		if err != nil {
+			log.Ctx(ctx).Error().Stack().Err(err).Str("tokenID", claims.ID).Msg("error checking access token deny-list")
+			return nil, ErrAuthAuthorize
+		}
+		if denied {
+			return nil, ErrAuthInvalidAccessToken
+		}
+	}
+
 	return claims, nil
 }
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// AuthKey is a key used to sign and/or verify JWT access tokens.
+type AuthKey struct {
+	ID        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// NewHMACAuthKey returns the legacy HS256 key. It has no ID, so it only verifies tokens without the `kid` header.
+// Being symmetric, it's never published in the JWKS.
+func NewHMACAuthKey(secret string) *AuthKey {
+	return &AuthKey{
+		ID:        "",
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+}
+
+// NewAuthKeyFromPEM parses a RSA, ECDSA or Ed25519 key from PEM.
+// A private key can sign and verify tokens, a public key can only verify them.
+// The signing algorithm is derived from the key type: RS256, ES256/ES384/ES512 or EdDSA.
+func NewAuthKeyFromPEM(id string, pemBytes []byte) (*AuthKey, error) {
+	if id == "" {
+		return nil, errors.New("auth key id is required")
+	}
+
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("auth key %q: no PEM data found", id)
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("auth key %q: unsupported PEM type %q", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("auth key %q: %w", id, err)
+	}
+
+	key := &AuthKey{ID: id}
+	if signer, ok := parsed.(crypto.Signer); ok {
+		key.signKey = signer
+		parsed = signer.Public()
+	}
+	key.verifyKey = parsed
+
+	switch pub := parsed.(type) {
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			key.method = jwt.SigningMethodES256
+		case elliptic.P384():
+			key.method = jwt.SigningMethodES384
+		case elliptic.P521():
+			key.method = jwt.SigningMethodES512
+		default:
+			return nil, fmt.Errorf("auth key %q: unsupported curve %s", id, pub.Curve.Params().Name)
+		}
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("auth key %q: unsupported key type %T", id, parsed)
+	}
+
+	return key, nil
+}
+
+// Algorithm returns the JWT `alg` of the key.
+func (k *AuthKey) Algorithm() string {
+	return k.method.Alg()
+}
+
+// CanSign checks whether it's a private key.
+func (k *AuthKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+// AuthKeySet holds the key used to sign new access tokens and all the keys accepted when verifying them.
+//
+// To rotate keys, add the new key, make it the signing key and keep the old one until
+// the tokens it signed have expired.
+type AuthKeySet struct {
+	signingKey *AuthKey
+	keys       map[string]*AuthKey
+}
+
+// NewAuthKeySet instantiates a new AuthKeySet signing with the key identified by signingKeyID.
+func NewAuthKeySet(signingKeyID string, keys ...*AuthKey) (*AuthKeySet, error) {
+	keySet := &AuthKeySet{
+		keys: make(map[string]*AuthKey, len(keys)),
+	}
+
+	for _, key := range keys {
+		if _, exists := keySet.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicated auth key %q", key.ID)
+		}
+		keySet.keys[key.ID] = key
+	}
+
+	signingKey, ok := keySet.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("signing auth key %q not found", signingKeyID)
+	}
+	if !signingKey.CanSign() {
+		return nil, fmt.Errorf("signing auth key %q is not a private key", signingKeyID)
+	}
+	keySet.signingKey = signingKey
+
+	return keySet, nil
+}
+
+// sign signs the claims with the signing key, setting the `kid` header.
+func (ks *AuthKeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signingKey.method, claims)
+	if ks.signingKey.ID != "" {
+		token.Header["kid"] = ks.signingKey.ID
+	}
+
+	return token.SignedString(ks.signingKey.signKey)
+}
+
+// verificationKey is a jwt.Keyfunc that picks the key by the `kid` header.
+// The token algorithm must match the key's one, so a public key can't be used as an HMAC secret.
+func (ks *AuthKeySet) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown token key id %q", kid)
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("unexpected token signing method")
+	}
+
+	return key.verifyKey, nil
+}
+
+// JWKOutput represents a public key in the JSON Web Key format (RFC 7517).
+type JWKOutput struct {
+	KeyType   string `json:"kty" example:"RSA"`
+	KeyID     string `json:"kid" example:"2022-01"`
+	Use       string `json:"use" example:"sig"`
+	Algorithm string `json:"alg" example:"RS256"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty" example:"AQAB"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKSOutput represents the JSON Web Key Set with the public keys used to verify access tokens.
+type JWKSOutput struct {
+	Keys []JWKOutput `json:"keys"`
+}
+
+// JWKS returns the public keys of the set, sorted by ID. Symmetric keys are never published.
+func (ks *AuthKeySet) JWKS() *JWKSOutput {
+	output := &JWKSOutput{
+		Keys: []JWKOutput{},
+	}
+
+	for _, key := range ks.keys {
+		jwk, ok := newJWKOutput(key)
+		if ok {
+			output.Keys = append(output.Keys, jwk)
+		}
+	}
+
+	sort.Slice(output.Keys, func(i, j int) bool {
+		return output.Keys[i].KeyID < output.Keys[j].KeyID
+	})
+
+	return output
+}
+
+func newJWKOutput(key *AuthKey) (JWKOutput, bool) {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	jwk := JWKOutput{
+		KeyID:     key.ID,
+		Use:       "sig",
+		Algorithm: key.Algorithm(),
+	}
+
+	switch pub := key.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encode(pub.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = pub.Curve.Params().Name
+		jwk.X = encode(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = encode(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encode(pub)
+	default:
+		return JWKOutput{}, false
+	}
+
+	return jwk, true
+}
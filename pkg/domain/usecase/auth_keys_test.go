@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func newTestHMACKeySet(t *testing.T, secretKey string) *AuthKeySet {
+	keySet, err := NewAuthKeySet("", NewHMACAuthKey(secretKey))
+	if err != nil {
+		t.Fatalf("error creating test key set: %v", err)
+	}
+
+	return keySet
+}
+
+func newTestPEMs(t *testing.T, privateKey interface{}, publicKey interface{}) (privatePEM []byte, publicPEM []byte) {
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("error marshaling test private key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatalf("error marshaling test public key: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+}
+
+func TestNewAuthKeyFromPEM(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaPrivatePEM, rsaPublicPEM := newTestPEMs(t, rsaKey, &rsaKey.PublicKey)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecPrivatePEM, ecPublicPEM := newTestPEMs(t, ecKey, &ecKey.PublicKey)
+	edPublicKey, edPrivateKey, _ := ed25519.GenerateKey(rand.Reader)
+	edPrivatePEM, edPublicPEM := newTestPEMs(t, edPrivateKey, edPublicKey)
+	pkcs1PEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	type args struct {
+		id       string
+		pemBytes []byte
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantAlg     string
+		wantCanSign bool
+		wantErr     bool
+	}{
+		{
+			name:        "RSA private key",
+			args:        args{id: "rsa", pemBytes: rsaPrivatePEM},
+			wantAlg:     "RS256",
+			wantCanSign: true,
+		},
+		{
+			name:        "RSA PKCS1 private key",
+			args:        args{id: "rsa", pemBytes: pkcs1PEM},
+			wantAlg:     "RS256",
+			wantCanSign: true,
+		},
+		{
+			name:        "RSA public key",
+			args:        args{id: "rsa", pemBytes: rsaPublicPEM},
+			wantAlg:     "RS256",
+			wantCanSign: false,
+		},
+		{
+			name:        "ECDSA P-256 private key",
+			args:        args{id: "ec", pemBytes: ecPrivatePEM},
+			wantAlg:     "ES256",
+			wantCanSign: true,
+		},
+		{
+			name:        "ECDSA P-256 public key",
+			args:        args{id: "ec", pemBytes: ecPublicPEM},
+			wantAlg:     "ES256",
+			wantCanSign: false,
+		},
+		{
+			name:        "Ed25519 private key",
+			args:        args{id: "ed", pemBytes: edPrivatePEM},
+			wantAlg:     "EdDSA",
+			wantCanSign: true,
+		},
+		{
+			name:        "Ed25519 public key",
+			args:        args{id: "ed", pemBytes: edPublicPEM},
+			wantAlg:     "EdDSA",
+			wantCanSign: false,
+		},
+		{
+			name:    "empty id should return error",
+			args:    args{id: "", pemBytes: rsaPrivatePEM},
+			wantErr: true,
+		},
+		{
+			name:    "not PEM should return error",
+			args:    args{id: "rsa", pemBytes: []byte("not a pem")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewAuthKeyFromPEM(tt.args.id, tt.args.pemBytes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewAuthKeyFromPEM() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Algorithm() != tt.wantAlg {
+				t.Errorf("NewAuthKeyFromPEM() Algorithm() = %v, want %v", got.Algorithm(), tt.wantAlg)
+			}
+			if got.CanSign() != tt.wantCanSign {
+				t.Errorf("NewAuthKeyFromPEM() CanSign() = %v, want %v", got.CanSign(), tt.wantCanSign)
+			}
+		})
+	}
+}
+
+func TestNewAuthKeySet(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, rsaPublicPEM := newTestPEMs(t, rsaKey, &rsaKey.PublicKey)
+	publicKey, _ := NewAuthKeyFromPEM("old", rsaPublicPEM)
+
+	tests := []struct {
+		name         string
+		signingKeyID string
+		keys         []*AuthKey
+		wantErr      bool
+	}{
+		{
+			name:         "success",
+			signingKeyID: "",
+			keys:         []*AuthKey{NewHMACAuthKey("secret"), publicKey},
+		},
+		{
+			name:         "unknown signing key should return error",
+			signingKeyID: "new",
+			keys:         []*AuthKey{NewHMACAuthKey("secret"), publicKey},
+			wantErr:      true,
+		},
+		{
+			name:         "public signing key should return error",
+			signingKeyID: "old",
+			keys:         []*AuthKey{publicKey},
+			wantErr:      true,
+		},
+		{
+			name:         "duplicated key id should return error",
+			signingKeyID: "",
+			keys:         []*AuthKey{NewHMACAuthKey("secret"), NewHMACAuthKey("other")},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAuthKeySet(tt.signingKeyID, tt.keys...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewAuthKeySet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthKeySet_rotation(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	oldKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	oldPrivatePEM, oldPublicPEM := newTestPEMs(t, oldKey, &oldKey.PublicKey)
+	_, newKey, _ := ed25519.GenerateKey(rand.Reader)
+	newPrivatePEM, _ := newTestPEMs(t, newKey, newKey.Public())
+
+	oldSigningKey, _ := NewAuthKeyFromPEM("2021", oldPrivatePEM)
+	oldSet, err := NewAuthKeySet("2021", oldSigningKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldVerifyingKey, _ := NewAuthKeyFromPEM("2021", oldPublicPEM)
+	newSigningKey, _ := NewAuthKeyFromPEM("2022", newPrivatePEM)
+	rotatedSet, err := NewAuthKeySet("2022", NewHMACAuthKey("legacy"), oldVerifyingKey, newSigningKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := jwt.RegisteredClaims{
+		Subject:   "uuid-1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+
+	oldToken, err := oldSet.sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newToken, err := rotatedSet.sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacyToken := newTestAccessToken(t, "legacy", claims)
+
+	// using the public key as HMAC secret must not work
+	confusedToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	confusedToken.Header["kid"] = "2021"
+	confusedTokenString, _ := confusedToken.SignedString(oldPublicPEM)
+
+	authUC := NewAuthUseCase(rotatedSet, time.Minute, time.Hour, LoginLockoutPolicy{}, nil, nil, nil)
+
+	tests := []struct {
+		name        string
+		accessToken string
+		wantErr     error
+	}{
+		{name: "token signed by the rotated key should be valid", accessToken: oldToken, wantErr: nil},
+		{name: "token signed by the new key should be valid", accessToken: newToken, wantErr: nil},
+		{name: "legacy HS256 token should be valid", accessToken: legacyToken, wantErr: nil},
+		{name: "HS256 token with asymmetric kid should be invalid", accessToken: confusedTokenString, wantErr: ErrAuthInvalidAccessToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := authUC.Authorize(backgroundCtx, tt.accessToken)
+			if err != tt.wantErr {
+				t.Errorf("Authorize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Subject != "uuid-1" {
+				t.Errorf("Authorize() got = %v, want subject %v", got, "uuid-1")
+			}
+		})
+	}
+
+	jwks := authUC.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("JWKS() got %d keys, want 2 without the HMAC key", len(jwks.Keys))
+	}
+	if jwks.Keys[0].KeyID != "2021" || jwks.Keys[0].KeyType != "EC" || jwks.Keys[0].Curve != "P-256" || len(jwks.Keys[0].X) != 43 {
+		t.Errorf("JWKS() got = %+v, want the P-256 key", jwks.Keys[0])
+	}
+	if jwks.Keys[1].KeyID != "2022" || jwks.Keys[1].KeyType != "OKP" || jwks.Keys[1].Algorithm != "EdDSA" {
+		t.Errorf("JWKS() got = %+v, want the Ed25519 key", jwks.Keys[1])
+	}
+}
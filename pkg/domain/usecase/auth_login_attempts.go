@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/infraestructure/monitoring"
+)
+
+var (
+	// ErrAuthTooManyLoginAttempts happens when the CPF or the client IP is locked out after too many failed login attempts.
+	ErrAuthTooManyLoginAttempts = errors.New("too many failed login attempts")
+)
+
+// AuthLoginLockedError is returned when the login is rejected because of a lockout.
+// It matches ErrAuthTooManyLoginAttempts with errors.Is.
+type AuthLoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *AuthLoginLockedError) Error() string {
+	return ErrAuthTooManyLoginAttempts.Error()
+}
+
+// Is makes errors.Is(err, ErrAuthTooManyLoginAttempts) true.
+func (e *AuthLoginLockedError) Is(target error) bool {
+	return target == ErrAuthTooManyLoginAttempts
+}
+
+// LoginLockoutPolicy defines when and for how long the failed login attempts lock out new attempts.
+//
+// After MaxFailuresPerCPF (or MaxFailuresPerIP) failures, each new failure locks out for BaseLockout,
+// doubled for every extra failure, up to MaxLockout.
+// The failures are forgotten after FailuresWindow without new failures.
+type LoginLockoutPolicy struct {
+	MaxFailuresPerCPF int
+	MaxFailuresPerIP  int
+	BaseLockout       time.Duration
+	MaxLockout        time.Duration
+	FailuresWindow    time.Duration
+}
+
+// lockoutDuration returns for how long to lock out after the given failures, or 0 if it shouldn't.
+func (p LoginLockoutPolicy) lockoutDuration(failures int, maxFailures int) time.Duration {
+	if maxFailures <= 0 || failures < maxFailures {
+		return 0
+	}
+
+	lockout := p.BaseLockout
+	for i := maxFailures; i < failures && lockout < p.MaxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > p.MaxLockout {
+		lockout = p.MaxLockout
+	}
+
+	return lockout
+}
+
+// failuresWindow returns the FailuresWindow, but never shorter than the MaxLockout,
+// so the failures are not forgotten while still locked out.
+func (p LoginLockoutPolicy) failuresWindow() time.Duration {
+	if p.FailuresWindow < p.MaxLockout {
+		return p.MaxLockout
+	}
+
+	return p.FailuresWindow
+}
+
+type loginAttemptKey struct {
+	scope       string
+	key         string
+	maxFailures int
+}
+
+func (authUC authUseCase) loginAttemptKeys(cpf model.CPF, clientIP string) []loginAttemptKey {
+	keys := []loginAttemptKey{
+		{scope: "cpf", key: "cpf:" + string(cpf), maxFailures: authUC.lockoutPolicy.MaxFailuresPerCPF},
+	}
+	if clientIP != "" {
+		keys = append(keys, loginAttemptKey{scope: "ip", key: "ip:" + clientIP, maxFailures: authUC.lockoutPolicy.MaxFailuresPerIP})
+	}
+
+	return keys
+}
+
+// checkLoginLockout returns an *AuthLoginLockedError if any of the keys is locked out.
+// The returned attempts are indexed like keys.
+func (authUC authUseCase) checkLoginLockout(ctx context.Context, keys []loginAttemptKey) ([]*model.LoginAttempts, error) {
+	now := time.Now()
+
+	var retryAfter time.Duration
+	allAttempts := make([]*model.LoginAttempts, len(keys))
+	for i, key := range keys {
+		attempts, err := authUC.attRepo.Get(ctx, key.key)
+		if err != nil {
+			log.Ctx(ctx).Error().Stack().Err(err).Str("scope", key.scope).Msg("error getting login attempts")
+			return nil, ErrAuthLogin
+		}
+		allAttempts[i] = attempts
+
+		if attempts.IsLocked(now) {
+			monitoring.LoginLockedRejections.WithLabelValues(key.scope).Inc()
+			if wait := attempts.LockedUntil.Sub(now); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+	}
+
+	if retryAfter > 0 {
+		return nil, &AuthLoginLockedError{RetryAfter: retryAfter}
+	}
+
+	return allAttempts, nil
+}
+
+// registerLoginFailure counts the failure for each key, locking it out if needed.
+// Errors are only logged, so the caller still gets the invalid credentials error.
+func (authUC authUseCase) registerLoginFailure(ctx context.Context, keys []loginAttemptKey) {
+	monitoring.LoginFailures.Inc()
+
+	for _, key := range keys {
+		failures, err := authUC.attRepo.RegisterFailure(ctx, key.key, authUC.lockoutPolicy.failuresWindow())
+		if err != nil {
+			log.Ctx(ctx).Error().Stack().Err(err).Str("scope", key.scope).Msg("error registering login failure")
+			continue
+		}
+
+		lockout := authUC.lockoutPolicy.lockoutDuration(failures, key.maxFailures)
+		if lockout <= 0 {
+			continue
+		}
+
+		lockedUntil := time.Now().Add(lockout)
+		err = authUC.attRepo.Lock(ctx, key.key, lockedUntil)
+		if err != nil {
+			log.Ctx(ctx).Error().Stack().Err(err).Str("scope", key.scope).Msg("error locking out login")
+			continue
+		}
+
+		monitoring.LoginLockouts.WithLabelValues(key.scope).Inc()
+		log.Ctx(ctx).Warn().Str("scope", key.scope).Str("key", key.key).Int("failures", failures).Time("lockedUntil", lockedUntil).Msg("login locked out after too many failed attempts")
+	}
+}
+
+// resetLoginFailures forgets the CPF failures after a successful login.
+// The client IP failures are kept, otherwise logging in to an own account would reset the guessing budget for the others.
+func (authUC authUseCase) resetLoginFailures(ctx context.Context, keys []loginAttemptKey, allAttempts []*model.LoginAttempts) {
+	for i, key := range keys {
+		if key.scope != "cpf" || allAttempts[i].Failures == 0 {
+			continue
+		}
+
+		err := authUC.attRepo.Reset(ctx, key.key)
+		if err != nil {
+			log.Ctx(ctx).Error().Stack().Err(err).Str("scope", key.scope).Msg("error resetting login failures")
+			continue
+		}
+
+		if !allAttempts[i].LockedUntil.IsZero() {
+			monitoring.LoginUnlocks.WithLabelValues(key.scope).Inc()
+			log.Ctx(ctx).Info().Str("scope", key.scope).Str("key", key.key).Int("failures", allAttempts[i].Failures).Msg("login unlocked after successful attempt")
+		}
+	}
+}
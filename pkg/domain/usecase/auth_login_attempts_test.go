@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+// newTestLoginAttemptRepository returns a mock keeping the attempts in a map.
+func newTestLoginAttemptRepository() (mock.LoginAttemptRepository, map[string]*model.LoginAttempts) {
+	var mu sync.Mutex
+	attempts := map[string]*model.LoginAttempts{}
+	getOrCreate := func(key string) *model.LoginAttempts {
+		if _, ok := attempts[key]; !ok {
+			attempts[key] = &model.LoginAttempts{}
+		}
+		return attempts[key]
+	}
+
+	return mock.LoginAttemptRepository{
+		OnGet: func(ctx context.Context, key string) (*model.LoginAttempts, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			ret := *getOrCreate(key)
+			return &ret, nil
+		},
+		OnRegisterFailure: func(ctx context.Context, key string, window time.Duration) (int, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			getOrCreate(key).Failures++
+			return attempts[key].Failures, nil
+		},
+		OnLock: func(ctx context.Context, key string, until time.Time) error {
+			mu.Lock()
+			defer mu.Unlock()
+			getOrCreate(key).LockedUntil = until
+			return nil
+		},
+		OnReset: func(ctx context.Context, key string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			delete(attempts, key)
+			return nil
+		},
+	}, attempts
+}
+
+func TestLoginLockoutPolicy_lockoutDuration(t *testing.T) {
+	t.Parallel()
+
+	policy := LoginLockoutPolicy{
+		BaseLockout: time.Minute,
+		MaxLockout:  10 * time.Minute,
+	}
+
+	tests := []struct {
+		name        string
+		failures    int
+		maxFailures int
+		want        time.Duration
+	}{
+		{name: "below max failures should not lock", failures: 4, maxFailures: 5, want: 0},
+		{name: "max failures should lock for base lockout", failures: 5, maxFailures: 5, want: time.Minute},
+		{name: "one more failure should double", failures: 6, maxFailures: 5, want: 2 * time.Minute},
+		{name: "three more failures should be 8 times", failures: 8, maxFailures: 5, want: 8 * time.Minute},
+		{name: "should be capped by max lockout", failures: 9, maxFailures: 5, want: 10 * time.Minute},
+		{name: "many failures should not overflow", failures: 1000, maxFailures: 5, want: 10 * time.Minute},
+		{name: "zero max failures should disable lockout", failures: 1000, maxFailures: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.lockoutDuration(tt.failures, tt.maxFailures); got != tt.want {
+				t.Errorf("lockoutDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_authUseCase_Login_lockout(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	hashedSecret, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	accRepo := mock.AccountRepository{
+		OnGetByCPF: func(ctx context.Context, cpf model.CPF) (*model.Account, error) {
+			return &model.Account{
+				ID:     "any-uuid-1",
+				CPF:    cpf,
+				Secret: string(hashedSecret),
+			}, nil
+		},
+	}
+	tknRepo := mock.TokenRepository{
+		OnCreateRefreshToken: func(ctx context.Context, refreshToken *model.RefreshToken) error {
+			return nil
+		},
+	}
+	attRepo, attempts := newTestLoginAttemptRepository()
+
+	authUC := NewAuthUseCase(
+		newTestHMACKeySet(t, "whatever"),
+		time.Minute,
+		time.Hour,
+		LoginLockoutPolicy{
+			MaxFailuresPerCPF: 2,
+			MaxFailuresPerIP:  3,
+			BaseLockout:       time.Minute,
+			MaxLockout:        time.Hour,
+			FailuresWindow:    24 * time.Hour,
+		},
+		accRepo,
+		tknRepo,
+		attRepo,
+	)
+
+	wrongInput := AuthLoginInput{CPF: "59951332099", Secret: "wrong", ClientIP: "10.0.0.1"}
+	rightInput := AuthLoginInput{CPF: "59951332099", Secret: "secret", ClientIP: "10.0.0.1"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := authUC.Login(backgroundCtx, wrongInput); err != ErrAuthInvalidCredentials {
+			t.Fatalf("Login() failure %d error = %v, wantErr %v", i+1, err, ErrAuthInvalidCredentials)
+		}
+	}
+
+	// even the right secret is rejected while locked out
+	_, err := authUC.Login(backgroundCtx, rightInput)
+	var lockedErr *AuthLoginLockedError
+	if !errors.As(err, &lockedErr) || !errors.Is(err, ErrAuthTooManyLoginAttempts) {
+		t.Fatalf("Login() locked error = %v, wantErr %v", err, ErrAuthTooManyLoginAttempts)
+	}
+	if lockedErr.RetryAfter <= 0 || lockedErr.RetryAfter > time.Minute {
+		t.Errorf("Login() RetryAfter = %v, want up to %v", lockedErr.RetryAfter, time.Minute)
+	}
+
+	// other CPFs from the same IP are still allowed until the IP limit
+	if _, err = authUC.Login(backgroundCtx, AuthLoginInput{CPF: "34363916206", Secret: "wrong", ClientIP: "10.0.0.1"}); err != ErrAuthInvalidCredentials {
+		t.Fatalf("Login() other CPF error = %v, wantErr %v", err, ErrAuthInvalidCredentials)
+	}
+	if _, err = authUC.Login(backgroundCtx, AuthLoginInput{CPF: "34363916206", Secret: "secret", ClientIP: "10.0.0.1"}); !errors.Is(err, ErrAuthTooManyLoginAttempts) {
+		t.Fatalf("Login() locked IP error = %v, wantErr %v", err, ErrAuthTooManyLoginAttempts)
+	}
+
+	// after the lockouts end, a successful login resets only the CPF failures
+	attempts["cpf:59951332099"].LockedUntil = time.Now().Add(-time.Second)
+	attempts["ip:10.0.0.1"].LockedUntil = time.Now().Add(-time.Second)
+	got, err := authUC.Login(backgroundCtx, rightInput)
+	if err != nil || got.AccessToken == "" {
+		t.Fatalf("Login() after lockout got = %v, error = %v", got, err)
+	}
+	if _, ok := attempts["cpf:59951332099"]; ok {
+		t.Errorf("Login() should reset the CPF failures")
+	}
+	if attempts["ip:10.0.0.1"].Failures != 3 {
+		t.Errorf("Login() IP failures = %v, want %v", attempts["ip:10.0.0.1"].Failures, 3)
+	}
+
+	// the lockout grows exponentially
+	if _, err = authUC.Login(backgroundCtx, AuthLoginInput{CPF: "34363916206", Secret: "wrong", ClientIP: "10.0.0.1"}); err != ErrAuthInvalidCredentials {
+		t.Fatalf("Login() error = %v, wantErr %v", err, ErrAuthInvalidCredentials)
+	}
+	if lockout := time.Until(attempts["ip:10.0.0.1"].LockedUntil); lockout <= time.Minute || lockout > 2*time.Minute {
+		t.Errorf("Login() IP lockout = %v, want %v", lockout, 2*time.Minute)
+	}
+}
+
+func Test_authUseCase_Login_lockoutRepositoryError(t *testing.T) {
+	t.Parallel()
+
+	authUC := NewAuthUseCase(
+		newTestHMACKeySet(t, "whatever"),
+		time.Minute,
+		time.Hour,
+		LoginLockoutPolicy{MaxFailuresPerCPF: 5},
+		nil,
+		nil,
+		mock.LoginAttemptRepository{
+			OnGet: func(ctx context.Context, key string) (*model.LoginAttempts, error) {
+				return nil, errors.New("any error")
+			},
+		},
+	)
+
+	_, err := authUC.Login(context.Background(), AuthLoginInput{CPF: "59951332099", Secret: "secret"})
+	if err != ErrAuthLogin {
+		t.Errorf("Login() error = %v, wantErr %v", err, ErrAuthLogin)
+	}
+}
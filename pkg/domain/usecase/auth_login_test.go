@@ -25,6 +25,7 @@ func Test_authUseCase_Login(t *testing.T) {
 		secretKey      string
 		accessTokenDur time.Duration
 		accRepo        repository.AccountRepository
+		tknRepo        repository.TokenRepository
 	}
 	type args struct {
 		ctx        context.Context
@@ -55,6 +56,11 @@ func Test_authUseCase_Login(t *testing.T) {
 						}, nil
 					},
 				},
+				tknRepo: mock.TokenRepository{
+					OnCreateRefreshToken: func(ctx context.Context, refreshToken *model.RefreshToken) error {
+						return nil
+					},
+				},
 			},
 			args: args{
 				ctx: backgroundCtx,
@@ -95,6 +101,36 @@ func Test_authUseCase_Login(t *testing.T) {
 			wantSub: "any-uuid-1",
 			wantErr: ErrAuthInvalidCredentials,
 		},
+		{
+			name: "closed account should return account closed error",
+			fields: fields{
+				secretKey:      "whatever",
+				accessTokenDur: 1 * time.Minute,
+				accRepo: mock.AccountRepository{
+					OnGetByCPF: func(ctx context.Context, cpf model.CPF) (*model.Account, error) {
+						hashedSecret, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.DefaultCost)
+						return &model.Account{
+							ID:        "any-uuid-1",
+							Name:      "Jon Snow",
+							CPF:       "59951332099",
+							Secret:    string(hashedSecret),
+							Balance:   0,
+							Status:    model.AccountStatusClosed,
+							CreatedAt: time.Time{},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				loginInput: AuthLoginInput{
+					CPF:    "59951332099",
+					Secret: "secret",
+				},
+			},
+			wantSub: "any-uuid-1",
+			wantErr: ErrAuthAccountClosed,
+		},
 		{
 			name: "not found account should return invalid credentials error",
 			fields: fields{
@@ -165,13 +201,49 @@ func Test_authUseCase_Login(t *testing.T) {
 			wantSub: "any-uuid-1",
 			wantErr: ErrAuthLogin,
 		},
+		{
+			name: "refresh token store error should return login error",
+			fields: fields{
+				secretKey:      "whatever",
+				accessTokenDur: 1 * time.Minute,
+				accRepo: mock.AccountRepository{
+					OnGetByCPF: func(ctx context.Context, cpf model.CPF) (*model.Account, error) {
+						hashedSecret, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.DefaultCost)
+						return &model.Account{
+							ID:     "any-uuid-1",
+							CPF:    "59951332099",
+							Secret: string(hashedSecret),
+						}, nil
+					},
+				},
+				tknRepo: mock.TokenRepository{
+					OnCreateRefreshToken: func(ctx context.Context, refreshToken *model.RefreshToken) error {
+						return errors.New("any error")
+					},
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				loginInput: AuthLoginInput{
+					CPF:    "59951332099",
+					Secret: "secret",
+				},
+			},
+			wantSub: "any-uuid-1",
+			wantErr: ErrAuthLogin,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			attRepo, _ := newTestLoginAttemptRepository()
 			authUC := NewAuthUseCase(
-				tt.fields.secretKey,
+				newTestHMACKeySet(t, tt.fields.secretKey),
 				tt.fields.accessTokenDur,
+				time.Hour,
+				LoginLockoutPolicy{},
 				tt.fields.accRepo,
+				tt.fields.tknRepo,
+				attRepo,
 			)
 
 			got, err := authUC.Login(tt.args.ctx, tt.args.loginInput)
@@ -183,6 +255,10 @@ func Test_authUseCase_Login(t *testing.T) {
 				return
 			}
 
+			if got.RefreshToken == "" {
+				t.Errorf("Login() RefreshToken should not be empty")
+			}
+
 			token, err := jwt.ParseWithClaims(
 				got.AccessToken,
 				&jwt.RegisteredClaims{},
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrAuthLogout happens when an error occurred while processing logout.
+	ErrAuthLogout = errors.New("could not logout")
+)
+
+// AuthLogoutInput represents the expected input data when logging out.
+type AuthLogoutInput struct {
+	AccountID     string `json:"-"`
+	AccessTokenID string `json:"-"`
+	RefreshToken  string `json:"refresh_token" example:"Vbq0mS3n2YB8uQ0Jm6lq1x7QvWc6h1mZk0pU2yJ8Xa4"`
+}
+
+// Logout deny-lists the current access token and revokes the refresh token family, if informed.
+func (authUC authUseCase) Logout(ctx context.Context, logoutInput AuthLogoutInput) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if logoutInput.RefreshToken != "" {
+		refreshToken, err := authUC.tknRepo.GetRefreshToken(ctx, logoutInput.RefreshToken)
+		if err != nil && err != repository.ErrRefreshTokenNotFound {
+			log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", logoutInput.AccountID).Msg("error getting refresh token")
+			return ErrAuthLogout
+		}
+
+		// an unknown refresh token has already expired, so there is nothing to revoke
+		if refreshToken != nil {
+			if string(refreshToken.AccountID) != logoutInput.AccountID {
+				return ErrAuthInvalidRefreshToken
+			}
+
+			err = authUC.tknRepo.RevokeRefreshTokenFamily(ctx, refreshToken.FamilyID, authUC.refreshTokenDur)
+			if err != nil {
+				log.Ctx(ctx).Error().Stack().Err(err).Str("familyID", string(refreshToken.FamilyID)).Msg("error revoking refresh token family")
+				return ErrAuthLogout
+			}
+		}
+	}
+
+	// the access token can't outlive accessTokenDur, so it's enough to deny it for that long
+	if logoutInput.AccessTokenID != "" {
+		err := authUC.tknRepo.DenyAccessToken(ctx, logoutInput.AccessTokenID, authUC.accessTokenDur)
+		if err != nil {
+			log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", logoutInput.AccountID).Msg("error denying access token")
+			return ErrAuthLogout
+		}
+	}
+
+	return nil
+}
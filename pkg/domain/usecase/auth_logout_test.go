@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_authUseCase_Logout(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	refreshTokenOf := func(accountID model.AccountID) func(ctx context.Context, token string) (*model.RefreshToken, error) {
+		return func(ctx context.Context, token string) (*model.RefreshToken, error) {
+			return &model.RefreshToken{
+				Token:     token,
+				FamilyID:  "family-1",
+				AccountID: accountID,
+				ExpiresAt: time.Now().Add(time.Hour),
+			}, nil
+		}
+	}
+
+	type fields struct {
+		tknRepo mock.TokenRepository
+	}
+	type args struct {
+		ctx         context.Context
+		logoutInput AuthLogoutInput
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantErr     error
+		wantDenied  string
+		wantRevoked model.RefreshTokenFamilyID
+	}{
+		{
+			name: "should deny access token and revoke refresh token family",
+			fields: fields{
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken: refreshTokenOf("any-uuid-1"),
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				logoutInput: AuthLogoutInput{
+					AccountID:     "any-uuid-1",
+					AccessTokenID: "jti-1",
+					RefreshToken:  "refresh-token-1",
+				},
+			},
+			wantErr:     nil,
+			wantDenied:  "jti-1",
+			wantRevoked: "family-1",
+		},
+		{
+			name: "without refresh token should only deny access token",
+			fields: fields{
+				tknRepo: mock.TokenRepository{},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				logoutInput: AuthLogoutInput{
+					AccountID:     "any-uuid-1",
+					AccessTokenID: "jti-1",
+				},
+			},
+			wantErr:    nil,
+			wantDenied: "jti-1",
+		},
+		{
+			name: "unknown refresh token should only deny access token",
+			fields: fields{
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken: func(ctx context.Context, token string) (*model.RefreshToken, error) {
+						return nil, repository.ErrRefreshTokenNotFound
+					},
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				logoutInput: AuthLogoutInput{
+					AccountID:     "any-uuid-1",
+					AccessTokenID: "jti-1",
+					RefreshToken:  "refresh-token-1",
+				},
+			},
+			wantErr:    nil,
+			wantDenied: "jti-1",
+		},
+		{
+			name: "refresh token of another account should return invalid refresh token",
+			fields: fields{
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken: refreshTokenOf("any-uuid-2"),
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				logoutInput: AuthLogoutInput{
+					AccountID:     "any-uuid-1",
+					AccessTokenID: "jti-1",
+					RefreshToken:  "refresh-token-1",
+				},
+			},
+			wantErr: ErrAuthInvalidRefreshToken,
+		},
+		{
+			name: "get refresh token error should return logout error",
+			fields: fields{
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken: func(ctx context.Context, token string) (*model.RefreshToken, error) {
+						return nil, errors.New("any error")
+					},
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				logoutInput: AuthLogoutInput{
+					AccountID:     "any-uuid-1",
+					AccessTokenID: "jti-1",
+					RefreshToken:  "refresh-token-1",
+				},
+			},
+			wantErr: ErrAuthLogout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deniedTokenID string
+			var revokedFamilyID model.RefreshTokenFamilyID
+			tknRepo := tt.fields.tknRepo
+			tknRepo.OnDenyAccessToken = func(ctx context.Context, tokenID string, duration time.Duration) error {
+				if duration != time.Minute {
+					return errors.New("should deny for the access token duration")
+				}
+				deniedTokenID = tokenID
+				return nil
+			}
+			tknRepo.OnRevokeRefreshTokenFamily = func(ctx context.Context, familyID model.RefreshTokenFamilyID, duration time.Duration) error {
+				revokedFamilyID = familyID
+				return nil
+			}
+
+			authUC := NewAuthUseCase(newTestHMACKeySet(t, "whatever"), time.Minute, time.Hour, LoginLockoutPolicy{}, nil, tknRepo, nil)
+
+			err := authUC.Logout(tt.args.ctx, tt.args.logoutInput)
+			if err != tt.wantErr {
+				t.Errorf("Logout() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if deniedTokenID != tt.wantDenied {
+				t.Errorf("Logout() denied token = %v, want %v", deniedTokenID, tt.wantDenied)
+			}
+			if revokedFamilyID != tt.wantRevoked {
+				t.Errorf("Logout() revoked family = %v, want %v", revokedFamilyID, tt.wantRevoked)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrAuthInvalidRefreshToken happens when the refresh token is unknown, expired, revoked or was already used.
+	ErrAuthInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrAuthRefresh happens when an error occurred while refreshing the tokens.
+	ErrAuthRefresh = errors.New("could not refresh token")
+)
+
+// AuthRefreshInput represents the expected input data when refreshing the tokens.
+type AuthRefreshInput struct {
+	RefreshToken string `json:"refresh_token" example:"Vbq0mS3n2YB8uQ0Jm6lq1x7QvWc6h1mZk0pU2yJ8Xa4"`
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh token.
+// Each refresh token can be used only once. If an already used refresh token is presented again,
+// it was probably stolen, so its whole family is revoked.
+func (authUC authUseCase) Refresh(ctx context.Context, refreshInput AuthRefreshInput) (*AuthTokenOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if refreshInput.RefreshToken == "" {
+		return nil, ErrAuthInvalidRefreshToken
+	}
+
+	refreshToken, err := authUC.tknRepo.GetRefreshToken(ctx, refreshInput.RefreshToken)
+	if err != nil {
+		if err == repository.ErrRefreshTokenNotFound {
+			return nil, ErrAuthInvalidRefreshToken
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error getting refresh token")
+		return nil, ErrAuthRefresh
+	}
+
+	if refreshToken.IsExpired() {
+		return nil, ErrAuthInvalidRefreshToken
+	}
+
+	revoked, err := authUC.tknRepo.IsRefreshTokenFamilyRevoked(ctx, refreshToken.FamilyID)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Str("familyID", string(refreshToken.FamilyID)).Msg("error checking refresh token family")
+		return nil, ErrAuthRefresh
+	}
+	if revoked {
+		return nil, ErrAuthInvalidRefreshToken
+	}
+
+	firstUse, err := authUC.tknRepo.UseRefreshToken(ctx, refreshToken)
+	if err != nil {
+		if err == repository.ErrRefreshTokenNotFound {
+			return nil, ErrAuthInvalidRefreshToken
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("familyID", string(refreshToken.FamilyID)).Msg("error using refresh token")
+		return nil, ErrAuthRefresh
+	}
+
+	if !firstUse {
+		log.Ctx(ctx).Warn().Str("familyID", string(refreshToken.FamilyID)).Str("accountID", string(refreshToken.AccountID)).Msg("refresh token reuse detected, revoking token family")
+
+		err = authUC.tknRepo.RevokeRefreshTokenFamily(ctx, refreshToken.FamilyID, authUC.refreshTokenDur)
+		if err != nil {
+			log.Ctx(ctx).Error().Stack().Err(err).Str("familyID", string(refreshToken.FamilyID)).Msg("error revoking refresh token family")
+			return nil, ErrAuthRefresh
+		}
+
+		return nil, ErrAuthInvalidRefreshToken
+	}
+
+	// the roles may have changed since the login, so the new access token gets the current ones
+	account, err := authUC.accRepo.GetByID(ctx, refreshToken.AccountID)
+	if err != nil {
+		if err == repository.ErrAccountNotFound {
+			return nil, ErrAuthInvalidRefreshToken
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(refreshToken.AccountID)).Msg("error getting refresh token account")
+		return nil, ErrAuthRefresh
+	}
+
+	if account.Status == model.AccountStatusClosed {
+		return nil, ErrAuthInvalidRefreshToken
+	}
+
+	authTokenOutput, err := authUC.createAccountToken(ctx, account, refreshToken.FamilyID)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(refreshToken.AccountID)).Msg("error creating new authTokenOutput")
+		return nil, ErrAuthRefresh
+	}
+
+	return authTokenOutput, nil
+}
@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_authUseCase_Refresh(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	validRefreshToken := func(ctx context.Context, token string) (*model.RefreshToken, error) {
+		return &model.RefreshToken{
+			Token:     token,
+			FamilyID:  "family-1",
+			AccountID: "any-uuid-1",
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil
+	}
+	notRevoked := func(ctx context.Context, familyID model.RefreshTokenFamilyID) (bool, error) {
+		return false, nil
+	}
+	firstUse := func(ctx context.Context, refreshToken *model.RefreshToken) (bool, error) {
+		return true, nil
+	}
+	adminAccount := mock.AccountRepository{
+		OnGetByID: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			return &model.Account{ID: id, Roles: []model.Role{model.RoleAdmin}}, nil
+		},
+	}
+
+	type fields struct {
+		accRepo mock.AccountRepository
+		tknRepo mock.TokenRepository
+	}
+	type args struct {
+		ctx          context.Context
+		refreshInput AuthRefreshInput
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantErr     error
+		wantRevoked bool
+		wantRoles   []model.Role
+		wantScope   string
+	}{
+		{
+			name: "success should rotate the refresh token within the same family",
+			fields: fields{
+				accRepo: adminAccount,
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken:             validRefreshToken,
+					OnIsRefreshTokenFamilyRevoked: notRevoked,
+					OnUseRefreshToken:             firstUse,
+					OnCreateRefreshToken: func(ctx context.Context, refreshToken *model.RefreshToken) error {
+						if refreshToken.FamilyID != "family-1" || refreshToken.AccountID != "any-uuid-1" {
+							return errors.New("should keep family and account")
+						}
+						if refreshToken.Token == "refresh-token-1" {
+							return errors.New("should rotate the token")
+						}
+						return nil
+					},
+				},
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				refreshInput: AuthRefreshInput{RefreshToken: "refresh-token-1"},
+			},
+			wantErr:   nil,
+			wantRoles: []model.Role{model.RoleAdmin},
+			wantScope: "accounts:read accounts:write cash:deposit transfers:reverse accounts:credit",
+		},
+		{
+			name: "closed account should return invalid refresh token",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetByID: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return &model.Account{ID: id, Status: model.AccountStatusClosed}, nil
+					},
+				},
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken:             validRefreshToken,
+					OnIsRefreshTokenFamilyRevoked: notRevoked,
+					OnUseRefreshToken:             firstUse,
+				},
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				refreshInput: AuthRefreshInput{RefreshToken: "refresh-token-1"},
+			},
+			wantErr: ErrAuthInvalidRefreshToken,
+		},
+		{
+			name: "empty refresh token should return invalid refresh token",
+			fields: fields{
+				tknRepo: mock.TokenRepository{},
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				refreshInput: AuthRefreshInput{RefreshToken: ""},
+			},
+			wantErr: ErrAuthInvalidRefreshToken,
+		},
+		{
+			name: "unknown refresh token should return invalid refresh token",
+			fields: fields{
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken: func(ctx context.Context, token string) (*model.RefreshToken, error) {
+						return nil, repository.ErrRefreshTokenNotFound
+					},
+				},
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				refreshInput: AuthRefreshInput{RefreshToken: "refresh-token-1"},
+			},
+			wantErr: ErrAuthInvalidRefreshToken,
+		},
+		{
+			name: "get refresh token error should return refresh error",
+			fields: fields{
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken: func(ctx context.Context, token string) (*model.RefreshToken, error) {
+						return nil, errors.New("any error")
+					},
+				},
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				refreshInput: AuthRefreshInput{RefreshToken: "refresh-token-1"},
+			},
+			wantErr: ErrAuthRefresh,
+		},
+		{
+			name: "expired refresh token should return invalid refresh token",
+			fields: fields{
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken: func(ctx context.Context, token string) (*model.RefreshToken, error) {
+						return &model.RefreshToken{
+							Token:     token,
+							FamilyID:  "family-1",
+							AccountID: "any-uuid-1",
+							ExpiresAt: time.Now().Add(-time.Second),
+						}, nil
+					},
+				},
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				refreshInput: AuthRefreshInput{RefreshToken: "refresh-token-1"},
+			},
+			wantErr: ErrAuthInvalidRefreshToken,
+		},
+		{
+			name: "revoked family should return invalid refresh token",
+			fields: fields{
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken: validRefreshToken,
+					OnIsRefreshTokenFamilyRevoked: func(ctx context.Context, familyID model.RefreshTokenFamilyID) (bool, error) {
+						return true, nil
+					},
+				},
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				refreshInput: AuthRefreshInput{RefreshToken: "refresh-token-1"},
+			},
+			wantErr: ErrAuthInvalidRefreshToken,
+		},
+		{
+			name: "reused refresh token should revoke the family",
+			fields: fields{
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken:             validRefreshToken,
+					OnIsRefreshTokenFamilyRevoked: notRevoked,
+					OnUseRefreshToken: func(ctx context.Context, refreshToken *model.RefreshToken) (bool, error) {
+						return false, nil
+					},
+				},
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				refreshInput: AuthRefreshInput{RefreshToken: "refresh-token-1"},
+			},
+			wantErr:     ErrAuthInvalidRefreshToken,
+			wantRevoked: true,
+		},
+		{
+			name: "use refresh token error should return refresh error",
+			fields: fields{
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken:             validRefreshToken,
+					OnIsRefreshTokenFamilyRevoked: notRevoked,
+					OnUseRefreshToken: func(ctx context.Context, refreshToken *model.RefreshToken) (bool, error) {
+						return false, errors.New("any error")
+					},
+				},
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				refreshInput: AuthRefreshInput{RefreshToken: "refresh-token-1"},
+			},
+			wantErr: ErrAuthRefresh,
+		},
+		{
+			name: "deleted account should return invalid refresh token",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetByID: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken:             validRefreshToken,
+					OnIsRefreshTokenFamilyRevoked: notRevoked,
+					OnUseRefreshToken:             firstUse,
+				},
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				refreshInput: AuthRefreshInput{RefreshToken: "refresh-token-1"},
+			},
+			wantErr: ErrAuthInvalidRefreshToken,
+		},
+		{
+			name: "get account error should return refresh error",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetByID: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return nil, errors.New("any error")
+					},
+				},
+				tknRepo: mock.TokenRepository{
+					OnGetRefreshToken:             validRefreshToken,
+					OnIsRefreshTokenFamilyRevoked: notRevoked,
+					OnUseRefreshToken:             firstUse,
+				},
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				refreshInput: AuthRefreshInput{RefreshToken: "refresh-token-1"},
+			},
+			wantErr: ErrAuthRefresh,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var revokedFamilyID model.RefreshTokenFamilyID
+			tknRepo := tt.fields.tknRepo
+			tknRepo.OnRevokeRefreshTokenFamily = func(ctx context.Context, familyID model.RefreshTokenFamilyID, duration time.Duration) error {
+				revokedFamilyID = familyID
+				return nil
+			}
+
+			keySet := newTestHMACKeySet(t, "whatever")
+			authUC := NewAuthUseCase(keySet, time.Minute, time.Hour, LoginLockoutPolicy{}, tt.fields.accRepo, tknRepo, nil)
+
+			got, err := authUC.Refresh(tt.args.ctx, tt.args.refreshInput)
+			if err != tt.wantErr {
+				t.Errorf("Refresh() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if (revokedFamilyID == "family-1") != tt.wantRevoked {
+				t.Errorf("Refresh() revoked family = %v, wantRevoked %v", revokedFamilyID, tt.wantRevoked)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.AccessToken == "" || got.RefreshToken == "" {
+				t.Errorf("Refresh() got = %v, want access and refresh tokens", got)
+				return
+			}
+
+			claims := &AuthClaims{}
+			if _, err := jwt.ParseWithClaims(got.AccessToken, claims, keySet.verificationKey); err != nil {
+				t.Errorf("Refresh() access token error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(claims.Roles, tt.wantRoles) {
+				t.Errorf("Refresh() access token roles = %v, want %v", claims.Roles, tt.wantRoles)
+			}
+			if claims.Scope != tt.wantScope {
+				t.Errorf("Refresh() access token scope = %v, want %v", claims.Scope, tt.wantScope)
+			}
+		})
+	}
+}
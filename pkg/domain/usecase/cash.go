@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// CashUseCase is the interface that wraps all business logic methods related to the deposits and withdrawals.
+type CashUseCase interface {
+	Deposit(ctx context.Context, caller model.Principal, depositInput DepositCreateInput) (*DepositCreateOutput, error)
+	Withdraw(ctx context.Context, withdrawalInput WithdrawalCreateInput) (*WithdrawalCreateOutput, error)
+}
+
+type cashUseCase struct {
+	cashRepo    repository.CashRepository
+	accRepo     repository.AccountRepository
+	ledgerRepo  repository.LedgerRepository
+	trfRepo     repository.TransferRepository
+	limitRepo   repository.TransferLimitRepository
+	limitPolicy TransferLimitPolicy
+}
+
+// NewCashUseCase instantiates a new CashUseCase.
+func NewCashUseCase(
+	cashRepo repository.CashRepository,
+	accRepo repository.AccountRepository,
+	ledgerRepo repository.LedgerRepository,
+	trfRepo repository.TransferRepository,
+	limitRepo repository.TransferLimitRepository,
+	limitPolicy TransferLimitPolicy,
+) CashUseCase {
+	return &cashUseCase{
+		cashRepo:    cashRepo,
+		accRepo:     accRepo,
+		ledgerRepo:  ledgerRepo,
+		trfRepo:     trfRepo,
+		limitRepo:   limitRepo,
+		limitPolicy: limitPolicy,
+	}
+}
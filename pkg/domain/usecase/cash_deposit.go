@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrCashAccountRequired happens when the deposit or withdrawal account ID is blank.
+	ErrCashAccountRequired = errors.New("account id is required")
+	// ErrCashAmountNotPositive happens when the deposit or withdrawal amount is less or equal to zero.
+	ErrCashAmountNotPositive = errors.New("'amount' must be greater than zero")
+	// ErrCashAccountNotActive happens when the deposit or withdrawal account is blocked or closed.
+	ErrCashAccountNotActive = errors.New("account is not active")
+	// ErrCashDeposit happens when an error occurred and the deposit was not created.
+	ErrCashDeposit = errors.New("could not create deposit")
+)
+
+// DepositCreateInput represents the expected input data when depositing cash into an account.
+type DepositCreateInput struct {
+	AccountID string `json:"-"`
+	Amount    Amount `json:"amount" swaggertype:"number" example:"9999.99"`
+}
+
+// Validate validates the DepositCreateInput fields.
+func (input *DepositCreateInput) Validate() error {
+	input.AccountID = strings.TrimSpace(input.AccountID)
+	if len(input.AccountID) < 1 {
+		return ErrCashAccountRequired
+	}
+
+	if input.Amount.Money <= 0 {
+		return ErrCashAmountNotPositive
+	}
+
+	return nil
+}
+
+// DepositCreateOutput represents the output data of the deposit method.
+type DepositCreateOutput struct {
+	ID         string    `json:"id" example:"0b9ca4a5-1b59-4d5a-9c1f-4f4cf4a5d8c6"`
+	AccountID  string    `json:"account_id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	OperatorID string    `json:"operator_id" example:"6c3b8a55-6b80-4137-9dff-503caf576514"`
+	Amount     Amount    `json:"amount" swaggertype:"number" example:"9999.99"`
+	CreatedAt  time.Time `json:"created_at" example:"2020-12-31T23:59:59.999999-03:00"`
+}
+
+func newDepositCreateOutput(deposit *model.Deposit, currency model.Currency) *DepositCreateOutput {
+	return &DepositCreateOutput{
+		ID:         string(deposit.ID),
+		AccountID:  string(deposit.AccountID),
+		OperatorID: string(deposit.OperatorID),
+		Amount:     NewAmountIn(deposit.Amount, currency),
+		CreatedAt:  deposit.CreatedAt,
+	}
+}
+
+// Deposit validates the input, saves the deposit and posts it to the ledger, crediting the amount on the account.
+// Only callers with the model.ScopeCashDeposit can deposit, otherwise it returns ErrAuthForbidden.
+func (cashUC cashUseCase) Deposit(ctx context.Context, caller model.Principal, depositInput DepositCreateInput) (*DepositCreateOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if !caller.HasScope(model.ScopeCashDeposit) {
+		return nil, ErrAuthForbidden
+	}
+
+	err := depositInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", depositInput).Msg("deposit create input is not valid")
+		return nil, err
+	}
+
+	deposit := model.NewDeposit(model.AccountID(depositInput.AccountID), caller.AccountID, depositInput.Amount.Money)
+
+	data, err := cashUC.cashRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		account, err := cashUC.accRepo.GetBalanceForUpdate(txCtx, deposit.AccountID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !account.IsActive() {
+			return nil, ErrCashAccountNotActive
+		}
+
+		deposit.Amount, err = depositInput.Amount.In(account.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		posting := model.NewLedgerPosting(
+			model.LedgerPostingDeposit,
+			string(deposit.ID),
+			model.LedgerExternalAccountID,
+			deposit.AccountID,
+			deposit.Amount,
+			account.Currency)
+
+		err = cashUC.ledgerRepo.Post(txCtx, posting)
+		if err != nil {
+			return nil, err
+		}
+
+		return account.Currency, cashUC.cashRepo.CreateDeposit(txCtx, deposit)
+	})
+	if err != nil {
+		if err == repository.ErrAccountNotFound || err == ErrCashAccountNotActive || err == ErrAmountInvalid {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("deposit", deposit).Msg("error persisting new deposit")
+		return nil, ErrCashDeposit
+	}
+
+	currency, _ := data.(model.Currency)
+	return newDepositCreateOutput(deposit, currency), nil
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_cashUseCase_Deposit(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	operator := model.Principal{AccountID: "operator-uuid", Scopes: []model.Scope{model.ScopeCashDeposit}}
+
+	cashRepo := mock.CashRepository{
+		OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+			return txFunc(ctx)
+		},
+		OnCreateDeposit: func(ctx context.Context, deposit *model.Deposit) error {
+			return nil
+		},
+	}
+	activeAccount := mock.AccountRepository{
+		OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			return &model.Account{ID: id, Balance: 0, Status: model.AccountStatusActive}, nil
+		},
+	}
+
+	type fields struct {
+		cashRepo repository.CashRepository
+		accRepo  repository.AccountRepository
+	}
+	type args struct {
+		caller       model.Principal
+		depositInput DepositCreateInput
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		postErr     error
+		want        *DepositCreateOutput
+		wantPosting bool
+		wantErr     error
+	}{
+		{
+			name: "caller without scope should return forbidden",
+			fields: fields{
+				cashRepo: cashRepo,
+				accRepo:  activeAccount,
+			},
+			args: args{
+				caller:       model.Principal{AccountID: "uuid-1"},
+				depositInput: DepositCreateInput{AccountID: "uuid-1", Amount: NewAmount(100)},
+			},
+			wantErr: ErrAuthForbidden,
+		},
+		{
+			name: "zero amount should return error",
+			fields: fields{
+				cashRepo: cashRepo,
+				accRepo:  activeAccount,
+			},
+			args: args{
+				caller:       operator,
+				depositInput: DepositCreateInput{AccountID: "uuid-1", Amount: NewAmount(0)},
+			},
+			wantErr: ErrCashAmountNotPositive,
+		},
+		{
+			name: "not found account should return not found error",
+			fields: fields{
+				cashRepo: cashRepo,
+				accRepo: mock.AccountRepository{
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+			},
+			args: args{
+				caller:       operator,
+				depositInput: DepositCreateInput{AccountID: "uuid-1", Amount: NewAmount(100)},
+			},
+			wantErr: repository.ErrAccountNotFound,
+		},
+		{
+			name: "blocked account should return error",
+			fields: fields{
+				cashRepo: cashRepo,
+				accRepo: mock.AccountRepository{
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return &model.Account{ID: id, Status: model.AccountStatusBlocked}, nil
+					},
+				},
+			},
+			args: args{
+				caller:       operator,
+				depositInput: DepositCreateInput{AccountID: "uuid-1", Amount: NewAmount(100)},
+			},
+			wantErr: ErrCashAccountNotActive,
+		},
+		{
+			name: "ledger post error should return error",
+			fields: fields{
+				cashRepo: cashRepo,
+				accRepo:  activeAccount,
+			},
+			args: args{
+				caller:       operator,
+				depositInput: DepositCreateInput{AccountID: "uuid-1", Amount: NewAmount(100)},
+			},
+			postErr:     errors.New("any database error"),
+			wantPosting: true,
+			wantErr:     ErrCashDeposit,
+		},
+		{
+			name: "success",
+			fields: fields{
+				cashRepo: cashRepo,
+				accRepo:  activeAccount,
+			},
+			args: args{
+				caller:       operator,
+				depositInput: DepositCreateInput{AccountID: "uuid-1", Amount: NewAmount(100)},
+			},
+			want: &DepositCreateOutput{
+				AccountID:  "uuid-1",
+				OperatorID: "operator-uuid",
+				Amount:     NewAmount(100),
+			},
+			wantPosting: true,
+			wantErr:     nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotPosting *model.LedgerPosting
+			ledgerRepo := mock.LedgerRepository{
+				OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+					gotPosting = posting
+					return tt.postErr
+				},
+			}
+
+			cashUC := NewCashUseCase(tt.fields.cashRepo, tt.fields.accRepo, ledgerRepo, nil, nil, TransferLimitPolicy{})
+			got, err := cashUC.Deposit(backgroundCtx, tt.args.caller, tt.args.depositInput)
+			if err != tt.wantErr {
+				t.Errorf("Deposit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if (gotPosting != nil) != tt.wantPosting {
+				t.Errorf("Deposit() posting = %v, wantPosting %v", gotPosting, tt.wantPosting)
+			}
+			if gotPosting != nil && (gotPosting.Kind != model.LedgerPostingDeposit ||
+				gotPosting.DebitAccountID != model.LedgerExternalAccountID || gotPosting.CreditAccountID != "uuid-1") {
+				t.Errorf("Deposit() posting = %v, want deposit from external to uuid-1", gotPosting)
+			}
+
+			if got == nil {
+				return
+			}
+
+			if len(got.ID) < 1 {
+				t.Errorf("Deposit() got = %v, want ID generated", got)
+			}
+			if got.CreatedAt.Before(time.Now().Add(-5 * time.Second)) {
+				t.Errorf("Deposit() got = %v, want CreatedAt in the last 5 seconds", got)
+			}
+			if got.AccountID != tt.want.AccountID || got.OperatorID != tt.want.OperatorID || got.Amount != tt.want.Amount {
+				t.Errorf("Deposit() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
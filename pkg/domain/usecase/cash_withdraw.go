@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrCashWithdraw happens when an error occurred and the withdrawal was not created.
+	ErrCashWithdraw = errors.New("could not create withdrawal")
+)
+
+// WithdrawalCreateInput represents the expected input data when withdrawing cash from an account.
+type WithdrawalCreateInput struct {
+	AccountID string `json:"-"`
+	Amount    Amount `json:"amount" swaggertype:"number" example:"9999.99"`
+}
+
+// Validate validates the WithdrawalCreateInput fields.
+func (input *WithdrawalCreateInput) Validate() error {
+	input.AccountID = strings.TrimSpace(input.AccountID)
+	if len(input.AccountID) < 1 {
+		return ErrCashAccountRequired
+	}
+
+	if input.Amount.Money <= 0 {
+		return ErrCashAmountNotPositive
+	}
+
+	return nil
+}
+
+// WithdrawalCreateOutput represents the output data of the withdraw method.
+type WithdrawalCreateOutput struct {
+	ID        string    `json:"id" example:"3f1b6a2e-5d8f-4b8a-a6f1-3c2d7e9b0a14"`
+	AccountID string    `json:"account_id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	Amount    Amount    `json:"amount" swaggertype:"number" example:"9999.99"`
+	CreatedAt time.Time `json:"created_at" example:"2020-12-31T23:59:59.999999-03:00"`
+}
+
+func newWithdrawalCreateOutput(withdrawal *model.Withdrawal, currency model.Currency) *WithdrawalCreateOutput {
+	return &WithdrawalCreateOutput{
+		ID:        string(withdrawal.ID),
+		AccountID: string(withdrawal.AccountID),
+		Amount:    NewAmountIn(withdrawal.Amount, currency),
+		CreatedAt: withdrawal.CreatedAt,
+	}
+}
+
+// Withdraw validates the input, saves the withdrawal and posts it to the ledger, debiting the amount from the account.
+// The account must have enough balance and the withdrawal is limited and counted by its transfer limits,
+// like the transfers it sends, so the cash can't take more out of the account than they allow.
+func (cashUC cashUseCase) Withdraw(ctx context.Context, withdrawalInput WithdrawalCreateInput) (*WithdrawalCreateOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := withdrawalInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", withdrawalInput).Msg("withdrawal create input is not valid")
+		return nil, err
+	}
+
+	withdrawal := model.NewWithdrawal(model.AccountID(withdrawalInput.AccountID), withdrawalInput.Amount.Money)
+
+	data, err := cashUC.cashRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		account, err := cashUC.accRepo.GetBalanceForUpdate(txCtx, withdrawal.AccountID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !account.IsActive() {
+			return nil, ErrCashAccountNotActive
+		}
+
+		withdrawal.Amount, err = withdrawalInput.Amount.In(account.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkTransferLimits(txCtx, cashUC.limitRepo, cashUC.trfRepo, cashUC.limitPolicy, account.ID, withdrawal.Amount, withdrawal.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ensureSufficientBalance(account, withdrawal.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		posting := model.NewLedgerPosting(
+			model.LedgerPostingWithdrawal,
+			string(withdrawal.ID),
+			withdrawal.AccountID,
+			model.LedgerExternalAccountID,
+			withdrawal.Amount,
+			account.Currency)
+
+		err = cashUC.ledgerRepo.Post(txCtx, posting)
+		if err != nil {
+			return nil, err
+		}
+
+		return account.Currency, cashUC.cashRepo.CreateWithdrawal(txCtx, withdrawal)
+	})
+	if err != nil {
+		switch err {
+		case repository.ErrAccountNotFound, ErrCashAccountNotActive, ErrAccountCurrentBalanceInsufficient, ErrAmountInvalid:
+			return nil, err
+		}
+		if errors.Is(err, ErrTransferLimitExceeded) {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("withdrawal", withdrawal).Msg("error persisting new withdrawal")
+		return nil, ErrCashWithdraw
+	}
+
+	currency, _ := data.(model.Currency)
+	return newWithdrawalCreateOutput(withdrawal, currency), nil
+}
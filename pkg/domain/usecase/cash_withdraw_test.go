@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_cashUseCase_Withdraw(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	cashRepo := mock.CashRepository{
+		OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+			return txFunc(ctx)
+		},
+		OnCreateWithdrawal: func(ctx context.Context, withdrawal *model.Withdrawal) error {
+			return nil
+		},
+	}
+	noAccountLimits := mock.TransferLimitRepository{
+		OnGet: func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+			return &model.AccountTransferLimits{AccountID: accountID}, nil
+		},
+	}
+	// the account withdrew or sent 900 today, out of the daily limit of 1000
+	trfRepo := mock.TransferRepository{
+		OnGetSentTotals: func(ctx context.Context, accountID model.AccountID, periods model.TransferLimitPeriods) (*model.TransferTotals, error) {
+			return &model.TransferTotals{Daily: 900, Monthly: 900}, nil
+		},
+	}
+	limitPolicy := TransferLimitPolicy{Defaults: model.TransferLimits{Daily: 1000}}
+	accountWith := func(balance model.Money, status model.AccountStatus) repository.AccountRepository {
+		return mock.AccountRepository{
+			OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+				return &model.Account{ID: id, Balance: balance, Status: status}, nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		accRepo         repository.AccountRepository
+		withdrawalInput WithdrawalCreateInput
+		want            *WithdrawalCreateOutput
+		wantErr         error
+	}{
+		{
+			name:            "blank account should return error",
+			accRepo:         accountWith(1000, model.AccountStatusActive),
+			withdrawalInput: WithdrawalCreateInput{AccountID: " ", Amount: NewAmount(100)},
+			wantErr:         ErrCashAccountRequired,
+		},
+		{
+			name:            "negative amount should return error",
+			accRepo:         accountWith(1000, model.AccountStatusActive),
+			withdrawalInput: WithdrawalCreateInput{AccountID: "uuid-1", Amount: NewAmount(-100)},
+			wantErr:         ErrCashAmountNotPositive,
+		},
+		{
+			name:            "closed account should return error",
+			accRepo:         accountWith(1000, model.AccountStatusClosed),
+			withdrawalInput: WithdrawalCreateInput{AccountID: "uuid-1", Amount: NewAmount(100)},
+			wantErr:         ErrCashAccountNotActive,
+		},
+		{
+			name:            "balance less than amount should return error",
+			accRepo:         accountWith(99, model.AccountStatusActive),
+			withdrawalInput: WithdrawalCreateInput{AccountID: "uuid-1", Amount: NewAmount(100)},
+			wantErr:         ErrAccountCurrentBalanceInsufficient,
+		},
+		{
+			name:            "amount over the daily limit left should return error",
+			accRepo:         accountWith(1000, model.AccountStatusActive),
+			withdrawalInput: WithdrawalCreateInput{AccountID: "uuid-1", Amount: NewAmount(101)},
+			wantErr:         &TransferLimitExceededError{Limit: model.TransferLimitDaily, Remaining: 100},
+		},
+		{
+			name: "not found account should return not found error",
+			accRepo: mock.AccountRepository{
+				OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+					return nil, repository.ErrAccountNotFound
+				},
+			},
+			withdrawalInput: WithdrawalCreateInput{AccountID: "uuid-1", Amount: NewAmount(100)},
+			wantErr:         repository.ErrAccountNotFound,
+		},
+		{
+			name:            "whole balance should succeed",
+			accRepo:         accountWith(100, model.AccountStatusActive),
+			withdrawalInput: WithdrawalCreateInput{AccountID: "uuid-1", Amount: NewAmount(100)},
+			want: &WithdrawalCreateOutput{
+				AccountID: "uuid-1",
+				Amount:    NewAmount(100),
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotPosting *model.LedgerPosting
+			ledgerRepo := mock.LedgerRepository{
+				OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+					gotPosting = posting
+					return nil
+				},
+			}
+
+			cashUC := NewCashUseCase(cashRepo, tt.accRepo, ledgerRepo, trfRepo, noAccountLimits, limitPolicy)
+			got, err := cashUC.Withdraw(backgroundCtx, tt.withdrawalInput)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("Withdraw() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got == nil {
+				if gotPosting != nil {
+					t.Errorf("Withdraw() posting = %v, want none", gotPosting)
+				}
+				return
+			}
+
+			if gotPosting == nil || gotPosting.Kind != model.LedgerPostingWithdrawal ||
+				gotPosting.DebitAccountID != "uuid-1" || gotPosting.CreditAccountID != model.LedgerExternalAccountID {
+				t.Errorf("Withdraw() posting = %v, want withdrawal from uuid-1 to external", gotPosting)
+			}
+
+			if len(got.ID) < 1 {
+				t.Errorf("Withdraw() got = %v, want ID generated", got)
+			}
+			if got.CreatedAt.Before(time.Now().Add(-5 * time.Second)) {
+				t.Errorf("Withdraw() got = %v, want CreatedAt in the last 5 seconds", got)
+			}
+			if got.AccountID != tt.want.AccountID || got.Amount != tt.want.Amount {
+				t.Errorf("Withdraw() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/infraestructure/monitoring"
+)
+
+// FeePolicy defines the fees charged by the bank, the account they're credited to and the time zone of their months.
+//
+// The fees are only charged to the accounts in the currency of the RevenueAccountID, and an empty one charges no fees.
+// A nil Location means UTC.
+type FeePolicy struct {
+	RevenueAccountID model.AccountID
+	Transfer         model.TransferFeeRule
+	Maintenance      model.MaintenanceFeeRule
+	Location         *time.Location
+}
+
+// chargesTransfers checks whether the transfers may be charged a fee.
+func (policy FeePolicy) chargesTransfers() bool {
+	return policy.RevenueAccountID != "" && !policy.Transfer.IsZero()
+}
+
+// chargesTransfersFrom checks whether the transfers sent by the origin account are charged a fee, credited to the
+// revenue account.
+func (policy FeePolicy) chargesTransfersFrom(origin, revenue *model.Account) bool {
+	return policy.chargesTransfers() &&
+		origin.ID != revenue.ID &&
+		origin.Currency == revenue.Currency &&
+		!policy.Transfer.IsExempt(origin.Tier)
+}
+
+// monthStart returns the midnight starting the month that contains t.
+func (policy FeePolicy) monthStart(t time.Time) time.Time {
+	location := policy.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	t = t.In(location)
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, location)
+}
+
+// MaintenanceFeeUseCase is the interface that wraps all business logic methods related to the maintenance fees.
+type MaintenanceFeeUseCase interface {
+	ExecuteDue(ctx context.Context, limit int) (int, error)
+}
+
+type maintenanceFeeUseCase struct {
+	feeRepo    repository.FeeRepository
+	accRepo    repository.AccountRepository
+	ledgerRepo repository.LedgerRepository
+	feePolicy  FeePolicy
+}
+
+// NewMaintenanceFeeUseCase instantiates a new MaintenanceFeeUseCase.
+func NewMaintenanceFeeUseCase(
+	feeRepo repository.FeeRepository,
+	accRepo repository.AccountRepository,
+	ledgerRepo repository.LedgerRepository,
+	feePolicy FeePolicy,
+) MaintenanceFeeUseCase {
+	return &maintenanceFeeUseCase{
+		feeRepo:    feeRepo,
+		accRepo:    accRepo,
+		ledgerRepo: ledgerRepo,
+		feePolicy:  feePolicy,
+	}
+}
+
+// ExecuteDue charges the maintenance fee of the last month that ended on up to limit accounts, returning how many
+// were charged.
+//
+// The accounts that existed before the month ended are charged once, in their own transaction holding their row
+// lock, so it's safe to run on multiple replicas and again in the same month. The months the executor didn't run
+// at all are not charged later.
+func (feeUC maintenanceFeeUseCase) ExecuteDue(ctx context.Context, limit int) (int, error) {
+	if feeUC.feePolicy.RevenueAccountID == "" || feeUC.feePolicy.Maintenance.Monthly <= 0 {
+		return 0, nil
+	}
+
+	monthEnd := feeUC.feePolicy.monthStart(time.Now())
+	filter := model.MaintenanceFeeFilter{
+		Month:            monthEnd.AddDate(0, -1, 0),
+		MonthEnd:         monthEnd,
+		ExemptTiers:      feeUC.feePolicy.Maintenance.ExemptTiers,
+		RevenueAccountID: feeUC.feePolicy.RevenueAccountID,
+	}
+
+	processed := 0
+	for processed < limit {
+		charge, err := feeUC.chargeNextDue(ctx, &filter)
+		if err != nil {
+			return processed, err
+		}
+		if charge == nil {
+			break
+		}
+
+		// the accounts are walked in ID order, so the ones already charged are not read again
+		filter.After = charge.AccountID
+
+		processed++
+		monitoring.MaintenanceFeeCharges.Inc()
+		log.Ctx(ctx).Info().Str("accountID", string(charge.AccountID)).Str("month", charge.Month.Format("2006-01")).
+			Int64("amount", int64(charge.Amount)).Msg("maintenance fee charged")
+	}
+
+	return processed, nil
+}
+
+// chargeNextDue charges the maintenance fee of the month on the next account that owes it, returning the charge,
+// or nil if there's none.
+func (feeUC maintenanceFeeUseCase) chargeNextDue(ctx context.Context, filter *model.MaintenanceFeeFilter) (*model.MaintenanceFeeCharge, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	data, err := feeUC.feeRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		// the revenue account is only read, the fee is credited by the ledger posting after the charged account
+		// is locked, like the transfers do
+		revenueAccount, err := feeUC.accRepo.GetBalance(txCtx, feeUC.feePolicy.RevenueAccountID)
+		if err != nil {
+			return nil, err
+		}
+		filter.Currency = revenueAccount.Currency
+
+		account, err := feeUC.feeRepo.LockNextMaintenanceDue(txCtx, *filter)
+		if err != nil || account == nil {
+			return nil, err
+		}
+
+		charge := model.NewMaintenanceFeeCharge(account.ID, filter.Month, feeUC.feePolicy.Maintenance.Monthly)
+
+		// like the overdraft interest, the fee is charged even beyond the credit limit
+		posting := model.NewLedgerPosting(
+			model.LedgerPostingMaintenanceFee,
+			string(charge.ID),
+			account.ID,
+			revenueAccount.ID,
+			charge.Amount,
+			revenueAccount.Currency)
+
+		err = feeUC.ledgerRepo.Post(txCtx, posting)
+		if err != nil {
+			return nil, err
+		}
+
+		return charge, feeUC.feeRepo.CreateMaintenanceCharge(txCtx, charge)
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error charging maintenance fee")
+		return nil, err
+	}
+
+	charge, _ := data.(*model.MaintenanceFeeCharge)
+	return charge, nil
+}
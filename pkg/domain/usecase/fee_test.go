@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func TestFeePolicy_monthStart(t *testing.T) {
+	t.Parallel()
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		t        time.Time
+		want     time.Time
+	}{
+		{
+			name: "nil location should be UTC",
+			t:    time.Date(2021, 3, 15, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "should be the month in the location",
+			location: saoPaulo,
+			t:        time.Date(2021, 3, 1, 2, 0, 0, 0, time.UTC),
+			want:     time.Date(2021, 2, 1, 0, 0, 0, 0, saoPaulo),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := FeePolicy{Location: tt.location}
+			if got := policy.monthStart(tt.t); !got.Equal(tt.want) {
+				t.Errorf("monthStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeePolicy_chargesTransfersFrom(t *testing.T) {
+	t.Parallel()
+
+	revenue := &model.Account{ID: "revenue-uuid", Currency: model.CurrencyBRL}
+	policy := FeePolicy{
+		RevenueAccountID: revenue.ID,
+		Transfer:         model.TransferFeeRule{Flat: 100, ExemptTiers: []model.AccountTier{model.AccountTierPremium}},
+	}
+
+	tests := []struct {
+		name   string
+		policy FeePolicy
+		origin *model.Account
+		want   bool
+	}{
+		{
+			name:   "standard account in the revenue currency should be charged",
+			policy: policy,
+			origin: &model.Account{ID: "uuid-1", Currency: model.CurrencyBRL, Tier: model.AccountTierStandard},
+			want:   true,
+		},
+		{
+			name:   "exempt tier should not be charged",
+			policy: policy,
+			origin: &model.Account{ID: "uuid-1", Currency: model.CurrencyBRL, Tier: model.AccountTierPremium},
+			want:   false,
+		},
+		{
+			name:   "other currency should not be charged",
+			policy: policy,
+			origin: &model.Account{ID: "uuid-1", Currency: model.CurrencyUSD, Tier: model.AccountTierStandard},
+			want:   false,
+		},
+		{
+			name:   "revenue account should not be charged",
+			policy: policy,
+			origin: revenue,
+			want:   false,
+		},
+		{
+			name:   "zero rule should not charge",
+			policy: FeePolicy{RevenueAccountID: revenue.ID},
+			origin: &model.Account{ID: "uuid-1", Currency: model.CurrencyBRL, Tier: model.AccountTierStandard},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.policy.chargesTransfersFrom(tt.origin, revenue); got != tt.want {
+				t.Errorf("chargesTransfersFrom() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_maintenanceFeeUseCase_ExecuteDue(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	accRepo := mock.AccountRepository{
+		OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			if id != "revenue-uuid" {
+				return nil, repository.ErrAccountNotFound
+			}
+			return &model.Account{ID: id, Currency: model.CurrencyBRL, Status: model.AccountStatusActive}, nil
+		},
+	}
+	// dueAccounts returns the accounts after the filter cursor, then none
+	dueAccounts := func(ids ...model.AccountID) func(ctx context.Context, filter model.MaintenanceFeeFilter) (*model.Account, error) {
+		return func(ctx context.Context, filter model.MaintenanceFeeFilter) (*model.Account, error) {
+			if filter.Currency != model.CurrencyBRL || filter.RevenueAccountID != "revenue-uuid" || !filter.MonthEnd.Equal(filter.Month.AddDate(0, 1, 0)) {
+				return nil, errors.New("should filter by the revenue account and the month ended")
+			}
+			for _, id := range ids {
+				if id > filter.After {
+					return &model.Account{ID: id, Currency: model.CurrencyBRL, Status: model.AccountStatusActive}, nil
+				}
+			}
+			return nil, nil
+		}
+	}
+	maintenance := model.MaintenanceFeeRule{Monthly: 990}
+
+	tests := []struct {
+		name       string
+		policy     FeePolicy
+		lockNext   func(ctx context.Context, filter model.MaintenanceFeeFilter) (*model.Account, error)
+		postErr    error
+		limit      int
+		want       int
+		wantErr    bool
+		wantPosted []model.AccountID
+	}{
+		{
+			name:     "no revenue account should charge none",
+			policy:   FeePolicy{Maintenance: maintenance},
+			lockNext: dueAccounts("uuid-1"),
+			limit:    10,
+			want:     0,
+		},
+		{
+			name:     "zero fee should charge none",
+			policy:   FeePolicy{RevenueAccountID: "revenue-uuid"},
+			lockNext: dueAccounts("uuid-1"),
+			limit:    10,
+			want:     0,
+		},
+		{
+			name:       "due accounts should be charged once and posted to the revenue account",
+			policy:     FeePolicy{RevenueAccountID: "revenue-uuid", Maintenance: maintenance},
+			lockNext:   dueAccounts("uuid-1", "uuid-2"),
+			limit:      10,
+			want:       2,
+			wantPosted: []model.AccountID{"uuid-1", "uuid-2"},
+		},
+		{
+			name:       "should stop at the limit",
+			policy:     FeePolicy{RevenueAccountID: "revenue-uuid", Maintenance: maintenance},
+			lockNext:   dueAccounts("uuid-1", "uuid-2", "uuid-3"),
+			limit:      2,
+			want:       2,
+			wantPosted: []model.AccountID{"uuid-1", "uuid-2"},
+		},
+		{
+			name:     "revenue account not found should return error",
+			policy:   FeePolicy{RevenueAccountID: "uuid-9", Maintenance: maintenance},
+			lockNext: dueAccounts("uuid-1"),
+			limit:    10,
+			want:     0,
+			wantErr:  true,
+		},
+		{
+			name:     "ledger error should not charge and return error",
+			policy:   FeePolicy{RevenueAccountID: "revenue-uuid", Maintenance: maintenance},
+			lockNext: dueAccounts("uuid-1"),
+			postErr:  errors.New("any database error"),
+			limit:    10,
+			want:     0,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var charged, posted []model.AccountID
+			feeRepo := mock.FeeRepository{
+				OnWithinTransaction:      withinTransaction,
+				OnLockNextMaintenanceDue: tt.lockNext,
+				OnCreateMaintenanceCharge: func(ctx context.Context, charge *model.MaintenanceFeeCharge) error {
+					if charge.Amount != 990 {
+						return errors.New("should charge the monthly fee")
+					}
+					charged = append(charged, charge.AccountID)
+					return nil
+				},
+			}
+			ledgerRepo := mock.LedgerRepository{
+				OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+					if tt.postErr != nil {
+						return tt.postErr
+					}
+					if posting.Kind != model.LedgerPostingMaintenanceFee || posting.CreditAccountID != "revenue-uuid" || posting.Amount != 990 {
+						return errors.New("should credit the fee to the revenue account")
+					}
+					posted = append(posted, posting.DebitAccountID)
+					return nil
+				},
+			}
+			feeUC := NewMaintenanceFeeUseCase(feeRepo, accRepo, ledgerRepo, tt.policy)
+
+			got, err := feeUC.ExecuteDue(backgroundCtx, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecuteDue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want || len(charged) != tt.want {
+				t.Errorf("ExecuteDue() got = %v and charged %v, want %v", got, len(charged), tt.want)
+			}
+			if len(posted) != len(tt.wantPosted) {
+				t.Fatalf("ExecuteDue() posted = %v, want %v", posted, tt.wantPosted)
+			}
+			for i := range posted {
+				if posted[i] != tt.wantPosted[i] {
+					t.Errorf("ExecuteDue() posted = %v, want %v", posted, tt.wantPosted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrFXQuoteCurrencyInvalid happens when the target currency is not one of model.Currency.
+	ErrFXQuoteCurrencyInvalid = errors.New("'target_currency' must be one of BRL, EUR and USD")
+	// ErrFXQuoteAmountInvalid happens when the amount is zero or negative.
+	ErrFXQuoteAmountInvalid = errors.New("'amount' must be greater than 0")
+	// ErrFXQuoteSameCurrency happens when quoting the currency of the account itself.
+	ErrFXQuoteSameCurrency = errors.New("'target_currency' must be different from the currency of the account")
+	// ErrFXQuoteAmountTooSmall happens when the converted amount rounds to zero.
+	ErrFXQuoteAmountTooSmall = errors.New("'amount' is too small to be converted")
+	// ErrFXQuoteExpired happens when transferring by a quote after it expired.
+	ErrFXQuoteExpired = errors.New("fx quote expired")
+	// ErrFXQuoteCreate happens when an error occurred and the quote was not created.
+	ErrFXQuoteCreate = errors.New("could not create fx quote")
+)
+
+// FXQuoteUseCase is the interface that wraps all business logic methods related to the FX quotes.
+type FXQuoteUseCase interface {
+	Create(ctx context.Context, quoteInput FXQuoteCreateInput) (*FXQuoteOutput, error)
+}
+
+type fxQuoteUseCase struct {
+	quoteRepo    repository.FXQuoteRepository
+	accRepo      repository.AccountRepository
+	rateProvider repository.FXRateProvider
+	quoteTTL     time.Duration
+}
+
+// NewFXQuoteUseCase instantiates a new FXQuoteUseCase. The quotes can be used by a transfer until quoteTTL elapses.
+func NewFXQuoteUseCase(
+	quoteRepo repository.FXQuoteRepository,
+	accRepo repository.AccountRepository,
+	rateProvider repository.FXRateProvider,
+	quoteTTL time.Duration,
+) FXQuoteUseCase {
+	return &fxQuoteUseCase{
+		quoteRepo:    quoteRepo,
+		accRepo:      accRepo,
+		rateProvider: rateProvider,
+		quoteTTL:     quoteTTL,
+	}
+}
+
+// FXQuoteCreateInput represents the expected input data when quoting an amount of the account currency in another one.
+type FXQuoteCreateInput struct {
+	AccountID      string `json:"-"`
+	TargetCurrency string `json:"target_currency" example:"USD" enums:"BRL,EUR,JPY,KWD,USD"`
+	Amount         Amount `json:"amount" swaggertype:"number" example:"9999.99"`
+}
+
+// Validate validates the FXQuoteCreateInput fields, normalizing the currency.
+func (input *FXQuoteCreateInput) Validate() error {
+	input.TargetCurrency = strings.ToUpper(strings.TrimSpace(input.TargetCurrency))
+	if !model.Currency(input.TargetCurrency).IsValid() {
+		return ErrFXQuoteCurrencyInvalid
+	}
+
+	if input.Amount.Money <= 0 {
+		return ErrFXQuoteAmountInvalid
+	}
+
+	return nil
+}
+
+// FXQuoteOutput represents a quote of the source amount, in the currency of the account, in the target currency.
+// The ID is the `quote_id` of the transfer that converts the amount, until ExpiresAt.
+type FXQuoteOutput struct {
+	ID             string    `json:"id" example:"0b7c3a2e-8d4f-4a51-9e6b-3c2d1f0a9b8e"`
+	SourceCurrency string    `json:"source_currency" example:"BRL"`
+	TargetCurrency string    `json:"target_currency" example:"USD"`
+	Rate           string    `json:"rate" example:"0.19518289"`
+	SourceAmount   Amount    `json:"source_amount" swaggertype:"number" example:"9999.99"`
+	TargetAmount   Amount    `json:"target_amount" swaggertype:"number" example:"1951.83"`
+	CreatedAt      time.Time `json:"created_at" example:"2020-12-31T23:59:29.999999-03:00"`
+	ExpiresAt      time.Time `json:"expires_at" example:"2020-12-31T23:59:59.999999-03:00"`
+}
+
+func newFXQuoteOutput(quote *model.FXQuote) *FXQuoteOutput {
+	return &FXQuoteOutput{
+		ID:             string(quote.ID),
+		SourceCurrency: string(quote.SourceCurrency),
+		TargetCurrency: string(quote.TargetCurrency),
+		Rate:           quote.Rate.String(),
+		SourceAmount:   NewAmountIn(quote.SourceAmount, quote.SourceCurrency),
+		TargetAmount:   NewAmountIn(quote.TargetAmount, quote.TargetCurrency),
+		CreatedAt:      quote.CreatedAt,
+		ExpiresAt:      quote.ExpiresAt,
+	}
+}
+
+// Create quotes the amount, in the currency of the account, in the target currency by the current rate of the
+// provider. The quote fixes the rate for a single transfer of the amount from the account.
+func (quoteUC fxQuoteUseCase) Create(ctx context.Context, quoteInput FXQuoteCreateInput) (*FXQuoteOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := quoteInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", quoteInput).Msg("fx quote create input is not valid")
+		return nil, err
+	}
+
+	account, err := quoteUC.accRepo.GetByID(ctx, model.AccountID(quoteInput.AccountID))
+	if err != nil {
+		if err == repository.ErrAccountNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", quoteInput.AccountID).Msg("error getting account")
+		return nil, ErrFXQuoteCreate
+	}
+
+	target := model.Currency(quoteInput.TargetCurrency)
+	if account.Currency == target {
+		return nil, ErrFXQuoteSameCurrency
+	}
+
+	amount, err := quoteInput.Amount.In(account.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := quoteUC.rateProvider.GetRate(ctx, account.Currency, target)
+	if err != nil {
+		if err == repository.ErrFXRateNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("source", string(account.Currency)).Str("target", string(target)).
+			Msg("error getting exchange rate")
+		return nil, ErrFXQuoteCreate
+	}
+
+	quote := model.NewFXQuote(account.ID, account.Currency, target, rate, amount, quoteUC.quoteTTL)
+	if quote.TargetAmount <= 0 {
+		return nil, ErrFXQuoteAmountTooSmall
+	}
+
+	err = quoteUC.quoteRepo.Create(ctx, quote)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("quote", quote).Msg("error creating fx quote")
+		return nil, ErrFXQuoteCreate
+	}
+
+	log.Ctx(ctx).Info().Str("quoteID", string(quote.ID)).Str("accountID", string(quote.AccountID)).
+		Str("rate", quote.Rate.String()).Msg("fx quote created")
+
+	return newFXQuoteOutput(quote), nil
+}
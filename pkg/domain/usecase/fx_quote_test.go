@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_fxQuoteUseCase_Create(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	accRepo := mock.AccountRepository{
+		OnGetByID: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			if id != "uuid-1" {
+				return nil, repository.ErrAccountNotFound
+			}
+			return &model.Account{ID: id, Currency: model.CurrencyBRL, Status: model.AccountStatusActive}, nil
+		},
+	}
+	rateProvider := mock.FXRateProvider{
+		OnGetRate: func(ctx context.Context, source, target model.Currency) (model.FXRate, error) {
+			if source != model.CurrencyBRL || target != model.CurrencyUSD {
+				return 0, repository.ErrFXRateNotFound
+			}
+			return 19518289, nil
+		},
+	}
+	quoteRepoOK := mock.FXQuoteRepository{
+		OnCreate: func(ctx context.Context, quote *model.FXQuote) error {
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name      string
+		quoteRepo repository.FXQuoteRepository
+		input     FXQuoteCreateInput
+		want      *FXQuoteOutput
+		wantErr   error
+	}{
+		{
+			name:    "unknown currency should return error",
+			input:   FXQuoteCreateInput{AccountID: "uuid-1", TargetCurrency: "XYZ", Amount: NewAmount(100)},
+			wantErr: ErrFXQuoteCurrencyInvalid,
+		},
+		{
+			name:    "zero amount should return error",
+			input:   FXQuoteCreateInput{AccountID: "uuid-1", TargetCurrency: "USD", Amount: NewAmount(0)},
+			wantErr: ErrFXQuoteAmountInvalid,
+		},
+		{
+			name:    "account not found should return error",
+			input:   FXQuoteCreateInput{AccountID: "uuid-9", TargetCurrency: "USD", Amount: NewAmount(100)},
+			wantErr: repository.ErrAccountNotFound,
+		},
+		{
+			name:    "currency of the account should return error",
+			input:   FXQuoteCreateInput{AccountID: "uuid-1", TargetCurrency: "BRL", Amount: NewAmount(100)},
+			wantErr: ErrFXQuoteSameCurrency,
+		},
+		{
+			name:    "pair without rate should return error",
+			input:   FXQuoteCreateInput{AccountID: "uuid-1", TargetCurrency: "EUR", Amount: NewAmount(100)},
+			wantErr: repository.ErrFXRateNotFound,
+		},
+		{
+			name:    "amount converted to zero should return error",
+			input:   FXQuoteCreateInput{AccountID: "uuid-1", TargetCurrency: "USD", Amount: NewAmount(2)},
+			wantErr: ErrFXQuoteAmountTooSmall,
+		},
+		{
+			name: "repo create error should return error",
+			quoteRepo: mock.FXQuoteRepository{
+				OnCreate: func(ctx context.Context, quote *model.FXQuote) error {
+					return errors.New("any error")
+				},
+			},
+			input:   FXQuoteCreateInput{AccountID: "uuid-1", TargetCurrency: "USD", Amount: NewAmount(51234)},
+			wantErr: ErrFXQuoteCreate,
+		},
+		{
+			name:      "success with lowercase currency",
+			quoteRepo: quoteRepoOK,
+			input:     FXQuoteCreateInput{AccountID: "uuid-1", TargetCurrency: " usd ", Amount: NewAmount(51234)},
+			want: &FXQuoteOutput{
+				SourceCurrency: "BRL",
+				TargetCurrency: "USD",
+				Rate:           "0.19518289",
+				SourceAmount:   NewAmountIn(51234, model.CurrencyBRL),
+				TargetAmount:   NewAmountIn(10000, model.CurrencyUSD),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			quoteUC := NewFXQuoteUseCase(tt.quoteRepo, accRepo, rateProvider, time.Minute)
+			got, err := quoteUC.Create(backgroundCtx, tt.input)
+			if err != tt.wantErr {
+				t.Fatalf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(got.ID) < 1 || got.CreatedAt.Before(time.Now().Add(-5*time.Second)) || !got.ExpiresAt.Equal(got.CreatedAt.Add(time.Minute)) {
+				t.Errorf("Create() got = %+v, want ID generated and expiring a minute after CreatedAt", got)
+			}
+			got.ID = ""
+			got.CreatedAt = time.Time{}
+			got.ExpiresAt = time.Time{}
+
+			if *got != *tt.want {
+				t.Errorf("Create() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,9 +9,15 @@ import (
 
 // AccountUseCase mocks an usecase.AccountUseCase.
 type AccountUseCase struct {
-	OnCreate     func(ctx context.Context, accountInput usecase.AccountCreateInput) (*usecase.AccountCreateOutput, error)
-	OnFetch      func(ctx context.Context) ([]usecase.AccountFetchOutput, error)
-	OnGetBalance func(ctx context.Context, id model.AccountID) (*usecase.AccountBalanceOutput, error)
+	OnCreate         func(ctx context.Context, accountInput usecase.AccountCreateInput) (*usecase.AccountCreateOutput, error)
+	OnFetch          func(ctx context.Context, caller model.Principal, fetchInput usecase.AccountFetchInput) (*usecase.AccountFetchPageOutput, error)
+	OnGetBalance     func(ctx context.Context, caller model.Principal, id model.AccountID) (*usecase.AccountBalanceOutput, error)
+	OnGetStatement   func(ctx context.Context, caller model.Principal, statementInput usecase.AccountStatementInput) (*usecase.AccountStatementOutput, error)
+	OnBlock          func(ctx context.Context, caller model.Principal, statusInput usecase.AccountStatusInput) (*usecase.AccountStatusOutput, error)
+	OnUnblock        func(ctx context.Context, caller model.Principal, statusInput usecase.AccountStatusInput) (*usecase.AccountStatusOutput, error)
+	OnClose          func(ctx context.Context, caller model.Principal, closeInput usecase.AccountCloseInput) (*usecase.AccountStatusOutput, error)
+	OnSetCreditLimit func(ctx context.Context, caller model.Principal, creditLimitInput usecase.AccountCreditLimitInput) (*usecase.AccountBalanceOutput, error)
+	OnSetTier        func(ctx context.Context, caller model.Principal, tierInput usecase.AccountTierInput) (*usecase.AccountTierOutput, error)
 }
 
 var _ usecase.AccountUseCase = (*AccountUseCase)(nil)
@@ -22,11 +28,41 @@ func (mAccUC AccountUseCase) Create(ctx context.Context, accountInput usecase.Ac
 }
 
 // Fetch returns the result of OnFetch.
-func (mAccUC AccountUseCase) Fetch(ctx context.Context) ([]usecase.AccountFetchOutput, error) {
-	return mAccUC.OnFetch(ctx)
+func (mAccUC AccountUseCase) Fetch(ctx context.Context, caller model.Principal, fetchInput usecase.AccountFetchInput) (*usecase.AccountFetchPageOutput, error) {
+	return mAccUC.OnFetch(ctx, caller, fetchInput)
 }
 
 // GetBalance returns the result of OnGetBalance.
-func (mAccUC AccountUseCase) GetBalance(ctx context.Context, id model.AccountID) (*usecase.AccountBalanceOutput, error) {
-	return mAccUC.OnGetBalance(ctx, id)
+func (mAccUC AccountUseCase) GetBalance(ctx context.Context, caller model.Principal, id model.AccountID) (*usecase.AccountBalanceOutput, error) {
+	return mAccUC.OnGetBalance(ctx, caller, id)
+}
+
+// GetStatement returns the result of OnGetStatement.
+func (mAccUC AccountUseCase) GetStatement(ctx context.Context, caller model.Principal, statementInput usecase.AccountStatementInput) (*usecase.AccountStatementOutput, error) {
+	return mAccUC.OnGetStatement(ctx, caller, statementInput)
+}
+
+// Block returns the result of OnBlock.
+func (mAccUC AccountUseCase) Block(ctx context.Context, caller model.Principal, statusInput usecase.AccountStatusInput) (*usecase.AccountStatusOutput, error) {
+	return mAccUC.OnBlock(ctx, caller, statusInput)
+}
+
+// Unblock returns the result of OnUnblock.
+func (mAccUC AccountUseCase) Unblock(ctx context.Context, caller model.Principal, statusInput usecase.AccountStatusInput) (*usecase.AccountStatusOutput, error) {
+	return mAccUC.OnUnblock(ctx, caller, statusInput)
+}
+
+// Close returns the result of OnClose.
+func (mAccUC AccountUseCase) Close(ctx context.Context, caller model.Principal, closeInput usecase.AccountCloseInput) (*usecase.AccountStatusOutput, error) {
+	return mAccUC.OnClose(ctx, caller, closeInput)
+}
+
+// SetCreditLimit returns the result of OnSetCreditLimit.
+func (mAccUC AccountUseCase) SetCreditLimit(ctx context.Context, caller model.Principal, creditLimitInput usecase.AccountCreditLimitInput) (*usecase.AccountBalanceOutput, error) {
+	return mAccUC.OnSetCreditLimit(ctx, caller, creditLimitInput)
+}
+
+// SetTier returns the result of OnSetTier.
+func (mAccUC AccountUseCase) SetTier(ctx context.Context, caller model.Principal, tierInput usecase.AccountTierInput) (*usecase.AccountTierOutput, error) {
+	return mAccUC.OnSetTier(ctx, caller, tierInput)
 }
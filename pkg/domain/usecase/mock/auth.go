@@ -3,15 +3,16 @@ package mock
 import (
 	"context"
 
-	"github.com/golang-jwt/jwt/v4"
-
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
 )
 
 // AuthUseCase mocks an usecase.AuthUseCase.
 type AuthUseCase struct {
 	OnLogin     func(ctx context.Context, loginInput usecase.AuthLoginInput) (*usecase.AuthTokenOutput, error)
-	OnAuthorize func(ctx context.Context, accessToken string) (*jwt.RegisteredClaims, error)
+	OnRefresh   func(ctx context.Context, refreshInput usecase.AuthRefreshInput) (*usecase.AuthTokenOutput, error)
+	OnLogout    func(ctx context.Context, logoutInput usecase.AuthLogoutInput) error
+	OnAuthorize func(ctx context.Context, accessToken string) (*usecase.AuthClaims, error)
+	OnJWKS      func() *usecase.JWKSOutput
 }
 
 var _ usecase.AuthUseCase = (*AuthUseCase)(nil)
@@ -21,7 +22,22 @@ func (mAuthUC AuthUseCase) Login(ctx context.Context, loginInput usecase.AuthLog
 	return mAuthUC.OnLogin(ctx, loginInput)
 }
 
-// Authorize executes Authorize.
-func (mAuthUC AuthUseCase) Authorize(ctx context.Context, accessToken string) (*jwt.RegisteredClaims, error) {
+// Refresh executes OnRefresh.
+func (mAuthUC AuthUseCase) Refresh(ctx context.Context, refreshInput usecase.AuthRefreshInput) (*usecase.AuthTokenOutput, error) {
+	return mAuthUC.OnRefresh(ctx, refreshInput)
+}
+
+// Logout executes OnLogout.
+func (mAuthUC AuthUseCase) Logout(ctx context.Context, logoutInput usecase.AuthLogoutInput) error {
+	return mAuthUC.OnLogout(ctx, logoutInput)
+}
+
+// Authorize executes OnAuthorize.
+func (mAuthUC AuthUseCase) Authorize(ctx context.Context, accessToken string) (*usecase.AuthClaims, error) {
 	return mAuthUC.OnAuthorize(ctx, accessToken)
 }
+
+// JWKS executes OnJWKS.
+func (mAuthUC AuthUseCase) JWKS() *usecase.JWKSOutput {
+	return mAuthUC.OnJWKS()
+}
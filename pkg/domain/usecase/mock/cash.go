@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+)
+
+// CashUseCase mocks an usecase.CashUseCase.
+type CashUseCase struct {
+	OnDeposit  func(ctx context.Context, caller model.Principal, depositInput usecase.DepositCreateInput) (*usecase.DepositCreateOutput, error)
+	OnWithdraw func(ctx context.Context, withdrawalInput usecase.WithdrawalCreateInput) (*usecase.WithdrawalCreateOutput, error)
+}
+
+var _ usecase.CashUseCase = (*CashUseCase)(nil)
+
+// Deposit returns the result of OnDeposit.
+func (mCashUC CashUseCase) Deposit(ctx context.Context, caller model.Principal, depositInput usecase.DepositCreateInput) (*usecase.DepositCreateOutput, error) {
+	return mCashUC.OnDeposit(ctx, caller, depositInput)
+}
+
+// Withdraw returns the result of OnWithdraw.
+func (mCashUC CashUseCase) Withdraw(ctx context.Context, withdrawalInput usecase.WithdrawalCreateInput) (*usecase.WithdrawalCreateOutput, error) {
+	return mCashUC.OnWithdraw(ctx, withdrawalInput)
+}
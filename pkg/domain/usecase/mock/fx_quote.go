@@ -0,0 +1,19 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+)
+
+// FXQuoteUseCase mocks an usecase.FXQuoteUseCase.
+type FXQuoteUseCase struct {
+	OnCreate func(ctx context.Context, quoteInput usecase.FXQuoteCreateInput) (*usecase.FXQuoteOutput, error)
+}
+
+var _ usecase.FXQuoteUseCase = (*FXQuoteUseCase)(nil)
+
+// Create returns the result of OnCreate.
+func (mQuoteUC FXQuoteUseCase) Create(ctx context.Context, quoteInput usecase.FXQuoteCreateInput) (*usecase.FXQuoteOutput, error) {
+	return mQuoteUC.OnCreate(ctx, quoteInput)
+}
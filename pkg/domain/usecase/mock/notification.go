@@ -0,0 +1,20 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+)
+
+// NotificationUseCase mocks an usecase.NotificationUseCase.
+type NotificationUseCase struct {
+	OnFetch func(ctx context.Context, accountID model.AccountID) ([]usecase.NotificationOutput, error)
+}
+
+var _ usecase.NotificationUseCase = (*NotificationUseCase)(nil)
+
+// Fetch returns the result of OnFetch.
+func (mNtfUC NotificationUseCase) Fetch(ctx context.Context, accountID model.AccountID) ([]usecase.NotificationOutput, error) {
+	return mNtfUC.OnFetch(ctx, accountID)
+}
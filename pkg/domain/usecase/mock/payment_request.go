@@ -0,0 +1,50 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+)
+
+// PaymentRequestUseCase mocks an usecase.PaymentRequestUseCase.
+type PaymentRequestUseCase struct {
+	OnCreate     func(ctx context.Context, requestInput usecase.PaymentRequestCreateInput) (*usecase.PaymentRequestOutput, error)
+	OnFetch      func(ctx context.Context, caller model.Principal, role string, status string) ([]usecase.PaymentRequestOutput, error)
+	OnGet        func(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error)
+	OnApprove    func(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error)
+	OnDecline    func(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error)
+	OnExecuteDue func(ctx context.Context, limit int) (int, error)
+}
+
+var _ usecase.PaymentRequestUseCase = (*PaymentRequestUseCase)(nil)
+
+// Create returns the result of OnCreate.
+func (mPrUC PaymentRequestUseCase) Create(ctx context.Context, requestInput usecase.PaymentRequestCreateInput) (*usecase.PaymentRequestOutput, error) {
+	return mPrUC.OnCreate(ctx, requestInput)
+}
+
+// Fetch returns the result of OnFetch.
+func (mPrUC PaymentRequestUseCase) Fetch(ctx context.Context, caller model.Principal, role string, status string) ([]usecase.PaymentRequestOutput, error) {
+	return mPrUC.OnFetch(ctx, caller, role, status)
+}
+
+// Get returns the result of OnGet.
+func (mPrUC PaymentRequestUseCase) Get(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error) {
+	return mPrUC.OnGet(ctx, caller, id)
+}
+
+// Approve returns the result of OnApprove.
+func (mPrUC PaymentRequestUseCase) Approve(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error) {
+	return mPrUC.OnApprove(ctx, caller, id)
+}
+
+// Decline returns the result of OnDecline.
+func (mPrUC PaymentRequestUseCase) Decline(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error) {
+	return mPrUC.OnDecline(ctx, caller, id)
+}
+
+// ExecuteDue returns the result of OnExecuteDue.
+func (mPrUC PaymentRequestUseCase) ExecuteDue(ctx context.Context, limit int) (int, error) {
+	return mPrUC.OnExecuteDue(ctx, limit)
+}
@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+)
+
+// PixKeyUseCase mocks an usecase.PixKeyUseCase.
+type PixKeyUseCase struct {
+	OnCreate func(ctx context.Context, caller model.Principal, createInput usecase.PixKeyCreateInput) (*usecase.PixKeyOutput, error)
+	OnVerify func(ctx context.Context, caller model.Principal, verifyInput usecase.PixKeyVerifyInput) (*usecase.PixKeyOutput, error)
+	OnFetch  func(ctx context.Context, caller model.Principal) ([]usecase.PixKeyOutput, error)
+	OnDelete func(ctx context.Context, caller model.Principal, key string) error
+	OnLookup func(ctx context.Context, caller model.Principal, key string) (*usecase.PixKeyLookupOutput, error)
+
+	OnCreateBRCode func(ctx context.Context, caller model.Principal, brCodeInput usecase.BRCodeCreateInput) (*usecase.BRCodeOutput, error)
+}
+
+var _ usecase.PixKeyUseCase = (*PixKeyUseCase)(nil)
+
+// Create returns the result of OnCreate.
+func (mKeyUC PixKeyUseCase) Create(ctx context.Context, caller model.Principal, createInput usecase.PixKeyCreateInput) (*usecase.PixKeyOutput, error) {
+	return mKeyUC.OnCreate(ctx, caller, createInput)
+}
+
+// Verify returns the result of OnVerify.
+func (mKeyUC PixKeyUseCase) Verify(ctx context.Context, caller model.Principal, verifyInput usecase.PixKeyVerifyInput) (*usecase.PixKeyOutput, error) {
+	return mKeyUC.OnVerify(ctx, caller, verifyInput)
+}
+
+// Fetch returns the result of OnFetch.
+func (mKeyUC PixKeyUseCase) Fetch(ctx context.Context, caller model.Principal) ([]usecase.PixKeyOutput, error) {
+	return mKeyUC.OnFetch(ctx, caller)
+}
+
+// Delete returns the result of OnDelete.
+func (mKeyUC PixKeyUseCase) Delete(ctx context.Context, caller model.Principal, key string) error {
+	return mKeyUC.OnDelete(ctx, caller, key)
+}
+
+// Lookup returns the result of OnLookup.
+func (mKeyUC PixKeyUseCase) Lookup(ctx context.Context, caller model.Principal, key string) (*usecase.PixKeyLookupOutput, error) {
+	return mKeyUC.OnLookup(ctx, caller, key)
+}
+
+// CreateBRCode returns the result of OnCreateBRCode.
+func (mKeyUC PixKeyUseCase) CreateBRCode(ctx context.Context, caller model.Principal, brCodeInput usecase.BRCodeCreateInput) (*usecase.BRCodeOutput, error) {
+	return mKeyUC.OnCreateBRCode(ctx, caller, brCodeInput)
+}
@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+)
+
+// SavingsUseCase mocks an usecase.SavingsUseCase.
+type SavingsUseCase struct {
+	OnExecuteDue func(ctx context.Context, limit int) (int, error)
+	OnGetHistory func(ctx context.Context, caller model.Principal, historyInput usecase.SavingsHistoryInput) (*usecase.SavingsHistoryOutput, error)
+}
+
+var _ usecase.SavingsUseCase = (*SavingsUseCase)(nil)
+
+// ExecuteDue returns the result of OnExecuteDue.
+func (mSavUC SavingsUseCase) ExecuteDue(ctx context.Context, limit int) (int, error) {
+	return mSavUC.OnExecuteDue(ctx, limit)
+}
+
+// GetHistory returns the result of OnGetHistory.
+func (mSavUC SavingsUseCase) GetHistory(ctx context.Context, caller model.Principal, historyInput usecase.SavingsHistoryInput) (*usecase.SavingsHistoryOutput, error) {
+	return mSavUC.OnGetHistory(ctx, caller, historyInput)
+}
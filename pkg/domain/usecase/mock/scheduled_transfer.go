@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+)
+
+// ScheduledTransferUseCase mocks an usecase.ScheduledTransferUseCase.
+type ScheduledTransferUseCase struct {
+	OnCreate     func(ctx context.Context, scheduleInput usecase.ScheduledTransferCreateInput) (*usecase.ScheduledTransferOutput, error)
+	OnFetch      func(ctx context.Context, originID model.AccountID, status string) ([]usecase.ScheduledTransferOutput, error)
+	OnCancel     func(ctx context.Context, caller model.Principal, id model.ScheduledTransferID) (*usecase.ScheduledTransferOutput, error)
+	OnExecuteDue func(ctx context.Context, limit int) (int, error)
+}
+
+var _ usecase.ScheduledTransferUseCase = (*ScheduledTransferUseCase)(nil)
+
+// Create returns the result of OnCreate.
+func (mSchUC ScheduledTransferUseCase) Create(ctx context.Context, scheduleInput usecase.ScheduledTransferCreateInput) (*usecase.ScheduledTransferOutput, error) {
+	return mSchUC.OnCreate(ctx, scheduleInput)
+}
+
+// Fetch returns the result of OnFetch.
+func (mSchUC ScheduledTransferUseCase) Fetch(ctx context.Context, originID model.AccountID, status string) ([]usecase.ScheduledTransferOutput, error) {
+	return mSchUC.OnFetch(ctx, originID, status)
+}
+
+// Cancel returns the result of OnCancel.
+func (mSchUC ScheduledTransferUseCase) Cancel(ctx context.Context, caller model.Principal, id model.ScheduledTransferID) (*usecase.ScheduledTransferOutput, error) {
+	return mSchUC.OnCancel(ctx, caller, id)
+}
+
+// ExecuteDue returns the result of OnExecuteDue.
+func (mSchUC ScheduledTransferUseCase) ExecuteDue(ctx context.Context, limit int) (int, error) {
+	return mSchUC.OnExecuteDue(ctx, limit)
+}
@@ -0,0 +1,56 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+)
+
+// StandingOrderUseCase mocks an usecase.StandingOrderUseCase.
+type StandingOrderUseCase struct {
+	OnCreate     func(ctx context.Context, orderInput usecase.StandingOrderCreateInput) (*usecase.StandingOrderOutput, error)
+	OnFetch      func(ctx context.Context, originID model.AccountID) ([]usecase.StandingOrderOutput, error)
+	OnUpdate     func(ctx context.Context, caller model.Principal, orderInput usecase.StandingOrderUpdateInput) (*usecase.StandingOrderOutput, error)
+	OnPause      func(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*usecase.StandingOrderOutput, error)
+	OnResume     func(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*usecase.StandingOrderOutput, error)
+	OnCancel     func(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*usecase.StandingOrderOutput, error)
+	OnExecuteDue func(ctx context.Context, limit int) (int, error)
+}
+
+var _ usecase.StandingOrderUseCase = (*StandingOrderUseCase)(nil)
+
+// Create returns the result of OnCreate.
+func (mSoUC StandingOrderUseCase) Create(ctx context.Context, orderInput usecase.StandingOrderCreateInput) (*usecase.StandingOrderOutput, error) {
+	return mSoUC.OnCreate(ctx, orderInput)
+}
+
+// Fetch returns the result of OnFetch.
+func (mSoUC StandingOrderUseCase) Fetch(ctx context.Context, originID model.AccountID) ([]usecase.StandingOrderOutput, error) {
+	return mSoUC.OnFetch(ctx, originID)
+}
+
+// Update returns the result of OnUpdate.
+func (mSoUC StandingOrderUseCase) Update(ctx context.Context, caller model.Principal, orderInput usecase.StandingOrderUpdateInput) (*usecase.StandingOrderOutput, error) {
+	return mSoUC.OnUpdate(ctx, caller, orderInput)
+}
+
+// Pause returns the result of OnPause.
+func (mSoUC StandingOrderUseCase) Pause(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*usecase.StandingOrderOutput, error) {
+	return mSoUC.OnPause(ctx, caller, id)
+}
+
+// Resume returns the result of OnResume.
+func (mSoUC StandingOrderUseCase) Resume(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*usecase.StandingOrderOutput, error) {
+	return mSoUC.OnResume(ctx, caller, id)
+}
+
+// Cancel returns the result of OnCancel.
+func (mSoUC StandingOrderUseCase) Cancel(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*usecase.StandingOrderOutput, error) {
+	return mSoUC.OnCancel(ctx, caller, id)
+}
+
+// ExecuteDue returns the result of OnExecuteDue.
+func (mSoUC StandingOrderUseCase) ExecuteDue(ctx context.Context, limit int) (int, error) {
+	return mSoUC.OnExecuteDue(ctx, limit)
+}
@@ -9,8 +9,11 @@ import (
 
 // TransferUseCase mocks an usecase.TransferUseCase.
 type TransferUseCase struct {
-	OnCreate func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error)
-	OnFetch  func(ctx context.Context, accountID model.AccountID) ([]usecase.TransferFetchOutput, error)
+	OnCreate   func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error)
+	OnFetch    func(ctx context.Context, fetchInput usecase.TransferFetchInput) (*usecase.TransferFetchPageOutput, error)
+	OnRefund   func(ctx context.Context, caller model.Principal, refundInput usecase.TransferRefundInput) (*usecase.TransferCreateOutput, error)
+	OnReverse  func(ctx context.Context, caller model.Principal, id model.TransferID) (*usecase.TransferCreateOutput, error)
+	OnQuoteFee func(ctx context.Context, quoteInput usecase.TransferFeeQuoteInput) (*usecase.TransferFeeQuoteOutput, error)
 }
 
 var _ usecase.TransferUseCase = (*TransferUseCase)(nil)
@@ -21,6 +24,21 @@ func (mTrfUC TransferUseCase) Create(ctx context.Context, transferInput usecase.
 }
 
 // Fetch returns the result of OnFetch.
-func (mTrfUC TransferUseCase) Fetch(ctx context.Context, accountID model.AccountID) ([]usecase.TransferFetchOutput, error) {
-	return mTrfUC.OnFetch(ctx, accountID)
+func (mTrfUC TransferUseCase) Fetch(ctx context.Context, fetchInput usecase.TransferFetchInput) (*usecase.TransferFetchPageOutput, error) {
+	return mTrfUC.OnFetch(ctx, fetchInput)
+}
+
+// Refund returns the result of OnRefund.
+func (mTrfUC TransferUseCase) Refund(ctx context.Context, caller model.Principal, refundInput usecase.TransferRefundInput) (*usecase.TransferCreateOutput, error) {
+	return mTrfUC.OnRefund(ctx, caller, refundInput)
+}
+
+// Reverse returns the result of OnReverse.
+func (mTrfUC TransferUseCase) Reverse(ctx context.Context, caller model.Principal, id model.TransferID) (*usecase.TransferCreateOutput, error) {
+	return mTrfUC.OnReverse(ctx, caller, id)
+}
+
+// QuoteFee returns the result of OnQuoteFee.
+func (mTrfUC TransferUseCase) QuoteFee(ctx context.Context, quoteInput usecase.TransferFeeQuoteInput) (*usecase.TransferFeeQuoteOutput, error) {
+	return mTrfUC.OnQuoteFee(ctx, quoteInput)
 }
@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+)
+
+// TransferBatchUseCase mocks an usecase.TransferBatchUseCase.
+type TransferBatchUseCase struct {
+	OnCreate func(ctx context.Context, batchInput usecase.TransferBatchCreateInput) (*usecase.TransferBatchOutput, error)
+	OnGet    func(ctx context.Context, caller model.Principal, id model.TransferBatchID) (*usecase.TransferBatchOutput, error)
+}
+
+var _ usecase.TransferBatchUseCase = (*TransferBatchUseCase)(nil)
+
+// Create returns the result of OnCreate.
+func (mBatchUC TransferBatchUseCase) Create(ctx context.Context, batchInput usecase.TransferBatchCreateInput) (*usecase.TransferBatchOutput, error) {
+	return mBatchUC.OnCreate(ctx, batchInput)
+}
+
+// Get returns the result of OnGet.
+func (mBatchUC TransferBatchUseCase) Get(ctx context.Context, caller model.Principal, id model.TransferBatchID) (*usecase.TransferBatchOutput, error) {
+	return mBatchUC.OnGet(ctx, caller, id)
+}
@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+)
+
+// TransferLimitUseCase mocks an usecase.TransferLimitUseCase.
+type TransferLimitUseCase struct {
+	OnGet func(ctx context.Context, caller model.Principal, accountID model.AccountID) (*usecase.TransferLimitsOutput, error)
+	OnSet func(ctx context.Context, caller model.Principal, limitsInput usecase.TransferLimitsInput) (*usecase.TransferLimitsOutput, error)
+}
+
+var _ usecase.TransferLimitUseCase = (*TransferLimitUseCase)(nil)
+
+// Get returns the result of OnGet.
+func (mLimitUC TransferLimitUseCase) Get(ctx context.Context, caller model.Principal, accountID model.AccountID) (*usecase.TransferLimitsOutput, error) {
+	return mLimitUC.OnGet(ctx, caller, accountID)
+}
+
+// Set returns the result of OnSet.
+func (mLimitUC TransferLimitUseCase) Set(ctx context.Context, caller model.Principal, limitsInput usecase.TransferLimitsInput) (*usecase.TransferLimitsOutput, error) {
+	return mLimitUC.OnSet(ctx, caller, limitsInput)
+}
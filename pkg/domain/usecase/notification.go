@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// NotificationFetchLimit is how many of the latest notifications are returned.
+const NotificationFetchLimit = 50
+
+var (
+	// ErrNotificationFetch happens when an error occurred while fetching the notifications.
+	ErrNotificationFetch = errors.New("could not fetch notifications")
+)
+
+// NotificationUseCase is the interface that wraps all business logic methods related to the notifications.
+type NotificationUseCase interface {
+	Fetch(ctx context.Context, accountID model.AccountID) ([]NotificationOutput, error)
+}
+
+type notificationUseCase struct {
+	ntfRepo repository.NotificationRepository
+}
+
+// NewNotificationUseCase instantiates a new NotificationUseCase.
+func NewNotificationUseCase(ntfRepo repository.NotificationRepository) NotificationUseCase {
+	return &notificationUseCase{ntfRepo: ntfRepo}
+}
+
+// NotificationOutput represents a notification to the account holder.
+type NotificationOutput struct {
+	ID          string    `json:"id" example:"9b2e7c1a-5d4f-4a3b-8c6d-0e1f2a3b4c5d"`
+	Type        string    `json:"type" example:"standing_order_retrying" enums:"standing_order_retrying,standing_order_failed"`
+	ReferenceID string    `json:"reference_id,omitempty" example:"3f1c5b7e-8d2a-4c6f-9e0b-1a2b3c4d5e6f"`
+	Message     string    `json:"message" example:"The standing order payment of 1500.00 to account ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d failed: current account balance is insufficient. It will be retried at 2021-01-05T13:00:00-03:00."`
+	CreatedAt   time.Time `json:"created_at" example:"2021-01-05T09:00:01.999999-03:00"`
+}
+
+// Fetch returns the latest notifications of the account, the newest first.
+func (ntfUC notificationUseCase) Fetch(ctx context.Context, accountID model.AccountID) ([]NotificationOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	notifications, err := ntfUC.ntfRepo.Fetch(ctx, accountID, NotificationFetchLimit)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error fetching notifications")
+		return nil, ErrNotificationFetch
+	}
+
+	outputs := make([]NotificationOutput, 0, len(notifications))
+	for _, notification := range notifications {
+		outputs = append(outputs, NotificationOutput{
+			ID:          string(notification.ID),
+			Type:        string(notification.Type),
+			ReferenceID: notification.ReferenceID,
+			Message:     notification.Message,
+			CreatedAt:   notification.CreatedAt,
+		})
+	}
+
+	return outputs, nil
+}
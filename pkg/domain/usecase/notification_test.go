@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_notificationUseCase_Fetch(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	createdAt := time.Now()
+
+	tests := []struct {
+		name    string
+		ntfRepo repository.NotificationRepository
+		want    []NotificationOutput
+		wantErr error
+	}{
+		{
+			name: "repository error should return fetch error",
+			ntfRepo: mock.NotificationRepository{
+				OnFetch: func(ctx context.Context, accountID model.AccountID, limit int) ([]model.Notification, error) {
+					return nil, errors.New("any database error")
+				},
+			},
+			wantErr: ErrNotificationFetch,
+		},
+		{
+			name: "no notifications should return empty",
+			ntfRepo: mock.NotificationRepository{
+				OnFetch: func(ctx context.Context, accountID model.AccountID, limit int) ([]model.Notification, error) {
+					return []model.Notification{}, nil
+				},
+			},
+			want: []NotificationOutput{},
+		},
+		{
+			name: "should return the latest notifications of the account",
+			ntfRepo: mock.NotificationRepository{
+				OnFetch: func(ctx context.Context, accountID model.AccountID, limit int) ([]model.Notification, error) {
+					if accountID != "uuid-1" || limit != NotificationFetchLimit {
+						return nil, errors.New("unexpected arguments")
+					}
+					return []model.Notification{
+						{ID: "ntf-uuid", AccountID: accountID, Type: model.NotificationStandingOrderFailed, ReferenceID: "so-uuid", Message: "any message", CreatedAt: createdAt},
+					}, nil
+				},
+			},
+			want: []NotificationOutput{
+				{ID: "ntf-uuid", Type: "standing_order_failed", ReferenceID: "so-uuid", Message: "any message", CreatedAt: createdAt},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ntfUC := NewNotificationUseCase(tt.ntfRepo)
+			got, err := ntfUC.Fetch(backgroundCtx, "uuid-1")
+			if err != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fetch() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
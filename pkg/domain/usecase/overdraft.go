@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/infraestructure/monitoring"
+)
+
+// OverdraftInterestPolicy defines the interest charged on the negative balances, the account it's credited to and
+// the time zone of its days. The interest is only charged to the accounts in the currency of the RevenueAccountID.
+// An empty RevenueAccountID or a zero MonthlyRate charges no interest and a nil Location means UTC.
+type OverdraftInterestPolicy struct {
+	MonthlyRate      model.InterestRate
+	RevenueAccountID model.AccountID
+	Location         *time.Location
+}
+
+// dayEnded returns the midnight starting the last day that ended before now.
+func (policy OverdraftInterestPolicy) dayEnded(now time.Time) time.Time {
+	location := policy.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	now = now.In(location)
+	return time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, location)
+}
+
+// OverdraftUseCase is the interface that wraps all business logic methods related to the overdraft.
+type OverdraftUseCase interface {
+	ExecuteDue(ctx context.Context, limit int) (int, error)
+}
+
+type overdraftUseCase struct {
+	odRepo         repository.OverdraftRepository
+	accRepo        repository.AccountRepository
+	ledgerRepo     repository.LedgerRepository
+	interestPolicy OverdraftInterestPolicy
+}
+
+// NewOverdraftUseCase instantiates a new OverdraftUseCase.
+func NewOverdraftUseCase(odRepo repository.OverdraftRepository, accRepo repository.AccountRepository, ledgerRepo repository.LedgerRepository, interestPolicy OverdraftInterestPolicy) OverdraftUseCase {
+	return &overdraftUseCase{
+		odRepo:         odRepo,
+		accRepo:        accRepo,
+		ledgerRepo:     ledgerRepo,
+		interestPolicy: interestPolicy,
+	}
+}
+
+// ExecuteDue charges the interest of the last day that ended on up to limit overdrawn accounts, returning how many
+// were charged.
+//
+// The accounts are picked and charged by their closing balance of the day, rebuilt from the ledger, so the movements
+// made since midnight don't change it: the ones overdrawn at midnight are charged even if repaid since, and the ones
+// overdrawn since are not. Each account is charged once a day, in its own transaction holding its row lock, so it's
+// safe to run on multiple replicas and again on the same day.
+// The days the executor didn't run are not charged later.
+func (odUC overdraftUseCase) ExecuteDue(ctx context.Context, limit int) (int, error) {
+	if odUC.interestPolicy.RevenueAccountID == "" || odUC.interestPolicy.MonthlyRate <= 0 {
+		return 0, nil
+	}
+
+	day := odUC.interestPolicy.dayEnded(time.Now())
+	filter := model.OverdraftInterestFilter{
+		Day:              day,
+		DayEnd:           day.AddDate(0, 0, 1),
+		RevenueAccountID: odUC.interestPolicy.RevenueAccountID,
+	}
+
+	processed := 0
+	for processed < limit {
+		charge, err := odUC.chargeNextOverdrawn(ctx, &filter)
+		if err != nil {
+			return processed, err
+		}
+		if charge == nil {
+			break
+		}
+
+		processed++
+		monitoring.OverdraftInterestCharges.Inc()
+		log.Ctx(ctx).Info().Str("accountID", string(charge.AccountID)).Str("day", charge.Day.Format("2006-01-02")).
+			Int64("balance", int64(charge.Balance)).Int64("amount", int64(charge.Amount)).Msg("overdraft interest charged")
+	}
+
+	return processed, nil
+}
+
+// chargeNextOverdrawn charges the interest of the day on the next overdrawn account, returning the charge,
+// or nil if there's none.
+func (odUC overdraftUseCase) chargeNextOverdrawn(ctx context.Context, filter *model.OverdraftInterestFilter) (*model.OverdraftInterestCharge, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	data, err := odUC.odRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		// the revenue account is only read, like the maintenance fee does, the interest is credited by the ledger
+		// posting after the charged account is locked
+		revenueAccount, err := odUC.accRepo.GetBalance(txCtx, odUC.interestPolicy.RevenueAccountID)
+		if err != nil {
+			return nil, err
+		}
+		filter.Currency = revenueAccount.Currency
+
+		account, err := odUC.odRepo.LockNextOverdrawn(txCtx, *filter)
+		if err != nil || account == nil {
+			return nil, err
+		}
+
+		// the movements made since midnight don't count. Had the balance not been negative then, nothing is charged,
+		// but the day is still recorded, so the account isn't picked again
+		balance, err := odUC.ledgerRepo.GetBalanceAt(txCtx, account.ID, account.Currency, filter.DayEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		charge := model.NewOverdraftInterestCharge(account.ID, filter.Day, balance, odUC.interestPolicy.MonthlyRate)
+
+		// the interest is charged even beyond the credit limit, it's not spent by the holder
+		if charge.Amount > 0 {
+			posting := model.NewLedgerPosting(
+				model.LedgerPostingOverdraftInterest,
+				string(charge.ID),
+				account.ID,
+				revenueAccount.ID,
+				charge.Amount,
+				revenueAccount.Currency)
+
+			err = odUC.ledgerRepo.Post(txCtx, posting)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return charge, odUC.odRepo.CreateInterestCharge(txCtx, charge)
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error charging overdraft interest")
+		return nil, err
+	}
+
+	charge, _ := data.(*model.OverdraftInterestCharge)
+	return charge, nil
+}
@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func TestOverdraftInterestPolicy_dayEnded(t *testing.T) {
+	t.Parallel()
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		now      time.Time
+		want     time.Time
+	}{
+		{
+			name: "nil location should be UTC",
+			now:  time.Date(2021, 3, 1, 0, 5, 0, 0, time.UTC),
+			want: time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "should be the day ended in the location",
+			location: saoPaulo,
+			now:      time.Date(2021, 3, 15, 2, 0, 0, 0, time.UTC),
+			want:     time.Date(2021, 3, 13, 0, 0, 0, 0, saoPaulo),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := OverdraftInterestPolicy{MonthlyRate: 800, Location: tt.location}
+			if got := policy.dayEnded(tt.now); !got.Equal(tt.want) {
+				t.Errorf("dayEnded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_overdraftUseCase_ExecuteDue(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	// overdrawnAccounts returns the accounts one by one, then none
+	overdrawnAccounts := func(balances ...model.Money) func(ctx context.Context, filter model.OverdraftInterestFilter) (*model.Account, error) {
+		return func(ctx context.Context, filter model.OverdraftInterestFilter) (*model.Account, error) {
+			if len(balances) == 0 {
+				return nil, nil
+			}
+			balance := balances[0]
+			balances = balances[1:]
+			return &model.Account{ID: "uuid-1", Currency: model.CurrencyBRL, Balance: balance, CreditLimit: 500000, Status: model.AccountStatusActive}, nil
+		}
+	}
+	revenue := &model.Account{ID: "revenue-uuid", Currency: model.CurrencyBRL, Status: model.AccountStatusActive}
+
+	type fields struct {
+		lockNextOverdrawn  func(ctx context.Context, filter model.OverdraftInterestFilter) (*model.Account, error)
+		movedSinceMidnight model.Money
+		postErr            error
+		getRevenueErr      error
+		noRevenueAccount   bool
+		monthlyRate        model.InterestRate
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		limit       int
+		want        int
+		wantErr     bool
+		wantAmounts []model.Money
+		wantPosted  []model.Money
+	}{
+		{
+			name: "zero rate should charge none",
+			fields: fields{
+				lockNextOverdrawn: overdrawnAccounts(-300000),
+				monthlyRate:       0,
+			},
+			limit: 10,
+			want:  0,
+		},
+		{
+			name: "no revenue account should charge none",
+			fields: fields{
+				lockNextOverdrawn: overdrawnAccounts(-300000),
+				noRevenueAccount:  true,
+				monthlyRate:       1000,
+			},
+			limit: 10,
+			want:  0,
+		},
+		{
+			name: "no overdrawn account should charge none",
+			fields: fields{
+				lockNextOverdrawn: overdrawnAccounts(),
+				monthlyRate:       1000,
+			},
+			limit: 10,
+			want:  0,
+		},
+		{
+			name: "overdrawn accounts should be charged and posted",
+			fields: fields{
+				lockNextOverdrawn: overdrawnAccounts(-300000, -600000),
+				monthlyRate:       1000,
+			},
+			limit:       10,
+			want:        2,
+			wantAmounts: []model.Money{1000, 2000},
+			wantPosted:  []model.Money{1000, 2000},
+		},
+		{
+			name: "interest below a cent should be charged without posting",
+			fields: fields{
+				lockNextOverdrawn: overdrawnAccounts(-100),
+				monthlyRate:       1000,
+			},
+			limit:       10,
+			want:        1,
+			wantAmounts: []model.Money{0},
+		},
+		{
+			name: "should be charged on the balance at the end of the day",
+			fields: fields{
+				lockNextOverdrawn:  overdrawnAccounts(-300000),
+				movedSinceMidnight: 300000,
+				monthlyRate:        1000,
+			},
+			limit:       10,
+			want:        1,
+			wantAmounts: []model.Money{2000},
+			wantPosted:  []model.Money{2000},
+		},
+		{
+			name: "not overdrawn at the end of the day should be charged nothing",
+			fields: fields{
+				lockNextOverdrawn:  overdrawnAccounts(-300000),
+				movedSinceMidnight: -400000,
+				monthlyRate:        1000,
+			},
+			limit:       10,
+			want:        1,
+			wantAmounts: []model.Money{0},
+		},
+		{
+			name: "should stop at the limit",
+			fields: fields{
+				lockNextOverdrawn: overdrawnAccounts(-300000, -300000, -300000),
+				monthlyRate:       1000,
+			},
+			limit:       2,
+			want:        2,
+			wantAmounts: []model.Money{1000, 1000},
+			wantPosted:  []model.Money{1000, 1000},
+		},
+		{
+			name: "ledger error should not charge and return error",
+			fields: fields{
+				lockNextOverdrawn: overdrawnAccounts(-300000),
+				postErr:           errors.New("any database error"),
+				monthlyRate:       1000,
+			},
+			limit:   10,
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "revenue account error should not charge and return error",
+			fields: fields{
+				lockNextOverdrawn: overdrawnAccounts(-300000),
+				getRevenueErr:     errors.New("any database error"),
+				monthlyRate:       1000,
+			},
+			limit:   10,
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "lock error should return error",
+			fields: fields{
+				lockNextOverdrawn: func(ctx context.Context, filter model.OverdraftInterestFilter) (*model.Account, error) {
+					return nil, errors.New("any database error")
+				},
+				monthlyRate: 1000,
+			},
+			limit:   10,
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var charged, posted []model.Money
+			var locked *model.Account
+			odRepo := mock.OverdraftRepository{
+				OnWithinTransaction: withinTransaction,
+				OnLockNextOverdrawn: func(ctx context.Context, filter model.OverdraftInterestFilter) (*model.Account, error) {
+					var err error
+					if !filter.DayEnd.Equal(filter.Day.AddDate(0, 0, 1)) {
+						return nil, errors.New("should pick the accounts by the balance at the end of the day")
+					}
+					if filter.Currency != revenue.Currency || filter.RevenueAccountID != revenue.ID {
+						return nil, errors.New("should pick the accounts in the currency of the revenue account")
+					}
+					locked, err = tt.fields.lockNextOverdrawn(ctx, filter)
+					return locked, err
+				},
+				OnCreateInterestCharge: func(ctx context.Context, charge *model.OverdraftInterestCharge) error {
+					charged = append(charged, charge.Amount)
+					return nil
+				},
+			}
+			accRepo := mock.AccountRepository{
+				OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+					if id != revenue.ID {
+						return nil, errors.New("should read the revenue account")
+					}
+					if tt.fields.getRevenueErr != nil {
+						return nil, tt.fields.getRevenueErr
+					}
+					return revenue, nil
+				},
+			}
+			ledgerRepo := mock.LedgerRepository{
+				OnGetBalanceAt: func(ctx context.Context, accountID model.AccountID, currency model.Currency, at time.Time) (model.Money, error) {
+					if accountID != locked.ID || currency != locked.Currency || at.Hour() != 0 || !at.After(time.Now().AddDate(0, 0, -1)) {
+						return 0, errors.New("should read the balance of the account at the midnight ending the day")
+					}
+					return locked.Balance - tt.fields.movedSinceMidnight, nil
+				},
+				OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+					if tt.fields.postErr != nil {
+						return tt.fields.postErr
+					}
+					if posting.Kind != model.LedgerPostingOverdraftInterest || posting.DebitAccountID != "uuid-1" ||
+						posting.CreditAccountID != revenue.ID || posting.Currency != revenue.Currency {
+						return errors.New("should debit the interest from the account and credit it to the revenue account")
+					}
+					posted = append(posted, posting.Amount)
+					return nil
+				},
+			}
+			policy := OverdraftInterestPolicy{MonthlyRate: tt.fields.monthlyRate, RevenueAccountID: revenue.ID}
+			if tt.fields.noRevenueAccount {
+				policy.RevenueAccountID = ""
+			}
+			odUC := NewOverdraftUseCase(odRepo, accRepo, ledgerRepo, policy)
+
+			got, err := odUC.ExecuteDue(backgroundCtx, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecuteDue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want || len(charged) != tt.want {
+				t.Errorf("ExecuteDue() got = %v and charged %v, want %v", got, len(charged), tt.want)
+			}
+			if len(charged) != len(tt.wantAmounts) || len(posted) != len(tt.wantPosted) {
+				t.Fatalf("ExecuteDue() charged = %v and posted %v, want %v and %v", charged, posted, tt.wantAmounts, tt.wantPosted)
+			}
+			for i := range charged {
+				if charged[i] != tt.wantAmounts[i] {
+					t.Errorf("ExecuteDue() charged = %v, want %v", charged, tt.wantAmounts)
+				}
+			}
+			for i := range posted {
+				if posted[i] != tt.wantPosted[i] {
+					t.Errorf("ExecuteDue() posted = %v, want %v", posted, tt.wantPosted)
+				}
+			}
+		})
+	}
+}
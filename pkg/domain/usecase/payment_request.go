@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// PaymentRequestUseCase is the interface that wraps all business logic methods related to the payment requests.
+type PaymentRequestUseCase interface {
+	Create(ctx context.Context, requestInput PaymentRequestCreateInput) (*PaymentRequestOutput, error)
+	Fetch(ctx context.Context, caller model.Principal, role string, status string) ([]PaymentRequestOutput, error)
+	Get(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*PaymentRequestOutput, error)
+	Approve(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*PaymentRequestOutput, error)
+	Decline(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*PaymentRequestOutput, error)
+	ExecuteDue(ctx context.Context, limit int) (int, error)
+}
+
+type paymentRequestUseCase struct {
+	prRepo  repository.PaymentRequestRepository
+	accRepo repository.AccountRepository
+	trfUC   transferUseCase
+}
+
+// NewPaymentRequestUseCase instantiates a new PaymentRequestUseCase.
+// The approved requests are paid with the same logic of TransferUseCase.Create.
+func NewPaymentRequestUseCase(
+	prRepo repository.PaymentRequestRepository,
+	trfRepo repository.TransferRepository,
+	accRepo repository.AccountRepository,
+	ledgerRepo repository.LedgerRepository,
+	limitRepo repository.TransferLimitRepository,
+	limitPolicy TransferLimitPolicy,
+	feePolicy FeePolicy,
+) PaymentRequestUseCase {
+	return &paymentRequestUseCase{
+		prRepo:  prRepo,
+		accRepo: accRepo,
+		trfUC: transferUseCase{
+			trfRepo:     trfRepo,
+			accRepo:     accRepo,
+			ledgerRepo:  ledgerRepo,
+			limitRepo:   limitRepo,
+			limitPolicy: limitPolicy,
+			feePolicy:   feePolicy,
+		},
+	}
+}
+
+// PaymentRequestOutput represents a payment request with its outcome, if closed.
+// The shared requests have the Link to be sent to the payers.
+type PaymentRequestOutput struct {
+	ID                 string     `json:"id" example:"7d3f5a1b-2c4e-4f6a-8b9c-0d1e2f3a4b5c"`
+	RequesterAccountID string     `json:"requester_account_id" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
+	PayerAccountID     string     `json:"payer_account_id,omitempty" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	Amount             Amount     `json:"amount" swaggertype:"number" example:"42.5"`
+	Description        string     `json:"description,omitempty" example:"Donuts"`
+	ExpiresAt          time.Time  `json:"expires_at" example:"2021-01-07T09:00:00-03:00"`
+	Status             string     `json:"status" example:"pending" enums:"pending,paid,declined,expired"`
+	TransferID         string     `json:"transfer_id,omitempty" example:"e82706ef-9ffb-45a2-8081-547accd818c4"`
+	Link               string     `json:"link,omitempty" example:"/payment-requests/7d3f5a1b-2c4e-4f6a-8b9c-0d1e2f3a4b5c"`
+	CreatedAt          time.Time  `json:"created_at" example:"2020-12-31T09:00:00.999999-03:00"`
+	ClosedAt           *time.Time `json:"closed_at,omitempty" example:"2021-01-02T18:30:00.999999-03:00"`
+}
+
+func newPaymentRequestOutput(request *model.PaymentRequest) PaymentRequestOutput {
+	output := PaymentRequestOutput{
+		ID:                 string(request.ID),
+		RequesterAccountID: string(request.RequesterID),
+		PayerAccountID:     string(request.PayerID),
+		Amount:             NewAmountIn(request.Amount, request.Currency),
+		Description:        request.Description,
+		ExpiresAt:          request.ExpiresAt,
+		Status:             string(request.Status),
+		TransferID:         string(request.TransferID),
+		CreatedAt:          request.CreatedAt,
+	}
+	if request.IsShared() {
+		output.Link = "/payment-requests/" + string(request.ID)
+	}
+	if !request.ClosedAt.IsZero() {
+		closedAt := request.ClosedAt
+		output.ClosedAt = &closedAt
+	}
+
+	return output
+}
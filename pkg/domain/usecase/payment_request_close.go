@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrPaymentRequestNotPayer happens when the requester approves its own payment request, or declines it.
+	ErrPaymentRequestNotPayer = errors.New("only the payer can approve or decline the payment request")
+	// ErrPaymentRequestSharedDecline happens when declining a payment request shared by link.
+	ErrPaymentRequestSharedDecline = errors.New("shared payment requests can't be declined")
+	// ErrPaymentRequestNotPending happens when approving or declining a request already paid, declined or expired.
+	ErrPaymentRequestNotPending = errors.New("payment request was already paid, declined or expired")
+	// ErrPaymentRequestExpired happens when approving or declining a request after its expiration.
+	ErrPaymentRequestExpired = errors.New("payment request is expired")
+	// ErrPaymentRequestApprove happens when an error occurred and the payment request was not paid.
+	ErrPaymentRequestApprove = errors.New("could not approve payment request")
+	// ErrPaymentRequestDecline happens when an error occurred and the payment request was not declined.
+	ErrPaymentRequestDecline = errors.New("could not decline payment request")
+)
+
+// Approve pays a pending request with a transfer from the caller account to the requester, marking it paid in the
+// same transaction, so the request is never paid twice nor marked paid without the transfer.
+// When the transfer is rejected, like for insufficient balance, the error is returned and the request is kept pending.
+func (prUC paymentRequestUseCase) Approve(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*PaymentRequestOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	request, err := prUC.close(ctx, caller, id, func(txCtx context.Context, request *model.PaymentRequest) error {
+		if !request.CanBePaidBy(caller.AccountID) {
+			return ErrPaymentRequestNotPayer
+		}
+
+		transfer := model.NewTransfer(string(caller.AccountID), string(request.RequesterID), request.Amount)
+		err := prUC.trfUC.execute(txCtx, transfer)
+		if err != nil {
+			return err
+		}
+
+		request.Paid(caller.AccountID, transfer.ID)
+		return nil
+	})
+	if err != nil {
+		if isPaymentRequestRejection(err) || isTransferRejection(err) {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("id", string(id)).Msg("error approving payment request")
+		return nil, ErrPaymentRequestApprove
+	}
+
+	log.Ctx(ctx).Info().Str("id", string(request.ID)).Str("transferID", string(request.TransferID)).Str("by", string(caller.AccountID)).Msg("payment request paid")
+
+	output := newPaymentRequestOutput(request)
+	return &output, nil
+}
+
+// Decline closes a pending request aimed at the caller account without paying it.
+// The shared requests can't be declined, as any account can pay them.
+func (prUC paymentRequestUseCase) Decline(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*PaymentRequestOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	request, err := prUC.close(ctx, caller, id, func(_ context.Context, request *model.PaymentRequest) error {
+		if request.IsShared() {
+			return ErrPaymentRequestSharedDecline
+		}
+		if request.PayerID != caller.AccountID {
+			return ErrPaymentRequestNotPayer
+		}
+
+		request.Declined()
+		return nil
+	})
+	if err != nil {
+		if isPaymentRequestRejection(err) {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("id", string(id)).Msg("error declining payment request")
+		return nil, ErrPaymentRequestDecline
+	}
+
+	log.Ctx(ctx).Info().Str("id", string(request.ID)).Str("by", string(caller.AccountID)).Msg("payment request declined")
+
+	output := newPaymentRequestOutput(request)
+	return &output, nil
+}
+
+// close runs closeFunc on a pending request the caller can see, holding its row lock, and saves its new status.
+// The lock makes concurrent approvals, declines and the expiration sweep wait for each other.
+func (prUC paymentRequestUseCase) close(
+	ctx context.Context,
+	caller model.Principal,
+	id model.PaymentRequestID,
+	closeFunc func(txCtx context.Context, request *model.PaymentRequest) error,
+) (*model.PaymentRequest, error) {
+	if _, err := uuid.Parse(string(id)); err != nil {
+		return nil, repository.ErrPaymentRequestNotFound
+	}
+
+	data, err := prUC.prRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		request, err := prUC.prRepo.GetByIDForUpdate(txCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !request.CanBeSeenBy(caller.AccountID) {
+			return nil, repository.ErrPaymentRequestNotFound
+		}
+		if !request.IsPending() {
+			return nil, ErrPaymentRequestNotPending
+		}
+		// the sweep may not have closed it yet
+		if request.IsExpired(time.Now()) {
+			return nil, ErrPaymentRequestExpired
+		}
+
+		err = closeFunc(txCtx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		return request, prUC.prRepo.UpdateStatus(txCtx, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	request, _ := data.(*model.PaymentRequest)
+	return request, nil
+}
+
+// isPaymentRequestRejection checks whether the error is a business rule rejecting the approval or decline, not a failure.
+func isPaymentRequestRejection(err error) bool {
+	switch err {
+	case repository.ErrPaymentRequestNotFound, ErrPaymentRequestNotPayer, ErrPaymentRequestSharedDecline,
+		ErrPaymentRequestNotPending, ErrPaymentRequestExpired:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_paymentRequestUseCase_Approve(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	requestID := model.PaymentRequestID("5b2e7c1a-3d4f-4a6b-8c9d-0e1f2a3b4c5d")
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	requestWith := func(payerID model.AccountID, status model.PaymentRequestStatus, expiresAt time.Time) func(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error) {
+		return func(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error) {
+			return &model.PaymentRequest{ID: id, RequesterID: "uuid-1", PayerID: payerID, Amount: 100, ExpiresAt: expiresAt, Status: status}, nil
+		}
+	}
+	accountsWithBalance := func(balance model.Money) mock.AccountRepository {
+		return mock.AccountRepository{
+			OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+				return &model.Account{ID: id, Balance: balance, Status: model.AccountStatusActive}, nil
+			},
+		}
+	}
+	ledgerRepoOK := mock.LedgerRepository{
+		OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+			return nil
+		},
+	}
+	noAccountLimits := mock.TransferLimitRepository{
+		OnGet: func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+			return &model.AccountTransferLimits{AccountID: accountID}, nil
+		},
+	}
+	trfRepoOK := mock.TransferRepository{
+		OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+			return nil
+		},
+	}
+
+	type fields struct {
+		getForUpdate func(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error)
+		accRepo      repository.AccountRepository
+	}
+	type args struct {
+		caller model.Principal
+		id     model.PaymentRequestID
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "id not uuid should return not found error",
+			fields:  fields{},
+			args:    args{caller: model.Principal{AccountID: "uuid-2"}, id: "any-id"},
+			wantErr: repository.ErrPaymentRequestNotFound,
+		},
+		{
+			name: "request aimed at another account should return not found error",
+			fields: fields{
+				getForUpdate: requestWith("uuid-3", model.PaymentRequestStatusPending, tomorrow),
+			},
+			args:    args{caller: model.Principal{AccountID: "uuid-2"}, id: requestID},
+			wantErr: repository.ErrPaymentRequestNotFound,
+		},
+		{
+			name: "requester approving its own request should return not payer error",
+			fields: fields{
+				getForUpdate: requestWith("", model.PaymentRequestStatusPending, tomorrow),
+			},
+			args:    args{caller: model.Principal{AccountID: "uuid-1"}, id: requestID},
+			wantErr: ErrPaymentRequestNotPayer,
+		},
+		{
+			name: "declined request should return not pending error",
+			fields: fields{
+				getForUpdate: requestWith("uuid-2", model.PaymentRequestStatusDeclined, tomorrow),
+			},
+			args:    args{caller: model.Principal{AccountID: "uuid-2"}, id: requestID},
+			wantErr: ErrPaymentRequestNotPending,
+		},
+		{
+			name: "request past its expiration should return expired error",
+			fields: fields{
+				getForUpdate: requestWith("uuid-2", model.PaymentRequestStatusPending, time.Now().Add(-time.Minute)),
+			},
+			args:    args{caller: model.Principal{AccountID: "uuid-2"}, id: requestID},
+			wantErr: ErrPaymentRequestExpired,
+		},
+		{
+			name: "insufficient balance should return the transfer rejection",
+			fields: fields{
+				getForUpdate: requestWith("uuid-2", model.PaymentRequestStatusPending, tomorrow),
+				accRepo:      accountsWithBalance(99),
+			},
+			args:    args{caller: model.Principal{AccountID: "uuid-2"}, id: requestID},
+			wantErr: ErrAccountCurrentBalanceInsufficient,
+		},
+		{
+			name: "lock error should return approve error",
+			fields: fields{
+				getForUpdate: func(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error) {
+					return nil, errors.New("any database error")
+				},
+			},
+			args:    args{caller: model.Principal{AccountID: "uuid-2"}, id: requestID},
+			wantErr: ErrPaymentRequestApprove,
+		},
+		{
+			name: "payer should pay the request",
+			fields: fields{
+				getForUpdate: requestWith("uuid-2", model.PaymentRequestStatusPending, tomorrow),
+				accRepo:      accountsWithBalance(100),
+			},
+			args: args{caller: model.Principal{AccountID: "uuid-2"}, id: requestID},
+		},
+		{
+			name: "any other account should pay a shared request",
+			fields: fields{
+				getForUpdate: requestWith("", model.PaymentRequestStatusPending, tomorrow),
+				accRepo:      accountsWithBalance(100),
+			},
+			args: args{caller: model.Principal{AccountID: "uuid-3"}, id: requestID},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var updated *model.PaymentRequest
+			prRepo := mock.PaymentRequestRepository{
+				OnWithinTransaction: withinTransaction,
+				OnGetByIDForUpdate:  tt.fields.getForUpdate,
+				OnUpdateStatus: func(ctx context.Context, request *model.PaymentRequest) error {
+					updated = request
+					return nil
+				},
+			}
+			prUC := NewPaymentRequestUseCase(prRepo, trfRepoOK, tt.fields.accRepo, ledgerRepoOK, noAccountLimits, TransferLimitPolicy{}, FeePolicy{})
+
+			got, err := prUC.Approve(backgroundCtx, tt.args.caller, tt.args.id)
+			if err != tt.wantErr {
+				t.Errorf("Approve() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if updated != nil {
+					t.Errorf("Approve() updated = %v, want the request kept pending", updated)
+				}
+				return
+			}
+
+			if got.Status != string(model.PaymentRequestStatusPaid) || got.PayerAccountID != string(tt.args.caller.AccountID) ||
+				got.TransferID == "" || got.ClosedAt == nil {
+				t.Errorf("Approve() got = %v, want paid by %v with the transfer", got, tt.args.caller.AccountID)
+			}
+			if updated == nil || updated.Status != model.PaymentRequestStatusPaid {
+				t.Errorf("Approve() updated = %v, want the paid status saved", updated)
+			}
+		})
+	}
+}
+
+func Test_paymentRequestUseCase_Decline(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	requestID := model.PaymentRequestID("5b2e7c1a-3d4f-4a6b-8c9d-0e1f2a3b4c5d")
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	requestWith := func(payerID model.AccountID, status model.PaymentRequestStatus) func(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error) {
+		return func(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error) {
+			return &model.PaymentRequest{ID: id, RequesterID: "uuid-1", PayerID: payerID, Amount: 100, ExpiresAt: time.Now().Add(time.Hour), Status: status}, nil
+		}
+	}
+
+	type fields struct {
+		getForUpdate func(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error)
+		updateErr    error
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		caller  model.Principal
+		wantErr error
+	}{
+		{
+			name: "shared request should return shared decline error",
+			fields: fields{
+				getForUpdate: requestWith("", model.PaymentRequestStatusPending),
+			},
+			caller:  model.Principal{AccountID: "uuid-2"},
+			wantErr: ErrPaymentRequestSharedDecline,
+		},
+		{
+			name: "requester should not decline its own request",
+			fields: fields{
+				getForUpdate: requestWith("uuid-2", model.PaymentRequestStatusPending),
+			},
+			caller:  model.Principal{AccountID: "uuid-1"},
+			wantErr: ErrPaymentRequestNotPayer,
+		},
+		{
+			name: "paid request should return not pending error",
+			fields: fields{
+				getForUpdate: requestWith("uuid-2", model.PaymentRequestStatusPaid),
+			},
+			caller:  model.Principal{AccountID: "uuid-2"},
+			wantErr: ErrPaymentRequestNotPending,
+		},
+		{
+			name: "repo update error should return decline error",
+			fields: fields{
+				getForUpdate: requestWith("uuid-2", model.PaymentRequestStatusPending),
+				updateErr:    errors.New("any database error"),
+			},
+			caller:  model.Principal{AccountID: "uuid-2"},
+			wantErr: ErrPaymentRequestDecline,
+		},
+		{
+			name: "payer should decline the request",
+			fields: fields{
+				getForUpdate: requestWith("uuid-2", model.PaymentRequestStatusPending),
+			},
+			caller: model.Principal{AccountID: "uuid-2"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			prRepo := mock.PaymentRequestRepository{
+				OnWithinTransaction: withinTransaction,
+				OnGetByIDForUpdate:  tt.fields.getForUpdate,
+				OnUpdateStatus: func(ctx context.Context, request *model.PaymentRequest) error {
+					return tt.fields.updateErr
+				},
+			}
+			prUC := NewPaymentRequestUseCase(prRepo, nil, nil, nil, nil, TransferLimitPolicy{}, FeePolicy{})
+
+			got, err := prUC.Decline(backgroundCtx, tt.caller, requestID)
+			if err != tt.wantErr {
+				t.Errorf("Decline() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Status != string(model.PaymentRequestStatusDeclined) || got.TransferID != "" || got.ClosedAt == nil {
+				t.Errorf("Decline() got = %v, want declined without transfer", got)
+			}
+		})
+	}
+}
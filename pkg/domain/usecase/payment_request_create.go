@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+const (
+	// PaymentRequestDefaultTTL is how long a payment request is open when its expiration is not informed.
+	PaymentRequestDefaultTTL = 7 * 24 * time.Hour
+	// PaymentRequestMaxTTL is how far in the future a payment request can expire.
+	PaymentRequestMaxTTL = 30 * 24 * time.Hour
+	// PaymentRequestDescriptionMaxLength is the maximum number of characters of the description.
+	PaymentRequestDescriptionMaxLength = 140
+)
+
+var (
+	// ErrPaymentRequestRequesterRequired happens when the requester account ID is empty.
+	ErrPaymentRequestRequesterRequired = errors.New("'requester_account_id' is required")
+	// ErrPaymentRequestPayerSelf happens when the payer is the requester account.
+	ErrPaymentRequestPayerSelf = errors.New("'payer_account_id' must be another account")
+	// ErrPaymentRequestDescriptionTooLong happens when the description is longer than PaymentRequestDescriptionMaxLength.
+	ErrPaymentRequestDescriptionTooLong = errors.New("'description' must be up to 140 characters")
+	// ErrPaymentRequestExpirationInvalid happens when the expiration is not in the future or is too far.
+	ErrPaymentRequestExpirationInvalid = errors.New("'expires_at' must be in the future, up to 30 days ahead")
+	// ErrPaymentRequestRequesterNotActive happens when the requester account is blocked or closed.
+	ErrPaymentRequestRequesterNotActive = errors.New("requester account is not active")
+	// ErrPaymentRequestPayerNotActive happens when the payer account is blocked or closed.
+	ErrPaymentRequestPayerNotActive = errors.New("payer account is not active")
+	// ErrPaymentRequestCreate happens when an error occurred and the payment request was not created.
+	ErrPaymentRequestCreate = errors.New("could not create payment request")
+)
+
+// PaymentRequestCreateInput represents the expected input data when requesting a payment.
+// Without PayerAccountID, the request is shared by link and any other account can pay it.
+// Without ExpiresAt, it expires after PaymentRequestDefaultTTL.
+type PaymentRequestCreateInput struct {
+	RequesterAccountID string     `json:"-"`
+	PayerAccountID     string     `json:"payer_account_id,omitempty" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	Amount             Amount     `json:"amount" swaggertype:"number" example:"42.5"`
+	Description        string     `json:"description,omitempty" example:"Donuts"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty" example:"2021-01-07T09:00:00-03:00"`
+}
+
+// Validate validates the PaymentRequestCreateInput fields.
+func (input *PaymentRequestCreateInput) Validate() error {
+	input.RequesterAccountID = strings.TrimSpace(input.RequesterAccountID)
+	if len(input.RequesterAccountID) < 1 {
+		return ErrPaymentRequestRequesterRequired
+	}
+
+	input.PayerAccountID = strings.TrimSpace(input.PayerAccountID)
+	if input.PayerAccountID == input.RequesterAccountID {
+		return ErrPaymentRequestPayerSelf
+	}
+
+	if input.Amount.Money <= 0 {
+		return ErrTransferAmountNotPositive
+	}
+
+	input.Description = strings.TrimSpace(input.Description)
+	if utf8.RuneCountInString(input.Description) > PaymentRequestDescriptionMaxLength {
+		return ErrPaymentRequestDescriptionTooLong
+	}
+
+	now := time.Now()
+	if input.ExpiresAt == nil {
+		expiresAt := now.Add(PaymentRequestDefaultTTL)
+		input.ExpiresAt = &expiresAt
+	}
+	if !input.ExpiresAt.After(now) || input.ExpiresAt.After(now.Add(PaymentRequestMaxTTL)) {
+		return ErrPaymentRequestExpirationInvalid
+	}
+
+	return nil
+}
+
+// Create validates the input and saves the payment request, pending until the payer approves or declines it.
+// Both accounts must be active when requesting, but the payer balance is only checked at the approval.
+func (prUC paymentRequestUseCase) Create(ctx context.Context, requestInput PaymentRequestCreateInput) (*PaymentRequestOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := requestInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", requestInput).Msg("payment request create input is not valid")
+		return nil, err
+	}
+
+	request := model.NewPaymentRequest(
+		model.AccountID(requestInput.RequesterAccountID),
+		model.AccountID(requestInput.PayerAccountID),
+		requestInput.Amount.Money,
+		requestInput.Description,
+		*requestInput.ExpiresAt)
+
+	err = prUC.ensureActiveAccounts(ctx, request)
+	if err != nil {
+		switch err {
+		case repository.ErrAccountNotFound, ErrPaymentRequestRequesterNotActive, ErrPaymentRequestPayerNotActive,
+			ErrTransferCurrencyMismatch:
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("request", request).Msg("error getting payment request accounts")
+		return nil, ErrPaymentRequestCreate
+	}
+
+	request.Amount, err = requestInput.Amount.In(request.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	err = prUC.prRepo.Create(ctx, request)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("request", request).Msg("error persisting new payment request")
+		return nil, ErrPaymentRequestCreate
+	}
+
+	output := newPaymentRequestOutput(request)
+	return &output, nil
+}
+
+// ensureActiveAccounts checks the requester and the payer, if any, exist and are active, without locking them.
+// The request is in the currency of the requester, which the payer must have, the requests are paid without an FX quote.
+func (prUC paymentRequestUseCase) ensureActiveAccounts(ctx context.Context, request *model.PaymentRequest) error {
+	requester, err := prUC.accRepo.GetBalance(ctx, request.RequesterID)
+	if err != nil {
+		return err
+	}
+	if !requester.IsActive() {
+		return ErrPaymentRequestRequesterNotActive
+	}
+	request.Currency = requester.Currency
+
+	if request.IsShared() {
+		return nil
+	}
+
+	payer, err := prUC.accRepo.GetBalance(ctx, request.PayerID)
+	if err != nil {
+		return err
+	}
+	if !payer.IsActive() {
+		return ErrPaymentRequestPayerNotActive
+	}
+	if payer.Currency != requester.Currency {
+		return ErrTransferCurrencyMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_paymentRequestUseCase_Create(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	tomorrow := time.Now().Add(24 * time.Hour)
+	tooFar := time.Now().Add(PaymentRequestMaxTTL + time.Hour)
+	yesterday := time.Now().Add(-24 * time.Hour)
+
+	accountsWithStatus := func(statuses map[model.AccountID]model.AccountStatus) mock.AccountRepository {
+		return mock.AccountRepository{
+			OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+				status, ok := statuses[id]
+				if !ok {
+					return nil, repository.ErrAccountNotFound
+				}
+				return &model.Account{ID: id, Status: status}, nil
+			},
+		}
+	}
+	bothActive := accountsWithStatus(map[model.AccountID]model.AccountStatus{"uuid-1": model.AccountStatusActive, "uuid-2": model.AccountStatusActive})
+	createOK := mock.PaymentRequestRepository{
+		OnCreate: func(ctx context.Context, request *model.PaymentRequest) error {
+			return nil
+		},
+	}
+
+	type fields struct {
+		prRepo  repository.PaymentRequestRepository
+		accRepo repository.AccountRepository
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		input    PaymentRequestCreateInput
+		wantLink bool
+		wantErr  error
+	}{
+		{
+			name:    "payer same as requester should return error",
+			fields:  fields{},
+			input:   PaymentRequestCreateInput{RequesterAccountID: "uuid-1", PayerAccountID: "uuid-1", Amount: NewAmount(100)},
+			wantErr: ErrPaymentRequestPayerSelf,
+		},
+		{
+			name:    "not positive amount should return error",
+			fields:  fields{},
+			input:   PaymentRequestCreateInput{RequesterAccountID: "uuid-1", PayerAccountID: "uuid-2", Amount: NewAmount(0)},
+			wantErr: ErrTransferAmountNotPositive,
+		},
+		{
+			name:   "long description should return error",
+			fields: fields{},
+			input: PaymentRequestCreateInput{RequesterAccountID: "uuid-1", PayerAccountID: "uuid-2", Amount: NewAmount(100),
+				Description: string(make([]rune, PaymentRequestDescriptionMaxLength+1))},
+			wantErr: ErrPaymentRequestDescriptionTooLong,
+		},
+		{
+			name:    "past expiration should return error",
+			fields:  fields{},
+			input:   PaymentRequestCreateInput{RequesterAccountID: "uuid-1", PayerAccountID: "uuid-2", Amount: NewAmount(100), ExpiresAt: &yesterday},
+			wantErr: ErrPaymentRequestExpirationInvalid,
+		},
+		{
+			name:    "expiration too far should return error",
+			fields:  fields{},
+			input:   PaymentRequestCreateInput{RequesterAccountID: "uuid-1", PayerAccountID: "uuid-2", Amount: NewAmount(100), ExpiresAt: &tooFar},
+			wantErr: ErrPaymentRequestExpirationInvalid,
+		},
+		{
+			name: "payer not found should return error",
+			fields: fields{
+				accRepo: accountsWithStatus(map[model.AccountID]model.AccountStatus{"uuid-1": model.AccountStatusActive}),
+			},
+			input:   PaymentRequestCreateInput{RequesterAccountID: "uuid-1", PayerAccountID: "uuid-2", Amount: NewAmount(100)},
+			wantErr: repository.ErrAccountNotFound,
+		},
+		{
+			name: "blocked payer should return error",
+			fields: fields{
+				accRepo: accountsWithStatus(map[model.AccountID]model.AccountStatus{"uuid-1": model.AccountStatusActive, "uuid-2": model.AccountStatusBlocked}),
+			},
+			input:   PaymentRequestCreateInput{RequesterAccountID: "uuid-1", PayerAccountID: "uuid-2", Amount: NewAmount(100)},
+			wantErr: ErrPaymentRequestPayerNotActive,
+		},
+		{
+			name: "repo error should return create error",
+			fields: fields{
+				prRepo: mock.PaymentRequestRepository{
+					OnCreate: func(ctx context.Context, request *model.PaymentRequest) error {
+						return errors.New("any database error")
+					},
+				},
+				accRepo: bothActive,
+			},
+			input:   PaymentRequestCreateInput{RequesterAccountID: "uuid-1", PayerAccountID: "uuid-2", Amount: NewAmount(100)},
+			wantErr: ErrPaymentRequestCreate,
+		},
+		{
+			name: "request aimed at the payer should be pending",
+			fields: fields{
+				prRepo:  createOK,
+				accRepo: bothActive,
+			},
+			input: PaymentRequestCreateInput{RequesterAccountID: "uuid-1", PayerAccountID: "uuid-2", Amount: NewAmount(100), ExpiresAt: &tomorrow},
+		},
+		{
+			name: "request without payer should be shared by link",
+			fields: fields{
+				prRepo:  createOK,
+				accRepo: bothActive,
+			},
+			input:    PaymentRequestCreateInput{RequesterAccountID: "uuid-1", Amount: NewAmount(100), Description: " Donuts "},
+			wantLink: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			prUC := NewPaymentRequestUseCase(tt.fields.prRepo, nil, tt.fields.accRepo, nil, nil, TransferLimitPolicy{}, FeePolicy{})
+
+			got, err := prUC.Create(backgroundCtx, tt.input)
+			if err != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if got.ID == "" || got.Status != string(model.PaymentRequestStatusPending) || got.Amount != tt.input.Amount ||
+				got.PayerAccountID != tt.input.PayerAccountID || (got.Link != "") != tt.wantLink {
+				t.Errorf("Create() got = %v, want pending request of %v", got, tt.input)
+			}
+			if tt.input.ExpiresAt == nil && !got.ExpiresAt.After(time.Now().Add(PaymentRequestDefaultTTL-time.Minute)) {
+				t.Errorf("Create() ExpiresAt = %v, want default expiration", got.ExpiresAt)
+			}
+		})
+	}
+}
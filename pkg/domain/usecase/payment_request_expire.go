@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/infraestructure/monitoring"
+)
+
+// ExecuteDue closes as expired up to limit pending requests past their expiration, returning how many were closed.
+//
+// The requests being approved or declined are skipped, so they're only closed if still pending on the next call,
+// and it's safe to run on multiple replicas.
+func (prUC paymentRequestUseCase) ExecuteDue(ctx context.Context, limit int) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	expired, err := prUC.prRepo.ExpireDue(ctx, time.Now(), limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error expiring payment requests")
+		return 0, err
+	}
+
+	if expired > 0 {
+		monitoring.PaymentRequestsExpired.Add(float64(expired))
+		log.Ctx(ctx).Info().Int("count", expired).Msg("payment requests expired")
+	}
+
+	return expired, nil
+}
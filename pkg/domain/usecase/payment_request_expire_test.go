@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_paymentRequestUseCase_ExecuteDue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		expireDue func(ctx context.Context, now time.Time, limit int) (int, error)
+		want      int
+		wantErr   bool
+	}{
+		{
+			name: "should return how many requests expired",
+			expireDue: func(ctx context.Context, now time.Time, limit int) (int, error) {
+				if limit != 10 {
+					return 0, errors.New("should pass the limit")
+				}
+				return 3, nil
+			},
+			want: 3,
+		},
+		{
+			name: "repo error should return error",
+			expireDue: func(ctx context.Context, now time.Time, limit int) (int, error) {
+				return 0, errors.New("any database error")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			prUC := NewPaymentRequestUseCase(mock.PaymentRequestRepository{OnExpireDue: tt.expireDue}, nil, nil, nil, nil, TransferLimitPolicy{}, FeePolicy{})
+
+			got, err := prUC.ExecuteDue(context.Background(), 10)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecuteDue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ExecuteDue() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
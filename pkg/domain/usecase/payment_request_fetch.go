@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+const (
+	// PaymentRequestRolePayer fetches the requests aimed at the caller account.
+	PaymentRequestRolePayer = "payer"
+	// PaymentRequestRoleRequester fetches the requests of the caller account.
+	PaymentRequestRoleRequester = "requester"
+)
+
+var (
+	// ErrPaymentRequestRoleInvalid happens when the role filter is not known.
+	ErrPaymentRequestRoleInvalid = errors.New("'role' must be 'payer' or 'requester'")
+	// ErrPaymentRequestStatusInvalid happens when the status filter is not known.
+	ErrPaymentRequestStatusInvalid = errors.New("'status' must be 'pending', 'paid', 'declined' or 'expired'")
+	// ErrPaymentRequestFetch happens when an error occurred while fetching the payment requests.
+	ErrPaymentRequestFetch = errors.New("could not fetch payment requests")
+)
+
+// Fetch returns the requests aimed at the caller account, or the ones it requested with the requester role,
+// the newest first, optionally filtered by status. The shared requests are only fetched by their requester.
+func (prUC paymentRequestUseCase) Fetch(ctx context.Context, caller model.Principal, role string, status string) ([]PaymentRequestOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	statusFilter := model.PaymentRequestStatus(status)
+	if statusFilter != "" && !statusFilter.IsValid() {
+		return nil, ErrPaymentRequestStatusInvalid
+	}
+
+	var requests []model.PaymentRequest
+	var err error
+	switch role {
+	case "", PaymentRequestRolePayer:
+		requests, err = prUC.prRepo.FetchByPayer(ctx, caller.AccountID, statusFilter)
+	case PaymentRequestRoleRequester:
+		requests, err = prUC.prRepo.FetchByRequester(ctx, caller.AccountID, statusFilter)
+	default:
+		return nil, ErrPaymentRequestRoleInvalid
+	}
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error fetching payment requests")
+		return nil, ErrPaymentRequestFetch
+	}
+
+	outputs := make([]PaymentRequestOutput, 0, len(requests))
+	for _, request := range requests {
+		outputs = append(outputs, newPaymentRequestOutput(&request))
+	}
+
+	return outputs, nil
+}
+
+// Get returns a request the caller account can see: its own, the ones aimed at it and the shared ones.
+// The others are reported as repository.ErrPaymentRequestNotFound, so their IDs are not disclosed.
+func (prUC paymentRequestUseCase) Get(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*PaymentRequestOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if _, err := uuid.Parse(string(id)); err != nil {
+		return nil, repository.ErrPaymentRequestNotFound
+	}
+
+	request, err := prUC.prRepo.GetByID(ctx, id)
+	if err == nil && !request.CanBeSeenBy(caller.AccountID) {
+		err = repository.ErrPaymentRequestNotFound
+	}
+	if err != nil {
+		if err == repository.ErrPaymentRequestNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("id", string(id)).Msg("error getting payment request")
+		return nil, ErrPaymentRequestFetch
+	}
+
+	output := newPaymentRequestOutput(request)
+	return &output, nil
+}
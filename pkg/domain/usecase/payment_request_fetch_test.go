@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_paymentRequestUseCase_Fetch(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	caller := model.Principal{AccountID: "uuid-1"}
+
+	fetchOf := func(role string) func(ctx context.Context, accountID model.AccountID, status model.PaymentRequestStatus) ([]model.PaymentRequest, error) {
+		return func(ctx context.Context, accountID model.AccountID, status model.PaymentRequestStatus) ([]model.PaymentRequest, error) {
+			if accountID != caller.AccountID {
+				return nil, errors.New("should fetch by the caller account")
+			}
+			return []model.PaymentRequest{{ID: model.PaymentRequestID(role), Status: status}}, nil
+		}
+	}
+	prRepo := mock.PaymentRequestRepository{
+		OnFetchByPayer:     fetchOf(PaymentRequestRolePayer),
+		OnFetchByRequester: fetchOf(PaymentRequestRoleRequester),
+	}
+
+	tests := []struct {
+		name    string
+		prRepo  repository.PaymentRequestRepository
+		role    string
+		status  string
+		wantID  string
+		wantErr error
+	}{
+		{
+			name:   "empty role should fetch as payer",
+			prRepo: prRepo,
+			wantID: PaymentRequestRolePayer,
+		},
+		{
+			name:   "requester role should fetch as requester",
+			prRepo: prRepo,
+			role:   PaymentRequestRoleRequester,
+			status: "paid",
+			wantID: PaymentRequestRoleRequester,
+		},
+		{
+			name:    "unknown role should return error",
+			role:    "owner",
+			wantErr: ErrPaymentRequestRoleInvalid,
+		},
+		{
+			name:    "unknown status should return error",
+			status:  "scheduled",
+			wantErr: ErrPaymentRequestStatusInvalid,
+		},
+		{
+			name: "repo error should return fetch error",
+			prRepo: mock.PaymentRequestRepository{
+				OnFetchByPayer: func(ctx context.Context, payerID model.AccountID, status model.PaymentRequestStatus) ([]model.PaymentRequest, error) {
+					return nil, errors.New("any database error")
+				},
+			},
+			wantErr: ErrPaymentRequestFetch,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			prUC := NewPaymentRequestUseCase(tt.prRepo, nil, nil, nil, nil, TransferLimitPolicy{}, FeePolicy{})
+
+			got, err := prUC.Fetch(backgroundCtx, caller, tt.role, tt.status)
+			if err != tt.wantErr {
+				t.Errorf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if len(got) != 1 || got[0].ID != tt.wantID || got[0].Status != tt.status {
+				t.Errorf("Fetch() got = %v, want the %v requests with status %q", got, tt.wantID, tt.status)
+			}
+		})
+	}
+}
+
+func Test_paymentRequestUseCase_Get(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	requestID := model.PaymentRequestID("5b2e7c1a-3d4f-4a6b-8c9d-0e1f2a3b4c5d")
+
+	requestOf := func(payerID model.AccountID) repository.PaymentRequestRepository {
+		return mock.PaymentRequestRepository{
+			OnGetByID: func(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error) {
+				return &model.PaymentRequest{ID: id, RequesterID: "uuid-1", PayerID: payerID, Status: model.PaymentRequestStatusPending}, nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		prRepo  repository.PaymentRequestRepository
+		caller  model.AccountID
+		id      model.PaymentRequestID
+		wantErr error
+	}{
+		{
+			name:    "id not uuid should return not found error",
+			caller:  "uuid-2",
+			id:      "any-id",
+			wantErr: repository.ErrPaymentRequestNotFound,
+		},
+		{
+			name:   "requester should get its request",
+			prRepo: requestOf("uuid-2"),
+			caller: "uuid-1",
+			id:     requestID,
+		},
+		{
+			name:   "payer should get the request aimed at it",
+			prRepo: requestOf("uuid-2"),
+			caller: "uuid-2",
+			id:     requestID,
+		},
+		{
+			name:   "any account should get a shared request",
+			prRepo: requestOf(""),
+			caller: "uuid-3",
+			id:     requestID,
+		},
+		{
+			name:    "request aimed at another account should return not found error",
+			prRepo:  requestOf("uuid-2"),
+			caller:  "uuid-3",
+			id:      requestID,
+			wantErr: repository.ErrPaymentRequestNotFound,
+		},
+		{
+			name: "repo error should return fetch error",
+			prRepo: mock.PaymentRequestRepository{
+				OnGetByID: func(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error) {
+					return nil, errors.New("any database error")
+				},
+			},
+			caller:  "uuid-1",
+			id:      requestID,
+			wantErr: ErrPaymentRequestFetch,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			prUC := NewPaymentRequestUseCase(tt.prRepo, nil, nil, nil, nil, TransferLimitPolicy{}, FeePolicy{})
+
+			got, err := prUC.Get(backgroundCtx, model.Principal{AccountID: tt.caller}, tt.id)
+			if err != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.ID != string(tt.id) {
+				t.Errorf("Get() got = %v, want %v", got, tt.id)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrPixKeyFetch happens when an error occurred while fetching the pix keys.
+	ErrPixKeyFetch = errors.New("could not fetch pix keys")
+	// ErrPixKeyDelete happens when an error occurred and the pix key was not deleted.
+	ErrPixKeyDelete = errors.New("could not delete pix key")
+)
+
+// PixKeyUseCase is the interface that wraps all business logic methods related to the pix keys.
+type PixKeyUseCase interface {
+	Create(ctx context.Context, caller model.Principal, createInput PixKeyCreateInput) (*PixKeyOutput, error)
+	Verify(ctx context.Context, caller model.Principal, verifyInput PixKeyVerifyInput) (*PixKeyOutput, error)
+	Fetch(ctx context.Context, caller model.Principal) ([]PixKeyOutput, error)
+	Delete(ctx context.Context, caller model.Principal, key string) error
+	Lookup(ctx context.Context, caller model.Principal, key string) (*PixKeyLookupOutput, error)
+	CreateBRCode(ctx context.Context, caller model.Principal, brCodeInput BRCodeCreateInput) (*BRCodeOutput, error)
+}
+
+type pixKeyUseCase struct {
+	keyRepo   repository.PixKeyRepository
+	accRepo   repository.AccountRepository
+	keySender repository.PixKeyVerificationSender
+}
+
+// NewPixKeyUseCase instantiates a new PixKeyUseCase.
+func NewPixKeyUseCase(keyRepo repository.PixKeyRepository, accRepo repository.AccountRepository, keySender repository.PixKeyVerificationSender) PixKeyUseCase {
+	return &pixKeyUseCase{
+		keyRepo:   keyRepo,
+		accRepo:   accRepo,
+		keySender: keySender,
+	}
+}
+
+// PixKeyOutput represents a pix key of the account.
+// VerificationExpiresAt is only informed for the keys pending verification.
+type PixKeyOutput struct {
+	Type                  string     `json:"type" example:"email" enums:"cpf,email,phone,random"`
+	Key                   string     `json:"key" example:"homer@springfield.com"`
+	Status                string     `json:"status" example:"pending" enums:"pending,active"`
+	VerificationExpiresAt *time.Time `json:"verification_expires_at,omitempty" example:"2021-01-05T09:15:00.999999-03:00"`
+	CreatedAt             time.Time  `json:"created_at" example:"2021-01-05T09:00:00.999999-03:00"`
+}
+
+func newPixKeyOutput(key *model.PixKey) *PixKeyOutput {
+	output := &PixKeyOutput{
+		Type:      string(key.Type),
+		Key:       key.Value,
+		Status:    string(key.Status),
+		CreatedAt: key.CreatedAt,
+	}
+	if !key.IsActive() {
+		expiresAt := key.VerificationExpiresAt
+		output.VerificationExpiresAt = &expiresAt
+	}
+
+	return output
+}
+
+// Fetch returns the keys of the caller account, the oldest first.
+func (keyUC pixKeyUseCase) Fetch(ctx context.Context, caller model.Principal) ([]PixKeyOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	keys, err := keyUC.keyRepo.Fetch(ctx, caller.AccountID)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error fetching pix keys")
+		return nil, ErrPixKeyFetch
+	}
+
+	outputs := make([]PixKeyOutput, 0, len(keys))
+	for i := range keys {
+		outputs = append(outputs, *newPixKeyOutput(&keys[i]))
+	}
+
+	return outputs, nil
+}
+
+// Delete removes a key of the caller account, so the value can be registered again, by any account.
+// The keys of other accounts are not found.
+func (keyUC pixKeyUseCase) Delete(ctx context.Context, caller model.Principal, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, value, err := model.ParsePixKey(key)
+	if err != nil {
+		return repository.ErrPixKeyNotFound
+	}
+
+	pixKey, err := keyUC.keyRepo.GetByAccountValue(ctx, caller.AccountID, value)
+	if err != nil {
+		if err == repository.ErrPixKeyNotFound {
+			return err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error getting pix key")
+		return ErrPixKeyDelete
+	}
+
+	err = keyUC.keyRepo.Delete(ctx, pixKey.ID)
+	if err != nil {
+		if err == repository.ErrPixKeyNotFound {
+			return err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("keyID", string(pixKey.ID)).Msg("error deleting pix key")
+		return ErrPixKeyDelete
+	}
+
+	log.Ctx(ctx).Info().Str("keyID", string(pixKey.ID)).Str("accountID", string(caller.AccountID)).Msg("pix key deleted")
+
+	return nil
+}
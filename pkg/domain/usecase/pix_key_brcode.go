@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/brcode"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrBRCodeAmountNegative happens when the BR Code amount is less than zero.
+	ErrBRCodeAmountNegative = errors.New("'amount' must not be negative")
+	// ErrBRCodeTxIDInvalid happens when the BR Code txid is not up to 25 letters and digits.
+	ErrBRCodeTxIDInvalid = errors.New("'txid' must be up to 25 letters and digits")
+	// ErrBRCodeDescriptionTooLong happens when the description doesn't fit the BR Code along with the key.
+	ErrBRCodeDescriptionTooLong = errors.New("'description' is too long for the key")
+	// ErrBRCodeCreate happens when an error occurred and the BR Code was not created.
+	ErrBRCodeCreate = errors.New("could not create BR Code")
+)
+
+// DefaultBRCodeCity is the merchant city of the BR Codes when the city is not informed.
+const DefaultBRCodeCity = "SPRINGFIELD"
+
+// BRCodeCreateInput represents the expected input data when creating the BR Code of a pix key.
+// A zero Amount lets the payer choose it.
+type BRCodeCreateInput struct {
+	Key         string
+	Amount      Amount
+	TxID        string
+	Description string
+	City        string
+}
+
+// Validate validates the BRCodeCreateInput fields.
+func (input *BRCodeCreateInput) Validate() error {
+	if input.Amount.Money < 0 {
+		return ErrBRCodeAmountNegative
+	}
+
+	// the amounts of the BR Codes are in BRL
+	amount, err := input.Amount.In(model.CurrencyBRL)
+	if err != nil {
+		return err
+	}
+	input.Amount = NewAmountIn(amount, model.CurrencyBRL)
+
+	input.TxID = strings.TrimSpace(input.TxID)
+	if len(input.TxID) > 25 || strings.IndexFunc(input.TxID, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) >= 0 {
+		return ErrBRCodeTxIDInvalid
+	}
+
+	input.Description = strings.TrimSpace(input.Description)
+	input.City = strings.TrimSpace(input.City)
+	if input.City == "" {
+		input.City = DefaultBRCodeCity
+	}
+
+	return nil
+}
+
+// BRCodeOutput represents a BR Code, the payload of the Pix QR Codes.
+type BRCodeOutput struct {
+	Payload string  `json:"payload" example:"00020126430014br.gov.bcb.pix0121homer@springfield.com520400005303986540510.505802BR5917Homer Jay Simpson6011SPRINGFIELD62120508DONUTS4263048175"`
+	Key     string  `json:"key" example:"homer@springfield.com"`
+	Amount  *Amount `json:"amount,omitempty" swaggertype:"number" example:"10.5"`
+	TxID    string  `json:"txid,omitempty" example:"DONUTS42"`
+}
+
+// CreateBRCode returns the static BR Code of an active key of the caller account, to receive transfers by QR Code.
+// The keys of other accounts are not found.
+func (keyUC pixKeyUseCase) CreateBRCode(ctx context.Context, caller model.Principal, brCodeInput BRCodeCreateInput) (*BRCodeOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := brCodeInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", brCodeInput).Msg("BR Code create input is not valid")
+		return nil, err
+	}
+
+	pixKey, err := getActivePixKey(ctx, keyUC.keyRepo, brCodeInput.Key)
+	if err == nil && pixKey.AccountID != caller.AccountID {
+		err = repository.ErrPixKeyNotFound
+	}
+	if err != nil {
+		if err == repository.ErrPixKeyNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error getting pix key")
+		return nil, ErrBRCodeCreate
+	}
+
+	account, err := keyUC.accRepo.GetByID(ctx, caller.AccountID)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error getting BR Code account")
+		return nil, ErrBRCodeCreate
+	}
+	if !account.IsActive() {
+		return nil, ErrPixKeyAccountNotActive
+	}
+
+	payload, err := brcode.Encode(brcode.Payload{
+		Key:          pixKey.Value,
+		Description:  brCodeInput.Description,
+		MerchantName: account.Name,
+		MerchantCity: brCodeInput.City,
+		Amount:       int64(brCodeInput.Amount.Money),
+		TxID:         brCodeInput.TxID,
+	})
+	if err != nil {
+		if err == brcode.ErrTooLong {
+			return nil, ErrBRCodeDescriptionTooLong
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("input", brCodeInput).Msg("error encoding BR Code")
+		return nil, ErrBRCodeCreate
+	}
+
+	output := &BRCodeOutput{
+		Payload: payload,
+		Key:     pixKey.Value,
+		TxID:    brCodeInput.TxID,
+	}
+	if brCodeInput.Amount.Money > 0 {
+		output.Amount = &brCodeInput.Amount
+	}
+
+	return output, nil
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/brcode"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_pixKeyUseCase_CreateBRCode(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	caller := model.Principal{AccountID: "uuid-1"}
+
+	keyOf := func(accountID model.AccountID, status model.PixKeyStatus) mock.PixKeyRepository {
+		return mock.PixKeyRepository{
+			OnGetByValue: func(ctx context.Context, value string) (*model.PixKey, error) {
+				return &model.PixKey{ID: "key-1", AccountID: accountID, Type: model.PixKeyTypeEmail, Value: value, Status: status}, nil
+			},
+		}
+	}
+	accountWith := func(status model.AccountStatus) mock.AccountRepository {
+		return mock.AccountRepository{
+			OnGetByID: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+				return &model.Account{ID: id, Name: "Homer Jay Simpson", CPF: "59951332099", Status: status}, nil
+			},
+		}
+	}
+
+	type fields struct {
+		keyRepo repository.PixKeyRepository
+		accRepo repository.AccountRepository
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		input       BRCodeCreateInput
+		wantPayload brcode.Payload
+		wantErr     error
+	}{
+		{
+			name:    "negative amount should return error",
+			fields:  fields{keyRepo: mock.PixKeyRepository{}, accRepo: mock.AccountRepository{}},
+			input:   BRCodeCreateInput{Key: "homer@springfield.com", Amount: Amount{Money: -1}},
+			wantErr: ErrBRCodeAmountNegative,
+		},
+		{
+			name:    "txid with symbols should return error",
+			fields:  fields{keyRepo: mock.PixKeyRepository{}, accRepo: mock.AccountRepository{}},
+			input:   BRCodeCreateInput{Key: "homer@springfield.com", TxID: "donuts-42"},
+			wantErr: ErrBRCodeTxIDInvalid,
+		},
+		{
+			name:    "txid longer than 25 should return error",
+			fields:  fields{keyRepo: mock.PixKeyRepository{}, accRepo: mock.AccountRepository{}},
+			input:   BRCodeCreateInput{Key: "homer@springfield.com", TxID: strings.Repeat("A", 26)},
+			wantErr: ErrBRCodeTxIDInvalid,
+		},
+		{
+			name:    "pending key should return not found",
+			fields:  fields{keyRepo: keyOf("uuid-1", model.PixKeyStatusPending), accRepo: mock.AccountRepository{}},
+			input:   BRCodeCreateInput{Key: "homer@springfield.com"},
+			wantErr: repository.ErrPixKeyNotFound,
+		},
+		{
+			name:    "key of another account should return not found",
+			fields:  fields{keyRepo: keyOf("uuid-2", model.PixKeyStatusActive), accRepo: mock.AccountRepository{}},
+			input:   BRCodeCreateInput{Key: "homer@springfield.com"},
+			wantErr: repository.ErrPixKeyNotFound,
+		},
+		{
+			name: "repository error should return create error",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnGetByValue: func(ctx context.Context, value string) (*model.PixKey, error) {
+						return nil, errors.New("connection refused")
+					},
+				},
+				accRepo: mock.AccountRepository{},
+			},
+			input:   BRCodeCreateInput{Key: "homer@springfield.com"},
+			wantErr: ErrBRCodeCreate,
+		},
+		{
+			name:    "blocked account should return error",
+			fields:  fields{keyRepo: keyOf("uuid-1", model.PixKeyStatusActive), accRepo: accountWith(model.AccountStatusBlocked)},
+			input:   BRCodeCreateInput{Key: "homer@springfield.com"},
+			wantErr: ErrPixKeyAccountNotActive,
+		},
+		{
+			name:    "description that doesn't fit should return error",
+			fields:  fields{keyRepo: keyOf("uuid-1", model.PixKeyStatusActive), accRepo: accountWith(model.AccountStatusActive)},
+			input:   BRCodeCreateInput{Key: "homer@springfield.com", Description: strings.Repeat("donuts ", 10)},
+			wantErr: ErrBRCodeDescriptionTooLong,
+		},
+		{
+			name:   "success without amount in the default city",
+			fields: fields{keyRepo: keyOf("uuid-1", model.PixKeyStatusActive), accRepo: accountWith(model.AccountStatusActive)},
+			input:  BRCodeCreateInput{Key: " Homer@Springfield.com "},
+			wantPayload: brcode.Payload{
+				Key:          "homer@springfield.com",
+				MerchantName: "Homer Jay Simpson",
+				MerchantCity: DefaultBRCodeCity,
+			},
+		},
+		{
+			name:   "success with amount, txid and description",
+			fields: fields{keyRepo: keyOf("uuid-1", model.PixKeyStatusActive), accRepo: accountWith(model.AccountStatusActive)},
+			input:  BRCodeCreateInput{Key: "homer@springfield.com", Amount: Amount{Money: 1050}, TxID: "DONUTS42", Description: "Donuts", City: "Shelbyville"},
+			wantPayload: brcode.Payload{
+				Key:          "homer@springfield.com",
+				Description:  "Donuts",
+				MerchantName: "Homer Jay Simpson",
+				MerchantCity: "Shelbyville",
+				Amount:       1050,
+				TxID:         "DONUTS42",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			keyUC := NewPixKeyUseCase(tt.fields.keyRepo, tt.fields.accRepo, mock.PixKeyVerificationSender{})
+
+			got, err := keyUC.CreateBRCode(backgroundCtx, caller, tt.input)
+			if err != tt.wantErr {
+				t.Errorf("CreateBRCode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			payload, err := brcode.Parse(got.Payload)
+			if err != nil {
+				t.Fatalf("CreateBRCode() payload %q is not valid: %v", got.Payload, err)
+			}
+			if *payload != tt.wantPayload {
+				t.Errorf("CreateBRCode() payload = %+v, want %+v", *payload, tt.wantPayload)
+			}
+			if got.Key != tt.wantPayload.Key || got.TxID != tt.wantPayload.TxID {
+				t.Errorf("CreateBRCode() got = %+v, want key %v and txid %v", got, tt.wantPayload.Key, tt.wantPayload.TxID)
+			}
+			if (got.Amount == nil) != (tt.wantPayload.Amount == 0) {
+				t.Errorf("CreateBRCode() amount = %v, want %v", got.Amount, tt.wantPayload.Amount)
+			}
+		})
+	}
+}
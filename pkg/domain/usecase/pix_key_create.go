@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrPixKeyTypeInvalid happens when the key type is not one of model.PixKeyType.
+	ErrPixKeyTypeInvalid = errors.New("'type' must be cpf, email, phone or random")
+	// ErrPixKeyValueInvalid happens when the key is not valid for its type.
+	ErrPixKeyValueInvalid = errors.New("'key' is not valid for its type")
+	// ErrPixKeyRandomValue happens when the value of a random key is sent. It's generated.
+	ErrPixKeyRandomValue = errors.New("'key' must be left out of random keys, it's generated")
+	// ErrPixKeyCPFNotOwn happens when the CPF key is not the CPF of the account holder.
+	ErrPixKeyCPFNotOwn = errors.New("'key' must be the CPF of the account holder")
+	// ErrPixKeyAccountNotActive happens when registering a key to a blocked or closed account.
+	ErrPixKeyAccountNotActive = errors.New("account is not active")
+	// ErrPixKeyCreate happens when an error occurred and the pix key was not created.
+	ErrPixKeyCreate = errors.New("could not create pix key")
+)
+
+// PixKeyCreateInput represents the expected input data when registering a pix key.
+// The key of random keys is generated and the key of CPF keys defaults to the CPF of the account holder.
+type PixKeyCreateInput struct {
+	Type string `json:"type" example:"email" enums:"cpf,email,phone,random"`
+	Key  string `json:"key,omitempty" example:"homer@springfield.com"`
+}
+
+// Validate validates the PixKeyCreateInput fields, normalizing the key.
+func (input *PixKeyCreateInput) Validate() error {
+	keyType := model.PixKeyType(input.Type)
+	if !keyType.IsValid() {
+		return ErrPixKeyTypeInvalid
+	}
+
+	input.Key = strings.TrimSpace(input.Key)
+	if keyType == model.PixKeyTypeRandom {
+		if input.Key != "" {
+			return ErrPixKeyRandomValue
+		}
+		return nil
+	}
+	if keyType == model.PixKeyTypeCPF && input.Key == "" {
+		return nil
+	}
+
+	value, err := model.NormalizePixKey(keyType, input.Key)
+	if err != nil {
+		return ErrPixKeyValueInvalid
+	}
+	input.Key = value
+
+	return nil
+}
+
+// Create registers a key to the caller account.
+//
+// The email keys are pending until the holder verifies them with the code sent to the address. Registering a
+// pending key of the account again sends a new code. The others are active right away. The account must be active and
+// have room for the key, and the key must not be active in any account. A pending key is only a claim: other accounts
+// can claim the same value and the first one verified takes it, so a pending key can't hold an address its account
+// doesn't own.
+func (keyUC pixKeyUseCase) Create(ctx context.Context, caller model.Principal, createInput PixKeyCreateInput) (*PixKeyOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := createInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", createInput).Msg("pix key create input is not valid")
+		return nil, err
+	}
+
+	data, err := keyUC.keyRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		// locks the account, so its keys are counted one at a time
+		account, err := keyUC.accRepo.GetBalanceForUpdate(txCtx, caller.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		if !account.IsActive() {
+			return nil, ErrPixKeyAccountNotActive
+		}
+
+		keyType := model.PixKeyType(createInput.Type)
+		value, err := keyUC.keyValue(txCtx, account.ID, keyType, createInput.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		existing, err := keyUC.keyRepo.GetByAccountValueForUpdate(txCtx, account.ID, value)
+		if err != nil && err != repository.ErrPixKeyNotFound {
+			return nil, err
+		}
+		if existing != nil && !existing.IsActive() {
+			return existing, keyUC.sendVerification(txCtx, existing, keyUC.keyRepo.Update)
+		}
+
+		key := model.NewPixKey(account.ID, keyType, value)
+		if key.IsActive() {
+			return key, keyUC.keyRepo.Create(txCtx, key)
+		}
+
+		return key, keyUC.sendVerification(txCtx, key, keyUC.keyRepo.Create)
+	})
+	if err != nil {
+		switch err {
+		case repository.ErrAccountNotFound, repository.ErrPixKeyAlreadyExists, repository.ErrPixKeyLimitReached,
+			ErrPixKeyAccountNotActive, ErrPixKeyCPFNotOwn:
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("input", createInput).Msg("error creating pix key")
+		return nil, ErrPixKeyCreate
+	}
+
+	key, _ := data.(*model.PixKey)
+	log.Ctx(ctx).Info().Str("keyID", string(key.ID)).Str("accountID", string(key.AccountID)).Str("type", string(key.Type)).
+		Str("status", string(key.Status)).Msg("pix key registered")
+
+	return newPixKeyOutput(key), nil
+}
+
+// keyValue returns the value of the new key of the type, generating the random ones and checking the CPF.
+func (keyUC pixKeyUseCase) keyValue(ctx context.Context, accountID model.AccountID, keyType model.PixKeyType, value string) (string, error) {
+	switch keyType {
+	case model.PixKeyTypeRandom:
+		return model.NewRandomPixKeyValue(), nil
+	case model.PixKeyTypeCPF:
+		account, err := keyUC.accRepo.GetByID(ctx, accountID)
+		if err != nil {
+			return "", err
+		}
+		if value != "" && model.CPF(value) != account.CPF {
+			return "", ErrPixKeyCPFNotOwn
+		}
+		return string(account.CPF), nil
+	default:
+		return value, nil
+	}
+}
+
+// sendVerification starts the verification of the key, saves it and sends the code.
+// The code is sent last, so it's not sent when the key is not saved.
+func (keyUC pixKeyUseCase) sendVerification(ctx context.Context, key *model.PixKey, save func(context.Context, *model.PixKey) error) error {
+	code, err := key.StartVerification()
+	if err != nil {
+		return err
+	}
+
+	err = save(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return keyUC.keySender.SendVerificationCode(ctx, key, code)
+}
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func TestPixKeyCreateInput_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   PixKeyCreateInput
+		wantKey string
+		wantErr error
+	}{
+		{name: "unknown type should return error", input: PixKeyCreateInput{Type: "iban", Key: "123"}, wantErr: ErrPixKeyTypeInvalid},
+		{name: "random with key should return error", input: PixKeyCreateInput{Type: "random", Key: "1a2b3c4d-5e6f-4a8b-9c0d-1e2f3a4b5c6d"}, wantErr: ErrPixKeyRandomValue},
+		{name: "invalid email should return error", input: PixKeyCreateInput{Type: "email", Key: "homer"}, wantErr: ErrPixKeyValueInvalid},
+		{name: "blank phone should return error", input: PixKeyCreateInput{Type: "phone", Key: " "}, wantErr: ErrPixKeyValueInvalid},
+		{name: "random", input: PixKeyCreateInput{Type: "random"}, wantKey: ""},
+		{name: "cpf defaults to the holder's", input: PixKeyCreateInput{Type: "cpf"}, wantKey: ""},
+		{name: "cpf is cleaned", input: PixKeyCreateInput{Type: "cpf", Key: "599.513.320-99"}, wantKey: "59951332099"},
+		{name: "phone is normalized", input: PixKeyCreateInput{Type: "phone", Key: "+55 (11) 98765-4321"}, wantKey: "+5511987654321"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.input.Validate()
+			if err != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && tt.input.Key != tt.wantKey {
+				t.Errorf("Validate() key = %v, want %v", tt.input.Key, tt.wantKey)
+			}
+		})
+	}
+}
+
+func Test_pixKeyUseCase_Create(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	caller := model.Principal{AccountID: "uuid-1"}
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	accRepo := func(status model.AccountStatus) mock.AccountRepository {
+		return mock.AccountRepository{
+			OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+				return &model.Account{ID: id, Status: status}, nil
+			},
+			OnGetByID: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+				return &model.Account{ID: id, CPF: "59951332099", Status: status}, nil
+			},
+		}
+	}
+	notFound := func(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error) {
+		return nil, repository.ErrPixKeyNotFound
+	}
+	createOK := func(ctx context.Context, key *model.PixKey) error {
+		return nil
+	}
+	noSender := mock.PixKeyVerificationSender{}
+
+	type fields struct {
+		keyRepo   repository.PixKeyRepository
+		accRepo   repository.AccountRepository
+		keySender repository.PixKeyVerificationSender
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		input      PixKeyCreateInput
+		wantType   string
+		wantKey    string
+		wantStatus string
+		wantErr    error
+	}{
+		{
+			name:    "invalid input should return error",
+			fields:  fields{keyRepo: mock.PixKeyRepository{}, accRepo: mock.AccountRepository{}, keySender: noSender},
+			input:   PixKeyCreateInput{Type: "email", Key: "homer"},
+			wantErr: ErrPixKeyValueInvalid,
+		},
+		{
+			name: "blocked account should return error",
+			fields: fields{
+				keyRepo:   mock.PixKeyRepository{OnWithinTransaction: withinTransaction},
+				accRepo:   accRepo(model.AccountStatusBlocked),
+				keySender: noSender,
+			},
+			input:   PixKeyCreateInput{Type: "random"},
+			wantErr: ErrPixKeyAccountNotActive,
+		},
+		{
+			name: "cpf of someone else should return error",
+			fields: fields{
+				keyRepo:   mock.PixKeyRepository{OnWithinTransaction: withinTransaction},
+				accRepo:   accRepo(model.AccountStatusActive),
+				keySender: noSender,
+			},
+			input:   PixKeyCreateInput{Type: "cpf", Key: "343.639.162-06"},
+			wantErr: ErrPixKeyCPFNotOwn,
+		},
+		{
+			name: "active key of another account should return already exists",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnWithinTransaction:          withinTransaction,
+					OnGetByAccountValueForUpdate: notFound,
+					OnCreate: func(ctx context.Context, key *model.PixKey) error {
+						return repository.ErrPixKeyAlreadyExists
+					},
+				},
+				accRepo:   accRepo(model.AccountStatusActive),
+				keySender: noSender,
+			},
+			input:   PixKeyCreateInput{Type: "phone", Key: "+5511987654321"},
+			wantErr: repository.ErrPixKeyAlreadyExists,
+		},
+		{
+			name: "account without room should return limit reached",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnWithinTransaction:          withinTransaction,
+					OnGetByAccountValueForUpdate: notFound,
+					OnCreate: func(ctx context.Context, key *model.PixKey) error {
+						return repository.ErrPixKeyLimitReached
+					},
+				},
+				accRepo:   accRepo(model.AccountStatusActive),
+				keySender: noSender,
+			},
+			input:   PixKeyCreateInput{Type: "random"},
+			wantErr: repository.ErrPixKeyLimitReached,
+		},
+		{
+			name: "repository error should return create error",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByAccountValueForUpdate: func(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error) {
+						return nil, errors.New("connection refused")
+					},
+				},
+				accRepo:   accRepo(model.AccountStatusActive),
+				keySender: noSender,
+			},
+			input:   PixKeyCreateInput{Type: "phone", Key: "+5511987654321"},
+			wantErr: ErrPixKeyCreate,
+		},
+		{
+			name: "cpf key defaults to the holder's",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnWithinTransaction:          withinTransaction,
+					OnGetByAccountValueForUpdate: notFound,
+					OnCreate:                     createOK,
+				},
+				accRepo:   accRepo(model.AccountStatusActive),
+				keySender: noSender,
+			},
+			input:      PixKeyCreateInput{Type: "cpf"},
+			wantType:   "cpf",
+			wantKey:    "59951332099",
+			wantStatus: "active",
+		},
+		{
+			name: "email key is pending and the code is sent",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnWithinTransaction:          withinTransaction,
+					OnGetByAccountValueForUpdate: notFound,
+					OnCreate: func(ctx context.Context, key *model.PixKey) error {
+						if key.VerificationCode == "" || key.VerificationExpiresAt.IsZero() {
+							return errors.New("should save the verification")
+						}
+						return nil
+					},
+				},
+				accRepo: accRepo(model.AccountStatusActive),
+				keySender: mock.PixKeyVerificationSender{
+					OnSendVerificationCode: func(ctx context.Context, key *model.PixKey, code string) error {
+						if key.Value != "homer@springfield.com" || len(code) != 6 {
+							return errors.New("should send the code to the key")
+						}
+						return nil
+					},
+				},
+			},
+			input:      PixKeyCreateInput{Type: "email", Key: "Homer@Springfield.com"},
+			wantType:   "email",
+			wantKey:    "homer@springfield.com",
+			wantStatus: "pending",
+		},
+		{
+			name: "pending key of the account sends a new code",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByAccountValueForUpdate: func(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error) {
+						if accountID != "uuid-1" {
+							return nil, errors.New("should get the key of the caller account")
+						}
+						return &model.PixKey{ID: "key-1", AccountID: "uuid-1", Type: model.PixKeyTypeEmail, Value: value, Status: model.PixKeyStatusPending, VerificationAttempts: 3}, nil
+					},
+					OnUpdate: func(ctx context.Context, key *model.PixKey) error {
+						if key.ID != "key-1" || key.VerificationAttempts != 0 {
+							return errors.New("should restart the verification of the key")
+						}
+						return nil
+					},
+				},
+				accRepo: accRepo(model.AccountStatusActive),
+				keySender: mock.PixKeyVerificationSender{
+					OnSendVerificationCode: func(ctx context.Context, key *model.PixKey, code string) error {
+						return nil
+					},
+				},
+			},
+			input:      PixKeyCreateInput{Type: "email", Key: "homer@springfield.com"},
+			wantType:   "email",
+			wantKey:    "homer@springfield.com",
+			wantStatus: "pending",
+		},
+		{
+			name: "sender error should return create error",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnWithinTransaction:          withinTransaction,
+					OnGetByAccountValueForUpdate: notFound,
+					OnCreate:                     createOK,
+				},
+				accRepo: accRepo(model.AccountStatusActive),
+				keySender: mock.PixKeyVerificationSender{
+					OnSendVerificationCode: func(ctx context.Context, key *model.PixKey, code string) error {
+						return errors.New("mailbox unavailable")
+					},
+				},
+			},
+			input:   PixKeyCreateInput{Type: "email", Key: "homer@springfield.com"},
+			wantErr: ErrPixKeyCreate,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			keyUC := NewPixKeyUseCase(tt.fields.keyRepo, tt.fields.accRepo, tt.fields.keySender)
+
+			got, err := keyUC.Create(backgroundCtx, caller, tt.input)
+			if err != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got == nil {
+				return
+			}
+
+			if got.Type != tt.wantType || got.Key != tt.wantKey || got.Status != tt.wantStatus {
+				t.Errorf("Create() got = %+v, want type %v, key %v and status %v", got, tt.wantType, tt.wantKey, tt.wantStatus)
+			}
+			if (got.VerificationExpiresAt != nil) != (tt.wantStatus == "pending") {
+				t.Errorf("Create() verificationExpiresAt = %v, want it only for pending keys", got.VerificationExpiresAt)
+			}
+			if got.VerificationExpiresAt != nil && got.VerificationExpiresAt.Before(time.Now()) {
+				t.Errorf("Create() verificationExpiresAt = %v, want it in the future", got.VerificationExpiresAt)
+			}
+		})
+	}
+}
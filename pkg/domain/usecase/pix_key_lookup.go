@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/cpfutil"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrPixKeyLookup happens when an error occurred while looking up a pix key.
+	ErrPixKeyLookup = errors.New("could not look up pix key")
+)
+
+// PixKeyLookupOutput represents the holder of the account a pix key addresses, masked,
+// so the sender can confirm the recipient before transferring.
+type PixKeyLookupOutput struct {
+	Type string `json:"type" example:"email" enums:"cpf,email,phone,random"`
+	Key  string `json:"key" example:"homer@springfield.com"`
+	Name string `json:"name" example:"Homer J. S."`
+	CPF  string `json:"cpf" example:"***.456.789-**"`
+}
+
+// Lookup returns the masked holder of the account the key addresses. Any caller can look keys up.
+// Pending keys and the keys of closed accounts are not found.
+func (keyUC pixKeyUseCase) Lookup(ctx context.Context, caller model.Principal, key string) (*PixKeyLookupOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pixKey, err := getActivePixKey(ctx, keyUC.keyRepo, key)
+	if err != nil {
+		if err == repository.ErrPixKeyNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error getting pix key")
+		return nil, ErrPixKeyLookup
+	}
+
+	account, err := keyUC.accRepo.GetByID(ctx, pixKey.AccountID)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Str("keyID", string(pixKey.ID)).Msg("error getting pix key account")
+		return nil, ErrPixKeyLookup
+	}
+	if account.Status == model.AccountStatusClosed {
+		return nil, repository.ErrPixKeyNotFound
+	}
+
+	log.Ctx(ctx).Info().Str("keyID", string(pixKey.ID)).Str("by", string(caller.AccountID)).Msg("pix key looked up")
+
+	return &PixKeyLookupOutput{
+		Type: string(pixKey.Type),
+		Key:  pixKey.Value,
+		Name: maskName(account.Name),
+		CPF:  cpfutil.Mask(string(account.CPF)),
+	}, nil
+}
+
+// getActivePixKey returns the active key with the value, in any format, or repository.ErrPixKeyNotFound.
+func getActivePixKey(ctx context.Context, keyRepo repository.PixKeyRepository, key string) (*model.PixKey, error) {
+	_, value, err := model.ParsePixKey(key)
+	if err != nil {
+		return nil, repository.ErrPixKeyNotFound
+	}
+
+	pixKey, err := keyRepo.GetByValue(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+	if !pixKey.IsActive() {
+		return nil, repository.ErrPixKeyNotFound
+	}
+
+	return pixKey, nil
+}
+
+// maskName keeps the first name and only the initials of the others, like "Homer J. S." for "Homer Jay Simpson".
+func maskName(name string) string {
+	names := strings.Fields(name)
+	for i := 1; i < len(names); i++ {
+		initial, _ := utf8.DecodeRuneInString(names[i])
+		names[i] = string(initial) + "."
+	}
+
+	return strings.Join(names, " ")
+}
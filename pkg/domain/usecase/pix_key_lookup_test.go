@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_pixKeyUseCase_Lookup(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	caller := model.Principal{AccountID: "uuid-2"}
+
+	keyWith := func(status model.PixKeyStatus) func(ctx context.Context, value string) (*model.PixKey, error) {
+		return func(ctx context.Context, value string) (*model.PixKey, error) {
+			return &model.PixKey{ID: "key-1", AccountID: "uuid-1", Type: model.PixKeyTypePhone, Value: value, Status: status}, nil
+		}
+	}
+	accountWith := func(status model.AccountStatus) mock.AccountRepository {
+		return mock.AccountRepository{
+			OnGetByID: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+				return &model.Account{ID: id, Name: "Homer Jay Simpson", CPF: "59951332099", Status: status}, nil
+			},
+		}
+	}
+
+	type fields struct {
+		keyRepo repository.PixKeyRepository
+		accRepo repository.AccountRepository
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		key     string
+		want    *PixKeyLookupOutput
+		wantErr error
+	}{
+		{
+			name:    "invalid key should return not found",
+			fields:  fields{keyRepo: mock.PixKeyRepository{}, accRepo: mock.AccountRepository{}},
+			key:     "homer",
+			wantErr: repository.ErrPixKeyNotFound,
+		},
+		{
+			name: "unknown key should return not found",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnGetByValue: func(ctx context.Context, value string) (*model.PixKey, error) {
+						return nil, repository.ErrPixKeyNotFound
+					},
+				},
+				accRepo: mock.AccountRepository{},
+			},
+			key:     "+5511987654321",
+			wantErr: repository.ErrPixKeyNotFound,
+		},
+		{
+			name: "pending key should return not found",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{OnGetByValue: keyWith(model.PixKeyStatusPending)},
+				accRepo: mock.AccountRepository{},
+			},
+			key:     "+5511987654321",
+			wantErr: repository.ErrPixKeyNotFound,
+		},
+		{
+			name: "key of closed account should return not found",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{OnGetByValue: keyWith(model.PixKeyStatusActive)},
+				accRepo: accountWith(model.AccountStatusClosed),
+			},
+			key:     "+5511987654321",
+			wantErr: repository.ErrPixKeyNotFound,
+		},
+		{
+			name: "repository error should return lookup error",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnGetByValue: func(ctx context.Context, value string) (*model.PixKey, error) {
+						return nil, errors.New("connection refused")
+					},
+				},
+				accRepo: mock.AccountRepository{},
+			},
+			key:     "+5511987654321",
+			wantErr: ErrPixKeyLookup,
+		},
+		{
+			name: "success with the holder masked",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{OnGetByValue: keyWith(model.PixKeyStatusActive)},
+				accRepo: accountWith(model.AccountStatusBlocked),
+			},
+			key: "+55 (11) 98765-4321",
+			want: &PixKeyLookupOutput{
+				Type: "phone",
+				Key:  "+5511987654321",
+				Name: "Homer J. S.",
+				CPF:  "***.513.320-**",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			keyUC := NewPixKeyUseCase(tt.fields.keyRepo, tt.fields.accRepo, mock.PixKeyVerificationSender{})
+
+			got, err := keyUC.Lookup(backgroundCtx, caller, tt.key)
+			if err != tt.wantErr {
+				t.Errorf("Lookup() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lookup() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_maskName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Homer Jay Simpson", want: "Homer J. S."},
+		{name: "  Marge   Bouvier ", want: "Marge B."},
+		{name: "Maggie", want: "Maggie"},
+		{name: "Ned Élcio Flanders", want: "Ned É. F."},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := maskName(tt.name); got != tt.want {
+				t.Errorf("maskName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
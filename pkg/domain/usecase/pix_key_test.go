@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_pixKeyUseCase_Fetch(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	createdAt := time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(model.PixKeyVerificationTTL)
+
+	tests := []struct {
+		name    string
+		keyRepo repository.PixKeyRepository
+		want    []PixKeyOutput
+		wantErr error
+	}{
+		{
+			name: "repository error should return fetch error",
+			keyRepo: mock.PixKeyRepository{
+				OnFetch: func(ctx context.Context, accountID model.AccountID) ([]model.PixKey, error) {
+					return nil, errors.New("connection refused")
+				},
+			},
+			wantErr: ErrPixKeyFetch,
+		},
+		{
+			name: "only pending keys have the verification expiration",
+			keyRepo: mock.PixKeyRepository{
+				OnFetch: func(ctx context.Context, accountID model.AccountID) ([]model.PixKey, error) {
+					if accountID != "uuid-1" {
+						return nil, errors.New("should fetch the keys of the caller")
+					}
+					return []model.PixKey{
+						{Type: model.PixKeyTypeCPF, Value: "59951332099", Status: model.PixKeyStatusActive, CreatedAt: createdAt},
+						{Type: model.PixKeyTypeEmail, Value: "homer@springfield.com", Status: model.PixKeyStatusPending, VerificationExpiresAt: expiresAt, CreatedAt: createdAt},
+					}, nil
+				},
+			},
+			want: []PixKeyOutput{
+				{Type: "cpf", Key: "59951332099", Status: "active", CreatedAt: createdAt},
+				{Type: "email", Key: "homer@springfield.com", Status: "pending", VerificationExpiresAt: &expiresAt, CreatedAt: createdAt},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			keyUC := NewPixKeyUseCase(tt.keyRepo, mock.AccountRepository{}, mock.PixKeyVerificationSender{})
+
+			got, err := keyUC.Fetch(backgroundCtx, model.Principal{AccountID: "uuid-1"})
+			if err != tt.wantErr {
+				t.Errorf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fetch() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pixKeyUseCase_Delete(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	// keyOf returns the key of the owner account, the other accounts have none
+	keyOf := func(ownerID model.AccountID) func(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error) {
+		return func(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error) {
+			if accountID != ownerID {
+				return nil, repository.ErrPixKeyNotFound
+			}
+			return &model.PixKey{ID: "key-1", AccountID: ownerID, Type: model.PixKeyTypeRandom, Value: value, Status: model.PixKeyStatusActive}, nil
+		}
+	}
+
+	tests := []struct {
+		name    string
+		keyRepo repository.PixKeyRepository
+		key     string
+		wantErr error
+	}{
+		{
+			name:    "invalid key should return not found",
+			keyRepo: mock.PixKeyRepository{},
+			key:     "homer",
+			wantErr: repository.ErrPixKeyNotFound,
+		},
+		{
+			name:    "key of another account should return not found",
+			keyRepo: mock.PixKeyRepository{OnGetByAccountValue: keyOf("uuid-2")},
+			key:     "1a2b3c4d-5e6f-4a8b-9c0d-1e2f3a4b5c6d",
+			wantErr: repository.ErrPixKeyNotFound,
+		},
+		{
+			name: "repository error should return delete error",
+			keyRepo: mock.PixKeyRepository{
+				OnGetByAccountValue: keyOf("uuid-1"),
+				OnDelete: func(ctx context.Context, id model.PixKeyID) error {
+					return errors.New("connection refused")
+				},
+			},
+			key:     "1a2b3c4d-5e6f-4a8b-9c0d-1e2f3a4b5c6d",
+			wantErr: ErrPixKeyDelete,
+		},
+		{
+			name: "success",
+			keyRepo: mock.PixKeyRepository{
+				OnGetByAccountValue: keyOf("uuid-1"),
+				OnDelete: func(ctx context.Context, id model.PixKeyID) error {
+					if id != "key-1" {
+						return errors.New("should delete the key")
+					}
+					return nil
+				},
+			},
+			key: "1a2b3c4d-5e6f-4a8b-9c0d-1e2f3a4b5c6d",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			keyUC := NewPixKeyUseCase(tt.keyRepo, mock.AccountRepository{}, mock.PixKeyVerificationSender{})
+
+			if err := keyUC.Delete(backgroundCtx, model.Principal{AccountID: "uuid-1"}, tt.key); err != tt.wantErr {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
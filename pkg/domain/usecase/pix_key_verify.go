@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrPixKeyVerificationCodeRequired happens when the verification code is blank.
+	ErrPixKeyVerificationCodeRequired = errors.New("'code' is required")
+	// ErrPixKeyVerificationCodeWrong happens when the verification code is not the one sent.
+	ErrPixKeyVerificationCodeWrong = errors.New("verification code is wrong")
+	// ErrPixKeyVerificationExpired happens when the verification code is too old or too many wrong codes were tried.
+	// Registering the key again sends a new code.
+	ErrPixKeyVerificationExpired = errors.New("verification code expired, register the key again to get a new one")
+	// ErrPixKeyAlreadyVerified happens when verifying an active key.
+	ErrPixKeyAlreadyVerified = errors.New("pix key is already verified")
+	// ErrPixKeyVerify happens when an error occurred and the pix key was not verified.
+	ErrPixKeyVerify = errors.New("could not verify pix key")
+)
+
+// PixKeyVerifyInput represents the expected input data when verifying a pix key.
+type PixKeyVerifyInput struct {
+	Key  string `json:"-"`
+	Code string `json:"code" example:"123456"`
+}
+
+// Validate validates the PixKeyVerifyInput fields.
+func (input *PixKeyVerifyInput) Validate() error {
+	input.Code = strings.TrimSpace(input.Code)
+	if input.Code == "" {
+		return ErrPixKeyVerificationCodeRequired
+	}
+
+	return nil
+}
+
+// Verify activates a pending key of the caller account with the code sent to it, dropping the pending keys of the
+// other accounts with the same value. It fails with repository.ErrPixKeyAlreadyExists when another account verified it
+// first. The wrong codes are counted and, after model.PixKeyMaxVerificationAttempts of them, the verification expires.
+// The keys of other accounts are not found.
+func (keyUC pixKeyUseCase) Verify(ctx context.Context, caller model.Principal, verifyInput PixKeyVerifyInput) (*PixKeyOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := verifyInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("pix key verify input is not valid")
+		return nil, err
+	}
+
+	_, value, err := model.ParsePixKey(verifyInput.Key)
+	if err != nil {
+		return nil, repository.ErrPixKeyNotFound
+	}
+
+	data, err := keyUC.keyRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		key, err := keyUC.keyRepo.GetByAccountValueForUpdate(txCtx, caller.AccountID, value)
+		if err != nil {
+			return nil, err
+		}
+		if key.IsActive() {
+			return nil, ErrPixKeyAlreadyVerified
+		}
+		if key.IsVerificationExpired() {
+			return nil, ErrPixKeyVerificationExpired
+		}
+
+		// the wrong attempts are saved too, so the transaction is not rolled back for them
+		verified := key.Verify(verifyInput.Code)
+		err = keyUC.keyRepo.Update(txCtx, key)
+		if err != nil || !verified {
+			return nil, err
+		}
+
+		return key, nil
+	})
+	if err != nil {
+		switch err {
+		case repository.ErrPixKeyNotFound, repository.ErrPixKeyAlreadyExists, ErrPixKeyAlreadyVerified, ErrPixKeyVerificationExpired:
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error verifying pix key")
+		return nil, ErrPixKeyVerify
+	}
+
+	key, _ := data.(*model.PixKey)
+	if key == nil {
+		log.Ctx(ctx).Warn().Str("accountID", string(caller.AccountID)).Msg("wrong pix key verification code")
+		return nil, ErrPixKeyVerificationCodeWrong
+	}
+
+	log.Ctx(ctx).Info().Str("keyID", string(key.ID)).Str("accountID", string(key.AccountID)).Msg("pix key verified")
+
+	return newPixKeyOutput(key), nil
+}
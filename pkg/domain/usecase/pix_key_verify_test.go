@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_pixKeyUseCase_Verify(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	caller := model.Principal{AccountID: "uuid-1"}
+
+	hashedCode, err := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	// keyWith returns the key of the owner account, the other accounts have none
+	keyWith := func(ownerID model.AccountID, status model.PixKeyStatus, expiresAt time.Time, attempts int) func(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error) {
+		return func(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error) {
+			if accountID != ownerID {
+				return nil, repository.ErrPixKeyNotFound
+			}
+			return &model.PixKey{
+				ID:                    "key-1",
+				AccountID:             ownerID,
+				Type:                  model.PixKeyTypeEmail,
+				Value:                 value,
+				Status:                status,
+				VerificationCode:      string(hashedCode),
+				VerificationExpiresAt: expiresAt,
+				VerificationAttempts:  attempts,
+			}, nil
+		}
+	}
+	inFiveMinutes := time.Now().Add(5 * time.Minute)
+
+	tests := []struct {
+		name    string
+		keyRepo repository.PixKeyRepository
+		input   PixKeyVerifyInput
+		want    *PixKeyOutput
+		wantErr error
+	}{
+		{
+			name:    "blank code should return error",
+			keyRepo: mock.PixKeyRepository{},
+			input:   PixKeyVerifyInput{Key: "homer@springfield.com", Code: " "},
+			wantErr: ErrPixKeyVerificationCodeRequired,
+		},
+		{
+			name:    "invalid key should return not found",
+			keyRepo: mock.PixKeyRepository{},
+			input:   PixKeyVerifyInput{Key: "homer", Code: "123456"},
+			wantErr: repository.ErrPixKeyNotFound,
+		},
+		{
+			name: "key of another account should return not found",
+			keyRepo: mock.PixKeyRepository{
+				OnWithinTransaction:          withinTransaction,
+				OnGetByAccountValueForUpdate: keyWith("uuid-2", model.PixKeyStatusPending, inFiveMinutes, 0),
+			},
+			input:   PixKeyVerifyInput{Key: "homer@springfield.com", Code: "123456"},
+			wantErr: repository.ErrPixKeyNotFound,
+		},
+		{
+			name: "active key should return already verified",
+			keyRepo: mock.PixKeyRepository{
+				OnWithinTransaction:          withinTransaction,
+				OnGetByAccountValueForUpdate: keyWith("uuid-1", model.PixKeyStatusActive, inFiveMinutes, 0),
+			},
+			input:   PixKeyVerifyInput{Key: "homer@springfield.com", Code: "123456"},
+			wantErr: ErrPixKeyAlreadyVerified,
+		},
+		{
+			name: "old code should return expired",
+			keyRepo: mock.PixKeyRepository{
+				OnWithinTransaction:          withinTransaction,
+				OnGetByAccountValueForUpdate: keyWith("uuid-1", model.PixKeyStatusPending, time.Now().Add(-time.Second), 0),
+			},
+			input:   PixKeyVerifyInput{Key: "homer@springfield.com", Code: "123456"},
+			wantErr: ErrPixKeyVerificationExpired,
+		},
+		{
+			name: "too many wrong codes should return expired",
+			keyRepo: mock.PixKeyRepository{
+				OnWithinTransaction:          withinTransaction,
+				OnGetByAccountValueForUpdate: keyWith("uuid-1", model.PixKeyStatusPending, inFiveMinutes, model.PixKeyMaxVerificationAttempts),
+			},
+			input:   PixKeyVerifyInput{Key: "homer@springfield.com", Code: "123456"},
+			wantErr: ErrPixKeyVerificationExpired,
+		},
+		{
+			name: "wrong code should save the attempt and return error",
+			keyRepo: mock.PixKeyRepository{
+				OnWithinTransaction:          withinTransaction,
+				OnGetByAccountValueForUpdate: keyWith("uuid-1", model.PixKeyStatusPending, inFiveMinutes, 1),
+				OnUpdate: func(ctx context.Context, key *model.PixKey) error {
+					if key.VerificationAttempts != 2 || key.IsActive() {
+						return errors.New("should save the wrong attempt")
+					}
+					return nil
+				},
+			},
+			input:   PixKeyVerifyInput{Key: "homer@springfield.com", Code: "654321"},
+			wantErr: ErrPixKeyVerificationCodeWrong,
+		},
+		{
+			name: "key verified first by another account should return already exists",
+			keyRepo: mock.PixKeyRepository{
+				OnWithinTransaction:          withinTransaction,
+				OnGetByAccountValueForUpdate: keyWith("uuid-1", model.PixKeyStatusPending, inFiveMinutes, 0),
+				OnUpdate: func(ctx context.Context, key *model.PixKey) error {
+					return repository.ErrPixKeyAlreadyExists
+				},
+			},
+			input:   PixKeyVerifyInput{Key: "homer@springfield.com", Code: "123456"},
+			wantErr: repository.ErrPixKeyAlreadyExists,
+		},
+		{
+			name: "update error should return verify error",
+			keyRepo: mock.PixKeyRepository{
+				OnWithinTransaction:          withinTransaction,
+				OnGetByAccountValueForUpdate: keyWith("uuid-1", model.PixKeyStatusPending, inFiveMinutes, 0),
+				OnUpdate: func(ctx context.Context, key *model.PixKey) error {
+					return errors.New("connection refused")
+				},
+			},
+			input:   PixKeyVerifyInput{Key: "homer@springfield.com", Code: "123456"},
+			wantErr: ErrPixKeyVerify,
+		},
+		{
+			name: "right code should activate the key",
+			keyRepo: mock.PixKeyRepository{
+				OnWithinTransaction:          withinTransaction,
+				OnGetByAccountValueForUpdate: keyWith("uuid-1", model.PixKeyStatusPending, inFiveMinutes, 2),
+				OnUpdate: func(ctx context.Context, key *model.PixKey) error {
+					if !key.IsActive() || key.VerificationCode != "" {
+						return errors.New("should save the key active")
+					}
+					return nil
+				},
+			},
+			input: PixKeyVerifyInput{Key: "Homer@Springfield.com", Code: "123456"},
+			want: &PixKeyOutput{
+				Type:   "email",
+				Key:    "homer@springfield.com",
+				Status: "active",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			keyUC := NewPixKeyUseCase(tt.keyRepo, mock.AccountRepository{}, mock.PixKeyVerificationSender{})
+
+			got, err := keyUC.Verify(backgroundCtx, caller, tt.input)
+			if err != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Errorf("Verify() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
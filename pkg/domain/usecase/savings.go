@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/infraestructure/monitoring"
+)
+
+// SavingsPolicy defines the yield on the positive balances, the account it's paid from, the holidays that are not
+// business days and the time zone of the days.
+//
+// Only the accounts in the currency of the TreasuryAccountID accrue interest, and an empty one or a zero AnnualRate
+// accrues none. A nil Location means UTC.
+type SavingsPolicy struct {
+	TreasuryAccountID model.AccountID
+	AnnualRate        model.InterestRate
+	Holidays          []time.Time
+	Location          *time.Location
+}
+
+// accrues checks whether the positive balances accrue interest.
+func (policy SavingsPolicy) accrues() bool {
+	return policy.TreasuryAccountID != "" && policy.AnnualRate > 0
+}
+
+// today returns the midnight starting the day that contains now.
+func (policy SavingsPolicy) today(now time.Time) time.Time {
+	location := policy.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	now = now.In(location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+}
+
+// isBusinessDay checks whether the day is a weekday and not one of the Holidays.
+func (policy SavingsPolicy) isBusinessDay(day time.Time) bool {
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return false
+	}
+
+	for _, holiday := range policy.Holidays {
+		if holiday.Year() == day.Year() && holiday.YearDay() == day.YearDay() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SavingsUseCase is the interface that wraps all business logic methods related to the savings interest.
+type SavingsUseCase interface {
+	ExecuteDue(ctx context.Context, limit int) (int, error)
+	GetHistory(ctx context.Context, caller model.Principal, historyInput SavingsHistoryInput) (*SavingsHistoryOutput, error)
+}
+
+type savingsUseCase struct {
+	savRepo       repository.SavingsRepository
+	accRepo       repository.AccountRepository
+	ledgerRepo    repository.LedgerRepository
+	savingsPolicy SavingsPolicy
+}
+
+// NewSavingsUseCase instantiates a new SavingsUseCase.
+func NewSavingsUseCase(
+	savRepo repository.SavingsRepository,
+	accRepo repository.AccountRepository,
+	ledgerRepo repository.LedgerRepository,
+	savingsPolicy SavingsPolicy,
+) SavingsUseCase {
+	return &savingsUseCase{
+		savRepo:       savRepo,
+		accRepo:       accRepo,
+		ledgerRepo:    ledgerRepo,
+		savingsPolicy: savingsPolicy,
+	}
+}
+
+// ExecuteDue accrues the interest of the last day that ended, when it's a business day, on up to limit accounts, and
+// then pays the interest of the last month that ended to the remaining of the limit, returning how many accounts were
+// processed.
+//
+// The accounts are picked and accrue the interest of a day by the balance they had when the day ended, rebuilt from
+// the ledger, so the movements made since midnight don't change it. Each account accrues once a day and is paid once a month, in its own transaction holding its row
+// lock, so it's safe to run on multiple replicas and again on the same day. The days the executor didn't run are
+// not accrued later.
+func (savUC savingsUseCase) ExecuteDue(ctx context.Context, limit int) (int, error) {
+	if !savUC.savingsPolicy.accrues() {
+		return 0, nil
+	}
+
+	today := savUC.savingsPolicy.today(time.Now())
+
+	treasuryAccount, err := savUC.getTreasuryAccount(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+
+	day := today.AddDate(0, 0, -1)
+	if savUC.savingsPolicy.isBusinessDay(day) {
+		filter := model.SavingsAccrualFilter{
+			Day:               day,
+			DayEnd:            today,
+			Currency:          treasuryAccount.Currency,
+			TreasuryAccountID: treasuryAccount.ID,
+		}
+		dailyRate := model.NewSavingsDailyRate(savUC.savingsPolicy.AnnualRate)
+
+		for processed < limit {
+			accrual, err := savUC.accrueNextDue(ctx, filter, dailyRate)
+			if err != nil {
+				return processed, err
+			}
+			if accrual == nil {
+				break
+			}
+
+			processed++
+			monitoring.SavingsInterestAccruals.Inc()
+			log.Ctx(ctx).Debug().Str("accountID", string(accrual.AccountID)).Str("day", accrual.Day.Format("2006-01-02")).
+				Int64("balance", int64(accrual.Balance)).Str("amount", accrual.Amount.String()).Msg("savings interest accrued")
+		}
+	}
+
+	monthEnd := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+	filter := model.SavingsPaymentFilter{
+		Month:             monthEnd.AddDate(0, -1, 0),
+		MonthEnd:          monthEnd,
+		Currency:          treasuryAccount.Currency,
+		TreasuryAccountID: treasuryAccount.ID,
+	}
+
+	for processed < limit {
+		payment, err := savUC.payNextDue(ctx, filter)
+		if err != nil {
+			return processed, err
+		}
+		if payment == nil {
+			break
+		}
+
+		processed++
+		monitoring.SavingsInterestPayments.Inc()
+		log.Ctx(ctx).Info().Str("accountID", string(payment.AccountID)).Str("month", payment.Month.Format("2006-01")).
+			Int64("amount", int64(payment.Amount)).Msg("savings interest paid")
+	}
+
+	return processed, nil
+}
+
+// getTreasuryAccount returns the account the interest is paid from, which tells the currency of the accounts that
+// accrue it.
+func (savUC savingsUseCase) getTreasuryAccount(ctx context.Context) (*model.Account, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	treasuryAccount, err := savUC.accRepo.GetBalance(ctx, savUC.savingsPolicy.TreasuryAccountID)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(savUC.savingsPolicy.TreasuryAccountID)).
+			Msg("error getting treasury account")
+		return nil, err
+	}
+
+	return treasuryAccount, nil
+}
+
+// accrueNextDue accrues the interest of the day on the next account that had a positive balance when the day ended,
+// returning the accrual, or nil if there's none.
+func (savUC savingsUseCase) accrueNextDue(ctx context.Context, filter model.SavingsAccrualFilter, dailyRate model.SavingsDailyRate) (*model.SavingsInterestAccrual, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	data, err := savUC.savRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		account, err := savUC.savRepo.LockNextAccrualDue(txCtx, filter)
+		if err != nil || account == nil {
+			return nil, err
+		}
+
+		// the movements made since midnight don't count
+		balance, err := savUC.ledgerRepo.GetBalanceAt(txCtx, account.ID, account.Currency, filter.DayEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		accrual := model.NewSavingsInterestAccrual(account.ID, filter.Day, balance, dailyRate)
+
+		return accrual, savUC.savRepo.CreateAccrual(txCtx, accrual)
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error accruing savings interest")
+		return nil, err
+	}
+
+	accrual, _ := data.(*model.SavingsInterestAccrual)
+	return accrual, nil
+}
+
+// payNextDue pays the interest of the month to the next account that accrued it, returning the payment,
+// or nil if there's none.
+func (savUC savingsUseCase) payNextDue(ctx context.Context, filter model.SavingsPaymentFilter) (*model.SavingsInterestPayment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	data, err := savUC.savRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		// the treasury account is locked before the paid one, which is skipped when a transfer holds it,
+		// so it can't deadlock with the transfers locking both
+		treasuryAccount, err := savUC.accRepo.GetBalanceForUpdate(txCtx, filter.TreasuryAccountID)
+		if err != nil {
+			return nil, err
+		}
+
+		account, err := savUC.savRepo.LockNextPaymentDue(txCtx, filter)
+		if err != nil || account == nil {
+			return nil, err
+		}
+
+		totals, err := savUC.savRepo.GetTotals(txCtx, account.ID, filter.MonthEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		payment := model.NewSavingsInterestPayment(account.ID, filter.Month, totals.Unpaid())
+
+		// the treasury account pays even beyond its credit limit, the bank owes the interest anyway
+		if payment.Amount > 0 {
+			posting := model.NewLedgerPosting(
+				model.LedgerPostingSavingsInterest,
+				string(payment.ID),
+				treasuryAccount.ID,
+				account.ID,
+				payment.Amount,
+				treasuryAccount.Currency)
+
+			err = savUC.ledgerRepo.Post(txCtx, posting)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return payment, savUC.savRepo.CreatePayment(txCtx, payment)
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error paying savings interest")
+		return nil, err
+	}
+
+	payment, _ := data.(*model.SavingsInterestPayment)
+	return payment, nil
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrSavingsHistoryPeriodInvalid happens when the savings history period ends before it starts.
+	ErrSavingsHistoryPeriodInvalid = errors.New("'from' must not be after 'to'")
+	// ErrSavingsGetHistory happens when an error occurred while getting the savings interest history.
+	ErrSavingsGetHistory = errors.New("could not get savings interest history")
+)
+
+// SavingsHistoryInput represents the expected input data when getting the savings interest history of an account.
+// From and To are dates, and the period includes both. When To is zero it's today, when From is zero it's the first
+// day of the month of To.
+type SavingsHistoryInput struct {
+	AccountID model.AccountID
+	From      time.Time
+	To        time.Time
+}
+
+// Validate validates the SavingsHistoryInput fields, after the period defaults are filled.
+func (input *SavingsHistoryInput) Validate() error {
+	if input.From.After(input.To) {
+		return ErrSavingsHistoryPeriodInvalid
+	}
+
+	return nil
+}
+
+// SavingsAccrualOutput represents the interest accrued on a business day.
+// The interest has all its decimal places, so the sum of the days is exact.
+type SavingsAccrualOutput struct {
+	Day       string `json:"day" example:"2021-01-04"`
+	Balance   Amount `json:"balance" swaggertype:"number" example:"10000"`
+	DailyRate string `json:"daily_rate" example:"0.00050788"`
+	Interest  string `json:"interest" example:"5.0788000000"`
+}
+
+// SavingsPaymentOutput represents the interest of a month credited to the account.
+type SavingsPaymentOutput struct {
+	Month  string    `json:"month" example:"2021-01"`
+	Amount Amount    `json:"amount" swaggertype:"number" example:"101.57"`
+	PaidAt time.Time `json:"paid_at" example:"2021-02-01T00:01:00-03:00"`
+}
+
+// SavingsHistoryOutput represents the output data of the GetHistory method: the interest accrued on the days of the
+// period, its total, and the interest paid for the months of the period.
+type SavingsHistoryOutput struct {
+	AccountID       string                 `json:"account_id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	AnnualRate      string                 `json:"annual_rate" example:"13.65%"`
+	From            string                 `json:"from" example:"2021-01-01"`
+	To              string                 `json:"to" example:"2021-01-31"`
+	AccruedInterest string                 `json:"accrued_interest" example:"101.5760000000"`
+	Accruals        []SavingsAccrualOutput `json:"accruals"`
+	Payments        []SavingsPaymentOutput `json:"payments"`
+}
+
+func newSavingsHistoryOutput(input SavingsHistoryInput, currency model.Currency, annualRate model.InterestRate, accruals []model.SavingsInterestAccrual, payments []model.SavingsInterestPayment) *SavingsHistoryOutput {
+	output := &SavingsHistoryOutput{
+		AccountID:  string(input.AccountID),
+		AnnualRate: annualRate.String(),
+		From:       input.From.Format("2006-01-02"),
+		To:         input.To.Format("2006-01-02"),
+		Accruals:   make([]SavingsAccrualOutput, 0, len(accruals)),
+		Payments:   make([]SavingsPaymentOutput, 0, len(payments)),
+	}
+
+	var accrued model.AccruedInterest
+	for _, accrual := range accruals {
+		accrued += accrual.Amount
+		output.Accruals = append(output.Accruals, SavingsAccrualOutput{
+			Day:       accrual.Day.Format("2006-01-02"),
+			Balance:   NewAmountIn(accrual.Balance, currency),
+			DailyRate: accrual.Rate.String(),
+			Interest:  accrual.Amount.String(),
+		})
+	}
+	output.AccruedInterest = accrued.String()
+
+	for _, payment := range payments {
+		output.Payments = append(output.Payments, SavingsPaymentOutput{
+			Month:  payment.Month.Format("2006-01"),
+			Amount: NewAmountIn(payment.Amount, currency),
+			PaidAt: payment.CreatedAt,
+		})
+	}
+
+	return output
+}
+
+// GetHistory returns the savings interest accrued on the account on the days of the period and paid for the months
+// of the period. Only the account owner can get it, otherwise it returns ErrAuthForbidden.
+func (savUC savingsUseCase) GetHistory(ctx context.Context, caller model.Principal, historyInput SavingsHistoryInput) (*SavingsHistoryOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if !caller.Owns(historyInput.AccountID) {
+		return nil, ErrAuthForbidden
+	}
+
+	if historyInput.To.IsZero() {
+		today := savUC.savingsPolicy.today(time.Now())
+		historyInput.To = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if historyInput.From.IsZero() {
+		historyInput.From = time.Date(historyInput.To.Year(), historyInput.To.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	err := historyInput.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := savUC.accRepo.GetBalance(ctx, historyInput.AccountID)
+	if err != nil {
+		if err == repository.ErrAccountNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(historyInput.AccountID)).Msg("error getting savings history account")
+		return nil, ErrSavingsGetHistory
+	}
+
+	// the dates are compared as days, the period end is the day after To
+	periodEnd := historyInput.To.AddDate(0, 0, 1)
+
+	accruals, err := savUC.savRepo.FetchAccruals(ctx, historyInput.AccountID, historyInput.From, periodEnd)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(historyInput.AccountID)).Msg("error fetching savings interest accruals")
+		return nil, ErrSavingsGetHistory
+	}
+
+	fromMonth := time.Date(historyInput.From.Year(), historyInput.From.Month(), 1, 0, 0, 0, 0, time.UTC)
+	payments, err := savUC.savRepo.FetchPayments(ctx, historyInput.AccountID, fromMonth, periodEnd)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(historyInput.AccountID)).Msg("error fetching savings interest payments")
+		return nil, ErrSavingsGetHistory
+	}
+
+	return newSavingsHistoryOutput(historyInput, account.Currency, savUC.savingsPolicy.AnnualRate, accruals, payments), nil
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_savingsUseCase_GetHistory(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	from := time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 2, 5, 0, 0, 0, 0, time.UTC)
+	paidAt := time.Date(2021, 2, 1, 0, 1, 0, 0, time.UTC)
+
+	existingAccountRepo := mock.AccountRepository{
+		OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			return &model.Account{ID: id, Status: model.AccountStatusActive}, nil
+		},
+	}
+	savingsRepo := mock.SavingsRepository{
+		OnFetchAccruals: func(ctx context.Context, accountID model.AccountID, f, t time.Time) ([]model.SavingsInterestAccrual, error) {
+			if !f.Equal(from) || !t.Equal(to.AddDate(0, 0, 1)) {
+				return nil, errors.New("should fetch the days of the period")
+			}
+			return []model.SavingsInterestAccrual{
+				{AccountID: accountID, Day: time.Date(2021, 1, 29, 0, 0, 0, 0, time.UTC), Balance: 1000000, Rate: 50788, Amount: 50788000000},
+				{AccountID: accountID, Day: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), Balance: 1000001, Rate: 50788, Amount: 50788050788},
+			}, nil
+		},
+		OnFetchPayments: func(ctx context.Context, accountID model.AccountID, f, t time.Time) ([]model.SavingsInterestPayment, error) {
+			if !f.Equal(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)) || !t.Equal(to.AddDate(0, 0, 1)) {
+				return nil, errors.New("should fetch the months of the period")
+			}
+			return []model.SavingsInterestPayment{
+				{AccountID: accountID, Month: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Amount: 10157, CreatedAt: paidAt},
+			}, nil
+		},
+	}
+
+	type fields struct {
+		accountRepo repository.AccountRepository
+		savingsRepo repository.SavingsRepository
+	}
+	type args struct {
+		ctx          context.Context
+		caller       model.Principal
+		historyInput SavingsHistoryInput
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *SavingsHistoryOutput
+		wantErr error
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				accountRepo: existingAccountRepo,
+				savingsRepo: savingsRepo,
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				caller:       model.Principal{AccountID: "any-uuid-1"},
+				historyInput: SavingsHistoryInput{AccountID: "any-uuid-1", From: from, To: to},
+			},
+			want: &SavingsHistoryOutput{
+				AccountID:       "any-uuid-1",
+				AnnualRate:      "13.65%",
+				From:            "2021-01-10",
+				To:              "2021-02-05",
+				AccruedInterest: "10.1576050788",
+				Accruals: []SavingsAccrualOutput{
+					{Day: "2021-01-29", Balance: NewAmount(1000000), DailyRate: "0.00050788", Interest: "5.0788000000"},
+					{Day: "2021-02-01", Balance: NewAmount(1000001), DailyRate: "0.00050788", Interest: "5.0788050788"},
+				},
+				Payments: []SavingsPaymentOutput{
+					{Month: "2021-01", Amount: NewAmount(10157), PaidAt: paidAt},
+				},
+			},
+		},
+		{
+			name: "another account should be forbidden",
+			fields: fields{
+				accountRepo: existingAccountRepo,
+				savingsRepo: savingsRepo,
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				caller:       model.Principal{AccountID: "any-uuid-2"},
+				historyInput: SavingsHistoryInput{AccountID: "any-uuid-1", From: from, To: to},
+			},
+			wantErr: ErrAuthForbidden,
+		},
+		{
+			name: "from after to should fail",
+			fields: fields{
+				accountRepo: existingAccountRepo,
+				savingsRepo: savingsRepo,
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				caller:       model.Principal{AccountID: "any-uuid-1"},
+				historyInput: SavingsHistoryInput{AccountID: "any-uuid-1", From: to, To: from},
+			},
+			wantErr: ErrSavingsHistoryPeriodInvalid,
+		},
+		{
+			name: "account not found should return error",
+			fields: fields{
+				accountRepo: mock.AccountRepository{
+					OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+				savingsRepo: savingsRepo,
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				caller:       model.Principal{AccountID: "any-uuid-1"},
+				historyInput: SavingsHistoryInput{AccountID: "any-uuid-1", From: from, To: to},
+			},
+			wantErr: repository.ErrAccountNotFound,
+		},
+		{
+			name: "repository error should return generic error",
+			fields: fields{
+				accountRepo: existingAccountRepo,
+				savingsRepo: mock.SavingsRepository{
+					OnFetchAccruals: func(ctx context.Context, accountID model.AccountID, from, to time.Time) ([]model.SavingsInterestAccrual, error) {
+						return nil, errors.New("any database error")
+					},
+				},
+			},
+			args: args{
+				ctx:          backgroundCtx,
+				caller:       model.Principal{AccountID: "any-uuid-1"},
+				historyInput: SavingsHistoryInput{AccountID: "any-uuid-1", From: from, To: to},
+			},
+			wantErr: ErrSavingsGetHistory,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			savUC := NewSavingsUseCase(tt.fields.savingsRepo, tt.fields.accountRepo, mock.LedgerRepository{}, SavingsPolicy{AnnualRate: 1365})
+			got, err := savUC.GetHistory(tt.args.ctx, tt.args.caller, tt.args.historyInput)
+			if err != tt.wantErr {
+				t.Errorf("GetHistory() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetHistory() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_savingsUseCase_GetHistory_defaultPeriod(t *testing.T) {
+	t.Parallel()
+
+	var gotFrom, gotTo time.Time
+	savUC := NewSavingsUseCase(
+		mock.SavingsRepository{
+			OnFetchAccruals: func(ctx context.Context, accountID model.AccountID, from, to time.Time) ([]model.SavingsInterestAccrual, error) {
+				gotFrom, gotTo = from, to
+				return nil, nil
+			},
+			OnFetchPayments: func(ctx context.Context, accountID model.AccountID, from, to time.Time) ([]model.SavingsInterestPayment, error) {
+				return nil, nil
+			},
+		},
+		mock.AccountRepository{
+			OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+				return &model.Account{ID: id, Status: model.AccountStatusActive}, nil
+			},
+		},
+		mock.LedgerRepository{},
+		SavingsPolicy{AnnualRate: 1365},
+	)
+
+	got, err := savUC.GetHistory(context.Background(), model.Principal{AccountID: "any-uuid-1"}, SavingsHistoryInput{AccountID: "any-uuid-1"})
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	wantFrom := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if !gotFrom.Equal(wantFrom) || !gotTo.Equal(today.AddDate(0, 0, 1)) {
+		t.Errorf("GetHistory() fetched [%v, %v), want the days of the month so far", gotFrom, gotTo)
+	}
+	if got.From != wantFrom.Format("2006-01-02") || got.To != today.Format("2006-01-02") || len(got.Accruals) != 0 || len(got.Payments) != 0 {
+		t.Errorf("GetHistory() got = %v, want the month so far and no accruals nor payments", got)
+	}
+}
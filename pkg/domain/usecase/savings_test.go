@@ -0,0 +1,448 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func TestSavingsPolicy_today(t *testing.T) {
+	t.Parallel()
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		now      time.Time
+		want     time.Time
+	}{
+		{
+			name: "nil location should be UTC",
+			now:  time.Date(2021, 3, 15, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2021, 3, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "should be the day in the location",
+			location: saoPaulo,
+			now:      time.Date(2021, 3, 15, 2, 0, 0, 0, time.UTC),
+			want:     time.Date(2021, 3, 14, 0, 0, 0, 0, saoPaulo),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := SavingsPolicy{Location: tt.location}
+			if got := policy.today(tt.now); !got.Equal(tt.want) {
+				t.Errorf("today() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSavingsPolicy_isBusinessDay(t *testing.T) {
+	t.Parallel()
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	policy := SavingsPolicy{
+		Holidays: []time.Time{time.Date(2021, 4, 21, 0, 0, 0, 0, time.UTC)},
+		Location: saoPaulo,
+	}
+
+	tests := []struct {
+		name string
+		day  time.Time
+		want bool
+	}{
+		{name: "weekday", day: time.Date(2021, 4, 20, 0, 0, 0, 0, saoPaulo), want: true},
+		{name: "holiday", day: time.Date(2021, 4, 21, 0, 0, 0, 0, saoPaulo), want: false},
+		{name: "saturday", day: time.Date(2021, 4, 24, 0, 0, 0, 0, saoPaulo), want: false},
+		{name: "sunday", day: time.Date(2021, 4, 25, 0, 0, 0, 0, saoPaulo), want: false},
+		{name: "holiday of another year", day: time.Date(2022, 4, 21, 0, 0, 0, 0, saoPaulo), want: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := policy.isBusinessDay(tt.day); got != tt.want {
+				t.Errorf("isBusinessDay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// noAccrualPolicy returns a SavingsPolicy where yesterday is a holiday, so ExecuteDue only pays the interest.
+func noAccrualPolicy(treasuryAccountID model.AccountID, annualRate model.InterestRate) SavingsPolicy {
+	return SavingsPolicy{
+		TreasuryAccountID: treasuryAccountID,
+		AnnualRate:        annualRate,
+		Holidays:          []time.Time{time.Now().UTC().AddDate(0, 0, -1)},
+		Location:          time.UTC,
+	}
+}
+
+func Test_savingsUseCase_ExecuteDue(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	treasury := &model.Account{ID: "treasury-uuid", Currency: model.CurrencyBRL, Status: model.AccountStatusActive}
+	getTreasury := func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+		if id != treasury.ID {
+			return nil, repository.ErrAccountNotFound
+		}
+		return treasury, nil
+	}
+	accRepo := mock.AccountRepository{
+		OnGetBalance:          getTreasury,
+		OnGetBalanceForUpdate: getTreasury,
+	}
+	// dueAccounts returns the accounts not paid yet, then none
+	dueAccounts := func(paid *[]model.AccountID, ids ...model.AccountID) func(ctx context.Context, filter model.SavingsPaymentFilter) (*model.Account, error) {
+		return func(ctx context.Context, filter model.SavingsPaymentFilter) (*model.Account, error) {
+			if filter.Currency != model.CurrencyBRL || filter.TreasuryAccountID != treasury.ID || !filter.MonthEnd.Equal(filter.Month.AddDate(0, 1, 0)) {
+				return nil, errors.New("should filter by the treasury account and the month ended")
+			}
+			if len(*paid) < len(ids) {
+				return &model.Account{ID: ids[len(*paid)], Currency: model.CurrencyBRL, Status: model.AccountStatusActive}, nil
+			}
+			return nil, nil
+		}
+	}
+	accrualsCalled := func(ctx context.Context, filter model.SavingsAccrualFilter) (*model.Account, error) {
+		return nil, errors.New("should not accrue the interest of a holiday")
+	}
+
+	tests := []struct {
+		name       string
+		policy     SavingsPolicy
+		dueIDs     []model.AccountID
+		limit      int
+		want       int
+		wantErr    bool
+		wantPosted []model.AccountID
+	}{
+		{
+			name:   "no treasury account should process none",
+			policy: noAccrualPolicy("", 1365),
+			dueIDs: []model.AccountID{"uuid-1"},
+			limit:  10,
+			want:   0,
+		},
+		{
+			name:   "zero rate should process none",
+			policy: noAccrualPolicy(treasury.ID, 0),
+			dueIDs: []model.AccountID{"uuid-1"},
+			limit:  10,
+			want:   0,
+		},
+		{
+			name:       "due accounts should be paid from the treasury account",
+			policy:     noAccrualPolicy(treasury.ID, 1365),
+			dueIDs:     []model.AccountID{"uuid-1", "uuid-2"},
+			limit:      10,
+			want:       2,
+			wantPosted: []model.AccountID{"uuid-1", "uuid-2"},
+		},
+		{
+			name:       "should stop at the limit",
+			policy:     noAccrualPolicy(treasury.ID, 1365),
+			dueIDs:     []model.AccountID{"uuid-1", "uuid-2", "uuid-3"},
+			limit:      2,
+			want:       2,
+			wantPosted: []model.AccountID{"uuid-1", "uuid-2"},
+		},
+		{
+			name:    "treasury account not found should return error",
+			policy:  noAccrualPolicy("uuid-9", 1365),
+			dueIDs:  []model.AccountID{"uuid-1"},
+			limit:   10,
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var paid, posted []model.AccountID
+			savRepo := mock.SavingsRepository{
+				OnWithinTransaction:  withinTransaction,
+				OnLockNextAccrualDue: accrualsCalled,
+				OnLockNextPaymentDue: dueAccounts(&paid, tt.dueIDs...),
+				OnGetTotals: func(ctx context.Context, accountID model.AccountID, end time.Time) (*model.SavingsInterestTotals, error) {
+					return &model.SavingsInterestTotals{Accrued: 10_150_000_000, Paid: 100}, nil
+				},
+				OnCreatePayment: func(ctx context.Context, payment *model.SavingsInterestPayment) error {
+					if payment.Amount != 1 {
+						return errors.New("should pay the whole cents not paid yet")
+					}
+					paid = append(paid, payment.AccountID)
+					return nil
+				},
+			}
+			ledgerRepo := mock.LedgerRepository{
+				OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+					if posting.Kind != model.LedgerPostingSavingsInterest || posting.DebitAccountID != treasury.ID || posting.Amount != 1 {
+						return errors.New("should debit the interest from the treasury account")
+					}
+					posted = append(posted, posting.CreditAccountID)
+					return nil
+				},
+			}
+			savUC := NewSavingsUseCase(savRepo, accRepo, ledgerRepo, tt.policy)
+
+			got, err := savUC.ExecuteDue(backgroundCtx, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecuteDue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want || len(paid) != tt.want {
+				t.Errorf("ExecuteDue() got = %v and paid %v, want %v", got, len(paid), tt.want)
+			}
+			if len(posted) != len(tt.wantPosted) {
+				t.Fatalf("ExecuteDue() posted = %v, want %v", posted, tt.wantPosted)
+			}
+			for i := range posted {
+				if posted[i] != tt.wantPosted[i] {
+					t.Errorf("ExecuteDue() posted = %v, want %v", posted, tt.wantPosted)
+				}
+			}
+		})
+	}
+}
+
+func Test_savingsUseCase_accrueNextDue(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	day := time.Date(2021, 3, 15, 0, 0, 0, 0, time.UTC)
+	filter := model.SavingsAccrualFilter{
+		Day:               day,
+		DayEnd:            day.AddDate(0, 0, 1),
+		Currency:          model.CurrencyBRL,
+		TreasuryAccountID: "treasury-uuid",
+	}
+
+	tests := []struct {
+		name         string
+		account      *model.Account
+		closing      model.Money
+		balanceErr   error
+		createErr    error
+		wantAccrual  *model.SavingsInterestAccrual
+		wantErr      bool
+		wantAccruals int
+	}{
+		{
+			name:         "should accrue on the closing balance of the day",
+			account:      &model.Account{ID: "uuid-1", Balance: 2000000},
+			closing:      1000000,
+			wantAccrual:  &model.SavingsInterestAccrual{AccountID: "uuid-1", Day: day, Balance: 1000000, Rate: 50788, Amount: 50788000000},
+			wantAccruals: 1,
+		},
+		{
+			name:         "not positive closing balance should accrue nothing",
+			account:      &model.Account{ID: "uuid-1", Balance: 2000000},
+			closing:      -500,
+			wantAccrual:  &model.SavingsInterestAccrual{AccountID: "uuid-1", Day: day, Balance: -500, Rate: 50788, Amount: 0},
+			wantAccruals: 1,
+		},
+		{
+			name:    "no account due should return nil",
+			account: nil,
+		},
+		{
+			name:       "ledger error should return error",
+			account:    &model.Account{ID: "uuid-1", Balance: 2000000},
+			balanceErr: errors.New("any database error"),
+			wantErr:    true,
+		},
+		{
+			name:      "create error should return error",
+			account:   &model.Account{ID: "uuid-1", Balance: 2000000},
+			closing:   1000000,
+			createErr: errors.New("duplicate key value violates unique constraint"),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			accruals := 0
+			savRepo := mock.SavingsRepository{
+				OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+					return txFunc(ctx)
+				},
+				OnLockNextAccrualDue: func(ctx context.Context, f model.SavingsAccrualFilter) (*model.Account, error) {
+					if f != filter {
+						return nil, errors.New("should pass the filter")
+					}
+					return tt.account, nil
+				},
+				OnCreateAccrual: func(ctx context.Context, accrual *model.SavingsInterestAccrual) error {
+					if tt.createErr != nil {
+						return tt.createErr
+					}
+					accruals++
+					return nil
+				},
+			}
+			ledgerRepo := mock.LedgerRepository{
+				OnGetBalanceAt: func(ctx context.Context, accountID model.AccountID, currency model.Currency, at time.Time) (model.Money, error) {
+					if !at.Equal(filter.DayEnd) {
+						return 0, errors.New("should get the balance at the end of the day")
+					}
+					return tt.closing, tt.balanceErr
+				},
+			}
+			savUC := savingsUseCase{savRepo: savRepo, ledgerRepo: ledgerRepo}
+
+			got, err := savUC.accrueNextDue(backgroundCtx, filter, 50788)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("accrueNextDue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if accruals != tt.wantAccruals {
+				t.Errorf("accrueNextDue() accruals = %v, want %v", accruals, tt.wantAccruals)
+			}
+			if tt.wantAccrual == nil {
+				if got != nil {
+					t.Errorf("accrueNextDue() got = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.AccountID != tt.wantAccrual.AccountID || !got.Day.Equal(tt.wantAccrual.Day) ||
+				got.Balance != tt.wantAccrual.Balance || got.Rate != tt.wantAccrual.Rate || got.Amount != tt.wantAccrual.Amount {
+				t.Errorf("accrueNextDue() got = %v, want %v", got, tt.wantAccrual)
+			}
+		})
+	}
+}
+
+func Test_savingsUseCase_payNextDue(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	month := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	filter := model.SavingsPaymentFilter{
+		Month:             month,
+		MonthEnd:          month.AddDate(0, 1, 0),
+		Currency:          model.CurrencyBRL,
+		TreasuryAccountID: "treasury-uuid",
+	}
+
+	tests := []struct {
+		name       string
+		totals     *model.SavingsInterestTotals
+		postErr    error
+		wantAmount model.Money
+		wantPosted bool
+		wantErr    bool
+	}{
+		{
+			name:       "should pay the whole cents and carry the fraction",
+			totals:     &model.SavingsInterestTotals{Accrued: 10_199_999_999, Paid: 100},
+			wantAmount: 1,
+			wantPosted: true,
+		},
+		{
+			name:       "less than a cent should be recorded and not posted",
+			totals:     &model.SavingsInterestTotals{Accrued: 10_099_999_999, Paid: 100},
+			wantAmount: 0,
+			wantPosted: false,
+		},
+		{
+			name:    "ledger error should return error",
+			totals:  &model.SavingsInterestTotals{Accrued: 10_199_999_999, Paid: 100},
+			postErr: errors.New("any database error"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var locked []model.AccountID
+			posted := false
+			accRepo := mock.AccountRepository{
+				OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+					locked = append(locked, id)
+					return &model.Account{ID: id, Currency: model.CurrencyBRL, Status: model.AccountStatusActive}, nil
+				},
+			}
+			savRepo := mock.SavingsRepository{
+				OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+					return txFunc(ctx)
+				},
+				OnLockNextPaymentDue: func(ctx context.Context, f model.SavingsPaymentFilter) (*model.Account, error) {
+					if len(locked) != 1 || locked[0] != "treasury-uuid" {
+						return nil, errors.New("should lock the treasury account first")
+					}
+					return &model.Account{ID: "uuid-1", Currency: model.CurrencyBRL, Status: model.AccountStatusActive}, nil
+				},
+				OnGetTotals: func(ctx context.Context, accountID model.AccountID, end time.Time) (*model.SavingsInterestTotals, error) {
+					if accountID != "uuid-1" || !end.Equal(filter.MonthEnd) {
+						return nil, errors.New("should sum the accruals until the month ended")
+					}
+					return tt.totals, nil
+				},
+				OnCreatePayment: func(ctx context.Context, payment *model.SavingsInterestPayment) error {
+					return nil
+				},
+			}
+			ledgerRepo := mock.LedgerRepository{
+				OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+					if tt.postErr != nil {
+						return tt.postErr
+					}
+					if posting.DebitAccountID != "treasury-uuid" || posting.CreditAccountID != "uuid-1" {
+						return errors.New("should credit the interest from the treasury account")
+					}
+					posted = true
+					return nil
+				},
+			}
+			savUC := savingsUseCase{savRepo: savRepo, accRepo: accRepo, ledgerRepo: ledgerRepo}
+
+			got, err := savUC.payNextDue(backgroundCtx, filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("payNextDue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got == nil || got.AccountID != "uuid-1" || !got.Month.Equal(month) || got.Amount != tt.wantAmount {
+				t.Errorf("payNextDue() got = %v, want %v of the month", got, tt.wantAmount)
+			}
+			if posted != tt.wantPosted {
+				t.Errorf("payNextDue() posted = %v, want %v", posted, tt.wantPosted)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// ScheduledTransferUseCase is the interface that wraps all business logic methods related to the scheduled transfers.
+type ScheduledTransferUseCase interface {
+	Create(ctx context.Context, scheduleInput ScheduledTransferCreateInput) (*ScheduledTransferOutput, error)
+	Fetch(ctx context.Context, originID model.AccountID, status string) ([]ScheduledTransferOutput, error)
+	Cancel(ctx context.Context, caller model.Principal, id model.ScheduledTransferID) (*ScheduledTransferOutput, error)
+	ExecuteDue(ctx context.Context, limit int) (int, error)
+}
+
+type scheduledTransferUseCase struct {
+	schRepo repository.ScheduledTransferRepository
+	accRepo repository.AccountRepository
+	trfUC   transferUseCase
+}
+
+// NewScheduledTransferUseCase instantiates a new ScheduledTransferUseCase.
+// The due schedules are executed with the same logic of TransferUseCase.Create.
+func NewScheduledTransferUseCase(
+	schRepo repository.ScheduledTransferRepository,
+	trfRepo repository.TransferRepository,
+	accRepo repository.AccountRepository,
+	ledgerRepo repository.LedgerRepository,
+	limitRepo repository.TransferLimitRepository,
+	limitPolicy TransferLimitPolicy,
+	feePolicy FeePolicy,
+) ScheduledTransferUseCase {
+	return &scheduledTransferUseCase{
+		schRepo: schRepo,
+		accRepo: accRepo,
+		trfUC: transferUseCase{
+			trfRepo:     trfRepo,
+			accRepo:     accRepo,
+			ledgerRepo:  ledgerRepo,
+			limitRepo:   limitRepo,
+			limitPolicy: limitPolicy,
+			feePolicy:   feePolicy,
+		},
+	}
+}
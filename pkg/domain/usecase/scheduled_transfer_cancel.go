@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrScheduledTransferNotPending happens when cancelling a schedule already executed, failed or cancelled.
+	ErrScheduledTransferNotPending = errors.New("scheduled transfer was already processed or cancelled")
+	// ErrScheduledTransferCancel happens when an error occurred and the scheduled transfer was not cancelled.
+	ErrScheduledTransferCancel = errors.New("could not cancel scheduled transfer")
+)
+
+// Cancel cancels a pending schedule of the caller account.
+// The schedules of other accounts are reported as repository.ErrScheduledTransferNotFound, so their IDs are not disclosed.
+func (schUC scheduledTransferUseCase) Cancel(ctx context.Context, caller model.Principal, id model.ScheduledTransferID) (*ScheduledTransferOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if _, err := uuid.Parse(string(id)); err != nil {
+		return nil, repository.ErrScheduledTransferNotFound
+	}
+
+	// the row lock waits for an executor running the schedule, so it's never cancelled after executed
+	data, err := schUC.schRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		schedule, err := schUC.schRepo.GetByIDForUpdate(txCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !caller.Owns(schedule.AccountOriginID) {
+			return nil, repository.ErrScheduledTransferNotFound
+		}
+		if !schedule.IsPending() {
+			return nil, ErrScheduledTransferNotPending
+		}
+
+		schedule.Cancelled()
+
+		return schedule, schUC.schRepo.UpdateStatus(txCtx, schedule)
+	})
+	if err != nil {
+		if err == repository.ErrScheduledTransferNotFound || err == ErrScheduledTransferNotPending {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("id", string(id)).Msg("error cancelling scheduled transfer")
+		return nil, ErrScheduledTransferCancel
+	}
+
+	schedule, _ := data.(*model.ScheduledTransfer)
+	log.Ctx(ctx).Info().Str("id", string(schedule.ID)).Str("by", string(caller.AccountID)).Msg("scheduled transfer cancelled")
+
+	output := newScheduledTransferOutput(schedule)
+	return &output, nil
+}
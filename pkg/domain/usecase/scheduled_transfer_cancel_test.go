@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_scheduledTransferUseCase_Cancel(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	caller := model.Principal{AccountID: "uuid-1"}
+	scheduleID := model.ScheduledTransferID("0c8b5e3c-6b6e-4a4f-9d3e-6a0f3b8b2a11")
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	scheduleWith := func(originID model.AccountID, status model.ScheduledTransferStatus) func(ctx context.Context, id model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+		return func(ctx context.Context, id model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+			return &model.ScheduledTransfer{ID: id, AccountOriginID: originID, AccountDestinationID: "uuid-2", Amount: 100, Status: status}, nil
+		}
+	}
+	updateStatusOK := func(ctx context.Context, schedule *model.ScheduledTransfer) error {
+		return nil
+	}
+
+	type fields struct {
+		schRepo repository.ScheduledTransferRepository
+	}
+	type args struct {
+		ctx    context.Context
+		caller model.Principal
+		id     model.ScheduledTransferID
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus string
+		wantErr    error
+	}{
+		{
+			name:   "id not uuid should return not found error",
+			fields: fields{},
+			args: args{
+				ctx:    backgroundCtx,
+				caller: caller,
+				id:     "any-id",
+			},
+			wantErr: repository.ErrScheduledTransferNotFound,
+		},
+		{
+			name: "not found schedule should return not found error",
+			fields: fields{
+				schRepo: mock.ScheduledTransferRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByIDForUpdate: func(ctx context.Context, id model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+						return nil, repository.ErrScheduledTransferNotFound
+					},
+				},
+			},
+			args: args{
+				ctx:    backgroundCtx,
+				caller: caller,
+				id:     scheduleID,
+			},
+			wantErr: repository.ErrScheduledTransferNotFound,
+		},
+		{
+			name: "schedule of another account should return not found error",
+			fields: fields{
+				schRepo: mock.ScheduledTransferRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByIDForUpdate:  scheduleWith("uuid-3", model.ScheduledTransferStatusScheduled),
+				},
+			},
+			args: args{
+				ctx:    backgroundCtx,
+				caller: caller,
+				id:     scheduleID,
+			},
+			wantErr: repository.ErrScheduledTransferNotFound,
+		},
+		{
+			name: "executed schedule should return not pending error",
+			fields: fields{
+				schRepo: mock.ScheduledTransferRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByIDForUpdate:  scheduleWith("uuid-1", model.ScheduledTransferStatusExecuted),
+				},
+			},
+			args: args{
+				ctx:    backgroundCtx,
+				caller: caller,
+				id:     scheduleID,
+			},
+			wantErr: ErrScheduledTransferNotPending,
+		},
+		{
+			name: "repo update error should return error",
+			fields: fields{
+				schRepo: mock.ScheduledTransferRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByIDForUpdate:  scheduleWith("uuid-1", model.ScheduledTransferStatusScheduled),
+					OnUpdateStatus: func(ctx context.Context, schedule *model.ScheduledTransfer) error {
+						return errors.New("any database error")
+					},
+				},
+			},
+			args: args{
+				ctx:    backgroundCtx,
+				caller: caller,
+				id:     scheduleID,
+			},
+			wantErr: ErrScheduledTransferCancel,
+		},
+		{
+			name: "pending schedule of the caller should be cancelled",
+			fields: fields{
+				schRepo: mock.ScheduledTransferRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByIDForUpdate:  scheduleWith("uuid-1", model.ScheduledTransferStatusScheduled),
+					OnUpdateStatus:      updateStatusOK,
+				},
+			},
+			args: args{
+				ctx:    backgroundCtx,
+				caller: caller,
+				id:     scheduleID,
+			},
+			wantStatus: "cancelled",
+			wantErr:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schUC := NewScheduledTransferUseCase(tt.fields.schRepo, nil, nil, nil, nil, TransferLimitPolicy{}, FeePolicy{})
+
+			got, err := schUC.Cancel(tt.args.ctx, tt.args.caller, tt.args.id)
+			if err != tt.wantErr {
+				t.Errorf("Cancel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if got.ID != string(tt.args.id) || got.Status != tt.wantStatus || got.ProcessedAt == nil {
+				t.Errorf("Cancel() got = %v, want status %v and processed", got, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// ScheduledTransferMaxAdvance is how far in the future a transfer can be scheduled.
+const ScheduledTransferMaxAdvance = 366 * 24 * time.Hour
+
+var (
+	// ErrScheduledTransferDateInvalid happens when the schedule date is not in the future or is too far.
+	ErrScheduledTransferDateInvalid = errors.New("'scheduled_for' must be in the future, up to one year ahead")
+	// ErrScheduledTransferCreate happens when an error occurred and the scheduled transfer was not created.
+	ErrScheduledTransferCreate = errors.New("could not create scheduled transfer")
+)
+
+// ScheduledTransferCreateInput represents the expected input data when scheduling a transfer.
+type ScheduledTransferCreateInput struct {
+	AccountOriginID      string    `json:"-"`
+	AccountDestinationID string    `json:"account_destination_id" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
+	Amount               Amount    `json:"amount" swaggertype:"number" example:"9999.99"`
+	ScheduledFor         time.Time `json:"scheduled_for" example:"2021-01-31T09:00:00-03:00"`
+}
+
+// Validate validates the ScheduledTransferCreateInput fields.
+func (input *ScheduledTransferCreateInput) Validate() error {
+	input.AccountOriginID = strings.TrimSpace(input.AccountOriginID)
+	if len(input.AccountOriginID) < 1 {
+		return ErrTransferOriginAccountRequired
+	}
+
+	input.AccountDestinationID = strings.TrimSpace(input.AccountDestinationID)
+	if len(input.AccountDestinationID) < 1 {
+		return ErrTransferDestinationAccountRequired
+	}
+
+	if input.Amount.Money <= 0 {
+		return ErrTransferAmountNotPositive
+	}
+
+	if input.AccountOriginID == input.AccountDestinationID {
+		return ErrTransferSameAccount
+	}
+
+	now := time.Now()
+	if !input.ScheduledFor.After(now) || input.ScheduledFor.After(now.Add(ScheduledTransferMaxAdvance)) {
+		return ErrScheduledTransferDateInvalid
+	}
+
+	return nil
+}
+
+// ScheduledTransferOutput represents a scheduled transfer with its outcome, if processed.
+type ScheduledTransferOutput struct {
+	ID                   string     `json:"id" example:"0c8b5e3c-6b6e-4a4f-9d3e-6a0f3b8b2a11"`
+	AccountOriginID      string     `json:"account_origin_id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	AccountDestinationID string     `json:"account_destination_id" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
+	Amount               Amount     `json:"amount" swaggertype:"number" example:"9999.99"`
+	ScheduledFor         time.Time  `json:"scheduled_for" example:"2021-01-31T09:00:00-03:00"`
+	Status               string     `json:"status" example:"executed" enums:"scheduled,executed,failed,cancelled"`
+	TransferID           string     `json:"transfer_id,omitempty" example:"e82706ef-9ffb-45a2-8081-547accd818c4"`
+	FailureReason        string     `json:"failure_reason,omitempty" example:"current account balance is insufficient"`
+	CreatedAt            time.Time  `json:"created_at" example:"2020-12-31T23:59:59.999999-03:00"`
+	ProcessedAt          *time.Time `json:"processed_at,omitempty" example:"2021-01-31T09:00:01.999999-03:00"`
+}
+
+func newScheduledTransferOutput(schedule *model.ScheduledTransfer) ScheduledTransferOutput {
+	output := ScheduledTransferOutput{
+		ID:                   string(schedule.ID),
+		AccountOriginID:      string(schedule.AccountOriginID),
+		AccountDestinationID: string(schedule.AccountDestinationID),
+		Amount:               NewAmountIn(schedule.Amount, schedule.Currency),
+		ScheduledFor:         schedule.ScheduledFor,
+		Status:               string(schedule.Status),
+		TransferID:           string(schedule.TransferID),
+		FailureReason:        schedule.FailureReason,
+		CreatedAt:            schedule.CreatedAt,
+	}
+	if !schedule.ProcessedAt.IsZero() {
+		processedAt := schedule.ProcessedAt
+		output.ProcessedAt = &processedAt
+	}
+
+	return output
+}
+
+// Create validates the input and saves the schedule, to be executed by ExecuteDue at its date.
+// Both accounts must be active when scheduling, but the balance is only checked at the execution.
+func (schUC scheduledTransferUseCase) Create(ctx context.Context, scheduleInput ScheduledTransferCreateInput) (*ScheduledTransferOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := scheduleInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", scheduleInput).Msg("scheduled transfer create input is not valid")
+		return nil, err
+	}
+
+	schedule := model.NewScheduledTransfer(
+		model.AccountID(scheduleInput.AccountOriginID),
+		model.AccountID(scheduleInput.AccountDestinationID),
+		scheduleInput.Amount.Money,
+		scheduleInput.ScheduledFor)
+
+	schedule.Currency, err = ensureActiveAccounts(ctx, schUC.accRepo, schedule.AccountOriginID, schedule.AccountDestinationID)
+	if err != nil {
+		switch err {
+		case repository.ErrAccountNotFound, ErrTransferOriginAccountNotActive, ErrTransferDestinationAccountNotActive,
+			ErrTransferCurrencyMismatch:
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("schedule", schedule).Msg("error getting scheduled transfer accounts")
+		return nil, ErrScheduledTransferCreate
+	}
+
+	schedule.Amount, err = scheduleInput.Amount.In(schedule.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schUC.schRepo.Create(ctx, schedule)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("schedule", schedule).Msg("error persisting new scheduled transfer")
+		return nil, ErrScheduledTransferCreate
+	}
+
+	output := newScheduledTransferOutput(schedule)
+	return &output, nil
+}
+
+// ensureActiveAccounts checks both accounts exist, are active and have the same currency, without locking them,
+// and returns their currency. The transfers executed later have no FX quote, so they can't convert the amount.
+func ensureActiveAccounts(ctx context.Context, accRepo repository.AccountRepository, originID, destinationID model.AccountID) (model.Currency, error) {
+	originAccount, err := accRepo.GetBalance(ctx, originID)
+	if err != nil {
+		return "", err
+	}
+	if !originAccount.IsActive() {
+		return "", ErrTransferOriginAccountNotActive
+	}
+
+	destinationAccount, err := accRepo.GetBalance(ctx, destinationID)
+	if err != nil {
+		return "", err
+	}
+	if !destinationAccount.IsActive() {
+		return "", ErrTransferDestinationAccountNotActive
+	}
+	if originAccount.Currency != destinationAccount.Currency {
+		return "", ErrTransferCurrencyMismatch
+	}
+
+	return originAccount.Currency, nil
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_scheduledTransferUseCase_Create(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	accountsWithStatus := func(statuses map[model.AccountID]model.AccountStatus) func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+		return func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			status, ok := statuses[id]
+			if !ok {
+				return nil, repository.ErrAccountNotFound
+			}
+			return &model.Account{ID: id, Status: status}, nil
+		}
+	}
+	bothActive := accountsWithStatus(map[model.AccountID]model.AccountStatus{"uuid-1": model.AccountStatusActive, "uuid-2": model.AccountStatusActive})
+
+	type fields struct {
+		schRepo repository.ScheduledTransferRepository
+		accRepo repository.AccountRepository
+	}
+	type args struct {
+		ctx           context.Context
+		scheduleInput ScheduledTransferCreateInput
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *ScheduledTransferOutput
+		wantErr error
+	}{
+		{
+			name:   "same account should return error",
+			fields: fields{},
+			args: args{
+				ctx:           backgroundCtx,
+				scheduleInput: ScheduledTransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-1", Amount: NewAmount(100), ScheduledFor: tomorrow},
+			},
+			wantErr: ErrTransferSameAccount,
+		},
+		{
+			name:   "not positive amount should return error",
+			fields: fields{},
+			args: args{
+				ctx:           backgroundCtx,
+				scheduleInput: ScheduledTransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(0), ScheduledFor: tomorrow},
+			},
+			wantErr: ErrTransferAmountNotPositive,
+		},
+		{
+			name:   "past date should return date invalid error",
+			fields: fields{},
+			args: args{
+				ctx:           backgroundCtx,
+				scheduleInput: ScheduledTransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(100), ScheduledFor: time.Now().Add(-time.Minute)},
+			},
+			wantErr: ErrScheduledTransferDateInvalid,
+		},
+		{
+			name:   "date over one year ahead should return date invalid error",
+			fields: fields{},
+			args: args{
+				ctx:           backgroundCtx,
+				scheduleInput: ScheduledTransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(100), ScheduledFor: time.Now().Add(ScheduledTransferMaxAdvance + time.Hour)},
+			},
+			wantErr: ErrScheduledTransferDateInvalid,
+		},
+		{
+			name: "destination not found should return error",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalance: accountsWithStatus(map[model.AccountID]model.AccountStatus{"uuid-1": model.AccountStatusActive}),
+				},
+			},
+			args: args{
+				ctx:           backgroundCtx,
+				scheduleInput: ScheduledTransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(100), ScheduledFor: tomorrow},
+			},
+			wantErr: repository.ErrAccountNotFound,
+		},
+		{
+			name: "blocked destination should return error",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalance: accountsWithStatus(map[model.AccountID]model.AccountStatus{"uuid-1": model.AccountStatusActive, "uuid-2": model.AccountStatusBlocked}),
+				},
+			},
+			args: args{
+				ctx:           backgroundCtx,
+				scheduleInput: ScheduledTransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(100), ScheduledFor: tomorrow},
+			},
+			wantErr: ErrTransferDestinationAccountNotActive,
+		},
+		{
+			name: "destination in another currency should return error",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						if id == "uuid-2" {
+							return &model.Account{ID: id, Currency: model.CurrencyUSD, Status: model.AccountStatusActive}, nil
+						}
+						return &model.Account{ID: id, Currency: model.CurrencyBRL, Status: model.AccountStatusActive}, nil
+					},
+				},
+			},
+			args: args{
+				ctx:           backgroundCtx,
+				scheduleInput: ScheduledTransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(100), ScheduledFor: tomorrow},
+			},
+			wantErr: ErrTransferCurrencyMismatch,
+		},
+		{
+			name: "repo create error should return error",
+			fields: fields{
+				schRepo: mock.ScheduledTransferRepository{
+					OnCreate: func(ctx context.Context, schedule *model.ScheduledTransfer) error {
+						return errors.New("any database error")
+					},
+				},
+				accRepo: mock.AccountRepository{OnGetBalance: bothActive},
+			},
+			args: args{
+				ctx:           backgroundCtx,
+				scheduleInput: ScheduledTransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(100), ScheduledFor: tomorrow},
+			},
+			wantErr: ErrScheduledTransferCreate,
+		},
+		{
+			name: "success",
+			fields: fields{
+				schRepo: mock.ScheduledTransferRepository{
+					OnCreate: func(ctx context.Context, schedule *model.ScheduledTransfer) error {
+						return nil
+					},
+				},
+				accRepo: mock.AccountRepository{OnGetBalance: bothActive},
+			},
+			args: args{
+				ctx:           backgroundCtx,
+				scheduleInput: ScheduledTransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: " uuid-2 ", Amount: NewAmount(100), ScheduledFor: tomorrow},
+			},
+			want: &ScheduledTransferOutput{
+				AccountOriginID:      "uuid-1",
+				AccountDestinationID: "uuid-2",
+				Amount:               NewAmount(100),
+				ScheduledFor:         tomorrow,
+				Status:               "scheduled",
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schUC := NewScheduledTransferUseCase(tt.fields.schRepo, nil, tt.fields.accRepo, nil, nil, TransferLimitPolicy{}, FeePolicy{})
+
+			got, err := schUC.Create(tt.args.ctx, tt.args.scheduleInput)
+			if err != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("Create() got = %v, want nil", got)
+				}
+				return
+			}
+
+			if len(got.ID) <= 0 || got.CreatedAt.IsZero() {
+				t.Errorf("Create() got = %v, ID and CreatedAt should not be empty", got)
+			}
+			got.ID = ""
+			got.CreatedAt = time.Time{}
+			if *got != *tt.want {
+				t.Errorf("Create() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
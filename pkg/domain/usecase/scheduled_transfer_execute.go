@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/infraestructure/monitoring"
+)
+
+// ExecuteDue executes up to limit due schedules, oldest first, returning how many were processed.
+//
+// Each schedule is executed in its own transaction, holding its row lock, so it's safe to run on multiple replicas.
+// When the transfer is rejected, like for insufficient balance, the schedule fails with the reason.
+// On any other error, the schedule is kept pending to be retried on the next call and skipped for the rest of this
+// one, so it doesn't hold back the schedules due after it.
+func (schUC scheduledTransferUseCase) ExecuteDue(ctx context.Context, limit int) (int, error) {
+	var skipped []model.ScheduledTransferID
+
+	processed := 0
+	for processed < limit {
+		schedule, err := schUC.executeNextDue(ctx, skipped)
+		if err != nil {
+			if schedule == nil {
+				return processed, err
+			}
+
+			skipped = append(skipped, schedule.ID)
+			log.Ctx(ctx).Warn().Str("id", string(schedule.ID)).Msg("scheduled transfer skipped until the next call")
+			continue
+		}
+		if schedule == nil {
+			break
+		}
+
+		processed++
+		monitoring.ScheduledTransfersProcessed.WithLabelValues(string(schedule.Status)).Inc()
+		log.Ctx(ctx).Info().Str("id", string(schedule.ID)).Str("status", string(schedule.Status)).Str("transferID", string(schedule.TransferID)).
+			Str("reason", schedule.FailureReason).Msg("scheduled transfer processed")
+	}
+
+	return processed, nil
+}
+
+// executeNextDue executes the next due schedule not skipped, returning it with its outcome, or nil if there's none.
+// When the schedule was locked but couldn't be executed, it's returned along with the error.
+func (schUC scheduledTransferUseCase) executeNextDue(ctx context.Context, skip []model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var schedule *model.ScheduledTransfer
+	_, err := schUC.schRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		var err error
+		schedule, err = schUC.schRepo.LockNextDue(txCtx, time.Now(), skip)
+		if err != nil || schedule == nil {
+			return nil, err
+		}
+
+		transfer := model.NewTransfer(
+			string(schedule.AccountOriginID),
+			string(schedule.AccountDestinationID),
+			schedule.Amount)
+
+		// a rejected transfer wrote nothing, so the failure can be saved in the same transaction
+		err = schUC.trfUC.execute(txCtx, transfer)
+		switch {
+		case err == nil:
+			schedule.Executed(transfer.ID)
+		case isTransferRejection(err):
+			schedule.Failed(err.Error())
+		default:
+			return nil, err
+		}
+
+		return schedule, schUC.schRepo.UpdateStatus(txCtx, schedule)
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error executing scheduled transfer")
+		return schedule, err
+	}
+
+	return schedule, nil
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_scheduledTransferUseCase_ExecuteDue(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	// dueSchedules returns the schedules one by one, then none
+	dueSchedules := func(count int) func(ctx context.Context, now time.Time, skip []model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+		return func(ctx context.Context, now time.Time, skip []model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+			if count == 0 {
+				return nil, nil
+			}
+			count--
+			return &model.ScheduledTransfer{ID: "schedule-uuid", AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: 100, Status: model.ScheduledTransferStatusScheduled}, nil
+		}
+	}
+	accountsWithBalance := func(balance model.Money) mock.AccountRepository {
+		return mock.AccountRepository{
+			OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+				return &model.Account{ID: id, Balance: balance, Status: model.AccountStatusActive}, nil
+			},
+		}
+	}
+	ledgerRepoOK := mock.LedgerRepository{
+		OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+			return nil
+		},
+	}
+	noAccountLimits := mock.TransferLimitRepository{
+		OnGet: func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+			return &model.AccountTransferLimits{AccountID: accountID}, nil
+		},
+	}
+	trfRepoOK := mock.TransferRepository{
+		OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+			return nil
+		},
+	}
+
+	type fields struct {
+		lockNextDue func(ctx context.Context, now time.Time, skip []model.ScheduledTransferID) (*model.ScheduledTransfer, error)
+		accRepo     repository.AccountRepository
+		ledgerRepo  repository.LedgerRepository
+	}
+	tests := []struct {
+		name              string
+		fields            fields
+		limit             int
+		want              int
+		wantErr           bool
+		wantStatus        model.ScheduledTransferStatus
+		wantFailureReason string
+	}{
+		{
+			name: "no due schedule should process none",
+			fields: fields{
+				lockNextDue: dueSchedules(0),
+			},
+			limit: 10,
+			want:  0,
+		},
+		{
+			name: "enough balance should execute the transfer",
+			fields: fields{
+				lockNextDue: dueSchedules(1),
+				accRepo:     accountsWithBalance(100),
+				ledgerRepo:  ledgerRepoOK,
+			},
+			limit:      10,
+			want:       1,
+			wantStatus: model.ScheduledTransferStatusExecuted,
+		},
+		{
+			name: "insufficient balance should fail with the reason",
+			fields: fields{
+				lockNextDue: dueSchedules(1),
+				accRepo:     accountsWithBalance(99),
+			},
+			limit:             10,
+			want:              1,
+			wantStatus:        model.ScheduledTransferStatusFailed,
+			wantFailureReason: ErrAccountCurrentBalanceInsufficient.Error(),
+		},
+		{
+			name: "should stop at the limit",
+			fields: fields{
+				lockNextDue: dueSchedules(3),
+				accRepo:     accountsWithBalance(100),
+				ledgerRepo:  ledgerRepoOK,
+			},
+			limit:      2,
+			want:       2,
+			wantStatus: model.ScheduledTransferStatusExecuted,
+		},
+		{
+			name: "ledger error should keep the schedule pending",
+			fields: fields{
+				lockNextDue: dueSchedules(1),
+				accRepo:     accountsWithBalance(100),
+				ledgerRepo: mock.LedgerRepository{
+					OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+						return errors.New("any database error")
+					},
+				},
+			},
+			limit: 10,
+			want:  0,
+		},
+		{
+			name: "schedule failing with an unexpected error should be skipped and the next ones executed",
+			fields: fields{
+				lockNextDue: func() func(ctx context.Context, now time.Time, skip []model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+					next := dueSchedules(2)
+					return func(ctx context.Context, now time.Time, skip []model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+						// the poison schedule is due first and stays pending until it's skipped
+						if len(skip) == 0 {
+							return &model.ScheduledTransfer{ID: "poison-uuid", AccountOriginID: "uuid-1", AccountDestinationID: "uuid-poison", Amount: 100, Status: model.ScheduledTransferStatusScheduled}, nil
+						}
+						if len(skip) != 1 || skip[0] != "poison-uuid" {
+							return nil, errors.New("should skip the poison schedule")
+						}
+						return next(ctx, now, skip)
+					}
+				}(),
+				accRepo: accountsWithBalance(100),
+				ledgerRepo: mock.LedgerRepository{
+					OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+						if posting.CreditAccountID == "uuid-poison" {
+							return errors.New("any database error")
+						}
+						return nil
+					},
+				},
+			},
+			limit:      10,
+			want:       2,
+			wantStatus: model.ScheduledTransferStatusExecuted,
+		},
+		{
+			name: "lock error should return error",
+			fields: fields{
+				lockNextDue: func(ctx context.Context, now time.Time, skip []model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+					return nil, errors.New("any database error")
+				},
+			},
+			limit:   10,
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated []model.ScheduledTransfer
+			schRepo := mock.ScheduledTransferRepository{
+				OnWithinTransaction: withinTransaction,
+				OnLockNextDue:       tt.fields.lockNextDue,
+				OnUpdateStatus: func(ctx context.Context, schedule *model.ScheduledTransfer) error {
+					updated = append(updated, *schedule)
+					return nil
+				},
+			}
+			schUC := NewScheduledTransferUseCase(schRepo, trfRepoOK, tt.fields.accRepo, tt.fields.ledgerRepo, noAccountLimits, TransferLimitPolicy{}, FeePolicy{})
+
+			got, err := schUC.ExecuteDue(backgroundCtx, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecuteDue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want || len(updated) != tt.want {
+				t.Errorf("ExecuteDue() got = %v and updated %v, want %v", got, len(updated), tt.want)
+			}
+
+			for _, schedule := range updated {
+				if schedule.Status != tt.wantStatus || schedule.FailureReason != tt.wantFailureReason {
+					t.Errorf("ExecuteDue() updated = %v, want status %v and failure reason %q", schedule, tt.wantStatus, tt.wantFailureReason)
+				}
+				if (schedule.Status == model.ScheduledTransferStatusExecuted) == (schedule.TransferID == "") {
+					t.Errorf("ExecuteDue() updated = %v, only executed schedules should have the transfer ID", schedule)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+var (
+	// ErrScheduledTransferStatusInvalid happens when the status filter is not known.
+	ErrScheduledTransferStatusInvalid = errors.New("'status' must be 'scheduled', 'executed', 'failed' or 'cancelled'")
+	// ErrScheduledTransferFetch happens when an error occurred while fetching the scheduled transfers.
+	ErrScheduledTransferFetch = errors.New("could not fetch scheduled transfers")
+)
+
+// Fetch returns the schedules of the origin account, the next ones first, optionally filtered by status.
+func (schUC scheduledTransferUseCase) Fetch(ctx context.Context, originID model.AccountID, status string) ([]ScheduledTransferOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	statusFilter := model.ScheduledTransferStatus(status)
+	if statusFilter != "" && !statusFilter.IsValid() {
+		return nil, ErrScheduledTransferStatusInvalid
+	}
+
+	schedules, err := schUC.schRepo.Fetch(ctx, originID, statusFilter)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error fetching scheduled transfers")
+		return nil, ErrScheduledTransferFetch
+	}
+
+	outputs := make([]ScheduledTransferOutput, 0, len(schedules))
+	for _, schedule := range schedules {
+		outputs = append(outputs, newScheduledTransferOutput(&schedule))
+	}
+
+	return outputs, nil
+}
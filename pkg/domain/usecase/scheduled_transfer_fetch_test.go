@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_scheduledTransferUseCase_Fetch(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	scheduledFor := time.Date(2021, 1, 31, 9, 0, 0, 0, time.UTC)
+	processedAt := scheduledFor.Add(time.Second)
+
+	type fields struct {
+		schRepo repository.ScheduledTransferRepository
+	}
+	type args struct {
+		ctx      context.Context
+		originID model.AccountID
+		status   string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []ScheduledTransferOutput
+		wantErr error
+	}{
+		{
+			name:   "unknown status should return status invalid error",
+			fields: fields{},
+			args: args{
+				ctx:      backgroundCtx,
+				originID: "uuid-1",
+				status:   "pending",
+			},
+			wantErr: ErrScheduledTransferStatusInvalid,
+		},
+		{
+			name: "repo fetch error should return error",
+			fields: fields{
+				schRepo: mock.ScheduledTransferRepository{
+					OnFetch: func(ctx context.Context, originID model.AccountID, status model.ScheduledTransferStatus) ([]model.ScheduledTransfer, error) {
+						return nil, errors.New("any database error")
+					},
+				},
+			},
+			args: args{
+				ctx:      backgroundCtx,
+				originID: "uuid-1",
+			},
+			wantErr: ErrScheduledTransferFetch,
+		},
+		{
+			name: "repo empty result should return empty result",
+			fields: fields{
+				schRepo: mock.ScheduledTransferRepository{
+					OnFetch: func(ctx context.Context, originID model.AccountID, status model.ScheduledTransferStatus) ([]model.ScheduledTransfer, error) {
+						return []model.ScheduledTransfer{}, nil
+					},
+				},
+			},
+			args: args{
+				ctx:      backgroundCtx,
+				originID: "uuid-1",
+			},
+			want:    []ScheduledTransferOutput{},
+			wantErr: nil,
+		},
+		{
+			name: "should pass the status to the repo and return the outcomes",
+			fields: fields{
+				schRepo: mock.ScheduledTransferRepository{
+					OnFetch: func(ctx context.Context, originID model.AccountID, status model.ScheduledTransferStatus) ([]model.ScheduledTransfer, error) {
+						if originID != "uuid-1" || status != model.ScheduledTransferStatusFailed {
+							return nil, errors.New("unexpected filter")
+						}
+						return []model.ScheduledTransfer{
+							{
+								ID:                   "schedule-uuid",
+								AccountOriginID:      "uuid-1",
+								AccountDestinationID: "uuid-2",
+								Amount:               100,
+								ScheduledFor:         scheduledFor,
+								Status:               model.ScheduledTransferStatusFailed,
+								FailureReason:        "current account balance is insufficient",
+								ProcessedAt:          processedAt,
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				ctx:      backgroundCtx,
+				originID: "uuid-1",
+				status:   "failed",
+			},
+			want: []ScheduledTransferOutput{
+				{
+					ID:                   "schedule-uuid",
+					AccountOriginID:      "uuid-1",
+					AccountDestinationID: "uuid-2",
+					Amount:               NewAmount(100),
+					ScheduledFor:         scheduledFor,
+					Status:               "failed",
+					FailureReason:        "current account balance is insufficient",
+					ProcessedAt:          &processedAt,
+				},
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schUC := NewScheduledTransferUseCase(tt.fields.schRepo, nil, nil, nil, nil, TransferLimitPolicy{}, FeePolicy{})
+
+			got, err := schUC.Fetch(tt.args.ctx, tt.args.originID, tt.args.status)
+			if err != tt.wantErr {
+				t.Errorf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fetch() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// StandingOrderUseCase is the interface that wraps all business logic methods related to the standing orders.
+type StandingOrderUseCase interface {
+	Create(ctx context.Context, orderInput StandingOrderCreateInput) (*StandingOrderOutput, error)
+	Fetch(ctx context.Context, originID model.AccountID) ([]StandingOrderOutput, error)
+	Update(ctx context.Context, caller model.Principal, orderInput StandingOrderUpdateInput) (*StandingOrderOutput, error)
+	Pause(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*StandingOrderOutput, error)
+	Resume(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*StandingOrderOutput, error)
+	Cancel(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*StandingOrderOutput, error)
+	ExecuteDue(ctx context.Context, limit int) (int, error)
+}
+
+// StandingOrderRetryPolicy defines how the failed occurrences of the standing orders are retried.
+//
+// A failed occurrence runs again after Interval, up to MaxRetries times, but never on or after the next occurrence.
+// Then it's skipped and the holder is notified.
+type StandingOrderRetryPolicy struct {
+	MaxRetries int
+	Interval   time.Duration
+}
+
+type standingOrderUseCase struct {
+	soRepo      repository.StandingOrderRepository
+	accRepo     repository.AccountRepository
+	ntfRepo     repository.NotificationRepository
+	trfUC       transferUseCase
+	retryPolicy StandingOrderRetryPolicy
+}
+
+// NewStandingOrderUseCase instantiates a new StandingOrderUseCase.
+// The occurrences are executed with the same logic of TransferUseCase.Create.
+func NewStandingOrderUseCase(
+	soRepo repository.StandingOrderRepository,
+	trfRepo repository.TransferRepository,
+	accRepo repository.AccountRepository,
+	ledgerRepo repository.LedgerRepository,
+	ntfRepo repository.NotificationRepository,
+	limitRepo repository.TransferLimitRepository,
+	limitPolicy TransferLimitPolicy,
+	feePolicy FeePolicy,
+	retryPolicy StandingOrderRetryPolicy,
+) StandingOrderUseCase {
+	return &standingOrderUseCase{
+		soRepo:  soRepo,
+		accRepo: accRepo,
+		ntfRepo: ntfRepo,
+		trfUC: transferUseCase{
+			trfRepo:     trfRepo,
+			accRepo:     accRepo,
+			ledgerRepo:  ledgerRepo,
+			limitRepo:   limitRepo,
+			limitPolicy: limitPolicy,
+			feePolicy:   feePolicy,
+		},
+		retryPolicy: retryPolicy,
+	}
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrStandingOrderFrequencyInvalid happens when the frequency is not known.
+	ErrStandingOrderFrequencyInvalid = errors.New("'frequency' must be 'weekly', 'monthly' or 'last_business_day'")
+	// ErrStandingOrderDayOfMonthInvalid happens when a monthly standing order has no day between 1 and 31.
+	ErrStandingOrderDayOfMonthInvalid = errors.New("'day_of_month' must be between 1 and 31 for monthly standing orders")
+	// ErrStandingOrderStartInvalid happens when the start date is not in the future or is too far.
+	ErrStandingOrderStartInvalid = errors.New("'starts_at' must be in the future, up to one year ahead")
+	// ErrStandingOrderEndInvalid happens when the end date or the occurrences count leave no occurrence to run.
+	ErrStandingOrderEndInvalid = errors.New("'ends_at' and 'max_occurrences' must allow the next occurrence")
+	// ErrStandingOrderCreate happens when an error occurred and the standing order was not created.
+	ErrStandingOrderCreate = errors.New("could not create standing order")
+)
+
+// StandingOrderCreateInput represents the expected input data when creating a standing order.
+type StandingOrderCreateInput struct {
+	AccountOriginID      string     `json:"-"`
+	AccountDestinationID string     `json:"account_destination_id" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
+	Amount               Amount     `json:"amount" swaggertype:"number" example:"1500"`
+	Frequency            string     `json:"frequency" example:"monthly" enums:"weekly,monthly,last_business_day"`
+	DayOfMonth           int        `json:"day_of_month,omitempty" example:"5"`
+	StartsAt             time.Time  `json:"starts_at" example:"2021-01-01T09:00:00-03:00"`
+	EndsAt               *time.Time `json:"ends_at,omitempty" example:"2021-12-31T23:59:59-03:00"`
+	MaxOccurrences       int        `json:"max_occurrences,omitempty" example:"12"`
+}
+
+// Validate validates the StandingOrderCreateInput fields.
+func (input *StandingOrderCreateInput) Validate() error {
+	input.AccountOriginID = strings.TrimSpace(input.AccountOriginID)
+	if len(input.AccountOriginID) < 1 {
+		return ErrTransferOriginAccountRequired
+	}
+
+	input.AccountDestinationID = strings.TrimSpace(input.AccountDestinationID)
+	if len(input.AccountDestinationID) < 1 {
+		return ErrTransferDestinationAccountRequired
+	}
+
+	if input.Amount.Money <= 0 {
+		return ErrTransferAmountNotPositive
+	}
+
+	if input.AccountOriginID == input.AccountDestinationID {
+		return ErrTransferSameAccount
+	}
+
+	frequency := model.StandingOrderFrequency(input.Frequency)
+	if !frequency.IsValid() {
+		return ErrStandingOrderFrequencyInvalid
+	}
+
+	if frequency != model.StandingOrderFrequencyMonthly {
+		input.DayOfMonth = 0
+	} else if input.DayOfMonth < 1 || input.DayOfMonth > 31 {
+		return ErrStandingOrderDayOfMonthInvalid
+	}
+
+	now := time.Now()
+	if !input.StartsAt.After(now) || input.StartsAt.After(now.Add(ScheduledTransferMaxAdvance)) {
+		return ErrStandingOrderStartInvalid
+	}
+
+	if input.MaxOccurrences < 0 {
+		return ErrStandingOrderEndInvalid
+	}
+
+	return nil
+}
+
+// StandingOrderOutput represents a standing order with the outcome of its last run.
+type StandingOrderOutput struct {
+	ID                   string     `json:"id" example:"3f1c5b7e-8d2a-4c6f-9e0b-1a2b3c4d5e6f"`
+	AccountOriginID      string     `json:"account_origin_id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	AccountDestinationID string     `json:"account_destination_id" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
+	Amount               Amount     `json:"amount" swaggertype:"number" example:"1500"`
+	Frequency            string     `json:"frequency" example:"monthly" enums:"weekly,monthly,last_business_day"`
+	DayOfMonth           int        `json:"day_of_month,omitempty" example:"5"`
+	StartsAt             time.Time  `json:"starts_at" example:"2021-01-01T09:00:00-03:00"`
+	EndsAt               *time.Time `json:"ends_at,omitempty" example:"2021-12-31T23:59:59-03:00"`
+	MaxOccurrences       int        `json:"max_occurrences,omitempty" example:"12"`
+	Occurrences          int        `json:"occurrences" example:"1"`
+	Status               string     `json:"status" example:"active" enums:"active,paused,finished,cancelled"`
+	NextDueAt            *time.Time `json:"next_due_at,omitempty" example:"2021-02-05T09:00:00-03:00"`
+	NextRunAt            *time.Time `json:"next_run_at,omitempty" example:"2021-02-05T09:00:00-03:00"`
+	Retries              int        `json:"retries,omitempty" example:"0"`
+	LastTransferID       string     `json:"last_transfer_id,omitempty" example:"e82706ef-9ffb-45a2-8081-547accd818c4"`
+	LastFailureReason    string     `json:"last_failure_reason,omitempty" example:"current account balance is insufficient"`
+	LastRunAt            *time.Time `json:"last_run_at,omitempty" example:"2021-01-05T09:00:01.999999-03:00"`
+	CreatedAt            time.Time  `json:"created_at" example:"2020-12-31T23:59:59.999999-03:00"`
+	UpdatedAt            time.Time  `json:"updated_at" example:"2021-01-05T09:00:01.999999-03:00"`
+}
+
+func newStandingOrderOutput(order *model.StandingOrder) StandingOrderOutput {
+	output := StandingOrderOutput{
+		ID:                   string(order.ID),
+		AccountOriginID:      string(order.AccountOriginID),
+		AccountDestinationID: string(order.AccountDestinationID),
+		Amount:               NewAmountIn(order.Amount, order.Currency),
+		Frequency:            string(order.Frequency),
+		DayOfMonth:           order.DayOfMonth,
+		StartsAt:             order.StartsAt,
+		MaxOccurrences:       order.MaxOccurrences,
+		Occurrences:          order.Occurrences,
+		Status:               string(order.Status),
+		Retries:              order.Retries,
+		LastTransferID:       string(order.LastTransferID),
+		LastFailureReason:    order.LastFailureReason,
+		CreatedAt:            order.CreatedAt,
+		UpdatedAt:            order.UpdatedAt,
+	}
+	if !order.EndsAt.IsZero() {
+		endsAt := order.EndsAt
+		output.EndsAt = &endsAt
+	}
+	// the next occurrence of a paused standing order is only known when it's resumed
+	if order.Status == model.StandingOrderStatusActive {
+		nextDueAt, nextRunAt := order.NextDueAt, order.NextRunAt
+		output.NextDueAt = &nextDueAt
+		output.NextRunAt = &nextRunAt
+	}
+	if !order.LastRunAt.IsZero() {
+		lastRunAt := order.LastRunAt
+		output.LastRunAt = &lastRunAt
+	}
+
+	return output
+}
+
+// Create validates the input and saves the standing order, whose occurrences are executed by ExecuteDue.
+// Both accounts must be active when creating, but the balance is only checked at each execution.
+func (soUC standingOrderUseCase) Create(ctx context.Context, orderInput StandingOrderCreateInput) (*StandingOrderOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := orderInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", orderInput).Msg("standing order create input is not valid")
+		return nil, err
+	}
+
+	var endsAt time.Time
+	if orderInput.EndsAt != nil {
+		endsAt = *orderInput.EndsAt
+	}
+
+	order := model.NewStandingOrder(
+		model.AccountID(orderInput.AccountOriginID),
+		model.AccountID(orderInput.AccountDestinationID),
+		orderInput.Amount.Money,
+		model.StandingOrderFrequency(orderInput.Frequency),
+		orderInput.DayOfMonth,
+		orderInput.StartsAt,
+		endsAt,
+		orderInput.MaxOccurrences)
+	if order.Status == model.StandingOrderStatusFinished {
+		return nil, ErrStandingOrderEndInvalid
+	}
+
+	order.Currency, err = ensureActiveAccounts(ctx, soUC.accRepo, order.AccountOriginID, order.AccountDestinationID)
+	if err != nil {
+		switch err {
+		case repository.ErrAccountNotFound, ErrTransferOriginAccountNotActive, ErrTransferDestinationAccountNotActive,
+			ErrTransferCurrencyMismatch:
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("order", order).Msg("error getting standing order accounts")
+		return nil, ErrStandingOrderCreate
+	}
+
+	order.Amount, err = orderInput.Amount.In(order.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	err = soUC.soRepo.Create(ctx, order)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("order", order).Msg("error persisting new standing order")
+		return nil, ErrStandingOrderCreate
+	}
+
+	output := newStandingOrderOutput(order)
+	return &output, nil
+}
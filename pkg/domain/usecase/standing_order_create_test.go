@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_standingOrderUseCase_Create(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	tomorrow := time.Now().Add(24 * time.Hour)
+	nextWeek := tomorrow.AddDate(0, 0, 7)
+	beforeStart := tomorrow.Add(-time.Minute)
+
+	accountsWithStatus := func(statuses map[model.AccountID]model.AccountStatus) func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+		return func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			status, ok := statuses[id]
+			if !ok {
+				return nil, repository.ErrAccountNotFound
+			}
+			return &model.Account{ID: id, Status: status}, nil
+		}
+	}
+	bothActive := mock.AccountRepository{
+		OnGetBalance: accountsWithStatus(map[model.AccountID]model.AccountStatus{"uuid-1": model.AccountStatusActive, "uuid-2": model.AccountStatusActive}),
+	}
+	createOK := mock.StandingOrderRepository{
+		OnCreate: func(ctx context.Context, order *model.StandingOrder) error {
+			return nil
+		},
+	}
+	weekly := func(input StandingOrderCreateInput) StandingOrderCreateInput {
+		input.AccountOriginID = "uuid-1"
+		input.AccountDestinationID = "uuid-2"
+		input.Amount = NewAmount(100)
+		input.Frequency = string(model.StandingOrderFrequencyWeekly)
+		if input.StartsAt.IsZero() {
+			input.StartsAt = tomorrow
+		}
+		return input
+	}
+
+	type fields struct {
+		soRepo  repository.StandingOrderRepository
+		accRepo repository.AccountRepository
+	}
+	tests := []struct {
+		name          string
+		fields        fields
+		orderInput    StandingOrderCreateInput
+		wantNextDueAt time.Time
+		wantErr       error
+	}{
+		{
+			name:       "same account should return error",
+			orderInput: StandingOrderCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-1", Amount: NewAmount(100), Frequency: "weekly", StartsAt: tomorrow},
+			wantErr:    ErrTransferSameAccount,
+		},
+		{
+			name:       "unknown frequency should return error",
+			orderInput: StandingOrderCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(100), Frequency: "daily", StartsAt: tomorrow},
+			wantErr:    ErrStandingOrderFrequencyInvalid,
+		},
+		{
+			name:       "monthly without day should return error",
+			orderInput: StandingOrderCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(100), Frequency: "monthly", StartsAt: tomorrow},
+			wantErr:    ErrStandingOrderDayOfMonthInvalid,
+		},
+		{
+			name:       "monthly with day 32 should return error",
+			orderInput: StandingOrderCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(100), Frequency: "monthly", DayOfMonth: 32, StartsAt: tomorrow},
+			wantErr:    ErrStandingOrderDayOfMonthInvalid,
+		},
+		{
+			name:       "past start should return error",
+			orderInput: weekly(StandingOrderCreateInput{StartsAt: time.Now().Add(-time.Minute)}),
+			wantErr:    ErrStandingOrderStartInvalid,
+		},
+		{
+			name:       "negative max occurrences should return error",
+			orderInput: weekly(StandingOrderCreateInput{MaxOccurrences: -1}),
+			wantErr:    ErrStandingOrderEndInvalid,
+		},
+		{
+			name:       "end before the first occurrence should return error",
+			orderInput: weekly(StandingOrderCreateInput{EndsAt: &beforeStart}),
+			wantErr:    ErrStandingOrderEndInvalid,
+		},
+		{
+			name: "blocked destination should return error",
+			fields: fields{
+				accRepo: mock.AccountRepository{
+					OnGetBalance: accountsWithStatus(map[model.AccountID]model.AccountStatus{"uuid-1": model.AccountStatusActive, "uuid-2": model.AccountStatusBlocked}),
+				},
+			},
+			orderInput: weekly(StandingOrderCreateInput{}),
+			wantErr:    ErrTransferDestinationAccountNotActive,
+		},
+		{
+			name: "repository error should return create error",
+			fields: fields{
+				soRepo: mock.StandingOrderRepository{
+					OnCreate: func(ctx context.Context, order *model.StandingOrder) error {
+						return errors.New("any database error")
+					},
+				},
+				accRepo: bothActive,
+			},
+			orderInput: weekly(StandingOrderCreateInput{}),
+			wantErr:    ErrStandingOrderCreate,
+		},
+		{
+			name: "weekly should start on the start date",
+			fields: fields{
+				soRepo:  createOK,
+				accRepo: bothActive,
+			},
+			orderInput:    weekly(StandingOrderCreateInput{EndsAt: &nextWeek, MaxOccurrences: 2}),
+			wantNextDueAt: tomorrow,
+		},
+		{
+			name: "last business day should ignore the day of month",
+			fields: fields{
+				soRepo:  createOK,
+				accRepo: bothActive,
+			},
+			orderInput: StandingOrderCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(100),
+				Frequency: "last_business_day", DayOfMonth: 40, StartsAt: tomorrow},
+			wantNextDueAt: (&model.StandingOrder{Frequency: model.StandingOrderFrequencyLastBusinessDay, StartsAt: tomorrow}).OccurrenceAfter(tomorrow.Add(-time.Nanosecond)),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			soUC := NewStandingOrderUseCase(tt.fields.soRepo, nil, tt.fields.accRepo, nil, nil, nil, TransferLimitPolicy{}, FeePolicy{}, StandingOrderRetryPolicy{})
+			got, err := soUC.Create(backgroundCtx, tt.orderInput)
+			if err != tt.wantErr {
+				t.Fatalf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.ID == "" || got.Status != string(model.StandingOrderStatusActive) || got.DayOfMonth != 0 || got.Occurrences != 0 {
+				t.Errorf("Create() got = %v, want a new active standing order", got)
+			}
+			if got.NextDueAt == nil || !got.NextDueAt.Equal(tt.wantNextDueAt) {
+				t.Errorf("Create() NextDueAt = %v, want %v", got.NextDueAt, tt.wantNextDueAt)
+			}
+		})
+	}
+}
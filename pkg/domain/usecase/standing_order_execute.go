@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/infraestructure/monitoring"
+)
+
+// standingOrderRun is the outcome of running an occurrence of a standing order.
+type standingOrderRun struct {
+	order    *model.StandingOrder
+	outcome  string
+	retrying bool
+}
+
+// ExecuteDue executes up to limit due standing order occurrences, the oldest first, returning how many were processed.
+//
+// Each occurrence is executed in its own transaction, holding the standing order row lock, so it's safe to run on
+// multiple replicas. When the transfer is rejected, like for insufficient balance, the occurrence is retried following
+// the StandingOrderRetryPolicy and the holder is notified. On any other error, the occurrence is kept due to be
+// executed on the next call.
+func (soUC standingOrderUseCase) ExecuteDue(ctx context.Context, limit int) (int, error) {
+	processed := 0
+	for processed < limit {
+		run, err := soUC.executeNextDue(ctx)
+		if err != nil {
+			return processed, err
+		}
+		if run == nil {
+			break
+		}
+
+		processed++
+		monitoring.StandingOrderOccurrencesProcessed.WithLabelValues(run.outcome).Inc()
+		log.Ctx(ctx).Info().Str("id", string(run.order.ID)).Str("outcome", run.outcome).Str("transferID", string(run.order.LastTransferID)).
+			Str("reason", run.order.LastFailureReason).Str("status", string(run.order.Status)).Msg("standing order occurrence processed")
+	}
+
+	return processed, nil
+}
+
+// executeNextDue executes the occurrence of the next due standing order, returning its outcome, or nil if there's none.
+func (soUC standingOrderUseCase) executeNextDue(ctx context.Context) (*standingOrderRun, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	data, err := soUC.soRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		order, err := soUC.soRepo.LockNextDue(txCtx, time.Now())
+		if err != nil || order == nil {
+			return nil, err
+		}
+
+		transfer := model.NewTransfer(
+			string(order.AccountOriginID),
+			string(order.AccountDestinationID),
+			order.Amount)
+
+		// a rejected transfer wrote nothing, so the failure can be saved in the same transaction
+		run := &standingOrderRun{order: order}
+		err = soUC.trfUC.execute(txCtx, transfer)
+		switch {
+		case err == nil:
+			order.Executed(transfer.ID)
+			run.outcome = "executed"
+		case isTransferRejection(err):
+			run.retrying = order.Failed(err.Error(), soUC.retryPolicy.MaxRetries, soUC.retryPolicy.Interval)
+			run.outcome = "failed"
+			if run.retrying {
+				run.outcome = "retrying"
+			}
+
+			err = soUC.ntfRepo.Create(txCtx, newStandingOrderFailureNotification(order, run.retrying))
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, err
+		}
+
+		return run, soUC.soRepo.Update(txCtx, order)
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error executing standing order")
+		return nil, err
+	}
+
+	run, _ := data.(*standingOrderRun)
+	return run, nil
+}
+
+func newStandingOrderFailureNotification(order *model.StandingOrder, retrying bool) *model.Notification {
+	message := fmt.Sprintf("The standing order payment of %s to account %s failed: %s.",
+		order.Amount, order.AccountDestinationID, order.LastFailureReason)
+
+	if retrying {
+		message += fmt.Sprintf(" It will be retried at %s.", order.NextRunAt.Format(time.RFC3339))
+		return model.NewNotification(order.AccountOriginID, model.NotificationStandingOrderRetrying, string(order.ID), message)
+	}
+
+	message += " It won't be retried and was skipped."
+	return model.NewNotification(order.AccountOriginID, model.NotificationStandingOrderFailed, string(order.ID), message)
+}
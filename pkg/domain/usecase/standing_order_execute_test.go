@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_standingOrderUseCase_ExecuteDue(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	retryPolicy := StandingOrderRetryPolicy{MaxRetries: 1, Interval: time.Hour}
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	// dueOrders returns the weekly standing orders one by one, then none
+	dueOrders := func(count int, retries int) func(ctx context.Context, now time.Time) (*model.StandingOrder, error) {
+		return func(ctx context.Context, now time.Time) (*model.StandingOrder, error) {
+			if count == 0 {
+				return nil, nil
+			}
+			count--
+			order := model.NewStandingOrder("uuid-1", "uuid-2", 100, model.StandingOrderFrequencyWeekly, 0, now.Add(-time.Minute), time.Time{}, 0)
+			order.Retries = retries
+			return order, nil
+		}
+	}
+	accountsWithBalance := func(balance model.Money) mock.AccountRepository {
+		return mock.AccountRepository{
+			OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+				return &model.Account{ID: id, Balance: balance, Status: model.AccountStatusActive}, nil
+			},
+		}
+	}
+	ledgerRepoOK := mock.LedgerRepository{
+		OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+			return nil
+		},
+	}
+	noAccountLimits := mock.TransferLimitRepository{
+		OnGet: func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+			return &model.AccountTransferLimits{AccountID: accountID}, nil
+		},
+	}
+	trfRepoOK := mock.TransferRepository{
+		OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+			return nil
+		},
+	}
+
+	type fields struct {
+		lockNextDue func(ctx context.Context, now time.Time) (*model.StandingOrder, error)
+		accRepo     repository.AccountRepository
+		ledgerRepo  repository.LedgerRepository
+	}
+	tests := []struct {
+		name                 string
+		fields               fields
+		limit                int
+		want                 int
+		wantErr              bool
+		wantRetries          int
+		wantOccurrences      int
+		wantNotificationType model.NotificationType
+	}{
+		{
+			name: "no due standing order should process none",
+			fields: fields{
+				lockNextDue: dueOrders(0, 0),
+			},
+			limit: 10,
+			want:  0,
+		},
+		{
+			name: "enough balance should execute the transfer and move to the next occurrence",
+			fields: fields{
+				lockNextDue: dueOrders(1, 0),
+				accRepo:     accountsWithBalance(100),
+				ledgerRepo:  ledgerRepoOK,
+			},
+			limit:           10,
+			want:            1,
+			wantOccurrences: 1,
+		},
+		{
+			name: "insufficient balance should retry and notify",
+			fields: fields{
+				lockNextDue: dueOrders(1, 0),
+				accRepo:     accountsWithBalance(99),
+			},
+			limit:                10,
+			want:                 1,
+			wantRetries:          1,
+			wantNotificationType: model.NotificationStandingOrderRetrying,
+		},
+		{
+			name: "insufficient balance without retries left should skip the occurrence and notify",
+			fields: fields{
+				lockNextDue: dueOrders(1, 1),
+				accRepo:     accountsWithBalance(99),
+			},
+			limit:                10,
+			want:                 1,
+			wantOccurrences:      1,
+			wantNotificationType: model.NotificationStandingOrderFailed,
+		},
+		{
+			name: "should stop at the limit",
+			fields: fields{
+				lockNextDue: dueOrders(3, 0),
+				accRepo:     accountsWithBalance(100),
+				ledgerRepo:  ledgerRepoOK,
+			},
+			limit:           2,
+			want:            2,
+			wantOccurrences: 1,
+		},
+		{
+			name: "ledger error should keep the occurrence due and return error",
+			fields: fields{
+				lockNextDue: dueOrders(1, 0),
+				accRepo:     accountsWithBalance(100),
+				ledgerRepo: mock.LedgerRepository{
+					OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+						return errors.New("any database error")
+					},
+				},
+			},
+			limit:   10,
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var updated []model.StandingOrder
+			soRepo := mock.StandingOrderRepository{
+				OnWithinTransaction: withinTransaction,
+				OnLockNextDue:       tt.fields.lockNextDue,
+				OnUpdate: func(ctx context.Context, order *model.StandingOrder) error {
+					updated = append(updated, *order)
+					return nil
+				},
+			}
+			var notifications []model.Notification
+			ntfRepo := mock.NotificationRepository{
+				OnCreate: func(ctx context.Context, notification *model.Notification) error {
+					notifications = append(notifications, *notification)
+					return nil
+				},
+			}
+			soUC := NewStandingOrderUseCase(soRepo, trfRepoOK, tt.fields.accRepo, tt.fields.ledgerRepo, ntfRepo, noAccountLimits, TransferLimitPolicy{}, FeePolicy{}, retryPolicy)
+
+			got, err := soUC.ExecuteDue(backgroundCtx, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecuteDue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want || len(updated) != tt.want {
+				t.Errorf("ExecuteDue() got = %v and updated %v, want %v", got, len(updated), tt.want)
+			}
+
+			for _, order := range updated {
+				if order.Retries != tt.wantRetries || order.Occurrences != tt.wantOccurrences || order.Status != model.StandingOrderStatusActive {
+					t.Errorf("ExecuteDue() updated = %v, want %v retries and %v occurrences", order, tt.wantRetries, tt.wantOccurrences)
+				}
+				if (tt.wantNotificationType == "") == (order.LastTransferID == "") {
+					t.Errorf("ExecuteDue() updated = %v, only executed occurrences should have the transfer ID", order)
+				}
+			}
+
+			if tt.wantNotificationType == "" {
+				if len(notifications) > 0 {
+					t.Errorf("ExecuteDue() notifications = %v, want none", notifications)
+				}
+				return
+			}
+			if len(notifications) != tt.want {
+				t.Fatalf("ExecuteDue() notifications = %v, want %v", notifications, tt.want)
+			}
+			for _, notification := range notifications {
+				if notification.Type != tt.wantNotificationType || notification.AccountID != "uuid-1" ||
+					!strings.Contains(notification.Message, ErrAccountCurrentBalanceInsufficient.Error()) {
+					t.Errorf("ExecuteDue() notification = %v, want %v to the origin account with the reason", notification, tt.wantNotificationType)
+				}
+			}
+		})
+	}
+}
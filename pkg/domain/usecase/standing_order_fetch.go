@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+var (
+	// ErrStandingOrderFetch happens when an error occurred while fetching the standing orders.
+	ErrStandingOrderFetch = errors.New("could not fetch standing orders")
+)
+
+// Fetch returns the standing orders of the origin account, the newest first.
+func (soUC standingOrderUseCase) Fetch(ctx context.Context, originID model.AccountID) ([]StandingOrderOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	orders, err := soUC.soRepo.Fetch(ctx, originID)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error fetching standing orders")
+		return nil, ErrStandingOrderFetch
+	}
+
+	outputs := make([]StandingOrderOutput, 0, len(orders))
+	for _, order := range orders {
+		outputs = append(outputs, newStandingOrderOutput(&order))
+	}
+
+	return outputs, nil
+}
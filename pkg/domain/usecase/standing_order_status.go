@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrStandingOrderStatusChangeInvalid happens when the standing order can't go from its current status to the requested one,
+	// like resuming an active standing order or changing a finished one.
+	ErrStandingOrderStatusChangeInvalid = errors.New("standing order status can't be changed")
+	// ErrStandingOrderChangeStatus happens when an error occurred and the standing order status was not changed.
+	ErrStandingOrderChangeStatus = errors.New("could not change standing order status")
+)
+
+// Pause stops executing the occurrences of an active standing order of the caller account until it's resumed.
+func (soUC standingOrderUseCase) Pause(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*StandingOrderOutput, error) {
+	return soUC.changeStatus(ctx, caller, id, model.StandingOrderStatusPaused)
+}
+
+// Resume makes a paused standing order of the caller account active again.
+// The occurrences missed while paused are not executed.
+func (soUC standingOrderUseCase) Resume(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*StandingOrderOutput, error) {
+	return soUC.changeStatus(ctx, caller, id, model.StandingOrderStatusActive)
+}
+
+// Cancel stops an active or paused standing order of the caller account for good.
+func (soUC standingOrderUseCase) Cancel(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*StandingOrderOutput, error) {
+	return soUC.changeStatus(ctx, caller, id, model.StandingOrderStatusCancelled)
+}
+
+func (soUC standingOrderUseCase) changeStatus(ctx context.Context, caller model.Principal, id model.StandingOrderID, status model.StandingOrderStatus) (*StandingOrderOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	// the row lock waits for an executor running the standing order
+	data, err := soUC.withOwnStandingOrder(ctx, caller, id, func(txCtx context.Context, order *model.StandingOrder) error {
+		if !order.CanChangeStatusTo(status) {
+			return ErrStandingOrderStatusChangeInvalid
+		}
+
+		order.ChangeStatus(status)
+
+		return soUC.soRepo.Update(txCtx, order)
+	})
+	if err != nil {
+		if err == repository.ErrStandingOrderNotFound || err == ErrStandingOrderStatusChangeInvalid {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("id", string(id)).Str("status", string(status)).Msg("error changing standing order status")
+		return nil, ErrStandingOrderChangeStatus
+	}
+
+	log.Ctx(ctx).Info().Str("id", string(data.ID)).Str("status", string(data.Status)).Str("by", string(caller.AccountID)).Msg("standing order status changed")
+
+	output := newStandingOrderOutput(data)
+	return &output, nil
+}
+
+// withOwnStandingOrder locks the standing order and calls txFunc with it, within a transaction.
+// The standing orders of other accounts are reported as repository.ErrStandingOrderNotFound, so their IDs are not disclosed.
+func (soUC standingOrderUseCase) withOwnStandingOrder(
+	ctx context.Context,
+	caller model.Principal,
+	id model.StandingOrderID,
+	txFunc func(txCtx context.Context, order *model.StandingOrder) error,
+) (*model.StandingOrder, error) {
+	if _, err := uuid.Parse(string(id)); err != nil {
+		return nil, repository.ErrStandingOrderNotFound
+	}
+
+	data, err := soUC.soRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		order, err := soUC.soRepo.GetByIDForUpdate(txCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !caller.Owns(order.AccountOriginID) {
+			return nil, repository.ErrStandingOrderNotFound
+		}
+
+		return order, txFunc(txCtx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	order, _ := data.(*model.StandingOrder)
+	return order, nil
+}
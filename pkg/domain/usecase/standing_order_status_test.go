@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_standingOrderUseCase_changeStatus(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	caller := model.Principal{AccountID: "uuid-1"}
+	orderID := model.StandingOrderID("3f1c5b7e-8d2a-4c6f-9e0b-1a2b3c4d5e6f")
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	orderWith := func(originID model.AccountID, status model.StandingOrderStatus) func(ctx context.Context, id model.StandingOrderID) (*model.StandingOrder, error) {
+		return func(ctx context.Context, id model.StandingOrderID) (*model.StandingOrder, error) {
+			order := model.NewStandingOrder(originID, "uuid-2", 100, model.StandingOrderFrequencyWeekly, 0, time.Now().Add(time.Hour), time.Time{}, 0)
+			order.ID = id
+			order.Status = status
+			return order, nil
+		}
+	}
+	soRepoWith := func(getByIDForUpdate func(ctx context.Context, id model.StandingOrderID) (*model.StandingOrder, error), updateErr error) mock.StandingOrderRepository {
+		return mock.StandingOrderRepository{
+			OnWithinTransaction: withinTransaction,
+			OnGetByIDForUpdate:  getByIDForUpdate,
+			OnUpdate: func(ctx context.Context, order *model.StandingOrder) error {
+				return updateErr
+			},
+		}
+	}
+
+	type args struct {
+		id     model.StandingOrderID
+		status model.StandingOrderStatus
+	}
+	tests := []struct {
+		name       string
+		soRepo     repository.StandingOrderRepository
+		args       args
+		wantStatus string
+		wantErr    error
+	}{
+		{
+			name:    "id not uuid should return not found error",
+			args:    args{id: "any-id", status: model.StandingOrderStatusPaused},
+			wantErr: repository.ErrStandingOrderNotFound,
+		},
+		{
+			name:    "other account standing order should return not found error",
+			soRepo:  soRepoWith(orderWith("uuid-3", model.StandingOrderStatusActive), nil),
+			args:    args{id: orderID, status: model.StandingOrderStatusPaused},
+			wantErr: repository.ErrStandingOrderNotFound,
+		},
+		{
+			name:    "resuming an active standing order should return status change error",
+			soRepo:  soRepoWith(orderWith("uuid-1", model.StandingOrderStatusActive), nil),
+			args:    args{id: orderID, status: model.StandingOrderStatusActive},
+			wantErr: ErrStandingOrderStatusChangeInvalid,
+		},
+		{
+			name:    "cancelling a finished standing order should return status change error",
+			soRepo:  soRepoWith(orderWith("uuid-1", model.StandingOrderStatusFinished), nil),
+			args:    args{id: orderID, status: model.StandingOrderStatusCancelled},
+			wantErr: ErrStandingOrderStatusChangeInvalid,
+		},
+		{
+			name:    "repository error should return change status error",
+			soRepo:  soRepoWith(orderWith("uuid-1", model.StandingOrderStatusActive), errors.New("any database error")),
+			args:    args{id: orderID, status: model.StandingOrderStatusPaused},
+			wantErr: ErrStandingOrderChangeStatus,
+		},
+		{
+			name:       "pausing an active standing order",
+			soRepo:     soRepoWith(orderWith("uuid-1", model.StandingOrderStatusActive), nil),
+			args:       args{id: orderID, status: model.StandingOrderStatusPaused},
+			wantStatus: "paused",
+		},
+		{
+			name:       "resuming a paused standing order",
+			soRepo:     soRepoWith(orderWith("uuid-1", model.StandingOrderStatusPaused), nil),
+			args:       args{id: orderID, status: model.StandingOrderStatusActive},
+			wantStatus: "active",
+		},
+		{
+			name:       "cancelling a paused standing order",
+			soRepo:     soRepoWith(orderWith("uuid-1", model.StandingOrderStatusPaused), nil),
+			args:       args{id: orderID, status: model.StandingOrderStatusCancelled},
+			wantStatus: "cancelled",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			soUC := NewStandingOrderUseCase(tt.soRepo, nil, nil, nil, nil, nil, TransferLimitPolicy{}, FeePolicy{}, StandingOrderRetryPolicy{})
+			changeStatus := map[model.StandingOrderStatus]func(context.Context, model.Principal, model.StandingOrderID) (*StandingOrderOutput, error){
+				model.StandingOrderStatusPaused:    soUC.Pause,
+				model.StandingOrderStatusActive:    soUC.Resume,
+				model.StandingOrderStatusCancelled: soUC.Cancel,
+			}[tt.args.status]
+
+			got, err := changeStatus(backgroundCtx, caller, tt.args.id)
+			if err != tt.wantErr {
+				t.Fatalf("changeStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.ID != string(orderID) || got.Status != tt.wantStatus {
+				t.Errorf("changeStatus() got = %v, want status %v", got, tt.wantStatus)
+			}
+			if (got.Status == "active") == (got.NextDueAt == nil) {
+				t.Errorf("changeStatus() got = %v, only active standing orders should have the next occurrence", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrStandingOrderNotRunning happens when editing a standing order already finished or cancelled.
+	ErrStandingOrderNotRunning = errors.New("standing order is finished or cancelled")
+	// ErrStandingOrderUpdate happens when an error occurred and the standing order was not updated.
+	ErrStandingOrderUpdate = errors.New("could not update standing order")
+)
+
+// StandingOrderUpdateInput represents the expected input data when editing a standing order.
+// Only the informed fields are changed. A zero 'max_occurrences' removes the occurrences limit.
+type StandingOrderUpdateInput struct {
+	ID                   string     `json:"-"`
+	AccountDestinationID *string    `json:"account_destination_id,omitempty" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
+	Amount               *Amount    `json:"amount,omitempty" swaggertype:"number" example:"1600"`
+	EndsAt               *time.Time `json:"ends_at,omitempty" example:"2022-12-31T23:59:59-03:00"`
+	MaxOccurrences       *int       `json:"max_occurrences,omitempty" example:"24"`
+}
+
+// Validate validates the StandingOrderUpdateInput fields.
+func (input *StandingOrderUpdateInput) Validate() error {
+	if input.AccountDestinationID != nil {
+		destinationID := strings.TrimSpace(*input.AccountDestinationID)
+		if len(destinationID) < 1 {
+			return ErrTransferDestinationAccountRequired
+		}
+		input.AccountDestinationID = &destinationID
+	}
+
+	if input.Amount != nil && input.Amount.Money <= 0 {
+		return ErrTransferAmountNotPositive
+	}
+
+	if input.MaxOccurrences != nil && *input.MaxOccurrences < 0 {
+		return ErrStandingOrderEndInvalid
+	}
+
+	return nil
+}
+
+// Update edits the destination, the amount or the end of an active or paused standing order of the caller account.
+// The changes apply from the next occurrence on, including the retries of a failed one.
+func (soUC standingOrderUseCase) Update(ctx context.Context, caller model.Principal, orderInput StandingOrderUpdateInput) (*StandingOrderOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := orderInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", orderInput).Msg("standing order update input is not valid")
+		return nil, err
+	}
+
+	order, err := soUC.withOwnStandingOrder(ctx, caller, model.StandingOrderID(orderInput.ID), func(txCtx context.Context, order *model.StandingOrder) error {
+		if order.Status != model.StandingOrderStatusActive && order.Status != model.StandingOrderStatusPaused {
+			return ErrStandingOrderNotRunning
+		}
+
+		err := soUC.applyUpdate(txCtx, order, orderInput)
+		if err != nil {
+			return err
+		}
+
+		return soUC.soRepo.Update(txCtx, order)
+	})
+	if err != nil {
+		switch err {
+		case repository.ErrStandingOrderNotFound, ErrStandingOrderNotRunning, ErrStandingOrderEndInvalid, ErrTransferSameAccount,
+			repository.ErrAccountNotFound, ErrTransferDestinationAccountNotActive, ErrTransferCurrencyMismatch, ErrAmountInvalid:
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("input", orderInput).Msg("error updating standing order")
+		return nil, ErrStandingOrderUpdate
+	}
+
+	log.Ctx(ctx).Info().Str("id", string(order.ID)).Str("by", string(caller.AccountID)).Msg("standing order updated")
+
+	output := newStandingOrderOutput(order)
+	return &output, nil
+}
+
+// applyUpdate changes the informed fields, checking the new destination account, which must have the currency of the
+// origin, and that there's still an occurrence to run.
+func (soUC standingOrderUseCase) applyUpdate(ctx context.Context, order *model.StandingOrder, orderInput StandingOrderUpdateInput) error {
+	if orderInput.AccountDestinationID != nil {
+		destinationID := model.AccountID(*orderInput.AccountDestinationID)
+		if destinationID == order.AccountOriginID {
+			return ErrTransferSameAccount
+		}
+
+		destinationAccount, err := soUC.accRepo.GetBalance(ctx, destinationID)
+		if err != nil {
+			return err
+		}
+		if !destinationAccount.IsActive() {
+			return ErrTransferDestinationAccountNotActive
+		}
+
+		originAccount, err := soUC.accRepo.GetBalance(ctx, order.AccountOriginID)
+		if err != nil {
+			return err
+		}
+		if originAccount.Currency != destinationAccount.Currency {
+			return ErrTransferCurrencyMismatch
+		}
+
+		order.AccountDestinationID = destinationID
+	}
+
+	if orderInput.Amount != nil {
+		amount, err := orderInput.Amount.In(order.Currency)
+		if err != nil {
+			return err
+		}
+		order.Amount = amount
+	}
+
+	if orderInput.EndsAt != nil {
+		order.EndsAt = *orderInput.EndsAt
+	}
+
+	if orderInput.MaxOccurrences != nil {
+		order.MaxOccurrences = *orderInput.MaxOccurrences
+	}
+
+	// a paused standing order resumes on the next occurrence from now on
+	next := order.NextDueAt
+	if order.Status == model.StandingOrderStatusPaused && next.Before(time.Now()) {
+		next = order.OccurrenceAfter(time.Now())
+	}
+	if order.IsEndedBy(next) {
+		return ErrStandingOrderEndInvalid
+	}
+
+	order.UpdatedAt = time.Now()
+
+	return nil
+}
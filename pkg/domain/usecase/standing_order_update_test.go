@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_standingOrderUseCase_Update(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	caller := model.Principal{AccountID: "uuid-1"}
+	orderID := "3f1c5b7e-8d2a-4c6f-9e0b-1a2b3c4d5e6f"
+	startsAt := time.Now().Add(time.Hour)
+
+	orderWith := func(status model.StandingOrderStatus, occurrences int) mock.StandingOrderRepository {
+		return mock.StandingOrderRepository{
+			OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+				return txFunc(ctx)
+			},
+			OnGetByIDForUpdate: func(ctx context.Context, id model.StandingOrderID) (*model.StandingOrder, error) {
+				order := model.NewStandingOrder("uuid-1", "uuid-2", 100, model.StandingOrderFrequencyWeekly, 0, startsAt, time.Time{}, 0)
+				order.ID = id
+				order.Status = status
+				order.Occurrences = occurrences
+				return order, nil
+			},
+			OnUpdate: func(ctx context.Context, order *model.StandingOrder) error {
+				return nil
+			},
+		}
+	}
+	accRepo := mock.AccountRepository{
+		OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			switch id {
+			case "uuid-1", "uuid-3":
+				return &model.Account{ID: id, Currency: model.CurrencyBRL, Status: model.AccountStatusActive}, nil
+			case "uuid-4":
+				return &model.Account{ID: id, Currency: model.CurrencyBRL, Status: model.AccountStatusClosed}, nil
+			case "uuid-5":
+				return &model.Account{ID: id, Currency: model.CurrencyUSD, Status: model.AccountStatusActive}, nil
+			default:
+				return nil, repository.ErrAccountNotFound
+			}
+		},
+	}
+	stringPtr := func(s string) *string { return &s }
+	intPtr := func(i int) *int { return &i }
+	amountPtr := func(m model.Money) *Amount {
+		amount := NewAmount(m)
+		return &amount
+	}
+	timePtr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name       string
+		soRepo     repository.StandingOrderRepository
+		orderInput StandingOrderUpdateInput
+		want       func(got *StandingOrderOutput) bool
+		wantErr    error
+	}{
+		{
+			name:       "not positive amount should return error",
+			orderInput: StandingOrderUpdateInput{ID: orderID, Amount: amountPtr(0)},
+			wantErr:    ErrTransferAmountNotPositive,
+		},
+		{
+			name:       "blank destination should return error",
+			orderInput: StandingOrderUpdateInput{ID: orderID, AccountDestinationID: stringPtr(" ")},
+			wantErr:    ErrTransferDestinationAccountRequired,
+		},
+		{
+			name:       "finished standing order should return not running error",
+			soRepo:     orderWith(model.StandingOrderStatusFinished, 0),
+			orderInput: StandingOrderUpdateInput{ID: orderID, Amount: amountPtr(200)},
+			wantErr:    ErrStandingOrderNotRunning,
+		},
+		{
+			name:       "origin as destination should return same account error",
+			soRepo:     orderWith(model.StandingOrderStatusActive, 0),
+			orderInput: StandingOrderUpdateInput{ID: orderID, AccountDestinationID: stringPtr("uuid-1")},
+			wantErr:    ErrTransferSameAccount,
+		},
+		{
+			name:       "closed destination should return error",
+			soRepo:     orderWith(model.StandingOrderStatusActive, 0),
+			orderInput: StandingOrderUpdateInput{ID: orderID, AccountDestinationID: stringPtr("uuid-4")},
+			wantErr:    ErrTransferDestinationAccountNotActive,
+		},
+		{
+			name:       "destination in another currency should return error",
+			soRepo:     orderWith(model.StandingOrderStatusActive, 0),
+			orderInput: StandingOrderUpdateInput{ID: orderID, AccountDestinationID: stringPtr("uuid-5")},
+			wantErr:    ErrTransferCurrencyMismatch,
+		},
+		{
+			name:       "max occurrences already reached should return end error",
+			soRepo:     orderWith(model.StandingOrderStatusActive, 2),
+			orderInput: StandingOrderUpdateInput{ID: orderID, MaxOccurrences: intPtr(2)},
+			wantErr:    ErrStandingOrderEndInvalid,
+		},
+		{
+			name:       "end before the next occurrence should return end error",
+			soRepo:     orderWith(model.StandingOrderStatusPaused, 0),
+			orderInput: StandingOrderUpdateInput{ID: orderID, EndsAt: timePtr(startsAt.Add(-time.Minute))},
+			wantErr:    ErrStandingOrderEndInvalid,
+		},
+		{
+			name:   "should change only the informed fields",
+			soRepo: orderWith(model.StandingOrderStatusActive, 2),
+			orderInput: StandingOrderUpdateInput{ID: orderID, AccountDestinationID: stringPtr("uuid-3"), Amount: amountPtr(250),
+				MaxOccurrences: intPtr(3)},
+			want: func(got *StandingOrderOutput) bool {
+				return got.AccountDestinationID == "uuid-3" && got.Amount.Money == 250 && got.MaxOccurrences == 3 &&
+					got.EndsAt == nil && got.Status == "active" && got.NextDueAt.Equal(startsAt)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			soUC := NewStandingOrderUseCase(tt.soRepo, nil, accRepo, nil, nil, nil, TransferLimitPolicy{}, FeePolicy{}, StandingOrderRetryPolicy{})
+			got, err := soUC.Update(backgroundCtx, caller, tt.orderInput)
+			if err != tt.wantErr {
+				t.Fatalf("Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if !tt.want(got) {
+				t.Errorf("Update() got = %+v", got)
+			}
+		})
+	}
+}
@@ -10,18 +10,42 @@ import (
 // TransferUseCase is the interface that wraps all business logic methods related to the transfers.
 type TransferUseCase interface {
 	Create(ctx context.Context, transferInput TransferCreateInput) (*TransferCreateOutput, error)
-	Fetch(ctx context.Context, accountID model.AccountID) ([]TransferFetchOutput, error)
+	Fetch(ctx context.Context, fetchInput TransferFetchInput) (*TransferFetchPageOutput, error)
+	Refund(ctx context.Context, caller model.Principal, refundInput TransferRefundInput) (*TransferCreateOutput, error)
+	Reverse(ctx context.Context, caller model.Principal, id model.TransferID) (*TransferCreateOutput, error)
+	QuoteFee(ctx context.Context, quoteInput TransferFeeQuoteInput) (*TransferFeeQuoteOutput, error)
 }
 
 type transferUseCase struct {
-	trfRepo repository.TransferRepository
-	accRepo repository.AccountRepository
+	trfRepo     repository.TransferRepository
+	accRepo     repository.AccountRepository
+	ledgerRepo  repository.LedgerRepository
+	limitRepo   repository.TransferLimitRepository
+	keyRepo     repository.PixKeyRepository
+	quoteRepo   repository.FXQuoteRepository
+	limitPolicy TransferLimitPolicy
+	feePolicy   FeePolicy
 }
 
 // NewTransferUseCase instantiates a new TransferUseCase.
-func NewTransferUseCase(trfRepo repository.TransferRepository, accRepo repository.AccountRepository) TransferUseCase {
+func NewTransferUseCase(
+	trfRepo repository.TransferRepository,
+	accRepo repository.AccountRepository,
+	ledgerRepo repository.LedgerRepository,
+	limitRepo repository.TransferLimitRepository,
+	keyRepo repository.PixKeyRepository,
+	quoteRepo repository.FXQuoteRepository,
+	limitPolicy TransferLimitPolicy,
+	feePolicy FeePolicy,
+) TransferUseCase {
 	return &transferUseCase{
-		trfRepo: trfRepo,
-		accRepo: accRepo,
+		trfRepo:     trfRepo,
+		accRepo:     accRepo,
+		ledgerRepo:  ledgerRepo,
+		limitRepo:   limitRepo,
+		keyRepo:     keyRepo,
+		quoteRepo:   quoteRepo,
+		limitPolicy: limitPolicy,
+		feePolicy:   feePolicy,
 	}
 }
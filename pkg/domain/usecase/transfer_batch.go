@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+// TransferBatchUseCase is the interface that wraps all business logic methods related to the transfer batches.
+type TransferBatchUseCase interface {
+	Create(ctx context.Context, batchInput TransferBatchCreateInput) (*TransferBatchOutput, error)
+	Get(ctx context.Context, caller model.Principal, id model.TransferBatchID) (*TransferBatchOutput, error)
+}
+
+type transferBatchUseCase struct {
+	batchRepo repository.TransferBatchRepository
+	trfUC     transferUseCase
+}
+
+// NewTransferBatchUseCase instantiates a new TransferBatchUseCase.
+// The items are made with the same logic of TransferUseCase.Create.
+func NewTransferBatchUseCase(
+	batchRepo repository.TransferBatchRepository,
+	trfRepo repository.TransferRepository,
+	accRepo repository.AccountRepository,
+	ledgerRepo repository.LedgerRepository,
+	limitRepo repository.TransferLimitRepository,
+	limitPolicy TransferLimitPolicy,
+	feePolicy FeePolicy,
+) TransferBatchUseCase {
+	return &transferBatchUseCase{
+		batchRepo: batchRepo,
+		trfUC: transferUseCase{
+			trfRepo:     trfRepo,
+			accRepo:     accRepo,
+			ledgerRepo:  ledgerRepo,
+			limitRepo:   limitRepo,
+			limitPolicy: limitPolicy,
+			feePolicy:   feePolicy,
+		},
+	}
+}
+
+// TransferBatchItemOutput represents the outcome of an item of a batch.
+type TransferBatchItemOutput struct {
+	ItemID               string `json:"item_id" example:"payroll-2021-01-0001"`
+	AccountDestinationID string `json:"account_destination_id" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
+	Amount               Amount `json:"amount" swaggertype:"number" example:"1500"`
+	Status               string `json:"status" example:"executed" enums:"executed,duplicate,rejected,skipped"`
+	TransferID           string `json:"transfer_id,omitempty" example:"e82706ef-9ffb-45a2-8081-547accd818c4"`
+	FailureReason        string `json:"failure_reason,omitempty" example:"current account balance is insufficient"`
+}
+
+// TransferBatchOutput represents a batch with the outcome of its items, in the requested order.
+type TransferBatchOutput struct {
+	ID              string                    `json:"id" example:"3f6c2a9e-8d1b-4c7e-a5f0-9b2d4e6c8a1f"`
+	AccountOriginID string                    `json:"account_origin_id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	Mode            string                    `json:"mode" example:"best_effort" enums:"atomic,best_effort"`
+	Status          string                    `json:"status" example:"partially_completed" enums:"processing,completed,partially_completed,failed"`
+	Items           []TransferBatchItemOutput `json:"items"`
+	CreatedAt       time.Time                 `json:"created_at" example:"2020-12-31T09:00:00.999999-03:00"`
+	CompletedAt     *time.Time                `json:"completed_at,omitempty" example:"2020-12-31T09:00:01.999999-03:00"`
+}
+
+func newTransferBatchOutput(batch *model.TransferBatch) TransferBatchOutput {
+	output := TransferBatchOutput{
+		ID:              string(batch.ID),
+		AccountOriginID: string(batch.AccountOriginID),
+		Mode:            string(batch.Mode),
+		Status:          string(batch.Status),
+		Items:           make([]TransferBatchItemOutput, 0, len(batch.Items)),
+		CreatedAt:       batch.CreatedAt,
+	}
+	for _, item := range batch.Items {
+		output.Items = append(output.Items, TransferBatchItemOutput{
+			ItemID:               item.ItemID,
+			AccountDestinationID: string(item.AccountDestinationID),
+			Amount:               NewAmountIn(item.Amount, batch.Currency),
+			Status:               string(item.Status),
+			TransferID:           string(item.TransferID),
+			FailureReason:        item.FailureReason,
+		})
+	}
+	if !batch.CompletedAt.IsZero() {
+		completedAt := batch.CompletedAt
+		output.CompletedAt = &completedAt
+	}
+
+	return output
+}
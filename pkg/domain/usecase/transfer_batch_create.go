@@ -0,0 +1,385 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+const (
+	// TransferBatchMaxItems is the maximum number of items of a batch.
+	TransferBatchMaxItems = 500
+	// TransferBatchItemIDMaxLength is the maximum number of characters of the item IDs.
+	TransferBatchItemIDMaxLength = 64
+)
+
+var (
+	// ErrTransferBatchModeInvalid happens when the batch mode is not known.
+	ErrTransferBatchModeInvalid = errors.New("'mode' must be 'atomic' or 'best_effort'")
+	// ErrTransferBatchItemsInvalid happens when the batch has no items or more than TransferBatchMaxItems.
+	ErrTransferBatchItemsInvalid = errors.New("'items' must have from 1 to 500 items")
+	// ErrTransferBatchItemInvalid happens when one of the items of the batch is not valid.
+	ErrTransferBatchItemInvalid = errors.New("transfer batch item is not valid")
+	// ErrTransferBatchItemIDRequired happens when the item ID is empty.
+	ErrTransferBatchItemIDRequired = errors.New("'item_id' is required")
+	// ErrTransferBatchItemIDTooLong happens when the item ID is longer than TransferBatchItemIDMaxLength.
+	ErrTransferBatchItemIDTooLong = errors.New("'item_id' must be up to 64 characters")
+	// ErrTransferBatchItemIDRepeated happens when more than one item of the batch have the same ID.
+	ErrTransferBatchItemIDRepeated = errors.New("'item_id' must be unique in the batch")
+	// ErrTransferBatchItemDestinationInvalid happens when the destination account ID of the item is not a UUID.
+	ErrTransferBatchItemDestinationInvalid = errors.New("'account_destination_id' must be a valid UUID")
+	// ErrTransferBatchItemExecutedConcurrently happens when an item of an atomic batch was executed by another batch of
+	// the origin account while it was made. The batch is rolled back and can be sent again, reporting the item as duplicate.
+	ErrTransferBatchItemExecutedConcurrently = errors.New("an item of the batch was executed by another batch at the same time, send the batch again")
+	// ErrTransferBatchCreate happens when an error occurred and the batch was not made.
+	ErrTransferBatchCreate = errors.New("could not create transfer batch")
+)
+
+// TransferBatchItemInvalidError is returned when the item at Index is not valid.
+// It matches ErrTransferBatchItemInvalid with errors.Is.
+type TransferBatchItemInvalidError struct {
+	Index int
+	Err   error
+}
+
+func (e *TransferBatchItemInvalidError) Error() string {
+	return fmt.Sprintf("items[%d]: %s", e.Index, e.Err)
+}
+
+// Is makes errors.Is(err, ErrTransferBatchItemInvalid) true.
+func (e *TransferBatchItemInvalidError) Is(target error) bool {
+	return target == ErrTransferBatchItemInvalid
+}
+
+// Unwrap returns why the item is not valid.
+func (e *TransferBatchItemInvalidError) Unwrap() error {
+	return e.Err
+}
+
+// TransferBatchItemInput represents the expected input data of each item of a batch.
+// ItemID is chosen by the client, unique among all the batches of the origin account.
+type TransferBatchItemInput struct {
+	ItemID               string `json:"item_id" example:"payroll-2021-01-0001"`
+	AccountDestinationID string `json:"account_destination_id" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
+	Amount               Amount `json:"amount" swaggertype:"number" example:"1500"`
+}
+
+// TransferBatchCreateInput represents the expected input data when creating a transfer batch.
+type TransferBatchCreateInput struct {
+	AccountOriginID string                   `json:"-"`
+	Mode            string                   `json:"mode" example:"best_effort" enums:"atomic,best_effort"`
+	Items           []TransferBatchItemInput `json:"items"`
+}
+
+// Validate validates the TransferBatchCreateInput fields.
+// The errors of the items are *TransferBatchItemInvalidError, telling which item is not valid.
+func (input *TransferBatchCreateInput) Validate() error {
+	input.AccountOriginID = strings.TrimSpace(input.AccountOriginID)
+	if len(input.AccountOriginID) < 1 {
+		return ErrTransferOriginAccountRequired
+	}
+
+	input.Mode = strings.TrimSpace(input.Mode)
+	if !model.TransferBatchMode(input.Mode).IsValid() {
+		return ErrTransferBatchModeInvalid
+	}
+
+	if len(input.Items) < 1 || len(input.Items) > TransferBatchMaxItems {
+		return ErrTransferBatchItemsInvalid
+	}
+
+	itemIDs := make(map[string]bool, len(input.Items))
+	for i := range input.Items {
+		err := input.validateItem(&input.Items[i], itemIDs)
+		if err != nil {
+			return &TransferBatchItemInvalidError{Index: i, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func (input *TransferBatchCreateInput) validateItem(item *TransferBatchItemInput, itemIDs map[string]bool) error {
+	item.ItemID = strings.TrimSpace(item.ItemID)
+	if len(item.ItemID) < 1 {
+		return ErrTransferBatchItemIDRequired
+	}
+	if utf8.RuneCountInString(item.ItemID) > TransferBatchItemIDMaxLength {
+		return ErrTransferBatchItemIDTooLong
+	}
+	if itemIDs[item.ItemID] {
+		return ErrTransferBatchItemIDRepeated
+	}
+	itemIDs[item.ItemID] = true
+
+	item.AccountDestinationID = strings.TrimSpace(item.AccountDestinationID)
+	if len(item.AccountDestinationID) < 1 {
+		return ErrTransferDestinationAccountRequired
+	}
+	// the unknown accounts are rejected when the item is made, but the malformed IDs never reach the database
+	if _, err := uuid.Parse(item.AccountDestinationID); err != nil {
+		return ErrTransferBatchItemDestinationInvalid
+	}
+
+	if item.Amount.Money <= 0 {
+		return ErrTransferAmountNotPositive
+	}
+
+	if item.AccountDestinationID == input.AccountOriginID {
+		return ErrTransferSameAccount
+	}
+
+	return nil
+}
+
+// Create validates the input and makes the transfers of the items, in the requested order, saving their outcomes.
+//
+// The items whose ID was already executed by the origin account are not made again, but reported as duplicate with
+// the transfer made then, so a batch interrupted halfway can be sent again. In the atomic mode, the items are made in
+// one transaction and the first rejected item fails the whole batch. In the best-effort mode, each item is made on
+// its own and the rejected ones are reported with the reason.
+func (batchUC transferBatchUseCase) Create(ctx context.Context, batchInput TransferBatchCreateInput) (*TransferBatchOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := batchInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("originID", batchInput.AccountOriginID).Msg("transfer batch create input is not valid")
+		return nil, err
+	}
+
+	// the amounts are in the currency of the origin account
+	origin, err := batchUC.trfUC.accRepo.GetBalance(ctx, model.AccountID(batchInput.AccountOriginID))
+	if err != nil {
+		if err == repository.ErrAccountNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("originID", batchInput.AccountOriginID).Msg("error getting transfer batch origin account")
+		return nil, ErrTransferBatchCreate
+	}
+
+	items := make([]model.TransferBatchItem, 0, len(batchInput.Items))
+	for i := range batchInput.Items {
+		amount, err := batchInput.Items[i].Amount.In(origin.Currency)
+		if err != nil {
+			return nil, &TransferBatchItemInvalidError{Index: i, Err: err}
+		}
+
+		items = append(items, model.TransferBatchItem{
+			ItemID:               batchInput.Items[i].ItemID,
+			AccountDestinationID: model.AccountID(batchInput.Items[i].AccountDestinationID),
+			Amount:               amount,
+		})
+	}
+	batch := model.NewTransferBatch(model.AccountID(batchInput.AccountOriginID), model.TransferBatchMode(batchInput.Mode), items)
+	batch.Currency = origin.Currency
+
+	err = batchUC.markDuplicates(ctx, batch)
+	if err == nil {
+		if batch.Mode == model.TransferBatchModeAtomic {
+			err = batchUC.runAtomic(ctx, batch)
+		} else {
+			err = batchUC.runBestEffort(ctx, batch)
+		}
+	}
+	if err == repository.ErrTransferBatchItemExecuted {
+		log.Ctx(ctx).Warn().Err(err).Str("id", string(batch.ID)).Str("originID", string(batch.AccountOriginID)).Msg("transfer batch item executed concurrently")
+		return nil, ErrTransferBatchItemExecutedConcurrently
+	}
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Str("id", string(batch.ID)).Str("originID", string(batch.AccountOriginID)).Msg("error processing transfer batch")
+		return nil, ErrTransferBatchCreate
+	}
+
+	log.Ctx(ctx).Info().Str("id", string(batch.ID)).Str("status", string(batch.Status)).Int("items", len(batch.Items)).Msg("transfer batch processed")
+
+	output := newTransferBatchOutput(batch)
+	return &output, nil
+}
+
+// markDuplicates marks the items already executed by the origin account as duplicate.
+func (batchUC transferBatchUseCase) markDuplicates(ctx context.Context, batch *model.TransferBatch) error {
+	itemIDs := make([]string, 0, len(batch.Items))
+	for _, item := range batch.Items {
+		itemIDs = append(itemIDs, item.ItemID)
+	}
+
+	executed, err := batchUC.batchRepo.FetchExecutedItems(ctx, batch.AccountOriginID, itemIDs)
+	if err != nil {
+		return err
+	}
+
+	transferIDs := make(map[string]model.TransferID, len(executed))
+	for _, item := range executed {
+		transferIDs[item.ItemID] = item.TransferID
+	}
+	for i := range batch.Items {
+		if transferID, ok := transferIDs[batch.Items[i].ItemID]; ok {
+			batch.Items[i].Duplicate(transferID)
+		}
+	}
+
+	return nil
+}
+
+// runAtomic makes all the items in one transaction. When one of them is rejected, the transaction is rolled back and
+// the failed batch is saved with the rejected item and the skipped ones. When one of them was executed by a concurrent
+// batch since markDuplicates, the transaction is rolled back and it returns repository.ErrTransferBatchItemExecuted.
+func (batchUC transferBatchUseCase) runAtomic(ctx context.Context, batch *model.TransferBatch) error {
+	rejectedAt := -1
+	var rejection error
+
+	_, err := batchUC.batchRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		err := batchUC.lockAccounts(txCtx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		err = batchUC.batchRepo.Create(txCtx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range batch.Items {
+			if batch.Items[i].Status != model.TransferBatchItemStatusDuplicate {
+				transfer := model.NewTransfer(string(batch.AccountOriginID), string(batch.Items[i].AccountDestinationID), batch.Items[i].Amount)
+				err = batchUC.trfUC.execute(txCtx, transfer)
+				if err != nil {
+					if isTransferRejection(err) {
+						rejectedAt, rejection = i, err
+					}
+					return nil, err
+				}
+				batch.Items[i].Executed(transfer.ID)
+			}
+
+			err = batchUC.batchRepo.SaveItem(txCtx, batch, i)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		batch.Completed()
+		return nil, batchUC.batchRepo.UpdateStatus(txCtx, batch)
+	})
+	if err == nil || rejectedAt < 0 {
+		return err
+	}
+
+	batch.Rollback(rejectedAt, rejection.Error())
+
+	_, err = batchUC.batchRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		err := batchUC.batchRepo.Create(txCtx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range batch.Items {
+			err = batchUC.batchRepo.SaveItem(txCtx, batch, i)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	})
+
+	return err
+}
+
+// lockAccounts locks all the accounts the atomic batch moves money between before any item is made, in the order
+// the transfers do (see lockAccountsRevenueLast). Locking them item by item would take the locks in the order
+// of the items, so the batches and the transfers between the same accounts in other directions could deadlock.
+// The unknown accounts are not locked, their items are rejected when they're made.
+func (batchUC transferBatchUseCase) lockAccounts(ctx context.Context, batch *model.TransferBatch) error {
+	ids := []model.AccountID{batch.AccountOriginID}
+	for _, item := range batch.Items {
+		if item.Status != model.TransferBatchItemStatusDuplicate {
+			ids = append(ids, item.AccountDestinationID)
+		}
+	}
+
+	sortLockOrder(ids, batchUC.trfUC.feePolicy.RevenueAccountID)
+	for i, id := range ids {
+		if i > 0 && id == ids[i-1] {
+			continue
+		}
+
+		_, err := batchUC.trfUC.accRepo.GetBalanceForUpdate(ctx, id)
+		if err != nil && err != repository.ErrAccountNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runBestEffort makes each item in its own transaction, saving its outcome as soon as it's known.
+// When it's interrupted, the batch stays processing with the outcomes saved so far.
+func (batchUC transferBatchUseCase) runBestEffort(ctx context.Context, batch *model.TransferBatch) error {
+	err := batchUC.batchRepo.Create(ctx, batch)
+	if err != nil {
+		return err
+	}
+
+	for i := range batch.Items {
+		if batch.Items[i].Status != model.TransferBatchItemStatusDuplicate {
+			err = batchUC.executeItem(ctx, batch, i)
+			if err == nil {
+				continue
+			}
+
+			switch {
+			case isTransferRejection(err):
+				batch.Items[i].Rejected(err.Error())
+			case err == repository.ErrTransferBatchItemExecuted:
+				// executed by a concurrent batch since markDuplicates
+				executed, err := batchUC.batchRepo.FetchExecutedItems(ctx, batch.AccountOriginID, []string{batch.Items[i].ItemID})
+				if err != nil {
+					return err
+				}
+				if len(executed) < 1 {
+					return repository.ErrTransferBatchItemExecuted
+				}
+				batch.Items[i].Duplicate(executed[0].TransferID)
+			default:
+				return err
+			}
+		}
+
+		err = batchUC.batchRepo.SaveItem(ctx, batch, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	batch.Completed()
+	return batchUC.batchRepo.UpdateStatus(ctx, batch)
+}
+
+// executeItem makes the transfer of the item at index and saves it as executed in the same transaction.
+func (batchUC transferBatchUseCase) executeItem(ctx context.Context, batch *model.TransferBatch, index int) error {
+	_, err := batchUC.batchRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		item := &batch.Items[index]
+
+		transfer := model.NewTransfer(string(batch.AccountOriginID), string(item.AccountDestinationID), item.Amount)
+		err := batchUC.trfUC.execute(txCtx, transfer)
+		if err != nil {
+			return nil, err
+		}
+
+		item.Executed(transfer.ID)
+		return nil, batchUC.batchRepo.SaveItem(txCtx, batch, index)
+	})
+
+	return err
+}
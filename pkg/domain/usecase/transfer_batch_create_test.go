@@ -0,0 +1,342 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func TestTransferBatchCreateInput_Validate(t *testing.T) {
+	t.Parallel()
+
+	originID, destinationID1, destinationID2, destinationID3 := uuid.NewString(), uuid.NewString(), uuid.NewString(), uuid.NewString()
+	item := func(itemID, destinationID string, amount model.Money) TransferBatchItemInput {
+		return TransferBatchItemInput{ItemID: itemID, AccountDestinationID: destinationID, Amount: NewAmount(amount)}
+	}
+
+	tests := []struct {
+		name      string
+		input     TransferBatchCreateInput
+		wantErr   error
+		wantIndex int
+	}{
+		{
+			name:    "unknown mode should return error",
+			input:   TransferBatchCreateInput{AccountOriginID: originID, Mode: "all", Items: []TransferBatchItemInput{item("1", destinationID1, 100)}},
+			wantErr: ErrTransferBatchModeInvalid,
+		},
+		{
+			name:    "no items should return error",
+			input:   TransferBatchCreateInput{AccountOriginID: originID, Mode: "atomic"},
+			wantErr: ErrTransferBatchItemsInvalid,
+		},
+		{
+			name:    "too many items should return error",
+			input:   TransferBatchCreateInput{AccountOriginID: originID, Mode: "atomic", Items: make([]TransferBatchItemInput, TransferBatchMaxItems+1)},
+			wantErr: ErrTransferBatchItemsInvalid,
+		},
+		{
+			name: "repeated item ID should return the item error",
+			input: TransferBatchCreateInput{AccountOriginID: originID, Mode: "best_effort", Items: []TransferBatchItemInput{
+				item("1", destinationID1, 100), item(" 1 ", destinationID2, 100)}},
+			wantErr:   ErrTransferBatchItemIDRepeated,
+			wantIndex: 1,
+		},
+		{
+			name: "long item ID should return the item error",
+			input: TransferBatchCreateInput{AccountOriginID: originID, Mode: "best_effort", Items: []TransferBatchItemInput{
+				item(strings.Repeat("a", TransferBatchItemIDMaxLength+1), destinationID1, 100)}},
+			wantErr: ErrTransferBatchItemIDTooLong,
+		},
+		{
+			name: "not positive amount should return the item error",
+			input: TransferBatchCreateInput{AccountOriginID: originID, Mode: "atomic", Items: []TransferBatchItemInput{
+				item("1", destinationID1, 100), item("2", destinationID2, 100), item("3", destinationID3, 0)}},
+			wantErr:   ErrTransferAmountNotPositive,
+			wantIndex: 2,
+		},
+		{
+			name: "destination not a UUID should return the item error",
+			input: TransferBatchCreateInput{AccountOriginID: originID, Mode: "best_effort", Items: []TransferBatchItemInput{
+				item("1", destinationID1, 100), item("2", "not-a-uuid", 100)}},
+			wantErr:   ErrTransferBatchItemDestinationInvalid,
+			wantIndex: 1,
+		},
+		{
+			name: "origin as destination should return the item error",
+			input: TransferBatchCreateInput{AccountOriginID: originID, Mode: "atomic", Items: []TransferBatchItemInput{
+				item("1", originID, 100)}},
+			wantErr: ErrTransferSameAccount,
+		},
+		{
+			name: "valid input",
+			input: TransferBatchCreateInput{AccountOriginID: originID, Mode: "atomic", Items: []TransferBatchItemInput{
+				item("1", destinationID1, 100), item("2", destinationID1, 100)}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.input.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var itemErr *TransferBatchItemInvalidError
+			if errors.As(err, &itemErr) {
+				if itemErr.Index != tt.wantIndex || !errors.Is(err, ErrTransferBatchItemInvalid) {
+					t.Errorf("Validate() error = %v, want item %d invalid", err, tt.wantIndex)
+				}
+			}
+		})
+	}
+}
+
+func Test_transferBatchUseCase_Create(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	originID, destinationID1, destinationID2, unknownID := uuid.NewString(), uuid.NewString(), uuid.NewString(), uuid.NewString()
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	// the unknown destination is rejected as not found
+	accRepo := mock.AccountRepository{
+		OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			return &model.Account{ID: id, Balance: 1000, Status: model.AccountStatusActive, Currency: model.CurrencyBRL}, nil
+		},
+		OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			if string(id) == unknownID {
+				return nil, repository.ErrAccountNotFound
+			}
+			return &model.Account{ID: id, Balance: 1000, Status: model.AccountStatusActive}, nil
+		},
+	}
+	ledgerRepoOK := mock.LedgerRepository{
+		OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+			return nil
+		},
+	}
+	noAccountLimits := mock.TransferLimitRepository{
+		OnGet: func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+			return &model.AccountTransferLimits{AccountID: accountID}, nil
+		},
+	}
+	trfRepoOK := mock.TransferRepository{
+		OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+			return nil
+		},
+	}
+	noneExecuted := func(ctx context.Context, originID model.AccountID, itemIDs []string) ([]model.TransferBatchItem, error) {
+		return []model.TransferBatchItem{}, nil
+	}
+	item := func(itemID, destinationID string) TransferBatchItemInput {
+		return TransferBatchItemInput{ItemID: itemID, AccountDestinationID: destinationID, Amount: NewAmount(100)}
+	}
+
+	tests := []struct {
+		name              string
+		input             TransferBatchCreateInput
+		fetchExecuted     func(ctx context.Context, originID model.AccountID, itemIDs []string) ([]model.TransferBatchItem, error)
+		saveItemErr       error
+		wantStatus        model.TransferBatchStatus
+		wantItemStatuses  []model.TransferBatchItemStatus
+		wantFailureReason string
+		wantErr           error
+	}{
+		{
+			name:    "invalid input should return error",
+			input:   TransferBatchCreateInput{AccountOriginID: originID, Mode: "all"},
+			wantErr: ErrTransferBatchModeInvalid,
+		},
+		{
+			name:             "atomic batch should make all the items",
+			input:            TransferBatchCreateInput{AccountOriginID: originID, Mode: "atomic", Items: []TransferBatchItemInput{item("1", destinationID1), item("2", destinationID2)}},
+			fetchExecuted:    noneExecuted,
+			wantStatus:       model.TransferBatchStatusCompleted,
+			wantItemStatuses: []model.TransferBatchItemStatus{model.TransferBatchItemStatusExecuted, model.TransferBatchItemStatusExecuted},
+		},
+		{
+			name: "atomic batch with a rejected item should fail",
+			input: TransferBatchCreateInput{AccountOriginID: originID, Mode: "atomic", Items: []TransferBatchItemInput{
+				item("1", destinationID1), item("2", unknownID), item("3", destinationID2)}},
+			fetchExecuted: noneExecuted,
+			wantStatus:    model.TransferBatchStatusFailed,
+			wantItemStatuses: []model.TransferBatchItemStatus{model.TransferBatchItemStatusSkipped, model.TransferBatchItemStatusRejected,
+				model.TransferBatchItemStatusSkipped},
+			wantFailureReason: repository.ErrAccountNotFound.Error(),
+		},
+		{
+			name: "best-effort batch with a rejected item should make the others",
+			input: TransferBatchCreateInput{AccountOriginID: originID, Mode: "best_effort", Items: []TransferBatchItemInput{
+				item("1", destinationID1), item("2", unknownID), item("3", destinationID2)}},
+			fetchExecuted: noneExecuted,
+			wantStatus:    model.TransferBatchStatusPartiallyCompleted,
+			wantItemStatuses: []model.TransferBatchItemStatus{model.TransferBatchItemStatusExecuted, model.TransferBatchItemStatusRejected,
+				model.TransferBatchItemStatusExecuted},
+			wantFailureReason: repository.ErrAccountNotFound.Error(),
+		},
+		{
+			name:  "already executed item should be duplicate",
+			input: TransferBatchCreateInput{AccountOriginID: originID, Mode: "best_effort", Items: []TransferBatchItemInput{item("1", destinationID1), item("2", destinationID2)}},
+			fetchExecuted: func(ctx context.Context, originID model.AccountID, itemIDs []string) ([]model.TransferBatchItem, error) {
+				return []model.TransferBatchItem{{ItemID: "1", Status: model.TransferBatchItemStatusExecuted, TransferID: "transfer-uuid"}}, nil
+			},
+			wantStatus:       model.TransferBatchStatusCompleted,
+			wantItemStatuses: []model.TransferBatchItemStatus{model.TransferBatchItemStatusDuplicate, model.TransferBatchItemStatusExecuted},
+		},
+		{
+			name:          "atomic batch with an item executed concurrently should return error",
+			input:         TransferBatchCreateInput{AccountOriginID: originID, Mode: "atomic", Items: []TransferBatchItemInput{item("1", destinationID1)}},
+			fetchExecuted: noneExecuted,
+			saveItemErr:   repository.ErrTransferBatchItemExecuted,
+			wantErr:       ErrTransferBatchItemExecutedConcurrently,
+		},
+		{
+			name:          "repo error should return create error",
+			input:         TransferBatchCreateInput{AccountOriginID: originID, Mode: "best_effort", Items: []TransferBatchItemInput{item("1", destinationID1)}},
+			fetchExecuted: noneExecuted,
+			saveItemErr:   errors.New("any database error"),
+			wantErr:       ErrTransferBatchCreate,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			saved := make(map[int]model.TransferBatchItem)
+			var savedStatus model.TransferBatchStatus
+			batchRepo := mock.TransferBatchRepository{
+				OnWithinTransaction:  withinTransaction,
+				OnFetchExecutedItems: tt.fetchExecuted,
+				OnCreate: func(ctx context.Context, batch *model.TransferBatch) error {
+					savedStatus = batch.Status
+					return nil
+				},
+				OnSaveItem: func(ctx context.Context, batch *model.TransferBatch, position int) error {
+					saved[position] = batch.Items[position]
+					return tt.saveItemErr
+				},
+				OnUpdateStatus: func(ctx context.Context, batch *model.TransferBatch) error {
+					savedStatus = batch.Status
+					return nil
+				},
+			}
+			batchUC := NewTransferBatchUseCase(batchRepo, trfRepoOK, accRepo, ledgerRepoOK, noAccountLimits, TransferLimitPolicy{}, FeePolicy{})
+
+			got, err := batchUC.Create(backgroundCtx, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Status != string(tt.wantStatus) || savedStatus != tt.wantStatus || got.CompletedAt == nil || len(got.Items) != len(tt.wantItemStatuses) {
+				t.Fatalf("Create() got = %v, saved status %v, want %v", got, savedStatus, tt.wantStatus)
+			}
+			for i, item := range got.Items {
+				if item.Status != string(tt.wantItemStatuses[i]) || saved[i].Status != tt.wantItemStatuses[i] {
+					t.Errorf("Create() item %d = %v, saved %v, want %v", i, item, saved[i], tt.wantItemStatuses[i])
+				}
+				if (item.TransferID != "") != (item.Status == "executed" || item.Status == "duplicate") {
+					t.Errorf("Create() item %d = %v, want transfer ID only when made", i, item)
+				}
+				if item.Status == "rejected" && item.FailureReason != tt.wantFailureReason {
+					t.Errorf("Create() item %d FailureReason = %v, want %v", i, item.FailureReason, tt.wantFailureReason)
+				}
+			}
+		})
+	}
+}
+
+func Test_transferBatchUseCase_Create_atomicLocksAllAccountsFirst(t *testing.T) {
+	t.Parallel()
+
+	originID := uuid.NewString()
+	destinationIDs := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+
+	var locked []model.AccountID
+	posted := false
+	accRepo := mock.AccountRepository{
+		OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			return &model.Account{ID: id, Balance: 1000, Status: model.AccountStatusActive}, nil
+		},
+		OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			if !posted {
+				locked = append(locked, id)
+			}
+			return &model.Account{ID: id, Balance: 1000, Status: model.AccountStatusActive}, nil
+		},
+	}
+	ledgerRepo := mock.LedgerRepository{
+		OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+			posted = true
+			return nil
+		},
+	}
+	batchRepo := mock.TransferBatchRepository{
+		OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+			return txFunc(ctx)
+		},
+		OnFetchExecutedItems: func(ctx context.Context, originID model.AccountID, itemIDs []string) ([]model.TransferBatchItem, error) {
+			return []model.TransferBatchItem{}, nil
+		},
+		OnCreate: func(ctx context.Context, batch *model.TransferBatch) error {
+			return nil
+		},
+		OnSaveItem: func(ctx context.Context, batch *model.TransferBatch, position int) error {
+			return nil
+		},
+		OnUpdateStatus: func(ctx context.Context, batch *model.TransferBatch) error {
+			return nil
+		},
+	}
+	trfRepo := mock.TransferRepository{
+		OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+			return nil
+		},
+	}
+	limitRepo := mock.TransferLimitRepository{
+		OnGet: func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+			return &model.AccountTransferLimits{AccountID: accountID}, nil
+		},
+	}
+
+	batchUC := NewTransferBatchUseCase(batchRepo, trfRepo, accRepo, ledgerRepo, limitRepo, TransferLimitPolicy{}, FeePolicy{})
+	_, err := batchUC.Create(context.Background(), TransferBatchCreateInput{AccountOriginID: originID, Mode: "atomic", Items: []TransferBatchItemInput{
+		{ItemID: "1", AccountDestinationID: destinationIDs[2], Amount: NewAmount(100)},
+		{ItemID: "2", AccountDestinationID: destinationIDs[0], Amount: NewAmount(100)},
+		{ItemID: "3", AccountDestinationID: destinationIDs[2], Amount: NewAmount(100)},
+		{ItemID: "4", AccountDestinationID: destinationIDs[1], Amount: NewAmount(100)},
+	}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// every account is locked once, lowest ID first, before the first item moves any money
+	want := []model.AccountID{model.AccountID(originID)}
+	for _, id := range destinationIDs {
+		want = append(want, model.AccountID(id))
+	}
+	sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+	if len(locked) < len(want) || !reflect.DeepEqual(locked[:len(want)], want) {
+		t.Errorf("Create() locked = %v before the first item, want %v first", locked, want)
+	}
+}
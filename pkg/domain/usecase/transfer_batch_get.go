@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrTransferBatchFetch happens when an error occurred while getting the transfer batch.
+	ErrTransferBatchFetch = errors.New("could not get transfer batch")
+)
+
+// Get returns a batch of the caller account with the outcome of its items.
+// The batches of other accounts are reported as repository.ErrTransferBatchNotFound.
+func (batchUC transferBatchUseCase) Get(ctx context.Context, caller model.Principal, id model.TransferBatchID) (*TransferBatchOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if _, err := uuid.Parse(string(id)); err != nil {
+		return nil, repository.ErrTransferBatchNotFound
+	}
+
+	batch, err := batchUC.batchRepo.GetByID(ctx, id)
+	if err == nil && batch.AccountOriginID != caller.AccountID {
+		err = repository.ErrTransferBatchNotFound
+	}
+	if err != nil {
+		if err == repository.ErrTransferBatchNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("id", string(id)).Msg("error getting transfer batch")
+		return nil, ErrTransferBatchFetch
+	}
+
+	output := newTransferBatchOutput(batch)
+	return &output, nil
+}
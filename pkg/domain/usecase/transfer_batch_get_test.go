@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_transferBatchUseCase_Get(t *testing.T) {
+	t.Parallel()
+
+	batchID := model.TransferBatchID("3f6c2a9e-8d1b-4c7e-a5f0-9b2d4e6c8a1f")
+	batchRepo := mock.TransferBatchRepository{
+		OnGetByID: func(ctx context.Context, id model.TransferBatchID) (*model.TransferBatch, error) {
+			return &model.TransferBatch{ID: id, AccountOriginID: "uuid-1", Status: model.TransferBatchStatusCompleted,
+				Items: []model.TransferBatchItem{{ItemID: "1", AccountDestinationID: "uuid-2", Amount: 100, Status: model.TransferBatchItemStatusExecuted}}}, nil
+		},
+	}
+
+	tests := []struct {
+		name      string
+		batchRepo repository.TransferBatchRepository
+		caller    model.AccountID
+		id        model.TransferBatchID
+		wantErr   error
+	}{
+		{
+			name:    "id not uuid should return not found error",
+			caller:  "uuid-1",
+			id:      "any-id",
+			wantErr: repository.ErrTransferBatchNotFound,
+		},
+		{
+			name:      "origin account should get its batch",
+			batchRepo: batchRepo,
+			caller:    "uuid-1",
+			id:        batchID,
+		},
+		{
+			name:      "batch of another account should return not found error",
+			batchRepo: batchRepo,
+			caller:    "uuid-2",
+			id:        batchID,
+			wantErr:   repository.ErrTransferBatchNotFound,
+		},
+		{
+			name: "repo error should return fetch error",
+			batchRepo: mock.TransferBatchRepository{
+				OnGetByID: func(ctx context.Context, id model.TransferBatchID) (*model.TransferBatch, error) {
+					return nil, errors.New("any database error")
+				},
+			},
+			caller:  "uuid-1",
+			id:      batchID,
+			wantErr: ErrTransferBatchFetch,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			batchUC := NewTransferBatchUseCase(tt.batchRepo, nil, nil, nil, nil, TransferLimitPolicy{}, FeePolicy{})
+
+			got, err := batchUC.Get(context.Background(), model.Principal{AccountID: tt.caller}, tt.id)
+			if err != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.ID != string(tt.id) || len(got.Items) != 1 || got.Items[0].Status != "executed") {
+				t.Errorf("Get() got = %v, want %v with its items", got, tt.id)
+			}
+		})
+	}
+}
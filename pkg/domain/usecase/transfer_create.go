@@ -3,11 +3,14 @@ package usecase
 import (
 	"context"
 	"errors"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 
+	"github.com/helder-jaspion/go-springfield-bank/pkg/brcode"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
 )
@@ -17,21 +20,56 @@ var (
 	ErrTransferOriginAccountRequired = errors.New("'account_origin_id' is required")
 	// ErrTransferDestinationAccountRequired happens when the Transfer destination account ID is not between 2-100 chars long.
 	ErrTransferDestinationAccountRequired = errors.New("'account_destination_id' is required")
+	// ErrTransferDestinationRequired happens when neither the Transfer destination account ID, key nor BR Code is informed.
+	ErrTransferDestinationRequired = errors.New("'account_destination_id', 'destination_key' or 'br_code' is required")
+	// ErrTransferDestinationAmbiguous happens when more than one of the Transfer destination account ID, key and BR Code are informed.
+	ErrTransferDestinationAmbiguous = errors.New("only one of 'account_destination_id', 'destination_key' and 'br_code' must be informed")
+	// ErrTransferDestinationKeyNotFound happens when the Transfer destination key is not an active pix key.
+	ErrTransferDestinationKeyNotFound = errors.New("destination key not found")
+	// ErrTransferBRCodeInvalid happens when the Transfer BR Code is malformed, its checksum doesn't match or it's not a Pix payment.
+	ErrTransferBRCodeInvalid = errors.New("'br_code' is not a valid Pix BR Code")
+	// ErrTransferBRCodeDynamic happens when the Transfer BR Code is dynamic, served by an URL instead of carrying the key.
+	ErrTransferBRCodeDynamic = errors.New("'br_code' must be a static BR Code, with the destination key")
+	// ErrTransferBRCodeAmountMismatch happens when the Transfer amount is not the one of the BR Code.
+	ErrTransferBRCodeAmountMismatch = errors.New("'amount' must match the amount of the 'br_code'")
 	// ErrTransferAmountNotPositive happens when the Transfer amount is less or equal to zero.
 	ErrTransferAmountNotPositive = errors.New("'amount' must be greater than zero")
 	// ErrTransferSameAccount happens when the origin and destination account IDs are the same.
 	ErrTransferSameAccount = errors.New("origin and destination accounts must not be the same")
-	// ErrAccountCurrentBalanceInsufficient happens when the origin account balance is less than the transfer amount.
+	// ErrTransferOriginAccountNotActive happens when the origin account is blocked or closed.
+	ErrTransferOriginAccountNotActive = errors.New("origin account is not active")
+	// ErrTransferDestinationAccountNotActive happens when the destination account is blocked or closed.
+	ErrTransferDestinationAccountNotActive = errors.New("destination account is not active")
+	// ErrTransferCurrencyMismatch happens when the origin and destination accounts have different currencies and
+	// no FX quote was informed.
+	ErrTransferCurrencyMismatch = errors.New("origin and destination accounts have different currencies, a 'quote_id' is required")
+	// ErrTransferFXQuoteCurrencyMismatch happens when the FX quote doesn't convert from the currency of the origin account
+	// to the currency of the destination account.
+	ErrTransferFXQuoteCurrencyMismatch = errors.New("the quote must convert from the currency of the origin account to the currency of the destination account")
+	// ErrTransferFXQuoteAmountMismatch happens when the Transfer amount is not the source amount of the FX quote.
+	ErrTransferFXQuoteAmountMismatch = errors.New("'amount' must match the source amount of the quote")
+	// ErrAccountCurrentBalanceInsufficient happens when the origin account available balance, including its credit limit,
+	// is less than the transfer amount plus its fee.
 	ErrAccountCurrentBalanceInsufficient = errors.New("current account balance is insufficient")
 	// ErrTransferCreate happens when an error occurred and the transfer was not created.
 	ErrTransferCreate = errors.New("could not create transfer")
 )
 
 // TransferCreateInput represents the expected input data when creating a transfer.
+// The destination is either the account ID, one of its pix keys, in DestinationKey, or a static Pix BR Code of
+// one of its keys, whose amount, when there is one, is the transfer amount.
+//
+// The amount is in the currency of the origin account. When the destination account has another currency, QuoteID
+// is an FX quote of the amount to the currency of the destination.
 type TransferCreateInput struct {
-	AccountOriginID      string  `json:"-"`
-	AccountDestinationID string  `json:"account_destination_id" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
-	Amount               float64 `json:"amount" example:"9999.99"`
+	AccountOriginID      string `json:"-"`
+	AccountDestinationID string `json:"account_destination_id,omitempty" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
+	DestinationKey       string `json:"destination_key,omitempty" example:"homer@springfield.com"`
+	BRCode               string `json:"br_code,omitempty" example:"00020126430014br.gov.bcb.pix0121homer@springfield.com5204000053039865802BR5917Homer Jay Simpson6011SPRINGFIELD62070503***6304120A"`
+	Amount               Amount `json:"amount" swaggertype:"number" example:"9999.99"`
+	QuoteID              string `json:"quote_id,omitempty" example:"0b7c3a2e-8d4f-4a51-9e6b-3c2d1f0a9b8e"`
+
+	brCodeTxID string
 }
 
 // Validate validates the TransferCreateInput fields.
@@ -42,11 +80,30 @@ func (input *TransferCreateInput) Validate() error {
 	}
 
 	input.AccountDestinationID = strings.TrimSpace(input.AccountDestinationID)
-	if len(input.AccountDestinationID) < 1 {
-		return ErrTransferDestinationAccountRequired
+	input.DestinationKey = strings.TrimSpace(input.DestinationKey)
+	input.BRCode = strings.TrimSpace(input.BRCode)
+	input.QuoteID = strings.TrimSpace(input.QuoteID)
+	destinations := 0
+	for _, destination := range []string{input.AccountDestinationID, input.DestinationKey, input.BRCode} {
+		if len(destination) > 0 {
+			destinations++
+		}
+	}
+	if destinations < 1 {
+		return ErrTransferDestinationRequired
+	}
+	if destinations > 1 {
+		return ErrTransferDestinationAmbiguous
+	}
+
+	if len(input.BRCode) > 0 {
+		err := input.readBRCode()
+		if err != nil {
+			return err
+		}
 	}
 
-	if input.Amount <= 0 {
+	if input.Amount.Money <= 0 {
 		return ErrTransferAmountNotPositive
 	}
 
@@ -57,26 +114,92 @@ func (input *TransferCreateInput) Validate() error {
 	return nil
 }
 
+// readBRCode sets the key and the amount of the BR Code as the destination key and the amount of the input.
+// The amounts of the BR Codes are in BRL.
+func (input *TransferCreateInput) readBRCode() error {
+	payload, err := brcode.Parse(input.BRCode)
+	if err != nil {
+		return ErrTransferBRCodeInvalid
+	}
+	if payload.IsDynamic() {
+		return ErrTransferBRCodeDynamic
+	}
+
+	if payload.Amount > 0 {
+		if input.Amount.Money != 0 && input.Amount.Money != model.Money(payload.Amount) {
+			return ErrTransferBRCodeAmountMismatch
+		}
+		input.Amount = NewAmount(model.Money(payload.Amount))
+	}
+
+	input.DestinationKey = payload.Key
+	input.brCodeTxID = payload.TxID
+
+	return nil
+}
+
 // TransferCreateOutput represents the output data of the create method.
+// RefundedAmount is only informed for the transfers of kind `transfer`, the ones that can be refunded.
+// The amounts are in the Currency of the origin account, Conversion is only informed for the cross-currency transfers.
+// Fee is only informed when the origin account was charged one, on top of the Amount.
 type TransferCreateOutput struct {
-	ID                   string    `json:"id" example:"e82706ef-9ffb-45a2-8081-547accd818c4"`
-	AccountOriginID      string    `json:"account_origin_id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
-	AccountDestinationID string    `json:"account_destination_id" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
-	Amount               float64   `json:"amount" example:"9999.99"`
-	CreatedAt            time.Time `json:"created_at" example:"2020-12-31T23:59:59.999999-03:00"`
+	ID                   string                    `json:"id" example:"e82706ef-9ffb-45a2-8081-547accd818c4"`
+	Kind                 string                    `json:"kind" example:"transfer" enums:"transfer,refund,reversal"`
+	OriginalTransferID   string                    `json:"original_transfer_id,omitempty" example:"5a4c6d2e-1f3b-4e8a-9c7d-2b1a0f9e8d7c"`
+	AccountOriginID      string                    `json:"account_origin_id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	AccountDestinationID string                    `json:"account_destination_id" example:"ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d"`
+	Currency             string                    `json:"currency" example:"BRL"`
+	Amount               Amount                    `json:"amount" swaggertype:"number" example:"9999.99"`
+	RefundedAmount       *Amount                   `json:"refunded_amount,omitempty" swaggertype:"number" example:"0"`
+	Fee                  *Amount                   `json:"fee,omitempty" swaggertype:"number" example:"1.5"`
+	Conversion           *TransferConversionOutput `json:"conversion,omitempty"`
+	CreatedAt            time.Time                 `json:"created_at" example:"2020-12-31T23:59:59.999999-03:00"`
+}
+
+// TransferConversionOutput represents how the amount of a cross-currency transfer was converted to the currency of
+// the destination account, by the rate of the quote.
+type TransferConversionOutput struct {
+	QuoteID             string `json:"quote_id" example:"0b7c3a2e-8d4f-4a51-9e6b-3c2d1f0a9b8e"`
+	Rate                string `json:"rate" example:"0.19518289"`
+	DestinationCurrency string `json:"destination_currency" example:"USD"`
+	DestinationAmount   Amount `json:"destination_amount" swaggertype:"number" example:"1951.63"`
 }
 
 func newTransferCreateOutput(transfer *model.Transfer) *TransferCreateOutput {
-	return &TransferCreateOutput{
+	output := &TransferCreateOutput{
 		ID:                   string(transfer.ID),
+		Kind:                 string(transfer.Kind),
+		OriginalTransferID:   string(transfer.OriginalTransferID),
 		AccountOriginID:      string(transfer.AccountOriginID),
 		AccountDestinationID: string(transfer.AccountDestinationID),
-		Amount:               transfer.Amount.Float64(),
+		Currency:             string(transfer.Currency),
+		Amount:               NewAmountIn(transfer.Amount, transfer.Currency),
 		CreatedAt:            transfer.CreatedAt,
 	}
+	if transfer.IsRefundable() {
+		refundedAmount := NewAmountIn(transfer.RefundedAmount, transfer.Currency)
+		output.RefundedAmount = &refundedAmount
+	}
+	if transfer.Fee > 0 {
+		fee := NewAmountIn(transfer.Fee, transfer.Currency)
+		output.Fee = &fee
+	}
+	if transfer.IsConversion() {
+		output.Conversion = &TransferConversionOutput{
+			QuoteID:             string(transfer.FXQuoteID),
+			Rate:                transfer.FXRate.String(),
+			DestinationCurrency: string(transfer.DestinationCurrency),
+			DestinationAmount:   NewAmountIn(transfer.DestinationAmount, transfer.DestinationCurrency),
+		}
+	}
+
+	return output
 }
 
-// Create validates the input, debit the amount from origin account, credit on destination account and saves the transfer.
+// Create validates the input, saves the transfer and posts it to the ledger, debiting the amount from origin account and crediting it on destination account.
+// When the destination is a pix key, or the BR Code of one, it's resolved to its account first.
+// When a quote is informed, the destination account is credited the amount converted by it instead.
+// The fee of the FeePolicy, if any, is debited from the origin account on top of the amount, within the same transaction.
 func (trfUC transferUseCase) Create(ctx context.Context, transferInput TransferCreateInput) (*TransferCreateOutput, error) {
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
@@ -87,53 +210,329 @@ func (trfUC transferUseCase) Create(ctx context.Context, transferInput TransferC
 		return nil, err
 	}
 
-	transfer := model.NewTransfer(
-		transferInput.AccountOriginID,
-		transferInput.AccountDestinationID,
-		transferInput.Amount)
-
-	_, err = trfUC.trfRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
-		err := trfUC.debitOriginAccount(txCtx, transfer)
+	if transferInput.DestinationKey != "" {
+		err = trfUC.resolveDestinationKey(ctx, &transferInput)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		err = trfUC.creditDestinationAccount(txCtx, transfer)
-		if err != nil {
+	// the currency of an account never changes, so it's read without locking the account before the transfer does
+	originAccount, err := trfUC.accRepo.GetBalance(ctx, model.AccountID(transferInput.AccountOriginID))
+	if err != nil {
+		if err == repository.ErrAccountNotFound {
 			return nil, err
 		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("originID", transferInput.AccountOriginID).Msg("error getting transfer origin account")
+		return nil, ErrTransferCreate
+	}
 
-		err = trfUC.trfRepo.Create(txCtx, transfer)
+	amount, err := transferInput.Amount.In(originAccount.Currency)
+	if err != nil {
 		return nil, err
+	}
+
+	transfer := model.NewTransfer(
+		transferInput.AccountOriginID,
+		transferInput.AccountDestinationID,
+		amount)
+
+	_, err = trfUC.trfRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		if transferInput.QuoteID != "" {
+			err := trfUC.convertByQuote(txCtx, model.FXQuoteID(transferInput.QuoteID), transfer)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, trfUC.execute(txCtx, transfer)
 	})
 	if err != nil {
-		if err == repository.ErrAccountNotFound || err == ErrAccountCurrentBalanceInsufficient {
+		if isTransferRejection(err) || isFXQuoteRejection(err) {
 			return nil, err
 		}
 		log.Ctx(ctx).Error().Stack().Err(err).Interface("transfer", transfer).Msg("error persisting new transfer")
 		return nil, ErrTransferCreate
 	}
 
+	if transferInput.BRCode != "" {
+		log.Ctx(ctx).Info().Str("id", string(transfer.ID)).Str("txid", transferInput.brCodeTxID).Msg("BR Code paid")
+	}
+
 	return newTransferCreateOutput(transfer), nil
 }
 
-func (trfUC transferUseCase) debitOriginAccount(ctx context.Context, transfer *model.Transfer) error {
-	originAccount, err := trfUC.accRepo.GetBalance(ctx, transfer.AccountOriginID)
+// resolveDestinationKey sets the account of the destination key as the destination account of the input.
+func (trfUC transferUseCase) resolveDestinationKey(ctx context.Context, transferInput *TransferCreateInput) error {
+	key, err := getActivePixKey(ctx, trfUC.keyRepo, transferInput.DestinationKey)
+	if err != nil {
+		if err == repository.ErrPixKeyNotFound {
+			return ErrTransferDestinationKeyNotFound
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Msg("error getting transfer destination key")
+		return ErrTransferCreate
+	}
+
+	transferInput.AccountDestinationID = string(key.AccountID)
+	if transferInput.AccountOriginID == transferInput.AccountDestinationID {
+		return ErrTransferSameAccount
+	}
+
+	return nil
+}
+
+// convertByQuote locks the quote, so it's used by one transfer at most, and sets its conversion to the transfer.
+// The quotes of other accounts are reported as repository.ErrFXQuoteNotFound, so their IDs are not disclosed.
+func (trfUC transferUseCase) convertByQuote(ctx context.Context, quoteID model.FXQuoteID, transfer *model.Transfer) error {
+	if _, err := uuid.Parse(string(quoteID)); err != nil {
+		return repository.ErrFXQuoteNotFound
+	}
+
+	quote, err := trfUC.quoteRepo.GetByIDForUpdate(ctx, quoteID)
 	if err != nil {
 		return err
 	}
-	if originAccount.Balance-transfer.Amount < 0 {
-		return ErrAccountCurrentBalanceInsufficient
+	if quote.AccountID != transfer.AccountOriginID {
+		return repository.ErrFXQuoteNotFound
+	}
+	if quote.IsUsed() {
+		return repository.ErrFXQuoteUsed
+	}
+	if quote.IsExpired(time.Now()) {
+		return ErrFXQuoteExpired
+	}
+	if quote.SourceAmount != transfer.Amount {
+		return ErrTransferFXQuoteAmountMismatch
 	}
 
-	return trfUC.accRepo.UpdateBalance(ctx, transfer.AccountOriginID, originAccount.Balance-transfer.Amount)
+	transfer.ConvertBy(quote)
+	return nil
 }
 
-func (trfUC transferUseCase) creditDestinationAccount(ctx context.Context, transfer *model.Transfer) error {
-	destinationAccount, err := trfUC.accRepo.GetBalance(ctx, transfer.AccountDestinationID)
+// isFXQuoteRejection checks whether the error is the FX quote of the transfer not being usable, not a failure.
+func isFXQuoteRejection(err error) bool {
+	switch err {
+	case repository.ErrFXQuoteNotFound, repository.ErrFXQuoteUsed, ErrFXQuoteExpired, ErrTransferFXQuoteAmountMismatch:
+		return true
+	default:
+		return false
+	}
+}
+
+// execute moves the money of the transfer, charges its fee and saves it. It must run within a transaction.
+//
+// The accounts and the limits are checked before anything is written, so when the transfer is rejected
+// (see isTransferRejection) the transaction can still be used.
+func (trfUC transferUseCase) execute(ctx context.Context, transfer *model.Transfer) error {
+	// refunds and reversals give money back, so they are neither limited nor charged
+	chargesFee := transfer.Kind == model.TransferKindTransfer && trfUC.feePolicy.chargesTransfers()
+
+	accounts, err := lockAccountsRevenueLast(ctx, trfUC.accRepo, trfUC.feePolicy.RevenueAccountID,
+		transfer.AccountOriginID, transfer.AccountDestinationID)
 	if err != nil {
 		return err
 	}
+	originAccount, destinationAccount := accounts[0], accounts[1]
 
-	return trfUC.accRepo.UpdateBalance(ctx, transfer.AccountDestinationID, destinationAccount.Balance+transfer.Amount)
+	if !originAccount.IsActive() {
+		return ErrTransferOriginAccountNotActive
+	}
+	if !destinationAccount.IsActive() {
+		return ErrTransferDestinationAccountNotActive
+	}
+
+	err = checkCurrencies(transfer, originAccount, destinationAccount)
+	if err != nil {
+		return err
+	}
+
+	if transfer.Kind == model.TransferKindTransfer {
+		err = trfUC.checkLimits(ctx, transfer)
+		if err != nil {
+			return err
+		}
+	}
+
+	if chargesFee {
+		// the revenue account is only read, the fee is credited by the ledger posting, so the transfers charging
+		// their fees to it are not serialized on its lock
+		revenueAccount, err := trfUC.accRepo.GetBalance(ctx, trfUC.feePolicy.RevenueAccountID)
+		if err != nil {
+			return err
+		}
+
+		transfer.Fee, err = trfUC.transferFee(ctx, originAccount, revenueAccount, transfer.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = trfUC.postTransfer(ctx, originAccount, transfer)
+	if err != nil {
+		return err
+	}
+
+	return trfUC.trfRepo.Create(ctx, transfer)
+}
+
+// isTransferRejection checks whether the error is a business rule rejecting the transfer, not a failure.
+func isTransferRejection(err error) bool {
+	switch err {
+	case repository.ErrAccountNotFound, ErrAccountCurrentBalanceInsufficient,
+		ErrTransferOriginAccountNotActive, ErrTransferDestinationAccountNotActive,
+		ErrTransferCurrencyMismatch, ErrTransferFXQuoteCurrencyMismatch:
+		return true
+	default:
+		return errors.Is(err, ErrTransferLimitExceeded)
+	}
+}
+
+// checkCurrencies checks the transfer is in the currencies of its accounts. The accounts of the same currency transfer
+// the amount as is, the others only by an FX quote from the currency of the origin to the currency of the destination.
+func checkCurrencies(transfer *model.Transfer, originAccount, destinationAccount *model.Account) error {
+	if !transfer.IsConversion() {
+		if originAccount.Currency != destinationAccount.Currency {
+			return ErrTransferCurrencyMismatch
+		}
+
+		transfer.InCurrency(originAccount.Currency)
+		return nil
+	}
+
+	if transfer.Currency != originAccount.Currency || transfer.DestinationCurrency != destinationAccount.Currency {
+		return ErrTransferFXQuoteCurrencyMismatch
+	}
+
+	return nil
+}
+
+// lockAccountPair locks both accounts until the end of the transaction, see lockAccounts.
+func lockAccountPair(ctx context.Context, accRepo repository.AccountRepository, firstID, secondID model.AccountID) (first *model.Account, second *model.Account, err error) {
+	accounts, err := lockAccounts(ctx, accRepo, firstID, secondID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return accounts[0], accounts[1], nil
+}
+
+// lockAccounts locks the accounts until the end of the transaction, returning them in the order of the IDs.
+// The rows are always locked in the same order (lowest ID first), so concurrent transfers between the same accounts
+// in opposite directions (A->B and B->A) can not deadlock.
+// A repeated ID is locked once, returning the same account.
+func lockAccounts(ctx context.Context, accRepo repository.AccountRepository, ids ...model.AccountID) ([]*model.Account, error) {
+	return lockAccountsRevenueLast(ctx, accRepo, "", ids...)
+}
+
+// lockAccountsRevenueLast works like lockAccounts, but locks the revenue account after all the others, whatever its
+// ID. The fees credited to it update its row at the end of the transactions without locking it first,
+// so a transfer to the revenue account locking it before the other accounts could deadlock with them.
+func lockAccountsRevenueLast(ctx context.Context, accRepo repository.AccountRepository, revenueID model.AccountID, ids ...model.AccountID) ([]*model.Account, error) {
+	sortedIDs := append([]model.AccountID(nil), ids...)
+	sortLockOrder(sortedIDs, revenueID)
+
+	locked := make(map[model.AccountID]*model.Account, len(ids))
+	for _, id := range sortedIDs {
+		if _, ok := locked[id]; ok {
+			continue
+		}
+
+		account, err := accRepo.GetBalanceForUpdate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		locked[id] = account
+	}
+
+	accounts := make([]*model.Account, 0, len(ids))
+	for _, id := range ids {
+		accounts = append(accounts, locked[id])
+	}
+
+	return accounts, nil
+}
+
+// sortLockOrder sorts the account IDs in the order they're locked, lowest ID first and the revenue account last.
+func sortLockOrder(ids []model.AccountID, revenueID model.AccountID) {
+	sort.Slice(ids, func(i, j int) bool {
+		if (ids[i] == revenueID) != (ids[j] == revenueID) {
+			return ids[j] == revenueID
+		}
+		return ids[i] < ids[j]
+	})
+}
+
+// postTransfer checks the origin account has enough balance for the amount and the fee and posts both to the ledger.
+// A cross-currency transfer is posted in two steps through the model.LedgerFXAccountID, one in each currency.
+func (trfUC transferUseCase) postTransfer(ctx context.Context, originAccount *model.Account, transfer *model.Transfer) error {
+	err := ensureSufficientBalance(originAccount, transfer.Amount+transfer.Fee)
+	if err != nil {
+		return err
+	}
+
+	err = trfUC.postAmount(ctx, transfer)
+	if err != nil {
+		return err
+	}
+
+	if transfer.Fee <= 0 {
+		return nil
+	}
+
+	fee := model.NewLedgerPosting(
+		model.LedgerPostingTransferFee,
+		string(transfer.ID),
+		transfer.AccountOriginID,
+		trfUC.feePolicy.RevenueAccountID,
+		transfer.Fee,
+		transfer.Currency)
+
+	return trfUC.ledgerRepo.Post(ctx, fee)
+}
+
+// postAmount posts the amount of the transfer from the origin account to the destination account.
+func (trfUC transferUseCase) postAmount(ctx context.Context, transfer *model.Transfer) error {
+	if !transfer.IsConversion() {
+		posting := model.NewLedgerPosting(
+			transfer.Kind.LedgerPostingKind(),
+			string(transfer.ID),
+			transfer.AccountOriginID,
+			transfer.AccountDestinationID,
+			transfer.Amount,
+			transfer.Currency)
+
+		return trfUC.ledgerRepo.Post(ctx, posting)
+	}
+
+	debit := model.NewLedgerPosting(
+		transfer.Kind.LedgerPostingKind(),
+		string(transfer.ID),
+		transfer.AccountOriginID,
+		model.LedgerFXAccountID,
+		transfer.Amount,
+		transfer.Currency)
+	err := trfUC.ledgerRepo.Post(ctx, debit)
+	if err != nil {
+		return err
+	}
+
+	credit := model.NewLedgerPosting(
+		transfer.Kind.LedgerPostingKind(),
+		string(transfer.ID),
+		model.LedgerFXAccountID,
+		transfer.AccountDestinationID,
+		transfer.DestinationAmount,
+		transfer.DestinationCurrency)
+
+	return trfUC.ledgerRepo.Post(ctx, credit)
+}
+
+// ensureSufficientBalance checks the account has enough balance to be debited the amount.
+// The balance can go below zero down to the account credit limit.
+func ensureSufficientBalance(account *model.Account, amount model.Money) error {
+	if account.AvailableBalance()-amount < 0 {
+		return ErrAccountCurrentBalanceInsufficient
+	}
+
+	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/helder-jaspion/go-springfield-bank/pkg/brcode"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
@@ -15,21 +16,37 @@ import (
 func TestTransferCreateInput_Validate(t *testing.T) {
 	t.Parallel()
 
+	encode := func(payload brcode.Payload) string {
+		payload.MerchantName, payload.MerchantCity = "Homer Jay Simpson", "SPRINGFIELD"
+		code, err := brcode.Encode(payload)
+		if err != nil {
+			t.Fatalf("brcode.Encode() error = %v", err)
+		}
+		return code
+	}
+	staticCode := encode(brcode.Payload{Key: "homer@springfield.com", TxID: "DONUTS42"})
+	staticCodeWithAmount := encode(brcode.Payload{Key: "homer@springfield.com", Amount: 1050})
+	dynamicCode := encode(brcode.Payload{URL: "pix.springfield.com/qr/v2/9d36b84f", Amount: 1050})
+
 	type fields struct {
 		AccountOriginID      string
 		AccountDestinationID string
-		Amount               float64
+		DestinationKey       string
+		BRCode               string
+		Amount               Amount
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		wantErr error
+		name               string
+		fields             fields
+		wantDestinationKey string
+		wantAmount         model.Money
+		wantErr            error
 	}{
 		{
 			name: "empty origin account should return error",
 			fields: fields{
 				AccountDestinationID: "uuid-2",
-				Amount:               10,
+				Amount:               NewAmount(1000),
 			},
 			wantErr: ErrTransferOriginAccountRequired,
 		},
@@ -37,16 +54,26 @@ func TestTransferCreateInput_Validate(t *testing.T) {
 			name: "empty destination account should return error",
 			fields: fields{
 				AccountOriginID: "uuid-1",
-				Amount:          10,
+				Amount:          NewAmount(1000),
+			},
+			wantErr: ErrTransferDestinationRequired,
+		},
+		{
+			name: "both destination account and key should return error",
+			fields: fields{
+				AccountOriginID:      "uuid-1",
+				AccountDestinationID: "uuid-2",
+				DestinationKey:       "homer@springfield.com",
+				Amount:               NewAmount(1000),
 			},
-			wantErr: ErrTransferDestinationAccountRequired,
+			wantErr: ErrTransferDestinationAmbiguous,
 		},
 		{
 			name: "zero amount should return error",
 			fields: fields{
 				AccountOriginID:      "uuid-1",
 				AccountDestinationID: "uuid-2",
-				Amount:               0,
+				Amount:               NewAmount(0),
 			},
 			wantErr: ErrTransferAmountNotPositive,
 		},
@@ -55,7 +82,7 @@ func TestTransferCreateInput_Validate(t *testing.T) {
 			fields: fields{
 				AccountOriginID:      "uuid-1",
 				AccountDestinationID: "uuid-2",
-				Amount:               -1,
+				Amount:               NewAmount(-100),
 			},
 			wantErr: ErrTransferAmountNotPositive,
 		},
@@ -64,7 +91,7 @@ func TestTransferCreateInput_Validate(t *testing.T) {
 			fields: fields{
 				AccountOriginID:      "uuid-1",
 				AccountDestinationID: "uuid-1",
-				Amount:               10,
+				Amount:               NewAmount(1000),
 			},
 			wantErr: ErrTransferSameAccount,
 		},
@@ -73,20 +100,113 @@ func TestTransferCreateInput_Validate(t *testing.T) {
 			fields: fields{
 				AccountOriginID:      "uuid-1",
 				AccountDestinationID: "uuid-2",
-				Amount:               10,
+				Amount:               NewAmount(1000),
 			},
 			wantErr: nil,
 		},
+		{
+			name: "success with destination key",
+			fields: fields{
+				AccountOriginID: "uuid-1",
+				DestinationKey:  "homer@springfield.com",
+				Amount:          NewAmount(1000),
+			},
+			wantDestinationKey: "homer@springfield.com",
+			wantAmount:         1000,
+			wantErr:            nil,
+		},
+		{
+			name: "both destination key and BR Code should return error",
+			fields: fields{
+				AccountOriginID: "uuid-1",
+				DestinationKey:  "homer@springfield.com",
+				BRCode:          staticCode,
+				Amount:          NewAmount(1000),
+			},
+			wantErr: ErrTransferDestinationAmbiguous,
+		},
+		{
+			name: "BR Code with wrong checksum should return error",
+			fields: fields{
+				AccountOriginID: "uuid-1",
+				BRCode:          staticCode[:len(staticCode)-4] + "0000",
+				Amount:          NewAmount(1000),
+			},
+			wantErr: ErrTransferBRCodeInvalid,
+		},
+		{
+			name: "dynamic BR Code should return error",
+			fields: fields{
+				AccountOriginID: "uuid-1",
+				BRCode:          dynamicCode,
+			},
+			wantErr: ErrTransferBRCodeDynamic,
+		},
+		{
+			name: "amount other than the BR Code one should return error",
+			fields: fields{
+				AccountOriginID: "uuid-1",
+				BRCode:          staticCodeWithAmount,
+				Amount:          NewAmount(1000),
+			},
+			wantErr: ErrTransferBRCodeAmountMismatch,
+		},
+		{
+			name: "BR Code without amount requires the amount",
+			fields: fields{
+				AccountOriginID: "uuid-1",
+				BRCode:          staticCode,
+			},
+			wantErr: ErrTransferAmountNotPositive,
+		},
+		{
+			name: "success with BR Code",
+			fields: fields{
+				AccountOriginID: "uuid-1",
+				BRCode:          " " + staticCode + "\n",
+				Amount:          NewAmount(1000),
+			},
+			wantDestinationKey: "homer@springfield.com",
+			wantAmount:         1000,
+			wantErr:            nil,
+		},
+		{
+			name: "success with the amount of the BR Code",
+			fields: fields{
+				AccountOriginID: "uuid-1",
+				BRCode:          staticCodeWithAmount,
+			},
+			wantDestinationKey: "homer@springfield.com",
+			wantAmount:         1050,
+			wantErr:            nil,
+		},
+		{
+			name: "success with the same amount of the BR Code",
+			fields: fields{
+				AccountOriginID: "uuid-1",
+				BRCode:          staticCodeWithAmount,
+				Amount:          NewAmount(1050),
+			},
+			wantDestinationKey: "homer@springfield.com",
+			wantAmount:         1050,
+			wantErr:            nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			input := &TransferCreateInput{
 				AccountOriginID:      tt.fields.AccountOriginID,
 				AccountDestinationID: tt.fields.AccountDestinationID,
+				DestinationKey:       tt.fields.DestinationKey,
+				BRCode:               tt.fields.BRCode,
 				Amount:               tt.fields.Amount,
 			}
 			if err := input.Validate(); err != tt.wantErr {
 				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantDestinationKey != "" && (input.DestinationKey != tt.wantDestinationKey || input.Amount.Money != tt.wantAmount) {
+				t.Errorf("Validate() destination key = %v and amount = %v, want %v and %v", input.DestinationKey, input.Amount.Money, tt.wantDestinationKey, tt.wantAmount)
 			}
 		})
 	}
@@ -98,8 +218,25 @@ func Test_transferUseCase_Create(t *testing.T) {
 	backgroundCtx := context.Background()
 
 	type fields struct {
-		trfRepo repository.TransferRepository
-		accRepo repository.AccountRepository
+		trfRepo    repository.TransferRepository
+		accRepo    repository.AccountRepository
+		ledgerRepo repository.LedgerRepository
+		keyRepo    repository.PixKeyRepository
+	}
+
+	ledgerRepo := mock.LedgerRepository{
+		OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+			return nil
+		},
+	}
+	noAccountLimits := mock.TransferLimitRepository{
+		OnGet: func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+			return &model.AccountTransferLimits{AccountID: accountID}, nil
+		},
+	}
+	// the origin is read for its currency before the transfer locks the accounts
+	getOrigin := func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+		return &model.Account{ID: id, Status: model.AccountStatusActive}, nil
 	}
 	type args struct {
 		ctx           context.Context
@@ -121,12 +258,13 @@ func Test_transferUseCase_Create(t *testing.T) {
 					},
 				},
 				accRepo: mock.AccountRepository{
-					OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
 						if id == "uuid-1" {
-							return &model.Account{Balance: 0}, nil
+							return &model.Account{Balance: 0, Status: model.AccountStatusActive}, nil
 						}
 						if id == "uuid-2" {
-							return &model.Account{Balance: 10}, nil
+							return &model.Account{Balance: 10, Status: model.AccountStatusActive}, nil
 						}
 
 						return nil, errors.New("account not found")
@@ -138,7 +276,7 @@ func Test_transferUseCase_Create(t *testing.T) {
 				transferInput: TransferCreateInput{
 					AccountOriginID:      "uuid-1",
 					AccountDestinationID: "uuid-2",
-					Amount:               0,
+					Amount:               NewAmount(0),
 				},
 			},
 			want:    nil,
@@ -153,12 +291,13 @@ func Test_transferUseCase_Create(t *testing.T) {
 					},
 				},
 				accRepo: mock.AccountRepository{
-					OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
 						if id == "uuid-1" {
-							return &model.Account{Balance: 0}, nil
+							return &model.Account{Balance: 0, Status: model.AccountStatusActive}, nil
 						}
 						if id == "uuid-2" {
-							return &model.Account{Balance: 10}, nil
+							return &model.Account{Balance: 10, Status: model.AccountStatusActive}, nil
 						}
 
 						return nil, errors.New("account not found")
@@ -170,7 +309,7 @@ func Test_transferUseCase_Create(t *testing.T) {
 				transferInput: TransferCreateInput{
 					AccountOriginID:      "uuid-1",
 					AccountDestinationID: "uuid-2",
-					Amount:               1,
+					Amount:               NewAmount(100),
 				},
 			},
 			want:    nil,
@@ -185,12 +324,13 @@ func Test_transferUseCase_Create(t *testing.T) {
 					},
 				},
 				accRepo: mock.AccountRepository{
-					OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
 						if id == "uuid-1" {
-							return &model.Account{Balance: 10}, nil
+							return &model.Account{Balance: 10, Status: model.AccountStatusActive}, nil
 						}
 						if id == "uuid-2" {
-							return &model.Account{Balance: 10}, nil
+							return &model.Account{Balance: 10, Status: model.AccountStatusActive}, nil
 						}
 
 						return nil, errors.New("account not found")
@@ -202,7 +342,7 @@ func Test_transferUseCase_Create(t *testing.T) {
 				transferInput: TransferCreateInput{
 					AccountOriginID:      "uuid-1",
 					AccountDestinationID: "uuid-2",
-					Amount:               10.01,
+					Amount:               NewAmount(1001),
 				},
 			},
 			want:    nil,
@@ -217,9 +357,10 @@ func Test_transferUseCase_Create(t *testing.T) {
 					},
 				},
 				accRepo: mock.AccountRepository{
-					OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
 						if id == "uuid-2" {
-							return &model.Account{Balance: 1000}, nil
+							return &model.Account{Balance: 1000, Status: model.AccountStatusActive}, nil
 						}
 
 						return nil, repository.ErrAccountNotFound
@@ -231,7 +372,7 @@ func Test_transferUseCase_Create(t *testing.T) {
 				transferInput: TransferCreateInput{
 					AccountOriginID:      "uuid-1",
 					AccountDestinationID: "uuid-2",
-					Amount:               1,
+					Amount:               NewAmount(100),
 				},
 			},
 			want:    nil,
@@ -246,24 +387,23 @@ func Test_transferUseCase_Create(t *testing.T) {
 					},
 				},
 				accRepo: mock.AccountRepository{
-					OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
 						if id == "uuid-1" {
-							return &model.Account{Balance: 1000}, nil
+							return &model.Account{Balance: 1000, Status: model.AccountStatusActive}, nil
 						}
 
 						return nil, repository.ErrAccountNotFound
 					},
-					OnUpdateBalance: func(ctx context.Context, id model.AccountID, balance model.Money) error {
-						return nil
-					},
 				},
+				ledgerRepo: ledgerRepo,
 			},
 			args: args{
 				ctx: backgroundCtx,
 				transferInput: TransferCreateInput{
 					AccountOriginID:      "uuid-1",
 					AccountDestinationID: "uuid-2",
-					Amount:               1,
+					Amount:               NewAmount(100),
 				},
 			},
 			want:    nil,
@@ -281,18 +421,48 @@ func Test_transferUseCase_Create(t *testing.T) {
 					},
 				},
 				accRepo: mock.AccountRepository{
-					OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
 						if id == "uuid-1" {
-							return &model.Account{Balance: 1000}, nil
+							return &model.Account{Balance: 1000, Status: model.AccountStatusActive}, nil
 						}
 						if id == "uuid-2" {
-							return &model.Account{Balance: 1000}, nil
+							return &model.Account{Balance: 1000, Status: model.AccountStatusActive}, nil
 						}
 
 						return nil, repository.ErrAccountNotFound
 					},
-					OnUpdateBalance: func(ctx context.Context, id model.AccountID, balance model.Money) error {
-						return nil
+				},
+				ledgerRepo: ledgerRepo,
+			},
+			args: args{
+				ctx: backgroundCtx,
+				transferInput: TransferCreateInput{
+					AccountOriginID:      "uuid-1",
+					AccountDestinationID: "uuid-2",
+					Amount:               NewAmount(100),
+				},
+			},
+			want:    nil,
+			wantErr: ErrTransferCreate,
+		},
+		{
+			name: "ledger post error should return error",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+						return txFunc(ctx)
+					},
+				},
+				accRepo: mock.AccountRepository{
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return &model.Account{ID: id, Balance: 1000, Status: model.AccountStatusActive}, nil
+					},
+				},
+				ledgerRepo: mock.LedgerRepository{
+					OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+						return errors.New("any error")
 					},
 				},
 			},
@@ -301,14 +471,119 @@ func Test_transferUseCase_Create(t *testing.T) {
 				transferInput: TransferCreateInput{
 					AccountOriginID:      "uuid-1",
 					AccountDestinationID: "uuid-2",
-					Amount:               1,
+					Amount:               NewAmount(100),
 				},
 			},
 			want:    nil,
 			wantErr: ErrTransferCreate,
 		},
 		{
-			name: "success",
+			name: "blocked origin account should return error",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+						return txFunc(ctx)
+					},
+				},
+				accRepo: mock.AccountRepository{
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						if id == "uuid-1" {
+							return &model.Account{Balance: 1000, Status: model.AccountStatusBlocked}, nil
+						}
+						if id == "uuid-2" {
+							return &model.Account{Balance: 1000, Status: model.AccountStatusActive}, nil
+						}
+
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+				ledgerRepo: ledgerRepo,
+			},
+			args: args{
+				ctx: backgroundCtx,
+				transferInput: TransferCreateInput{
+					AccountOriginID:      "uuid-1",
+					AccountDestinationID: "uuid-2",
+					Amount:               NewAmount(199),
+				},
+			},
+			want:    nil,
+			wantErr: ErrTransferOriginAccountNotActive,
+		},
+		{
+			name: "closed destination account should return error",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+						return txFunc(ctx)
+					},
+				},
+				accRepo: mock.AccountRepository{
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						if id == "uuid-1" {
+							return &model.Account{Balance: 1000, Status: model.AccountStatusActive}, nil
+						}
+						if id == "uuid-2" {
+							return &model.Account{Balance: 1000, Status: model.AccountStatusClosed}, nil
+						}
+
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+				ledgerRepo: ledgerRepo,
+			},
+			args: args{
+				ctx: backgroundCtx,
+				transferInput: TransferCreateInput{
+					AccountOriginID:      "uuid-1",
+					AccountDestinationID: "uuid-2",
+					Amount:               NewAmount(199),
+				},
+			},
+			want:    nil,
+			wantErr: ErrTransferDestinationAccountNotActive,
+		},
+		{
+			name: "origin account overdraft beyond the credit limit should return error",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+						return txFunc(ctx)
+					},
+					OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+						return nil
+					},
+				},
+				accRepo: mock.AccountRepository{
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						if id == "uuid-1" {
+							return &model.Account{Balance: -100, CreditLimit: 1000, Status: model.AccountStatusActive}, nil
+						}
+						if id == "uuid-2" {
+							return &model.Account{Balance: 1000, Status: model.AccountStatusActive}, nil
+						}
+
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+				ledgerRepo: ledgerRepo,
+			},
+			args: args{
+				ctx: backgroundCtx,
+				transferInput: TransferCreateInput{
+					AccountOriginID:      "uuid-1",
+					AccountDestinationID: "uuid-2",
+					Amount:               NewAmount(901),
+				},
+			},
+			want:    nil,
+			wantErr: ErrAccountCurrentBalanceInsufficient,
+		},
+		{
+			name: "origin account overdraft within the credit limit should succeed",
 			fields: fields{
 				trfRepo: mock.TransferRepository{
 					OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
@@ -319,40 +594,231 @@ func Test_transferUseCase_Create(t *testing.T) {
 					},
 				},
 				accRepo: mock.AccountRepository{
-					OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
 						if id == "uuid-1" {
-							return &model.Account{Balance: 1000}, nil
+							return &model.Account{Balance: -100, CreditLimit: 1000, Status: model.AccountStatusActive}, nil
 						}
 						if id == "uuid-2" {
-							return &model.Account{Balance: 1000}, nil
+							return &model.Account{Balance: 1000, Status: model.AccountStatusActive}, nil
 						}
 
 						return nil, repository.ErrAccountNotFound
 					},
-					OnUpdateBalance: func(ctx context.Context, id model.AccountID, balance model.Money) error {
+				},
+				ledgerRepo: ledgerRepo,
+			},
+			args: args{
+				ctx: backgroundCtx,
+				transferInput: TransferCreateInput{
+					AccountOriginID:      "uuid-1",
+					AccountDestinationID: "uuid-2",
+					Amount:               NewAmount(900),
+				},
+			},
+			want: &TransferCreateOutput{
+				Kind:                 "transfer",
+				AccountOriginID:      "uuid-1",
+				AccountDestinationID: "uuid-2",
+				Amount:               NewAmount(900),
+				RefundedAmount:       &Amount{},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+						return txFunc(ctx)
+					},
+					OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
 						return nil
 					},
 				},
+				accRepo: mock.AccountRepository{
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						if id == "uuid-1" {
+							return &model.Account{Balance: 1000, Status: model.AccountStatusActive}, nil
+						}
+						if id == "uuid-2" {
+							return &model.Account{Balance: 1000, Status: model.AccountStatusActive}, nil
+						}
+
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+				ledgerRepo: ledgerRepo,
 			},
 			args: args{
 				ctx: backgroundCtx,
 				transferInput: TransferCreateInput{
 					AccountOriginID:      "uuid-1",
 					AccountDestinationID: "uuid-2",
-					Amount:               1.99,
+					Amount:               NewAmount(199),
 				},
 			},
 			want: &TransferCreateOutput{
+				Kind:                 "transfer",
 				AccountOriginID:      "uuid-1",
 				AccountDestinationID: "uuid-2",
-				Amount:               1.99,
+				Amount:               NewAmount(199),
+				RefundedAmount:       &Amount{},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "destination key not found should return error",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnGetByValue: func(ctx context.Context, value string) (*model.PixKey, error) {
+						return nil, repository.ErrPixKeyNotFound
+					},
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				transferInput: TransferCreateInput{
+					AccountOriginID: "uuid-1",
+					DestinationKey:  "homer@springfield.com",
+					Amount:          NewAmount(199),
+				},
+			},
+			want:    nil,
+			wantErr: ErrTransferDestinationKeyNotFound,
+		},
+		{
+			name: "pending destination key should return error",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnGetByValue: func(ctx context.Context, value string) (*model.PixKey, error) {
+						return &model.PixKey{AccountID: "uuid-2", Type: model.PixKeyTypeEmail, Value: value, Status: model.PixKeyStatusPending}, nil
+					},
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				transferInput: TransferCreateInput{
+					AccountOriginID: "uuid-1",
+					DestinationKey:  "homer@springfield.com",
+					Amount:          NewAmount(199),
+				},
+			},
+			want:    nil,
+			wantErr: ErrTransferDestinationKeyNotFound,
+		},
+		{
+			name: "destination key of the origin account should return error",
+			fields: fields{
+				keyRepo: mock.PixKeyRepository{
+					OnGetByValue: func(ctx context.Context, value string) (*model.PixKey, error) {
+						return &model.PixKey{AccountID: "uuid-1", Type: model.PixKeyTypeEmail, Value: value, Status: model.PixKeyStatusActive}, nil
+					},
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				transferInput: TransferCreateInput{
+					AccountOriginID: "uuid-1",
+					DestinationKey:  "homer@springfield.com",
+					Amount:          NewAmount(199),
+				},
+			},
+			want:    nil,
+			wantErr: ErrTransferSameAccount,
+		},
+		{
+			name: "success with destination key",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+						return txFunc(ctx)
+					},
+					OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+						return nil
+					},
+				},
+				accRepo: mock.AccountRepository{
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return &model.Account{ID: id, Balance: 1000, Status: model.AccountStatusActive}, nil
+					},
+				},
+				ledgerRepo: ledgerRepo,
+				keyRepo: mock.PixKeyRepository{
+					OnGetByValue: func(ctx context.Context, value string) (*model.PixKey, error) {
+						if value != "+5511987654321" {
+							return nil, repository.ErrPixKeyNotFound
+						}
+						return &model.PixKey{AccountID: "uuid-2", Type: model.PixKeyTypePhone, Value: value, Status: model.PixKeyStatusActive}, nil
+					},
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				transferInput: TransferCreateInput{
+					AccountOriginID: "uuid-1",
+					DestinationKey:  "+55 (11) 98765-4321",
+					Amount:          NewAmount(199),
+				},
+			},
+			want: &TransferCreateOutput{
+				Kind:                 "transfer",
+				AccountOriginID:      "uuid-1",
+				AccountDestinationID: "uuid-2",
+				Amount:               NewAmount(199),
+				RefundedAmount:       &Amount{},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success with the amount of the BR Code",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+						return txFunc(ctx)
+					},
+					OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+						return nil
+					},
+				},
+				accRepo: mock.AccountRepository{
+					OnGetBalance: getOrigin,
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return &model.Account{ID: id, Balance: 2000, Status: model.AccountStatusActive}, nil
+					},
+				},
+				ledgerRepo: ledgerRepo,
+				keyRepo: mock.PixKeyRepository{
+					OnGetByValue: func(ctx context.Context, value string) (*model.PixKey, error) {
+						if value != "homer@springfield.com" {
+							return nil, repository.ErrPixKeyNotFound
+						}
+						return &model.PixKey{AccountID: "uuid-2", Type: model.PixKeyTypeEmail, Value: value, Status: model.PixKeyStatusActive}, nil
+					},
+				},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				transferInput: TransferCreateInput{
+					AccountOriginID: "uuid-1",
+					BRCode:          "00020126430014br.gov.bcb.pix0121homer@springfield.com520400005303986540510.505802BR5917Homer Jay Simpson6011SPRINGFIELD62120508DONUTS4263048175",
+				},
+			},
+			want: &TransferCreateOutput{
+				Kind:                 "transfer",
+				AccountOriginID:      "uuid-1",
+				AccountDestinationID: "uuid-2",
+				Amount:               NewAmount(1050),
+				RefundedAmount:       &Amount{},
 			},
 			wantErr: nil,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			trfUC := NewTransferUseCase(tt.fields.trfRepo, tt.fields.accRepo)
+			trfUC := NewTransferUseCase(tt.fields.trfRepo, tt.fields.accRepo, tt.fields.ledgerRepo, noAccountLimits, tt.fields.keyRepo, nil, TransferLimitPolicy{}, FeePolicy{})
 
 			got, err := trfUC.Create(tt.args.ctx, tt.args.transferInput)
 			if err != tt.wantErr {
@@ -380,3 +846,371 @@ func Test_transferUseCase_Create(t *testing.T) {
 		})
 	}
 }
+
+func Test_transferUseCase_Create_currencies(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	const (
+		quoteID        = "0b7c3a2e-8d4f-4a51-9e6b-3c2d1f0a9b8e"
+		expiredQuoteID = "1c8d4b3f-9e5a-4b62-8f7c-4d3e2a1b0c9f"
+		usedQuoteID    = "2d9e5c4a-0f6b-4c73-9a8d-5e4f3b2c1d0a"
+		otherQuoteID   = "3e0f6d5b-1a7c-4d84-8b9e-6f5a4c3d2e1b"
+		eurQuoteID     = "4f1a7e6c-2b8d-4e95-9cae-7a6b5d4e3f2c"
+	)
+
+	getAccount := func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+		currency := model.CurrencyBRL
+		switch id {
+		case "uuid-2":
+			currency = model.CurrencyUSD
+		case "uuid-jpy", "uuid-jpy-2":
+			currency = model.CurrencyJPY
+		}
+		return &model.Account{ID: id, Currency: currency, Balance: 100000, Status: model.AccountStatusActive}, nil
+	}
+	accRepo := mock.AccountRepository{
+		OnGetBalance:          getAccount,
+		OnGetBalanceForUpdate: getAccount,
+	}
+	quoteRepo := mock.FXQuoteRepository{
+		OnGetByIDForUpdate: func(ctx context.Context, id model.FXQuoteID) (*model.FXQuote, error) {
+			quote := model.NewFXQuote("uuid-1", model.CurrencyBRL, model.CurrencyUSD, 19518289, 51234, time.Minute)
+			quote.ID = id
+			switch id {
+			case quoteID:
+			case expiredQuoteID:
+				quote.ExpiresAt = time.Now().Add(-time.Second)
+			case usedQuoteID:
+				quote.TransferID = "e82706ef-9ffb-45a2-8081-547accd818c4"
+			case otherQuoteID:
+				quote.AccountID = "uuid-9"
+			case eurQuoteID:
+				quote.TargetCurrency = model.CurrencyEUR
+			default:
+				return nil, repository.ErrFXQuoteNotFound
+			}
+			return quote, nil
+		},
+	}
+	noAccountLimits := mock.TransferLimitRepository{
+		OnGet: func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+			return &model.AccountTransferLimits{AccountID: accountID}, nil
+		},
+	}
+	trfRepo := mock.TransferRepository{
+		OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+			return txFunc(ctx)
+		},
+		OnGetSentTotals: func(ctx context.Context, accountID model.AccountID, periods model.TransferLimitPeriods) (*model.TransferTotals, error) {
+			return &model.TransferTotals{}, nil
+		},
+		OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name         string
+		input        TransferCreateInput
+		want         *TransferCreateOutput
+		wantPostings []model.LedgerPosting
+		wantErr      error
+	}{
+		{
+			name:  "same currency should transfer the amount as is",
+			input: TransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-3", Amount: NewAmount(51234)},
+			want: &TransferCreateOutput{Kind: "transfer", AccountOriginID: "uuid-1", AccountDestinationID: "uuid-3", Currency: "BRL",
+				Amount: NewAmountIn(51234, model.CurrencyBRL), RefundedAmount: amountPtrIn(0, model.CurrencyBRL)},
+			wantPostings: []model.LedgerPosting{
+				{Kind: model.LedgerPostingTransfer, DebitAccountID: "uuid-1", CreditAccountID: "uuid-3", Amount: 51234, Currency: model.CurrencyBRL},
+			},
+		},
+		{
+			name:    "another currency without quote should return error",
+			input:   TransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(51234)},
+			wantErr: ErrTransferCurrencyMismatch,
+		},
+		{
+			name:    "malformed quote should return not found error",
+			input:   TransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(51234), QuoteID: "quote-1"},
+			wantErr: repository.ErrFXQuoteNotFound,
+		},
+		{
+			name:    "quote of another account should return not found error",
+			input:   TransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(51234), QuoteID: otherQuoteID},
+			wantErr: repository.ErrFXQuoteNotFound,
+		},
+		{
+			name:    "used quote should return error",
+			input:   TransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(51234), QuoteID: usedQuoteID},
+			wantErr: repository.ErrFXQuoteUsed,
+		},
+		{
+			name:    "expired quote should return error",
+			input:   TransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(51234), QuoteID: expiredQuoteID},
+			wantErr: ErrFXQuoteExpired,
+		},
+		{
+			name:    "amount other than the quoted one should return error",
+			input:   TransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(50000), QuoteID: quoteID},
+			wantErr: ErrTransferFXQuoteAmountMismatch,
+		},
+		{
+			name:    "quote to a currency other than the destination one should return error",
+			input:   TransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(51234), QuoteID: eurQuoteID},
+			wantErr: ErrTransferFXQuoteCurrencyMismatch,
+		},
+		{
+			name:  "quote should convert the amount through the fx account",
+			input: TransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(51234), QuoteID: " " + quoteID + " "},
+			want: &TransferCreateOutput{Kind: "transfer", AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Currency: "BRL",
+				Amount: NewAmountIn(51234, model.CurrencyBRL), RefundedAmount: amountPtrIn(0, model.CurrencyBRL),
+				Conversion: &TransferConversionOutput{QuoteID: quoteID, Rate: "0.19518289", DestinationCurrency: "USD", DestinationAmount: NewAmountIn(10000, model.CurrencyUSD)}},
+			wantPostings: []model.LedgerPosting{
+				{Kind: model.LedgerPostingTransfer, DebitAccountID: "uuid-1", CreditAccountID: model.LedgerFXAccountID, Amount: 51234, Currency: model.CurrencyBRL},
+				{Kind: model.LedgerPostingTransfer, DebitAccountID: model.LedgerFXAccountID, CreditAccountID: "uuid-2", Amount: 10000, Currency: model.CurrencyUSD},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var postings []model.LedgerPosting
+			ledgerRepo := mock.LedgerRepository{
+				OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+					postings = append(postings, model.LedgerPosting{Kind: posting.Kind, DebitAccountID: posting.DebitAccountID,
+						CreditAccountID: posting.CreditAccountID, Amount: posting.Amount, Currency: posting.Currency})
+					return nil
+				},
+			}
+
+			trfUC := NewTransferUseCase(trfRepo, accRepo, ledgerRepo, noAccountLimits, nil, quoteRepo, TransferLimitPolicy{}, FeePolicy{})
+			got, err := trfUC.Create(backgroundCtx, tt.input)
+			if err != tt.wantErr {
+				t.Fatalf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			got.ID = ""
+			got.CreatedAt = time.Time{}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Create() got = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(postings, tt.wantPostings) {
+				t.Errorf("Create() postings = %+v, want %+v", postings, tt.wantPostings)
+			}
+		})
+	}
+}
+
+func Test_transferUseCase_Create_fees(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	getAccount := func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+		account := &model.Account{ID: id, Currency: model.CurrencyBRL, Balance: 100000, Tier: model.AccountTierStandard, Status: model.AccountStatusActive}
+		switch id {
+		case "uuid-premium":
+			account.Tier = model.AccountTierPremium
+		case "uuid-usd", "uuid-usd-2":
+			account.Currency = model.CurrencyUSD
+		case "uuid-poor":
+			account.Balance = 10000
+		}
+		return account, nil
+	}
+	accRepo := mock.AccountRepository{
+		OnGetBalance: getAccount,
+		OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			if id == "revenue-uuid" {
+				return nil, errors.New("should not lock the revenue account")
+			}
+			return getAccount(ctx, id)
+		},
+	}
+	noAccountLimits := mock.TransferLimitRepository{
+		OnGet: func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+			return &model.AccountTransferLimits{AccountID: accountID}, nil
+		},
+	}
+	feePolicy := FeePolicy{
+		RevenueAccountID: "revenue-uuid",
+		Transfer: model.TransferFeeRule{
+			Flat:         100,
+			Rate:         100,
+			Min:          50,
+			Max:          500,
+			FreePerMonth: 2,
+			ExemptTiers:  []model.AccountTier{model.AccountTierPremium},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		input        TransferCreateInput
+		sent         int
+		want         *TransferCreateOutput
+		wantPostings []model.LedgerPosting
+		wantErr      error
+	}{
+		{
+			name:  "free transfers left should not charge",
+			input: TransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(10000)},
+			sent:  1,
+			want: &TransferCreateOutput{Kind: "transfer", AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Currency: "BRL",
+				Amount: NewAmountIn(10000, model.CurrencyBRL), RefundedAmount: amountPtrIn(0, model.CurrencyBRL)},
+			wantPostings: []model.LedgerPosting{
+				{Kind: model.LedgerPostingTransfer, DebitAccountID: "uuid-1", CreditAccountID: "uuid-2", Amount: 10000},
+			},
+		},
+		{
+			name:  "no free transfers left should charge the fee to the revenue account",
+			input: TransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(10000)},
+			sent:  2,
+			want: &TransferCreateOutput{Kind: "transfer", AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Currency: "BRL",
+				Amount: NewAmountIn(10000, model.CurrencyBRL), RefundedAmount: amountPtrIn(0, model.CurrencyBRL), Fee: amountPtrIn(200, model.CurrencyBRL)},
+			wantPostings: []model.LedgerPosting{
+				{Kind: model.LedgerPostingTransfer, DebitAccountID: "uuid-1", CreditAccountID: "uuid-2", Amount: 10000},
+				{Kind: model.LedgerPostingTransferFee, DebitAccountID: "uuid-1", CreditAccountID: "revenue-uuid", Amount: 200},
+			},
+		},
+		{
+			name:  "exempt tier should not charge",
+			input: TransferCreateInput{AccountOriginID: "uuid-premium", AccountDestinationID: "uuid-2", Amount: NewAmount(10000)},
+			sent:  2,
+			want: &TransferCreateOutput{Kind: "transfer", AccountOriginID: "uuid-premium", AccountDestinationID: "uuid-2", Currency: "BRL",
+				Amount: NewAmountIn(10000, model.CurrencyBRL), RefundedAmount: amountPtrIn(0, model.CurrencyBRL)},
+			wantPostings: []model.LedgerPosting{
+				{Kind: model.LedgerPostingTransfer, DebitAccountID: "uuid-premium", CreditAccountID: "uuid-2", Amount: 10000},
+			},
+		},
+		{
+			name:  "currency other than the revenue one should not charge",
+			input: TransferCreateInput{AccountOriginID: "uuid-usd", AccountDestinationID: "uuid-usd-2", Amount: NewAmount(10000)},
+			sent:  2,
+			want: &TransferCreateOutput{Kind: "transfer", AccountOriginID: "uuid-usd", AccountDestinationID: "uuid-usd-2", Currency: "USD",
+				Amount: NewAmountIn(10000, model.CurrencyUSD), RefundedAmount: amountPtrIn(0, model.CurrencyUSD)},
+			wantPostings: []model.LedgerPosting{
+				{Kind: model.LedgerPostingTransfer, DebitAccountID: "uuid-usd", CreditAccountID: "uuid-usd-2", Amount: 10000},
+			},
+		},
+		{
+			name:    "balance not enough for the amount plus the fee should return error",
+			input:   TransferCreateInput{AccountOriginID: "uuid-poor", AccountDestinationID: "uuid-2", Amount: NewAmount(10000)},
+			sent:    2,
+			wantErr: ErrAccountCurrentBalanceInsufficient,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var postings []model.LedgerPosting
+			ledgerRepo := mock.LedgerRepository{
+				OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+					postings = append(postings, model.LedgerPosting{Kind: posting.Kind, DebitAccountID: posting.DebitAccountID,
+						CreditAccountID: posting.CreditAccountID, Amount: posting.Amount})
+					return nil
+				},
+			}
+			var saved *model.Transfer
+			trfRepo := mock.TransferRepository{
+				OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+					return txFunc(ctx)
+				},
+				OnGetSentTotals: func(ctx context.Context, accountID model.AccountID, periods model.TransferLimitPeriods) (*model.TransferTotals, error) {
+					return &model.TransferTotals{}, nil
+				},
+				OnCountSent: func(ctx context.Context, accountID model.AccountID, since time.Time) (int, error) {
+					return tt.sent, nil
+				},
+				OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+					saved = transfer
+					return nil
+				},
+			}
+
+			trfUC := NewTransferUseCase(trfRepo, accRepo, ledgerRepo, noAccountLimits, nil, nil, TransferLimitPolicy{}, feePolicy)
+			got, err := trfUC.Create(backgroundCtx, tt.input)
+			if err != tt.wantErr {
+				t.Fatalf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			got.ID = ""
+			got.CreatedAt = time.Time{}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Create() got = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(postings, tt.wantPostings) {
+				t.Errorf("Create() postings = %+v, want %+v", postings, tt.wantPostings)
+			}
+			if tt.want.Fee != nil && saved.Fee != tt.want.Fee.Money {
+				t.Errorf("Create() saved fee = %v, want %v", saved.Fee, tt.want.Fee.Money)
+			}
+		})
+	}
+}
+
+func Test_lockAccounts(t *testing.T) {
+	t.Parallel()
+
+	var locked []model.AccountID
+	accRepo := mock.AccountRepository{
+		OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			locked = append(locked, id)
+			return &model.Account{ID: id}, nil
+		},
+	}
+
+	got, err := lockAccounts(context.Background(), accRepo, "uuid-3", "uuid-1", "uuid-2", "uuid-1")
+	if err != nil {
+		t.Fatalf("lockAccounts() error = %v", err)
+	}
+
+	wantLocked := []model.AccountID{"uuid-1", "uuid-2", "uuid-3"}
+	if !reflect.DeepEqual(locked, wantLocked) {
+		t.Errorf("lockAccounts() locked = %v, want %v", locked, wantLocked)
+	}
+	for i, id := range []model.AccountID{"uuid-3", "uuid-1", "uuid-2", "uuid-1"} {
+		if got[i].ID != id {
+			t.Errorf("lockAccounts() got[%d] = %v, want %v", i, got[i].ID, id)
+		}
+	}
+}
+
+func Test_lockAccountsRevenueLast(t *testing.T) {
+	t.Parallel()
+
+	var locked []model.AccountID
+	accRepo := mock.AccountRepository{
+		OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			locked = append(locked, id)
+			return &model.Account{ID: id}, nil
+		},
+	}
+
+	got, err := lockAccountsRevenueLast(context.Background(), accRepo, "uuid-1", "uuid-1", "uuid-3", "uuid-2")
+	if err != nil {
+		t.Fatalf("lockAccountsRevenueLast() error = %v", err)
+	}
+
+	wantLocked := []model.AccountID{"uuid-2", "uuid-3", "uuid-1"}
+	if !reflect.DeepEqual(locked, wantLocked) {
+		t.Errorf("lockAccountsRevenueLast() locked = %v, want %v", locked, wantLocked)
+	}
+	for i, id := range []model.AccountID{"uuid-1", "uuid-3", "uuid-2"} {
+		if got[i].ID != id {
+			t.Errorf("lockAccountsRevenueLast() got[%d] = %v, want %v", i, got[i].ID, id)
+		}
+	}
+}
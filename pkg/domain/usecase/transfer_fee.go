@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrTransferFeeQuote happens when an error occurred and the fee was not quoted.
+	ErrTransferFeeQuote = errors.New("could not quote transfer fee")
+)
+
+// TransferFeeQuoteInput represents the expected input data when quoting the fee of a transfer.
+type TransferFeeQuoteInput struct {
+	AccountOriginID string `json:"-"`
+	Amount          Amount `json:"amount" swaggertype:"number" example:"9999.99"`
+}
+
+// Validate validates the TransferFeeQuoteInput fields.
+func (input *TransferFeeQuoteInput) Validate() error {
+	if input.Amount.Money <= 0 {
+		return ErrTransferAmountNotPositive
+	}
+
+	return nil
+}
+
+// TransferFeeQuoteOutput represents the fee a transfer of the amount would be charged if sent now, in the Currency of
+// the origin account. Total is the amount plus the fee, debited from the origin account.
+// FreeTransfersLeft is only informed when the fee has free transfers per month.
+type TransferFeeQuoteOutput struct {
+	Currency          string `json:"currency" example:"BRL"`
+	Amount            Amount `json:"amount" swaggertype:"number" example:"9999.99"`
+	Fee               Amount `json:"fee" swaggertype:"number" example:"1.5"`
+	Total             Amount `json:"total" swaggertype:"number" example:"10001.49"`
+	FreeTransfersLeft *int   `json:"free_transfers_left,omitempty" example:"0"`
+}
+
+// QuoteFee returns the fee a transfer of the amount from the origin account would be charged if sent now.
+// The quote is not binding: the fee is calculated again when the transfer is created.
+func (trfUC transferUseCase) QuoteFee(ctx context.Context, quoteInput TransferFeeQuoteInput) (*TransferFeeQuoteOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := quoteInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", quoteInput).Msg("transfer fee quote input is not valid")
+		return nil, err
+	}
+
+	origin, err := trfUC.accRepo.GetBalance(ctx, model.AccountID(quoteInput.AccountOriginID))
+	if err != nil {
+		if err == repository.ErrAccountNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", quoteInput.AccountOriginID).Msg("error getting account")
+		return nil, ErrTransferFeeQuote
+	}
+
+	amount, err := quoteInput.Amount.In(origin.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &TransferFeeQuoteOutput{
+		Currency: string(origin.Currency),
+		Amount:   NewAmountIn(amount, origin.Currency),
+		Fee:      NewAmountIn(0, origin.Currency),
+		Total:    NewAmountIn(amount, origin.Currency),
+	}
+
+	if !trfUC.feePolicy.chargesTransfers() {
+		return output, nil
+	}
+
+	revenue, err := trfUC.accRepo.GetBalance(ctx, trfUC.feePolicy.RevenueAccountID)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(trfUC.feePolicy.RevenueAccountID)).
+			Msg("error getting revenue account")
+		return nil, ErrTransferFeeQuote
+	}
+	if !trfUC.feePolicy.chargesTransfersFrom(origin, revenue) {
+		return output, nil
+	}
+
+	freeLeft, err := trfUC.freeTransfersLeft(ctx, origin.ID)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(origin.ID)).Msg("error counting sent transfers")
+		return nil, ErrTransferFeeQuote
+	}
+	if trfUC.feePolicy.Transfer.FreePerMonth > 0 {
+		output.FreeTransfersLeft = &freeLeft
+	}
+
+	if freeLeft == 0 {
+		output.Fee = NewAmountIn(trfUC.feePolicy.Transfer.Fee(amount), origin.Currency)
+		output.Total = NewAmountIn(amount+output.Fee.Money, origin.Currency)
+	}
+
+	return output, nil
+}
+
+// transferFee returns the fee charged to the origin account for sending a transfer of the amount, credited to the
+// revenue account. The origin account must be locked, so the free transfers it sends concurrently are counted one
+// after the other.
+func (trfUC transferUseCase) transferFee(ctx context.Context, origin, revenue *model.Account, amount model.Money) (model.Money, error) {
+	if !trfUC.feePolicy.chargesTransfersFrom(origin, revenue) {
+		return 0, nil
+	}
+
+	freeLeft, err := trfUC.freeTransfersLeft(ctx, origin.ID)
+	if err != nil || freeLeft > 0 {
+		return 0, err
+	}
+
+	return trfUC.feePolicy.Transfer.Fee(amount), nil
+}
+
+// freeTransfersLeft returns how many transfers the account can still send this month without being charged.
+func (trfUC transferUseCase) freeTransfersLeft(ctx context.Context, accountID model.AccountID) (int, error) {
+	freePerMonth := trfUC.feePolicy.Transfer.FreePerMonth
+	if freePerMonth <= 0 {
+		return 0, nil
+	}
+
+	sent, err := trfUC.trfRepo.CountSent(ctx, accountID, trfUC.feePolicy.monthStart(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+	if sent >= freePerMonth {
+		return 0, nil
+	}
+
+	return freePerMonth - sent, nil
+}
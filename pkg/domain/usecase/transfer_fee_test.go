@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_transferUseCase_QuoteFee(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+
+	accRepo := mock.AccountRepository{
+		OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			account := &model.Account{ID: id, Currency: model.CurrencyBRL, Tier: model.AccountTierStandard, Status: model.AccountStatusActive}
+			switch id {
+			case "uuid-premium":
+				account.Tier = model.AccountTierPremium
+			case "uuid-9":
+				return nil, repository.ErrAccountNotFound
+			}
+			return account, nil
+		},
+	}
+	feePolicy := FeePolicy{
+		RevenueAccountID: "revenue-uuid",
+		Transfer: model.TransferFeeRule{
+			Flat:        100,
+			Rate:        100,
+			ExemptTiers: []model.AccountTier{model.AccountTierPremium},
+		},
+	}
+	withFreeTransfers := feePolicy
+	withFreeTransfers.Transfer.FreePerMonth = 3
+	intPtr := func(i int) *int { return &i }
+
+	tests := []struct {
+		name       string
+		policy     FeePolicy
+		countSent  func(ctx context.Context, accountID model.AccountID, since time.Time) (int, error)
+		quoteInput TransferFeeQuoteInput
+		want       *TransferFeeQuoteOutput
+		wantErr    error
+	}{
+		{
+			name:       "zero amount should return error",
+			policy:     feePolicy,
+			quoteInput: TransferFeeQuoteInput{AccountOriginID: "uuid-1", Amount: NewAmount(0)},
+			wantErr:    ErrTransferAmountNotPositive,
+		},
+		{
+			name:       "not found account should return not found error",
+			policy:     feePolicy,
+			quoteInput: TransferFeeQuoteInput{AccountOriginID: "uuid-9", Amount: NewAmount(10000)},
+			wantErr:    repository.ErrAccountNotFound,
+		},
+		{
+			name:       "no fee policy should quote no fee",
+			policy:     FeePolicy{},
+			quoteInput: TransferFeeQuoteInput{AccountOriginID: "uuid-1", Amount: NewAmount(10000)},
+			want:       &TransferFeeQuoteOutput{Currency: "BRL", Amount: NewAmountIn(10000, model.CurrencyBRL), Fee: NewAmountIn(0, model.CurrencyBRL), Total: NewAmountIn(10000, model.CurrencyBRL)},
+		},
+		{
+			name:       "exempt tier should quote no fee",
+			policy:     feePolicy,
+			quoteInput: TransferFeeQuoteInput{AccountOriginID: "uuid-premium", Amount: NewAmount(10000)},
+			want:       &TransferFeeQuoteOutput{Currency: "BRL", Amount: NewAmountIn(10000, model.CurrencyBRL), Fee: NewAmountIn(0, model.CurrencyBRL), Total: NewAmountIn(10000, model.CurrencyBRL)},
+		},
+		{
+			name:       "charged account should quote the fee",
+			policy:     feePolicy,
+			quoteInput: TransferFeeQuoteInput{AccountOriginID: "uuid-1", Amount: NewAmount(10000)},
+			want:       &TransferFeeQuoteOutput{Currency: "BRL", Amount: NewAmountIn(10000, model.CurrencyBRL), Fee: NewAmountIn(200, model.CurrencyBRL), Total: NewAmountIn(10200, model.CurrencyBRL)},
+		},
+		{
+			name:   "free transfers left should quote no fee",
+			policy: withFreeTransfers,
+			countSent: func(ctx context.Context, accountID model.AccountID, since time.Time) (int, error) {
+				return 1, nil
+			},
+			quoteInput: TransferFeeQuoteInput{AccountOriginID: "uuid-1", Amount: NewAmount(10000)},
+			want:       &TransferFeeQuoteOutput{Currency: "BRL", Amount: NewAmountIn(10000, model.CurrencyBRL), Fee: NewAmountIn(0, model.CurrencyBRL), Total: NewAmountIn(10000, model.CurrencyBRL), FreeTransfersLeft: intPtr(2)},
+		},
+		{
+			name:   "no free transfers left should quote the fee",
+			policy: withFreeTransfers,
+			countSent: func(ctx context.Context, accountID model.AccountID, since time.Time) (int, error) {
+				return 5, nil
+			},
+			quoteInput: TransferFeeQuoteInput{AccountOriginID: "uuid-1", Amount: NewAmount(10000)},
+			want:       &TransferFeeQuoteOutput{Currency: "BRL", Amount: NewAmountIn(10000, model.CurrencyBRL), Fee: NewAmountIn(200, model.CurrencyBRL), Total: NewAmountIn(10200, model.CurrencyBRL), FreeTransfersLeft: intPtr(0)},
+		},
+		{
+			name:   "repo count error should return error",
+			policy: withFreeTransfers,
+			countSent: func(ctx context.Context, accountID model.AccountID, since time.Time) (int, error) {
+				return 0, errors.New("any database error")
+			},
+			quoteInput: TransferFeeQuoteInput{AccountOriginID: "uuid-1", Amount: NewAmount(10000)},
+			wantErr:    ErrTransferFeeQuote,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trfRepo := mock.TransferRepository{OnCountSent: tt.countSent}
+			trfUC := NewTransferUseCase(trfRepo, accRepo, nil, nil, nil, nil, TransferLimitPolicy{}, tt.policy)
+
+			got, err := trfUC.QuoteFee(backgroundCtx, tt.quoteInput)
+			if err != tt.wantErr {
+				t.Errorf("QuoteFee() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QuoteFee() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
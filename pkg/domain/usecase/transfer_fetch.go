@@ -2,43 +2,212 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+const (
+	// TransferFetchDefaultLimit is the page size when the limit is not informed.
+	TransferFetchDefaultLimit = 20
+	// TransferFetchMaxLimit is the biggest page size.
+	TransferFetchMaxLimit = 100
 )
 
 var (
 	// ErrTransferFetch happens when an error occurred while fetching the transfers.
 	ErrTransferFetch = errors.New("could not fetch transfers")
+	// ErrTransferFetchCursorInvalid happens when the cursor was not returned by a previous fetch.
+	ErrTransferFetchCursorInvalid = errors.New("'cursor' is not valid")
+	// ErrTransferFetchLimitInvalid happens when the page size is out of bounds.
+	ErrTransferFetchLimitInvalid = errors.New("'limit' must be between 1 and 100")
+	// ErrTransferFetchDirectionInvalid happens when the direction is not known.
+	ErrTransferFetchDirectionInvalid = errors.New("'direction' must be 'sent' or 'received'")
+	// ErrTransferFetchCounterpartInvalid happens when the counterpart account ID is not an uuid.
+	ErrTransferFetchCounterpartInvalid = errors.New("'counterpart_id' must be an account ID")
+	// ErrTransferFetchPeriodInvalid happens when the period doesn't end after it starts.
+	ErrTransferFetchPeriodInvalid = errors.New("'from' must be before 'to'")
+	// ErrTransferFetchAmountRangeInvalid happens when the amount range is empty or has non-positive bounds.
+	ErrTransferFetchAmountRangeInvalid = errors.New("'min_amount' and 'max_amount' must be positive, with 'min_amount' up to 'max_amount'")
 )
 
-// TransferFetchOutput represents the output data of the fetch method.
+// TransferFetchInput represents the expected input data when fetching the transfers of an account.
+// Zero values don't filter. The period includes From and excludes To.
+type TransferFetchInput struct {
+	AccountID     model.AccountID
+	Cursor        string
+	Limit         int
+	Direction     string
+	CounterpartID string
+	From          time.Time
+	To            time.Time
+	MinAmount     *Amount
+	MaxAmount     *Amount
+}
+
+// filter validates the TransferFetchInput fields and returns the corresponding model.TransferFilter, with the amounts
+// in the currency of the account. It asks one transfer more than the page size, to know if there's a next page.
+func (input TransferFetchInput) filter(currency model.Currency) (model.TransferFilter, error) {
+	filter := model.TransferFilter{
+		AccountID:     input.AccountID,
+		Direction:     model.TransferDirection(input.Direction),
+		CounterpartID: model.AccountID(strings.TrimSpace(input.CounterpartID)),
+		CreatedFrom:   input.From,
+		CreatedTo:     input.To,
+		Limit:         input.Limit,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = TransferFetchDefaultLimit
+	}
+	if filter.Limit < 1 || filter.Limit > TransferFetchMaxLimit {
+		return filter, ErrTransferFetchLimitInvalid
+	}
+	filter.Limit++
+
+	if filter.Direction != "" && !filter.Direction.IsValid() {
+		return filter, ErrTransferFetchDirectionInvalid
+	}
+
+	if filter.CounterpartID != "" {
+		if _, err := uuid.Parse(string(filter.CounterpartID)); err != nil {
+			return filter, ErrTransferFetchCounterpartInvalid
+		}
+	}
+
+	if !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero() && !filter.CreatedFrom.Before(filter.CreatedTo) {
+		return filter, ErrTransferFetchPeriodInvalid
+	}
+
+	var err error
+	if input.MinAmount != nil {
+		filter.MinAmount, err = input.MinAmount.In(currency)
+		if err != nil {
+			return filter, err
+		}
+		if filter.MinAmount <= 0 {
+			return filter, ErrTransferFetchAmountRangeInvalid
+		}
+	}
+	if input.MaxAmount != nil {
+		filter.MaxAmount, err = input.MaxAmount.In(currency)
+		if err != nil {
+			return filter, err
+		}
+		if filter.MaxAmount <= 0 || filter.MaxAmount < filter.MinAmount {
+			return filter, ErrTransferFetchAmountRangeInvalid
+		}
+	}
+
+	if input.Cursor != "" {
+		cursor, err := decodeTransferCursor(input.Cursor)
+		if err != nil {
+			return filter, ErrTransferFetchCursorInvalid
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
+
+// encodeTransferCursor returns an opaque cursor to fetch the transfers after the given one.
+func encodeTransferCursor(transfer model.Transfer) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(transfer.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + string(transfer.ID)))
+}
+
+func decodeTransferCursor(cursor string) (*model.TransferCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(decoded), "|", 2)
+	if len(parts) != 2 {
+		return nil, ErrTransferFetchCursorInvalid
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TransferCursor{CreatedAt: createdAt, ID: model.TransferID(id.String())}, nil
+}
+
+// TransferFetchOutput represents a transfer of the fetch method output.
 type TransferFetchOutput struct {
 	TransferCreateOutput
 }
 
-func newTransferFetchOutputList(transfers []model.Transfer) []TransferFetchOutput {
-	var outputs = make([]TransferFetchOutput, 0)
+// TransferFetchPageOutput represents the output data of the fetch method.
+// NextCursor is null on the last page.
+type TransferFetchPageOutput struct {
+	Transfers  []TransferFetchOutput `json:"transfers"`
+	NextCursor *string               `json:"next_cursor" example:"MjAyMC0xMi0zMVQyMzo1OTo1OS45OTk5OTlafGU3ZGY5NGJhLTZlOTMtNGI3Mi04MmYxLTlkMmE0M2I2ZjZlYQ"`
+}
+
+func newTransferFetchPageOutput(transfers []model.Transfer, limit int) *TransferFetchPageOutput {
+	output := &TransferFetchPageOutput{
+		Transfers: make([]TransferFetchOutput, 0, len(transfers)),
+	}
+
+	if len(transfers) > limit {
+		transfers = transfers[:limit]
+		nextCursor := encodeTransferCursor(transfers[limit-1])
+		output.NextCursor = &nextCursor
+	}
 
 	for _, transfer := range transfers {
-		outputs = append(outputs, TransferFetchOutput{
+		output.Transfers = append(output.Transfers, TransferFetchOutput{
 			TransferCreateOutput: *newTransferCreateOutput(&transfer),
 		})
 	}
 
-	return outputs
+	return output
 }
 
-// Fetch returns all the transfers from repository.TransferRepository.
-func (trfUC *transferUseCase) Fetch(ctx context.Context, accountID model.AccountID) ([]TransferFetchOutput, error) {
+// Fetch returns a page of the account transfers from repository.TransferRepository, newest first,
+// with the cursor of the next page, if any. The amount range is in the currency of the account.
+func (trfUC *transferUseCase) Fetch(ctx context.Context, fetchInput TransferFetchInput) (*TransferFetchPageOutput, error) {
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	transfers, err := trfUC.trfRepo.Fetch(ctx, accountID)
+	var currency model.Currency
+	if fetchInput.MinAmount != nil || fetchInput.MaxAmount != nil {
+		account, err := trfUC.accRepo.GetBalance(ctx, fetchInput.AccountID)
+		if err != nil {
+			if err == repository.ErrAccountNotFound {
+				return nil, err
+			}
+			log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(fetchInput.AccountID)).Msg("error getting account of the transfers")
+			return nil, ErrTransferFetch
+		}
+		currency = account.Currency
+	}
+
+	filter, err := fetchInput.filter(currency)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", fetchInput).Msg("transfer fetch input is not valid")
+		return nil, err
+	}
+
+	transfers, err := trfUC.trfRepo.Fetch(ctx, filter)
 	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("filter", filter).Msg("error fetching transfers")
 		return nil, ErrTransferFetch
 	}
 
-	return newTransferFetchOutputList(transfers), nil
+	return newTransferFetchPageOutput(transfers, filter.Limit-1), nil
 }
@@ -17,140 +17,288 @@ func Test_transferUseCase_Fetch(t *testing.T) {
 
 	backgroundCtx := context.Background()
 
+	createdAt := time.Date(2021, 1, 31, 12, 0, 0, 123456000, time.UTC)
+	twoTransfers := []model.Transfer{
+		{
+			ID:                   "any-uuid-1",
+			AccountOriginID:      "uuid-1",
+			AccountDestinationID: "uuid-2",
+			Amount:               1,
+			CreatedAt:            createdAt,
+		},
+		{
+			ID:                   "any-uuid-2",
+			AccountOriginID:      "uuid-3",
+			AccountDestinationID: "uuid-1",
+			Amount:               2,
+			CreatedAt:            createdAt,
+		},
+	}
+	cursor := encodeTransferCursor(model.Transfer{ID: "e7df94ba-6e93-4b72-82f1-9d2a43b6f6ea", CreatedAt: createdAt})
+	minAmount, maxAmount := NewAmount(50), NewAmount(100)
+	// the amount range is in the currency of the account
+	accRepo := mock.AccountRepository{
+		OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			if id == "uuid-jpy" {
+				return &model.Account{ID: id, Currency: model.CurrencyJPY}, nil
+			}
+			return &model.Account{ID: id, Currency: model.CurrencyBRL}, nil
+		},
+	}
+	amountOf := func(s string) *Amount {
+		amount, err := ParseAmount(s)
+		if err != nil {
+			t.Fatalf("ParseAmount() error = %v", err)
+		}
+		return &amount
+	}
+
 	type fields struct {
 		trfRepo repository.TransferRepository
-		accRepo repository.AccountRepository
 	}
 	type args struct {
-		ctx       context.Context
-		accountID model.AccountID
+		ctx        context.Context
+		fetchInput TransferFetchInput
 	}
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
-		want    []TransferFetchOutput
-		wantErr bool
+		want    *TransferFetchPageOutput
+		wantErr error
 	}{
 		{
 			name: "repo fetch error should return error",
 			fields: fields{
 				trfRepo: mock.TransferRepository{
-					OnFetch: func(ctx context.Context, accountID model.AccountID) ([]model.Transfer, error) {
+					OnFetch: func(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error) {
 						return nil, errors.New("any database error")
 					},
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-1"},
 			},
 			want:    nil,
-			wantErr: true,
+			wantErr: ErrTransferFetch,
 		},
 		{
-			name: "repo empty result should return empty result",
+			name: "repo empty result should return empty page",
 			fields: fields{
 				trfRepo: mock.TransferRepository{
-					OnFetch: func(ctx context.Context, accountID model.AccountID) ([]model.Transfer, error) {
+					OnFetch: func(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error) {
+						if filter.AccountID != "uuid-1" || filter.Limit != TransferFetchDefaultLimit+1 {
+							return nil, errors.New("should fetch the default page size plus one")
+						}
 						return []model.Transfer{}, nil
 					},
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-1"},
+			},
+			want: &TransferFetchPageOutput{
+				Transfers:  []TransferFetchOutput{},
+				NextCursor: nil,
 			},
-			want:    []TransferFetchOutput{},
-			wantErr: false,
+			wantErr: nil,
 		},
 		{
-			name: "repo one result should return one result",
+			name: "repo results up to the limit should return the last page",
 			fields: fields{
 				trfRepo: mock.TransferRepository{
-					OnFetch: func(ctx context.Context, accountID model.AccountID) ([]model.Transfer, error) {
-						return []model.Transfer{
-							{
-								ID:                   "any-uuid-1",
-								AccountOriginID:      "uuid-1",
-								AccountDestinationID: "uuid-2",
-								Amount:               1,
-								CreatedAt:            time.Time{},
-							},
-						}, nil
+					OnFetch: func(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error) {
+						return twoTransfers, nil
 					},
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-1", Limit: 2},
 			},
-			want: []TransferFetchOutput{
-				{TransferCreateOutput: TransferCreateOutput{
-					ID:                   "any-uuid-1",
-					AccountOriginID:      "uuid-1",
-					AccountDestinationID: "uuid-2",
-					Amount:               0.01,
-					CreatedAt:            time.Time{},
-				}},
+			want: &TransferFetchPageOutput{
+				Transfers: []TransferFetchOutput{
+					{TransferCreateOutput: TransferCreateOutput{ID: "any-uuid-1", AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(1), CreatedAt: createdAt}},
+					{TransferCreateOutput: TransferCreateOutput{ID: "any-uuid-2", AccountOriginID: "uuid-3", AccountDestinationID: "uuid-1", Amount: NewAmount(2), CreatedAt: createdAt}},
+				},
+				NextCursor: nil,
 			},
-			wantErr: false,
+			wantErr: nil,
 		},
 		{
-			name: "repo two results should return two results",
+			name: "repo results over the limit should return the next cursor",
 			fields: fields{
 				trfRepo: mock.TransferRepository{
-					OnFetch: func(ctx context.Context, accountID model.AccountID) ([]model.Transfer, error) {
-						return []model.Transfer{
-							{
-								ID:                   "any-uuid-1",
-								AccountOriginID:      "uuid-1",
-								AccountDestinationID: "uuid-2",
-								Amount:               1,
-								CreatedAt:            time.Time{},
-							},
-							{
-								ID:                   "any-uuid-2",
-								AccountOriginID:      "uuid-3",
-								AccountDestinationID: "uuid-4",
-								Amount:               2,
-								CreatedAt:            time.Time{},
-							},
-						}, nil
+					OnFetch: func(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error) {
+						return twoTransfers, nil
 					},
 				},
 			},
 			args: args{
-				ctx: backgroundCtx,
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-1", Limit: 1},
 			},
-			want: []TransferFetchOutput{
-				{
-					TransferCreateOutput: TransferCreateOutput{
-						ID:                   "any-uuid-1",
-						AccountOriginID:      "uuid-1",
-						AccountDestinationID: "uuid-2",
-						Amount:               0.01,
-						CreatedAt:            time.Time{},
+			want: &TransferFetchPageOutput{
+				Transfers: []TransferFetchOutput{
+					{TransferCreateOutput: TransferCreateOutput{ID: "any-uuid-1", AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(1), CreatedAt: createdAt}},
+				},
+				NextCursor: func() *string {
+					cursor := encodeTransferCursor(twoTransfers[0])
+					return &cursor
+				}(),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should pass the filters to the repo",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnFetch: func(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error) {
+						want := model.TransferFilter{
+							AccountID:     "uuid-1",
+							Direction:     model.TransferDirectionSent,
+							CounterpartID: "ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d",
+							CreatedFrom:   createdAt.Add(-time.Hour),
+							CreatedTo:     createdAt,
+							MinAmount:     50,
+							MaxAmount:     100,
+							After:         &model.TransferCursor{ID: "e7df94ba-6e93-4b72-82f1-9d2a43b6f6ea", CreatedAt: createdAt},
+							Limit:         11,
+						}
+						if !reflect.DeepEqual(filter, want) {
+							return nil, errors.New("unexpected filter")
+						}
+						return []model.Transfer{}, nil
 					},
 				},
-				{
-					TransferCreateOutput: TransferCreateOutput{
-						ID:                   "any-uuid-2",
-						AccountOriginID:      "uuid-3",
-						AccountDestinationID: "uuid-4",
-						Amount:               0.02,
-						CreatedAt:            time.Time{},
+			},
+			args: args{
+				ctx: backgroundCtx,
+				fetchInput: TransferFetchInput{
+					AccountID:     "uuid-1",
+					Cursor:        cursor,
+					Limit:         10,
+					Direction:     "sent",
+					CounterpartID: "ce8ba94a-2c5f-4e00-80a1-6fcb0ce7382d",
+					From:          createdAt.Add(-time.Hour),
+					To:            createdAt,
+					MinAmount:     &minAmount,
+					MaxAmount:     &maxAmount,
+				},
+			},
+			want:    &TransferFetchPageOutput{Transfers: []TransferFetchOutput{}},
+			wantErr: nil,
+		},
+		{
+			name: "should pass the amount range in the currency of the account",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnFetch: func(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error) {
+						if filter.MinAmount != 50 || filter.MaxAmount != 100 {
+							return nil, errors.New("unexpected filter")
+						}
+						return []model.Transfer{}, nil
 					},
 				},
 			},
-			wantErr: false,
+			args: args{
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-jpy", MinAmount: amountOf("50"), MaxAmount: amountOf("100")},
+			},
+			want:    &TransferFetchPageOutput{Transfers: []TransferFetchOutput{}},
+			wantErr: nil,
+		},
+		{
+			name:   "amount with more decimal places than the currency of the account should return error",
+			fields: fields{trfRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-jpy", MinAmount: amountOf("0.5")},
+			},
+			want:    nil,
+			wantErr: ErrAmountInvalid,
+		},
+		{
+			name:   "limit over the max should return limit invalid error",
+			fields: fields{trfRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-1", Limit: TransferFetchMaxLimit + 1},
+			},
+			want:    nil,
+			wantErr: ErrTransferFetchLimitInvalid,
+		},
+		{
+			name:   "negative limit should return limit invalid error",
+			fields: fields{trfRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-1", Limit: -1},
+			},
+			want:    nil,
+			wantErr: ErrTransferFetchLimitInvalid,
+		},
+		{
+			name:   "unknown direction should return direction invalid error",
+			fields: fields{trfRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-1", Direction: "both"},
+			},
+			want:    nil,
+			wantErr: ErrTransferFetchDirectionInvalid,
+		},
+		{
+			name:   "counterpart not uuid should return counterpart invalid error",
+			fields: fields{trfRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-1", CounterpartID: "any"},
+			},
+			want:    nil,
+			wantErr: ErrTransferFetchCounterpartInvalid,
+		},
+		{
+			name:   "'from' after 'to' should return period invalid error",
+			fields: fields{trfRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-1", From: createdAt, To: createdAt},
+			},
+			want:    nil,
+			wantErr: ErrTransferFetchPeriodInvalid,
+		},
+		{
+			name:   "min amount over max amount should return amount range invalid error",
+			fields: fields{trfRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-1", MinAmount: &maxAmount, MaxAmount: &minAmount},
+			},
+			want:    nil,
+			wantErr: ErrTransferFetchAmountRangeInvalid,
+		},
+		{
+			name:   "unknown cursor should return cursor invalid error",
+			fields: fields{trfRepo: nil},
+			args: args{
+				ctx:        backgroundCtx,
+				fetchInput: TransferFetchInput{AccountID: "uuid-1", Cursor: "any-cursor"},
+			},
+			want:    nil,
+			wantErr: ErrTransferFetchCursorInvalid,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			trfUC := &transferUseCase{
 				trfRepo: tt.fields.trfRepo,
-				accRepo: tt.fields.accRepo,
+				accRepo: accRepo,
 			}
-			got, err := trfUC.Fetch(tt.args.ctx, tt.args.accountID)
-			if (err != nil) != tt.wantErr {
+			got, err := trfUC.Fetch(tt.args.ctx, tt.args.fetchInput)
+			if err != tt.wantErr {
 				t.Errorf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
@@ -160,3 +308,20 @@ func Test_transferUseCase_Fetch(t *testing.T) {
 		})
 	}
 }
+
+func Test_transferCursor(t *testing.T) {
+	t.Parallel()
+
+	transfer := model.Transfer{
+		ID:        "e7df94ba-6e93-4b72-82f1-9d2a43b6f6ea",
+		CreatedAt: time.Date(2021, 1, 31, 9, 0, 0, 123456000, time.FixedZone("BRT", -3*60*60)),
+	}
+
+	got, err := decodeTransferCursor(encodeTransferCursor(transfer))
+	if err != nil {
+		t.Fatalf("decodeTransferCursor() error = %v", err)
+	}
+	if got.ID != transfer.ID || !got.CreatedAt.Equal(transfer.CreatedAt) {
+		t.Errorf("decodeTransferCursor() got = %v, want %v", got, transfer)
+	}
+}
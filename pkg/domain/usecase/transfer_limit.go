@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrTransferLimitExceeded happens when the transfer amount is greater than the allowance left by the limits of the origin account.
+	ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+	// ErrTransferLimitNegative happens when a limit set for an account is negative.
+	ErrTransferLimitNegative = errors.New("transfer limits must not be negative")
+	// ErrTransferLimitGet happens when an error occurred while getting the transfer limits.
+	ErrTransferLimitGet = errors.New("could not get transfer limits")
+	// ErrTransferLimitSave happens when an error occurred and the transfer limits were not saved.
+	ErrTransferLimitSave = errors.New("could not save transfer limits")
+)
+
+// TransferLimitExceededError is returned when the transfer is rejected by one of the limits of the origin account.
+// It matches ErrTransferLimitExceeded with errors.Is.
+type TransferLimitExceededError struct {
+	Limit     model.TransferLimit
+	Remaining model.Money
+}
+
+func (e *TransferLimitExceededError) Error() string {
+	return fmt.Sprintf("'amount' exceeds the %s transfer limit, the remaining allowance is %s",
+		strings.ReplaceAll(string(e.Limit), "_", " "), e.Remaining)
+}
+
+// Is makes errors.Is(err, ErrTransferLimitExceeded) true.
+func (e *TransferLimitExceededError) Is(target error) bool {
+	return target == ErrTransferLimitExceeded
+}
+
+// TransferLimitPolicy defines the default transfer limits of the accounts and when their periods start.
+type TransferLimitPolicy struct {
+	Defaults model.TransferLimits
+	Calendar model.TransferLimitCalendar
+}
+
+// TransferLimitUseCase is the interface that wraps all business logic methods related to the transfer limits.
+type TransferLimitUseCase interface {
+	Get(ctx context.Context, caller model.Principal, accountID model.AccountID) (*TransferLimitsOutput, error)
+	Set(ctx context.Context, caller model.Principal, limitsInput TransferLimitsInput) (*TransferLimitsOutput, error)
+}
+
+type transferLimitUseCase struct {
+	limitRepo   repository.TransferLimitRepository
+	trfRepo     repository.TransferRepository
+	accRepo     repository.AccountRepository
+	limitPolicy TransferLimitPolicy
+}
+
+// NewTransferLimitUseCase instantiates a new TransferLimitUseCase.
+func NewTransferLimitUseCase(
+	limitRepo repository.TransferLimitRepository,
+	trfRepo repository.TransferRepository,
+	accRepo repository.AccountRepository,
+	limitPolicy TransferLimitPolicy,
+) TransferLimitUseCase {
+	return &transferLimitUseCase{
+		limitRepo:   limitRepo,
+		trfRepo:     trfRepo,
+		accRepo:     accRepo,
+		limitPolicy: limitPolicy,
+	}
+}
+
+// TransferLimitsInput represents the expected input data when setting the transfer limits of an account.
+// The limits left out, or null, go back to the defaults. Zero means no limit.
+type TransferLimitsInput struct {
+	AccountID           string  `json:"-"`
+	PerTransaction      *Amount `json:"per_transaction" swaggertype:"number" example:"5000"`
+	Daily               *Amount `json:"daily" swaggertype:"number" example:"10000"`
+	Monthly             *Amount `json:"monthly" swaggertype:"number" example:"50000"`
+	NightPerTransaction *Amount `json:"night_per_transaction" swaggertype:"number" example:"1000"`
+	NightTotal          *Amount `json:"night_total" swaggertype:"number" example:"1000"`
+}
+
+// Validate validates the TransferLimitsInput fields.
+func (input *TransferLimitsInput) Validate() error {
+	for _, limit := range []*Amount{input.PerTransaction, input.Daily, input.Monthly, input.NightPerTransaction, input.NightTotal} {
+		if limit != nil && limit.Money < 0 {
+			return ErrTransferLimitNegative
+		}
+	}
+
+	return nil
+}
+
+// TransferLimitsOutput represents the transfer limits in force for an account and what's left of them.
+// The limits left out are not limited.
+type TransferLimitsOutput struct {
+	AccountID           string  `json:"account_id" example:"16b1d860-43d3-4970-bb54-ec395908599a"`
+	PerTransaction      *Amount `json:"per_transaction,omitempty" swaggertype:"number" example:"5000"`
+	Daily               *Amount `json:"daily,omitempty" swaggertype:"number" example:"10000"`
+	Monthly             *Amount `json:"monthly,omitempty" swaggertype:"number" example:"50000"`
+	NightPerTransaction *Amount `json:"night_per_transaction,omitempty" swaggertype:"number" example:"1000"`
+	NightTotal          *Amount `json:"night_total,omitempty" swaggertype:"number" example:"1000"`
+	Night               bool    `json:"night" example:"false"`
+	Allowance           *Amount `json:"allowance,omitempty" swaggertype:"number" example:"4500"`
+	AllowanceLimit      string  `json:"allowance_limit,omitempty" example:"per_transaction" enums:"per_transaction,daily,monthly,night_per_transaction,night_total"`
+}
+
+func newTransferLimitsOutput(account *model.Account, limits model.TransferLimits, periods model.TransferLimitPeriods, totals model.TransferTotals) *TransferLimitsOutput {
+	amountOrNil := func(limit model.Money) *Amount {
+		if limit <= 0 {
+			return nil
+		}
+		amount := NewAmountIn(limit, account.Currency)
+		return &amount
+	}
+
+	output := &TransferLimitsOutput{
+		AccountID:           string(account.ID),
+		PerTransaction:      amountOrNil(limits.PerTransaction),
+		Daily:               amountOrNil(limits.Daily),
+		Monthly:             amountOrNil(limits.Monthly),
+		NightPerTransaction: amountOrNil(limits.NightPerTransaction),
+		NightTotal:          amountOrNil(limits.NightTotal),
+		Night:               periods.IsNight(),
+	}
+	if allowance, ok := limits.Allowance(totals, periods.IsNight()); ok {
+		remaining := NewAmountIn(allowance.Remaining, account.Currency)
+		output.Allowance = &remaining
+		output.AllowanceLimit = string(allowance.Limit)
+	}
+
+	return output
+}
+
+// Get returns the transfer limits in force for the account and the allowance left by them now.
+// Only the account owner or callers with the model.ScopeAccountsRead can read them, otherwise it returns ErrAuthForbidden.
+func (limitUC transferLimitUseCase) Get(ctx context.Context, caller model.Principal, accountID model.AccountID) (*TransferLimitsOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if !caller.Owns(accountID) && !caller.HasScope(model.ScopeAccountsRead) {
+		return nil, ErrAuthForbidden
+	}
+
+	account, err := limitUC.accRepo.GetBalance(ctx, accountID)
+	if err != nil {
+		if err == repository.ErrAccountNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(accountID)).Msg("error getting account of the transfer limits")
+		return nil, ErrTransferLimitGet
+	}
+
+	output, err := limitUC.newOutput(ctx, account)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(accountID)).Msg("error getting transfer limits")
+		return nil, ErrTransferLimitGet
+	}
+
+	return output, nil
+}
+
+// Set replaces the transfer limits set for the account.
+// Only callers with the model.ScopeAccountsWrite can set them, otherwise it returns ErrAuthForbidden.
+func (limitUC transferLimitUseCase) Set(ctx context.Context, caller model.Principal, limitsInput TransferLimitsInput) (*TransferLimitsOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if !caller.HasScope(model.ScopeAccountsWrite) {
+		return nil, ErrAuthForbidden
+	}
+
+	err := limitsInput.Validate()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Interface("input", limitsInput).Msg("transfer limits input is not valid")
+		return nil, err
+	}
+
+	accountID := model.AccountID(limitsInput.AccountID)
+	account, err := limitUC.accRepo.GetBalance(ctx, accountID)
+	if err != nil {
+		if err == repository.ErrAccountNotFound {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("input", limitsInput).Msg("error getting account of the transfer limits")
+		return nil, ErrTransferLimitSave
+	}
+
+	// the limits are in the currency of the account
+	for _, limit := range []*Amount{limitsInput.PerTransaction, limitsInput.Daily, limitsInput.Monthly, limitsInput.NightPerTransaction, limitsInput.NightTotal} {
+		if limit == nil {
+			continue
+		}
+		if _, err := limit.In(account.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	moneyOrNil := func(amount *Amount) *model.Money {
+		if amount == nil {
+			return nil
+		}
+		money := amount.Money
+		return &money
+	}
+
+	err = limitUC.limitRepo.Save(ctx, &model.AccountTransferLimits{
+		AccountID:           accountID,
+		PerTransaction:      moneyOrNil(limitsInput.PerTransaction),
+		Daily:               moneyOrNil(limitsInput.Daily),
+		Monthly:             moneyOrNil(limitsInput.Monthly),
+		NightPerTransaction: moneyOrNil(limitsInput.NightPerTransaction),
+		NightTotal:          moneyOrNil(limitsInput.NightTotal),
+		UpdatedAt:           time.Now(),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("input", limitsInput).Msg("error saving transfer limits")
+		return nil, ErrTransferLimitSave
+	}
+
+	log.Ctx(ctx).Info().Str("accountID", string(accountID)).Str("by", string(caller.AccountID)).Msg("transfer limits set")
+
+	output, err := limitUC.newOutput(ctx, account)
+	if err != nil {
+		log.Ctx(ctx).Error().Stack().Err(err).Str("accountID", string(accountID)).Msg("error getting transfer limits")
+		return nil, ErrTransferLimitGet
+	}
+
+	return output, nil
+}
+
+func (limitUC transferLimitUseCase) newOutput(ctx context.Context, account *model.Account) (*TransferLimitsOutput, error) {
+	limits, err := getTransferLimits(ctx, limitUC.limitRepo, limitUC.limitPolicy, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := limitUC.limitPolicy.Calendar.PeriodsAt(time.Now())
+	totals := &model.TransferTotals{}
+	if !limits.IsUnlimited() {
+		totals, err = limitUC.trfRepo.GetSentTotals(ctx, account.ID, periods)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newTransferLimitsOutput(account, limits, periods, *totals), nil
+}
+
+// checkLimits rejects the transfer with a *TransferLimitExceededError when its amount is greater than the
+// allowance left by the limits of the origin account.
+//
+// The origin account must be locked, so the transfers it sends concurrently are counted one after the other.
+func (trfUC transferUseCase) checkLimits(ctx context.Context, transfer *model.Transfer) error {
+	return checkTransferLimits(ctx, trfUC.limitRepo, trfUC.trfRepo, trfUC.limitPolicy, transfer.AccountOriginID, transfer.Amount, transfer.CreatedAt)
+}
+
+// checkTransferLimits rejects the amount the account sends at the time with a *TransferLimitExceededError when it's
+// greater than the allowance left by its limits. The account must be locked, see checkLimits.
+func checkTransferLimits(
+	ctx context.Context,
+	limitRepo repository.TransferLimitRepository,
+	trfRepo repository.TransferRepository,
+	limitPolicy TransferLimitPolicy,
+	accountID model.AccountID,
+	amount model.Money,
+	at time.Time,
+) error {
+	limits, err := getTransferLimits(ctx, limitRepo, limitPolicy, accountID)
+	if err != nil {
+		return err
+	}
+	if limits.IsUnlimited() {
+		return nil
+	}
+
+	periods := limitPolicy.Calendar.PeriodsAt(at)
+	totals, err := trfRepo.GetSentTotals(ctx, accountID, periods)
+	if err != nil {
+		return err
+	}
+
+	allowance, ok := limits.Allowance(*totals, periods.IsNight())
+	if ok && amount > allowance.Remaining {
+		return &TransferLimitExceededError{Limit: allowance.Limit, Remaining: allowance.Remaining}
+	}
+
+	return nil
+}
+
+// getTransferLimits returns the default limits replaced by the ones set for the account.
+func getTransferLimits(ctx context.Context, limitRepo repository.TransferLimitRepository, limitPolicy TransferLimitPolicy, accountID model.AccountID) (model.TransferLimits, error) {
+	accountLimits, err := limitRepo.Get(ctx, accountID)
+	if err != nil {
+		return model.TransferLimits{}, err
+	}
+
+	return limitPolicy.Defaults.With(accountLimits), nil
+}
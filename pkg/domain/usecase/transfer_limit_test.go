@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_transferUseCase_Create_limits(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	daily := model.Money(5000)
+
+	defaults := TransferLimitPolicy{
+		Defaults: model.TransferLimits{PerTransaction: 1000, Daily: 3000},
+	}
+	// night all day long
+	atNight := TransferLimitPolicy{
+		Defaults: model.TransferLimits{PerTransaction: 1000, Daily: 3000, NightPerTransaction: 500, NightTotal: 800},
+		Calendar: model.TransferLimitCalendar{NightStartHour: 0, NightEndHour: 24},
+	}
+	getAccount := func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+		return &model.Account{ID: id, Balance: 10000, Status: model.AccountStatusActive}, nil
+	}
+	accounts := mock.AccountRepository{
+		OnGetBalance:          getAccount,
+		OnGetBalanceForUpdate: getAccount,
+	}
+	ledgerRepo := mock.LedgerRepository{
+		OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+			return nil
+		},
+	}
+	accountLimits := func(limits model.AccountTransferLimits) mock.TransferLimitRepository {
+		return mock.TransferLimitRepository{
+			OnGet: func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+				if accountID != "uuid-1" {
+					return nil, errors.New("should get the limits of the origin account")
+				}
+				limits.AccountID = accountID
+				return &limits, nil
+			},
+		}
+	}
+	// sentTotals returns the totals sent by the origin account, failing when the night period is not the expected one
+	sentTotals := func(totals model.TransferTotals, night bool) mock.TransferRepository {
+		return mock.TransferRepository{
+			OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+				return txFunc(ctx)
+			},
+			OnGetSentTotals: func(ctx context.Context, accountID model.AccountID, periods model.TransferLimitPeriods) (*model.TransferTotals, error) {
+				if accountID != "uuid-1" || periods.IsNight() != night || periods.DayStart.IsZero() {
+					return nil, errors.New("unexpected periods")
+				}
+				return &totals, nil
+			},
+			OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+				return nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		trfRepo     repository.TransferRepository
+		limitRepo   repository.TransferLimitRepository
+		limitPolicy TransferLimitPolicy
+		amount      model.Money
+		wantErr     error
+	}{
+		{
+			name: "no limits should not read the totals",
+			trfRepo: mock.TransferRepository{
+				OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+					return txFunc(ctx)
+				},
+				OnGetSentTotals: func(ctx context.Context, accountID model.AccountID, periods model.TransferLimitPeriods) (*model.TransferTotals, error) {
+					return nil, errors.New("should not read the totals")
+				},
+				OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+					return nil
+				},
+			},
+			limitRepo:   accountLimits(model.AccountTransferLimits{}),
+			limitPolicy: TransferLimitPolicy{},
+			amount:      9000,
+		},
+		{
+			name:        "within the limits should succeed",
+			trfRepo:     sentTotals(model.TransferTotals{Daily: 2000, Monthly: 2000}, false),
+			limitRepo:   accountLimits(model.AccountTransferLimits{}),
+			limitPolicy: defaults,
+			amount:      1000,
+		},
+		{
+			name:        "above the per transaction limit should return error",
+			trfRepo:     sentTotals(model.TransferTotals{}, false),
+			limitRepo:   accountLimits(model.AccountTransferLimits{}),
+			limitPolicy: defaults,
+			amount:      1001,
+			wantErr:     &TransferLimitExceededError{Limit: model.TransferLimitPerTransaction, Remaining: 1000},
+		},
+		{
+			name:        "above what's left of the daily limit should return error",
+			trfRepo:     sentTotals(model.TransferTotals{Daily: 2500, Monthly: 2500}, false),
+			limitRepo:   accountLimits(model.AccountTransferLimits{}),
+			limitPolicy: defaults,
+			amount:      600,
+			wantErr:     &TransferLimitExceededError{Limit: model.TransferLimitDaily, Remaining: 500},
+		},
+		{
+			name:        "account limits should replace the defaults",
+			trfRepo:     sentTotals(model.TransferTotals{Daily: 2500, Monthly: 2500}, false),
+			limitRepo:   accountLimits(model.AccountTransferLimits{Daily: &daily}),
+			limitPolicy: defaults,
+			amount:      1000,
+		},
+		{
+			name:        "above what's left of the night total should return error",
+			trfRepo:     sentTotals(model.TransferTotals{Daily: 500, Monthly: 500, Night: 500}, true),
+			limitRepo:   accountLimits(model.AccountTransferLimits{}),
+			limitPolicy: atNight,
+			amount:      400,
+			wantErr:     &TransferLimitExceededError{Limit: model.TransferLimitNightTotal, Remaining: 300},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trfUC := NewTransferUseCase(tt.trfRepo, accounts, ledgerRepo, tt.limitRepo, nil, nil, tt.limitPolicy, FeePolicy{})
+			got, err := trfUC.Create(backgroundCtx, TransferCreateInput{AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: NewAmount(tt.amount)})
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrTransferLimitExceeded) {
+					t.Errorf("Create() error = %v, should be ErrTransferLimitExceeded", err)
+				}
+				return
+			}
+
+			if got.Amount.Money != tt.amount {
+				t.Errorf("Create() got = %v, want a transfer of %v", got, tt.amount)
+			}
+		})
+	}
+}
+
+func TestTransferLimitExceededError_Error(t *testing.T) {
+	t.Parallel()
+
+	err := &TransferLimitExceededError{Limit: model.TransferLimitNightPerTransaction, Remaining: 100050}
+	want := "'amount' exceeds the night per transaction transfer limit, the remaining allowance is 1000.50"
+	if err.Error() != want {
+		t.Errorf("Error() = %v, want %v", err.Error(), want)
+	}
+}
+
+func Test_transferLimitUseCase_Get(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	operator := model.Principal{AccountID: "uuid-9", Roles: []model.Role{model.RoleOperator}, Scopes: model.ScopesOf([]model.Role{model.RoleOperator})}
+	limitPolicy := TransferLimitPolicy{Defaults: model.TransferLimits{PerTransaction: 1000, Daily: 3000}}
+
+	accRepo := mock.AccountRepository{
+		OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			if id != "uuid-1" {
+				return nil, repository.ErrAccountNotFound
+			}
+			return &model.Account{ID: id}, nil
+		},
+	}
+	limitRepo := mock.TransferLimitRepository{
+		OnGet: func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+			return &model.AccountTransferLimits{AccountID: accountID}, nil
+		},
+	}
+	trfRepo := mock.TransferRepository{
+		OnGetSentTotals: func(ctx context.Context, accountID model.AccountID, periods model.TransferLimitPeriods) (*model.TransferTotals, error) {
+			return &model.TransferTotals{Daily: 2500, Monthly: 2500}, nil
+		},
+	}
+	amountOf := func(money model.Money) *Amount {
+		amount := NewAmount(money)
+		return &amount
+	}
+
+	tests := []struct {
+		name      string
+		caller    model.Principal
+		accountID model.AccountID
+		want      *TransferLimitsOutput
+		wantErr   error
+	}{
+		{
+			name:      "other account should be forbidden",
+			caller:    model.Principal{AccountID: "uuid-2"},
+			accountID: "uuid-1",
+			wantErr:   ErrAuthForbidden,
+		},
+		{
+			name:      "unknown account should return not found",
+			caller:    operator,
+			accountID: "uuid-3",
+			wantErr:   repository.ErrAccountNotFound,
+		},
+		{
+			name:      "owner should get the limits and the allowance",
+			caller:    model.Principal{AccountID: "uuid-1"},
+			accountID: "uuid-1",
+			want: &TransferLimitsOutput{
+				AccountID:      "uuid-1",
+				PerTransaction: amountOf(1000),
+				Daily:          amountOf(3000),
+				Allowance:      amountOf(500),
+				AllowanceLimit: "daily",
+			},
+		},
+		{
+			name:      "operator should get the limits of any account",
+			caller:    operator,
+			accountID: "uuid-1",
+			want: &TransferLimitsOutput{
+				AccountID:      "uuid-1",
+				PerTransaction: amountOf(1000),
+				Daily:          amountOf(3000),
+				Allowance:      amountOf(500),
+				AllowanceLimit: "daily",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			limitUC := NewTransferLimitUseCase(limitRepo, trfRepo, accRepo, limitPolicy)
+			got, err := limitUC.Get(backgroundCtx, tt.caller, tt.accountID)
+			if err != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_transferLimitUseCase_Set(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	admin := model.Principal{AccountID: "uuid-9", Roles: []model.Role{model.RoleAdmin}, Scopes: model.ScopesOf([]model.Role{model.RoleAdmin})}
+	limitPolicy := TransferLimitPolicy{Defaults: model.TransferLimits{PerTransaction: 1000, Daily: 3000}}
+
+	accRepo := mock.AccountRepository{
+		OnGetBalance: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			if id != "uuid-1" {
+				return nil, repository.ErrAccountNotFound
+			}
+			return &model.Account{ID: id}, nil
+		},
+	}
+	trfRepo := mock.TransferRepository{
+		OnGetSentTotals: func(ctx context.Context, accountID model.AccountID, periods model.TransferLimitPeriods) (*model.TransferTotals, error) {
+			return &model.TransferTotals{}, nil
+		},
+	}
+	// savedLimits keeps the saved limits, so they're returned by Get
+	savedLimits := func() mock.TransferLimitRepository {
+		saved := &model.AccountTransferLimits{}
+		return mock.TransferLimitRepository{
+			OnGet: func(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+				return saved, nil
+			},
+			OnSave: func(ctx context.Context, limits *model.AccountTransferLimits) error {
+				if limits.AccountID != "uuid-1" || limits.UpdatedAt.IsZero() {
+					return errors.New("unexpected limits")
+				}
+				*saved = *limits
+				return nil
+			},
+		}
+	}
+	amountOf := func(money model.Money) *Amount {
+		amount := NewAmount(money)
+		return &amount
+	}
+
+	tests := []struct {
+		name        string
+		limitRepo   repository.TransferLimitRepository
+		caller      model.Principal
+		limitsInput TransferLimitsInput
+		want        *TransferLimitsOutput
+		wantErr     error
+	}{
+		{
+			name:        "customer should be forbidden",
+			caller:      model.Principal{AccountID: "uuid-1"},
+			limitsInput: TransferLimitsInput{AccountID: "uuid-1"},
+			wantErr:     ErrAuthForbidden,
+		},
+		{
+			name:        "negative limit should return error",
+			caller:      admin,
+			limitsInput: TransferLimitsInput{AccountID: "uuid-1", NightTotal: amountOf(-1)},
+			wantErr:     ErrTransferLimitNegative,
+		},
+		{
+			name:        "unknown account should return not found",
+			caller:      admin,
+			limitsInput: TransferLimitsInput{AccountID: "uuid-3"},
+			wantErr:     repository.ErrAccountNotFound,
+		},
+		{
+			name: "repository error should return save error",
+			limitRepo: mock.TransferLimitRepository{
+				OnSave: func(ctx context.Context, limits *model.AccountTransferLimits) error {
+					return errors.New("any database error")
+				},
+			},
+			caller:      admin,
+			limitsInput: TransferLimitsInput{AccountID: "uuid-1"},
+			wantErr:     ErrTransferLimitSave,
+		},
+		{
+			name:        "should replace the defaults and keep the ones left out",
+			limitRepo:   savedLimits(),
+			caller:      admin,
+			limitsInput: TransferLimitsInput{AccountID: "uuid-1", PerTransaction: amountOf(0), Monthly: amountOf(20000)},
+			want: &TransferLimitsOutput{
+				AccountID:      "uuid-1",
+				Daily:          amountOf(3000),
+				Monthly:        amountOf(20000),
+				Allowance:      amountOf(3000),
+				AllowanceLimit: "daily",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			limitUC := NewTransferLimitUseCase(tt.limitRepo, trfRepo, accRepo, limitPolicy)
+			got, err := limitUC.Set(backgroundCtx, tt.caller, tt.limitsInput)
+			if err != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Set() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+var (
+	// ErrTransferNotRefundable happens when refunding or reversing a refund or a reversal.
+	ErrTransferNotRefundable = errors.New("refunds and reversals can not be refunded or reversed")
+	// ErrTransferConversionNotRefundable happens when refunding or reversing a cross-currency transfer. Its amount
+	// would have to be converted back by a rate other than the one of its quote.
+	ErrTransferConversionNotRefundable = errors.New("cross-currency transfers can not be refunded or reversed")
+	// ErrTransferFullyRefunded happens when the refunds and reversals of the transfer already sum its amount.
+	ErrTransferFullyRefunded = errors.New("transfer was already fully refunded or reversed")
+	// ErrTransferRefundAmountExceeded happens when the refund amount is greater than the amount not refunded yet.
+	ErrTransferRefundAmountExceeded = errors.New("'amount' must not exceed the transfer amount not refunded yet")
+	// ErrTransferRefund happens when an error occurred and the transfer was not refunded.
+	ErrTransferRefund = errors.New("could not refund transfer")
+	// ErrTransferReverse happens when an error occurred and the transfer was not reversed.
+	ErrTransferReverse = errors.New("could not reverse transfer")
+)
+
+// TransferRefundInput represents the expected input data when refunding a transfer.
+// When the amount is not informed, what's left of the transfer is refunded.
+type TransferRefundInput struct {
+	TransferID string  `json:"-"`
+	Amount     *Amount `json:"amount,omitempty" swaggertype:"number" example:"50.00"`
+}
+
+// Refund gives back the amount of a transfer received by the caller account, as a new transfer of kind refund in
+// the opposite direction. The refunds and reversals of a transfer can't sum more than its amount.
+// The transfers not received by the caller are reported as repository.ErrTransferNotFound, so their IDs are not disclosed.
+func (trfUC transferUseCase) Refund(ctx context.Context, caller model.Principal, refundInput TransferRefundInput) (*TransferCreateOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if refundInput.Amount != nil && refundInput.Amount.Money <= 0 {
+		return nil, ErrTransferAmountNotPositive
+	}
+
+	refund, err := trfUC.refund(ctx, model.TransferID(refundInput.TransferID), model.TransferKindRefund, refundInput.Amount,
+		func(original *model.Transfer) bool {
+			return caller.Owns(original.AccountDestinationID)
+		})
+	if err != nil {
+		if isTransferRefundRejection(err) {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Interface("input", refundInput).Msg("error refunding transfer")
+		return nil, ErrTransferRefund
+	}
+
+	log.Ctx(ctx).Info().Str("id", string(refund.ID)).Str("originalTransferID", string(refund.OriginalTransferID)).
+		Msg("transfer refunded")
+
+	return newTransferCreateOutput(refund), nil
+}
+
+// Reverse undoes what's left of a mistaken transfer of any account, as a new transfer of kind reversal in the
+// opposite direction. Only callers with the model.ScopeTransfersReverse can reverse, otherwise it returns ErrAuthForbidden.
+func (trfUC transferUseCase) Reverse(ctx context.Context, caller model.Principal, id model.TransferID) (*TransferCreateOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if !caller.HasScope(model.ScopeTransfersReverse) {
+		return nil, ErrAuthForbidden
+	}
+
+	reversal, err := trfUC.refund(ctx, id, model.TransferKindReversal, nil,
+		func(original *model.Transfer) bool {
+			return true
+		})
+	if err != nil {
+		if isTransferRefundRejection(err) {
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Stack().Err(err).Str("id", string(id)).Msg("error reversing transfer")
+		return nil, ErrTransferReverse
+	}
+
+	log.Ctx(ctx).Info().Str("id", string(reversal.ID)).Str("originalTransferID", string(id)).Str("by", string(caller.AccountID)).
+		Msg("transfer reversed")
+
+	return newTransferCreateOutput(reversal), nil
+}
+
+// refund executes a refund or a reversal of the amount of the original transfer, in its currency, or of what's left
+// of it when amount is nil, and adds it to the refunded amount of the original.
+// The original transfers not allowed by canRefund are reported as repository.ErrTransferNotFound.
+func (trfUC transferUseCase) refund(
+	ctx context.Context,
+	id model.TransferID,
+	kind model.TransferKind,
+	amount *Amount,
+	canRefund func(original *model.Transfer) bool,
+) (*model.Transfer, error) {
+	if _, err := uuid.Parse(string(id)); err != nil {
+		return nil, repository.ErrTransferNotFound
+	}
+
+	data, err := trfUC.trfRepo.WithinTransaction(ctx, func(txCtx context.Context) (interface{}, error) {
+		// the row lock makes the concurrent refunds of the transfer wait, so they never sum more than its amount
+		original, err := trfUC.trfRepo.GetByIDForUpdate(txCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !canRefund(original) {
+			return nil, repository.ErrTransferNotFound
+		}
+		if !original.IsRefundable() {
+			return nil, ErrTransferNotRefundable
+		}
+		if original.IsConversion() {
+			return nil, ErrTransferConversionNotRefundable
+		}
+
+		refundAmount := original.RefundableAmount()
+		if refundAmount <= 0 {
+			return nil, ErrTransferFullyRefunded
+		}
+		if amount != nil {
+			requested, err := amount.In(original.Currency)
+			if err != nil {
+				return nil, err
+			}
+			if requested > refundAmount {
+				return nil, ErrTransferRefundAmountExceeded
+			}
+			refundAmount = requested
+		}
+
+		refund := original.NewRefundOf(kind, refundAmount)
+		err = trfUC.execute(txCtx, refund)
+		if err != nil {
+			return nil, err
+		}
+
+		original.RefundedAmount += refund.Amount
+		return refund, trfUC.trfRepo.UpdateRefundedAmount(txCtx, original)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refund, _ := data.(*model.Transfer)
+	return refund, nil
+}
+
+// isTransferRefundRejection checks whether the error is a business rule rejecting the refund or the reversal, not a failure.
+func isTransferRefundRejection(err error) bool {
+	switch err {
+	case repository.ErrTransferNotFound, ErrTransferNotRefundable, ErrTransferConversionNotRefundable,
+		ErrTransferFullyRefunded, ErrTransferRefundAmountExceeded, ErrAmountInvalid:
+		return true
+	default:
+		return isTransferRejection(err)
+	}
+}
@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_transferUseCase_Refund(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	recipient := model.Principal{AccountID: "uuid-2"}
+	transferID := model.TransferID("5a4c6d2e-1f3b-4e8a-9c7d-2b1a0f9e8d7c")
+
+	withinTransaction := func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+		return txFunc(ctx)
+	}
+	transferWith := func(kind model.TransferKind, refundedAmount model.Money) func(ctx context.Context, id model.TransferID) (*model.Transfer, error) {
+		return func(ctx context.Context, id model.TransferID) (*model.Transfer, error) {
+			return &model.Transfer{ID: id, Kind: kind, AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: 1000, RefundedAmount: refundedAmount}, nil
+		}
+	}
+	activeAccounts := mock.AccountRepository{
+		OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			return &model.Account{ID: id, Balance: 5000, Status: model.AccountStatusActive}, nil
+		},
+	}
+	ledgerRepo := mock.LedgerRepository{
+		OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+			if posting.Kind != model.LedgerPostingRefund || posting.DebitAccountID != "uuid-2" || posting.CreditAccountID != "uuid-1" {
+				return errors.New("should post a refund from the recipient to the sender")
+			}
+			return nil
+		},
+	}
+	// refundableTransfer returns a transfer of 1000 with 300 refunded, failing when the new refunded amount is not the wanted one
+	refundableTransfer := func(wantRefundedAmount model.Money) mock.TransferRepository {
+		return mock.TransferRepository{
+			OnWithinTransaction: withinTransaction,
+			OnGetByIDForUpdate:  transferWith(model.TransferKindTransfer, 300),
+			OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+				return nil
+			},
+			OnUpdateRefundedAmount: func(ctx context.Context, transfer *model.Transfer) error {
+				if transfer.RefundedAmount != wantRefundedAmount {
+					return errors.New("unexpected refunded amount")
+				}
+				return nil
+			},
+		}
+	}
+	amountOf := func(money model.Money) *Amount {
+		amount := NewAmount(money)
+		return &amount
+	}
+
+	type fields struct {
+		trfRepo repository.TransferRepository
+		accRepo repository.AccountRepository
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		caller      model.Principal
+		refundInput TransferRefundInput
+		wantAmount  model.Money
+		wantErr     error
+	}{
+		{
+			name:        "zero amount should return error",
+			caller:      recipient,
+			refundInput: TransferRefundInput{TransferID: string(transferID), Amount: amountOf(0)},
+			wantErr:     ErrTransferAmountNotPositive,
+		},
+		{
+			name:        "id not uuid should return not found error",
+			caller:      recipient,
+			refundInput: TransferRefundInput{TransferID: "any-id"},
+			wantErr:     repository.ErrTransferNotFound,
+		},
+		{
+			name: "transfer sent by the caller should return not found error",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByIDForUpdate:  transferWith(model.TransferKindTransfer, 0),
+				},
+			},
+			caller:      model.Principal{AccountID: "uuid-1"},
+			refundInput: TransferRefundInput{TransferID: string(transferID)},
+			wantErr:     repository.ErrTransferNotFound,
+		},
+		{
+			name: "refund should not be refundable",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByIDForUpdate:  transferWith(model.TransferKindRefund, 0),
+				},
+			},
+			caller:      recipient,
+			refundInput: TransferRefundInput{TransferID: string(transferID)},
+			wantErr:     ErrTransferNotRefundable,
+		},
+		{
+			name: "cross-currency transfer should not be refundable",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByIDForUpdate: func(ctx context.Context, id model.TransferID) (*model.Transfer, error) {
+						transfer := &model.Transfer{ID: id, Kind: model.TransferKindTransfer, AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: 1000}
+						transfer.ConvertBy(model.NewFXQuote("uuid-1", model.CurrencyUSD, model.CurrencyBRL, 512340000, 1000, 0))
+						return transfer, nil
+					},
+				},
+			},
+			caller:      recipient,
+			refundInput: TransferRefundInput{TransferID: string(transferID)},
+			wantErr:     ErrTransferConversionNotRefundable,
+		},
+		{
+			name: "fully refunded transfer should return error",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByIDForUpdate:  transferWith(model.TransferKindTransfer, 1000),
+				},
+			},
+			caller:      recipient,
+			refundInput: TransferRefundInput{TransferID: string(transferID)},
+			wantErr:     ErrTransferFullyRefunded,
+		},
+		{
+			name: "amount greater than not refunded should return error",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByIDForUpdate:  transferWith(model.TransferKindTransfer, 600),
+				},
+			},
+			caller:      recipient,
+			refundInput: TransferRefundInput{TransferID: string(transferID), Amount: amountOf(401)},
+			wantErr:     ErrTransferRefundAmountExceeded,
+		},
+		{
+			name: "insufficient balance should return error",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByIDForUpdate:  transferWith(model.TransferKindTransfer, 0),
+				},
+				accRepo: mock.AccountRepository{
+					OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+						return &model.Account{ID: id, Balance: 10, Status: model.AccountStatusActive}, nil
+					},
+				},
+			},
+			caller:      recipient,
+			refundInput: TransferRefundInput{TransferID: string(transferID)},
+			wantErr:     ErrAccountCurrentBalanceInsufficient,
+		},
+		{
+			name: "repo update error should return refund error",
+			fields: fields{
+				trfRepo: mock.TransferRepository{
+					OnWithinTransaction: withinTransaction,
+					OnGetByIDForUpdate:  transferWith(model.TransferKindTransfer, 0),
+					OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+						return nil
+					},
+					OnUpdateRefundedAmount: func(ctx context.Context, transfer *model.Transfer) error {
+						return errors.New("any database error")
+					},
+				},
+				accRepo: activeAccounts,
+			},
+			caller:      recipient,
+			refundInput: TransferRefundInput{TransferID: string(transferID)},
+			wantErr:     ErrTransferRefund,
+		},
+		{
+			name: "partial refund",
+			fields: fields{
+				trfRepo: refundableTransfer(450),
+				accRepo: activeAccounts,
+			},
+			caller:      recipient,
+			refundInput: TransferRefundInput{TransferID: string(transferID), Amount: amountOf(150)},
+			wantAmount:  150,
+		},
+		{
+			name: "refund without amount should refund what's left",
+			fields: fields{
+				trfRepo: refundableTransfer(1000),
+				accRepo: activeAccounts,
+			},
+			caller:      recipient,
+			refundInput: TransferRefundInput{TransferID: string(transferID)},
+			wantAmount:  700,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trfUC := NewTransferUseCase(tt.fields.trfRepo, tt.fields.accRepo, ledgerRepo, nil, nil, nil, TransferLimitPolicy{}, FeePolicy{})
+			got, err := trfUC.Refund(backgroundCtx, tt.caller, tt.refundInput)
+			if err != tt.wantErr {
+				t.Fatalf("Refund() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Kind != string(model.TransferKindRefund) || got.OriginalTransferID != string(transferID) || got.AccountOriginID != "uuid-2" ||
+				got.Amount.Money != tt.wantAmount || got.RefundedAmount != nil {
+				t.Errorf("Refund() got = %v, want a refund of %v to the sender", got, tt.wantAmount)
+			}
+		})
+	}
+}
+
+func Test_transferUseCase_Reverse(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	operator := model.Principal{AccountID: "uuid-9", Roles: []model.Role{model.RoleOperator}, Scopes: model.ScopesOf([]model.Role{model.RoleOperator})}
+	transferID := model.TransferID("5a4c6d2e-1f3b-4e8a-9c7d-2b1a0f9e8d7c")
+
+	trfRepo := mock.TransferRepository{
+		OnWithinTransaction: func(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+			return txFunc(ctx)
+		},
+		OnGetByIDForUpdate: func(ctx context.Context, id model.TransferID) (*model.Transfer, error) {
+			return &model.Transfer{ID: id, Kind: model.TransferKindTransfer, AccountOriginID: "uuid-1", AccountDestinationID: "uuid-2", Amount: 1000, RefundedAmount: 250}, nil
+		},
+		OnCreate: func(ctx context.Context, transfer *model.Transfer) error {
+			return nil
+		},
+		OnUpdateRefundedAmount: func(ctx context.Context, transfer *model.Transfer) error {
+			if transfer.RefundedAmount != 1000 {
+				return errors.New("should be fully refunded")
+			}
+			return nil
+		},
+	}
+	accRepo := mock.AccountRepository{
+		OnGetBalanceForUpdate: func(ctx context.Context, id model.AccountID) (*model.Account, error) {
+			return &model.Account{ID: id, Balance: 5000, Status: model.AccountStatusActive}, nil
+		},
+	}
+	ledgerRepo := mock.LedgerRepository{
+		OnPost: func(ctx context.Context, posting *model.LedgerPosting) error {
+			if posting.Kind != model.LedgerPostingReversal {
+				return errors.New("should post a reversal")
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		caller  model.Principal
+		wantErr error
+	}{
+		{
+			name:    "customer should be forbidden",
+			caller:  model.Principal{AccountID: "uuid-2"},
+			wantErr: ErrAuthForbidden,
+		},
+		{
+			name:    "operator should reverse what's left of any transfer",
+			caller:  operator,
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trfUC := NewTransferUseCase(trfRepo, accRepo, ledgerRepo, nil, nil, nil, TransferLimitPolicy{}, FeePolicy{})
+			got, err := trfUC.Reverse(backgroundCtx, tt.caller, transferID)
+			if err != tt.wantErr {
+				t.Fatalf("Reverse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Kind != string(model.TransferKindReversal) || got.OriginalTransferID != string(transferID) || got.Amount.Money != 750 {
+				t.Errorf("Reverse() got = %v, want a reversal of 750", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type currencyPair struct {
+	source model.Currency
+	target model.Currency
+}
+
+type fxRateProvider struct {
+	rates map[currencyPair]model.FXRate
+}
+
+// NewFXRateProvider instantiates a new FXRateProvider with a static table of rates, decimal strings keyed by currency
+// pair, like {"USD/BRL": "5.1234"} for 5.1234 BRL per USD. The rate of the opposite pair, when not in the table, is
+// the inverse one.
+func NewFXRateProvider(rates map[string]string) (repository.FXRateProvider, error) {
+	provider := &fxRateProvider{
+		rates: make(map[currencyPair]model.FXRate, len(rates)),
+	}
+
+	for key, value := range rates {
+		pair, err := parseCurrencyPair(key)
+		if err != nil {
+			return nil, err
+		}
+
+		rate, err := model.ParseFXRate(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange rate %q of %s: %w", value, key, err)
+		}
+
+		provider.rates[pair] = rate
+	}
+
+	return provider, nil
+}
+
+// NewFXRateProviderFromFile instantiates a new FXRateProvider with the static table of rates read from a JSON file,
+// an object in the format of NewFXRateProvider. The file is only read once, so changes require a restart.
+func NewFXRateProviderFromFile(filename string) (repository.FXRateProvider, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var rates map[string]string
+	err = json.Unmarshal(content, &rates)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exchange rates file %s: %w", filename, err)
+	}
+
+	return NewFXRateProvider(rates)
+}
+
+func parseCurrencyPair(s string) (currencyPair, error) {
+	codes := strings.Split(strings.ToUpper(strings.TrimSpace(s)), "/")
+	if len(codes) != 2 {
+		return currencyPair{}, fmt.Errorf("invalid currency pair %q, it must be like USD/BRL", s)
+	}
+
+	pair := currencyPair{source: model.Currency(codes[0]), target: model.Currency(codes[1])}
+	if !pair.source.IsValid() || !pair.target.IsValid() || pair.source == pair.target {
+		return currencyPair{}, fmt.Errorf("invalid currency pair %q, it must have two different supported currencies", s)
+	}
+
+	return pair, nil
+}
+
+func (provider fxRateProvider) GetRate(_ context.Context, source, target model.Currency) (model.FXRate, error) {
+	if rate, ok := provider.rates[currencyPair{source: source, target: target}]; ok {
+		return rate, nil
+	}
+
+	if rate, ok := provider.rates[currencyPair{source: target, target: source}]; ok {
+		return rate.Inverse(), nil
+	}
+
+	return 0, repository.ErrFXRateNotFound
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+func Test_fxRateProvider_GetRate(t *testing.T) {
+	t.Parallel()
+
+	provider, err := NewFXRateProviderFromFile("testdata/fx_rates.json")
+	if err != nil {
+		t.Fatalf("NewFXRateProviderFromFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		source  model.Currency
+		target  model.Currency
+		want    model.FXRate
+		wantErr error
+	}{
+		{name: "rate of the table", source: model.CurrencyUSD, target: model.CurrencyBRL, want: 512340000},
+		{name: "inverse rate of the opposite pair", source: model.CurrencyBRL, target: model.CurrencyEUR, want: 18181818},
+		{name: "pair not in the table should fail", source: model.CurrencyUSD, target: model.CurrencyEUR, wantErr: repository.ErrFXRateNotFound},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := provider.GetRate(context.Background(), tt.source, tt.target)
+			if err != tt.wantErr {
+				t.Errorf("GetRate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetRate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFXRateProvider(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rates   map[string]string
+		wantErr bool
+	}{
+		{name: "lowercase pair", rates: map[string]string{"usd/brl": "5.10"}},
+		{name: "empty table", rates: map[string]string{}},
+		{name: "unsupported currency should fail", rates: map[string]string{"XYZ/BRL": "1"}, wantErr: true},
+		{name: "same currency should fail", rates: map[string]string{"BRL/BRL": "1"}, wantErr: true},
+		{name: "malformed pair should fail", rates: map[string]string{"USDBRL": "5.10"}, wantErr: true},
+		{name: "zero rate should fail", rates: map[string]string{"USD/BRL": "0"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewFXRateProvider(tt.rates)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFXRateProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewFXRateProviderFromFile("testdata/missing.json"); err == nil {
+		t.Errorf("NewFXRateProviderFromFile() missing file error = nil, want an error")
+	}
+}
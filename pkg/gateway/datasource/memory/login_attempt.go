@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type loginAttempt struct {
+	attempts  model.LoginAttempts
+	expiresAt time.Time
+}
+
+// sweepInterval is how many failures are registered between the removals of all the expired attempts.
+const sweepInterval = 1024
+
+type loginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]*loginAttempt
+	writes   int
+	now      func() time.Time
+}
+
+// NewLoginAttemptRepository instantiates a new login attempt in-memory repository.
+// The attempts aren't shared between instances, so it should only be used when running a single instance.
+func NewLoginAttemptRepository() repository.LoginAttemptRepository {
+	return &loginAttemptRepository{
+		attempts: make(map[string]*loginAttempt),
+		now:      time.Now,
+	}
+}
+
+// get returns the non-expired attempts of the key, removing it if expired. The lock must be held.
+func (attRepo *loginAttemptRepository) get(key string) (*loginAttempt, bool) {
+	attempt, ok := attRepo.attempts[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !attRepo.now().Before(attempt.expiresAt) {
+		delete(attRepo.attempts, key)
+		return nil, false
+	}
+
+	return attempt, true
+}
+
+// sweep removes all the expired attempts. The lock must be held.
+func (attRepo *loginAttemptRepository) sweep() {
+	now := attRepo.now()
+	for key, attempt := range attRepo.attempts {
+		if !now.Before(attempt.expiresAt) {
+			delete(attRepo.attempts, key)
+		}
+	}
+}
+
+func (attRepo *loginAttemptRepository) Get(_ context.Context, key string) (*model.LoginAttempts, error) {
+	attRepo.mu.Lock()
+	defer attRepo.mu.Unlock()
+
+	attempt, ok := attRepo.get(key)
+	if !ok {
+		return &model.LoginAttempts{}, nil
+	}
+
+	attempts := attempt.attempts
+	return &attempts, nil
+}
+
+func (attRepo *loginAttemptRepository) RegisterFailure(_ context.Context, key string, window time.Duration) (int, error) {
+	attRepo.mu.Lock()
+	defer attRepo.mu.Unlock()
+
+	// expired attempts are only removed when read, so sweep them from time to time
+	attRepo.writes++
+	if attRepo.writes%sweepInterval == 0 {
+		attRepo.sweep()
+	}
+
+	attempt, ok := attRepo.get(key)
+	if !ok {
+		attempt = &loginAttempt{}
+		attRepo.attempts[key] = attempt
+	}
+
+	attempt.attempts.Failures++
+	if expiresAt := attRepo.now().Add(window); expiresAt.After(attempt.expiresAt) {
+		attempt.expiresAt = expiresAt
+	}
+
+	return attempt.attempts.Failures, nil
+}
+
+func (attRepo *loginAttemptRepository) Lock(_ context.Context, key string, until time.Time) error {
+	attRepo.mu.Lock()
+	defer attRepo.mu.Unlock()
+
+	attempt, ok := attRepo.get(key)
+	if !ok {
+		attempt = &loginAttempt{}
+		attRepo.attempts[key] = attempt
+	}
+
+	attempt.attempts.LockedUntil = until
+	if until.After(attempt.expiresAt) {
+		attempt.expiresAt = until
+	}
+
+	return nil
+}
+
+func (attRepo *loginAttemptRepository) Reset(_ context.Context, key string) error {
+	attRepo.mu.Lock()
+	defer attRepo.mu.Unlock()
+
+	delete(attRepo.attempts, key)
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func Test_loginAttemptRepository(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	now := time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	attRepo := NewLoginAttemptRepository().(*loginAttemptRepository)
+	attRepo.now = func() time.Time { return now }
+
+	got, err := attRepo.Get(backgroundCtx, "cpf:1")
+	if err != nil || got.Failures != 0 || !got.LockedUntil.IsZero() {
+		t.Fatalf("Get() empty = %+v, error = %v, want zero attempts", got, err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		failures, err := attRepo.RegisterFailure(backgroundCtx, "cpf:1", time.Hour)
+		if err != nil || failures != i {
+			t.Fatalf("RegisterFailure() = %v, error = %v, want %v", failures, err, i)
+		}
+	}
+
+	if err = attRepo.Lock(backgroundCtx, "cpf:1", now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	got, _ = attRepo.Get(backgroundCtx, "cpf:1")
+	if got.Failures != 3 || !got.IsLocked(now) {
+		t.Errorf("Get() = %+v, want 3 failures and locked", got)
+	}
+
+	other, _ := attRepo.Get(backgroundCtx, "ip:1")
+	if other.Failures != 0 {
+		t.Errorf("Get() other key = %+v, want zero attempts", other)
+	}
+
+	// the lock outlives the failures window
+	now = now.Add(90 * time.Minute)
+	got, _ = attRepo.Get(backgroundCtx, "cpf:1")
+	if got.Failures != 3 || !got.IsLocked(now) {
+		t.Errorf("Get() after window = %+v, want still locked", got)
+	}
+
+	now = now.Add(time.Hour)
+	got, _ = attRepo.Get(backgroundCtx, "cpf:1")
+	if got.Failures != 0 || got.IsLocked(now) {
+		t.Errorf("Get() after lock = %+v, want zero attempts", got)
+	}
+
+	_, _ = attRepo.RegisterFailure(backgroundCtx, "cpf:1", time.Hour)
+	if err = attRepo.Reset(backgroundCtx, "cpf:1"); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	got, _ = attRepo.Get(backgroundCtx, "cpf:1")
+	if got.Failures != 0 {
+		t.Errorf("Get() after reset = %+v, want zero attempts", got)
+	}
+}
+
+func Test_loginAttemptRepository_sweep(t *testing.T) {
+	t.Parallel()
+
+	backgroundCtx := context.Background()
+	now := time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	attRepo := NewLoginAttemptRepository().(*loginAttemptRepository)
+	attRepo.now = func() time.Time { return now }
+
+	for i := 0; i < sweepInterval-1; i++ {
+		_, _ = attRepo.RegisterFailure(backgroundCtx, fmt.Sprintf("ip:%d", i), time.Minute)
+	}
+
+	now = now.Add(time.Hour)
+	_, _ = attRepo.RegisterFailure(backgroundCtx, "ip:new", time.Minute)
+
+	if len(attRepo.attempts) != 1 {
+		t.Errorf("RegisterFailure() should sweep expired attempts, got %d attempts", len(attRepo.attempts))
+	}
+}
@@ -2,6 +2,10 @@ package postgres
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -22,9 +26,9 @@ func NewAccountRepository(db *pgxpool.Pool) repository.AccountRepository {
 func (accRepo accountRepository) Create(ctx context.Context, account *model.Account) error {
 	var query = `
 		INSERT INTO
-			accounts (id, name, cpf, secret, balance, created_at)
+			accounts (id, name, cpf, secret, currency, balance, roles, created_at)
 		VALUES
-			($1, $2, $3, $4, $5, $6)
+			($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
 	_, err := getConnFromCtx(ctx, accRepo.db).Exec(
@@ -34,7 +38,9 @@ func (accRepo accountRepository) Create(ctx context.Context, account *model.Acco
 		account.Name,
 		account.CPF,
 		account.Secret,
+		currencyOrDefault(account.Currency),
 		account.Balance,
+		rolesToStrings(account.Roles),
 		account.CreatedAt,
 	)
 	if err != nil {
@@ -52,11 +58,20 @@ func (accRepo accountRepository) ExistsByCPF(ctx context.Context, cpf model.CPF)
 	return accountExists, err
 }
 
+// accountColumns are the columns read by scanAccount.
+const accountColumns = "id, name, cpf, secret, currency, balance, credit_limit, tier, roles, status, status_reason, status_changed_at, created_at"
+
 func (accRepo accountRepository) GetByCPF(ctx context.Context, cpf model.CPF) (*model.Account, error) {
-	var query = "SELECT id, name, cpf, secret, balance, created_at FROM accounts WHERE cpf = $1"
+	return accRepo.getAccount(ctx, "SELECT "+accountColumns+" FROM accounts WHERE cpf = $1", string(cpf))
+}
 
+func (accRepo accountRepository) GetByID(ctx context.Context, id model.AccountID) (*model.Account, error) {
+	return accRepo.getAccount(ctx, "SELECT "+accountColumns+" FROM accounts WHERE id = $1", string(id))
+}
+
+func (accRepo accountRepository) getAccount(ctx context.Context, query string, arg string) (*model.Account, error) {
 	account := new(model.Account)
-	err := getConnFromCtx(ctx, accRepo.db).QueryRow(ctx, query, cpf).Scan(&account.ID, &account.Name, &account.CPF, &account.Secret, &account.Balance, &account.CreatedAt)
+	err := scanAccount(getConnFromCtx(ctx, accRepo.db).QueryRow(ctx, query, arg), account)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, repository.ErrAccountNotFound
@@ -67,15 +82,72 @@ func (accRepo accountRepository) GetByCPF(ctx context.Context, cpf model.CPF) (*
 	return account, nil
 }
 
-func (accRepo accountRepository) Fetch(ctx context.Context) ([]model.Account, error) {
+// scanAccount scans the accountColumns into the account.
+func scanAccount(row pgx.Row, account *model.Account) error {
+	var roles []string
+	var statusChangedAt *time.Time
+	err := row.Scan(&account.ID, &account.Name, &account.CPF, &account.Secret, &account.Currency, &account.Balance, &account.CreditLimit, &account.Tier, &roles, &account.Status, &account.StatusReason, &statusChangedAt, &account.CreatedAt)
+	if err != nil {
+		return err
+	}
+	account.Roles = stringsToRoles(roles)
+	if statusChangedAt != nil {
+		account.StatusChangedAt = *statusChangedAt
+	}
+
+	return nil
+}
+
+// accountListColumns are the columns read when listing accounts. It must never have the secret.
+const accountListColumns = "id, name, cpf, currency, balance, status, created_at"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (accRepo accountRepository) Fetch(ctx context.Context, filter model.AccountFilter) ([]model.Account, error) {
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	var conditions []string
+	if filter.Name != "" {
+		// the trigram index serves both the prefix and the similarity search
+		conditions = append(conditions, fmt.Sprintf("(name ILIKE %s OR name %% %s)", addArg(likeEscaper.Replace(filter.Name)+"%"), addArg(filter.Name)))
+	}
+	if filter.CPF != "" {
+		conditions = append(conditions, "cpf = "+addArg(string(filter.CPF)))
+	}
+
+	sortColumn, direction, comparison := "created_at", "asc", ">"
+	if filter.Sort == model.AccountSortName {
+		sortColumn = "name"
+	}
+	if filter.Descending {
+		direction, comparison = "desc", "<"
+	}
+	if filter.After != nil {
+		var sortValue interface{} = filter.After.CreatedAt
+		if filter.Sort == model.AccountSortName {
+			sortValue = filter.After.Name
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (%s, %s::uuid)", sortColumn, comparison, addArg(sortValue), addArg(string(filter.After.ID))))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
 	var query = `
 		SELECT
-			id, name, cpf, secret, balance, created_at
+			` + accountListColumns + `
 		FROM accounts
-		ORDER BY created_at asc
-	`
+		` + where + `
+		ORDER BY ` + sortColumn + ` ` + direction + `, id ` + direction + `
+		LIMIT ` + addArg(filter.Limit)
 
-	rows, err := getConnFromCtx(ctx, accRepo.db).Query(ctx, query)
+	rows, err := getConnFromCtx(ctx, accRepo.db).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +156,7 @@ func (accRepo accountRepository) Fetch(ctx context.Context) ([]model.Account, er
 	var accounts = make([]model.Account, 0)
 	for rows.Next() {
 		var account model.Account
-		err := rows.Scan(&account.ID, &account.Name, &account.CPF, &account.Secret, &account.Balance, &account.CreatedAt)
+		err := rows.Scan(&account.ID, &account.Name, &account.CPF, &account.Currency, &account.Balance, &account.Status, &account.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -99,12 +171,18 @@ func (accRepo accountRepository) Fetch(ctx context.Context) ([]model.Account, er
 }
 
 func (accRepo accountRepository) GetBalance(ctx context.Context, id model.AccountID) (*model.Account, error) {
-	var query = "SELECT balance FROM accounts WHERE id = $1"
+	return accRepo.getBalance(ctx, "SELECT currency, balance, credit_limit, tier, status FROM accounts WHERE id = $1", id)
+}
+
+func (accRepo accountRepository) GetBalanceForUpdate(ctx context.Context, id model.AccountID) (*model.Account, error) {
+	return accRepo.getBalance(ctx, "SELECT currency, balance, credit_limit, tier, status FROM accounts WHERE id = $1 FOR UPDATE", id)
+}
 
+func (accRepo accountRepository) getBalance(ctx context.Context, query string, id model.AccountID) (*model.Account, error) {
 	account := new(model.Account)
 	account.ID = id
 
-	err := getConnFromCtx(ctx, accRepo.db).QueryRow(ctx, query, string(id)).Scan(&account.Balance)
+	err := getConnFromCtx(ctx, accRepo.db).QueryRow(ctx, query, string(id)).Scan(&account.Currency, &account.Balance, &account.CreditLimit, &account.Tier, &account.Status)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, repository.ErrAccountNotFound
@@ -115,13 +193,80 @@ func (accRepo accountRepository) GetBalance(ctx context.Context, id model.Accoun
 	return account, nil
 }
 
-func (accRepo accountRepository) UpdateBalance(ctx context.Context, id model.AccountID, balance model.Money) error {
-	query := "UPDATE accounts SET balance = $1 WHERE id = $2"
+func (accRepo accountRepository) UpdateStatus(ctx context.Context, account *model.Account) error {
+	var query = `
+		UPDATE accounts
+		SET status = $2, status_reason = $3, status_changed_at = $4
+		WHERE id = $1
+	`
 
-	_, err := getConnFromCtx(ctx, accRepo.db).Exec(ctx, query, balance, string(id))
+	tag, err := getConnFromCtx(ctx, accRepo.db).Exec(ctx, query, string(account.ID), account.Status, account.StatusReason, account.StatusChangedAt)
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrAccountNotFound
+	}
 
 	return nil
 }
+
+func (accRepo accountRepository) UpdateCreditLimit(ctx context.Context, account *model.Account) error {
+	var query = `
+		UPDATE accounts
+		SET credit_limit = $2
+		WHERE id = $1
+	`
+
+	tag, err := getConnFromCtx(ctx, accRepo.db).Exec(ctx, query, string(account.ID), account.CreditLimit)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrAccountNotFound
+	}
+
+	return nil
+}
+
+func (accRepo accountRepository) UpdateTier(ctx context.Context, account *model.Account) error {
+	var query = `
+		UPDATE accounts
+		SET tier = $2
+		WHERE id = $1
+	`
+
+	tag, err := getConnFromCtx(ctx, accRepo.db).Exec(ctx, query, string(account.ID), account.Tier)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrAccountNotFound
+	}
+
+	return nil
+}
+
+// rolesToStrings converts the roles to a non-nil slice, so they are never saved as NULL.
+func rolesToStrings(roles []model.Role) []string {
+	strs := make([]string, 0, len(roles))
+	for _, role := range roles {
+		strs = append(strs, string(role))
+	}
+
+	return strs
+}
+
+// stringsToRoles converts the saved roles, returning nil if there is none.
+func stringsToRoles(strs []string) []model.Role {
+	if len(strs) == 0 {
+		return nil
+	}
+
+	roles := make([]model.Role, 0, len(strs))
+	for _, str := range strs {
+		roles = append(roles, model.Role(str))
+	}
+
+	return roles
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
 )
 
 func Test_accountRepository_Create(t *testing.T) {
@@ -225,7 +226,8 @@ func Test_accountRepository_Fetch(t *testing.T) {
 		db *pgxpool.Pool
 	}
 	type args struct {
-		ctx context.Context
+		ctx    context.Context
+		filter model.AccountFilter
 	}
 	tests := []struct {
 		name      string
@@ -241,7 +243,8 @@ func Test_accountRepository_Fetch(t *testing.T) {
 				db: testDbPool,
 			},
 			args: args{
-				ctx: backgroundCtx,
+				ctx:    backgroundCtx,
+				filter: model.AccountFilter{Sort: model.AccountSortCreatedAt, Limit: 10},
 			},
 			want:    []model.Account{},
 			wantErr: false,
@@ -255,7 +258,8 @@ func Test_accountRepository_Fetch(t *testing.T) {
 				db: testDbPool,
 			},
 			args: args{
-				ctx: backgroundCtx,
+				ctx:    backgroundCtx,
+				filter: model.AccountFilter{Sort: model.AccountSortCreatedAt, Limit: 10},
 			},
 			want: []model.Account{
 				{
@@ -264,6 +268,7 @@ func Test_accountRepository_Fetch(t *testing.T) {
 					CPF:       "00000000001",
 					Secret:    "secret001",
 					Balance:   1,
+					Status:    model.AccountStatusActive,
 					CreatedAt: time.Now().Round(time.Microsecond),
 				},
 			},
@@ -286,7 +291,8 @@ func Test_accountRepository_Fetch(t *testing.T) {
 				db: testDbPool,
 			},
 			args: args{
-				ctx: backgroundCtx,
+				ctx:    backgroundCtx,
+				filter: model.AccountFilter{Sort: model.AccountSortCreatedAt, Limit: 10},
 			},
 			want: []model.Account{
 				{
@@ -295,6 +301,7 @@ func Test_accountRepository_Fetch(t *testing.T) {
 					CPF:       "00000000001",
 					Secret:    "secret001",
 					Balance:   1,
+					Status:    model.AccountStatusActive,
 					CreatedAt: time.Now().Round(time.Microsecond),
 				},
 				{
@@ -303,6 +310,7 @@ func Test_accountRepository_Fetch(t *testing.T) {
 					CPF:       "00000000002",
 					Secret:    "secret002",
 					Balance:   2,
+					Status:    model.AccountStatusActive,
 					CreatedAt: time.Now().Round(time.Microsecond),
 				},
 			},
@@ -325,7 +333,8 @@ func Test_accountRepository_Fetch(t *testing.T) {
 				db: testDbPool,
 			},
 			args: args{
-				ctx: backgroundCtx,
+				ctx:    backgroundCtx,
+				filter: model.AccountFilter{Sort: model.AccountSortCreatedAt, Limit: 10},
 			},
 			want: []model.Account{
 				{
@@ -334,6 +343,7 @@ func Test_accountRepository_Fetch(t *testing.T) {
 					CPF:       "00000000001",
 					Secret:    "secret001",
 					Balance:   1,
+					Status:    model.AccountStatusActive,
 					CreatedAt: time.Now().Add(-1 * time.Minute).Round(time.Microsecond),
 				},
 				{
@@ -342,6 +352,7 @@ func Test_accountRepository_Fetch(t *testing.T) {
 					CPF:       "00000000002",
 					Secret:    "secret002",
 					Balance:   2,
+					Status:    model.AccountStatusActive,
 					CreatedAt: time.Now().Round(time.Microsecond),
 				},
 			},
@@ -368,13 +379,112 @@ func Test_accountRepository_Fetch(t *testing.T) {
 			}
 
 			accRepo := NewAccountRepository(tt.fields.db)
-			got, err := accRepo.Fetch(tt.args.ctx)
+			got, err := accRepo.Fetch(tt.args.ctx, tt.args.filter)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Fetch() got = %v, want %v", got, tt.want)
+
+			// the secret is never read, and the accounts are created in the default currency
+			want := make([]model.Account, 0, len(tt.want))
+			for _, account := range tt.want {
+				account.Secret = ""
+				account.Currency = model.DefaultCurrency
+				want = append(want, account)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Fetch() got = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_accountRepository_Fetch_search(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	// oldest first: Lisa, Bart, Homer, Marge
+	start := time.Now().Add(-time.Hour).Round(time.Microsecond)
+	accounts := []model.Account{
+		{ID: model.NewAccountID(), Name: "Lisa Simpson", CPF: "00000000001", CreatedAt: start},
+		{ID: model.NewAccountID(), Name: "Bart Simpson", CPF: "00000000002", CreatedAt: start.Add(time.Minute)},
+		{ID: model.NewAccountID(), Name: "Homer Simpson", CPF: "00000000003", CreatedAt: start.Add(2 * time.Minute)},
+		{ID: model.NewAccountID(), Name: "Marge Simpson", CPF: "00000000004", CreatedAt: start.Add(3 * time.Minute)},
+	}
+	for _, v := range accounts {
+		_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret, created_at) VALUES ($1, $2, $3, $4, $5)",
+			string(v.ID), v.Name, v.CPF, "any secret", v.CreatedAt)
+		if err != nil {
+			t.Fatalf("Fetch() error on runBefore = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter model.AccountFilter
+		want   []model.Account
+	}{
+		{
+			name:   "should limit the page",
+			filter: model.AccountFilter{Sort: model.AccountSortCreatedAt, Limit: 2},
+			want:   accounts[:2],
+		},
+		{
+			name:   "should start after the cursor",
+			filter: model.AccountFilter{Sort: model.AccountSortCreatedAt, Limit: 2, After: &model.AccountCursor{ID: accounts[1].ID, CreatedAt: accounts[1].CreatedAt}},
+			want:   accounts[2:],
+		},
+		{
+			name:   "should sort by created_at descending",
+			filter: model.AccountFilter{Sort: model.AccountSortCreatedAt, Descending: true, Limit: 2},
+			want:   []model.Account{accounts[3], accounts[2]},
+		},
+		{
+			name:   "should sort by name",
+			filter: model.AccountFilter{Sort: model.AccountSortName, Limit: 10},
+			want:   []model.Account{accounts[1], accounts[2], accounts[0], accounts[3]},
+		},
+		{
+			name:   "should sort by name descending after the cursor",
+			filter: model.AccountFilter{Sort: model.AccountSortName, Descending: true, Limit: 10, After: &model.AccountCursor{ID: accounts[0].ID, Name: accounts[0].Name}},
+			want:   []model.Account{accounts[2], accounts[1]},
+		},
+		{
+			name:   "should search the name prefix ignoring case",
+			filter: model.AccountFilter{Name: "hom", Sort: model.AccountSortCreatedAt, Limit: 10},
+			want:   accounts[2:3],
+		},
+		{
+			name:   "should search similar names",
+			filter: model.AccountFilter{Name: "Bart Simpsn", Sort: model.AccountSortCreatedAt, Limit: 10},
+			want:   accounts[1:2],
+		},
+		{
+			name:   "should escape the name wildcards",
+			filter: model.AccountFilter{Name: "Ma_ge", Sort: model.AccountSortCreatedAt, Limit: 10},
+			want:   []model.Account{},
+		},
+		{
+			name:   "should search the exact cpf",
+			filter: model.AccountFilter{CPF: "00000000003", Sort: model.AccountSortCreatedAt, Limit: 10},
+			want:   accounts[2:3],
+		},
+	}
+	accRepo := NewAccountRepository(testDbPool)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := accRepo.Fetch(backgroundCtx, tt.filter)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Fetch() got %v accounts, want %v", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].ID != tt.want[i].ID || got[i].Secret != "" {
+					t.Errorf("Fetch() got[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
 			}
 		})
 	}
@@ -449,8 +559,11 @@ func Test_accountRepository_GetBalance(t *testing.T) {
 			},
 			want: func(args args) *model.Account {
 				return &model.Account{
-					ID:      args.id,
-					Balance: 1050,
+					ID:       args.id,
+					Currency: model.CurrencyBRL,
+					Balance:  1050,
+					Tier:     model.AccountTierStandard,
+					Status:   model.AccountStatusActive,
 				}
 			},
 			wantErr: false,
@@ -485,6 +598,114 @@ func Test_accountRepository_GetBalance(t *testing.T) {
 	}
 }
 
+func Test_accountRepository_GetBalanceForUpdate(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	type fields struct {
+		db *pgxpool.Pool
+	}
+	type args struct {
+		ctx context.Context
+		id  model.AccountID
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		want      func(args) *model.Account
+		wantErr   bool
+		runBefore func(args)
+	}{
+		{
+			name: "should return error if empty db",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx: backgroundCtx,
+				id:  model.AccountID(uuid.NewString()),
+			},
+			want: func(args args) *model.Account {
+				return nil
+			},
+			wantErr: true,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
+		{
+			name: "should return error if not found",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx: backgroundCtx,
+				id:  model.AccountID(uuid.NewString()),
+			},
+			want: func(args args) *model.Account {
+				return nil
+			},
+			wantErr: true,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+
+				_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret, balance, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+					uuid.NewString(), "Any name", "11111111111", "any secret", 0, time.Now())
+				if err != nil {
+					t.Errorf("GetBalanceForUpdate() error on runBefore = %v", err)
+				}
+			},
+		},
+		{
+			name: "should return success",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx: backgroundCtx,
+				id:  model.AccountID(uuid.NewString()),
+			},
+			want: func(args args) *model.Account {
+				return &model.Account{
+					ID:       args.id,
+					Currency: model.CurrencyBRL,
+					Balance:  1050,
+					Tier:     model.AccountTierStandard,
+					Status:   model.AccountStatusActive,
+				}
+			},
+			wantErr: false,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+
+				_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret, balance, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+					string(args.id), "Any name", "12345678911", "any secret", 1050, time.Now())
+				if err != nil {
+					t.Errorf("GetBalanceForUpdate() error on runBefore = %v", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.runBefore != nil {
+				tt.runBefore(tt.args)
+			}
+
+			accRepo := NewAccountRepository(tt.fields.db)
+			got, err := accRepo.GetBalanceForUpdate(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetBalanceForUpdate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			want := tt.want(tt.args)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GetBalanceForUpdate() got = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
 func Test_accountRepository_GetByCPF(t *testing.T) {
 	backgroundCtx := context.Background()
 
@@ -560,7 +781,10 @@ func Test_accountRepository_GetByCPF(t *testing.T) {
 					Name:      "Bart Simpson 001",
 					CPF:       "12345678901",
 					Secret:    "any secret",
+					Currency:  model.CurrencyBRL,
 					Balance:   1050,
+					Tier:      model.AccountTierStandard,
+					Status:    model.AccountStatusActive,
 					CreatedAt: time.Date(2021, 01, 04, 11, 51, 59, 0, time.Local),
 				}
 			},
@@ -596,58 +820,149 @@ func Test_accountRepository_GetByCPF(t *testing.T) {
 	}
 }
 
-func Test_accountRepository_UpdateBalance(t *testing.T) {
+func Test_accountRepository_GetByID(t *testing.T) {
 	backgroundCtx := context.Background()
 
 	type fields struct {
 		db *pgxpool.Pool
 	}
 	type args struct {
-		ctx     context.Context
-		id      model.AccountID
-		balance model.Money
+		ctx context.Context
+		id  model.AccountID
 	}
 	tests := []struct {
 		name      string
 		fields    fields
 		args      args
-		wantErr   bool
+		want      *model.Account
+		wantErr   error
 		runBefore func(args)
-		check     func(args)
 	}{
 		{
-			name: "should success",
+			name: "should return error if not found",
 			fields: fields{
 				db: testDbPool,
 			},
 			args: args{
-				ctx:     backgroundCtx,
-				id:      model.AccountID(uuid.NewString()),
-				balance: 100,
+				ctx: backgroundCtx,
+				id:  model.AccountID(uuid.NewString()),
 			},
-			wantErr: false,
+			want:    nil,
+			wantErr: repository.ErrAccountNotFound,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
+		{
+			name: "should return the account with its roles",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx: backgroundCtx,
+				id:  "6c3b8a55-6b80-4137-9dff-503caf576514",
+			},
+			want: &model.Account{
+				ID:        "6c3b8a55-6b80-4137-9dff-503caf576514",
+				Name:      "Seymour Skinner",
+				CPF:       "12345678901",
+				Secret:    "any secret",
+				Currency:  model.CurrencyBRL,
+				Balance:   1050,
+				Tier:      model.AccountTierStandard,
+				Roles:     []model.Role{model.RoleAdmin},
+				Status:    model.AccountStatusActive,
+				CreatedAt: time.Date(2021, 01, 04, 11, 51, 59, 0, time.Local),
+			},
+			wantErr: nil,
 			runBefore: func(args args) {
 				truncateDatabase(t)
 
-				_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
-					string(args.id),
-					"any name",
-					"12345678911",
-					"any secret",
-					5999)
+				_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret, balance, roles, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+					string(args.id), "Seymour Skinner", "12345678901", "any secret", 1050, []string{"admin"}, time.Date(2021, 01, 04, 11, 51, 59, 0, time.Local))
 				if err != nil {
-					t.Errorf("UpdateBalance() error on runBefore = %v", err)
+					t.Errorf("GetByID() error on runBefore = %v", err)
 				}
 			},
-			check: func(args args) {
-				var got model.Account
-				err := testDbPool.QueryRow(backgroundCtx, "SELECT id, name, cpf, secret, balance, created_at FROM accounts WHERE id = $1", string(args.id)).
-					Scan(&got.ID, &got.Name, &got.CPF, &got.Secret, &got.Balance, &got.CreatedAt)
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.runBefore != nil {
+				tt.runBefore(tt.args)
+			}
+
+			accRepo := NewAccountRepository(tt.fields.db)
+			got, err := accRepo.GetByID(tt.args.ctx, tt.args.id)
+			if err != tt.wantErr {
+				t.Errorf("GetByID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetByID() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_accountRepository_UpdateStatus(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	type fields struct {
+		db *pgxpool.Pool
+	}
+	type args struct {
+		ctx     context.Context
+		account *model.Account
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantErr   error
+		runBefore func(args)
+	}{
+		{
+			name: "should return error if not found",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx: backgroundCtx,
+				account: &model.Account{
+					ID:              model.AccountID(uuid.NewString()),
+					Status:          model.AccountStatusBlocked,
+					StatusReason:    "suspected fraud",
+					StatusChangedAt: time.Date(2021, 01, 05, 10, 0, 0, 0, time.Local),
+				},
+			},
+			wantErr: repository.ErrAccountNotFound,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
+		{
+			name: "should save the status",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx: backgroundCtx,
+				account: &model.Account{
+					ID:              "6c3b8a55-6b80-4137-9dff-503caf576514",
+					Status:          model.AccountStatusBlocked,
+					StatusReason:    "suspected fraud",
+					StatusChangedAt: time.Date(2021, 01, 05, 10, 0, 0, 0, time.Local),
+				},
+			},
+			wantErr: nil,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+
+				_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret, balance, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+					string(args.account.ID), "Homer Simpson", "12345678901", "any secret", 0, time.Date(2021, 01, 04, 11, 51, 59, 0, time.Local))
 				if err != nil {
-					t.Errorf("UpdateBalance() error = %v, wantErr %v", err, false)
-				}
-				if got.Balance != args.balance {
-					t.Errorf("UpdateBalance() got balance = %v, want %v", got, args.balance)
+					t.Errorf("UpdateStatus() error on runBefore = %v", err)
 				}
 			},
 		},
@@ -659,11 +974,173 @@ func Test_accountRepository_UpdateBalance(t *testing.T) {
 			}
 
 			accRepo := NewAccountRepository(tt.fields.db)
-			if err := accRepo.UpdateBalance(tt.args.ctx, tt.args.id, tt.args.balance); (err != nil) != tt.wantErr {
-				t.Errorf("UpdateBalance() error = %v, wantErr %v", err, tt.wantErr)
+			err := accRepo.UpdateStatus(tt.args.ctx, tt.args.account)
+			if err != tt.wantErr {
+				t.Errorf("UpdateStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
 			}
 
-			tt.check(tt.args)
+			got, err := accRepo.GetByID(tt.args.ctx, tt.args.account.ID)
+			if err != nil {
+				t.Errorf("UpdateStatus() error getting account = %v", err)
+				return
+			}
+			if got.Status != tt.args.account.Status || got.StatusReason != tt.args.account.StatusReason || !got.StatusChangedAt.Equal(tt.args.account.StatusChangedAt) {
+				t.Errorf("UpdateStatus() got = %v, want %v", got, tt.args.account)
+			}
+		})
+	}
+}
+
+func Test_accountRepository_UpdateCreditLimit(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	type fields struct {
+		db *pgxpool.Pool
+	}
+	type args struct {
+		ctx     context.Context
+		account *model.Account
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantErr   error
+		runBefore func(args)
+	}{
+		{
+			name: "should return error if not found",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx:     backgroundCtx,
+				account: &model.Account{ID: model.AccountID(uuid.NewString()), CreditLimit: 50000},
+			},
+			wantErr: repository.ErrAccountNotFound,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
+		{
+			name: "should save the credit limit",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx:     backgroundCtx,
+				account: &model.Account{ID: "6c3b8a55-6b80-4137-9dff-503caf576514", CreditLimit: 50000},
+			},
+			wantErr: nil,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+				insertTestAccount(t, args.account.ID, "12345678901", -1000)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.runBefore != nil {
+				tt.runBefore(tt.args)
+			}
+
+			accRepo := NewAccountRepository(tt.fields.db)
+			err := accRepo.UpdateCreditLimit(tt.args.ctx, tt.args.account)
+			if err != tt.wantErr {
+				t.Errorf("UpdateCreditLimit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			got, err := accRepo.GetBalance(tt.args.ctx, tt.args.account.ID)
+			if err != nil {
+				t.Errorf("UpdateCreditLimit() error getting balance = %v", err)
+				return
+			}
+			if got.Balance != -1000 || got.CreditLimit != tt.args.account.CreditLimit {
+				t.Errorf("UpdateCreditLimit() got = %v, want %v", got, tt.args.account)
+			}
+		})
+	}
+}
+
+func Test_accountRepository_UpdateTier(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	type fields struct {
+		db *pgxpool.Pool
+	}
+	type args struct {
+		ctx     context.Context
+		account *model.Account
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantErr   error
+		runBefore func(args)
+	}{
+		{
+			name: "should return error if not found",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx:     backgroundCtx,
+				account: &model.Account{ID: model.AccountID(uuid.NewString()), Tier: model.AccountTierPremium},
+			},
+			wantErr: repository.ErrAccountNotFound,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
+		{
+			name: "should save the tier",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx:     backgroundCtx,
+				account: &model.Account{ID: "6c3b8a55-6b80-4137-9dff-503caf576514", Tier: model.AccountTierPremium},
+			},
+			wantErr: nil,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+				insertTestAccount(t, args.account.ID, "12345678901", 1000)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.runBefore != nil {
+				tt.runBefore(tt.args)
+			}
+
+			accRepo := NewAccountRepository(tt.fields.db)
+			err := accRepo.UpdateTier(tt.args.ctx, tt.args.account)
+			if err != tt.wantErr {
+				t.Errorf("UpdateTier() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			got, err := accRepo.GetBalance(tt.args.ctx, tt.args.account.ID)
+			if err != nil {
+				t.Errorf("UpdateTier() error getting balance = %v", err)
+				return
+			}
+			if got.Balance != 1000 || got.Tier != tt.args.account.Tier {
+				t.Errorf("UpdateTier() got = %v, want %v", got, tt.args.account)
+			}
 		})
 	}
 }
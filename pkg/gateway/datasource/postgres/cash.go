@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type cashRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCashRepository instantiates a new deposit and withdrawal postgres repository.
+func NewCashRepository(db *pgxpool.Pool) repository.CashRepository {
+	return &cashRepository{db}
+}
+
+func (cashRepo cashRepository) CreateDeposit(ctx context.Context, deposit *model.Deposit) error {
+	var query = `
+		INSERT INTO
+			deposits (id, account_id, operator_id, amount, created_at)
+		VALUES
+			($1, $2, $3, $4, $5)
+	`
+
+	_, err := getConnFromCtx(ctx, cashRepo.db).Exec(
+		ctx,
+		query,
+		string(deposit.ID),
+		string(deposit.AccountID),
+		string(deposit.OperatorID),
+		deposit.Amount,
+		deposit.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cashRepo cashRepository) CreateWithdrawal(ctx context.Context, withdrawal *model.Withdrawal) error {
+	var query = `
+		INSERT INTO
+			withdrawals (id, account_id, amount, created_at)
+		VALUES
+			($1, $2, $3, $4)
+	`
+
+	_, err := getConnFromCtx(ctx, cashRepo.db).Exec(
+		ctx,
+		query,
+		string(withdrawal.ID),
+		string(withdrawal.AccountID),
+		withdrawal.Amount,
+		withdrawal.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cashRepo cashRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return execTransaction(ctx, cashRepo.db, txFunc)
+}
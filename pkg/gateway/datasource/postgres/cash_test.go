@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+func Test_cashRepository_CreateDeposit(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	type fields struct {
+		db *pgxpool.Pool
+	}
+	type args struct {
+		ctx     context.Context
+		deposit *model.Deposit
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantErr   bool
+		runBefore func(args)
+	}{
+		{
+			name: "should return err when account not exists",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx:     backgroundCtx,
+				deposit: model.NewDeposit(model.NewAccountID(), model.NewAccountID(), 100),
+			},
+			wantErr: true,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
+		{
+			name: "should save the deposit",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx:     backgroundCtx,
+				deposit: model.NewDeposit(model.NewAccountID(), model.NewAccountID(), 100),
+			},
+			wantErr: false,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+
+				for i, id := range []model.AccountID{args.deposit.AccountID, args.deposit.OperatorID} {
+					_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret) VALUES ($1, $2, $3, $4)",
+						string(id), "any name", fmt.Sprintf("0000000000%d", i+1), "any secret")
+					if err != nil {
+						t.Errorf("CreateDeposit() error on runBefore = %v", err)
+					}
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.runBefore != nil {
+				tt.runBefore(tt.args)
+			}
+
+			cashRepo := NewCashRepository(tt.fields.db)
+			err := cashRepo.CreateDeposit(tt.args.ctx, tt.args.deposit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateDeposit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got model.Deposit
+			err = testDbPool.QueryRow(backgroundCtx, "SELECT id, account_id, operator_id, amount, created_at FROM deposits WHERE id = $1", string(tt.args.deposit.ID)).
+				Scan(&got.ID, &got.AccountID, &got.OperatorID, &got.Amount, &got.CreatedAt)
+			if err != nil {
+				t.Errorf("CreateDeposit() error getting deposit = %v", err)
+				return
+			}
+			if got.AccountID != tt.args.deposit.AccountID || got.OperatorID != tt.args.deposit.OperatorID || got.Amount != tt.args.deposit.Amount ||
+				!got.CreatedAt.Equal(tt.args.deposit.CreatedAt.Round(time.Microsecond)) {
+				t.Errorf("CreateDeposit() got = %v, want %v", got, tt.args.deposit)
+			}
+		})
+	}
+}
+
+func Test_cashRepository_CreateWithdrawal(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	type fields struct {
+		db *pgxpool.Pool
+	}
+	type args struct {
+		ctx        context.Context
+		withdrawal *model.Withdrawal
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantErr   bool
+		runBefore func(args)
+	}{
+		{
+			name: "should return err when account not exists",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx:        backgroundCtx,
+				withdrawal: model.NewWithdrawal(model.NewAccountID(), 100),
+			},
+			wantErr: true,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
+		{
+			name: "should return err when amount is not positive",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx:        backgroundCtx,
+				withdrawal: model.NewWithdrawal(model.NewAccountID(), 0),
+			},
+			wantErr: true,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+
+				_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret) VALUES ($1, $2, $3, $4)",
+					string(args.withdrawal.AccountID), "any name", "00000000001", "any secret")
+				if err != nil {
+					t.Errorf("CreateWithdrawal() error on runBefore = %v", err)
+				}
+			},
+		},
+		{
+			name: "should save the withdrawal",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx:        backgroundCtx,
+				withdrawal: model.NewWithdrawal(model.NewAccountID(), 100),
+			},
+			wantErr: false,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+
+				_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret) VALUES ($1, $2, $3, $4)",
+					string(args.withdrawal.AccountID), "any name", "00000000001", "any secret")
+				if err != nil {
+					t.Errorf("CreateWithdrawal() error on runBefore = %v", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.runBefore != nil {
+				tt.runBefore(tt.args)
+			}
+
+			cashRepo := NewCashRepository(tt.fields.db)
+			err := cashRepo.CreateWithdrawal(tt.args.ctx, tt.args.withdrawal)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateWithdrawal() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got model.Withdrawal
+			err = testDbPool.QueryRow(backgroundCtx, "SELECT id, account_id, amount, created_at FROM withdrawals WHERE id = $1", string(tt.args.withdrawal.ID)).
+				Scan(&got.ID, &got.AccountID, &got.Amount, &got.CreatedAt)
+			if err != nil {
+				t.Errorf("CreateWithdrawal() error getting withdrawal = %v", err)
+				return
+			}
+			if got.AccountID != tt.args.withdrawal.AccountID || got.Amount != tt.args.withdrawal.Amount ||
+				!got.CreatedAt.Equal(tt.args.withdrawal.CreatedAt.Round(time.Microsecond)) {
+				t.Errorf("CreateWithdrawal() got = %v, want %v", got, tt.args.withdrawal)
+			}
+		})
+	}
+}
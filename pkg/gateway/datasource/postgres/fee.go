@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type feeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewFeeRepository instantiates a new fee postgres repository.
+func NewFeeRepository(db *pgxpool.Pool) repository.FeeRepository {
+	return &feeRepository{db}
+}
+
+// LockNextMaintenanceDue walks the accounts by the primary key from the filter cursor. SKIP LOCKED makes the executors
+// running on other replicas take the next accounts instead of waiting for the locked ones, like the ones transferring now.
+func (feeRepo feeRepository) LockNextMaintenanceDue(ctx context.Context, filter model.MaintenanceFeeFilter) (*model.Account, error) {
+	var query = `
+		SELECT
+			id, currency, balance, credit_limit, tier, status
+		FROM accounts a
+		WHERE ($1::uuid IS NULL OR id > $1)
+		AND status <> 'closed'
+		AND currency = $2
+		AND id <> $3
+		AND created_at < $4
+		AND NOT (tier = ANY($5::varchar[]))
+		AND NOT EXISTS (SELECT 1 FROM maintenance_fee_charges c WHERE c.account_id = a.id AND c.month = $6)
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	exemptTiers := make([]string, 0, len(filter.ExemptTiers))
+	for _, tier := range filter.ExemptTiers {
+		exemptTiers = append(exemptTiers, string(tier))
+	}
+
+	account := new(model.Account)
+	err := getConnFromCtx(ctx, feeRepo.db).QueryRow(
+		ctx,
+		query,
+		nullableString(string(filter.After)),
+		filter.Currency,
+		string(filter.RevenueAccountID),
+		filter.MonthEnd,
+		exemptTiers,
+		filter.Month,
+	).Scan(&account.ID, &account.Currency, &account.Balance, &account.CreditLimit, &account.Tier, &account.Status)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (feeRepo feeRepository) CreateMaintenanceCharge(ctx context.Context, charge *model.MaintenanceFeeCharge) error {
+	var query = `
+		INSERT INTO
+			maintenance_fee_charges (id, account_id, month, amount, created_at)
+		VALUES
+			($1, $2, $3, $4, $5)
+	`
+
+	_, err := getConnFromCtx(ctx, feeRepo.db).Exec(
+		ctx,
+		query,
+		string(charge.ID),
+		string(charge.AccountID),
+		charge.Month,
+		charge.Amount,
+		charge.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (feeRepo feeRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return execTransaction(ctx, feeRepo.db, txFunc)
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+func Test_feeRepository_LockNextMaintenanceDue_CreateMaintenanceCharge(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	revenueID, dueID, premiumID, closedID := model.NewAccountID(), model.NewAccountID(), model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, revenueID, "00000000001", 0)
+	insertTestAccount(t, dueID, "00000000002", 1000)
+	insertTestAccount(t, premiumID, "00000000003", 1000)
+	insertTestAccount(t, closedID, "00000000004", 0)
+	_, err := testDbPool.Exec(backgroundCtx, "UPDATE accounts SET tier = 'premium' WHERE id = $1", string(premiumID))
+	if err != nil {
+		t.Fatalf("error on setup = %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "UPDATE accounts SET status = 'closed' WHERE id = $1", string(closedID))
+	if err != nil {
+		t.Fatalf("error on setup = %v", err)
+	}
+
+	feeRepo := NewFeeRepository(testDbPool)
+	month := time.Date(time.Now().Year(), time.Now().Month(), 1, 0, 0, 0, 0, time.UTC)
+	filter := model.MaintenanceFeeFilter{
+		Month:            month,
+		MonthEnd:         time.Now().Add(time.Hour),
+		Currency:         model.CurrencyBRL,
+		ExemptTiers:      []model.AccountTier{model.AccountTierPremium},
+		RevenueAccountID: revenueID,
+	}
+
+	// lockAndCharge locks the next account due of the month and charges it, returning the account locked
+	lockAndCharge := func() *model.Account {
+		data, err := feeRepo.WithinTransaction(backgroundCtx, func(txCtx context.Context) (interface{}, error) {
+			account, err := feeRepo.LockNextMaintenanceDue(txCtx, filter)
+			if err != nil || account == nil {
+				return nil, err
+			}
+
+			return account, feeRepo.CreateMaintenanceCharge(txCtx, model.NewMaintenanceFeeCharge(account.ID, filter.Month, 990))
+		})
+		if err != nil {
+			t.Fatalf("LockNextMaintenanceDue() error = %v", err)
+		}
+
+		account, _ := data.(*model.Account)
+		return account
+	}
+
+	// the revenue, the premium and the closed accounts are not due
+	got := lockAndCharge()
+	if got == nil || got.ID != dueID || got.Balance != 1000 || got.Tier != model.AccountTierStandard {
+		t.Fatalf("LockNextMaintenanceDue() got = %v, want the due account", got)
+	}
+
+	// the account was already charged for the month
+	if got := lockAndCharge(); got != nil {
+		t.Errorf("LockNextMaintenanceDue() got = %v, want none", got)
+	}
+
+	// the cursor skips the accounts already walked
+	filter.Month = month.AddDate(0, 1, 0)
+	filter.After = dueID
+	if got := lockAndCharge(); got != nil {
+		t.Errorf("LockNextMaintenanceDue() got = %v, want none after the cursor", got)
+	}
+
+	// but not for the next month
+	filter.After = ""
+	if got := lockAndCharge(); got == nil || got.ID != dueID {
+		t.Errorf("LockNextMaintenanceDue() got = %v, want the due account", got)
+	}
+
+	// the accounts created after the month ended are not due
+	filter.Month = month.AddDate(0, 2, 0)
+	filter.MonthEnd = time.Now().Add(-time.Hour)
+	if got := lockAndCharge(); got != nil {
+		t.Errorf("LockNextMaintenanceDue() got = %v, want none", got)
+	}
+
+	// the same month can't be charged twice
+	err = feeRepo.CreateMaintenanceCharge(backgroundCtx, model.NewMaintenanceFeeCharge(dueID, month, 990))
+	if err == nil {
+		t.Errorf("CreateMaintenanceCharge() should fail when the month was already charged")
+	}
+}
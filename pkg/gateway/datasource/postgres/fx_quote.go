@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type fxQuoteRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewFXQuoteRepository instantiates a new FX quote postgres repository.
+func NewFXQuoteRepository(db *pgxpool.Pool) repository.FXQuoteRepository {
+	return &fxQuoteRepository{db}
+}
+
+func (quoteRepo fxQuoteRepository) Create(ctx context.Context, quote *model.FXQuote) error {
+	var query = `
+		INSERT INTO
+			fx_quotes (id, account_id, source_currency, target_currency, rate, source_amount, target_amount,
+				created_at, expires_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := getConnFromCtx(ctx, quoteRepo.db).Exec(
+		ctx,
+		query,
+		string(quote.ID),
+		string(quote.AccountID),
+		quote.SourceCurrency,
+		quote.TargetCurrency,
+		quote.Rate,
+		quote.SourceAmount,
+		quote.TargetAmount,
+		quote.CreatedAt,
+		quote.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetByIDForUpdate finds the transfer made by the quote through the unique index of the transfers quotes.
+func (quoteRepo fxQuoteRepository) GetByIDForUpdate(ctx context.Context, id model.FXQuoteID) (*model.FXQuote, error) {
+	var query = `
+		SELECT
+			q.id, q.account_id, q.source_currency, q.target_currency, q.rate, q.source_amount, q.target_amount,
+			t.id, q.created_at, q.expires_at
+		FROM fx_quotes q
+		LEFT JOIN transfers t ON t.fx_quote_id = q.id
+		WHERE q.id = $1
+		FOR UPDATE OF q
+	`
+
+	quote := new(model.FXQuote)
+	var transferID *string
+	err := getConnFromCtx(ctx, quoteRepo.db).QueryRow(ctx, query, string(id)).Scan(&quote.ID, &quote.AccountID,
+		&quote.SourceCurrency, &quote.TargetCurrency, &quote.Rate, &quote.SourceAmount, &quote.TargetAmount, &transferID,
+		&quote.CreatedAt, &quote.ExpiresAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, repository.ErrFXQuoteNotFound
+		}
+		return nil, err
+	}
+	if transferID != nil {
+		quote.TransferID = model.TransferID(*transferID)
+	}
+
+	return quote, nil
+}
+
+func (quoteRepo fxQuoteRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return execTransaction(ctx, quoteRepo.db, txFunc)
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+func Test_fxQuoteRepository(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	originID, destinationID := model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, originID, "00000000001", 10000)
+	insertTestAccount(t, destinationID, "00000000002", 0)
+
+	quoteRepo := NewFXQuoteRepository(testDbPool)
+	trfRepo := NewTransferRepository(testDbPool)
+
+	quote := model.NewFXQuote(originID, model.CurrencyBRL, model.CurrencyUSD, 19518289, 51234, time.Minute)
+	if err := quoteRepo.Create(backgroundCtx, quote); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if _, err := quoteRepo.GetByIDForUpdate(backgroundCtx, model.NewFXQuoteID()); err != repository.ErrFXQuoteNotFound {
+		t.Errorf("GetByIDForUpdate() error = %v, want %v", err, repository.ErrFXQuoteNotFound)
+	}
+
+	got, err := quoteRepo.GetByIDForUpdate(backgroundCtx, quote.ID)
+	if err != nil {
+		t.Fatalf("GetByIDForUpdate() error = %v", err)
+	}
+	if got.IsUsed() || got.Rate != quote.Rate || got.SourceAmount != 51234 || got.TargetAmount != 10000 ||
+		got.SourceCurrency != model.CurrencyBRL || got.TargetCurrency != model.CurrencyUSD || !got.ExpiresAt.Equal(quote.ExpiresAt) {
+		t.Errorf("GetByIDForUpdate() got = %v, want %v", got, quote)
+	}
+
+	transfer := model.NewTransfer(string(originID), string(destinationID), quote.SourceAmount)
+	transfer.ConvertBy(quote)
+	if err := trfRepo.Create(backgroundCtx, transfer); err != nil {
+		t.Fatalf("error creating transfer = %v", err)
+	}
+
+	got, err = quoteRepo.GetByIDForUpdate(backgroundCtx, quote.ID)
+	if err != nil {
+		t.Fatalf("GetByIDForUpdate() error = %v", err)
+	}
+	if got.TransferID != transfer.ID {
+		t.Errorf("GetByIDForUpdate() TransferID = %v, want %v", got.TransferID, transfer.ID)
+	}
+
+	// a quote is used by one transfer at most
+	again := model.NewTransfer(string(originID), string(destinationID), quote.SourceAmount)
+	again.ConvertBy(quote)
+	if err := trfRepo.Create(backgroundCtx, again); err != repository.ErrFXQuoteUsed {
+		t.Errorf("Create() error = %v, want %v", err, repository.ErrFXQuoteUsed)
+	}
+
+	transfers, err := trfRepo.Fetch(backgroundCtx, model.TransferFilter{AccountID: destinationID, Limit: 10})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(transfers) != 1 || !transfers[0].IsConversion() || transfers[0].FXRate != quote.Rate ||
+		transfers[0].Currency != model.CurrencyBRL || transfers[0].DestinationCurrency != model.CurrencyUSD ||
+		transfers[0].DestinationAmount != 10000 {
+		t.Errorf("Fetch() got = %v, want the conversion %v", transfers, transfer)
+	}
+}
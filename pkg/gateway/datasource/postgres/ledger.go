@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type ledgerRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewLedgerRepository instantiates a new ledger postgres repository.
+func NewLedgerRepository(db *pgxpool.Pool) repository.LedgerRepository {
+	return &ledgerRepository{db}
+}
+
+func (ldgRepo ledgerRepository) Post(ctx context.Context, posting *model.LedgerPosting) error {
+	var query = `
+		WITH entries AS (
+			INSERT INTO
+				ledger_entries (id, posting_id, account_id, type, amount, currency, kind, reference_id, created_at)
+			VALUES
+				($1, $2, $3, $4, $5, $12, $6, $7, $8),
+				($9, $2, $10, $11, $5, $12, $6, $7, $8)
+			RETURNING account_id, type, amount
+		)
+		UPDATE accounts a
+		SET balance = a.balance + CASE e.type WHEN 'credit' THEN e.amount ELSE -e.amount END
+		FROM entries e
+		WHERE a.id = e.account_id
+	`
+
+	entries := posting.Entries()
+	debit, credit := entries[0], entries[1]
+
+	cmdTag, err := getConnFromCtx(ctx, ldgRepo.db).Exec(
+		ctx,
+		query,
+		string(debit.ID),
+		string(posting.ID),
+		string(debit.AccountID),
+		string(debit.Type),
+		posting.Amount,
+		string(posting.Kind),
+		posting.ReferenceID,
+		posting.CreatedAt,
+		string(credit.ID),
+		string(credit.AccountID),
+		string(credit.Type),
+		posting.Currency,
+	)
+	if err != nil {
+		return err
+	}
+
+	wantUpdated := int64(0)
+	for _, entry := range entries {
+		if !model.IsBankLedgerAccount(entry.AccountID) {
+			wantUpdated++
+		}
+	}
+	if cmdTag.RowsAffected() != wantUpdated {
+		return repository.ErrAccountNotFound
+	}
+
+	return nil
+}
+
+func (ldgRepo ledgerRepository) GetBalance(ctx context.Context, accountID model.AccountID, currency model.Currency) (model.Money, error) {
+	var query = `
+		SELECT
+			COALESCE(SUM(CASE type WHEN 'credit' THEN amount ELSE -amount END), 0)
+		FROM ledger_entries
+		WHERE account_id = $1 AND currency = $2
+	`
+
+	var balance model.Money
+	err := getConnFromCtx(ctx, ldgRepo.db).QueryRow(ctx, query, string(accountID), currency).Scan(&balance)
+	return balance, err
+}
+
+func (ldgRepo ledgerRepository) GetBalanceAt(ctx context.Context, accountID model.AccountID, currency model.Currency, at time.Time) (model.Money, error) {
+	var query = `
+		SELECT
+			COALESCE(SUM(CASE type WHEN 'credit' THEN amount ELSE -amount END), 0)
+		FROM ledger_entries
+		WHERE account_id = $1 AND currency = $2 AND created_at < $3
+	`
+
+	var balance model.Money
+	err := getConnFromCtx(ctx, ldgRepo.db).QueryRow(ctx, query, string(accountID), currency, at).Scan(&balance)
+	return balance, err
+}
+
+func (ldgRepo ledgerRepository) FetchEntries(ctx context.Context, accountID model.AccountID) ([]model.LedgerEntry, error) {
+	var query = `
+		SELECT
+			id, posting_id, account_id, type, amount, currency, kind, reference_id, created_at
+		FROM ledger_entries
+		WHERE account_id = $1
+		ORDER BY created_at asc, id asc
+	`
+
+	rows, err := getConnFromCtx(ctx, ldgRepo.db).Query(ctx, query, string(accountID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries = make([]model.LedgerEntry, 0)
+	for rows.Next() {
+		var entry model.LedgerEntry
+		err := rows.Scan(&entry.ID, &entry.PostingID, &entry.AccountID, &entry.Type, &entry.Amount, &entry.Currency, &entry.Kind, &entry.ReferenceID, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetStatement computes the running balance with a window function over the period entries,
+// starting from the sum of the entries before the period, so it doesn't load the whole account history.
+func (ldgRepo ledgerRepository) GetStatement(ctx context.Context, accountID model.AccountID, currency model.Currency, from, to time.Time) (*model.Statement, error) {
+	var openingQuery = `
+		SELECT
+			COALESCE(SUM(CASE type WHEN 'credit' THEN amount ELSE -amount END), 0)
+		FROM ledger_entries
+		WHERE account_id = $1 AND currency = $2 AND created_at < $3
+	`
+
+	var entriesQuery = `
+		SELECT
+			e.id, e.posting_id, e.account_id, e.type, e.amount, e.currency, e.kind, e.reference_id, e.created_at,
+			c.account_id, COALESCE(a.name, ''),
+			$5::bigint + (SUM(CASE e.type WHEN 'credit' THEN e.amount ELSE -e.amount END)
+				OVER (ORDER BY e.created_at, e.id ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW))::bigint
+		FROM ledger_entries e
+		JOIN ledger_entries c ON c.posting_id = e.posting_id AND c.type <> e.type
+		LEFT JOIN accounts a ON a.id = c.account_id
+		WHERE e.account_id = $1 AND e.currency = $2 AND e.created_at >= $3 AND e.created_at < $4
+		ORDER BY e.created_at asc, e.id asc
+	`
+
+	statement := &model.Statement{
+		AccountID: accountID,
+		Currency:  currency,
+		From:      from,
+		To:        to,
+		Entries:   make([]model.StatementEntry, 0),
+	}
+
+	conn := getConnFromCtx(ctx, ldgRepo.db)
+
+	err := conn.QueryRow(ctx, openingQuery, string(accountID), currency, from).Scan(&statement.OpeningBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, entriesQuery, string(accountID), currency, from, to, statement.OpeningBalance)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statement.ClosingBalance = statement.OpeningBalance
+	for rows.Next() {
+		var entry model.StatementEntry
+		err := rows.Scan(&entry.ID, &entry.PostingID, &entry.AccountID, &entry.Type, &entry.Amount, &entry.Currency, &entry.Kind, &entry.ReferenceID, &entry.CreatedAt,
+			&entry.CounterpartID, &entry.CounterpartName, &entry.BalanceAfter)
+		if err != nil {
+			return nil, err
+		}
+
+		statement.Entries = append(statement.Entries, entry)
+		statement.ClosingBalance = entry.BalanceAfter
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+func (ldgRepo ledgerRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return execTransaction(ctx, ldgRepo.db, txFunc)
+}
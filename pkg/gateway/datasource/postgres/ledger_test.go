@@ -0,0 +1,418 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+func insertTestAccount(t *testing.T, id model.AccountID, cpf string, balance model.Money) {
+	_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+		string(id),
+		"any name",
+		cpf,
+		"any secret",
+		balance)
+	if err != nil {
+		t.Errorf("error inserting test account = %v", err)
+	}
+}
+
+// postTestMovement posts the amount to the account at the time, from the outside of the bank.
+// The negative amounts are taken from the account.
+func postTestMovement(t *testing.T, accountID model.AccountID, amount model.Money, at time.Time) {
+	posting := model.NewLedgerPosting(model.LedgerPostingCorrection, "", model.LedgerExternalAccountID, accountID, amount, model.CurrencyBRL)
+	if amount < 0 {
+		posting = model.NewLedgerPosting(model.LedgerPostingCorrection, "", accountID, model.LedgerExternalAccountID, -amount, model.CurrencyBRL)
+	}
+	posting.CreatedAt = at
+
+	if err := NewLedgerRepository(testDbPool).Post(context.Background(), posting); err != nil {
+		t.Fatalf("error posting test movement = %v", err)
+	}
+}
+
+func Test_ledgerRepository_Post(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	type fields struct {
+		db *pgxpool.Pool
+	}
+	type args struct {
+		ctx     context.Context
+		posting *model.LedgerPosting
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantErr   error
+		runBefore func(args)
+		check     func(args)
+	}{
+		{
+			name: "should return err when credit account not exists",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx:     backgroundCtx,
+				posting: model.NewLedgerPosting(model.LedgerPostingTransfer, "", model.NewAccountID(), model.NewAccountID(), 10, model.CurrencyBRL),
+			},
+			wantErr: repository.ErrAccountNotFound,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+				insertTestAccount(t, args.posting.DebitAccountID, "00000000001", 100)
+			},
+			check: func(args args) {},
+		},
+		{
+			name: "should debit and credit the accounts",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx:     backgroundCtx,
+				posting: model.NewLedgerPosting(model.LedgerPostingTransfer, "any-reference", model.NewAccountID(), model.NewAccountID(), 10, model.CurrencyBRL),
+			},
+			wantErr: nil,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+				insertTestAccount(t, args.posting.DebitAccountID, "00000000001", 100)
+				insertTestAccount(t, args.posting.CreditAccountID, "00000000002", 5)
+			},
+			check: func(args args) {
+				var debitBalance, creditBalance model.Money
+				err := testDbPool.QueryRow(backgroundCtx, "SELECT balance FROM accounts WHERE id = $1", string(args.posting.DebitAccountID)).Scan(&debitBalance)
+				if err != nil {
+					t.Errorf("Post() error = %v", err)
+				}
+				err = testDbPool.QueryRow(backgroundCtx, "SELECT balance FROM accounts WHERE id = $1", string(args.posting.CreditAccountID)).Scan(&creditBalance)
+				if err != nil {
+					t.Errorf("Post() error = %v", err)
+				}
+				if debitBalance != 90 || creditBalance != 15 {
+					t.Errorf("Post() got balances = %v and %v, want 90 and 15", debitBalance, creditBalance)
+				}
+
+				entriesCount := 0
+				err = testDbPool.QueryRow(backgroundCtx, "SELECT COUNT(id) FROM ledger_entries WHERE posting_id = $1 AND reference_id = 'any-reference'", string(args.posting.ID)).Scan(&entriesCount)
+				if err != nil {
+					t.Errorf("Post() error = %v", err)
+				}
+				if entriesCount != 2 {
+					t.Errorf("Post() got %v entries, want 2", entriesCount)
+				}
+			},
+		},
+		{
+			name: "should not require the external account to exist",
+			fields: fields{
+				db: testDbPool,
+			},
+			args: args{
+				ctx:     backgroundCtx,
+				posting: model.NewLedgerPosting(model.LedgerPostingInitialBalance, "", model.LedgerExternalAccountID, model.NewAccountID(), 10, model.CurrencyBRL),
+			},
+			wantErr: nil,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+				insertTestAccount(t, args.posting.CreditAccountID, "00000000002", 0)
+			},
+			check: func(args args) {
+				var creditBalance model.Money
+				err := testDbPool.QueryRow(backgroundCtx, "SELECT balance FROM accounts WHERE id = $1", string(args.posting.CreditAccountID)).Scan(&creditBalance)
+				if err != nil {
+					t.Errorf("Post() error = %v", err)
+				}
+				if creditBalance != 10 {
+					t.Errorf("Post() got balance = %v, want 10", creditBalance)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.runBefore != nil {
+				tt.runBefore(tt.args)
+			}
+
+			ldgRepo := NewLedgerRepository(tt.fields.db)
+			if err := ldgRepo.Post(tt.args.ctx, tt.args.posting); err != tt.wantErr {
+				t.Errorf("Post() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			tt.check(tt.args)
+		})
+	}
+}
+
+func Test_ledgerRepository_GetBalance(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	accountID := model.NewAccountID()
+	otherAccountID := model.NewAccountID()
+
+	truncateDatabase(t)
+	insertTestAccount(t, accountID, "00000000001", 0)
+	insertTestAccount(t, otherAccountID, "00000000002", 0)
+
+	ldgRepo := NewLedgerRepository(testDbPool)
+
+	got, err := ldgRepo.GetBalance(backgroundCtx, accountID, model.CurrencyBRL)
+	if err != nil || got != 0 {
+		t.Errorf("GetBalance() got = %v, err = %v, want 0", got, err)
+	}
+
+	postings := []*model.LedgerPosting{
+		model.NewLedgerPosting(model.LedgerPostingInitialBalance, "", model.LedgerExternalAccountID, accountID, 1000, model.CurrencyBRL),
+		model.NewLedgerPosting(model.LedgerPostingTransfer, "", accountID, otherAccountID, 300, model.CurrencyBRL),
+		model.NewLedgerPosting(model.LedgerPostingTransfer, "", otherAccountID, accountID, 50, model.CurrencyBRL),
+	}
+	for _, posting := range postings {
+		if err := ldgRepo.Post(backgroundCtx, posting); err != nil {
+			t.Fatalf("GetBalance() error on runBefore = %v", err)
+		}
+	}
+
+	got, err = ldgRepo.GetBalance(backgroundCtx, accountID, model.CurrencyBRL)
+	if err != nil || got != 750 {
+		t.Errorf("GetBalance() got = %v, err = %v, want 750", got, err)
+	}
+
+	got, err = ldgRepo.GetBalance(backgroundCtx, otherAccountID, model.CurrencyBRL)
+	if err != nil || got != 250 {
+		t.Errorf("GetBalance() got = %v, err = %v, want 250", got, err)
+	}
+}
+
+func Test_ledgerRepository_GetBalance_fxPositions(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	brlAccountID := model.NewAccountID()
+	usdAccountID := model.NewAccountID()
+
+	truncateDatabase(t)
+	insertTestAccount(t, brlAccountID, "00000000001", 0)
+	insertTestAccount(t, usdAccountID, "00000000002", 0)
+	_, err := testDbPool.Exec(backgroundCtx, "UPDATE accounts SET currency = 'USD' WHERE id = $1", string(usdAccountID))
+	if err != nil {
+		t.Fatalf("error on setup = %v", err)
+	}
+
+	ldgRepo := NewLedgerRepository(testDbPool)
+
+	// a cross-currency transfer goes through the fx account, one posting in each currency
+	postings := []*model.LedgerPosting{
+		model.NewLedgerPosting(model.LedgerPostingInitialBalance, "", model.LedgerExternalAccountID, brlAccountID, 1000, model.CurrencyBRL),
+		model.NewLedgerPosting(model.LedgerPostingTransfer, "", brlAccountID, model.LedgerFXAccountID, 500, model.CurrencyBRL),
+		model.NewLedgerPosting(model.LedgerPostingTransfer, "", model.LedgerFXAccountID, usdAccountID, 100, model.CurrencyUSD),
+	}
+	for _, posting := range postings {
+		if err := ldgRepo.Post(backgroundCtx, posting); err != nil {
+			t.Fatalf("GetBalance() error on runBefore = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		accountID model.AccountID
+		currency  model.Currency
+		want      model.Money
+	}{
+		{name: "fx position in BRL", accountID: model.LedgerFXAccountID, currency: model.CurrencyBRL, want: 500},
+		{name: "fx position in USD", accountID: model.LedgerFXAccountID, currency: model.CurrencyUSD, want: -100},
+		{name: "fx position in EUR", accountID: model.LedgerFXAccountID, currency: model.CurrencyEUR, want: 0},
+		{name: "BRL account", accountID: brlAccountID, currency: model.CurrencyBRL, want: 500},
+		{name: "USD account", accountID: usdAccountID, currency: model.CurrencyUSD, want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ldgRepo.GetBalance(backgroundCtx, tt.accountID, tt.currency)
+			if err != nil || got != tt.want {
+				t.Errorf("GetBalance() got = %v, err = %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+
+	statement, err := ldgRepo.GetStatement(backgroundCtx, model.LedgerFXAccountID, model.CurrencyUSD, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil || statement.Currency != model.CurrencyUSD || len(statement.Entries) != 1 || statement.ClosingBalance != -100 {
+		t.Errorf("GetStatement() got = %+v, err = %v, want the fx entry in USD only", statement, err)
+	}
+}
+
+func Test_ledgerRepository_GetBalanceAt(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	accountID := model.NewAccountID()
+	otherAccountID := model.NewAccountID()
+
+	truncateDatabase(t)
+	insertTestAccount(t, accountID, "00000000001", 0)
+	insertTestAccount(t, otherAccountID, "00000000002", 0)
+
+	ldgRepo := NewLedgerRepository(testDbPool)
+
+	midnight := time.Date(2021, 1, 5, 3, 0, 0, 0, time.UTC)
+	postings := []*model.LedgerPosting{
+		model.NewLedgerPosting(model.LedgerPostingInitialBalance, "", model.LedgerExternalAccountID, accountID, 1000, model.CurrencyBRL),
+		model.NewLedgerPosting(model.LedgerPostingTransfer, "", accountID, otherAccountID, 300, model.CurrencyBRL),
+		model.NewLedgerPosting(model.LedgerPostingTransfer, "", accountID, otherAccountID, 50, model.CurrencyBRL),
+	}
+	postings[0].CreatedAt = midnight.Add(-time.Hour)
+	postings[1].CreatedAt = midnight.Add(-time.Minute)
+	postings[2].CreatedAt = midnight
+	for _, posting := range postings {
+		if err := ldgRepo.Post(backgroundCtx, posting); err != nil {
+			t.Fatalf("GetBalanceAt() error on runBefore = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want model.Money
+	}{
+		{name: "before the entries", at: midnight.Add(-2 * time.Hour), want: 0},
+		{name: "before the transfers", at: midnight.Add(-time.Minute), want: 1000},
+		{name: "the entries at the instant don't count", at: midnight, want: 700},
+		{name: "after all the entries", at: midnight.Add(time.Second), want: 650},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ldgRepo.GetBalanceAt(backgroundCtx, accountID, model.CurrencyBRL, tt.at)
+			if err != nil || got != tt.want {
+				t.Errorf("GetBalanceAt() got = %v, err = %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ledgerRepository_FetchEntries(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	accountID := model.NewAccountID()
+
+	truncateDatabase(t)
+	insertTestAccount(t, accountID, "00000000001", 0)
+
+	ldgRepo := NewLedgerRepository(testDbPool)
+
+	posting := model.NewLedgerPosting(model.LedgerPostingInitialBalance, "any-reference", model.LedgerExternalAccountID, accountID, 1000, model.CurrencyBRL)
+	posting.CreatedAt = time.Now().Round(time.Microsecond)
+	if err := ldgRepo.Post(backgroundCtx, posting); err != nil {
+		t.Fatalf("FetchEntries() error on runBefore = %v", err)
+	}
+
+	got, err := ldgRepo.FetchEntries(backgroundCtx, accountID)
+	if err != nil {
+		t.Fatalf("FetchEntries() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("FetchEntries() got %v entries, want 1", len(got))
+	}
+
+	want := model.LedgerEntry{
+		ID:          got[0].ID,
+		PostingID:   posting.ID,
+		AccountID:   accountID,
+		Type:        model.LedgerEntryCredit,
+		Amount:      1000,
+		Currency:    model.CurrencyBRL,
+		Kind:        model.LedgerPostingInitialBalance,
+		ReferenceID: "any-reference",
+		CreatedAt:   posting.CreatedAt,
+	}
+	if !reflect.DeepEqual(got[0], want) {
+		t.Errorf("FetchEntries() got = %v, want %v", got[0], want)
+	}
+
+	_, err = testDbPool.Exec(backgroundCtx, "UPDATE ledger_entries SET amount = 1 WHERE account_id = $1", string(accountID))
+	if err == nil {
+		t.Errorf("FetchEntries() ledger entries should be immutable")
+	}
+}
+
+func Test_ledgerRepository_GetStatement(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	accountID := model.NewAccountID()
+	otherAccountID := model.NewAccountID()
+
+	truncateDatabase(t)
+	insertTestAccount(t, accountID, "00000000001", 0)
+	insertTestAccount(t, otherAccountID, "00000000002", 0)
+
+	ldgRepo := NewLedgerRepository(testDbPool)
+
+	start := time.Now().Add(-time.Hour).Round(time.Microsecond)
+	postings := []*model.LedgerPosting{
+		model.NewLedgerPosting(model.LedgerPostingInitialBalance, "", model.LedgerExternalAccountID, accountID, 1000, model.CurrencyBRL),
+		model.NewLedgerPosting(model.LedgerPostingTransfer, "transfer-1", accountID, otherAccountID, 300, model.CurrencyBRL),
+		model.NewLedgerPosting(model.LedgerPostingTransfer, "transfer-2", otherAccountID, accountID, 50, model.CurrencyBRL),
+		model.NewLedgerPosting(model.LedgerPostingWithdrawal, "withdrawal-1", accountID, model.LedgerExternalAccountID, 100, model.CurrencyBRL),
+	}
+	for i, posting := range postings {
+		posting.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		if err := ldgRepo.Post(backgroundCtx, posting); err != nil {
+			t.Fatalf("GetStatement() error on runBefore = %v", err)
+		}
+	}
+
+	// the period leaves out the initial balance and the withdrawal
+	from := start.Add(time.Minute)
+	to := start.Add(3 * time.Minute)
+	got, err := ldgRepo.GetStatement(backgroundCtx, accountID, model.CurrencyBRL, from, to)
+	if err != nil {
+		t.Fatalf("GetStatement() error = %v", err)
+	}
+
+	if got.OpeningBalance != 1000 || got.ClosingBalance != 750 {
+		t.Errorf("GetStatement() got opening = %v and closing = %v, want 1000 and 750", got.OpeningBalance, got.ClosingBalance)
+	}
+	if len(got.Entries) != 2 {
+		t.Fatalf("GetStatement() got %v entries, want 2", len(got.Entries))
+	}
+
+	wantEntries := []struct {
+		referenceID     string
+		entryType       model.LedgerEntryType
+		counterpartID   model.AccountID
+		counterpartName string
+		balanceAfter    model.Money
+	}{
+		{"transfer-1", model.LedgerEntryDebit, otherAccountID, "any name", 700},
+		{"transfer-2", model.LedgerEntryCredit, otherAccountID, "any name", 750},
+	}
+	for i, want := range wantEntries {
+		entry := got.Entries[i]
+		if entry.ReferenceID != want.referenceID || entry.Type != want.entryType || entry.CounterpartID != want.counterpartID ||
+			entry.CounterpartName != want.counterpartName || entry.BalanceAfter != want.balanceAfter {
+			t.Errorf("GetStatement() got entry %d = %+v, want %+v", i, entry, want)
+		}
+	}
+
+	// an empty period keeps the balance
+	got, err = ldgRepo.GetStatement(backgroundCtx, accountID, model.CurrencyBRL, start.Add(time.Hour), start.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("GetStatement() error = %v", err)
+	}
+	if got.OpeningBalance != 650 || got.ClosingBalance != 650 || len(got.Entries) != 0 {
+		t.Errorf("GetStatement() got opening = %v, closing = %v and %v entries, want 650, 650 and 0", got.OpeningBalance, got.ClosingBalance, len(got.Entries))
+	}
+
+	// the external counterpart has no name
+	got, err = ldgRepo.GetStatement(backgroundCtx, accountID, model.CurrencyBRL, start.Add(3*time.Minute), start.Add(4*time.Minute))
+	if err != nil {
+		t.Fatalf("GetStatement() error = %v", err)
+	}
+	if len(got.Entries) != 1 || !got.Entries[0].HasExternalCounterpart() || got.Entries[0].CounterpartName != "" || got.ClosingBalance != 650 {
+		t.Errorf("GetStatement() got = %+v, want only the withdrawal to the external account", got)
+	}
+}
@@ -0,0 +1,55 @@
+CREATE TABLE "ledger_entries"
+(
+    "id"           uuid PRIMARY KEY,
+    "posting_id"   uuid        NOT NULL,
+    "account_id"   uuid        NOT NULL,
+    "type"         varchar     NOT NULL CHECK ("type" IN ('debit', 'credit')),
+    "amount"       bigint      NOT NULL CHECK ("amount" > 0),
+    "kind"         varchar     NOT NULL,
+    "reference_id" varchar     NOT NULL DEFAULT (''),
+    "created_at"   timestamptz NOT NULL DEFAULT (now())
+);
+
+-- one debit and one credit per posting
+CREATE UNIQUE INDEX ON "ledger_entries" ("posting_id", "type");
+
+CREATE INDEX ON "ledger_entries" ("account_id", "created_at");
+
+CREATE FUNCTION ledger_entries_immutable() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'ledger entries are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_entries_immutable
+    BEFORE UPDATE OR DELETE
+    ON "ledger_entries"
+    FOR EACH ROW
+EXECUTE PROCEDURE ledger_entries_immutable();
+
+-- the current balances become the opening balance postings, so every balance can be rebuilt from the entries
+WITH postings AS MATERIALIZED (
+    SELECT md5(random()::text || "id"::text)::uuid AS posting_id, "id" AS account_id, "balance"
+    FROM "accounts"
+    WHERE "balance" <> 0
+)
+INSERT
+INTO "ledger_entries" ("id", "posting_id", "account_id", "type", "amount", "kind", "reference_id")
+SELECT md5(random()::text || posting_id::text || 'debit')::uuid,
+       posting_id,
+       CASE WHEN "balance" > 0 THEN '00000000-0000-0000-0000-000000000000'::uuid ELSE account_id END,
+       'debit',
+       abs("balance"),
+       'opening_balance',
+       account_id::text
+FROM postings
+UNION ALL
+SELECT md5(random()::text || posting_id::text || 'credit')::uuid,
+       posting_id,
+       CASE WHEN "balance" > 0 THEN account_id ELSE '00000000-0000-0000-0000-000000000000'::uuid END,
+       'credit',
+       abs("balance"),
+       'opening_balance',
+       account_id::text
+FROM postings;
@@ -0,0 +1,2 @@
+ALTER TABLE "accounts"
+    ADD COLUMN "roles" text[] NOT NULL DEFAULT ('{}');
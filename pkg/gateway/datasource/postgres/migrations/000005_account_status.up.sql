@@ -0,0 +1,4 @@
+ALTER TABLE "accounts"
+    ADD COLUMN "status" varchar NOT NULL DEFAULT 'active' CHECK ("status" IN ('active', 'blocked', 'closed')),
+    ADD COLUMN "status_reason" varchar NOT NULL DEFAULT '',
+    ADD COLUMN "status_changed_at" timestamptz NULL;
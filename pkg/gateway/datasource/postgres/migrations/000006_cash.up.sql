@@ -0,0 +1,29 @@
+CREATE TABLE "deposits"
+(
+    "id"          uuid PRIMARY KEY,
+    "account_id"  uuid        NOT NULL,
+    "operator_id" uuid        NOT NULL,
+    "amount"      bigint      NOT NULL CHECK ("amount" > 0),
+    "created_at"  timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "deposits"
+    ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "deposits"
+    ADD FOREIGN KEY ("operator_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "deposits" ("account_id", "created_at");
+
+CREATE TABLE "withdrawals"
+(
+    "id"         uuid PRIMARY KEY,
+    "account_id" uuid        NOT NULL,
+    "amount"     bigint      NOT NULL CHECK ("amount" > 0),
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "withdrawals"
+    ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "withdrawals" ("account_id", "created_at");
@@ -0,0 +1,7 @@
+CREATE INDEX ON "transfers" ("account_origin_id");
+
+CREATE INDEX ON "transfers" ("account_destination_id");
+
+DROP INDEX "transfers_account_origin_id_created_at_id_idx";
+
+DROP INDEX "transfers_account_destination_id_created_at_id_idx";
@@ -0,0 +1,12 @@
+-- the transfers are fetched by account, newest first, with keyset pagination on (created_at, id).
+-- The other columns are included, so the pages are read from the indexes only.
+CREATE INDEX "transfers_account_origin_id_created_at_id_idx"
+    ON "transfers" ("account_origin_id", "created_at", "id") INCLUDE ("account_destination_id", "amount");
+
+CREATE INDEX "transfers_account_destination_id_created_at_id_idx"
+    ON "transfers" ("account_destination_id", "created_at", "id") INCLUDE ("account_origin_id", "amount");
+
+-- prefixes of the new indexes
+DROP INDEX "transfers_account_origin_id_idx";
+
+DROP INDEX "transfers_account_destination_id_idx";
@@ -0,0 +1,7 @@
+DROP INDEX "accounts_name_id_idx";
+
+DROP INDEX "accounts_created_at_id_idx";
+
+DROP INDEX "accounts_name_trgm_idx";
+
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,9 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- name search by prefix (ILIKE 'name%') and by similarity (name % 'name')
+CREATE INDEX "accounts_name_trgm_idx" ON "accounts" USING gin ("name" gin_trgm_ops);
+
+-- keyset pagination for each AccountSort
+CREATE INDEX "accounts_created_at_id_idx" ON "accounts" ("created_at", "id");
+
+CREATE INDEX "accounts_name_id_idx" ON "accounts" ("name", "id");
@@ -0,0 +1,27 @@
+CREATE TABLE "scheduled_transfers"
+(
+    "id"                     uuid PRIMARY KEY,
+    "account_origin_id"      uuid        NOT NULL,
+    "account_destination_id" uuid        NOT NULL,
+    "amount"                 bigint      NOT NULL CHECK ("amount" > 0),
+    "scheduled_for"          timestamptz NOT NULL,
+    "status"                 varchar     NOT NULL DEFAULT 'scheduled' CHECK ("status" IN ('scheduled', 'executed', 'failed', 'cancelled')),
+    "transfer_id"            uuid        NULL,
+    "failure_reason"         varchar     NOT NULL DEFAULT '',
+    "created_at"             timestamptz NOT NULL DEFAULT (now()),
+    "processed_at"           timestamptz NULL
+);
+
+ALTER TABLE "scheduled_transfers"
+    ADD FOREIGN KEY ("account_origin_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers"
+    ADD FOREIGN KEY ("account_destination_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers"
+    ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "scheduled_transfers" ("account_origin_id", "scheduled_for");
+
+-- the executor only looks for the pending schedules
+CREATE INDEX "scheduled_transfers_due_idx" ON "scheduled_transfers" ("scheduled_for") WHERE "status" = 'scheduled';
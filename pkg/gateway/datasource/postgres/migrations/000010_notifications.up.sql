@@ -0,0 +1,14 @@
+CREATE TABLE "notifications"
+(
+    "id"           uuid PRIMARY KEY,
+    "account_id"   uuid        NOT NULL,
+    "type"         varchar     NOT NULL,
+    "reference_id" varchar     NOT NULL DEFAULT '',
+    "message"      varchar     NOT NULL,
+    "created_at"   timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "notifications"
+    ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "notifications" ("account_id", "created_at");
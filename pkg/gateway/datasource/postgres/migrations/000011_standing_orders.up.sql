@@ -0,0 +1,38 @@
+CREATE TABLE "standing_orders"
+(
+    "id"                     uuid PRIMARY KEY,
+    "account_origin_id"      uuid        NOT NULL,
+    "account_destination_id" uuid        NOT NULL,
+    "amount"                 bigint      NOT NULL CHECK ("amount" > 0),
+    "frequency"              varchar     NOT NULL CHECK ("frequency" IN ('weekly', 'monthly', 'last_business_day')),
+    "day_of_month"           integer     NOT NULL DEFAULT 0 CHECK ("day_of_month" BETWEEN 0 AND 31),
+    "starts_at"              timestamptz NOT NULL,
+    -- the UTC offset of starts_at in seconds, so the occurrences are computed on the holder's calendar
+    "utc_offset"             integer     NOT NULL DEFAULT 0,
+    "ends_at"                timestamptz NULL,
+    "max_occurrences"        integer     NOT NULL DEFAULT 0 CHECK ("max_occurrences" >= 0),
+    "occurrences"            integer     NOT NULL DEFAULT 0,
+    "next_due_at"            timestamptz NOT NULL,
+    "next_run_at"            timestamptz NOT NULL,
+    "retries"                integer     NOT NULL DEFAULT 0,
+    "status"                 varchar     NOT NULL DEFAULT 'active' CHECK ("status" IN ('active', 'paused', 'finished', 'cancelled')),
+    "last_transfer_id"       uuid        NULL,
+    "last_failure_reason"    varchar     NOT NULL DEFAULT '',
+    "last_run_at"            timestamptz NULL,
+    "created_at"             timestamptz NOT NULL DEFAULT (now()),
+    "updated_at"             timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "standing_orders"
+    ADD FOREIGN KEY ("account_origin_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "standing_orders"
+    ADD FOREIGN KEY ("account_destination_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "standing_orders"
+    ADD FOREIGN KEY ("last_transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "standing_orders" ("account_origin_id", "created_at");
+
+-- the executor only looks for the active standing orders
+CREATE INDEX "standing_orders_due_idx" ON "standing_orders" ("next_run_at") WHERE "status" = 'active';
@@ -0,0 +1,14 @@
+DROP INDEX "transfers_account_origin_id_created_at_id_idx";
+
+DROP INDEX "transfers_account_destination_id_created_at_id_idx";
+
+ALTER TABLE "transfers"
+    DROP COLUMN "kind",
+    DROP COLUMN "original_transfer_id",
+    DROP COLUMN "refunded_amount";
+
+CREATE INDEX "transfers_account_origin_id_created_at_id_idx"
+    ON "transfers" ("account_origin_id", "created_at", "id") INCLUDE ("account_destination_id", "amount");
+
+CREATE INDEX "transfers_account_destination_id_created_at_id_idx"
+    ON "transfers" ("account_destination_id", "created_at", "id") INCLUDE ("account_origin_id", "amount");
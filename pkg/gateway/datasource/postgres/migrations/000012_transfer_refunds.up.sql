@@ -0,0 +1,19 @@
+ALTER TABLE "transfers"
+    ADD COLUMN "kind" varchar NOT NULL DEFAULT 'transfer' CHECK ("kind" IN ('transfer', 'refund', 'reversal')),
+    ADD COLUMN "original_transfer_id" uuid NULL REFERENCES "transfers" ("id"),
+    ADD COLUMN "refunded_amount" bigint NOT NULL DEFAULT (0),
+    ADD CHECK ("refunded_amount" BETWEEN 0 AND "amount"),
+    ADD CHECK (("kind" = 'transfer') = ("original_transfer_id" IS NULL));
+
+-- the pages keep being read from the keyset indexes only
+DROP INDEX "transfers_account_origin_id_created_at_id_idx";
+
+DROP INDEX "transfers_account_destination_id_created_at_id_idx";
+
+CREATE INDEX "transfers_account_origin_id_created_at_id_idx"
+    ON "transfers" ("account_origin_id", "created_at", "id")
+    INCLUDE ("account_destination_id", "amount", "kind", "original_transfer_id", "refunded_amount");
+
+CREATE INDEX "transfers_account_destination_id_created_at_id_idx"
+    ON "transfers" ("account_destination_id", "created_at", "id")
+    INCLUDE ("account_origin_id", "amount", "kind", "original_transfer_id", "refunded_amount");
@@ -0,0 +1,14 @@
+-- the limits set for each account, replacing the default ones. NULL keeps the default and 0 means no limit.
+CREATE TABLE "transfer_limits"
+(
+    "account_id"            uuid PRIMARY KEY,
+    "per_transaction"       bigint      NULL CHECK ("per_transaction" >= 0),
+    "daily"                 bigint      NULL CHECK ("daily" >= 0),
+    "monthly"               bigint      NULL CHECK ("monthly" >= 0),
+    "night_per_transaction" bigint      NULL CHECK ("night_per_transaction" >= 0),
+    "night_total"           bigint      NULL CHECK ("night_total" >= 0),
+    "updated_at"            timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transfer_limits"
+    ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "overdraft_interest_charges";
+
+DROP INDEX IF EXISTS "accounts_overdrawn_idx";
+
+ALTER TABLE "accounts"
+    DROP COLUMN "credit_limit";
@@ -0,0 +1,23 @@
+-- how far below zero the balance can go, approved by the back-office. 0 means no overdraft.
+ALTER TABLE "accounts"
+    ADD COLUMN "credit_limit" bigint NOT NULL DEFAULT (0) CHECK ("credit_limit" >= 0);
+
+-- the overdrawn accounts are looked up every day to accrue their interest
+CREATE INDEX "accounts_overdrawn_idx" ON "accounts" ("id") WHERE "balance" < 0;
+
+-- the interest accrued on each overdrawn account, at most once a day
+CREATE TABLE "overdraft_interest_charges"
+(
+    "id"         uuid PRIMARY KEY,
+    "account_id" uuid        NOT NULL,
+    "day"        date        NOT NULL,
+    "balance"    bigint      NOT NULL CHECK ("balance" < 0),
+    "rate"       bigint      NOT NULL CHECK ("rate" >= 0),
+    "amount"     bigint      NOT NULL CHECK ("amount" >= 0),
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "overdraft_interest_charges"
+    ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE UNIQUE INDEX ON "overdraft_interest_charges" ("account_id", "day");
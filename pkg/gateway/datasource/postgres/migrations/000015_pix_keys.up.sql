@@ -0,0 +1,22 @@
+-- the keys addressing the accounts in transfers, like the Brazilian Pix keys
+CREATE TABLE "pix_keys"
+(
+    "id"                      uuid PRIMARY KEY,
+    "account_id"              uuid        NOT NULL,
+    "type"                    varchar     NOT NULL CHECK ("type" IN ('cpf', 'email', 'phone', 'random')),
+    "value"                   varchar(77) NOT NULL,
+    -- each key takes one of the account slots, so the unique index below limits the keys per account
+    "slot"                    smallint    NOT NULL CHECK ("slot" BETWEEN 1 AND 5),
+    "status"                  varchar     NOT NULL CHECK ("status" IN ('pending', 'active')),
+    "verification_code"       varchar     NULL,
+    "verification_expires_at" timestamptz NULL,
+    "verification_attempts"   smallint    NOT NULL DEFAULT 0,
+    "created_at"              timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "pix_keys"
+    ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE UNIQUE INDEX "pix_keys_value_key" ON "pix_keys" ("value");
+
+CREATE UNIQUE INDEX "pix_keys_account_slot_key" ON "pix_keys" ("account_id", "slot");
@@ -0,0 +1,30 @@
+-- the charges a requester account asks to be paid, aimed at a payer account or shared by link
+CREATE TABLE "payment_requests"
+(
+    "id"           uuid PRIMARY KEY,
+    "requester_id" uuid         NOT NULL,
+    "payer_id"     uuid         NULL,
+    "amount"       bigint       NOT NULL CHECK ("amount" > 0),
+    "description"  varchar(140) NOT NULL DEFAULT '',
+    "expires_at"   timestamptz  NOT NULL,
+    "status"       varchar      NOT NULL DEFAULT 'pending' CHECK ("status" IN ('pending', 'paid', 'declined', 'expired')),
+    "transfer_id"  uuid         NULL,
+    "created_at"   timestamptz  NOT NULL DEFAULT (now()),
+    "closed_at"    timestamptz  NULL
+);
+
+ALTER TABLE "payment_requests"
+    ADD FOREIGN KEY ("requester_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payment_requests"
+    ADD FOREIGN KEY ("payer_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payment_requests"
+    ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "payment_requests" ("requester_id", "created_at");
+
+CREATE INDEX ON "payment_requests" ("payer_id", "created_at");
+
+-- the sweep only looks for the pending requests
+CREATE INDEX "payment_requests_due_idx" ON "payment_requests" ("expires_at") WHERE "status" = 'pending';
@@ -0,0 +1,37 @@
+-- many transfers from the same origin account requested at once
+CREATE TABLE "transfer_batches"
+(
+    "id"                uuid PRIMARY KEY,
+    "account_origin_id" uuid        NOT NULL,
+    "mode"              varchar     NOT NULL CHECK ("mode" IN ('atomic', 'best_effort')),
+    "status"            varchar     NOT NULL DEFAULT 'processing' CHECK ("status" IN ('processing', 'completed', 'partially_completed', 'failed')),
+    "created_at"        timestamptz NOT NULL DEFAULT (now()),
+    "completed_at"      timestamptz NULL
+);
+
+ALTER TABLE "transfer_batches"
+    ADD FOREIGN KEY ("account_origin_id") REFERENCES "accounts" ("id");
+
+-- the outcome of each item; the destination has no foreign key, the items to unknown accounts are saved as rejected
+CREATE TABLE "transfer_batch_items"
+(
+    "batch_id"               uuid        NOT NULL,
+    "position"               int         NOT NULL,
+    "item_id"                varchar(64) NOT NULL,
+    "account_origin_id"      uuid        NOT NULL,
+    "account_destination_id" varchar     NOT NULL,
+    "amount"                 bigint      NOT NULL CHECK ("amount" > 0),
+    "status"                 varchar     NOT NULL CHECK ("status" IN ('executed', 'duplicate', 'rejected', 'skipped')),
+    "transfer_id"            uuid        NULL,
+    "failure_reason"         varchar     NULL,
+    PRIMARY KEY ("batch_id", "position")
+);
+
+ALTER TABLE "transfer_batch_items"
+    ADD FOREIGN KEY ("batch_id") REFERENCES "transfer_batches" ("id");
+
+ALTER TABLE "transfer_batch_items"
+    ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+-- an item ID is executed only once per origin account, whatever the batch
+CREATE UNIQUE INDEX "transfer_batch_items_executed_idx" ON "transfer_batch_items" ("account_origin_id", "item_id") WHERE "status" = 'executed';
@@ -0,0 +1,23 @@
+DROP INDEX "transfers_account_origin_id_created_at_id_idx";
+
+DROP INDEX "transfers_account_destination_id_created_at_id_idx";
+
+ALTER TABLE "transfers"
+    DROP COLUMN "currency",
+    DROP COLUMN "fx_quote_id",
+    DROP COLUMN "fx_rate",
+    DROP COLUMN "destination_currency",
+    DROP COLUMN "destination_amount";
+
+CREATE INDEX "transfers_account_origin_id_created_at_id_idx"
+    ON "transfers" ("account_origin_id", "created_at", "id")
+    INCLUDE ("account_destination_id", "amount", "kind", "original_transfer_id", "refunded_amount");
+
+CREATE INDEX "transfers_account_destination_id_created_at_id_idx"
+    ON "transfers" ("account_destination_id", "created_at", "id")
+    INCLUDE ("account_origin_id", "amount", "kind", "original_transfer_id", "refunded_amount");
+
+DROP TABLE IF EXISTS "fx_quotes";
+
+ALTER TABLE "accounts"
+    DROP COLUMN "currency";
@@ -0,0 +1,49 @@
+-- the ISO 4217 currency of the balance, the credit limit and the amounts sent by the account
+ALTER TABLE "accounts"
+    ADD COLUMN "currency" char(3) NOT NULL DEFAULT 'BRL';
+
+-- the conversion of an amount of the currency of an account to another currency, valid until expires_at
+CREATE TABLE "fx_quotes"
+(
+    "id"              uuid PRIMARY KEY,
+    "account_id"      uuid        NOT NULL,
+    "source_currency" char(3)     NOT NULL,
+    "target_currency" char(3)     NOT NULL CHECK ("target_currency" <> "source_currency"),
+    "rate"            bigint      NOT NULL CHECK ("rate" > 0),
+    "source_amount"   bigint      NOT NULL CHECK ("source_amount" > 0),
+    "target_amount"   bigint      NOT NULL CHECK ("target_amount" >= 0),
+    "created_at"      timestamptz NOT NULL DEFAULT (now()),
+    "expires_at"      timestamptz NOT NULL
+);
+
+ALTER TABLE "fx_quotes"
+    ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+-- the amount is in the currency of the origin account; the conversion columns are only filled for cross-currency transfers
+ALTER TABLE "transfers"
+    ADD COLUMN "currency" char(3) NOT NULL DEFAULT 'BRL',
+    ADD COLUMN "fx_quote_id" uuid NULL REFERENCES "fx_quotes" ("id"),
+    ADD COLUMN "fx_rate" bigint NULL CHECK ("fx_rate" > 0),
+    ADD COLUMN "destination_currency" char(3) NULL,
+    ADD COLUMN "destination_amount" bigint NULL,
+    ADD CHECK (("fx_quote_id" IS NULL) = ("fx_rate" IS NULL)
+        AND ("fx_quote_id" IS NULL) = ("destination_currency" IS NULL)
+        AND ("fx_quote_id" IS NULL) = ("destination_amount" IS NULL));
+
+-- a quote is used by one transfer at most
+CREATE UNIQUE INDEX "transfers_fx_quote_id_idx" ON "transfers" ("fx_quote_id");
+
+-- the pages keep being read from the keyset indexes only
+DROP INDEX "transfers_account_origin_id_created_at_id_idx";
+
+DROP INDEX "transfers_account_destination_id_created_at_id_idx";
+
+CREATE INDEX "transfers_account_origin_id_created_at_id_idx"
+    ON "transfers" ("account_origin_id", "created_at", "id")
+    INCLUDE ("account_destination_id", "amount", "kind", "original_transfer_id", "refunded_amount",
+             "currency", "fx_quote_id", "fx_rate", "destination_currency", "destination_amount");
+
+CREATE INDEX "transfers_account_destination_id_created_at_id_idx"
+    ON "transfers" ("account_destination_id", "created_at", "id")
+    INCLUDE ("account_origin_id", "amount", "kind", "original_transfer_id", "refunded_amount",
+             "currency", "fx_quote_id", "fx_rate", "destination_currency", "destination_amount");
@@ -0,0 +1,21 @@
+DROP TABLE IF EXISTS "maintenance_fee_charges";
+
+DROP INDEX "transfers_account_origin_id_created_at_id_idx";
+
+DROP INDEX "transfers_account_destination_id_created_at_id_idx";
+
+ALTER TABLE "transfers"
+    DROP COLUMN "fee";
+
+CREATE INDEX "transfers_account_origin_id_created_at_id_idx"
+    ON "transfers" ("account_origin_id", "created_at", "id")
+    INCLUDE ("account_destination_id", "amount", "kind", "original_transfer_id", "refunded_amount",
+             "currency", "fx_quote_id", "fx_rate", "destination_currency", "destination_amount");
+
+CREATE INDEX "transfers_account_destination_id_created_at_id_idx"
+    ON "transfers" ("account_destination_id", "created_at", "id")
+    INCLUDE ("account_origin_id", "amount", "kind", "original_transfer_id", "refunded_amount",
+             "currency", "fx_quote_id", "fx_rate", "destination_currency", "destination_amount");
+
+ALTER TABLE "accounts"
+    DROP COLUMN "tier";
@@ -0,0 +1,37 @@
+-- the tier of the account tells the fees it's exempt from
+ALTER TABLE "accounts"
+    ADD COLUMN "tier" varchar NOT NULL DEFAULT 'standard' CHECK ("tier" IN ('standard', 'premium'));
+
+-- the fee charged to the origin account on top of the amount, in its currency. 0 means no fee.
+ALTER TABLE "transfers"
+    ADD COLUMN "fee" bigint NOT NULL DEFAULT (0) CHECK ("fee" >= 0);
+
+-- the pages keep being read from the keyset indexes only
+DROP INDEX "transfers_account_origin_id_created_at_id_idx";
+
+DROP INDEX "transfers_account_destination_id_created_at_id_idx";
+
+CREATE INDEX "transfers_account_origin_id_created_at_id_idx"
+    ON "transfers" ("account_origin_id", "created_at", "id")
+    INCLUDE ("account_destination_id", "amount", "kind", "original_transfer_id", "refunded_amount",
+             "currency", "fx_quote_id", "fx_rate", "destination_currency", "destination_amount", "fee");
+
+CREATE INDEX "transfers_account_destination_id_created_at_id_idx"
+    ON "transfers" ("account_destination_id", "created_at", "id")
+    INCLUDE ("account_origin_id", "amount", "kind", "original_transfer_id", "refunded_amount",
+             "currency", "fx_quote_id", "fx_rate", "destination_currency", "destination_amount", "fee");
+
+-- the maintenance fee charged on each account, at most once a month
+CREATE TABLE "maintenance_fee_charges"
+(
+    "id"         uuid PRIMARY KEY,
+    "account_id" uuid        NOT NULL,
+    "month"      date        NOT NULL,
+    "amount"     bigint      NOT NULL CHECK ("amount" > 0),
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "maintenance_fee_charges"
+    ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE UNIQUE INDEX ON "maintenance_fee_charges" ("account_id", "month");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "savings_interest_payments";
+
+DROP TABLE IF EXISTS "savings_interest_accruals";
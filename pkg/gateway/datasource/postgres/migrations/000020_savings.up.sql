@@ -0,0 +1,32 @@
+-- the interest accrued on the closing balance of each account, at most once a business day.
+-- the amount is in the minor unit of the currency scaled by 10^8, so the days are summed without rounding.
+CREATE TABLE "savings_interest_accruals"
+(
+    "id"         uuid PRIMARY KEY,
+    "account_id" uuid        NOT NULL,
+    "day"        date        NOT NULL,
+    "balance"    bigint      NOT NULL,
+    "rate"       bigint      NOT NULL CHECK ("rate" >= 0),
+    "amount"     bigint      NOT NULL CHECK ("amount" >= 0),
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "savings_interest_accruals"
+    ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE UNIQUE INDEX ON "savings_interest_accruals" ("account_id", "day");
+
+-- the interest paid to each account, at most once a month
+CREATE TABLE "savings_interest_payments"
+(
+    "id"         uuid PRIMARY KEY,
+    "account_id" uuid        NOT NULL,
+    "month"      date        NOT NULL,
+    "amount"     bigint      NOT NULL CHECK ("amount" >= 0),
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "savings_interest_payments"
+    ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE UNIQUE INDEX ON "savings_interest_payments" ("account_id", "month");
@@ -0,0 +1,6 @@
+DROP INDEX "ledger_entries_account_id_currency_created_at_idx";
+
+CREATE INDEX ON "ledger_entries" ("account_id", "created_at");
+
+ALTER TABLE "ledger_entries"
+    DROP COLUMN "currency";
@@ -0,0 +1,32 @@
+-- the ISO 4217 currency of the entry amount, so the bank ledger accounts keep one position per currency
+ALTER TABLE "ledger_entries"
+    ADD COLUMN "currency" char(3) NOT NULL DEFAULT 'BRL';
+
+-- the entries are immutable, but the existing ones take the currency they were already in
+ALTER TABLE "ledger_entries"
+    DISABLE TRIGGER ledger_entries_immutable;
+
+UPDATE "ledger_entries" e
+SET "currency" = a."currency"
+FROM "accounts" a
+WHERE a."id" = e."account_id"
+  AND a."currency" <> 'BRL';
+
+-- the bank ledger accounts are not accounts, their entries are in the currency of the other side of the posting
+UPDATE "ledger_entries" e
+SET "currency" = c."currency"
+FROM "ledger_entries" c
+WHERE c."posting_id" = e."posting_id"
+  AND c."type" <> e."type"
+  AND e."account_id" IN ('00000000-0000-0000-0000-000000000000', '00000000-0000-0000-0000-000000000001')
+  AND c."currency" <> 'BRL';
+
+ALTER TABLE "ledger_entries"
+    ENABLE TRIGGER ledger_entries_immutable;
+
+ALTER TABLE "ledger_entries"
+    ALTER COLUMN "currency" DROP DEFAULT;
+
+DROP INDEX "ledger_entries_account_id_created_at_idx";
+
+CREATE INDEX ON "ledger_entries" ("account_id", "currency", "created_at");
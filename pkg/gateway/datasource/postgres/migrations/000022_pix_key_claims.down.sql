@@ -0,0 +1,15 @@
+-- only the oldest claim of each value is kept, unless it's already taken by an active key
+DELETE
+FROM "pix_keys" k
+WHERE k."status" = 'pending'
+  AND EXISTS(SELECT 1
+             FROM "pix_keys" o
+             WHERE o."value" = k."value"
+               AND o."id" <> k."id"
+               AND (o."status" = 'active' OR (o."created_at", o."id") < (k."created_at", k."id")));
+
+DROP INDEX "pix_keys_account_value_key";
+
+DROP INDEX "pix_keys_value_key";
+
+CREATE UNIQUE INDEX "pix_keys_value_key" ON "pix_keys" ("value");
@@ -0,0 +1,6 @@
+-- the pending keys only claim their value: several accounts can claim it and the first one verified takes it
+DROP INDEX "pix_keys_value_key";
+
+CREATE UNIQUE INDEX "pix_keys_value_key" ON "pix_keys" ("value") WHERE "status" = 'active';
+
+CREATE UNIQUE INDEX "pix_keys_account_value_key" ON "pix_keys" ("account_id", "value");
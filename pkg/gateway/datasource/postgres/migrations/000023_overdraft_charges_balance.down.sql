@@ -0,0 +1,6 @@
+DELETE
+FROM "overdraft_interest_charges"
+WHERE "balance" >= 0;
+
+ALTER TABLE "overdraft_interest_charges"
+    ADD CONSTRAINT "overdraft_interest_charges_balance_check" CHECK ("balance" < 0);
@@ -0,0 +1,3 @@
+-- the accounts are charged by their balance at the end of the day, the day is recorded even if it was not negative
+ALTER TABLE "overdraft_interest_charges"
+    DROP CONSTRAINT "overdraft_interest_charges_balance_check";
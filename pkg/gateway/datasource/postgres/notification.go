@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type notificationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewNotificationRepository instantiates a new notification postgres repository.
+func NewNotificationRepository(db *pgxpool.Pool) repository.NotificationRepository {
+	return &notificationRepository{db}
+}
+
+func (ntfRepo notificationRepository) Create(ctx context.Context, notification *model.Notification) error {
+	var query = `
+		INSERT INTO
+			notifications (id, account_id, type, reference_id, message, created_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := getConnFromCtx(ctx, ntfRepo.db).Exec(
+		ctx,
+		query,
+		string(notification.ID),
+		string(notification.AccountID),
+		notification.Type,
+		notification.ReferenceID,
+		notification.Message,
+		notification.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ntfRepo notificationRepository) Fetch(ctx context.Context, accountID model.AccountID, limit int) ([]model.Notification, error) {
+	var query = `
+		SELECT
+			id, account_id, type, reference_id, message, created_at
+		FROM notifications
+		WHERE account_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := getConnFromCtx(ctx, ntfRepo.db).Query(ctx, query, string(accountID), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications = make([]model.Notification, 0)
+	for rows.Next() {
+		var notification model.Notification
+		err := rows.Scan(&notification.ID, &notification.AccountID, &notification.Type, &notification.ReferenceID,
+			&notification.Message, &notification.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
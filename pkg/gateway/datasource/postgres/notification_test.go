@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+func Test_notificationRepository_Fetch(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	accountID, otherAccountID := model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, accountID, "00000000001", 0)
+	insertTestAccount(t, otherAccountID, "00000000002", 0)
+
+	ntfRepo := NewNotificationRepository(testDbPool)
+	var created []*model.Notification
+	for i, id := range []model.AccountID{accountID, accountID, otherAccountID, accountID} {
+		notification := model.NewNotification(id, model.NotificationStandingOrderFailed, "any-reference", "any message")
+		notification.CreatedAt = notification.CreatedAt.Add(time.Duration(i) * time.Second).Round(time.Microsecond)
+		if err := ntfRepo.Create(backgroundCtx, notification); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		created = append(created, notification)
+	}
+
+	got, err := ntfRepo.Fetch(backgroundCtx, accountID, 2)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != created[3].ID || got[1].ID != created[1].ID {
+		t.Fatalf("Fetch() got = %v, want the 2 newest notifications of the account", got)
+	}
+	if got[0].Type != model.NotificationStandingOrderFailed || got[0].ReferenceID != "any-reference" ||
+		got[0].Message != "any message" || !got[0].CreatedAt.Equal(created[3].CreatedAt) {
+		t.Errorf("Fetch() got[0] = %v, want %v", got[0], created[3])
+	}
+}
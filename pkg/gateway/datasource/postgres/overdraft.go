@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type overdraftRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewOverdraftRepository instantiates a new overdraft postgres repository.
+func NewOverdraftRepository(db *pgxpool.Pool) repository.OverdraftRepository {
+	return &overdraftRepository{db}
+}
+
+// LockNextOverdrawn rebuilds the balance at the end of the day from the ledger only for the accounts that can have
+// been overdrawn then: the ones overdrawn now, by the partial index, and the ones that moved since. SKIP LOCKED makes
+// the executors running on other replicas take the next accounts instead of waiting for the locked ones, like the ones
+// transferring now.
+func (odRepo overdraftRepository) LockNextOverdrawn(ctx context.Context, filter model.OverdraftInterestFilter) (*model.Account, error) {
+	var query = `
+		SELECT
+			id, currency, balance, credit_limit, status
+		FROM accounts a
+		WHERE (
+			balance < 0
+			OR EXISTS (SELECT 1 FROM ledger_entries m WHERE m.account_id = a.id AND m.currency = a.currency AND m.created_at >= $2)
+		)
+		AND (
+			SELECT COALESCE(SUM(CASE e.type WHEN 'credit' THEN e.amount ELSE -e.amount END), 0)
+			FROM ledger_entries e
+			WHERE e.account_id = a.id AND e.currency = a.currency AND e.created_at < $2
+		) < 0
+		AND currency = $3
+		AND id <> $4
+		AND NOT EXISTS (SELECT 1 FROM overdraft_interest_charges c WHERE c.account_id = a.id AND c.day = $1)
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	account := new(model.Account)
+	err := getConnFromCtx(ctx, odRepo.db).QueryRow(ctx, query, filter.Day, filter.DayEnd, filter.Currency, string(filter.RevenueAccountID)).
+		Scan(&account.ID, &account.Currency, &account.Balance, &account.CreditLimit, &account.Status)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (odRepo overdraftRepository) CreateInterestCharge(ctx context.Context, charge *model.OverdraftInterestCharge) error {
+	var query = `
+		INSERT INTO
+			overdraft_interest_charges (id, account_id, day, balance, rate, amount, created_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := getConnFromCtx(ctx, odRepo.db).Exec(
+		ctx,
+		query,
+		string(charge.ID),
+		string(charge.AccountID),
+		charge.Day,
+		charge.Balance,
+		charge.Rate,
+		charge.Amount,
+		charge.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (odRepo overdraftRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return execTransaction(ctx, odRepo.db, txFunc)
+}
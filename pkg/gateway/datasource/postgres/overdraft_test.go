@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+func Test_overdraftRepository_LockNextOverdrawn_CreateInterestCharge(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	day := time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)
+	dayEnd := day.AddDate(0, 0, 1)
+
+	ledgerRepo := NewLedgerRepository(testDbPool)
+
+	overdrawnID := model.NewAccountID()
+	insertTestAccount(t, overdrawnID, "00000000001", 0)
+	postTestMovement(t, overdrawnID, -30000, day.Add(10*time.Hour))
+
+	// overdrawn at midnight, repaid since
+	repaidID := model.NewAccountID()
+	insertTestAccount(t, repaidID, "00000000002", 0)
+	postTestMovement(t, repaidID, -5000, day.Add(10*time.Hour))
+	postTestMovement(t, repaidID, 5000, dayEnd.Add(8*time.Hour))
+
+	// overdrawn only after midnight
+	lateID := model.NewAccountID()
+	insertTestAccount(t, lateID, "00000000003", 0)
+	postTestMovement(t, lateID, 1000, day.Add(10*time.Hour))
+	postTestMovement(t, lateID, -3000, dayEnd.Add(8*time.Hour))
+
+	positiveID := model.NewAccountID()
+	insertTestAccount(t, positiveID, "00000000004", 0)
+	postTestMovement(t, positiveID, 1000, day.Add(10*time.Hour))
+
+	// the revenue account is never charged, even when overdrawn
+	revenueID := model.NewAccountID()
+	insertTestAccount(t, revenueID, "00000000005", 0)
+	postTestMovement(t, revenueID, -1000, day.Add(10*time.Hour))
+
+	odRepo := NewOverdraftRepository(testDbPool)
+
+	// lockAndCharge locks the next overdrawn account of the day and charges it, returning the account locked
+	lockAndCharge := func() *model.Account {
+		data, err := odRepo.WithinTransaction(backgroundCtx, func(txCtx context.Context) (interface{}, error) {
+			filter := model.OverdraftInterestFilter{Day: day, DayEnd: dayEnd, Currency: model.CurrencyBRL, RevenueAccountID: revenueID}
+			account, err := odRepo.LockNextOverdrawn(txCtx, filter)
+			if err != nil || account == nil {
+				return nil, err
+			}
+
+			balance, err := ledgerRepo.GetBalanceAt(txCtx, account.ID, account.Currency, dayEnd)
+			if err != nil {
+				return nil, err
+			}
+
+			return account, odRepo.CreateInterestCharge(txCtx, model.NewOverdraftInterestCharge(account.ID, day, balance, 800))
+		})
+		if err != nil {
+			t.Fatalf("LockNextOverdrawn() error = %v", err)
+		}
+
+		account, _ := data.(*model.Account)
+		return account
+	}
+	// lockAndChargeAll charges all the overdrawn accounts of the day, returning their current balances
+	lockAndChargeAll := func() map[model.AccountID]model.Money {
+		charged := make(map[model.AccountID]model.Money)
+		for account := lockAndCharge(); account != nil; account = lockAndCharge() {
+			if _, ok := charged[account.ID]; ok {
+				t.Fatalf("LockNextOverdrawn() got %v again", account.ID)
+			}
+			charged[account.ID] = account.Balance
+		}
+		return charged
+	}
+
+	// picked by the balance at the end of the day, not by the current one
+	got := lockAndChargeAll()
+	if len(got) != 2 || got[overdrawnID] != -30000 || got[repaidID] != 0 {
+		t.Fatalf("LockNextOverdrawn() got = %v, want the accounts overdrawn at the end of the day", got)
+	}
+
+	// the accounts were already charged for the day
+	if got := lockAndCharge(); got != nil {
+		t.Errorf("LockNextOverdrawn() got = %v, want none", got)
+	}
+
+	// but not for the next day
+	day, dayEnd = dayEnd, dayEnd.AddDate(0, 0, 1)
+	got = lockAndChargeAll()
+	if len(got) != 2 || got[overdrawnID] != -30000 || got[lateID] != -2000 {
+		t.Errorf("LockNextOverdrawn() got = %v, want the accounts overdrawn at the end of the next day", got)
+	}
+
+	// the days the account was not overdrawn are recorded too
+	err := odRepo.CreateInterestCharge(backgroundCtx, model.NewOverdraftInterestCharge(positiveID, day, 1000, 800))
+	if err != nil {
+		t.Errorf("CreateInterestCharge() not overdrawn error = %v", err)
+	}
+
+	// the same day can't be charged twice
+	err = odRepo.CreateInterestCharge(backgroundCtx, model.NewOverdraftInterestCharge(overdrawnID, day, -30000, 800))
+	if err == nil {
+		t.Errorf("CreateInterestCharge() should fail when the day was already charged")
+	}
+}
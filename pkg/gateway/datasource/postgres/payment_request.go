@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type paymentRequestRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPaymentRequestRepository instantiates a new payment request postgres repository.
+func NewPaymentRequestRepository(db *pgxpool.Pool) repository.PaymentRequestRepository {
+	return &paymentRequestRepository{db}
+}
+
+const paymentRequestColumns = `id, requester_id, payer_id, amount, description, expires_at, status,
+	transfer_id, created_at, closed_at`
+
+// paymentRequestCurrencyColumn reads the currency of the requester, which the amount is in.
+const paymentRequestCurrencyColumn = `(SELECT a.currency FROM accounts a WHERE a.id = requester_id)`
+
+func (prRepo paymentRequestRepository) Create(ctx context.Context, request *model.PaymentRequest) error {
+	var query = `
+		INSERT INTO
+			payment_requests (id, requester_id, payer_id, amount, description, expires_at, status, created_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := getConnFromCtx(ctx, prRepo.db).Exec(
+		ctx,
+		query,
+		string(request.ID),
+		string(request.RequesterID),
+		nullableString(string(request.PayerID)),
+		request.Amount,
+		request.Description,
+		request.ExpiresAt,
+		request.Status,
+		request.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (prRepo paymentRequestRepository) FetchByPayer(ctx context.Context, payerID model.AccountID, status model.PaymentRequestStatus) ([]model.PaymentRequest, error) {
+	return prRepo.fetch(ctx, "payer_id", payerID, status)
+}
+
+func (prRepo paymentRequestRepository) FetchByRequester(ctx context.Context, requesterID model.AccountID, status model.PaymentRequestStatus) ([]model.PaymentRequest, error) {
+	return prRepo.fetch(ctx, "requester_id", requesterID, status)
+}
+
+// fetch returns the requests whose account column, payer_id or requester_id, is the account.
+func (prRepo paymentRequestRepository) fetch(ctx context.Context, accountColumn string, accountID model.AccountID, status model.PaymentRequestStatus) ([]model.PaymentRequest, error) {
+	var query = `
+		SELECT
+			` + paymentRequestColumns + `, ` + paymentRequestCurrencyColumn + `
+		FROM payment_requests
+		WHERE ` + accountColumn + ` = $1
+		AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := getConnFromCtx(ctx, prRepo.db).Query(ctx, query, string(accountID), string(status))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests = make([]model.PaymentRequest, 0)
+	for rows.Next() {
+		var request model.PaymentRequest
+		err := scanPaymentRequest(rows, &request)
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, request)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+func (prRepo paymentRequestRepository) GetByID(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error) {
+	return prRepo.getByID(ctx, id, "")
+}
+
+func (prRepo paymentRequestRepository) GetByIDForUpdate(ctx context.Context, id model.PaymentRequestID) (*model.PaymentRequest, error) {
+	return prRepo.getByID(ctx, id, "FOR UPDATE")
+}
+
+func (prRepo paymentRequestRepository) getByID(ctx context.Context, id model.PaymentRequestID, lock string) (*model.PaymentRequest, error) {
+	var query = `
+		SELECT
+			` + paymentRequestColumns + `, ` + paymentRequestCurrencyColumn + `
+		FROM payment_requests
+		WHERE id = $1
+		` + lock
+
+	request := new(model.PaymentRequest)
+	err := scanPaymentRequest(getConnFromCtx(ctx, prRepo.db).QueryRow(ctx, query, string(id)), request)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, repository.ErrPaymentRequestNotFound
+		}
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (prRepo paymentRequestRepository) UpdateStatus(ctx context.Context, request *model.PaymentRequest) error {
+	var query = `
+		UPDATE payment_requests
+		SET status = $2, payer_id = $3, transfer_id = $4, closed_at = $5
+		WHERE id = $1
+	`
+
+	tag, err := getConnFromCtx(ctx, prRepo.db).Exec(ctx, query, string(request.ID), request.Status,
+		nullableString(string(request.PayerID)), nullableString(string(request.TransferID)), request.ClosedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrPaymentRequestNotFound
+	}
+
+	return nil
+}
+
+// ExpireDue walks the partial index of the pending requests. SKIP LOCKED leaves the requests being paid or declined,
+// and the ones taken by the sweeps running on other replicas, to them.
+func (prRepo paymentRequestRepository) ExpireDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	var query = `
+		UPDATE payment_requests
+		SET status = 'expired', closed_at = $1
+		WHERE id IN (
+			SELECT id
+			FROM payment_requests
+			WHERE status = 'pending'
+			AND expires_at <= $1
+			ORDER BY expires_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+
+	tag, err := getConnFromCtx(ctx, prRepo.db).Exec(ctx, query, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func (prRepo paymentRequestRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return execTransaction(ctx, prRepo.db, txFunc)
+}
+
+func scanPaymentRequest(row pgx.Row, request *model.PaymentRequest) error {
+	var payerID, transferID *string
+	var closedAt *time.Time
+	err := row.Scan(&request.ID, &request.RequesterID, &payerID, &request.Amount, &request.Description, &request.ExpiresAt,
+		&request.Status, &transferID, &request.CreatedAt, &closedAt, &request.Currency)
+	if err != nil {
+		return err
+	}
+	if payerID != nil {
+		request.PayerID = model.AccountID(*payerID)
+	}
+	if transferID != nil {
+		request.TransferID = model.TransferID(*transferID)
+	}
+	if closedAt != nil {
+		request.ClosedAt = *closedAt
+	}
+
+	return nil
+}
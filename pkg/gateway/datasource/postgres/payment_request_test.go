@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+func Test_paymentRequestRepository_Fetch(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	requesterID, payerID := model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, requesterID, "00000000001", 0)
+	insertTestAccount(t, payerID, "00000000002", 0)
+
+	now := time.Now().Round(time.Microsecond)
+	older := model.NewPaymentRequest(requesterID, payerID, 100, "Donuts", now.Add(time.Hour))
+	older.CreatedAt = now.Add(-time.Minute)
+	newer := model.NewPaymentRequest(requesterID, payerID, 200, "Duff", now.Add(time.Hour))
+	newer.CreatedAt = now
+	shared := model.NewPaymentRequest(requesterID, "", 300, "", now.Add(time.Hour))
+	shared.CreatedAt = now.Add(-2 * time.Minute)
+
+	prRepo := NewPaymentRequestRepository(testDbPool)
+	for _, request := range []*model.PaymentRequest{older, newer, shared} {
+		if err := prRepo.Create(backgroundCtx, request); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	older.Declined()
+	older.ClosedAt = older.ClosedAt.Round(time.Microsecond)
+	if err := prRepo.UpdateStatus(backgroundCtx, older); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	got, err := prRepo.FetchByPayer(backgroundCtx, payerID, "")
+	if err != nil {
+		t.Fatalf("FetchByPayer() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != newer.ID || got[1].ID != older.ID {
+		t.Fatalf("FetchByPayer() got = %v, want the requests aimed at the payer, the newest first", got)
+	}
+	if got[0].Status != model.PaymentRequestStatusPending || got[0].Description != "Duff" || got[0].Amount != 200 ||
+		!got[0].ExpiresAt.Equal(newer.ExpiresAt) || !got[0].ClosedAt.IsZero() {
+		t.Errorf("FetchByPayer() got[0] = %v, want %v", got[0], newer)
+	}
+	if got[1].Status != model.PaymentRequestStatusDeclined || !got[1].ClosedAt.Equal(older.ClosedAt) {
+		t.Errorf("FetchByPayer() got[1] = %v, want %v", got[1], older)
+	}
+
+	got, err = prRepo.FetchByPayer(backgroundCtx, payerID, model.PaymentRequestStatusPending)
+	if err != nil {
+		t.Fatalf("FetchByPayer() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != newer.ID {
+		t.Errorf("FetchByPayer() got = %v, want only the pending request", got)
+	}
+
+	got, err = prRepo.FetchByRequester(backgroundCtx, requesterID, "")
+	if err != nil {
+		t.Fatalf("FetchByRequester() error = %v", err)
+	}
+	if len(got) != 3 || got[0].ID != newer.ID || got[2].ID != shared.ID || got[2].PayerID != "" {
+		t.Errorf("FetchByRequester() got = %v, want all the requests, the newest first", got)
+	}
+}
+
+func Test_paymentRequestRepository_GetByID(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	requesterID, payerID := model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, requesterID, "00000000001", 0)
+	insertTestAccount(t, payerID, "00000000002", 0)
+
+	prRepo := NewPaymentRequestRepository(testDbPool)
+	request := model.NewPaymentRequest(requesterID, "", 100, "Donuts", time.Now().Add(time.Hour))
+	if err := prRepo.Create(backgroundCtx, request); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	got, err := prRepo.GetByID(backgroundCtx, request.ID)
+	if err != nil || got.ID != request.ID || got.RequesterID != requesterID || !got.IsShared() || got.Currency != model.DefaultCurrency {
+		t.Errorf("GetByID() got = %v, error = %v, want %v", got, err, request)
+	}
+
+	// the transfer of the payment must exist
+	transfer := model.NewTransfer(string(payerID), string(requesterID), 100)
+	if err := NewTransferRepository(testDbPool).Create(backgroundCtx, transfer); err != nil {
+		t.Fatalf("error creating transfer = %v", err)
+	}
+	request.Paid(payerID, transfer.ID)
+	if err := prRepo.UpdateStatus(backgroundCtx, request); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	got, err = prRepo.GetByIDForUpdate(backgroundCtx, request.ID)
+	if err != nil || got.Status != model.PaymentRequestStatusPaid || got.PayerID != payerID || got.TransferID != transfer.ID {
+		t.Errorf("GetByIDForUpdate() got = %v, error = %v, want %v", got, err, request)
+	}
+
+	_, err = prRepo.GetByID(backgroundCtx, model.NewPaymentRequestID())
+	if err != repository.ErrPaymentRequestNotFound {
+		t.Errorf("GetByID() error = %v, want %v", err, repository.ErrPaymentRequestNotFound)
+	}
+}
+
+func Test_paymentRequestRepository_ExpireDue(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	requesterID, payerID := model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, requesterID, "00000000001", 0)
+	insertTestAccount(t, payerID, "00000000002", 0)
+
+	now := time.Now()
+	dueFirst := model.NewPaymentRequest(requesterID, payerID, 100, "", now.Add(-2*time.Minute))
+	dueSecond := model.NewPaymentRequest(requesterID, payerID, 100, "", now.Add(-time.Minute))
+	locked := model.NewPaymentRequest(requesterID, payerID, 100, "", now.Add(-3*time.Minute))
+	notDue := model.NewPaymentRequest(requesterID, payerID, 100, "", now.Add(time.Hour))
+	declined := model.NewPaymentRequest(requesterID, payerID, 100, "", now.Add(-3*time.Minute))
+
+	prRepo := NewPaymentRequestRepository(testDbPool)
+	for _, request := range []*model.PaymentRequest{dueFirst, dueSecond, locked, notDue, declined} {
+		if err := prRepo.Create(backgroundCtx, request); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	declined.Declined()
+	if err := prRepo.UpdateStatus(backgroundCtx, declined); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	// the locked request simulates a payer approving it
+	tx, err := testDbPool.Begin(backgroundCtx)
+	if err != nil {
+		t.Fatalf("error beginning transaction = %v", err)
+	}
+	defer func() {
+		_ = tx.Rollback(backgroundCtx)
+	}()
+	if _, err = prRepo.GetByIDForUpdate(context.WithValue(backgroundCtx, transactionContextKey, tx), locked.ID); err != nil {
+		t.Fatalf("GetByIDForUpdate() error = %v", err)
+	}
+
+	got, err := prRepo.ExpireDue(backgroundCtx, now, 1)
+	if err != nil || got != 1 {
+		t.Fatalf("ExpireDue() got = %v, error = %v, want 1", got, err)
+	}
+	got, err = prRepo.ExpireDue(backgroundCtx, now, 10)
+	if err != nil || got != 1 {
+		t.Fatalf("ExpireDue() got = %v, error = %v, want 1", got, err)
+	}
+
+	for _, want := range []struct {
+		request *model.PaymentRequest
+		status  model.PaymentRequestStatus
+	}{
+		{dueFirst, model.PaymentRequestStatusExpired},
+		{dueSecond, model.PaymentRequestStatusExpired},
+		{locked, model.PaymentRequestStatusPending},
+		{notDue, model.PaymentRequestStatusPending},
+		{declined, model.PaymentRequestStatusDeclined},
+	} {
+		request, err := prRepo.GetByID(backgroundCtx, want.request.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if request.Status != want.status || (want.status == model.PaymentRequestStatusExpired && request.ClosedAt.IsZero()) {
+			t.Errorf("ExpireDue() request = %v, want status %v", request, want.status)
+		}
+	}
+}
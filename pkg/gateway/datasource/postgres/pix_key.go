@@ -0,0 +1,252 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+const (
+	pgUniqueViolation  = "23505"
+	pgNotNullViolation = "23502"
+)
+
+type pixKeyRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPixKeyRepository instantiates a new pix key postgres repository.
+func NewPixKeyRepository(db *pgxpool.Pool) repository.PixKeyRepository {
+	return &pixKeyRepository{db}
+}
+
+// pixKeyColumns are the columns read by scanPixKey.
+const pixKeyColumns = `id, account_id, type, value, status, verification_code, verification_expires_at,
+	verification_attempts, created_at`
+
+// Create takes the first free slot of the account. When there's none, the slot is null and the insert fails,
+// the unique index of the slots covers the concurrent inserts. The value is only unique among the active keys,
+// so the insert is skipped when one has it, and the partial unique index covers the concurrent inserts.
+func (keyRepo pixKeyRepository) Create(ctx context.Context, key *model.PixKey) error {
+	var deleteQuery = `
+		DELETE FROM pix_keys k
+		WHERE k.value = $1
+		AND (
+			(k.status = 'pending' AND (k.verification_expires_at < now() OR k.verification_attempts >= $2))
+			OR EXISTS(SELECT 1 FROM accounts a WHERE a.id = k.account_id AND a.status = 'closed')
+		)
+	`
+
+	conn := getConnFromCtx(ctx, keyRepo.db)
+	_, err := conn.Exec(ctx, deleteQuery, key.Value, model.PixKeyMaxVerificationAttempts)
+	if err != nil {
+		return err
+	}
+
+	var query = `
+		INSERT INTO
+			pix_keys (id, account_id, type, value, slot, status, verification_code, verification_expires_at,
+				verification_attempts, created_at)
+		SELECT
+			$1, $2, $3, $4,
+			(SELECT min(n) FROM generate_series(1, $5::smallint) n
+			WHERE n NOT IN (SELECT slot FROM pix_keys WHERE account_id = $2)),
+			$6, $7, $8, $9, $10
+		WHERE NOT EXISTS(SELECT 1 FROM pix_keys WHERE value = $4 AND status = 'active')
+	`
+
+	tag, err := conn.Exec(
+		ctx,
+		query,
+		string(key.ID),
+		string(key.AccountID),
+		key.Type,
+		key.Value,
+		model.MaxPixKeysPerAccount,
+		key.Status,
+		nullableString(key.VerificationCode),
+		nullableTime(key.VerificationExpiresAt),
+		key.VerificationAttempts,
+		key.CreatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch {
+			case isPixKeyValueTaken(pgErr):
+				return repository.ErrPixKeyAlreadyExists
+			case pgErr.Code == pgUniqueViolation, pgErr.Code == pgNotNullViolation && pgErr.ColumnName == "slot":
+				return repository.ErrPixKeyLimitReached
+			}
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrPixKeyAlreadyExists
+	}
+
+	return nil
+}
+
+func (keyRepo pixKeyRepository) Fetch(ctx context.Context, accountID model.AccountID) ([]model.PixKey, error) {
+	var query = `
+		SELECT
+			` + pixKeyColumns + `
+		FROM pix_keys
+		WHERE account_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := getConnFromCtx(ctx, keyRepo.db).Query(ctx, query, string(accountID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys = make([]model.PixKey, 0)
+	for rows.Next() {
+		var key model.PixKey
+		err := scanPixKey(rows, &key)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// isPixKeyValueTaken checks whether the error is the value already taken, by an active key or by a key of the same
+// account.
+func isPixKeyValueTaken(pgErr *pgconn.PgError) bool {
+	return pgErr.Code == pgUniqueViolation &&
+		(pgErr.ConstraintName == "pix_keys_value_key" || pgErr.ConstraintName == "pix_keys_account_value_key")
+}
+
+func (keyRepo pixKeyRepository) GetByValue(ctx context.Context, value string) (*model.PixKey, error) {
+	return keyRepo.getPixKey(ctx, "SELECT "+pixKeyColumns+" FROM pix_keys WHERE value = $1 AND status = 'active'", value)
+}
+
+func (keyRepo pixKeyRepository) GetByAccountValue(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error) {
+	return keyRepo.getPixKey(ctx, "SELECT "+pixKeyColumns+" FROM pix_keys WHERE value = $1 AND account_id = $2", value, string(accountID))
+}
+
+func (keyRepo pixKeyRepository) GetByAccountValueForUpdate(ctx context.Context, accountID model.AccountID, value string) (*model.PixKey, error) {
+	return keyRepo.getPixKey(ctx, "SELECT "+pixKeyColumns+" FROM pix_keys WHERE value = $1 AND account_id = $2 FOR UPDATE", value, string(accountID))
+}
+
+func (keyRepo pixKeyRepository) getPixKey(ctx context.Context, query string, args ...interface{}) (*model.PixKey, error) {
+	key := new(model.PixKey)
+	err := scanPixKey(getConnFromCtx(ctx, keyRepo.db).QueryRow(ctx, query, args...), key)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, repository.ErrPixKeyNotFound
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Update activating the key is rejected by the partial unique index of the values when another account was verified
+// first. Otherwise, the claims of the other accounts are dropped with it. SKIP LOCKED leaves the claims being verified
+// at the same time, instead of deadlocking with them: they wait for this one and fail on the unique index.
+func (keyRepo pixKeyRepository) Update(ctx context.Context, key *model.PixKey) error {
+	var query = `
+		UPDATE pix_keys
+		SET status = $2, verification_code = $3, verification_expires_at = $4, verification_attempts = $5
+		WHERE id = $1
+	`
+
+	conn := getConnFromCtx(ctx, keyRepo.db)
+	tag, err := conn.Exec(
+		ctx,
+		query,
+		string(key.ID),
+		key.Status,
+		nullableString(key.VerificationCode),
+		nullableTime(key.VerificationExpiresAt),
+		key.VerificationAttempts,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && isPixKeyValueTaken(pgErr) {
+			return repository.ErrPixKeyAlreadyExists
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrPixKeyNotFound
+	}
+	if !key.IsActive() {
+		return nil
+	}
+
+	var deleteQuery = `
+		DELETE FROM pix_keys
+		WHERE id IN (
+			SELECT id FROM pix_keys
+			WHERE value = $1
+			AND id <> $2
+			AND status = 'pending'
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+
+	_, err = conn.Exec(ctx, deleteQuery, key.Value, string(key.ID))
+	return err
+}
+
+func (keyRepo pixKeyRepository) Delete(ctx context.Context, id model.PixKeyID) error {
+	tag, err := getConnFromCtx(ctx, keyRepo.db).Exec(ctx, "DELETE FROM pix_keys WHERE id = $1", string(id))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrPixKeyNotFound
+	}
+
+	return nil
+}
+
+func (keyRepo pixKeyRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return execTransaction(ctx, keyRepo.db, txFunc)
+}
+
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+// scanPixKey scans the pixKeyColumns into the key.
+func scanPixKey(row pgx.Row, key *model.PixKey) error {
+	var verificationCode *string
+	var verificationExpiresAt *time.Time
+	err := row.Scan(&key.ID, &key.AccountID, &key.Type, &key.Value, &key.Status, &verificationCode,
+		&verificationExpiresAt, &key.VerificationAttempts, &key.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if verificationCode != nil {
+		key.VerificationCode = *verificationCode
+	}
+	if verificationExpiresAt != nil {
+		key.VerificationExpiresAt = *verificationExpiresAt
+	}
+
+	return nil
+}
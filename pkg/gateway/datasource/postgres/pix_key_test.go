@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+func Test_pixKeyRepository(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	accountID := model.NewAccountID()
+	insertTestAccount(t, accountID, "00000000001", 0)
+	otherAccountID := model.NewAccountID()
+	insertTestAccount(t, otherAccountID, "00000000002", 0)
+
+	keyRepo := NewPixKeyRepository(testDbPool)
+
+	emailKey := model.NewPixKey(accountID, model.PixKeyTypeEmail, "homer@springfield.com")
+	emailKey.CreatedAt = emailKey.CreatedAt.Round(time.Microsecond)
+	if _, err := emailKey.StartVerification(); err != nil {
+		t.Fatal(err)
+	}
+	emailKey.VerificationExpiresAt = emailKey.VerificationExpiresAt.Round(time.Microsecond)
+	if err := keyRepo.Create(backgroundCtx, emailKey); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	got, err := keyRepo.GetByAccountValue(backgroundCtx, accountID, "homer@springfield.com")
+	if err != nil {
+		t.Fatalf("GetByAccountValue() error = %v", err)
+	}
+	if got.ID != emailKey.ID || got.AccountID != accountID || got.Status != model.PixKeyStatusPending ||
+		got.VerificationCode != emailKey.VerificationCode || !got.VerificationExpiresAt.Equal(emailKey.VerificationExpiresAt) ||
+		!got.CreatedAt.Equal(emailKey.CreatedAt) {
+		t.Errorf("GetByAccountValue() got = %+v, want %+v", got, emailKey)
+	}
+	if _, err := keyRepo.GetByValue(backgroundCtx, "homer@springfield.com"); err != repository.ErrPixKeyNotFound {
+		t.Errorf("GetByValue() pending error = %v, want %v", err, repository.ErrPixKeyNotFound)
+	}
+	if _, err := keyRepo.GetByAccountValue(backgroundCtx, otherAccountID, "homer@springfield.com"); err != repository.ErrPixKeyNotFound {
+		t.Errorf("GetByAccountValue() other account error = %v, want %v", err, repository.ErrPixKeyNotFound)
+	}
+
+	// a pending key doesn't take the value, the other accounts can claim it too, but only once
+	otherEmailKey := model.NewPixKey(otherAccountID, model.PixKeyTypeEmail, "homer@springfield.com")
+	if _, err := otherEmailKey.StartVerification(); err != nil {
+		t.Fatal(err)
+	}
+	if err := keyRepo.Create(backgroundCtx, otherEmailKey); err != nil {
+		t.Fatalf("Create() claim of the other account error = %v", err)
+	}
+	if err := keyRepo.Create(backgroundCtx, model.NewPixKey(otherAccountID, model.PixKeyTypeEmail, "homer@springfield.com")); err != repository.ErrPixKeyAlreadyExists {
+		t.Errorf("Create() claim again error = %v, want %v", err, repository.ErrPixKeyAlreadyExists)
+	}
+
+	// the first verified takes the value and drops the claims of the other accounts
+	emailKey.Verify("wrong")
+	emailKey.Status = model.PixKeyStatusActive
+	emailKey.VerificationCode = ""
+	if err := keyRepo.Update(backgroundCtx, emailKey); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, err = keyRepo.GetByAccountValueForUpdate(backgroundCtx, accountID, "homer@springfield.com")
+	if err != nil {
+		t.Fatalf("GetByAccountValueForUpdate() error = %v", err)
+	}
+	if !got.IsActive() || got.VerificationCode != "" || got.VerificationAttempts != 1 {
+		t.Errorf("GetByAccountValueForUpdate() got = %+v, want the key updated", got)
+	}
+	got, err = keyRepo.GetByValue(backgroundCtx, "homer@springfield.com")
+	if err != nil || got.ID != emailKey.ID {
+		t.Errorf("GetByValue() got = %+v, error = %v, want the active key", got, err)
+	}
+	if _, err := keyRepo.GetByAccountValue(backgroundCtx, otherAccountID, "homer@springfield.com"); err != repository.ErrPixKeyNotFound {
+		t.Errorf("GetByAccountValue() dropped claim error = %v, want %v", err, repository.ErrPixKeyNotFound)
+	}
+
+	// the value is taken, by any account
+	if err := keyRepo.Create(backgroundCtx, model.NewPixKey(otherAccountID, model.PixKeyTypeEmail, "homer@springfield.com")); err != repository.ErrPixKeyAlreadyExists {
+		t.Errorf("Create() error = %v, want %v", err, repository.ErrPixKeyAlreadyExists)
+	}
+
+	// a claim can't be activated once another account's key was
+	thirdAccountID := model.NewAccountID()
+	insertTestAccount(t, thirdAccountID, "00000000003", 0)
+	thirdKey := model.NewPixKey(thirdAccountID, model.PixKeyTypeEmail, "marge@springfield.com")
+	if err := keyRepo.Create(backgroundCtx, thirdKey); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	otherKey := model.NewPixKey(otherAccountID, model.PixKeyTypeEmail, "marge@springfield.com")
+	if err := keyRepo.Create(backgroundCtx, otherKey); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := testDbPool.Exec(backgroundCtx, "UPDATE pix_keys SET status = 'active' WHERE id = $1", string(thirdKey.ID)); err != nil {
+		t.Fatal(err)
+	}
+	otherKey.Status = model.PixKeyStatusActive
+	if err := keyRepo.Update(backgroundCtx, otherKey); err != repository.ErrPixKeyAlreadyExists {
+		t.Errorf("Update() activated by another account error = %v, want %v", err, repository.ErrPixKeyAlreadyExists)
+	}
+
+	// the account has room for 4 more keys
+	for i := 1; i < model.MaxPixKeysPerAccount; i++ {
+		key := model.NewPixKey(accountID, model.PixKeyTypePhone, fmt.Sprintf("+55119876543%02d", i))
+		if err := keyRepo.Create(backgroundCtx, key); err != nil {
+			t.Fatalf("Create() key %d error = %v", i, err)
+		}
+	}
+	if err := keyRepo.Create(backgroundCtx, model.NewPixKey(accountID, model.PixKeyTypeRandom, model.NewRandomPixKeyValue())); err != repository.ErrPixKeyLimitReached {
+		t.Errorf("Create() error = %v, want %v", err, repository.ErrPixKeyLimitReached)
+	}
+
+	keys, err := keyRepo.Fetch(backgroundCtx, accountID)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(keys) != model.MaxPixKeysPerAccount || keys[0].ID != emailKey.ID {
+		t.Errorf("Fetch() got = %+v, want %d keys, the oldest first", keys, model.MaxPixKeysPerAccount)
+	}
+
+	// deleting a key frees its slot
+	if err := keyRepo.Delete(backgroundCtx, keys[2].ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := keyRepo.Delete(backgroundCtx, keys[2].ID); err != repository.ErrPixKeyNotFound {
+		t.Errorf("Delete() again error = %v, want %v", err, repository.ErrPixKeyNotFound)
+	}
+	if _, err := keyRepo.GetByValue(backgroundCtx, keys[2].Value); err != repository.ErrPixKeyNotFound {
+		t.Errorf("GetByValue() deleted error = %v, want %v", err, repository.ErrPixKeyNotFound)
+	}
+	if err := keyRepo.Create(backgroundCtx, model.NewPixKey(accountID, model.PixKeyTypeRandom, model.NewRandomPixKeyValue())); err != nil {
+		t.Errorf("Create() after delete error = %v", err)
+	}
+
+	if err := keyRepo.Update(backgroundCtx, &model.PixKey{ID: model.NewPixKeyID(), Status: model.PixKeyStatusActive}); err != repository.ErrPixKeyNotFound {
+		t.Errorf("Update() error = %v, want %v", err, repository.ErrPixKeyNotFound)
+	}
+}
+
+func Test_pixKeyRepository_Create_replaces(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	accountID := model.NewAccountID()
+	insertTestAccount(t, accountID, "00000000001", 0)
+	otherAccountID := model.NewAccountID()
+	insertTestAccount(t, otherAccountID, "00000000002", 0)
+
+	keyRepo := NewPixKeyRepository(testDbPool)
+
+	// an expired pending key doesn't take the value
+	pendingKey := model.NewPixKey(accountID, model.PixKeyTypeEmail, "homer@springfield.com")
+	if _, err := pendingKey.StartVerification(); err != nil {
+		t.Fatal(err)
+	}
+	pendingKey.VerificationExpiresAt = time.Now().Add(-time.Second)
+	if err := keyRepo.Create(backgroundCtx, pendingKey); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	otherKey := model.NewPixKey(otherAccountID, model.PixKeyTypeEmail, "homer@springfield.com")
+	if _, err := otherKey.StartVerification(); err != nil {
+		t.Fatal(err)
+	}
+	if err := keyRepo.Create(backgroundCtx, otherKey); err != nil {
+		t.Fatalf("Create() over expired key error = %v", err)
+	}
+	if _, err := keyRepo.GetByAccountValue(backgroundCtx, accountID, "homer@springfield.com"); err != repository.ErrPixKeyNotFound {
+		t.Errorf("GetByAccountValue() expired key error = %v, want %v", err, repository.ErrPixKeyNotFound)
+	}
+	got, err := keyRepo.GetByAccountValue(backgroundCtx, otherAccountID, "homer@springfield.com")
+	if err != nil || got.ID != otherKey.ID {
+		t.Errorf("GetByAccountValue() got = %+v, error = %v, want the key of the other account", got, err)
+	}
+
+	// neither does an active key of a closed account
+	phoneKey := model.NewPixKey(otherAccountID, model.PixKeyTypePhone, "+5511987654321")
+	phoneKey.Status = model.PixKeyStatusActive
+	if err := keyRepo.Create(backgroundCtx, phoneKey); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := testDbPool.Exec(backgroundCtx, "UPDATE accounts SET status = 'closed' WHERE id = $1", string(otherAccountID)); err != nil {
+		t.Fatal(err)
+	}
+	if err := keyRepo.Create(backgroundCtx, model.NewPixKey(accountID, model.PixKeyTypePhone, "+5511987654321")); err != nil {
+		t.Fatalf("Create() over closed account key error = %v", err)
+	}
+	got, err = keyRepo.GetByAccountValue(backgroundCtx, accountID, "+5511987654321")
+	if err != nil || got.AccountID != accountID {
+		t.Errorf("GetByValue() got = %+v, error = %v, want the key of the account", got, err)
+	}
+}
@@ -101,10 +101,75 @@ func TestMain(m *testing.M) {
 func truncateDatabase(t *testing.T) {
 	backgroundCtx := context.Background()
 
-	_, err := testDbPool.Exec(backgroundCtx, "DELETE FROM transfers")
+	// ledger entries are immutable, so they can not be deleted, only truncated
+	_, err := testDbPool.Exec(backgroundCtx, "TRUNCATE ledger_entries")
+	if err != nil {
+		t.Errorf("Error truncating ledger_entries table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM notifications")
+	if err != nil {
+		t.Errorf("Error truncating notifications table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM standing_orders")
+	if err != nil {
+		t.Errorf("Error truncating standing_orders table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM scheduled_transfers")
+	if err != nil {
+		t.Errorf("Error truncating scheduled_transfers table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM payment_requests")
+	if err != nil {
+		t.Errorf("Error truncating payment_requests table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM transfer_batch_items")
+	if err != nil {
+		t.Errorf("Error truncating transfer_batch_items table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM transfer_batches")
+	if err != nil {
+		t.Errorf("Error truncating transfer_batches table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM transfers")
 	if err != nil {
 		t.Errorf("Error truncating transfers table: %v", err)
 	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM fx_quotes")
+	if err != nil {
+		t.Errorf("Error truncating fx_quotes table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM deposits")
+	if err != nil {
+		t.Errorf("Error truncating deposits table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM withdrawals")
+	if err != nil {
+		t.Errorf("Error truncating withdrawals table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM transfer_limits")
+	if err != nil {
+		t.Errorf("Error truncating transfer_limits table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM overdraft_interest_charges")
+	if err != nil {
+		t.Errorf("Error truncating overdraft_interest_charges table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM maintenance_fee_charges")
+	if err != nil {
+		t.Errorf("Error truncating maintenance_fee_charges table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM savings_interest_payments")
+	if err != nil {
+		t.Errorf("Error truncating savings_interest_payments table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM savings_interest_accruals")
+	if err != nil {
+		t.Errorf("Error truncating savings_interest_accruals table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM pix_keys")
+	if err != nil {
+		t.Errorf("Error truncating pix_keys table: %v", err)
+	}
 	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM accounts")
 	if err != nil {
 		t.Errorf("Error truncating accounts table: %v", err)
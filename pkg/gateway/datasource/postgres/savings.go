@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type savingsRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewSavingsRepository instantiates a new savings postgres repository.
+func NewSavingsRepository(db *pgxpool.Pool) repository.SavingsRepository {
+	return &savingsRepository{db}
+}
+
+// LockNextAccrualDue walks the accounts by the primary key, rebuilding the balance at the end of the day from the
+// ledger only for the ones that can have been positive then: the ones positive now and the ones that moved since.
+// SKIP LOCKED makes the executors running on other replicas take the next accounts instead of waiting for the locked
+// ones, like the ones transferring now.
+func (savRepo savingsRepository) LockNextAccrualDue(ctx context.Context, filter model.SavingsAccrualFilter) (*model.Account, error) {
+	var query = `
+		SELECT
+			id, currency, balance, credit_limit, tier, status
+		FROM accounts a
+		WHERE (
+			balance > 0
+			OR EXISTS (SELECT 1 FROM ledger_entries m WHERE m.account_id = a.id AND m.currency = a.currency AND m.created_at >= $3)
+		)
+		AND (
+			SELECT COALESCE(SUM(CASE e.type WHEN 'credit' THEN e.amount ELSE -e.amount END), 0)
+			FROM ledger_entries e
+			WHERE e.account_id = a.id AND e.currency = a.currency AND e.created_at < $3
+		) > 0
+		AND status <> 'closed'
+		AND currency = $1
+		AND id <> $2
+		AND created_at < $3
+		AND NOT EXISTS (SELECT 1 FROM savings_interest_accruals s WHERE s.account_id = a.id AND s.day = $4)
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	return savRepo.lockNext(ctx, query, filter.Currency, string(filter.TreasuryAccountID), filter.DayEnd, filter.Day)
+}
+
+func (savRepo savingsRepository) CreateAccrual(ctx context.Context, accrual *model.SavingsInterestAccrual) error {
+	var query = `
+		INSERT INTO
+			savings_interest_accruals (id, account_id, day, balance, rate, amount, created_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := getConnFromCtx(ctx, savRepo.db).Exec(
+		ctx,
+		query,
+		string(accrual.ID),
+		string(accrual.AccountID),
+		accrual.Day,
+		accrual.Balance,
+		accrual.Rate,
+		accrual.Amount,
+		accrual.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LockNextPaymentDue walks the accounts by the primary key, checking their accruals of the month by the unique index.
+func (savRepo savingsRepository) LockNextPaymentDue(ctx context.Context, filter model.SavingsPaymentFilter) (*model.Account, error) {
+	var query = `
+		SELECT
+			id, currency, balance, credit_limit, tier, status
+		FROM accounts a
+		WHERE status <> 'closed'
+		AND currency = $1
+		AND id <> $2
+		AND EXISTS (SELECT 1 FROM savings_interest_accruals s WHERE s.account_id = a.id AND s.day >= $3 AND s.day < $4)
+		AND NOT EXISTS (SELECT 1 FROM savings_interest_payments p WHERE p.account_id = a.id AND p.month = $3)
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	return savRepo.lockNext(ctx, query, filter.Currency, string(filter.TreasuryAccountID), filter.Month, filter.MonthEnd)
+}
+
+// lockNext scans the account returned by the locking query, or nil if there's none.
+func (savRepo savingsRepository) lockNext(ctx context.Context, query string, args ...interface{}) (*model.Account, error) {
+	account := new(model.Account)
+	err := getConnFromCtx(ctx, savRepo.db).QueryRow(ctx, query, args...).
+		Scan(&account.ID, &account.Currency, &account.Balance, &account.CreditLimit, &account.Tier, &account.Status)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// GetTotals sums the accruals and the payments in a single round trip.
+func (savRepo savingsRepository) GetTotals(ctx context.Context, accountID model.AccountID, end time.Time) (*model.SavingsInterestTotals, error) {
+	var query = `
+		SELECT
+			(SELECT COALESCE(SUM(amount), 0) FROM savings_interest_accruals WHERE account_id = $1 AND day < $2)::bigint,
+			(SELECT COALESCE(SUM(amount), 0) FROM savings_interest_payments WHERE account_id = $1)::bigint
+	`
+
+	totals := new(model.SavingsInterestTotals)
+	err := getConnFromCtx(ctx, savRepo.db).QueryRow(ctx, query, string(accountID), end).Scan(&totals.Accrued, &totals.Paid)
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+func (savRepo savingsRepository) CreatePayment(ctx context.Context, payment *model.SavingsInterestPayment) error {
+	var query = `
+		INSERT INTO
+			savings_interest_payments (id, account_id, month, amount, created_at)
+		VALUES
+			($1, $2, $3, $4, $5)
+	`
+
+	_, err := getConnFromCtx(ctx, savRepo.db).Exec(
+		ctx,
+		query,
+		string(payment.ID),
+		string(payment.AccountID),
+		payment.Month,
+		payment.Amount,
+		payment.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (savRepo savingsRepository) FetchAccruals(ctx context.Context, accountID model.AccountID, from, to time.Time) ([]model.SavingsInterestAccrual, error) {
+	var query = `
+		SELECT
+			id, account_id, day, balance, rate, amount, created_at
+		FROM savings_interest_accruals
+		WHERE account_id = $1 AND day >= $2 AND day < $3
+		ORDER BY day asc
+	`
+
+	rows, err := getConnFromCtx(ctx, savRepo.db).Query(ctx, query, string(accountID), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accruals = make([]model.SavingsInterestAccrual, 0)
+	for rows.Next() {
+		var accrual model.SavingsInterestAccrual
+		err := rows.Scan(&accrual.ID, &accrual.AccountID, &accrual.Day, &accrual.Balance, &accrual.Rate, &accrual.Amount, &accrual.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		accruals = append(accruals, accrual)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accruals, nil
+}
+
+func (savRepo savingsRepository) FetchPayments(ctx context.Context, accountID model.AccountID, from, to time.Time) ([]model.SavingsInterestPayment, error) {
+	var query = `
+		SELECT
+			id, account_id, month, amount, created_at
+		FROM savings_interest_payments
+		WHERE account_id = $1 AND month >= $2 AND month < $3
+		ORDER BY month asc
+	`
+
+	rows, err := getConnFromCtx(ctx, savRepo.db).Query(ctx, query, string(accountID), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments = make([]model.SavingsInterestPayment, 0)
+	for rows.Next() {
+		var payment model.SavingsInterestPayment
+		err := rows.Scan(&payment.ID, &payment.AccountID, &payment.Month, &payment.Amount, &payment.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		payments = append(payments, payment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+func (savRepo savingsRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return execTransaction(ctx, savRepo.db, txFunc)
+}
@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+func Test_savingsRepository_LockNextAccrualDue_CreateAccrual(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	now := time.Now()
+	dayEnd := now.Add(time.Hour)
+
+	treasuryID, dueID, emptyID, closedID := model.NewAccountID(), model.NewAccountID(), model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, treasuryID, "00000000001", 0)
+	postTestMovement(t, treasuryID, 1000, now)
+	insertTestAccount(t, dueID, "00000000002", 0)
+	postTestMovement(t, dueID, 1000, now)
+	insertTestAccount(t, emptyID, "00000000003", 0)
+	insertTestAccount(t, closedID, "00000000004", 0)
+	postTestMovement(t, closedID, 1000, now)
+	_, err := testDbPool.Exec(backgroundCtx, "UPDATE accounts SET status = 'closed' WHERE id = $1", string(closedID))
+	if err != nil {
+		t.Fatalf("error on setup = %v", err)
+	}
+
+	// positive when the day ended, spent since
+	spentID := model.NewAccountID()
+	insertTestAccount(t, spentID, "00000000005", 0)
+	postTestMovement(t, spentID, 1000, now)
+	postTestMovement(t, spentID, -1000, dayEnd.Add(time.Hour))
+
+	// positive only after the day ended
+	lateID := model.NewAccountID()
+	insertTestAccount(t, lateID, "00000000006", 0)
+	postTestMovement(t, lateID, 1000, dayEnd.Add(time.Hour))
+
+	savRepo := NewSavingsRepository(testDbPool)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	filter := model.SavingsAccrualFilter{
+		Day:               day,
+		DayEnd:            dayEnd,
+		Currency:          model.CurrencyBRL,
+		TreasuryAccountID: treasuryID,
+	}
+
+	// lockAndAccrue locks the next account due of the day and accrues its interest, returning the account locked
+	lockAndAccrue := func() *model.Account {
+		data, err := savRepo.WithinTransaction(backgroundCtx, func(txCtx context.Context) (interface{}, error) {
+			account, err := savRepo.LockNextAccrualDue(txCtx, filter)
+			if err != nil || account == nil {
+				return nil, err
+			}
+
+			return account, savRepo.CreateAccrual(txCtx, model.NewSavingsInterestAccrual(account.ID, filter.Day, account.Balance, 50788))
+		})
+		if err != nil {
+			t.Fatalf("LockNextAccrualDue() error = %v", err)
+		}
+
+		account, _ := data.(*model.Account)
+		return account
+	}
+
+	// lockAndAccrueAll accrues the interest of the day on all the accounts due, returning their current balances
+	lockAndAccrueAll := func() map[model.AccountID]model.Money {
+		accrued := make(map[model.AccountID]model.Money)
+		for account := lockAndAccrue(); account != nil; account = lockAndAccrue() {
+			if _, ok := accrued[account.ID]; ok {
+				t.Fatalf("LockNextAccrualDue() got %v again", account.ID)
+			}
+			accrued[account.ID] = account.Balance
+		}
+		return accrued
+	}
+
+	// the treasury, the empty, the closed and the accounts positive only after the day ended are not due
+	got := lockAndAccrueAll()
+	if len(got) != 2 || got[dueID] != 1000 || got[spentID] != 0 {
+		t.Fatalf("LockNextAccrualDue() got = %v, want the accounts positive at the end of the day", got)
+	}
+
+	// the account already accrued on the day
+	if got := lockAndAccrue(); got != nil {
+		t.Errorf("LockNextAccrualDue() got = %v, want none", got)
+	}
+
+	// but not on the next day
+	filter.Day = day.AddDate(0, 0, 1)
+	if got := lockAndAccrueAll(); len(got) != 2 || got[dueID] != 1000 || got[spentID] != 0 {
+		t.Errorf("LockNextAccrualDue() got = %v, want the accounts positive at the end of the day", got)
+	}
+
+	// the accounts created after the day ended are not due
+	filter.Day = day.AddDate(0, 0, 2)
+	filter.DayEnd = time.Now().Add(-time.Hour)
+	if got := lockAndAccrue(); got != nil {
+		t.Errorf("LockNextAccrualDue() got = %v, want none", got)
+	}
+
+	// the same day can't accrue twice
+	err = savRepo.CreateAccrual(backgroundCtx, model.NewSavingsInterestAccrual(dueID, day, 1000, 50788))
+	if err == nil {
+		t.Errorf("CreateAccrual() should fail when the day already accrued")
+	}
+}
+
+func Test_savingsRepository_LockNextPaymentDue_CreatePayment(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	treasuryID, dueID, notAccruedID := model.NewAccountID(), model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, treasuryID, "00000000001", 1000)
+	insertTestAccount(t, dueID, "00000000002", 1000000)
+	insertTestAccount(t, notAccruedID, "00000000003", 1000000)
+
+	savRepo := NewSavingsRepository(testDbPool)
+	month := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, accrual := range []*model.SavingsInterestAccrual{
+		model.NewSavingsInterestAccrual(dueID, time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), 1000000, 50788),
+		model.NewSavingsInterestAccrual(dueID, time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC), 1000000, 50788),
+		model.NewSavingsInterestAccrual(dueID, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), 1000000, 50788),
+	} {
+		if err := savRepo.CreateAccrual(backgroundCtx, accrual); err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+	if err := savRepo.CreatePayment(backgroundCtx, model.NewSavingsInterestPayment(dueID, month.AddDate(0, -1, 0), 5)); err != nil {
+		t.Fatalf("error on setup = %v", err)
+	}
+
+	filter := model.SavingsPaymentFilter{
+		Month:             month,
+		MonthEnd:          month.AddDate(0, 1, 0),
+		Currency:          model.CurrencyBRL,
+		TreasuryAccountID: treasuryID,
+	}
+
+	// lockAndPay locks the next account due of the month and pays its interest, returning the account locked
+	lockAndPay := func() *model.Account {
+		data, err := savRepo.WithinTransaction(backgroundCtx, func(txCtx context.Context) (interface{}, error) {
+			account, err := savRepo.LockNextPaymentDue(txCtx, filter)
+			if err != nil || account == nil {
+				return nil, err
+			}
+
+			totals, err := savRepo.GetTotals(txCtx, account.ID, filter.MonthEnd)
+			if err != nil {
+				return nil, err
+			}
+			// the accrual of the next month doesn't count
+			if totals.Accrued != 101576000000 || totals.Paid != 5 || totals.Unpaid() != 1010 {
+				t.Errorf("GetTotals() got = %+v, want the accruals until the month ended and the payments", totals)
+			}
+
+			return account, savRepo.CreatePayment(txCtx, model.NewSavingsInterestPayment(account.ID, filter.Month, totals.Unpaid()))
+		})
+		if err != nil {
+			t.Fatalf("LockNextPaymentDue() error = %v", err)
+		}
+
+		account, _ := data.(*model.Account)
+		return account
+	}
+
+	// the treasury and the account that didn't accrue in the month are not due
+	got := lockAndPay()
+	if got == nil || got.ID != dueID {
+		t.Fatalf("LockNextPaymentDue() got = %v, want the due account", got)
+	}
+
+	// the account was already paid for the month
+	if got := lockAndPay(); got != nil {
+		t.Errorf("LockNextPaymentDue() got = %v, want none", got)
+	}
+
+	// the same month can't be paid twice
+	err := savRepo.CreatePayment(backgroundCtx, model.NewSavingsInterestPayment(dueID, month, 5))
+	if err == nil {
+		t.Errorf("CreatePayment() should fail when the month was already paid")
+	}
+
+	accruals, err := savRepo.FetchAccruals(backgroundCtx, dueID, month, filter.MonthEnd)
+	if err != nil || len(accruals) != 1 || !accruals[0].Day.Equal(time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)) || accruals[0].Amount != 50788000000 {
+		t.Errorf("FetchAccruals() got = %v, err = %v, want the accrual of the month", accruals, err)
+	}
+
+	payments, err := savRepo.FetchPayments(backgroundCtx, dueID, month.AddDate(0, -1, 0), filter.MonthEnd)
+	if err != nil || len(payments) != 2 || !payments[1].Month.Equal(month) || payments[1].Amount != 5 {
+		t.Errorf("FetchPayments() got = %v, err = %v, want the payments of both months", payments, err)
+	}
+}
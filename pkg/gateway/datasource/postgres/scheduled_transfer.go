@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type scheduledTransferRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewScheduledTransferRepository instantiates a new scheduled transfer postgres repository.
+func NewScheduledTransferRepository(db *pgxpool.Pool) repository.ScheduledTransferRepository {
+	return &scheduledTransferRepository{db}
+}
+
+const scheduledTransferColumns = `id, account_origin_id, account_destination_id, amount, scheduled_for, status,
+	transfer_id, failure_reason, created_at, processed_at`
+
+// scheduledTransferCurrencyColumn reads the currency of the origin account, which the amount is in.
+const scheduledTransferCurrencyColumn = `(SELECT a.currency FROM accounts a WHERE a.id = account_origin_id)`
+
+func (schRepo scheduledTransferRepository) Create(ctx context.Context, schedule *model.ScheduledTransfer) error {
+	var query = `
+		INSERT INTO
+			scheduled_transfers (id, account_origin_id, account_destination_id, amount, scheduled_for, status, created_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := getConnFromCtx(ctx, schRepo.db).Exec(
+		ctx,
+		query,
+		string(schedule.ID),
+		string(schedule.AccountOriginID),
+		string(schedule.AccountDestinationID),
+		schedule.Amount,
+		schedule.ScheduledFor,
+		schedule.Status,
+		schedule.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (schRepo scheduledTransferRepository) Fetch(ctx context.Context, originID model.AccountID, status model.ScheduledTransferStatus) ([]model.ScheduledTransfer, error) {
+	var query = `
+		SELECT
+			` + scheduledTransferColumns + `, ` + scheduledTransferCurrencyColumn + `
+		FROM scheduled_transfers
+		WHERE account_origin_id = $1
+		AND ($2 = '' OR status = $2)
+		ORDER BY scheduled_for, created_at
+	`
+
+	rows, err := getConnFromCtx(ctx, schRepo.db).Query(ctx, query, string(originID), string(status))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules = make([]model.ScheduledTransfer, 0)
+	for rows.Next() {
+		var schedule model.ScheduledTransfer
+		err := scanScheduledTransfer(rows, &schedule)
+		if err != nil {
+			return nil, err
+		}
+
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (schRepo scheduledTransferRepository) GetByIDForUpdate(ctx context.Context, id model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+	var query = `
+		SELECT
+			` + scheduledTransferColumns + `, ` + scheduledTransferCurrencyColumn + `
+		FROM scheduled_transfers
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	schedule := new(model.ScheduledTransfer)
+	err := scanScheduledTransfer(getConnFromCtx(ctx, schRepo.db).QueryRow(ctx, query, string(id)), schedule)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, repository.ErrScheduledTransferNotFound
+		}
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// LockNextDue walks the partial index of the pending schedules. SKIP LOCKED makes the executors running on
+// other replicas take the next schedules instead of waiting for the locked ones.
+func (schRepo scheduledTransferRepository) LockNextDue(ctx context.Context, now time.Time, skip []model.ScheduledTransferID) (*model.ScheduledTransfer, error) {
+	var query = `
+		SELECT
+			` + scheduledTransferColumns + `, ` + scheduledTransferCurrencyColumn + `
+		FROM scheduled_transfers
+		WHERE status = 'scheduled'
+		AND scheduled_for <= $1
+		AND id <> ALL($2)
+		ORDER BY scheduled_for
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	// never NULL, which would match no row
+	skipIDs := make([]string, 0, len(skip))
+	for _, id := range skip {
+		skipIDs = append(skipIDs, string(id))
+	}
+
+	schedule := new(model.ScheduledTransfer)
+	err := scanScheduledTransfer(getConnFromCtx(ctx, schRepo.db).QueryRow(ctx, query, now, skipIDs), schedule)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (schRepo scheduledTransferRepository) UpdateStatus(ctx context.Context, schedule *model.ScheduledTransfer) error {
+	var query = `
+		UPDATE scheduled_transfers
+		SET status = $2, transfer_id = $3, failure_reason = $4, processed_at = $5
+		WHERE id = $1
+	`
+
+	var transferID *string
+	if schedule.TransferID != "" {
+		id := string(schedule.TransferID)
+		transferID = &id
+	}
+
+	tag, err := getConnFromCtx(ctx, schRepo.db).Exec(ctx, query, string(schedule.ID), schedule.Status, transferID, schedule.FailureReason, schedule.ProcessedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrScheduledTransferNotFound
+	}
+
+	return nil
+}
+
+func (schRepo scheduledTransferRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return execTransaction(ctx, schRepo.db, txFunc)
+}
+
+func scanScheduledTransfer(row pgx.Row, schedule *model.ScheduledTransfer) error {
+	var transferID *string
+	var processedAt *time.Time
+	err := row.Scan(&schedule.ID, &schedule.AccountOriginID, &schedule.AccountDestinationID, &schedule.Amount, &schedule.ScheduledFor,
+		&schedule.Status, &transferID, &schedule.FailureReason, &schedule.CreatedAt, &processedAt, &schedule.Currency)
+	if err != nil {
+		return err
+	}
+	if transferID != nil {
+		schedule.TransferID = model.TransferID(*transferID)
+	}
+	if processedAt != nil {
+		schedule.ProcessedAt = *processedAt
+	}
+
+	return nil
+}
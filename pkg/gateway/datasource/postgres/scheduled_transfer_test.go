@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+func Test_scheduledTransferRepository_Fetch(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	originID, destinationID := model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, originID, "00000000001", 0)
+	insertTestAccount(t, destinationID, "00000000002", 0)
+
+	now := time.Now().Round(time.Microsecond)
+	later := model.NewScheduledTransfer(originID, destinationID, 200, now.Add(2*time.Hour))
+	sooner := model.NewScheduledTransfer(originID, destinationID, 100, now.Add(time.Hour))
+	received := model.NewScheduledTransfer(destinationID, originID, 300, now.Add(time.Hour))
+
+	schRepo := NewScheduledTransferRepository(testDbPool)
+	for _, schedule := range []*model.ScheduledTransfer{later, sooner, received} {
+		schedule.CreatedAt = schedule.CreatedAt.Round(time.Microsecond)
+		if err := schRepo.Create(backgroundCtx, schedule); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	sooner.Failed("current account balance is insufficient")
+	sooner.ProcessedAt = sooner.ProcessedAt.Round(time.Microsecond)
+	if err := schRepo.UpdateStatus(backgroundCtx, sooner); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	got, err := schRepo.Fetch(backgroundCtx, originID, "")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != sooner.ID || got[1].ID != later.ID {
+		t.Fatalf("Fetch() got = %v, want the origin schedules, the next ones first", got)
+	}
+	if got[0].Status != model.ScheduledTransferStatusFailed || got[0].FailureReason != sooner.FailureReason ||
+		!got[0].ProcessedAt.Equal(sooner.ProcessedAt) || !got[0].ScheduledFor.Equal(sooner.ScheduledFor) || got[0].Amount != 100 {
+		t.Errorf("Fetch() got[0] = %v, want %v", got[0], sooner)
+	}
+	if got[1].Status != model.ScheduledTransferStatusScheduled || !got[1].ProcessedAt.IsZero() || got[1].TransferID != "" {
+		t.Errorf("Fetch() got[1] = %v, want %v", got[1], later)
+	}
+
+	got, err = schRepo.Fetch(backgroundCtx, originID, model.ScheduledTransferStatusScheduled)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != later.ID {
+		t.Errorf("Fetch() got = %v, want only the pending schedule", got)
+	}
+}
+
+func Test_scheduledTransferRepository_GetByIDForUpdate(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	originID, destinationID := model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, originID, "00000000001", 0)
+	insertTestAccount(t, destinationID, "00000000002", 0)
+
+	schRepo := NewScheduledTransferRepository(testDbPool)
+	schedule := model.NewScheduledTransfer(originID, destinationID, 100, time.Now().Add(time.Hour))
+	if err := schRepo.Create(backgroundCtx, schedule); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	got, err := schRepo.GetByIDForUpdate(backgroundCtx, schedule.ID)
+	if err != nil || got.ID != schedule.ID || got.AccountOriginID != originID || got.Currency != model.DefaultCurrency {
+		t.Errorf("GetByIDForUpdate() got = %v, error = %v, want %v", got, err, schedule)
+	}
+
+	_, err = schRepo.GetByIDForUpdate(backgroundCtx, model.NewScheduledTransferID())
+	if err != repository.ErrScheduledTransferNotFound {
+		t.Errorf("GetByIDForUpdate() error = %v, want %v", err, repository.ErrScheduledTransferNotFound)
+	}
+}
+
+func Test_scheduledTransferRepository_LockNextDue(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	originID, destinationID := model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, originID, "00000000001", 0)
+	insertTestAccount(t, destinationID, "00000000002", 0)
+
+	now := time.Now()
+	dueFirst := model.NewScheduledTransfer(originID, destinationID, 100, now.Add(-2*time.Minute))
+	dueSecond := model.NewScheduledTransfer(originID, destinationID, 100, now.Add(-time.Minute))
+	notDue := model.NewScheduledTransfer(originID, destinationID, 100, now.Add(time.Hour))
+	cancelled := model.NewScheduledTransfer(originID, destinationID, 100, now.Add(-3*time.Minute))
+
+	schRepo := NewScheduledTransferRepository(testDbPool)
+	for _, schedule := range []*model.ScheduledTransfer{dueFirst, dueSecond, notDue, cancelled} {
+		if err := schRepo.Create(backgroundCtx, schedule); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	cancelled.Cancelled()
+	if err := schRepo.UpdateStatus(backgroundCtx, cancelled); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	// each transaction simulates an executor running on another replica
+	var txs []pgx.Tx
+	var txCtxs []context.Context
+	for i := 0; i < 3; i++ {
+		tx, err := testDbPool.Begin(backgroundCtx)
+		if err != nil {
+			t.Fatalf("error beginning transaction = %v", err)
+		}
+		defer func() {
+			_ = tx.Rollback(backgroundCtx)
+		}()
+		txs = append(txs, tx)
+		txCtxs = append(txCtxs, context.WithValue(backgroundCtx, transactionContextKey, tx))
+	}
+
+	wants := []*model.ScheduledTransfer{dueFirst, dueSecond, nil}
+	for i, want := range wants {
+		got, err := schRepo.LockNextDue(txCtxs[i], now, nil)
+		if err != nil {
+			t.Fatalf("LockNextDue() error = %v", err)
+		}
+		if (got == nil) != (want == nil) || (got != nil && got.ID != want.ID) {
+			t.Errorf("LockNextDue() on transaction %d got = %v, want %v", i, got, want)
+		}
+	}
+	// the skipped schedules are not locked, even when they're free
+	for _, tx := range txs[:2] {
+		_ = tx.Rollback(backgroundCtx)
+	}
+	got, err := schRepo.LockNextDue(txCtxs[2], now, []model.ScheduledTransferID{dueFirst.ID})
+	if err != nil || got == nil || got.ID != dueSecond.ID {
+		t.Errorf("LockNextDue() skipping the first got = %v, error = %v, want %v", got, err, dueSecond)
+	}
+}
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type standingOrderRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewStandingOrderRepository instantiates a new standing order postgres repository.
+func NewStandingOrderRepository(db *pgxpool.Pool) repository.StandingOrderRepository {
+	return &standingOrderRepository{db}
+}
+
+const standingOrderColumns = `id, account_origin_id, account_destination_id, amount, frequency, day_of_month,
+	starts_at, utc_offset, ends_at, max_occurrences, occurrences, next_due_at, next_run_at, retries, status,
+	last_transfer_id, last_failure_reason, last_run_at, created_at, updated_at`
+
+// standingOrderCurrencyColumn reads the currency of the origin account, which the amount is in.
+const standingOrderCurrencyColumn = `(SELECT a.currency FROM accounts a WHERE a.id = account_origin_id)`
+
+func (soRepo standingOrderRepository) Create(ctx context.Context, order *model.StandingOrder) error {
+	var query = `
+		INSERT INTO
+			standing_orders (` + standingOrderColumns + `)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+	`
+
+	_, utcOffset := order.StartsAt.Zone()
+	lastTransferID, lastRunAt := nullableStandingOrderLastRun(order)
+
+	_, err := getConnFromCtx(ctx, soRepo.db).Exec(
+		ctx,
+		query,
+		string(order.ID),
+		string(order.AccountOriginID),
+		string(order.AccountDestinationID),
+		order.Amount,
+		order.Frequency,
+		order.DayOfMonth,
+		order.StartsAt,
+		utcOffset,
+		nullableTime(order.EndsAt),
+		order.MaxOccurrences,
+		order.Occurrences,
+		order.NextDueAt,
+		order.NextRunAt,
+		order.Retries,
+		order.Status,
+		lastTransferID,
+		order.LastFailureReason,
+		lastRunAt,
+		order.CreatedAt,
+		order.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (soRepo standingOrderRepository) Fetch(ctx context.Context, originID model.AccountID) ([]model.StandingOrder, error) {
+	var query = `
+		SELECT
+			` + standingOrderColumns + `, ` + standingOrderCurrencyColumn + `
+		FROM standing_orders
+		WHERE account_origin_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := getConnFromCtx(ctx, soRepo.db).Query(ctx, query, string(originID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders = make([]model.StandingOrder, 0)
+	for rows.Next() {
+		var order model.StandingOrder
+		err := scanStandingOrder(rows, &order)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (soRepo standingOrderRepository) GetByIDForUpdate(ctx context.Context, id model.StandingOrderID) (*model.StandingOrder, error) {
+	var query = `
+		SELECT
+			` + standingOrderColumns + `, ` + standingOrderCurrencyColumn + `
+		FROM standing_orders
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	order := new(model.StandingOrder)
+	err := scanStandingOrder(getConnFromCtx(ctx, soRepo.db).QueryRow(ctx, query, string(id)), order)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, repository.ErrStandingOrderNotFound
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// LockNextDue walks the partial index of the active standing orders. SKIP LOCKED makes the executors running on
+// other replicas take the next standing orders instead of waiting for the locked ones.
+func (soRepo standingOrderRepository) LockNextDue(ctx context.Context, now time.Time) (*model.StandingOrder, error) {
+	var query = `
+		SELECT
+			` + standingOrderColumns + `, ` + standingOrderCurrencyColumn + `
+		FROM standing_orders
+		WHERE status = 'active'
+		AND next_run_at <= $1
+		ORDER BY next_run_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	order := new(model.StandingOrder)
+	err := scanStandingOrder(getConnFromCtx(ctx, soRepo.db).QueryRow(ctx, query, now), order)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (soRepo standingOrderRepository) Update(ctx context.Context, order *model.StandingOrder) error {
+	var query = `
+		UPDATE standing_orders
+		SET account_destination_id = $2, amount = $3, ends_at = $4, max_occurrences = $5, occurrences = $6,
+			next_due_at = $7, next_run_at = $8, retries = $9, status = $10, last_transfer_id = $11,
+			last_failure_reason = $12, last_run_at = $13, updated_at = $14
+		WHERE id = $1
+	`
+
+	lastTransferID, lastRunAt := nullableStandingOrderLastRun(order)
+
+	tag, err := getConnFromCtx(ctx, soRepo.db).Exec(
+		ctx,
+		query,
+		string(order.ID),
+		string(order.AccountDestinationID),
+		order.Amount,
+		nullableTime(order.EndsAt),
+		order.MaxOccurrences,
+		order.Occurrences,
+		order.NextDueAt,
+		order.NextRunAt,
+		order.Retries,
+		order.Status,
+		lastTransferID,
+		order.LastFailureReason,
+		lastRunAt,
+		order.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrStandingOrderNotFound
+	}
+
+	return nil
+}
+
+func (soRepo standingOrderRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return execTransaction(ctx, soRepo.db, txFunc)
+}
+
+func nullableStandingOrderLastRun(order *model.StandingOrder) (lastTransferID *string, lastRunAt *time.Time) {
+	if order.LastTransferID != "" {
+		id := string(order.LastTransferID)
+		lastTransferID = &id
+	}
+
+	return lastTransferID, nullableTime(order.LastRunAt)
+}
+
+func nullableTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+// scanStandingOrder restores the UTC offset of the start date, which the recurrence depends on.
+func scanStandingOrder(row pgx.Row, order *model.StandingOrder) error {
+	var utcOffset int
+	var endsAt, lastRunAt *time.Time
+	var lastTransferID *string
+	err := row.Scan(&order.ID, &order.AccountOriginID, &order.AccountDestinationID, &order.Amount, &order.Frequency,
+		&order.DayOfMonth, &order.StartsAt, &utcOffset, &endsAt, &order.MaxOccurrences, &order.Occurrences,
+		&order.NextDueAt, &order.NextRunAt, &order.Retries, &order.Status, &lastTransferID, &order.LastFailureReason,
+		&lastRunAt, &order.CreatedAt, &order.UpdatedAt, &order.Currency)
+	if err != nil {
+		return err
+	}
+
+	order.StartsAt = order.StartsAt.In(time.FixedZone("", utcOffset))
+	if endsAt != nil {
+		order.EndsAt = *endsAt
+	}
+	if lastTransferID != nil {
+		order.LastTransferID = model.TransferID(*lastTransferID)
+	}
+	if lastRunAt != nil {
+		order.LastRunAt = *lastRunAt
+	}
+
+	return nil
+}
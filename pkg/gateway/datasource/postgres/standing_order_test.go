@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+func Test_standingOrderRepository_CreateAndUpdate(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	originID, destinationID := model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, originID, "00000000001", 0)
+	insertTestAccount(t, destinationID, "00000000002", 0)
+
+	brt := time.FixedZone("BRT", -3*60*60)
+	startsAt := time.Date(2021, 1, 10, 9, 0, 0, 0, brt)
+	endsAt := time.Date(2021, 12, 31, 0, 0, 0, 0, brt)
+	order := model.NewStandingOrder(originID, destinationID, 1000, model.StandingOrderFrequencyLastBusinessDay, 0, startsAt, endsAt, 0)
+	order.CreatedAt = order.CreatedAt.Round(time.Microsecond)
+	order.UpdatedAt = order.CreatedAt
+
+	soRepo := NewStandingOrderRepository(testDbPool)
+	if err := soRepo.Create(backgroundCtx, order); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	got, err := soRepo.GetByIDForUpdate(backgroundCtx, order.ID)
+	if err != nil {
+		t.Fatalf("GetByIDForUpdate() error = %v", err)
+	}
+	if _, offset := got.StartsAt.Zone(); offset != -3*60*60 || !got.StartsAt.Equal(startsAt) || !got.EndsAt.Equal(endsAt) {
+		t.Errorf("GetByIDForUpdate() StartsAt = %v, EndsAt = %v, want %v and %v", got.StartsAt, got.EndsAt, startsAt, endsAt)
+	}
+	if !got.NextDueAt.Equal(order.NextDueAt) || got.Status != model.StandingOrderStatusActive || got.LastTransferID != "" || !got.LastRunAt.IsZero() ||
+		got.Currency != model.DefaultCurrency {
+		t.Errorf("GetByIDForUpdate() got = %v, want %v", got, order)
+	}
+
+	// the occurrence after the next one must be computed on the holder's calendar
+	if want := time.Date(2021, 2, 26, 9, 0, 0, 0, brt); !got.OccurrenceAfter(got.NextDueAt).Equal(want) {
+		t.Errorf("OccurrenceAfter() = %v, want %v", got.OccurrenceAfter(got.NextDueAt), want)
+	}
+
+	got.Failed("current account balance is insufficient", 3, time.Hour)
+	got.Amount = 2000
+	got.EndsAt = time.Time{}
+	if err := soRepo.Update(backgroundCtx, got); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	orders, err := soRepo.Fetch(backgroundCtx, originID)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(orders) != 1 {
+		t.Fatalf("Fetch() got = %v, want 1 standing order", orders)
+	}
+	if orders[0].Amount != 2000 || !orders[0].EndsAt.IsZero() || orders[0].Retries != 1 ||
+		orders[0].LastFailureReason != "current account balance is insufficient" || orders[0].LastRunAt.IsZero() {
+		t.Errorf("Fetch() got = %v, want the updated standing order", orders[0])
+	}
+
+	_, err = soRepo.GetByIDForUpdate(backgroundCtx, model.NewStandingOrderID())
+	if err != repository.ErrStandingOrderNotFound {
+		t.Errorf("GetByIDForUpdate() error = %v, want %v", err, repository.ErrStandingOrderNotFound)
+	}
+}
+
+func Test_standingOrderRepository_LockNextDue(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	originID, destinationID := model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, originID, "00000000001", 0)
+	insertTestAccount(t, destinationID, "00000000002", 0)
+
+	now := time.Now()
+	newOrder := func(startsAt time.Time) *model.StandingOrder {
+		return model.NewStandingOrder(originID, destinationID, 100, model.StandingOrderFrequencyWeekly, 0, startsAt, time.Time{}, 0)
+	}
+	dueFirst := newOrder(now.Add(-2 * time.Minute))
+	dueSecond := newOrder(now.Add(-time.Minute))
+	notDue := newOrder(now.Add(time.Hour))
+	paused := newOrder(now.Add(-3 * time.Minute))
+	paused.ChangeStatus(model.StandingOrderStatusPaused)
+
+	soRepo := NewStandingOrderRepository(testDbPool)
+	for _, order := range []*model.StandingOrder{dueFirst, dueSecond, notDue, paused} {
+		if err := soRepo.Create(backgroundCtx, order); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	// each transaction simulates an executor running on another replica
+	var txCtxs []context.Context
+	for i := 0; i < 3; i++ {
+		tx, err := testDbPool.Begin(backgroundCtx)
+		if err != nil {
+			t.Fatalf("error beginning transaction = %v", err)
+		}
+		defer func() {
+			_ = tx.Rollback(backgroundCtx)
+		}()
+		txCtxs = append(txCtxs, context.WithValue(backgroundCtx, transactionContextKey, tx))
+	}
+
+	wants := []*model.StandingOrder{dueFirst, dueSecond, nil}
+	for i, want := range wants {
+		got, err := soRepo.LockNextDue(txCtxs[i], now)
+		if err != nil {
+			t.Fatalf("LockNextDue() error = %v", err)
+		}
+		if (got == nil) != (want == nil) || (got != nil && got.ID != want.ID) {
+			t.Errorf("LockNextDue() on transaction %d got = %v, want %v", i, got, want)
+		}
+	}
+}
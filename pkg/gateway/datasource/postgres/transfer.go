@@ -2,7 +2,14 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
@@ -18,40 +25,121 @@ func NewTransferRepository(db *pgxpool.Pool) repository.TransferRepository {
 	return &transferRepository{db}
 }
 
+const transferColumns = `id, kind, original_transfer_id, account_origin_id, account_destination_id, currency, amount, refunded_amount,
+	fx_quote_id, fx_rate, destination_currency, destination_amount, fee, created_at`
+
 func (trfRepo transferRepository) Create(ctx context.Context, transfer *model.Transfer) error {
 	var query = `
 		INSERT INTO
-			transfers (id, account_origin_id, account_destination_id, amount, created_at)
+			transfers (id, kind, original_transfer_id, account_origin_id, account_destination_id, currency, amount,
+				refunded_amount, fx_quote_id, fx_rate, destination_currency, destination_amount, fee, created_at)
 		VALUES
-			($1, $2, $3, $4, $5)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
+	var originalTransferID *string
+	if transfer.OriginalTransferID != "" {
+		id := string(transfer.OriginalTransferID)
+		originalTransferID = &id
+	}
+
+	// the conversion columns are only filled for cross-currency transfers
+	var fxRate, destinationAmount *int64
+	var destinationCurrency *string
+	if transfer.IsConversion() {
+		rate, amount, currency := int64(transfer.FXRate), int64(transfer.DestinationAmount), string(transfer.DestinationCurrency)
+		fxRate, destinationAmount, destinationCurrency = &rate, &amount, &currency
+	}
+
 	_, err := getConnFromCtx(ctx, trfRepo.db).Exec(
 		ctx,
 		query,
 		string(transfer.ID),
+		transfer.Kind,
+		originalTransferID,
 		string(transfer.AccountOriginID),
 		string(transfer.AccountDestinationID),
+		currencyOrDefault(transfer.Currency),
 		transfer.Amount,
+		transfer.RefundedAmount,
+		nullableString(string(transfer.FXQuoteID)),
+		fxRate,
+		destinationCurrency,
+		destinationAmount,
+		transfer.Fee,
 		transfer.CreatedAt,
 	)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation && pgErr.ConstraintName == "transfers_fx_quote_id_idx" {
+			return repository.ErrFXQuoteUsed
+		}
 		return err
 	}
 
 	return nil
 }
 
-func (trfRepo transferRepository) Fetch(ctx context.Context, accountID model.AccountID) ([]model.Transfer, error) {
-	var query = `
-		SELECT
-			id, account_origin_id, account_destination_id, amount, created_at
-		FROM transfers
-		WHERE account_origin_id = $1 OR account_destination_id = $1
-		ORDER BY created_at desc
-	`
+// Fetch queries the sent and the received transfers separately, each one walking its
+// (account, created_at, id) index backwards from the cursor, and merges them.
+func (trfRepo transferRepository) Fetch(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error) {
+	args := []interface{}{string(filter.AccountID)}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
 
-	rows, err := getConnFromCtx(ctx, trfRepo.db).Query(ctx, query, string(accountID))
+	var conditions []string
+	if !filter.CreatedFrom.IsZero() {
+		conditions = append(conditions, "created_at >= "+addArg(filter.CreatedFrom))
+	}
+	if !filter.CreatedTo.IsZero() {
+		conditions = append(conditions, "created_at < "+addArg(filter.CreatedTo))
+	}
+	if filter.MinAmount > 0 {
+		conditions = append(conditions, "amount >= "+addArg(filter.MinAmount))
+	}
+	if filter.MaxAmount > 0 {
+		conditions = append(conditions, "amount <= "+addArg(filter.MaxAmount))
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < (%s, %s::uuid)", addArg(filter.After.CreatedAt), addArg(string(filter.After.ID))))
+	}
+	counterpartArg := ""
+	if filter.CounterpartID != "" {
+		counterpartArg = addArg(string(filter.CounterpartID))
+	}
+	limitArg := addArg(filter.Limit)
+
+	branch := func(accountColumn, counterpartColumn string) string {
+		where := append([]string{accountColumn + " = $1"}, conditions...)
+		if counterpartArg != "" {
+			where = append(where, counterpartColumn+" = "+counterpartArg)
+		}
+
+		return fmt.Sprintf(`
+			(SELECT
+				%s
+			FROM transfers
+			WHERE %s
+			ORDER BY created_at desc, id desc
+			LIMIT %s)`, transferColumns, strings.Join(where, " AND "), limitArg)
+	}
+
+	var branches []string
+	if filter.Direction != model.TransferDirectionReceived {
+		branches = append(branches, branch("account_origin_id", "account_destination_id"))
+	}
+	if filter.Direction != model.TransferDirectionSent {
+		branches = append(branches, branch("account_destination_id", "account_origin_id"))
+	}
+
+	// an account can't transfer to itself, so the branches never return the same transfer
+	query := strings.Join(branches, "\n\t\tUNION ALL") + `
+		ORDER BY created_at desc, id desc
+		LIMIT ` + limitArg
+
+	rows, err := getConnFromCtx(ctx, trfRepo.db).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +148,7 @@ func (trfRepo transferRepository) Fetch(ctx context.Context, accountID model.Acc
 	var transfers = make([]model.Transfer, 0)
 	for rows.Next() {
 		var transfer model.Transfer
-		err := rows.Scan(&transfer.ID, &transfer.AccountOriginID, &transfer.AccountDestinationID, &transfer.Amount, &transfer.CreatedAt)
+		err := scanTransfer(rows, &transfer)
 		if err != nil {
 			return nil, err
 		}
@@ -74,6 +162,126 @@ func (trfRepo transferRepository) Fetch(ctx context.Context, accountID model.Acc
 	return transfers, nil
 }
 
+func (trfRepo transferRepository) GetByIDForUpdate(ctx context.Context, id model.TransferID) (*model.Transfer, error) {
+	var query = `
+		SELECT
+			` + transferColumns + `
+		FROM transfers
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	transfer := new(model.Transfer)
+	err := scanTransfer(getConnFromCtx(ctx, trfRepo.db).QueryRow(ctx, query, string(id)), transfer)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, repository.ErrTransferNotFound
+		}
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (trfRepo transferRepository) UpdateRefundedAmount(ctx context.Context, transfer *model.Transfer) error {
+	var query = `
+		UPDATE transfers
+		SET refunded_amount = $2
+		WHERE id = $1
+	`
+
+	tag, err := getConnFromCtx(ctx, trfRepo.db).Exec(ctx, query, string(transfer.ID), transfer.RefundedAmount)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrTransferNotFound
+	}
+
+	return nil
+}
+
+// GetSentTotals reads the sent transfers from the origin keyset index and the withdrawals from their account index,
+// since the start of the month or the start of the night, whichever comes first.
+func (trfRepo transferRepository) GetSentTotals(ctx context.Context, accountID model.AccountID, periods model.TransferLimitPeriods) (*model.TransferTotals, error) {
+	var query = `
+		SELECT
+			COALESCE(SUM(amount) FILTER (WHERE created_at >= $2), 0),
+			COALESCE(SUM(amount) FILTER (WHERE created_at >= $3), 0),
+			COALESCE(SUM(amount) FILTER (WHERE created_at >= $4), 0)
+		FROM (
+			SELECT amount, created_at
+			FROM transfers
+			WHERE account_origin_id = $1 AND kind = 'transfer' AND created_at >= LEAST($3, $4)
+			UNION ALL
+			SELECT amount, created_at
+			FROM withdrawals
+			WHERE account_id = $1 AND created_at >= LEAST($3, $4)
+		) sent
+	`
+
+	var nightStart *time.Time
+	if periods.IsNight() {
+		nightStart = &periods.NightStart
+	}
+
+	totals := new(model.TransferTotals)
+	err := getConnFromCtx(ctx, trfRepo.db).QueryRow(ctx, query, string(accountID), periods.DayStart, periods.MonthStart, nightStart).
+		Scan(&totals.Daily, &totals.Monthly, &totals.Night)
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// CountSent reads the sent transfers from the origin keyset index only.
+func (trfRepo transferRepository) CountSent(ctx context.Context, accountID model.AccountID, since time.Time) (int, error) {
+	var query = `
+		SELECT COUNT(*)
+		FROM transfers
+		WHERE account_origin_id = $1 AND kind = 'transfer' AND created_at >= $2
+	`
+
+	count := 0
+	err := getConnFromCtx(ctx, trfRepo.db).QueryRow(ctx, query, string(accountID), since).Scan(&count)
+	return count, err
+}
+
 func (trfRepo transferRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
 	return execTransaction(ctx, trfRepo.db, txFunc)
 }
+
+func scanTransfer(row pgx.Row, transfer *model.Transfer) error {
+	var originalTransferID, fxQuoteID, destinationCurrency *string
+	var fxRate, destinationAmount *int64
+	err := row.Scan(&transfer.ID, &transfer.Kind, &originalTransferID, &transfer.AccountOriginID, &transfer.AccountDestinationID,
+		&transfer.Currency, &transfer.Amount, &transfer.RefundedAmount, &fxQuoteID, &fxRate, &destinationCurrency,
+		&destinationAmount, &transfer.Fee, &transfer.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if originalTransferID != nil {
+		transfer.OriginalTransferID = model.TransferID(*originalTransferID)
+	}
+
+	if fxQuoteID == nil {
+		transfer.InCurrency(transfer.Currency)
+		return nil
+	}
+	transfer.FXQuoteID = model.FXQuoteID(*fxQuoteID)
+	transfer.FXRate = model.FXRate(*fxRate)
+	transfer.DestinationCurrency = model.Currency(*destinationCurrency)
+	transfer.DestinationAmount = model.Money(*destinationAmount)
+
+	return nil
+}
+
+// currencyOrDefault returns the currency, or model.DefaultCurrency when it's not set.
+func currencyOrDefault(currency model.Currency) model.Currency {
+	if currency == "" {
+		return model.DefaultCurrency
+	}
+
+	return currency
+}
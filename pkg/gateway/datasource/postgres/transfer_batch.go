@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type transferBatchRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTransferBatchRepository instantiates a new transfer batch postgres repository.
+func NewTransferBatchRepository(db *pgxpool.Pool) repository.TransferBatchRepository {
+	return &transferBatchRepository{db}
+}
+
+const transferBatchItemColumns = `item_id, account_destination_id, amount, status, transfer_id, failure_reason`
+
+func (batchRepo transferBatchRepository) Create(ctx context.Context, batch *model.TransferBatch) error {
+	var query = `
+		INSERT INTO
+			transfer_batches (id, account_origin_id, mode, status, created_at, completed_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := getConnFromCtx(ctx, batchRepo.db).Exec(
+		ctx,
+		query,
+		string(batch.ID),
+		string(batch.AccountOriginID),
+		batch.Mode,
+		batch.Status,
+		batch.CreatedAt,
+		nullableTime(batch.CompletedAt),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (batchRepo transferBatchRepository) SaveItem(ctx context.Context, batch *model.TransferBatch, position int) error {
+	var query = `
+		INSERT INTO
+			transfer_batch_items (batch_id, position, item_id, account_origin_id, account_destination_id, amount,
+				status, transfer_id, failure_reason)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	item := batch.Items[position]
+	_, err := getConnFromCtx(ctx, batchRepo.db).Exec(
+		ctx,
+		query,
+		string(batch.ID),
+		position,
+		item.ItemID,
+		string(batch.AccountOriginID),
+		string(item.AccountDestinationID),
+		item.Amount,
+		item.Status,
+		nullableString(string(item.TransferID)),
+		nullableString(item.FailureReason),
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation && pgErr.ConstraintName == "transfer_batch_items_executed_idx" {
+			return repository.ErrTransferBatchItemExecuted
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (batchRepo transferBatchRepository) UpdateStatus(ctx context.Context, batch *model.TransferBatch) error {
+	var query = `
+		UPDATE transfer_batches
+		SET status = $2, completed_at = $3
+		WHERE id = $1
+	`
+
+	tag, err := getConnFromCtx(ctx, batchRepo.db).Exec(ctx, query, string(batch.ID), batch.Status, nullableTime(batch.CompletedAt))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrTransferBatchNotFound
+	}
+
+	return nil
+}
+
+func (batchRepo transferBatchRepository) GetByID(ctx context.Context, id model.TransferBatchID) (*model.TransferBatch, error) {
+	var query = `
+		SELECT
+			id, account_origin_id, mode, status, created_at, completed_at,
+			(SELECT a.currency FROM accounts a WHERE a.id = account_origin_id)
+		FROM transfer_batches
+		WHERE id = $1
+	`
+
+	conn := getConnFromCtx(ctx, batchRepo.db)
+
+	batch := new(model.TransferBatch)
+	var completedAt *time.Time
+	err := conn.QueryRow(ctx, query, string(id)).Scan(&batch.ID, &batch.AccountOriginID, &batch.Mode, &batch.Status,
+		&batch.CreatedAt, &completedAt, &batch.Currency)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, repository.ErrTransferBatchNotFound
+		}
+		return nil, err
+	}
+	if completedAt != nil {
+		batch.CompletedAt = *completedAt
+	}
+
+	query = `
+		SELECT
+			` + transferBatchItemColumns + `
+		FROM transfer_batch_items
+		WHERE batch_id = $1
+		ORDER BY position
+	`
+
+	batch.Items, err = batchRepo.queryItems(ctx, query, string(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+func (batchRepo transferBatchRepository) FetchExecutedItems(ctx context.Context, originID model.AccountID, itemIDs []string) ([]model.TransferBatchItem, error) {
+	var query = `
+		SELECT
+			` + transferBatchItemColumns + `
+		FROM transfer_batch_items
+		WHERE account_origin_id = $1
+		AND item_id = ANY($2)
+		AND status = 'executed'
+	`
+
+	return batchRepo.queryItems(ctx, query, string(originID), itemIDs)
+}
+
+func (batchRepo transferBatchRepository) queryItems(ctx context.Context, query string, args ...interface{}) ([]model.TransferBatchItem, error) {
+	rows, err := getConnFromCtx(ctx, batchRepo.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items = make([]model.TransferBatchItem, 0)
+	for rows.Next() {
+		var item model.TransferBatchItem
+		var transferID, failureReason *string
+		err := rows.Scan(&item.ItemID, &item.AccountDestinationID, &item.Amount, &item.Status, &transferID, &failureReason)
+		if err != nil {
+			return nil, err
+		}
+		if transferID != nil {
+			item.TransferID = model.TransferID(*transferID)
+		}
+		if failureReason != nil {
+			item.FailureReason = *failureReason
+		}
+
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (batchRepo transferBatchRepository) WithinTransaction(ctx context.Context, txFunc func(context.Context) (interface{}, error)) (data interface{}, err error) {
+	return execTransaction(ctx, batchRepo.db, txFunc)
+}
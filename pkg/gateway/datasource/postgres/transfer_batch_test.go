@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+func Test_transferBatchRepository(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	originID, destinationID := model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, originID, "00000000001", 1000)
+	insertTestAccount(t, destinationID, "00000000002", 0)
+
+	transfer := model.NewTransfer(string(originID), string(destinationID), 100)
+	if err := NewTransferRepository(testDbPool).Create(backgroundCtx, transfer); err != nil {
+		t.Fatalf("error creating transfer = %v", err)
+	}
+
+	batchRepo := NewTransferBatchRepository(testDbPool)
+
+	first := model.NewTransferBatch(originID, model.TransferBatchModeBestEffort, []model.TransferBatchItem{
+		{ItemID: "payroll-1", AccountDestinationID: destinationID, Amount: 100},
+		{ItemID: "payroll-2", AccountDestinationID: "unknown", Amount: 200},
+	})
+	if err := batchRepo.Create(backgroundCtx, first); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	first.Items[0].Executed(transfer.ID)
+	first.Items[1].Rejected("account not found")
+	for position := range first.Items {
+		if err := batchRepo.SaveItem(backgroundCtx, first, position); err != nil {
+			t.Fatalf("SaveItem() error = %v", err)
+		}
+	}
+	first.Completed()
+	if err := batchRepo.UpdateStatus(backgroundCtx, first); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	got, err := batchRepo.GetByID(backgroundCtx, first.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.Status != model.TransferBatchStatusPartiallyCompleted || got.Mode != model.TransferBatchModeBestEffort ||
+		got.AccountOriginID != originID || got.Currency != model.DefaultCurrency || got.CompletedAt.IsZero() || len(got.Items) != 2 {
+		t.Fatalf("GetByID() got = %v, want %v", got, first)
+	}
+	for i, item := range got.Items {
+		if item != first.Items[i] {
+			t.Errorf("GetByID() item %d = %v, want %v", i, item, first.Items[i])
+		}
+	}
+
+	// the rejected item ID can be executed by a later batch, but not the executed one
+	second := model.NewTransferBatch(originID, model.TransferBatchModeAtomic, []model.TransferBatchItem{
+		{ItemID: "payroll-1", AccountDestinationID: destinationID, Amount: 100},
+	})
+	if err := batchRepo.Create(backgroundCtx, second); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	second.Items[0].Executed(transfer.ID)
+	if err := batchRepo.SaveItem(backgroundCtx, second, 0); err != repository.ErrTransferBatchItemExecuted {
+		t.Errorf("SaveItem() error = %v, want %v", err, repository.ErrTransferBatchItemExecuted)
+	}
+	second.Items[0].Duplicate(transfer.ID)
+	if err := batchRepo.SaveItem(backgroundCtx, second, 0); err != nil {
+		t.Errorf("SaveItem() error = %v", err)
+	}
+
+	executed, err := batchRepo.FetchExecutedItems(backgroundCtx, originID, []string{"payroll-1", "payroll-2", "payroll-3"})
+	if err != nil {
+		t.Fatalf("FetchExecutedItems() error = %v", err)
+	}
+	if len(executed) != 1 || executed[0].ItemID != "payroll-1" || executed[0].TransferID != transfer.ID {
+		t.Errorf("FetchExecutedItems() got = %v, want only the executed item", executed)
+	}
+
+	_, err = batchRepo.GetByID(backgroundCtx, model.NewTransferBatchID())
+	if err != repository.ErrTransferBatchNotFound {
+		t.Errorf("GetByID() error = %v, want %v", err, repository.ErrTransferBatchNotFound)
+	}
+}
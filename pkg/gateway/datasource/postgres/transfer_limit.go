@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type transferLimitRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTransferLimitRepository instantiates a new transfer limit postgres repository.
+func NewTransferLimitRepository(db *pgxpool.Pool) repository.TransferLimitRepository {
+	return &transferLimitRepository{db}
+}
+
+func (limitRepo transferLimitRepository) Get(ctx context.Context, accountID model.AccountID) (*model.AccountTransferLimits, error) {
+	var query = `
+		SELECT
+			per_transaction, daily, monthly, night_per_transaction, night_total, updated_at
+		FROM transfer_limits
+		WHERE account_id = $1
+	`
+
+	limits := &model.AccountTransferLimits{AccountID: accountID}
+	err := getConnFromCtx(ctx, limitRepo.db).QueryRow(ctx, query, string(accountID)).
+		Scan(&limits.PerTransaction, &limits.Daily, &limits.Monthly, &limits.NightPerTransaction, &limits.NightTotal, &limits.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return &model.AccountTransferLimits{AccountID: accountID}, nil
+		}
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+func (limitRepo transferLimitRepository) Save(ctx context.Context, limits *model.AccountTransferLimits) error {
+	var query = `
+		INSERT INTO
+			transfer_limits (account_id, per_transaction, daily, monthly, night_per_transaction, night_total, updated_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (account_id) DO UPDATE SET
+			per_transaction = EXCLUDED.per_transaction,
+			daily = EXCLUDED.daily,
+			monthly = EXCLUDED.monthly,
+			night_per_transaction = EXCLUDED.night_per_transaction,
+			night_total = EXCLUDED.night_total,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := getConnFromCtx(ctx, limitRepo.db).Exec(
+		ctx,
+		query,
+		string(limits.AccountID),
+		limits.PerTransaction,
+		limits.Daily,
+		limits.Monthly,
+		limits.NightPerTransaction,
+		limits.NightTotal,
+		limits.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
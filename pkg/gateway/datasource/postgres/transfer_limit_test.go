@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+func Test_transferLimitRepository_Get_Save(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	accountID := model.NewAccountID()
+	insertTestAccount(t, accountID, "00000000001", 0)
+
+	limitRepo := NewTransferLimitRepository(testDbPool)
+
+	got, err := limitRepo.Get(backgroundCtx, accountID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &model.AccountTransferLimits{AccountID: accountID}) {
+		t.Errorf("Get() got = %v, want no limits set", got)
+	}
+
+	daily, nightTotal := model.Money(250000), model.Money(0)
+	limits := &model.AccountTransferLimits{
+		AccountID:  accountID,
+		Daily:      &daily,
+		NightTotal: &nightTotal,
+		UpdatedAt:  time.Now().Round(time.Microsecond),
+	}
+	if err := limitRepo.Save(backgroundCtx, limits); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err = limitRepo.Get(backgroundCtx, accountID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.PerTransaction != nil || got.Monthly != nil || got.NightPerTransaction != nil ||
+		*got.Daily != daily || *got.NightTotal != nightTotal || !got.UpdatedAt.Equal(limits.UpdatedAt) {
+		t.Errorf("Get() got = %v, want %v", got, limits)
+	}
+
+	// saving again replaces all the limits
+	perTransaction := model.Money(10000)
+	limits = &model.AccountTransferLimits{
+		AccountID:      accountID,
+		PerTransaction: &perTransaction,
+		UpdatedAt:      time.Now().Round(time.Microsecond),
+	}
+	if err := limitRepo.Save(backgroundCtx, limits); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err = limitRepo.Get(backgroundCtx, accountID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Daily != nil || got.NightTotal != nil || *got.PerTransaction != perTransaction {
+		t.Errorf("Get() got = %v, want %v", got, limits)
+	}
+
+	if err := limitRepo.Save(backgroundCtx, &model.AccountTransferLimits{AccountID: model.NewAccountID(), UpdatedAt: time.Now()}); err == nil {
+		t.Errorf("Save() should fail when the account does not exist")
+	}
+}
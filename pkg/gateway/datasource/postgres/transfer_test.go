@@ -3,7 +3,9 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,6 +13,8 @@ import (
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
 )
 
 func Test_transferRepository_Create(t *testing.T) {
@@ -40,6 +44,7 @@ func Test_transferRepository_Create(t *testing.T) {
 				ctx: backgroundCtx,
 				transfer: &model.Transfer{
 					ID:                   model.NewTransferID(),
+					Kind:                 model.TransferKindTransfer,
 					AccountOriginID:      model.NewAccountID(),
 					AccountDestinationID: model.NewAccountID(),
 					Amount:               10,
@@ -70,6 +75,7 @@ func Test_transferRepository_Create(t *testing.T) {
 				ctx: backgroundCtx,
 				transfer: &model.Transfer{
 					ID:                   model.NewTransferID(),
+					Kind:                 model.TransferKindTransfer,
 					AccountOriginID:      model.NewAccountID(),
 					AccountDestinationID: model.NewAccountID(),
 					Amount:               10,
@@ -100,6 +106,7 @@ func Test_transferRepository_Create(t *testing.T) {
 				ctx: backgroundCtx,
 				transfer: &model.Transfer{
 					ID:                   model.NewTransferID(),
+					Kind:                 model.TransferKindTransfer,
 					AccountOriginID:      model.NewAccountID(),
 					AccountDestinationID: model.NewAccountID(),
 					Amount:               10,
@@ -130,8 +137,7 @@ func Test_transferRepository_Create(t *testing.T) {
 			},
 			check: func(args args) {
 				var got model.Transfer
-				err := testDbPool.QueryRow(backgroundCtx, "SELECT id, account_origin_id, account_destination_id, amount, created_at FROM transfers WHERE id = $1", string(args.transfer.ID)).
-					Scan(&got.ID, &got.AccountOriginID, &got.AccountDestinationID, &got.Amount, &got.CreatedAt)
+				err := scanTransfer(testDbPool.QueryRow(backgroundCtx, "SELECT "+transferColumns+" FROM transfers WHERE id = $1", string(args.transfer.ID)), &got)
 				if err != nil {
 					t.Errorf("Create() error = %v, wantErr %v", err, false)
 				}
@@ -205,6 +211,7 @@ func Test_transferRepository_Fetch(t *testing.T) {
 				return []model.Transfer{
 					{
 						ID:                   model.NewTransferID(),
+						Kind:                 model.TransferKindTransfer,
 						AccountOriginID:      args.accountID,
 						AccountDestinationID: model.NewAccountID(),
 						Amount:               123,
@@ -260,6 +267,7 @@ func Test_transferRepository_Fetch(t *testing.T) {
 				return []model.Transfer{
 					{
 						ID:                   model.NewTransferID(),
+						Kind:                 model.TransferKindTransfer,
 						AccountOriginID:      model.NewAccountID(),
 						AccountDestinationID: args.accountID,
 						Amount:               123,
@@ -315,6 +323,7 @@ func Test_transferRepository_Fetch(t *testing.T) {
 				return []model.Transfer{
 					{
 						ID:                   model.NewTransferID(),
+						Kind:                 model.TransferKindTransfer,
 						AccountOriginID:      model.NewAccountID(),
 						AccountDestinationID: args.accountID,
 						Amount:               123,
@@ -322,6 +331,7 @@ func Test_transferRepository_Fetch(t *testing.T) {
 					},
 					{
 						ID:                   model.NewTransferID(),
+						Kind:                 model.TransferKindTransfer,
 						AccountOriginID:      args.accountID,
 						AccountDestinationID: model.NewAccountID(),
 						Amount:               111,
@@ -413,7 +423,7 @@ func Test_transferRepository_Fetch(t *testing.T) {
 			}
 
 			trfRepo := NewTransferRepository(tt.fields.db)
-			got, err := trfRepo.Fetch(tt.args.ctx, tt.args.accountID)
+			got, err := trfRepo.Fetch(tt.args.ctx, model.TransferFilter{AccountID: tt.args.accountID, Limit: 10})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -425,6 +435,108 @@ func Test_transferRepository_Fetch(t *testing.T) {
 	}
 }
 
+func Test_transferRepository_Fetch_filter(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	accountID, otherID, anotherID := model.NewAccountID(), model.NewAccountID(), model.NewAccountID()
+	for i, id := range []model.AccountID{accountID, otherID, anotherID} {
+		_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret) VALUES ($1, $2, $3, $4)",
+			string(id), "any name", fmt.Sprintf("%011d", i+1), "any secret")
+		if err != nil {
+			t.Fatalf("Fetch() error on runBefore = %v", err)
+		}
+	}
+
+	// newest first: sent to other, received from another, received from other, sent to another, sent to other
+	start := time.Now().Add(-time.Hour).Round(time.Microsecond)
+	transfers := []model.Transfer{
+		{ID: model.NewTransferID(), Kind: model.TransferKindTransfer, AccountOriginID: accountID, AccountDestinationID: otherID, Amount: 500, CreatedAt: start.Add(4 * time.Minute)},
+		{ID: model.NewTransferID(), Kind: model.TransferKindTransfer, AccountOriginID: anotherID, AccountDestinationID: accountID, Amount: 400, CreatedAt: start.Add(3 * time.Minute)},
+		{ID: model.NewTransferID(), Kind: model.TransferKindTransfer, AccountOriginID: otherID, AccountDestinationID: accountID, Amount: 300, CreatedAt: start.Add(2 * time.Minute)},
+		{ID: model.NewTransferID(), Kind: model.TransferKindTransfer, AccountOriginID: accountID, AccountDestinationID: anotherID, Amount: 200, CreatedAt: start.Add(1 * time.Minute)},
+		{ID: model.NewTransferID(), Kind: model.TransferKindTransfer, AccountOriginID: accountID, AccountDestinationID: otherID, Amount: 100, CreatedAt: start},
+	}
+	trfRepo := NewTransferRepository(testDbPool)
+	for _, transfer := range transfers {
+		transfer := transfer
+		if err := trfRepo.Create(backgroundCtx, &transfer); err != nil {
+			t.Fatalf("Fetch() error on runBefore = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter model.TransferFilter
+		want   []model.Transfer
+	}{
+		{
+			name:   "should limit the page",
+			filter: model.TransferFilter{AccountID: accountID, Limit: 2},
+			want:   transfers[:2],
+		},
+		{
+			name:   "should start after the cursor",
+			filter: model.TransferFilter{AccountID: accountID, Limit: 2, After: &model.TransferCursor{CreatedAt: transfers[1].CreatedAt, ID: transfers[1].ID}},
+			want:   transfers[2:4],
+		},
+		{
+			name:   "should filter sent",
+			filter: model.TransferFilter{AccountID: accountID, Direction: model.TransferDirectionSent, Limit: 10},
+			want:   []model.Transfer{transfers[0], transfers[3], transfers[4]},
+		},
+		{
+			name:   "should filter received",
+			filter: model.TransferFilter{AccountID: accountID, Direction: model.TransferDirectionReceived, Limit: 10},
+			want:   transfers[1:3],
+		},
+		{
+			name:   "should filter counterpart",
+			filter: model.TransferFilter{AccountID: accountID, CounterpartID: otherID, Limit: 10},
+			want:   []model.Transfer{transfers[0], transfers[2], transfers[4]},
+		},
+		{
+			name:   "should filter period",
+			filter: model.TransferFilter{AccountID: accountID, CreatedFrom: start.Add(time.Minute), CreatedTo: start.Add(3 * time.Minute), Limit: 10},
+			want:   transfers[2:4],
+		},
+		{
+			name:   "should filter amount range",
+			filter: model.TransferFilter{AccountID: accountID, MinAmount: 200, MaxAmount: 400, Limit: 10},
+			want:   transfers[1:4],
+		},
+		{
+			name: "should combine the filters",
+			filter: model.TransferFilter{
+				AccountID:     accountID,
+				Direction:     model.TransferDirectionSent,
+				CounterpartID: otherID,
+				MaxAmount:     400,
+				After:         &model.TransferCursor{CreatedAt: transfers[0].CreatedAt, ID: transfers[0].ID},
+				Limit:         10,
+			},
+			want: transfers[4:],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := trfRepo.Fetch(backgroundCtx, tt.filter)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Fetch() got %v transfers, want %v", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].ID != tt.want[i].ID || !got[i].CreatedAt.Equal(tt.want[i].CreatedAt) {
+					t.Errorf("Fetch() got[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
 func Test_transferRepository_WithinTransaction(t *testing.T) {
 	backgroundCtx := context.Background()
 
@@ -586,3 +698,319 @@ func Test_transferRepository_WithinTransaction(t *testing.T) {
 		})
 	}
 }
+
+func Test_transferRepository_ConcurrentTransfers(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	const (
+		accountsCount  = 10
+		initialBalance = 1000
+		transfersCount = 500
+	)
+
+	truncateDatabase(t)
+
+	ldgRepo := NewLedgerRepository(testDbPool)
+	accountIDs := make([]model.AccountID, accountsCount)
+	for i := range accountIDs {
+		accountIDs[i] = model.NewAccountID()
+		_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret) VALUES ($1, $2, $3, $4)",
+			string(accountIDs[i]),
+			"any name",
+			fmt.Sprintf("%011d", i+1),
+			"any secret")
+		if err != nil {
+			t.Fatalf("ConcurrentTransfers() error on runBefore = %v", err)
+		}
+
+		err = ldgRepo.Post(backgroundCtx, model.NewLedgerPosting(model.LedgerPostingInitialBalance, "", model.LedgerExternalAccountID, accountIDs[i], initialBalance, model.CurrencyBRL))
+		if err != nil {
+			t.Fatalf("ConcurrentTransfers() error on runBefore = %v", err)
+		}
+	}
+
+	trfUC := usecase.NewTransferUseCase(NewTransferRepository(testDbPool), NewAccountRepository(testDbPool), ldgRepo, NewTransferLimitRepository(testDbPool), NewPixKeyRepository(testDbPool), NewFXQuoteRepository(testDbPool), usecase.TransferLimitPolicy{},
+		usecase.FeePolicy{})
+
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	inputs := make([]usecase.TransferCreateInput, transfersCount)
+	for i := range inputs {
+		origin := random.Intn(accountsCount)
+		destination := (origin + 1 + random.Intn(accountsCount-1)) % accountsCount
+		inputs[i] = usecase.TransferCreateInput{
+			AccountOriginID:      string(accountIDs[origin]),
+			AccountDestinationID: string(accountIDs[destination]),
+			Amount:               usecase.NewAmount(model.Money(1 + random.Intn(300))),
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, transfersCount)
+	for _, input := range inputs {
+		wg.Add(1)
+		go func(input usecase.TransferCreateInput) {
+			defer wg.Done()
+			if _, err := trfUC.Create(backgroundCtx, input); err != nil && err != usecase.ErrAccountCurrentBalanceInsufficient {
+				errs <- err
+			}
+		}(input)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("ConcurrentTransfers() unexpected error = %v", err)
+	}
+
+	var totalBalance, negativeBalances int64
+	err := testDbPool.QueryRow(backgroundCtx, "SELECT SUM(balance), COUNT(id) FILTER (WHERE balance < 0) FROM accounts").
+		Scan(&totalBalance, &negativeBalances)
+	if err != nil {
+		t.Fatalf("ConcurrentTransfers() error = %v", err)
+	}
+	if totalBalance != accountsCount*initialBalance {
+		t.Errorf("ConcurrentTransfers() total balance = %v, want %v", totalBalance, accountsCount*initialBalance)
+	}
+	if negativeBalances != 0 {
+		t.Errorf("ConcurrentTransfers() accounts with negative balance = %v, want 0", negativeBalances)
+	}
+
+	// every account balance must match its initial balance plus the persisted transfers
+	var mismatches int64
+	err = testDbPool.QueryRow(backgroundCtx, `
+		SELECT COUNT(a.id)
+		FROM accounts a
+		WHERE a.balance <> $1
+			+ COALESCE((SELECT SUM(amount) FROM transfers WHERE account_destination_id = a.id), 0)
+			- COALESCE((SELECT SUM(amount) FROM transfers WHERE account_origin_id = a.id), 0)
+	`, initialBalance).Scan(&mismatches)
+	if err != nil {
+		t.Fatalf("ConcurrentTransfers() error = %v", err)
+	}
+	if mismatches != 0 {
+		t.Errorf("ConcurrentTransfers() accounts with balance not matching transfers = %v, want 0", mismatches)
+	}
+
+	// and the ledger must be able to rebuild every balance
+	for _, accountID := range accountIDs {
+		var balance model.Money
+		err = testDbPool.QueryRow(backgroundCtx, "SELECT balance FROM accounts WHERE id = $1", string(accountID)).Scan(&balance)
+		if err != nil {
+			t.Fatalf("ConcurrentTransfers() error = %v", err)
+		}
+
+		ledgerBalance, err := ldgRepo.GetBalance(backgroundCtx, accountID, model.CurrencyBRL)
+		if err != nil {
+			t.Fatalf("ConcurrentTransfers() error = %v", err)
+		}
+		if ledgerBalance != balance {
+			t.Errorf("ConcurrentTransfers() ledger balance = %v, want %v", ledgerBalance, balance)
+		}
+	}
+}
+
+func Test_transferRepository_GetByIDForUpdate_UpdateRefundedAmount(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	originID, destinationID := model.NewAccountID(), model.NewAccountID()
+	for i, id := range []model.AccountID{originID, destinationID} {
+		_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret) VALUES ($1, $2, $3, $4)",
+			string(id), "any name", fmt.Sprintf("%011d", i+1), "any secret")
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	trfRepo := NewTransferRepository(testDbPool)
+
+	original := model.NewTransfer(string(originID), string(destinationID), 1000)
+	original.CreatedAt = original.CreatedAt.Round(time.Microsecond)
+	if err := trfRepo.Create(backgroundCtx, original); err != nil {
+		t.Fatalf("error on setup = %v", err)
+	}
+
+	refund := original.NewRefundOf(model.TransferKindRefund, 400)
+	refund.CreatedAt = refund.CreatedAt.Round(time.Microsecond)
+	if err := trfRepo.Create(backgroundCtx, refund); err != nil {
+		t.Fatalf("Create() refund error = %v", err)
+	}
+
+	original.RefundedAmount = 400
+	if err := trfRepo.UpdateRefundedAmount(backgroundCtx, original); err != nil {
+		t.Fatalf("UpdateRefundedAmount() error = %v", err)
+	}
+
+	got, err := trfRepo.GetByIDForUpdate(backgroundCtx, original.ID)
+	if err != nil {
+		t.Fatalf("GetByIDForUpdate() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, original) {
+		t.Errorf("GetByIDForUpdate() got = %v, want %v", got, original)
+	}
+
+	got, err = trfRepo.GetByIDForUpdate(backgroundCtx, refund.ID)
+	if err != nil {
+		t.Fatalf("GetByIDForUpdate() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, refund) {
+		t.Errorf("GetByIDForUpdate() got = %v, want %v", got, refund)
+	}
+
+	original.RefundedAmount = 1001
+	if err := trfRepo.UpdateRefundedAmount(backgroundCtx, original); err == nil {
+		t.Errorf("UpdateRefundedAmount() should not refund more than the amount")
+	}
+
+	if _, err := trfRepo.GetByIDForUpdate(backgroundCtx, model.NewTransferID()); err != repository.ErrTransferNotFound {
+		t.Errorf("GetByIDForUpdate() error = %v, wantErr %v", err, repository.ErrTransferNotFound)
+	}
+
+	if err := trfRepo.UpdateRefundedAmount(backgroundCtx, &model.Transfer{ID: model.NewTransferID()}); err != repository.ErrTransferNotFound {
+		t.Errorf("UpdateRefundedAmount() error = %v, wantErr %v", err, repository.ErrTransferNotFound)
+	}
+}
+
+func Test_transferRepository_GetSentTotals(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	originID, destinationID := model.NewAccountID(), model.NewAccountID()
+	for i, id := range []model.AccountID{originID, destinationID} {
+		_, err := testDbPool.Exec(backgroundCtx, "INSERT INTO accounts (id, name, cpf, secret) VALUES ($1, $2, $3, $4)",
+			string(id), "any name", fmt.Sprintf("%011d", i+1), "any secret")
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	trfRepo := NewTransferRepository(testDbPool)
+
+	now := time.Now()
+	periods := model.TransferLimitPeriods{
+		DayStart:   now.Add(-2 * time.Hour),
+		MonthStart: now.Add(-48 * time.Hour),
+		NightStart: now.Add(-time.Hour),
+	}
+	sent := []struct {
+		from      model.AccountID
+		to        model.AccountID
+		amount    model.Money
+		createdAt time.Time
+	}{
+		{originID, destinationID, 100, now.Add(-72 * time.Hour)},   // before the month
+		{originID, destinationID, 200, now.Add(-24 * time.Hour)},   // in the month
+		{originID, destinationID, 300, now.Add(-90 * time.Minute)}, // in the day
+		{originID, destinationID, 400, now.Add(-30 * time.Minute)}, // in the night
+		{destinationID, originID, 500, now.Add(-30 * time.Minute)}, // received
+	}
+	for _, s := range sent {
+		transfer := model.NewTransfer(string(s.from), string(s.to), s.amount)
+		transfer.CreatedAt = s.createdAt
+		if err := trfRepo.Create(backgroundCtx, transfer); err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+		if s.from == destinationID {
+			// the refund of a received transfer is sent by the account, but it's not counted
+			refund := transfer.NewRefundOf(model.TransferKindRefund, 150)
+			if err := trfRepo.Create(backgroundCtx, refund); err != nil {
+				t.Fatalf("error on setup = %v", err)
+			}
+		}
+	}
+
+	// the cash withdrawals are counted like the transfers sent
+	cashRepo := NewCashRepository(testDbPool)
+	withdrawals := []struct {
+		accountID model.AccountID
+		amount    model.Money
+		createdAt time.Time
+	}{
+		{originID, 1000, now.Add(-72 * time.Hour)},  // before the month
+		{originID, 50, now.Add(-10 * time.Minute)},  // in the night
+		{destinationID, 600, now.Add(-time.Minute)}, // another account
+	}
+	for _, w := range withdrawals {
+		withdrawal := model.NewWithdrawal(w.accountID, w.amount)
+		withdrawal.CreatedAt = w.createdAt
+		if err := cashRepo.CreateWithdrawal(backgroundCtx, withdrawal); err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	got, err := trfRepo.GetSentTotals(backgroundCtx, originID, periods)
+	if err != nil {
+		t.Fatalf("GetSentTotals() error = %v", err)
+	}
+	want := &model.TransferTotals{Daily: 750, Monthly: 950, Night: 450}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSentTotals() got = %v, want %v", got, want)
+	}
+
+	periods.NightStart = time.Time{}
+	got, err = trfRepo.GetSentTotals(backgroundCtx, originID, periods)
+	if err != nil {
+		t.Fatalf("GetSentTotals() error = %v", err)
+	}
+	want = &model.TransferTotals{Daily: 750, Monthly: 950}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSentTotals() by day got = %v, want %v", got, want)
+	}
+}
+
+func Test_transferRepository_CountSent(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	truncateDatabase(t)
+
+	originID, destinationID := model.NewAccountID(), model.NewAccountID()
+	insertTestAccount(t, originID, "00000000001", 0)
+	insertTestAccount(t, destinationID, "00000000002", 0)
+
+	trfRepo := NewTransferRepository(testDbPool)
+
+	now := time.Now()
+	sent := []struct {
+		from      model.AccountID
+		to        model.AccountID
+		createdAt time.Time
+	}{
+		{originID, destinationID, now.Add(-72 * time.Hour)}, // before the month
+		{originID, destinationID, now.Add(-24 * time.Hour)},
+		{originID, destinationID, now.Add(-time.Hour)},
+		{destinationID, originID, now.Add(-time.Hour)}, // received
+	}
+	for _, s := range sent {
+		transfer := model.NewTransfer(string(s.from), string(s.to), 100)
+		transfer.CreatedAt = s.createdAt
+		transfer.Fee = 10
+		if err := trfRepo.Create(backgroundCtx, transfer); err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+		if s.from == destinationID {
+			// the refund of a received transfer is sent by the account, but it's not counted
+			refund := transfer.NewRefundOf(model.TransferKindRefund, 50)
+			if err := trfRepo.Create(backgroundCtx, refund); err != nil {
+				t.Fatalf("error on setup = %v", err)
+			}
+		}
+	}
+
+	got, err := trfRepo.CountSent(backgroundCtx, originID, now.Add(-48*time.Hour))
+	if err != nil {
+		t.Fatalf("CountSent() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("CountSent() got = %v, want %v", got, 2)
+	}
+
+	transfers, err := trfRepo.Fetch(backgroundCtx, model.TransferFilter{AccountID: originID, Direction: model.TransferDirectionSent, Limit: 1})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(transfers) != 1 || transfers[0].Fee != 10 {
+		t.Errorf("Fetch() got = %v, want the fee saved", transfers)
+	}
+}
@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type loginAttemptRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewLoginAttemptRepository instantiates a new login attempt redis repository.
+// The keys are stored by their SHA-256 hash, as they may contain CPFs.
+func NewLoginAttemptRepository(client *redis.Client) repository.LoginAttemptRepository {
+	return &loginAttemptRepository{client, "_LOGIN_ATTEMPTS_"}
+}
+
+func (attRepo loginAttemptRepository) Get(ctx context.Context, key string) (*model.LoginAttempts, error) {
+	values, err := attRepo.client.WithContext(ctx).HGetAll(attRepo.prefix + hashToken(key)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := model.LoginAttempts{}
+	if failures, ok := values["failures"]; ok {
+		attempts.Failures, err = strconv.Atoi(failures)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if lockedUntil, ok := values["locked_until"]; ok {
+		lockedUntilNano, err := strconv.ParseInt(lockedUntil, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		attempts.LockedUntil = time.Unix(0, lockedUntilNano)
+	}
+
+	return &attempts, nil
+}
+
+func (attRepo loginAttemptRepository) RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	redisKey := attRepo.prefix + hashToken(key)
+
+	var failures *redis.IntCmd
+	_, err := attRepo.client.WithContext(ctx).TxPipelined(func(pipe redis.Pipeliner) error {
+		failures = pipe.HIncrBy(redisKey, "failures", 1)
+		pipe.Expire(redisKey, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(failures.Val()), nil
+}
+
+func (attRepo loginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	redisKey := attRepo.prefix + hashToken(key)
+	client := attRepo.client.WithContext(ctx)
+
+	err := client.HSet(redisKey, "locked_until", until.UnixNano()).Err()
+	if err != nil {
+		return err
+	}
+
+	// the lock must not be forgotten before it ends
+	ttl, err := client.TTL(redisKey).Result()
+	if err != nil {
+		return err
+	}
+	if ttl < time.Until(until) {
+		return client.ExpireAt(redisKey, until).Err()
+	}
+
+	return nil
+}
+
+func (attRepo loginAttemptRepository) Reset(ctx context.Context, key string) error {
+	return attRepo.client.WithContext(ctx).Del(attRepo.prefix + hashToken(key)).Err()
+}
@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_loginAttemptRepository(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	attRepo := NewLoginAttemptRepository(testRedisClient)
+
+	got, err := attRepo.Get(backgroundCtx, "cpf:34363916206")
+	if err != nil || got.Failures != 0 || !got.LockedUntil.IsZero() {
+		t.Fatalf("Get() empty = %+v, error = %v, want zero attempts", got, err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		failures, err := attRepo.RegisterFailure(backgroundCtx, "cpf:34363916206", 10*time.Second)
+		if err != nil || failures != i {
+			t.Fatalf("RegisterFailure() = %v, error = %v, want %v", failures, err, i)
+		}
+	}
+
+	lockedUntil := time.Now().Add(time.Minute)
+	if err = attRepo.Lock(backgroundCtx, "cpf:34363916206", lockedUntil); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	got, err = attRepo.Get(backgroundCtx, "cpf:34363916206")
+	if err != nil || got.Failures != 3 || !got.LockedUntil.Equal(lockedUntil) {
+		t.Errorf("Get() = %+v, error = %v, want 3 failures locked until %v", got, err, lockedUntil)
+	}
+
+	if keys := testRedisClient.Keys("_LOGIN_ATTEMPTS_cpf:*").Val(); len(keys) != 0 {
+		t.Errorf("the keys should not be stored in plain text, got %v", keys)
+	}
+
+	// the lock must outlive the failures window
+	if ttl := testRedisClient.TTL("_LOGIN_ATTEMPTS_" + hashToken("cpf:34363916206")).Val(); ttl <= 10*time.Second {
+		t.Errorf("Lock() ttl = %v, want more than the failures window", ttl)
+	}
+
+	if err = attRepo.Reset(backgroundCtx, "cpf:34363916206"); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	got, _ = attRepo.Get(backgroundCtx, "cpf:34363916206")
+	if got.Failures != 0 {
+		t.Errorf("Get() after reset = %+v, want zero attempts", got)
+	}
+}
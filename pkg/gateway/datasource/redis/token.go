@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type tokenRepository struct {
+	client                    *redis.Client
+	refreshTokenPrefix        string
+	usedRefreshTokenPrefix    string
+	revokedTokenFamilyPrefix  string
+	deniedAccessTokenIDPrefix string
+}
+
+type refreshTokenValue struct {
+	FamilyID  model.RefreshTokenFamilyID `json:"family_id"`
+	AccountID model.AccountID            `json:"account_id"`
+	ExpiresAt time.Time                  `json:"expires_at"`
+}
+
+// NewTokenRepository instantiates a new auth token redis repository.
+// Refresh tokens are stored by their SHA-256 hash, so a leaked dump can't be used to issue access tokens.
+func NewTokenRepository(client *redis.Client) repository.TokenRepository {
+	return &tokenRepository{
+		client:                    client,
+		refreshTokenPrefix:        "_REFRESH_TOKEN_",
+		usedRefreshTokenPrefix:    "_REFRESH_TOKEN_USED_",
+		revokedTokenFamilyPrefix:  "_REFRESH_TOKEN_FAMILY_REVOKED_",
+		deniedAccessTokenIDPrefix: "_ACCESS_TOKEN_DENIED_",
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (tknRepo tokenRepository) CreateRefreshToken(ctx context.Context, refreshToken *model.RefreshToken) error {
+	value, err := json.Marshal(refreshTokenValue{
+		FamilyID:  refreshToken.FamilyID,
+		AccountID: refreshToken.AccountID,
+		ExpiresAt: refreshToken.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(refreshToken.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	key := tknRepo.refreshTokenPrefix + hashToken(refreshToken.Token)
+	return tknRepo.client.WithContext(ctx).Set(key, value, ttl).Err()
+}
+
+func (tknRepo tokenRepository) GetRefreshToken(ctx context.Context, token string) (*model.RefreshToken, error) {
+	data, err := tknRepo.client.WithContext(ctx).Get(tknRepo.refreshTokenPrefix + hashToken(token)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, repository.ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+
+	var value refreshTokenValue
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	return &model.RefreshToken{
+		Token:     token,
+		FamilyID:  value.FamilyID,
+		AccountID: value.AccountID,
+		ExpiresAt: value.ExpiresAt,
+	}, nil
+}
+
+func (tknRepo tokenRepository) UseRefreshToken(ctx context.Context, refreshToken *model.RefreshToken) (bool, error) {
+	// a non-positive expiration would make the key live forever
+	ttl := time.Until(refreshToken.ExpiresAt)
+	if ttl <= 0 {
+		return false, repository.ErrRefreshTokenNotFound
+	}
+
+	key := tknRepo.usedRefreshTokenPrefix + hashToken(refreshToken.Token)
+	return tknRepo.client.WithContext(ctx).SetNX(key, time.Now().Unix(), ttl).Result()
+}
+
+func (tknRepo tokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID model.RefreshTokenFamilyID, duration time.Duration) error {
+	return tknRepo.client.WithContext(ctx).Set(tknRepo.revokedTokenFamilyPrefix+string(familyID), time.Now().Unix(), duration).Err()
+}
+
+func (tknRepo tokenRepository) IsRefreshTokenFamilyRevoked(ctx context.Context, familyID model.RefreshTokenFamilyID) (bool, error) {
+	return tknRepo.exists(ctx, tknRepo.revokedTokenFamilyPrefix+string(familyID))
+}
+
+func (tknRepo tokenRepository) DenyAccessToken(ctx context.Context, tokenID string, duration time.Duration) error {
+	return tknRepo.client.WithContext(ctx).Set(tknRepo.deniedAccessTokenIDPrefix+tokenID, time.Now().Unix(), duration).Err()
+}
+
+func (tknRepo tokenRepository) IsAccessTokenDenied(ctx context.Context, tokenID string) (bool, error) {
+	return tknRepo.exists(ctx, tknRepo.deniedAccessTokenIDPrefix+tokenID)
+}
+
+func (tknRepo tokenRepository) exists(ctx context.Context, key string) (bool, error) {
+	count, err := tknRepo.client.WithContext(ctx).Exists(key).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,170 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+func Test_tokenRepository_GetRefreshToken(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	type fields struct {
+		client *redis.Client
+	}
+	type args struct {
+		ctx   context.Context
+		token string
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		want      *model.RefreshToken
+		wantErr   error
+		runBefore func(args)
+	}{
+		{
+			name: "not found should return ErrRefreshTokenNotFound",
+			fields: fields{
+				client: testRedisClient,
+			},
+			args: args{
+				ctx:   backgroundCtx,
+				token: "refresh-token-1",
+			},
+			want:    nil,
+			wantErr: repository.ErrRefreshTokenNotFound,
+		},
+		{
+			name: "should success",
+			fields: fields{
+				client: testRedisClient,
+			},
+			args: args{
+				ctx:   backgroundCtx,
+				token: "refresh-token-2",
+			},
+			runBefore: func(args args) {
+				err := NewTokenRepository(testRedisClient).CreateRefreshToken(backgroundCtx, &model.RefreshToken{
+					Token:     args.token,
+					FamilyID:  "family-2",
+					AccountID: "uuid-2",
+					ExpiresAt: time.Date(2999, time.January, 1, 0, 0, 0, 0, time.UTC),
+				})
+				if err != nil {
+					t.Errorf("CreateRefreshToken() error = %v", err)
+				}
+			},
+			want: &model.RefreshToken{
+				Token:     "refresh-token-2",
+				FamilyID:  "family-2",
+				AccountID: "uuid-2",
+				ExpiresAt: time.Date(2999, time.January, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.runBefore != nil {
+				tt.runBefore(tt.args)
+			}
+
+			tknRepo := NewTokenRepository(tt.fields.client)
+
+			got, err := tknRepo.GetRefreshToken(tt.args.ctx, tt.args.token)
+			if err != tt.wantErr {
+				t.Errorf("GetRefreshToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.want == nil {
+				return
+			}
+			if got.Token != tt.want.Token || got.FamilyID != tt.want.FamilyID || got.AccountID != tt.want.AccountID || !got.ExpiresAt.Equal(tt.want.ExpiresAt) {
+				t.Errorf("GetRefreshToken() got = %v, want %v", got, tt.want)
+			}
+
+			if testRedisClient.Exists("_REFRESH_TOKEN_"+tt.args.token).Val() != 0 {
+				t.Errorf("CreateRefreshToken() should not store the plain token")
+			}
+		})
+	}
+}
+
+func Test_tokenRepository_UseRefreshToken(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	tknRepo := NewTokenRepository(testRedisClient)
+	refreshToken := &model.RefreshToken{
+		Token:     "refresh-token-3",
+		FamilyID:  "family-3",
+		AccountID: "uuid-3",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	first, err := tknRepo.UseRefreshToken(backgroundCtx, refreshToken)
+	if err != nil || !first {
+		t.Errorf("UseRefreshToken() first use = %v, error = %v, want true", first, err)
+	}
+
+	second, err := tknRepo.UseRefreshToken(backgroundCtx, refreshToken)
+	if err != nil || second {
+		t.Errorf("UseRefreshToken() second use = %v, error = %v, want false", second, err)
+	}
+
+	refreshToken.ExpiresAt = time.Now().Add(-time.Minute)
+	if _, err = tknRepo.UseRefreshToken(backgroundCtx, refreshToken); err != repository.ErrRefreshTokenNotFound {
+		t.Errorf("UseRefreshToken() expired error = %v, wantErr %v", err, repository.ErrRefreshTokenNotFound)
+	}
+}
+
+func Test_tokenRepository_RevokeRefreshTokenFamily(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	tknRepo := NewTokenRepository(testRedisClient)
+
+	revoked, err := tknRepo.IsRefreshTokenFamilyRevoked(backgroundCtx, "family-4")
+	if err != nil || revoked {
+		t.Errorf("IsRefreshTokenFamilyRevoked() = %v, error = %v, want false", revoked, err)
+	}
+
+	if err = tknRepo.RevokeRefreshTokenFamily(backgroundCtx, "family-4", 10*time.Second); err != nil {
+		t.Errorf("RevokeRefreshTokenFamily() error = %v", err)
+	}
+
+	revoked, err = tknRepo.IsRefreshTokenFamilyRevoked(backgroundCtx, "family-4")
+	if err != nil || !revoked {
+		t.Errorf("IsRefreshTokenFamilyRevoked() = %v, error = %v, want true", revoked, err)
+	}
+}
+
+func Test_tokenRepository_DenyAccessToken(t *testing.T) {
+	backgroundCtx := context.Background()
+
+	tknRepo := NewTokenRepository(testRedisClient)
+
+	denied, err := tknRepo.IsAccessTokenDenied(backgroundCtx, "jti-5")
+	if err != nil || denied {
+		t.Errorf("IsAccessTokenDenied() = %v, error = %v, want false", denied, err)
+	}
+
+	if err = tknRepo.DenyAccessToken(backgroundCtx, "jti-5", 10*time.Second); err != nil {
+		t.Errorf("DenyAccessToken() error = %v", err)
+	}
+
+	denied, err = tknRepo.IsAccessTokenDenied(backgroundCtx, "jti-5")
+	if err != nil || !denied {
+		t.Errorf("IsAccessTokenDenied() = %v, error = %v, want true", denied, err)
+	}
+
+	ttl := testRedisClient.TTL("_ACCESS_TOKEN_DENIED_jti-5").Val()
+	if ttl <= 0 || ttl > 10*time.Second {
+		t.Errorf("DenyAccessToken() ttl = %v, want up to %v", ttl, 10*time.Second)
+	}
+}
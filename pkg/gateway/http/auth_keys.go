@@ -0,0 +1,38 @@
+package http
+
+import (
+	"os"
+	"sort"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+)
+
+// newAuthKeySet loads the legacy HS256 secret and the PEM key files from the configuration.
+func newAuthKeySet(authConf config.ConfAuth) (*usecase.AuthKeySet, error) {
+	var keys []*usecase.AuthKey
+	if !authConf.HS256Disabled {
+		keys = append(keys, usecase.NewHMACAuthKey(authConf.SecretKey))
+	}
+
+	keyIDs := make([]string, 0, len(authConf.KeyFiles))
+	for keyID := range authConf.KeyFiles {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Strings(keyIDs)
+
+	for _, keyID := range keyIDs {
+		pemBytes, err := os.ReadFile(authConf.KeyFiles[keyID])
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := usecase.NewAuthKeyFromPEM(keyID, pemBytes)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return usecase.NewAuthKeySet(authConf.SigningKeyID, keys...)
+}
@@ -1,12 +1,17 @@
 package controller
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/hlog"
 
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
@@ -18,6 +23,12 @@ type AccountController interface {
 	Create(w http.ResponseWriter, r *http.Request)
 	Fetch(w http.ResponseWriter, r *http.Request)
 	GetBalance(w http.ResponseWriter, r *http.Request)
+	GetStatement(w http.ResponseWriter, r *http.Request)
+	Block(w http.ResponseWriter, r *http.Request)
+	Unblock(w http.ResponseWriter, r *http.Request)
+	Close(w http.ResponseWriter, r *http.Request)
+	SetCreditLimit(w http.ResponseWriter, r *http.Request)
+	SetTier(w http.ResponseWriter, r *http.Request)
 }
 
 type accountController struct {
@@ -49,6 +60,10 @@ func (accCtrl accountController) Create(w http.ResponseWriter, r *http.Request)
 	var input usecase.AccountCreateInput
 	if err := io.ReadInput(r, logger, &input); err != nil {
 		logger.Error().Stack().Err(err).Msg("error decoding account create input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
 		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
 		return
 	}
@@ -59,62 +74,383 @@ func (accCtrl accountController) Create(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	io.WriteSuccess(w, logger, http.StatusCreated, result)
+	io.WriteSuccess(w, r, logger, http.StatusCreated, result)
 }
 
 // @Summary Fetch accounts
-// @Description Fetch all the accounts
+// @Description Fetch a page of the accounts. Only operators and admins (`accounts:read` scope) get the full CPF and the balance, the others get the CPF masked and no balance.
+// @Description To get the next page, send the `next_cursor` of the previous one as `cursor` with the same sort and search.
+// @Description `name` matches the names starting with it or similar to it. Only operators and admins can search by `cpf`.
 // @tags Accounts
 // @Produce json
-// @Success 200 {object} []usecase.AccountFetchOutput
+// @Security Access token
+// @Param cursor query string false "Cursor of the page"
+// @Param limit query int false "Page size, up to 100" default(20)
+// @Param sort query string false "Sort field, prefixed with - for descending order" Enums(created_at, -created_at, name, -name) default(created_at)
+// @Param name query string false "Name search" example(bart)
+// @Param cpf query string false "CPF search" example(999.999.999-99)
+// @Success 200 {object} usecase.AccountFetchPageOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
 // @failure 500 {object} io.ErrorOutput
 // @Router /accounts [get]
 func (accCtrl accountController) Fetch(w http.ResponseWriter, r *http.Request) {
 	logger := hlog.FromRequest(r)
 
-	result, err := accCtrl.accUC.Fetch(logger.WithContext(r.Context()))
+	caller, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		accCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	input, err := readAccountFetchInput(r.URL.Query())
+	if err != nil {
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := accCtrl.accUC.Fetch(logger.WithContext(r.Context()), caller, input)
 	if err != nil {
 		accCtrl.writeError(w, logger, http.StatusInternalServerError, err)
 		return
 	}
 
-	io.WriteSuccess(w, logger, http.StatusOK, result)
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+func readAccountFetchInput(query url.Values) (usecase.AccountFetchInput, error) {
+	input := usecase.AccountFetchInput{
+		Cursor: query.Get("cursor"),
+		Sort:   query.Get("sort"),
+		Name:   query.Get("name"),
+		CPF:    query.Get("cpf"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		input.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return input, usecase.ErrAccountFetchLimitInvalid
+		}
+	}
+
+	return input, nil
 }
 
 // @Summary Get account balance
-// @Description Get the balance of an account
+// @Description Get the balance of an account. Only the account owner can get it.
+// @Description The `balance` is negative when the account is overdrawn and the `available_balance` is how much it can spend, including its `credit_limit`.
 // @tags Accounts
 // @Accept json
 // @Produce json
+// @Security Access token
 // @Param id path string true "Account ID"
 // @Success 200 {object} usecase.AccountBalanceOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
 // @failure 404 {object} io.ErrorOutput
 // @failure 500 {object} io.ErrorOutput
-// @Router /account/{id}/balance [get]
+// @Router /accounts/{id}/balance [get]
 func (accCtrl accountController) GetBalance(w http.ResponseWriter, r *http.Request) {
 	logger := hlog.FromRequest(r)
 
+	caller, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		accCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
 	params := httprouter.ParamsFromContext(r.Context())
 
-	result, err := accCtrl.accUC.GetBalance(r.Context(), model.AccountID(params.ByName("id")))
+	result, err := accCtrl.accUC.GetBalance(logger.WithContext(r.Context()), caller, model.AccountID(params.ByName("id")))
+	if err != nil {
+		accCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Get account statement
+// @Description Get the account movements in the period, with the opening balance, the balance after each movement and the closing balance. Only the account owner can get it.
+// @Description `from` and `to` accept RFC 3339 timestamps or UTC dates (YYYY-MM-DD). The period includes `from` and excludes `to`, except for dates, which include the whole `to` day. By default, it's the last 30 days.
+// @tags Accounts
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param id path string true "Account ID"
+// @Param from query string false "Period start" example(2020-12-01)
+// @Param to query string false "Period end" example(2020-12-31)
+// @Success 200 {object} usecase.AccountStatementOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /accounts/{id}/statement [get]
+func (accCtrl accountController) GetStatement(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	caller, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		accCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := parsePeriodTime(query.Get("from"), false)
+	if err != nil {
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, errPeriodTimeInvalid.Error())
+		return
+	}
+	to, err := parsePeriodTime(query.Get("to"), true)
+	if err != nil {
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, errPeriodTimeInvalid.Error())
+		return
+	}
+
+	input := usecase.AccountStatementInput{
+		AccountID: model.AccountID(httprouter.ParamsFromContext(r.Context()).ByName("id")),
+		From:      from,
+		To:        to,
+	}
+
+	result, err := accCtrl.accUC.GetStatement(logger.WithContext(r.Context()), caller, input)
+	if err != nil {
+		accCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Block account
+// @Description Blocks an active account, so it can't send nor receive transfers until it's unblocked. Only admins (`accounts:write` scope) can block accounts.
+// @tags Accounts
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param id path string true "Account ID"
+// @Param input body usecase.AccountStatusInput true "Reason"
+// @Success 200 {object} usecase.AccountStatusOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /accounts/{id}/block [post]
+func (accCtrl accountController) Block(w http.ResponseWriter, r *http.Request) {
+	accCtrl.changeStatus(w, r, accCtrl.accUC.Block)
+}
+
+// @Summary Unblock account
+// @Description Makes a blocked account active again. Only admins (`accounts:write` scope) can unblock accounts.
+// @tags Accounts
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param id path string true "Account ID"
+// @Param input body usecase.AccountStatusInput true "Reason"
+// @Success 200 {object} usecase.AccountStatusOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /accounts/{id}/unblock [post]
+func (accCtrl accountController) Unblock(w http.ResponseWriter, r *http.Request) {
+	accCtrl.changeStatus(w, r, accCtrl.accUC.Unblock)
+}
+
+type accountChangeStatusFunc func(ctx context.Context, caller model.Principal, statusInput usecase.AccountStatusInput) (*usecase.AccountStatusOutput, error)
+
+func (accCtrl accountController) changeStatus(w http.ResponseWriter, r *http.Request, changeStatus accountChangeStatusFunc) {
+	logger := hlog.FromRequest(r)
+
+	caller, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		accCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.AccountStatusInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding account status input")
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.AccountID = httprouter.ParamsFromContext(r.Context()).ByName("id")
+
+	result, err := changeStatus(logger.WithContext(r.Context()), caller, input)
+	if err != nil {
+		accCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Close account
+// @Description Closes an account for good. The balance must be zero, unless `sweep_account_id` is informed: then the remaining balance is transferred to that active account. Only admins (`accounts:write` scope) can close accounts.
+// @tags Accounts
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param id path string true "Account ID"
+// @Param input body usecase.AccountCloseInput true "Reason and sweep account"
+// @Success 200 {object} usecase.AccountStatusOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /accounts/{id}/close [post]
+func (accCtrl accountController) Close(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	caller, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		accCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.AccountCloseInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding account close input")
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.AccountID = httprouter.ParamsFromContext(r.Context()).ByName("id")
+
+	result, err := accCtrl.accUC.Close(logger.WithContext(r.Context()), caller, input)
+	if err != nil {
+		accCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Set account credit limit
+// @Description Sets how far below zero the account balance can go, its overdraft. A limit lower than what the account already owes only stops new debits. Only operators and admins (`accounts:credit` scope) can set it.
+// @tags Accounts
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param id path string true "Account ID"
+// @Param input body usecase.AccountCreditLimitInput true "Credit limit"
+// @Success 200 {object} usecase.AccountBalanceOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /accounts/{id}/credit-limit [put]
+func (accCtrl accountController) SetCreditLimit(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	caller, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		accCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.AccountCreditLimitInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding account credit limit input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.AccountID = httprouter.ParamsFromContext(r.Context()).ByName("id")
+
+	result, err := accCtrl.accUC.SetCreditLimit(logger.WithContext(r.Context()), caller, input)
+	if err != nil {
+		accCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Set account tier
+// @Description Sets the tier of the account, which tells the fees it's exempt from from then on. Only admins (`accounts:write` scope) can set it.
+// @tags Accounts
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param id path string true "Account ID"
+// @Param input body usecase.AccountTierInput true "Tier"
+// @Success 200 {object} usecase.AccountTierOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /accounts/{id}/tier [put]
+func (accCtrl accountController) SetTier(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	caller, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		accCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.AccountTierInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding account tier input")
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.AccountID = httprouter.ParamsFromContext(r.Context()).ByName("id")
+
+	result, err := accCtrl.accUC.SetTier(logger.WithContext(r.Context()), caller, input)
 	if err != nil {
 		accCtrl.writeError(w, logger, http.StatusInternalServerError, err)
 		return
 	}
 
-	io.WriteSuccess(w, logger, http.StatusOK, result)
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
 }
 
 func (accCtrl accountController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
 	switch err {
 	case repository.ErrAccountNotFound:
 		statusCode = http.StatusNotFound
-	case usecase.ErrAccountCPFAlreadyExists:
+	case usecase.ErrAccountCPFAlreadyExists,
+		usecase.ErrAccountStatusChangeInvalid,
+		usecase.ErrAccountCreditLimitClosed:
 		statusCode = http.StatusConflict
+	case usecase.ErrAccountCloseBalanceNotZero,
+		usecase.ErrAccountCloseBalanceNegative,
+		usecase.ErrAccountSweepAccountInvalid:
+		statusCode = http.StatusUnprocessableEntity
+	case usecase.ErrAuthInvalidAccessToken:
+		statusCode = http.StatusUnauthorized
+	case usecase.ErrAuthForbidden:
+		statusCode = http.StatusForbidden
 	case usecase.ErrAccountNameWrongLength,
 		usecase.ErrAccountSecretWrongLength,
 		usecase.ErrAccountBalanceNegative,
-		usecase.ErrAccountCPFInvalid:
+		usecase.ErrAccountCPFInvalid,
+		usecase.ErrAccountStatusReasonRequired,
+		usecase.ErrAccountStatementPeriodInvalid,
+		usecase.ErrAccountFetchCursorInvalid,
+		usecase.ErrAccountFetchLimitInvalid,
+		usecase.ErrAccountFetchSortInvalid,
+		usecase.ErrAccountCreditLimitNegative,
+		usecase.ErrAccountTierInvalid,
+		usecase.ErrAmountInvalid:
 		statusCode = http.StatusBadRequest
 	}
 
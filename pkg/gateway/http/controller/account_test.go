@@ -10,8 +10,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/julienschmidt/httprouter"
 	"github.com/kinbiko/jsonassert"
 
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
@@ -46,7 +48,8 @@ func Test_accountController_Create(t *testing.T) {
 							ID:        "uuid-1",
 							Name:      "Bart Simpson",
 							CPF:       "123.456.789-11",
-							Balance:   0,
+							Currency:  "BRL",
+							Balance:   usecase.NewAmount(0),
 							CreatedAt: time.Time{},
 						}
 
@@ -61,7 +64,7 @@ func Test_accountController_Create(t *testing.T) {
 				}(),
 			},
 			wantStatus: 201,
-			want:       `{"id": "uuid-1", "name": "Bart Simpson", "cpf": "123.456.789-11", "balance": 0, "created_at": "<<PRESENCE>>"}`,
+			want:       `{"id": "uuid-1", "name": "Bart Simpson", "cpf": "123.456.789-11", "currency": "BRL", "balance": 0, "created_at": "<<PRESENCE>>"}`,
 		},
 		{
 			name: "successful maximum input",
@@ -72,7 +75,8 @@ func Test_accountController_Create(t *testing.T) {
 							ID:        "uuid-1",
 							Name:      "Bart Simpson",
 							CPF:       "123.456.789-11",
-							Balance:   5.96,
+							Currency:  "BRL",
+							Balance:   usecase.NewAmount(596),
 							CreatedAt: time.Time{},
 						}
 
@@ -87,7 +91,7 @@ func Test_accountController_Create(t *testing.T) {
 				}(),
 			},
 			wantStatus: 201,
-			want:       `{"id": "uuid-1", "name": "Bart Simpson", "cpf": "123.456.789-11", "balance": 5.96, "created_at": "<<PRESENCE>>"}`,
+			want:       `{"id": "uuid-1", "name": "Bart Simpson", "cpf": "123.456.789-11", "currency": "BRL", "balance": 5.96, "created_at": "<<PRESENCE>>"}`,
 		},
 		{
 			name: "should return 500 when usecase error",
@@ -258,53 +262,55 @@ func Test_accountController_Fetch(t *testing.T) {
 			name: "successful empty result",
 			fields: fields{
 				accUC: mock.AccountUseCase{
-					OnFetch: func(ctx context.Context) ([]usecase.AccountFetchOutput, error) {
-						return []usecase.AccountFetchOutput{}, nil
+					OnFetch: func(ctx context.Context, caller model.Principal, fetchInput usecase.AccountFetchInput) (*usecase.AccountFetchPageOutput, error) {
+						return &usecase.AccountFetchPageOutput{Accounts: []usecase.AccountFetchOutput{}}, nil
 					},
 				},
 			},
 			args: args{
 				w: httptest.NewRecorder(),
 				r: func() *http.Request {
-					return httptest.NewRequest(http.MethodGet, "/accounts", nil)
+					req := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 200,
-			want:       `[]`,
+			want:       `{"accounts": [], "next_cursor": null}`,
 		},
 		{
 			name: "successful one result",
 			fields: fields{
 				accUC: mock.AccountUseCase{
-					OnFetch: func(ctx context.Context) ([]usecase.AccountFetchOutput, error) {
-						return []usecase.AccountFetchOutput{
+					OnFetch: func(ctx context.Context, caller model.Principal, fetchInput usecase.AccountFetchInput) (*usecase.AccountFetchPageOutput, error) {
+						return &usecase.AccountFetchPageOutput{Accounts: []usecase.AccountFetchOutput{
 							{
-								AccountCreateOutput: usecase.AccountCreateOutput{
-									ID:        "uuid-1",
-									Name:      "Bart Simpson",
-									CPF:       "123.456.789-11",
-									Balance:   0,
-									CreatedAt: time.Time{},
-								},
+								ID:        "uuid-1",
+								Name:      "Bart Simpson",
+								CPF:       "***.456.789-**",
+								Currency:  "BRL",
+								CreatedAt: time.Time{},
 							},
-						}, nil
+						}}, nil
 					},
 				},
 			},
 			args: args{
 				w: httptest.NewRecorder(),
 				r: func() *http.Request {
-					return httptest.NewRequest(http.MethodGet, "/accounts", nil)
+					req := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 200,
-			want:       `[{"id": "uuid-1", "name": "Bart Simpson", "cpf": "123.456.789-11", "balance": 0, "created_at": "<<PRESENCE>>"}]`,
+			want:       `{"accounts": [{"id": "uuid-1", "name": "Bart Simpson", "cpf": "***.456.789-**", "currency": "BRL", "created_at": "<<PRESENCE>>"}], "next_cursor": null}`,
 		},
 		{
 			name: "should return 500 when usecase error",
 			fields: fields{
 				accUC: mock.AccountUseCase{
-					OnFetch: func(ctx context.Context) ([]usecase.AccountFetchOutput, error) {
+					OnFetch: func(ctx context.Context, caller model.Principal, fetchInput usecase.AccountFetchInput) (*usecase.AccountFetchPageOutput, error) {
 						return nil, errors.New("any error")
 					},
 				},
@@ -312,12 +318,147 @@ func Test_accountController_Fetch(t *testing.T) {
 			args: args{
 				w: httptest.NewRecorder(),
 				r: func() *http.Request {
-					return httptest.NewRequest(http.MethodGet, "/accounts", nil)
+					req := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 500,
 			want:       `{"code": 500, "message": "any error"}`,
 		},
+		{
+			name: "should pass the authenticated principal to the usecase",
+			fields: fields{
+				accUC: mock.AccountUseCase{
+					OnFetch: func(ctx context.Context, caller model.Principal, fetchInput usecase.AccountFetchInput) (*usecase.AccountFetchPageOutput, error) {
+						if caller.AccountID != "uuid-1" || !caller.HasScope(model.ScopeAccountsRead) {
+							return nil, errors.New("unexpected caller")
+						}
+						balance := usecase.NewAmount(1059)
+						return &usecase.AccountFetchPageOutput{Accounts: []usecase.AccountFetchOutput{
+							{
+								ID:        "uuid-2",
+								Name:      "Lisa Simpson",
+								CPF:       "123.456.789-11",
+								Currency:  "BRL",
+								Balance:   &balance,
+								CreatedAt: time.Time{},
+							},
+						}}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+					principal := model.Principal{AccountID: "uuid-1", Roles: []model.Role{model.RoleAdmin}, Scopes: []model.Scope{model.ScopeAccountsRead}}
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), principal))
+				}(),
+			},
+			wantStatus: 200,
+			want:       `{"accounts": [{"id": "uuid-2", "name": "Lisa Simpson", "cpf": "123.456.789-11", "currency": "BRL", "balance": 10.59, "created_at": "<<PRESENCE>>"}], "next_cursor": null}`,
+		},
+		{
+			name: "should pass the query params to the usecase",
+			fields: fields{
+				accUC: mock.AccountUseCase{
+					OnFetch: func(ctx context.Context, caller model.Principal, fetchInput usecase.AccountFetchInput) (*usecase.AccountFetchPageOutput, error) {
+						want := usecase.AccountFetchInput{Cursor: "any-cursor", Limit: 10, Sort: "-name", Name: "bart", CPF: "123.456.789-11"}
+						if fetchInput != want {
+							return nil, errors.New("unexpected input")
+						}
+						nextCursor := "next-cursor"
+						return &usecase.AccountFetchPageOutput{Accounts: []usecase.AccountFetchOutput{}, NextCursor: &nextCursor}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/accounts?cursor=any-cursor&limit=10&sort=-name&name=bart&cpf=123.456.789-11", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 200,
+			want:       `{"accounts": [], "next_cursor": "next-cursor"}`,
+		},
+		{
+			name: "should return 400 when limit is not a number",
+			fields: fields{
+				accUC: mock.AccountUseCase{
+					OnFetch: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/accounts?limit=ten", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": "%s"}`, usecase.ErrAccountFetchLimitInvalid),
+		},
+		{
+			name: "should return 400 when usecase returns sort invalid error",
+			fields: fields{
+				accUC: mock.AccountUseCase{
+					OnFetch: func(ctx context.Context, caller model.Principal, fetchInput usecase.AccountFetchInput) (*usecase.AccountFetchPageOutput, error) {
+						return nil, usecase.ErrAccountFetchSortInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/accounts?sort=balance", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": "%s"}`, usecase.ErrAccountFetchSortInvalid),
+		},
+		{
+			name: "should return 403 when usecase returns forbidden error",
+			fields: fields{
+				accUC: mock.AccountUseCase{
+					OnFetch: func(ctx context.Context, caller model.Principal, fetchInput usecase.AccountFetchInput) (*usecase.AccountFetchPageOutput, error) {
+						return nil, usecase.ErrAuthForbidden
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/accounts?cpf=123.456.789-11", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code": 403, "message": "%s"}`, usecase.ErrAuthForbidden),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				accUC: mock.AccountUseCase{
+					OnFetch: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					return httptest.NewRequest(http.MethodGet, "/accounts", nil)
+				}(),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": "%s"}`, usecase.ErrAuthInvalidAccessToken),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -365,10 +506,13 @@ func Test_accountController_GetBalance(t *testing.T) {
 			name: "successful result zero balance",
 			fields: fields{
 				accountUC: mock.AccountUseCase{
-					OnGetBalance: func(ctx context.Context, id model.AccountID) (*usecase.AccountBalanceOutput, error) {
+					OnGetBalance: func(ctx context.Context, caller model.Principal, id model.AccountID) (*usecase.AccountBalanceOutput, error) {
 						return &usecase.AccountBalanceOutput{
-							ID:      "uuid-1",
-							Balance: 0,
+							ID:               "uuid-1",
+							Currency:         "BRL",
+							Balance:          usecase.NewAmount(0),
+							CreditLimit:      usecase.NewAmount(0),
+							AvailableBalance: usecase.NewAmount(0),
 						}, nil
 					},
 				},
@@ -376,20 +520,25 @@ func Test_accountController_GetBalance(t *testing.T) {
 			args: args{
 				w: httptest.NewRecorder(),
 				r: func() *http.Request {
-					return httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/balance", nil)
+					req := httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/balance", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 200,
-			want:       `{"id":"uuid-1", "balance":0}`,
+			want:       `{"id":"uuid-1", "currency":"BRL", "balance":0, "credit_limit":0, "available_balance":0}`,
 		},
 		{
-			name: "successful result positive balance",
+			name: "successful result overdrawn balance",
 			fields: fields{
 				accountUC: mock.AccountUseCase{
-					OnGetBalance: func(ctx context.Context, id model.AccountID) (*usecase.AccountBalanceOutput, error) {
+					OnGetBalance: func(ctx context.Context, caller model.Principal, id model.AccountID) (*usecase.AccountBalanceOutput, error) {
 						return &usecase.AccountBalanceOutput{
-							ID:      "uuid-1",
-							Balance: 10.59,
+							ID:               "uuid-1",
+							Currency:         "BRL",
+							Balance:          usecase.NewAmount(-1059),
+							CreditLimit:      usecase.NewAmount(5000),
+							AvailableBalance: usecase.NewAmount(3941),
 						}, nil
 					},
 				},
@@ -397,17 +546,19 @@ func Test_accountController_GetBalance(t *testing.T) {
 			args: args{
 				w: httptest.NewRecorder(),
 				r: func() *http.Request {
-					return httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/balance", nil)
+					req := httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/balance", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 200,
-			want:       `{"id":"uuid-1", "balance":10.59}`,
+			want:       `{"id":"uuid-1", "currency":"BRL", "balance":-10.59, "credit_limit":50, "available_balance":39.41}`,
 		},
 		{
 			name: "should return 500 when usecase error",
 			fields: fields{
 				accountUC: mock.AccountUseCase{
-					OnGetBalance: func(ctx context.Context, id model.AccountID) (*usecase.AccountBalanceOutput, error) {
+					OnGetBalance: func(ctx context.Context, caller model.Principal, id model.AccountID) (*usecase.AccountBalanceOutput, error) {
 						return nil, errors.New("any error")
 					},
 				},
@@ -415,7 +566,9 @@ func Test_accountController_GetBalance(t *testing.T) {
 			args: args{
 				w: httptest.NewRecorder(),
 				r: func() *http.Request {
-					return httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/balance", nil)
+					req := httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/balance", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 500,
@@ -425,7 +578,7 @@ func Test_accountController_GetBalance(t *testing.T) {
 			name: "should return 404 when account not found",
 			fields: fields{
 				accountUC: mock.AccountUseCase{
-					OnGetBalance: func(ctx context.Context, id model.AccountID) (*usecase.AccountBalanceOutput, error) {
+					OnGetBalance: func(ctx context.Context, caller model.Principal, id model.AccountID) (*usecase.AccountBalanceOutput, error) {
 						return nil, repository.ErrAccountNotFound
 					},
 				},
@@ -433,12 +586,50 @@ func Test_accountController_GetBalance(t *testing.T) {
 			args: args{
 				w: httptest.NewRecorder(),
 				r: func() *http.Request {
-					return httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/balance", nil)
+					req := httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/balance", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 404,
 			want:       fmt.Sprintf(`{"code": 404, "message": "%s"}`, repository.ErrAccountNotFound),
 		},
+		{
+			name: "should return 403 when not the account owner",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnGetBalance: func(ctx context.Context, caller model.Principal, id model.AccountID) (*usecase.AccountBalanceOutput, error) {
+						return nil, usecase.ErrAuthForbidden
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/balance", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-2"}))
+				}(),
+			},
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code": 403, "message": "%s"}`, usecase.ErrAuthForbidden),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnGetBalance: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					return httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/balance", nil)
+				}(),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": "%s"}`, usecase.ErrAuthInvalidAccessToken),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -462,3 +653,837 @@ func Test_accountController_GetBalance(t *testing.T) {
 		})
 	}
 }
+
+// newTestAccountStatementRequest returns a request for the account statement endpoint, with the id param and the owner principal.
+func newTestAccountStatementRequest(query string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/statement"+query, nil)
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "uuid-1"}})
+	ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "uuid-1"})
+
+	return req.WithContext(ctx)
+}
+
+func Test_accountController_GetStatement(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	type fields struct {
+		accountUC usecase.AccountUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful with dates",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnGetStatement: func(ctx context.Context, caller model.Principal, statementInput usecase.AccountStatementInput) (*usecase.AccountStatementOutput, error) {
+						wantFrom := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+						wantTo := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+						if statementInput.AccountID != "uuid-1" || !statementInput.From.Equal(wantFrom) || !statementInput.To.Equal(wantTo) {
+							return nil, fmt.Errorf("unexpected input %+v", statementInput)
+						}
+
+						return &usecase.AccountStatementOutput{
+							AccountID:      "uuid-1",
+							From:           statementInput.From,
+							To:             statementInput.To,
+							OpeningBalance: usecase.NewAmount(1000),
+							ClosingBalance: usecase.NewAmount(700),
+							Entries: []usecase.AccountStatementEntryOutput{
+								{
+									ID:           "entry-1",
+									Direction:    "debit",
+									Kind:         "transfer",
+									ReferenceID:  "transfer-1",
+									Amount:       usecase.NewAmount(300),
+									BalanceAfter: usecase.NewAmount(700),
+									Counterpart:  &usecase.AccountStatementCounterpartOutput{ID: "uuid-2", Name: "Bart Simpson"},
+									CreatedAt:    statementInput.From,
+								},
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatementRequest("?from=2021-01-01&to=2021-01-31"),
+			},
+			wantStatus: 200,
+			want: `{
+				"account_id":"uuid-1",
+				"from":"2021-01-01T00:00:00Z",
+				"to":"2021-02-01T00:00:00Z",
+				"opening_balance":10,
+				"closing_balance":7,
+				"entries":[{
+					"id":"entry-1",
+					"direction":"debit",
+					"kind":"transfer",
+					"reference_id":"transfer-1",
+					"amount":3,
+					"balance_after":7,
+					"counterpart":{"id":"uuid-2", "name":"Bart Simpson"},
+					"created_at":"2021-01-01T00:00:00Z"
+				}]
+			}`,
+		},
+		{
+			name: "successful with timestamps",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnGetStatement: func(ctx context.Context, caller model.Principal, statementInput usecase.AccountStatementInput) (*usecase.AccountStatementOutput, error) {
+						wantTo := time.Date(2021, 1, 31, 12, 0, 0, 0, time.UTC)
+						if !statementInput.From.IsZero() || !statementInput.To.Equal(wantTo) {
+							return nil, fmt.Errorf("unexpected input %+v", statementInput)
+						}
+
+						return &usecase.AccountStatementOutput{
+							AccountID: "uuid-1",
+							Entries:   []usecase.AccountStatementEntryOutput{},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatementRequest("?to=2021-01-31T09:00:00-03:00"),
+			},
+			wantStatus: 200,
+			want:       `{"account_id":"uuid-1", "from":"<<PRESENCE>>", "to":"<<PRESENCE>>", "opening_balance":0, "closing_balance":0, "entries":[]}`,
+		},
+		{
+			name: "should return 400 when date is invalid",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnGetStatement: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatementRequest("?from=01/01/2021"),
+			},
+			wantStatus: 400,
+			want:       `{"code":400, "message":"'from' and 'to' must be RFC 3339 timestamps or dates (YYYY-MM-DD)"}`,
+		},
+		{
+			name: "should return 400 when period is invalid",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnGetStatement: func(ctx context.Context, caller model.Principal, statementInput usecase.AccountStatementInput) (*usecase.AccountStatementOutput, error) {
+						return nil, usecase.ErrAccountStatementPeriodInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatementRequest("?from=2021-02-01&to=2021-01-01"),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code":400, "message":%q}`, usecase.ErrAccountStatementPeriodInvalid),
+		},
+		{
+			name: "should return 403 when not the owner",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnGetStatement: func(ctx context.Context, caller model.Principal, statementInput usecase.AccountStatementInput) (*usecase.AccountStatementOutput, error) {
+						return nil, usecase.ErrAuthForbidden
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatementRequest(""),
+			},
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code":403, "message":%q}`, usecase.ErrAuthForbidden),
+		},
+		{
+			name: "should return 404 when account not found",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnGetStatement: func(ctx context.Context, caller model.Principal, statementInput usecase.AccountStatementInput) (*usecase.AccountStatementOutput, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatementRequest(""),
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code":404, "message":%q}`, repository.ErrAccountNotFound),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnGetStatement: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/statement", nil),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code":401, "message":%q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accCtrl := NewAccountController(tt.fields.accountUC)
+
+			accCtrl.GetStatement(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("GetStatement() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+// newTestAccountStatusRequest returns a request for the account status endpoints, with the id param and the admin principal.
+func newTestAccountStatusRequest(path string, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader([]byte(body)))
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "uuid-1"}})
+	ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "admin-uuid", Scopes: []model.Scope{model.ScopeAccountsWrite}})
+
+	return req.WithContext(ctx)
+}
+
+func Test_accountController_Block(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	type fields struct {
+		accountUC usecase.AccountUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnBlock: func(ctx context.Context, caller model.Principal, statusInput usecase.AccountStatusInput) (*usecase.AccountStatusOutput, error) {
+						if statusInput.AccountID != "uuid-1" || statusInput.Reason != "suspected fraud" {
+							return nil, errors.New("should pass the id and the reason")
+						}
+
+						return &usecase.AccountStatusOutput{
+							ID:              statusInput.AccountID,
+							Status:          "blocked",
+							StatusReason:    statusInput.Reason,
+							StatusChangedAt: time.Now(),
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatusRequest("/accounts/uuid-1/block", `{"reason":"suspected fraud"}`),
+			},
+			wantStatus: 200,
+			want:       `{"id":"uuid-1", "status":"blocked", "status_reason":"suspected fraud", "status_changed_at":"<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 400 when reason is missing",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnBlock: func(ctx context.Context, caller model.Principal, statusInput usecase.AccountStatusInput) (*usecase.AccountStatusOutput, error) {
+						return nil, usecase.ErrAccountStatusReasonRequired
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatusRequest("/accounts/uuid-1/block", `{}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": "%s"}`, usecase.ErrAccountStatusReasonRequired),
+		},
+		{
+			name: "should return 409 when status can't be changed",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnBlock: func(ctx context.Context, caller model.Principal, statusInput usecase.AccountStatusInput) (*usecase.AccountStatusOutput, error) {
+						return nil, usecase.ErrAccountStatusChangeInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatusRequest("/accounts/uuid-1/block", `{"reason":"suspected fraud"}`),
+			},
+			wantStatus: 409,
+			want:       fmt.Sprintf(`{"code": 409, "message": "%s"}`, usecase.ErrAccountStatusChangeInvalid),
+		},
+		{
+			name: "should return 404 when account not found",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnBlock: func(ctx context.Context, caller model.Principal, statusInput usecase.AccountStatusInput) (*usecase.AccountStatusOutput, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatusRequest("/accounts/uuid-1/block", `{"reason":"suspected fraud"}`),
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": "%s"}`, repository.ErrAccountNotFound),
+		},
+		{
+			name: "should return 403 when caller can't change status",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnBlock: func(ctx context.Context, caller model.Principal, statusInput usecase.AccountStatusInput) (*usecase.AccountStatusOutput, error) {
+						return nil, usecase.ErrAuthForbidden
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatusRequest("/accounts/uuid-1/block", `{"reason":"suspected fraud"}`),
+			},
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code": 403, "message": "%s"}`, usecase.ErrAuthForbidden),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnBlock: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/accounts/uuid-1/block", bytes.NewReader([]byte(`{"reason":"suspected fraud"}`))),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": "%s"}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAccountController(tt.fields.accountUC)
+
+			a.Block(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Block() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_accountController_Close(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	type fields struct {
+		accountUC usecase.AccountUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful with sweep",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnClose: func(ctx context.Context, caller model.Principal, closeInput usecase.AccountCloseInput) (*usecase.AccountStatusOutput, error) {
+						if closeInput.AccountID != "uuid-1" || closeInput.SweepAccountID != "uuid-2" {
+							return nil, errors.New("should pass the id and the sweep account id")
+						}
+
+						sweptAmount := usecase.NewAmount(1050)
+						return &usecase.AccountStatusOutput{
+							ID:              closeInput.AccountID,
+							Status:          "closed",
+							StatusReason:    closeInput.Reason,
+							StatusChangedAt: time.Now(),
+							SweptAmount:     &sweptAmount,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatusRequest("/accounts/uuid-1/close", `{"reason":"requested by the holder", "sweep_account_id":"uuid-2"}`),
+			},
+			wantStatus: 200,
+			want:       `{"id":"uuid-1", "status":"closed", "status_reason":"requested by the holder", "status_changed_at":"<<PRESENCE>>", "swept_amount":10.5}`,
+		},
+		{
+			name: "should return 422 when balance is not zero",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnClose: func(ctx context.Context, caller model.Principal, closeInput usecase.AccountCloseInput) (*usecase.AccountStatusOutput, error) {
+						return nil, usecase.ErrAccountCloseBalanceNotZero
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatusRequest("/accounts/uuid-1/close", `{"reason":"requested by the holder"}`),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": "%s"}`, usecase.ErrAccountCloseBalanceNotZero),
+		},
+		{
+			name: "should return 422 when sweep account is invalid",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnClose: func(ctx context.Context, caller model.Principal, closeInput usecase.AccountCloseInput) (*usecase.AccountStatusOutput, error) {
+						return nil, usecase.ErrAccountSweepAccountInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatusRequest("/accounts/uuid-1/close", `{"reason":"requested by the holder", "sweep_account_id":"uuid-3"}`),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": "%s"}`, usecase.ErrAccountSweepAccountInvalid),
+		},
+		{
+			name: "should return 400 when body is invalid",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnClose: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountStatusRequest("/accounts/uuid-1/close", `{`),
+			},
+			wantStatus: 400,
+			want:       `{"code": 400, "message": "error reading input"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAccountController(tt.fields.accountUC)
+
+			a.Close(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Close() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+// newTestAccountCreditLimitRequest returns a request for the account credit limit endpoint, with the id param and the operator principal.
+func newTestAccountCreditLimitRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, "/accounts/uuid-1/credit-limit", bytes.NewReader([]byte(body)))
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "uuid-1"}})
+	ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "operator-uuid", Scopes: []model.Scope{model.ScopeAccountsCredit}})
+
+	return req.WithContext(ctx)
+}
+
+func Test_accountController_SetCreditLimit(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	type fields struct {
+		accountUC usecase.AccountUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetCreditLimit: func(ctx context.Context, caller model.Principal, creditLimitInput usecase.AccountCreditLimitInput) (*usecase.AccountBalanceOutput, error) {
+						if creditLimitInput.AccountID != "uuid-1" || creditLimitInput.CreditLimit.Money != 50050 {
+							return nil, errors.New("should pass the id and the credit limit")
+						}
+
+						return &usecase.AccountBalanceOutput{
+							ID:               creditLimitInput.AccountID,
+							Currency:         "BRL",
+							Balance:          usecase.NewAmount(-1000),
+							CreditLimit:      creditLimitInput.CreditLimit,
+							AvailableBalance: usecase.NewAmount(49050),
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountCreditLimitRequest(`{"credit_limit":"500.50"}`),
+			},
+			wantStatus: 200,
+			want:       `{"id":"uuid-1", "currency":"BRL", "balance":-10, "credit_limit":500.5, "available_balance":490.5}`,
+		},
+		{
+			name: "should return 400 when credit limit is negative",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetCreditLimit: func(ctx context.Context, caller model.Principal, creditLimitInput usecase.AccountCreditLimitInput) (*usecase.AccountBalanceOutput, error) {
+						return nil, usecase.ErrAccountCreditLimitNegative
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountCreditLimitRequest(`{"credit_limit":-1}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrAccountCreditLimitNegative),
+		},
+		{
+			name: "should return 400 when credit limit has more than 3 decimal places",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetCreditLimit: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountCreditLimitRequest(`{"credit_limit":0.0001}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrAmountInvalid),
+		},
+		{
+			name: "should return 409 when account is closed",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetCreditLimit: func(ctx context.Context, caller model.Principal, creditLimitInput usecase.AccountCreditLimitInput) (*usecase.AccountBalanceOutput, error) {
+						return nil, usecase.ErrAccountCreditLimitClosed
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountCreditLimitRequest(`{"credit_limit":500}`),
+			},
+			wantStatus: 409,
+			want:       fmt.Sprintf(`{"code": 409, "message": %q}`, usecase.ErrAccountCreditLimitClosed),
+		},
+		{
+			name: "should return 404 when account not found",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetCreditLimit: func(ctx context.Context, caller model.Principal, creditLimitInput usecase.AccountCreditLimitInput) (*usecase.AccountBalanceOutput, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountCreditLimitRequest(`{"credit_limit":500}`),
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrAccountNotFound),
+		},
+		{
+			name: "should return 403 when caller can't set credit limits",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetCreditLimit: func(ctx context.Context, caller model.Principal, creditLimitInput usecase.AccountCreditLimitInput) (*usecase.AccountBalanceOutput, error) {
+						return nil, usecase.ErrAuthForbidden
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountCreditLimitRequest(`{"credit_limit":500}`),
+			},
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code": 403, "message": %q}`, usecase.ErrAuthForbidden),
+		},
+		{
+			name: "should return 500 when usecase error",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetCreditLimit: func(ctx context.Context, caller model.Principal, creditLimitInput usecase.AccountCreditLimitInput) (*usecase.AccountBalanceOutput, error) {
+						return nil, usecase.ErrAccountSetCreditLimit
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountCreditLimitRequest(`{"credit_limit":500}`),
+			},
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": %q}`, usecase.ErrAccountSetCreditLimit),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetCreditLimit: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPut, "/accounts/uuid-1/credit-limit", bytes.NewReader([]byte(`{"credit_limit":500}`))),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := NewAccountController(tt.fields.accountUC)
+
+			a.SetCreditLimit(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("SetCreditLimit() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func newTestAccountTierRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, "/accounts/uuid-1/tier", bytes.NewReader([]byte(body)))
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "uuid-1"}})
+	ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "admin-uuid", Scopes: []model.Scope{model.ScopeAccountsWrite}})
+
+	return req.WithContext(ctx)
+}
+
+func Test_accountController_SetTier(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	type fields struct {
+		accountUC usecase.AccountUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetTier: func(ctx context.Context, caller model.Principal, tierInput usecase.AccountTierInput) (*usecase.AccountTierOutput, error) {
+						if tierInput.AccountID != "uuid-1" || tierInput.Tier != "premium" {
+							return nil, errors.New("should pass the id and the tier")
+						}
+
+						return &usecase.AccountTierOutput{ID: tierInput.AccountID, Tier: tierInput.Tier}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountTierRequest(`{"tier":"premium"}`),
+			},
+			wantStatus: 200,
+			want:       `{"id":"uuid-1", "tier":"premium"}`,
+		},
+		{
+			name: "should return 400 when tier is invalid",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetTier: func(ctx context.Context, caller model.Principal, tierInput usecase.AccountTierInput) (*usecase.AccountTierOutput, error) {
+						return nil, usecase.ErrAccountTierInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountTierRequest(`{"tier":"gold"}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrAccountTierInvalid),
+		},
+		{
+			name: "should return 400 when input is malformed",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetTier: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountTierRequest(`{"tier":`),
+			},
+			wantStatus: 400,
+			want:       `{"code": 400, "message": "error reading input"}`,
+		},
+		{
+			name: "should return 404 when account not found",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetTier: func(ctx context.Context, caller model.Principal, tierInput usecase.AccountTierInput) (*usecase.AccountTierOutput, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountTierRequest(`{"tier":"premium"}`),
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrAccountNotFound),
+		},
+		{
+			name: "should return 403 when caller can't set tiers",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetTier: func(ctx context.Context, caller model.Principal, tierInput usecase.AccountTierInput) (*usecase.AccountTierOutput, error) {
+						return nil, usecase.ErrAuthForbidden
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountTierRequest(`{"tier":"premium"}`),
+			},
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code": 403, "message": %q}`, usecase.ErrAuthForbidden),
+		},
+		{
+			name: "should return 500 when usecase error",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetTier: func(ctx context.Context, caller model.Principal, tierInput usecase.AccountTierInput) (*usecase.AccountTierOutput, error) {
+						return nil, usecase.ErrAccountSetTier
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestAccountTierRequest(`{"tier":"premium"}`),
+			},
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": %q}`, usecase.ErrAccountSetTier),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				accountUC: mock.AccountUseCase{
+					OnSetTier: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPut, "/accounts/uuid-1/tier", bytes.NewReader([]byte(`{"tier":"premium"}`))),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := NewAccountController(tt.fields.accountUC)
+
+			a.SetTier(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("SetTier() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
@@ -1,11 +1,16 @@
 package controller
 
 import (
+	"errors"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/hlog"
 
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
 )
@@ -13,8 +18,9 @@ import (
 // AuthController is the interface that wraps http handle methods related to authentication.
 type AuthController interface {
 	Login(w http.ResponseWriter, r *http.Request)
-	//Logout(w http.ResponseWriter, r *http.Request)
-	//RefreshToken(w http.ResponseWriter, r *http.Request)
+	Logout(w http.ResponseWriter, r *http.Request)
+	RefreshToken(w http.ResponseWriter, r *http.Request)
+	JWKS(w http.ResponseWriter, r *http.Request)
 }
 
 type authController struct {
@@ -29,7 +35,7 @@ func NewAuthController(authUC usecase.AuthUseCase) AuthController {
 }
 
 // @Summary Login
-// @Description Authenticates the user/account
+// @Description Authenticates the user/account. Returns a short-lived access token and a refresh token to get new access tokens from `/token/refresh`.
 // @tags Authentication
 // @Accept json
 // @Produce json
@@ -37,6 +43,7 @@ func NewAuthController(authUC usecase.AuthUseCase) AuthController {
 // @Success 200 {object} usecase.AuthTokenOutput
 // @failure 400 {object} io.ErrorOutput
 // @failure 401 {object} io.ErrorOutput
+// @failure 429 {object} io.ErrorOutput "Too many failed attempts. Retry after the seconds in the Retry-After header."
 // @Router /login [post]
 func (authCtrl authController) Login(w http.ResponseWriter, r *http.Request) {
 	logger := hlog.FromRequest(r)
@@ -47,21 +54,132 @@ func (authCtrl authController) Login(w http.ResponseWriter, r *http.Request) {
 		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
 		return
 	}
+	input.ClientIP = clientIP(r)
 
 	result, err := authCtrl.authUC.Login(logger.WithContext(r.Context()), input)
 	if err != nil {
+		var lockedErr *usecase.AuthLoginLockedError
+		if errors.As(err, &lockedErr) {
+			retryAfter := int(math.Ceil(lockedErr.RetryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		}
+
 		authCtrl.writeError(w, logger, http.StatusInternalServerError, err)
 		return
 	}
 
-	io.WriteSuccess(w, logger, http.StatusOK, result)
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Refresh token
+// @Description Exchanges a refresh token for a new access token and a new refresh token. Each refresh token can be used only once: reusing it revokes all the tokens issued from the same login.
+// @tags Authentication
+// @Accept json
+// @Produce json
+// @Param refresh_token body usecase.AuthRefreshInput true "Refresh token"
+// @Success 200 {object} usecase.AuthTokenOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /token/refresh [post]
+func (authCtrl authController) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	var input usecase.AuthRefreshInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding refresh token input")
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+
+	result, err := authCtrl.authUC.Refresh(logger.WithContext(r.Context()), input)
+	if err != nil {
+		authCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Logout
+// @Description Revokes the current access token and, if informed, the refresh token.
+// @tags Authentication
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param refresh_token body usecase.AuthLogoutInput false "Refresh token"
+// @Success 204
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /logout [post]
+func (authCtrl authController) Logout(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		authCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.AuthLogoutInput
+	if r.ContentLength != 0 {
+		if err := io.ReadInput(r, logger, &input); err != nil {
+			logger.Error().Stack().Err(err).Msg("error decoding logout input")
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+			return
+		}
+	}
+	input.AccountID = string(principal.AccountID)
+	input.AccessTokenID, _ = appcontext.GetAuthTokenID(r.Context())
+
+	err := authCtrl.authUC.Logout(logger.WithContext(r.Context()), input)
+	if err != nil {
+		authCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary JSON Web Key Set
+// @Description Returns the public keys used to verify the access tokens. The token `kid` header identifies the key.
+// @tags Authentication
+// @Produce json
+// @Success 200 {object} usecase.JWKSOutput
+// @Router /.well-known/jwks.json [get]
+func (authCtrl authController) JWKS(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	// verifiers may cache the keys, as rotated keys are kept while the tokens signed by them are valid
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	io.WriteSuccess(w, r, logger, http.StatusOK, authCtrl.authUC.JWKS())
 }
 
 func (authCtrl authController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
 	switch err {
-	case usecase.ErrAuthInvalidCredentials:
+	case usecase.ErrAuthInvalidCredentials,
+		usecase.ErrAuthInvalidRefreshToken,
+		usecase.ErrAuthInvalidAccessToken:
 		statusCode = http.StatusUnauthorized
+	case usecase.ErrAuthAccountClosed:
+		statusCode = http.StatusForbidden
+	}
+
+	if errors.Is(err, usecase.ErrAuthTooManyLoginAttempts) {
+		statusCode = http.StatusTooManyRequests
 	}
 
 	io.WriteErrorMsg(w, logger, statusCode, err.Error())
 }
+
+// clientIP returns the IP of the client connection, without the port.
+// Forwarded headers are not trusted, as clients could set them to evade the login lockout.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
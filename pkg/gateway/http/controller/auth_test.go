@@ -8,9 +8,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/kinbiko/jsonassert"
 
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
 )
@@ -28,11 +31,12 @@ func Test_authController_Login(t *testing.T) {
 		r *http.Request
 	}
 	tests := []struct {
-		name       string
-		fields     fields
-		args       args
-		wantStatus int
-		want       string
+		name           string
+		fields         fields
+		args           args
+		wantStatus     int
+		wantRetryAfter string
+		want           string
 	}{
 		{
 			name: "successful",
@@ -56,6 +60,24 @@ func Test_authController_Login(t *testing.T) {
 			wantStatus: 200,
 			want:       `{"access_token": "my_access_token"}`,
 		},
+		{
+			name: "should return 403 when account is closed",
+			fields: fields{
+				authUC: mock.AuthUseCase{
+					OnLogin: func(ctx context.Context, loginInput usecase.AuthLoginInput) (*usecase.AuthTokenOutput, error) {
+						return nil, usecase.ErrAuthAccountClosed
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					return httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader([]byte(`{"cpf":"12345678911", "secret": "secret"}`)))
+				}(),
+			},
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code": 403, "message": "%s"}`, usecase.ErrAuthAccountClosed),
+		},
 		{
 			name: "should return 500 when usecase error",
 			fields: fields{
@@ -106,6 +128,26 @@ func Test_authController_Login(t *testing.T) {
 			wantStatus: 400,
 			want:       `{"code": 400, "message": "error reading input"}`,
 		},
+		{
+			name: "should return 429 with Retry-After when locked out",
+			fields: fields{
+				authUC: mock.AuthUseCase{
+					OnLogin: func(ctx context.Context, loginInput usecase.AuthLoginInput) (*usecase.AuthTokenOutput, error) {
+						if loginInput.ClientIP != "192.0.2.1" {
+							return nil, fmt.Errorf("unexpected client IP %q", loginInput.ClientIP)
+						}
+						return nil, &usecase.AuthLoginLockedError{RetryAfter: 90500 * time.Millisecond}
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader([]byte(`{"cpf":"12345678911", "secret": "secret"}`))),
+			},
+			wantStatus:     429,
+			wantRetryAfter: "91",
+			want:           fmt.Sprintf(`{"code": 429, "message": "%s"}`, usecase.ErrAuthTooManyLoginAttempts),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -123,9 +165,304 @@ func Test_authController_Login(t *testing.T) {
 				t.Errorf("Login() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
 			}
 
+			if retryAfter := rec.Header().Get("Retry-After"); retryAfter != tt.wantRetryAfter {
+				t.Errorf("Login() Retry-After = %v, want %v", retryAfter, tt.wantRetryAfter)
+			}
+
 			// Check result response
 			bodyStr := rec.Body.String()
 			ja.Assertf(bodyStr, tt.want)
 		})
 	}
 }
+
+func Test_authController_RefreshToken(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	type fields struct {
+		authUC usecase.AuthUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				authUC: mock.AuthUseCase{
+					OnRefresh: func(ctx context.Context, refreshInput usecase.AuthRefreshInput) (*usecase.AuthTokenOutput, error) {
+						if refreshInput.RefreshToken != "my_refresh_token" {
+							return nil, errors.New("unexpected refresh token")
+						}
+
+						ret := usecase.AuthTokenOutput{
+							AccessToken:  "my_new_access_token",
+							RefreshToken: "my_new_refresh_token",
+						}
+
+						return &ret, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/token/refresh", bytes.NewReader([]byte(`{"refresh_token":"my_refresh_token"}`))),
+			},
+			wantStatus: 200,
+			want:       `{"access_token": "my_new_access_token", "refresh_token": "my_new_refresh_token"}`,
+		},
+		{
+			name: "should return 401 when invalid refresh token",
+			fields: fields{
+				authUC: mock.AuthUseCase{
+					OnRefresh: func(ctx context.Context, refreshInput usecase.AuthRefreshInput) (*usecase.AuthTokenOutput, error) {
+						return nil, usecase.ErrAuthInvalidRefreshToken
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/token/refresh", bytes.NewReader([]byte(`{"refresh_token":"reused_refresh_token"}`))),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": "%s"}`, usecase.ErrAuthInvalidRefreshToken),
+		},
+		{
+			name: "should return 500 when usecase error",
+			fields: fields{
+				authUC: mock.AuthUseCase{
+					OnRefresh: func(ctx context.Context, refreshInput usecase.AuthRefreshInput) (*usecase.AuthTokenOutput, error) {
+						return nil, usecase.ErrAuthRefresh
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/token/refresh", bytes.NewReader([]byte(`{"refresh_token":"my_refresh_token"}`))),
+			},
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": "%s"}`, usecase.ErrAuthRefresh),
+		},
+		{
+			name: "should return 400 with error msg when request body is missing",
+			fields: fields{
+				authUC: mock.AuthUseCase{
+					OnRefresh: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/token/refresh", nil),
+			},
+			wantStatus: 400,
+			want:       `{"code": 400, "message": "error reading input"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authCtrl := NewAuthController(tt.fields.authUC)
+
+			authCtrl.RefreshToken(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("RefreshToken() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_authController_Logout(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	type fields struct {
+		authUC usecase.AuthUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful with refresh token",
+			fields: fields{
+				authUC: mock.AuthUseCase{
+					OnLogout: func(ctx context.Context, logoutInput usecase.AuthLogoutInput) error {
+						if logoutInput.AccountID != "uuid-1" || logoutInput.AccessTokenID != "jti-1" || logoutInput.RefreshToken != "my_refresh_token" {
+							return fmt.Errorf("unexpected input %v", logoutInput)
+						}
+						return nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/logout", bytes.NewReader([]byte(`{"refresh_token":"my_refresh_token"}`)))
+					ctx := appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"})
+
+					return req.WithContext(appcontext.WithAuthTokenID(ctx, "jti-1"))
+				}(),
+			},
+			wantStatus: 204,
+			want:       ``,
+		},
+		{
+			name: "successful without body",
+			fields: fields{
+				authUC: mock.AuthUseCase{
+					OnLogout: func(ctx context.Context, logoutInput usecase.AuthLogoutInput) error {
+						if logoutInput.AccessTokenID != "jti-1" || logoutInput.RefreshToken != "" {
+							return fmt.Errorf("unexpected input %v", logoutInput)
+						}
+						return nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+					ctx := appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"})
+
+					return req.WithContext(appcontext.WithAuthTokenID(ctx, "jti-1"))
+				}(),
+			},
+			wantStatus: 204,
+			want:       ``,
+		},
+		{
+			name: "should return 401 when refresh token belongs to another account",
+			fields: fields{
+				authUC: mock.AuthUseCase{
+					OnLogout: func(ctx context.Context, logoutInput usecase.AuthLogoutInput) error {
+						return usecase.ErrAuthInvalidRefreshToken
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/logout", bytes.NewReader([]byte(`{"refresh_token":"other_refresh_token"}`)))
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": "%s"}`, usecase.ErrAuthInvalidRefreshToken),
+		},
+		{
+			name: "should return 500 when usecase error",
+			fields: fields{
+				authUC: mock.AuthUseCase{
+					OnLogout: func(ctx context.Context, logoutInput usecase.AuthLogoutInput) error {
+						return usecase.ErrAuthLogout
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": "%s"}`, usecase.ErrAuthLogout),
+		},
+		{
+			name: "should return 401 when invalid token",
+			fields: fields{
+				authUC: mock.AuthUseCase{
+					OnLogout: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/logout", nil),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": "%s"}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authCtrl := NewAuthController(tt.fields.authUC)
+
+			authCtrl.Logout(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Logout() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			if bodyStr := rec.Body.String(); tt.want == "" {
+				if bodyStr != "" {
+					t.Errorf("Logout() body = %v, want empty", bodyStr)
+				}
+			} else {
+				ja.Assertf(bodyStr, tt.want)
+			}
+		})
+	}
+}
+
+func Test_authController_JWKS(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	authCtrl := NewAuthController(mock.AuthUseCase{
+		OnJWKS: func() *usecase.JWKSOutput {
+			return &usecase.JWKSOutput{
+				Keys: []usecase.JWKOutput{
+					{KeyType: "OKP", KeyID: "2022", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+				},
+			}
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	authCtrl.JWKS(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("JWKS() statusCode = %v, wantStatus %v", rec.Code, http.StatusOK)
+	}
+	if cacheControl := rec.Header().Get("Cache-Control"); cacheControl == "" {
+		t.Errorf("JWKS() Cache-Control header should be set")
+	}
+	ja.Assertf(rec.Body.String(), `{"keys": [{"kty": "OKP", "kid": "2022", "use": "sig", "alg": "EdDSA", "crv": "Ed25519", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`)
+}
@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/hlog"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
+)
+
+// CashController is the interface that wraps http handle methods related to the deposits and withdrawals.
+type CashController interface {
+	Deposit(w http.ResponseWriter, r *http.Request)
+	Withdraw(w http.ResponseWriter, r *http.Request)
+}
+
+type cashController struct {
+	cashUC usecase.CashUseCase
+}
+
+// NewCashController instantiates a new deposit and withdrawal controller.
+func NewCashController(cashUC usecase.CashUseCase) CashController {
+	return &cashController{
+		cashUC: cashUC,
+	}
+}
+
+// @Summary Deposit cash
+// @Description Deposits cash into an account, crediting the amount. Only operators and admins (`cash:deposit` scope) can deposit.
+// @tags Cash
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param id path string true "Account ID"
+// @Param deposit body usecase.DepositCreateInput true "Deposit"
+// @Param X-Idempotency-Key header string false "Idempotency key"
+// @Success 201 {object} usecase.DepositCreateOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /accounts/{id}/deposits [post]
+func (cashCtrl cashController) Deposit(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	caller, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		cashCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.DepositCreateInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding deposit create input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.AccountID = httprouter.ParamsFromContext(r.Context()).ByName("id")
+
+	result, err := cashCtrl.cashUC.Deposit(logger.WithContext(r.Context()), caller, input)
+	if err != nil {
+		cashCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusCreated, result)
+}
+
+// @Summary Withdraw cash
+// @Description Withdraws cash from the current account, debiting the amount. It's limited by the transfer limits of the account.
+// @tags Cash
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param withdrawal body usecase.WithdrawalCreateInput true "Withdrawal"
+// @Param X-Idempotency-Key header string false "Idempotency key"
+// @Success 201 {object} usecase.WithdrawalCreateOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /withdrawals [post]
+func (cashCtrl cashController) Withdraw(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		cashCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.WithdrawalCreateInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding withdrawal create input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.AccountID = string(principal.AccountID)
+
+	result, err := cashCtrl.cashUC.Withdraw(logger.WithContext(r.Context()), input)
+	if err != nil {
+		cashCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusCreated, result)
+}
+
+func (cashCtrl cashController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
+	switch err {
+	case repository.ErrAccountNotFound,
+		usecase.ErrAccountCurrentBalanceInsufficient,
+		usecase.ErrCashAccountNotActive:
+		statusCode = http.StatusUnprocessableEntity
+	case usecase.ErrCashAccountRequired,
+		usecase.ErrCashAmountNotPositive,
+		usecase.ErrAmountInvalid:
+		statusCode = http.StatusBadRequest
+	case usecase.ErrAuthInvalidAccessToken:
+		statusCode = http.StatusUnauthorized
+	case usecase.ErrAuthForbidden:
+		statusCode = http.StatusForbidden
+	}
+
+	if errors.Is(err, usecase.ErrTransferLimitExceeded) {
+		statusCode = http.StatusUnprocessableEntity
+	}
+
+	io.WriteErrorMsg(w, logger, statusCode, err.Error())
+}
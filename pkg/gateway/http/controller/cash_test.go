@@ -0,0 +1,344 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
+)
+
+func Test_cashController_Deposit(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/accounts/uuid-1/deposits", bytes.NewReader([]byte(body)))
+		ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "uuid-1"}})
+		ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "operator-uuid", Scopes: []model.Scope{model.ScopeCashDeposit}})
+
+		return req.WithContext(ctx)
+	}
+
+	type fields struct {
+		cashUC usecase.CashUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnDeposit: func(ctx context.Context, caller model.Principal, depositInput usecase.DepositCreateInput) (*usecase.DepositCreateOutput, error) {
+						if depositInput.AccountID != "uuid-1" || caller.AccountID != "operator-uuid" {
+							return nil, errors.New("should pass the account id and the caller")
+						}
+
+						return &usecase.DepositCreateOutput{
+							ID:         "dep-uuid-1",
+							AccountID:  depositInput.AccountID,
+							OperatorID: string(caller.AccountID),
+							Amount:     depositInput.Amount,
+							CreatedAt:  time.Now(),
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 10.5}`),
+			},
+			wantStatus: 201,
+			want:       `{"id":"dep-uuid-1", "account_id":"uuid-1", "operator_id":"operator-uuid", "amount":10.5, "created_at":"<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 400 when amount has more than 3 decimal places",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnDeposit: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 10.5012}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrAmountInvalid),
+		},
+		{
+			name: "should return 400 when amount is not positive",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnDeposit: func(ctx context.Context, caller model.Principal, depositInput usecase.DepositCreateInput) (*usecase.DepositCreateOutput, error) {
+						return nil, usecase.ErrCashAmountNotPositive
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 0}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrCashAmountNotPositive),
+		},
+		{
+			name: "should return 422 when account not found",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnDeposit: func(ctx context.Context, caller model.Principal, depositInput usecase.DepositCreateInput) (*usecase.DepositCreateOutput, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 1}`),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, repository.ErrAccountNotFound),
+		},
+		{
+			name: "should return 403 when caller can't deposit",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnDeposit: func(ctx context.Context, caller model.Principal, depositInput usecase.DepositCreateInput) (*usecase.DepositCreateOutput, error) {
+						return nil, usecase.ErrAuthForbidden
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 1}`),
+			},
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code": 403, "message": %q}`, usecase.ErrAuthForbidden),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnDeposit: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/accounts/uuid-1/deposits", bytes.NewReader([]byte(`{"amount": 1}`))),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cashCtrl := NewCashController(tt.fields.cashUC)
+
+			cashCtrl.Deposit(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Deposit() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_cashController_Withdraw(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/withdrawals", bytes.NewReader([]byte(body)))
+
+		return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+	}
+
+	type fields struct {
+		cashUC usecase.CashUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnWithdraw: func(ctx context.Context, withdrawalInput usecase.WithdrawalCreateInput) (*usecase.WithdrawalCreateOutput, error) {
+						if withdrawalInput.AccountID != "uuid-1" {
+							return nil, errors.New("should withdraw from the logged-in account")
+						}
+
+						return &usecase.WithdrawalCreateOutput{
+							ID:        "wdr-uuid-1",
+							AccountID: withdrawalInput.AccountID,
+							Amount:    withdrawalInput.Amount,
+							CreatedAt: time.Now(),
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 1}`),
+			},
+			wantStatus: 201,
+			want:       `{"id":"wdr-uuid-1", "account_id":"uuid-1", "amount":1, "created_at":"<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 422 when balance is insufficient",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnWithdraw: func(ctx context.Context, withdrawalInput usecase.WithdrawalCreateInput) (*usecase.WithdrawalCreateOutput, error) {
+						return nil, usecase.ErrAccountCurrentBalanceInsufficient
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 1}`),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, usecase.ErrAccountCurrentBalanceInsufficient),
+		},
+		{
+			name: "should return 422 when a transfer limit is exceeded",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnWithdraw: func(ctx context.Context, withdrawalInput usecase.WithdrawalCreateInput) (*usecase.WithdrawalCreateOutput, error) {
+						return nil, &usecase.TransferLimitExceededError{Limit: model.TransferLimitDaily, Remaining: 5050}
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 100}`),
+			},
+			wantStatus: 422,
+			want:       `{"code": 422, "message": "'amount' exceeds the daily transfer limit, the remaining allowance is 50.50"}`,
+		},
+		{
+			name: "should return 422 when account is not active",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnWithdraw: func(ctx context.Context, withdrawalInput usecase.WithdrawalCreateInput) (*usecase.WithdrawalCreateOutput, error) {
+						return nil, usecase.ErrCashAccountNotActive
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 1}`),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, usecase.ErrCashAccountNotActive),
+		},
+		{
+			name: "should return 500 when usecase error",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnWithdraw: func(ctx context.Context, withdrawalInput usecase.WithdrawalCreateInput) (*usecase.WithdrawalCreateOutput, error) {
+						return nil, errors.New("any error")
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 1}`),
+			},
+			wantStatus: 500,
+			want:       `{"code": 500, "message": "any error"}`,
+		},
+		{
+			name: "should return 400 when request body is missing",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnWithdraw: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/withdrawals", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 400,
+			want:       `{"code": 400, "message": "error reading input"}`,
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				cashUC: mock.CashUseCase{
+					OnWithdraw: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/withdrawals", bytes.NewReader([]byte(`{"amount": 1}`))),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cashCtrl := NewCashController(tt.fields.cashUC)
+
+			cashCtrl.Withdraw(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Withdraw() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
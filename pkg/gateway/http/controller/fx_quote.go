@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/hlog"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
+)
+
+// FXQuoteController is the interface that wraps http handle methods related to the FX quotes.
+type FXQuoteController interface {
+	Create(w http.ResponseWriter, r *http.Request)
+}
+
+type fxQuoteController struct {
+	quoteUC usecase.FXQuoteUseCase
+}
+
+// NewFXQuoteController instantiates a new FX quote controller.
+func NewFXQuoteController(quoteUC usecase.FXQuoteUseCase) FXQuoteController {
+	return &fxQuoteController{
+		quoteUC: quoteUC,
+	}
+}
+
+// @Summary Create FX quote
+// @Description Quotes the `amount`, in the currency of the current account, in `target_currency` by the current exchange rate.
+// @Description The quote fixes the rate until `expires_at` for a single transfer of the amount to an account in the target currency,
+// @Description created with its `id` as `quote_id`.
+// @tags FX quotes
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param quote body usecase.FXQuoteCreateInput true "FX quote"
+// @Param X-Idempotency-Key header string false "Idempotency key"
+// @Success 201 {object} usecase.FXQuoteOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /fx-quotes [post]
+func (quoteCtrl fxQuoteController) Create(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		quoteCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.FXQuoteCreateInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding fx quote create input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.AccountID = string(principal.AccountID)
+
+	result, err := quoteCtrl.quoteUC.Create(logger.WithContext(r.Context()), input)
+	if err != nil {
+		quoteCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusCreated, result)
+}
+
+func (quoteCtrl fxQuoteController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
+	switch err {
+	case repository.ErrAccountNotFound,
+		repository.ErrFXRateNotFound,
+		usecase.ErrFXQuoteAmountTooSmall:
+		statusCode = http.StatusUnprocessableEntity
+	case usecase.ErrFXQuoteCurrencyInvalid,
+		usecase.ErrFXQuoteAmountInvalid,
+		usecase.ErrFXQuoteSameCurrency,
+		usecase.ErrAmountInvalid:
+		statusCode = http.StatusBadRequest
+	case usecase.ErrAuthInvalidAccessToken:
+		statusCode = http.StatusUnauthorized
+	}
+
+	io.WriteErrorMsg(w, logger, statusCode, err.Error())
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
+)
+
+func newTestFXQuoteRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/fx-quotes", bytes.NewReader([]byte(body)))
+
+	return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+}
+
+func Test_fxQuoteController_Create(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	createdAt := time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		quoteUC    usecase.FXQuoteUseCase
+		r          *http.Request
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			quoteUC: mock.FXQuoteUseCase{
+				OnCreate: func(ctx context.Context, quoteInput usecase.FXQuoteCreateInput) (*usecase.FXQuoteOutput, error) {
+					if quoteInput.AccountID != "uuid-1" || quoteInput.TargetCurrency != "USD" || quoteInput.Amount.Money != 51234 {
+						return nil, errors.New("should pass the caller account and the input")
+					}
+					return &usecase.FXQuoteOutput{
+						ID:             "quote-uuid-1",
+						SourceCurrency: "BRL",
+						TargetCurrency: "USD",
+						Rate:           "0.19518289",
+						SourceAmount:   usecase.NewAmount(51234),
+						TargetAmount:   usecase.NewAmount(10000),
+						CreatedAt:      createdAt,
+						ExpiresAt:      createdAt.Add(30 * time.Second),
+					}, nil
+				},
+			},
+			r:          newTestFXQuoteRequest(`{"target_currency":"USD", "amount":"512.34"}`),
+			wantStatus: 201,
+			want: `{"id":"quote-uuid-1", "source_currency":"BRL", "target_currency":"USD", "rate":"0.19518289", "source_amount":512.34,
+				"target_amount":100, "created_at":"2022-03-01T10:00:00Z", "expires_at":"2022-03-01T10:00:30Z"}`,
+		},
+		{
+			name:       "should return 400 when amount has more than 3 decimal places",
+			quoteUC:    mock.FXQuoteUseCase{},
+			r:          newTestFXQuoteRequest(`{"target_currency":"USD", "amount":"512.3456"}`),
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrAmountInvalid),
+		},
+		{
+			name: "should return 400 when currency is the account one",
+			quoteUC: mock.FXQuoteUseCase{
+				OnCreate: func(ctx context.Context, quoteInput usecase.FXQuoteCreateInput) (*usecase.FXQuoteOutput, error) {
+					return nil, usecase.ErrFXQuoteSameCurrency
+				},
+			},
+			r:          newTestFXQuoteRequest(`{"target_currency":"BRL", "amount":"1"}`),
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrFXQuoteSameCurrency),
+		},
+		{
+			name: "should return 422 when there's no rate",
+			quoteUC: mock.FXQuoteUseCase{
+				OnCreate: func(ctx context.Context, quoteInput usecase.FXQuoteCreateInput) (*usecase.FXQuoteOutput, error) {
+					return nil, repository.ErrFXRateNotFound
+				},
+			},
+			r:          newTestFXQuoteRequest(`{"target_currency":"EUR", "amount":"1"}`),
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, repository.ErrFXRateNotFound),
+		},
+		{
+			name: "should return 500 when usecase error",
+			quoteUC: mock.FXQuoteUseCase{
+				OnCreate: func(ctx context.Context, quoteInput usecase.FXQuoteCreateInput) (*usecase.FXQuoteOutput, error) {
+					return nil, usecase.ErrFXQuoteCreate
+				},
+			},
+			r:          newTestFXQuoteRequest(`{"target_currency":"USD", "amount":"1"}`),
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": %q}`, usecase.ErrFXQuoteCreate),
+		},
+		{
+			name:       "should return 401 when not authenticated",
+			quoteUC:    mock.FXQuoteUseCase{},
+			r:          httptest.NewRequest(http.MethodPost, "/fx-quotes", bytes.NewReader([]byte(`{"target_currency":"USD", "amount":"1"}`))),
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			NewFXQuoteController(tt.quoteUC).Create(rec, tt.r)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Create() statusCode = %v, wantStatus %v", rec.Code, tt.wantStatus)
+			}
+			ja.Assertf(rec.Body.String(), tt.want)
+		})
+	}
+}
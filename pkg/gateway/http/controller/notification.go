@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/hlog"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
+)
+
+// NotificationController is the interface that wraps http handle methods related to the notifications.
+type NotificationController interface {
+	Fetch(w http.ResponseWriter, r *http.Request)
+}
+
+type notificationController struct {
+	ntfUC usecase.NotificationUseCase
+}
+
+// NewNotificationController instantiates a new notification controller.
+func NewNotificationController(ntfUC usecase.NotificationUseCase) NotificationController {
+	return &notificationController{
+		ntfUC: ntfUC,
+	}
+}
+
+// @Summary Fetch notifications
+// @Description Fetch the latest 50 notifications of the current account, the newest first, like the failed standing order payments.
+// @tags Notifications
+// @Produce json
+// @Security Access token
+// @Success 200 {object} []usecase.NotificationOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /notifications [get]
+func (ntfCtrl notificationController) Fetch(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		ntfCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	result, err := ntfCtrl.ntfUC.Fetch(logger.WithContext(r.Context()), principal.AccountID)
+	if err != nil {
+		ntfCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+func (ntfCtrl notificationController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
+	if err == usecase.ErrAuthInvalidAccessToken {
+		statusCode = http.StatusUnauthorized
+	}
+
+	io.WriteErrorMsg(w, logger, statusCode, err.Error())
+}
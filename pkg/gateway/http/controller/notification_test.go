@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
+)
+
+func Test_notificationController_Fetch(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/notifications", nil)
+
+		return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+	}
+
+	type fields struct {
+		ntfUC usecase.NotificationUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "should pass the current account to the usecase",
+			fields: fields{
+				ntfUC: mock.NotificationUseCase{
+					OnFetch: func(ctx context.Context, accountID model.AccountID) ([]usecase.NotificationOutput, error) {
+						if accountID != "uuid-1" {
+							return nil, errors.New("should pass the current account")
+						}
+						return []usecase.NotificationOutput{
+							{
+								ID:          "notification-uuid",
+								Type:        "standing_order_failed",
+								ReferenceID: "order-uuid",
+								Message:     "any message",
+								CreatedAt:   time.Now(),
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 200,
+			want: `[{"id": "notification-uuid", "type": "standing_order_failed", "reference_id": "order-uuid",
+				"message": "any message", "created_at": "<<PRESENCE>>"}]`,
+		},
+		{
+			name: "should return 500 when usecase error",
+			fields: fields{
+				ntfUC: mock.NotificationUseCase{
+					OnFetch: func(ctx context.Context, accountID model.AccountID) ([]usecase.NotificationOutput, error) {
+						return nil, usecase.ErrNotificationFetch
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": %q}`, usecase.ErrNotificationFetch),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				ntfUC: mock.NotificationUseCase{
+					OnFetch: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/notifications", nil),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ntfCtrl := NewNotificationController(tt.fields.ntfUC)
+
+			ntfCtrl.Fetch(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Fetch() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/hlog"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
+)
+
+// PaymentRequestController is the interface that wraps http handle methods related to the payment requests.
+type PaymentRequestController interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Fetch(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Approve(w http.ResponseWriter, r *http.Request)
+	Decline(w http.ResponseWriter, r *http.Request)
+}
+
+type paymentRequestController struct {
+	prUC usecase.PaymentRequestUseCase
+}
+
+// NewPaymentRequestController instantiates a new payment request controller.
+func NewPaymentRequestController(prUC usecase.PaymentRequestUseCase) PaymentRequestController {
+	return &paymentRequestController{
+		prUC: prUC,
+	}
+}
+
+// @Summary Request payment
+// @Description Asks `payer_account_id` to pay the current account the `amount`. Without `payer_account_id`, the request is
+// @Description shared by its `link` and any other account can pay it. It expires at `expires_at`, up to 30 days ahead,
+// @Description or in 7 days when not informed. The payer balance is only checked when it approves the request.
+// @tags Payment requests
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param request body usecase.PaymentRequestCreateInput true "Payment request"
+// @Param X-Idempotency-Key header string false "Idempotency key"
+// @Success 201 {object} usecase.PaymentRequestOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /payment-requests [post]
+func (prCtrl paymentRequestController) Create(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		prCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.PaymentRequestCreateInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding payment request create input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.RequesterAccountID = string(principal.AccountID)
+
+	result, err := prCtrl.prUC.Create(logger.WithContext(r.Context()), input)
+	if err != nil {
+		prCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusCreated, result)
+}
+
+// @Summary Fetch payment requests
+// @Description Fetch the payment requests aimed at the current account or, with `role=requester`, the ones it requested,
+// @Description the newest first. The shared requests are only fetched by their requester.
+// @tags Payment requests
+// @Produce json
+// @Security Access token
+// @Param role query string false "Role of the current account in the requests" Enums(payer, requester)
+// @Param status query string false "Status of the requests" Enums(pending, paid, declined, expired)
+// @Success 200 {object} []usecase.PaymentRequestOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /payment-requests [get]
+func (prCtrl paymentRequestController) Fetch(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		prCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	query := r.URL.Query()
+
+	result, err := prCtrl.prUC.Fetch(logger.WithContext(r.Context()), principal, query.Get("role"), query.Get("status"))
+	if err != nil {
+		prCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Get payment request
+// @Description Gets a payment request of the current account, aimed at it or shared by link.
+// @tags Payment requests
+// @Produce json
+// @Security Access token
+// @Param id path string true "Payment request ID"
+// @Success 200 {object} usecase.PaymentRequestOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /payment-requests/{id} [get]
+func (prCtrl paymentRequestController) Get(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		prCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	params := httprouter.ParamsFromContext(r.Context())
+
+	result, err := prCtrl.prUC.Get(logger.WithContext(r.Context()), principal, model.PaymentRequestID(params.ByName("id")))
+	if err != nil {
+		prCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Approve payment request
+// @Description Pays a pending payment request aimed at the current account, or shared by link, with a transfer to the requester.
+// @Description The transfer follows the rules of `POST /transfers`: when it's rejected, like for insufficient balance,
+// @Description it returns 422 and the request is kept pending.
+// @tags Payment requests
+// @Produce json
+// @Security Access token
+// @Param id path string true "Payment request ID"
+// @Param X-Idempotency-Key header string false "Idempotency key"
+// @Success 200 {object} usecase.PaymentRequestOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /payment-requests/{id}/approve [post]
+func (prCtrl paymentRequestController) Approve(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		prCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	params := httprouter.ParamsFromContext(r.Context())
+
+	result, err := prCtrl.prUC.Approve(logger.WithContext(r.Context()), principal, model.PaymentRequestID(params.ByName("id")))
+	if err != nil {
+		prCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Decline payment request
+// @Description Closes a pending payment request aimed at the current account without paying it.
+// @Description The requests shared by link can't be declined.
+// @tags Payment requests
+// @Produce json
+// @Security Access token
+// @Param id path string true "Payment request ID"
+// @Success 200 {object} usecase.PaymentRequestOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /payment-requests/{id}/decline [post]
+func (prCtrl paymentRequestController) Decline(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		prCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	params := httprouter.ParamsFromContext(r.Context())
+
+	result, err := prCtrl.prUC.Decline(logger.WithContext(r.Context()), principal, model.PaymentRequestID(params.ByName("id")))
+	if err != nil {
+		prCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+func (prCtrl paymentRequestController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
+	switch err {
+	case repository.ErrPaymentRequestNotFound:
+		statusCode = http.StatusNotFound
+	case usecase.ErrPaymentRequestNotPending,
+		usecase.ErrPaymentRequestExpired:
+		statusCode = http.StatusConflict
+	case usecase.ErrPaymentRequestNotPayer:
+		statusCode = http.StatusForbidden
+	case repository.ErrAccountNotFound,
+		usecase.ErrPaymentRequestRequesterNotActive,
+		usecase.ErrPaymentRequestPayerNotActive,
+		usecase.ErrPaymentRequestSharedDecline,
+		usecase.ErrAccountCurrentBalanceInsufficient,
+		usecase.ErrTransferOriginAccountNotActive,
+		usecase.ErrTransferDestinationAccountNotActive,
+		usecase.ErrTransferCurrencyMismatch:
+		statusCode = http.StatusUnprocessableEntity
+	case usecase.ErrPaymentRequestRequesterRequired,
+		usecase.ErrPaymentRequestPayerSelf,
+		usecase.ErrPaymentRequestDescriptionTooLong,
+		usecase.ErrPaymentRequestExpirationInvalid,
+		usecase.ErrPaymentRequestRoleInvalid,
+		usecase.ErrPaymentRequestStatusInvalid,
+		usecase.ErrTransferAmountNotPositive,
+		usecase.ErrAmountInvalid:
+		statusCode = http.StatusBadRequest
+	case usecase.ErrAuthInvalidAccessToken:
+		statusCode = http.StatusUnauthorized
+	}
+
+	if errors.Is(err, usecase.ErrTransferLimitExceeded) {
+		statusCode = http.StatusUnprocessableEntity
+	}
+
+	io.WriteErrorMsg(w, logger, statusCode, err.Error())
+}
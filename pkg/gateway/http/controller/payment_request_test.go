@@ -0,0 +1,538 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
+)
+
+func Test_paymentRequestController_Create(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/payment-requests", bytes.NewReader([]byte(body)))
+
+		return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+	}
+
+	type fields struct {
+		prUC usecase.PaymentRequestUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnCreate: func(ctx context.Context, requestInput usecase.PaymentRequestCreateInput) (*usecase.PaymentRequestOutput, error) {
+						if requestInput.RequesterAccountID != "uuid-1" || requestInput.PayerAccountID != "uuid-2" {
+							return nil, errors.New("should pass the current account as the requester")
+						}
+
+						return &usecase.PaymentRequestOutput{
+							ID:                 "request-uuid",
+							RequesterAccountID: requestInput.RequesterAccountID,
+							PayerAccountID:     requestInput.PayerAccountID,
+							Amount:             requestInput.Amount,
+							Description:        requestInput.Description,
+							ExpiresAt:          time.Now().Add(usecase.PaymentRequestDefaultTTL),
+							Status:             "pending",
+							CreatedAt:          time.Now(),
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"payer_account_id": "uuid-2", "amount": 10.5, "description": "Donuts"}`),
+			},
+			wantStatus: 201,
+			want: `{"id": "request-uuid", "requester_account_id": "uuid-1", "payer_account_id": "uuid-2", "amount": 10.5,
+				"description": "Donuts", "expires_at": "<<PRESENCE>>", "status": "pending", "created_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 400 when expiration is not valid",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnCreate: func(ctx context.Context, requestInput usecase.PaymentRequestCreateInput) (*usecase.PaymentRequestOutput, error) {
+						return nil, usecase.ErrPaymentRequestExpirationInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 10.5, "expires_at": "2020-01-31T09:00:00-03:00"}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrPaymentRequestExpirationInvalid),
+		},
+		{
+			name: "should return 400 when body is not valid",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnCreate: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 10.5, "expires_at": "tomorrow"}`),
+			},
+			wantStatus: 400,
+			want:       `{"code": 400, "message": "error reading input"}`,
+		},
+		{
+			name: "should return 422 when payer is not active",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnCreate: func(ctx context.Context, requestInput usecase.PaymentRequestCreateInput) (*usecase.PaymentRequestOutput, error) {
+						return nil, usecase.ErrPaymentRequestPayerNotActive
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"payer_account_id": "uuid-2", "amount": 10.5}`),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, usecase.ErrPaymentRequestPayerNotActive),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnCreate: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/payment-requests", bytes.NewReader([]byte(`{}`))),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prCtrl := NewPaymentRequestController(tt.fields.prUC)
+
+			prCtrl.Create(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Create() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_paymentRequestController_Fetch(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func(query string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/payment-requests"+query, nil)
+
+		return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+	}
+
+	type fields struct {
+		prUC usecase.PaymentRequestUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "should pass the caller, the role and the status to the usecase",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnFetch: func(ctx context.Context, caller model.Principal, role string, status string) ([]usecase.PaymentRequestOutput, error) {
+						if caller.AccountID != "uuid-1" || role != "requester" || status != "pending" {
+							return nil, errors.New("unexpected filter")
+						}
+						return []usecase.PaymentRequestOutput{
+							{
+								ID:                 "request-uuid",
+								RequesterAccountID: "uuid-1",
+								Amount:             usecase.NewAmount(1050),
+								Status:             "pending",
+								Link:               "/payment-requests/request-uuid",
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest("?role=requester&status=pending"),
+			},
+			wantStatus: 200,
+			want: `[{"id": "request-uuid", "requester_account_id": "uuid-1", "amount": 10.5, "expires_at": "<<PRESENCE>>",
+				"status": "pending", "link": "/payment-requests/request-uuid", "created_at": "<<PRESENCE>>"}]`,
+		},
+		{
+			name: "should return 400 when role is not valid",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnFetch: func(ctx context.Context, caller model.Principal, role string, status string) ([]usecase.PaymentRequestOutput, error) {
+						return nil, usecase.ErrPaymentRequestRoleInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest("?role=owner"),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrPaymentRequestRoleInvalid),
+		},
+		{
+			name: "should return 500 when usecase error",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnFetch: func(ctx context.Context, caller model.Principal, role string, status string) ([]usecase.PaymentRequestOutput, error) {
+						return nil, errors.New("any error")
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(""),
+			},
+			wantStatus: 500,
+			want:       `{"code": 500, "message": "any error"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prCtrl := NewPaymentRequestController(tt.fields.prUC)
+
+			prCtrl.Fetch(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Fetch() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_paymentRequestController_Approve(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/payment-requests/request-uuid/approve", nil)
+		ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "request-uuid"}})
+		ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "uuid-2"})
+
+		return req.WithContext(ctx)
+	}
+
+	type fields struct {
+		prUC usecase.PaymentRequestUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnApprove: func(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error) {
+						if caller.AccountID != "uuid-2" || id != "request-uuid" {
+							return nil, errors.New("should pass the caller and the id")
+						}
+						closedAt := time.Now()
+						return &usecase.PaymentRequestOutput{
+							ID:                 string(id),
+							RequesterAccountID: "uuid-1",
+							PayerAccountID:     "uuid-2",
+							Amount:             usecase.NewAmount(1050),
+							Status:             "paid",
+							TransferID:         "transfer-uuid",
+							ClosedAt:           &closedAt,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 200,
+			want: `{"id": "request-uuid", "requester_account_id": "uuid-1", "payer_account_id": "uuid-2", "amount": 10.5,
+				"expires_at": "<<PRESENCE>>", "status": "paid", "transfer_id": "transfer-uuid", "created_at": "<<PRESENCE>>", "closed_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 404 when not found",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnApprove: func(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error) {
+						return nil, repository.ErrPaymentRequestNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrPaymentRequestNotFound),
+		},
+		{
+			name: "should return 403 when caller is not the payer",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnApprove: func(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error) {
+						return nil, usecase.ErrPaymentRequestNotPayer
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code": 403, "message": %q}`, usecase.ErrPaymentRequestNotPayer),
+		},
+		{
+			name: "should return 409 when already closed",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnApprove: func(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error) {
+						return nil, usecase.ErrPaymentRequestNotPending
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 409,
+			want:       fmt.Sprintf(`{"code": 409, "message": %q}`, usecase.ErrPaymentRequestNotPending),
+		},
+		{
+			name: "should return 422 when balance is insufficient",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnApprove: func(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error) {
+						return nil, usecase.ErrAccountCurrentBalanceInsufficient
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, usecase.ErrAccountCurrentBalanceInsufficient),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnApprove: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/payment-requests/request-uuid/approve", nil),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prCtrl := NewPaymentRequestController(tt.fields.prUC)
+
+			prCtrl.Approve(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Approve() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_paymentRequestController_Decline(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/payment-requests/request-uuid/decline", nil)
+		ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "request-uuid"}})
+		ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "uuid-2"})
+
+		return req.WithContext(ctx)
+	}
+
+	type fields struct {
+		prUC usecase.PaymentRequestUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnDecline: func(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error) {
+						if caller.AccountID != "uuid-2" || id != "request-uuid" {
+							return nil, errors.New("should pass the caller and the id")
+						}
+						closedAt := time.Now()
+						return &usecase.PaymentRequestOutput{
+							ID:                 string(id),
+							RequesterAccountID: "uuid-1",
+							PayerAccountID:     "uuid-2",
+							Amount:             usecase.NewAmount(1050),
+							Status:             "declined",
+							ClosedAt:           &closedAt,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 200,
+			want: `{"id": "request-uuid", "requester_account_id": "uuid-1", "payer_account_id": "uuid-2", "amount": 10.5,
+				"expires_at": "<<PRESENCE>>", "status": "declined", "created_at": "<<PRESENCE>>", "closed_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 422 when request is shared",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnDecline: func(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error) {
+						return nil, usecase.ErrPaymentRequestSharedDecline
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, usecase.ErrPaymentRequestSharedDecline),
+		},
+		{
+			name: "should return 409 when expired",
+			fields: fields{
+				prUC: mock.PaymentRequestUseCase{
+					OnDecline: func(ctx context.Context, caller model.Principal, id model.PaymentRequestID) (*usecase.PaymentRequestOutput, error) {
+						return nil, usecase.ErrPaymentRequestExpired
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 409,
+			want:       fmt.Sprintf(`{"code": 409, "message": %q}`, usecase.ErrPaymentRequestExpired),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prCtrl := NewPaymentRequestController(tt.fields.prUC)
+
+			prCtrl.Decline(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Decline() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/hlog"
+	"github.com/skip2/go-qrcode"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
+)
+
+// PixKeyController is the interface that wraps http handle methods related to the pix keys.
+type PixKeyController interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Verify(w http.ResponseWriter, r *http.Request)
+	Fetch(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Lookup(w http.ResponseWriter, r *http.Request)
+	CreateBRCode(w http.ResponseWriter, r *http.Request)
+}
+
+var errBRCodeFormatInvalid = errors.New("'format' must be json or png")
+
+// brCodeQRCodeSize is the width and height, in pixels, of the BR Code QR Code images.
+const brCodeQRCodeSize = 256
+
+type pixKeyController struct {
+	keyUC usecase.PixKeyUseCase
+}
+
+// NewPixKeyController instantiates a new pix key controller.
+func NewPixKeyController(keyUC usecase.PixKeyUseCase) PixKeyController {
+	return &pixKeyController{
+		keyUC: keyUC,
+	}
+}
+
+// @Summary Register pix key
+// @Description Registers a key to the current account, so it can receive transfers by `destination_key`.
+// @Description The key can be the CPF of the holder (`key` defaults to it), an email address, a phone number
+// @Description in the E.164 format (+5511987654321) or a `random` key, generated. Each account can have up to 5 keys.
+// @Description The email keys are `pending` until verified with the code sent to the address, for 15 minutes.
+// @Description Registering a pending key again sends a new code. The pending keys don't take the value, other accounts
+// @Description can register it too until one of them is verified.
+// @tags Pix keys
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param key body usecase.PixKeyCreateInput true "Pix key"
+// @Success 201 {object} usecase.PixKeyOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /pix-keys [post]
+func (keyCtrl pixKeyController) Create(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		keyCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.PixKeyCreateInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding pix key create input")
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+
+	result, err := keyCtrl.keyUC.Create(logger.WithContext(r.Context()), principal, input)
+	if err != nil {
+		keyCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusCreated, result)
+}
+
+// @Summary Verify pix key
+// @Description Activates a pending key of the current account with the code sent to it.
+// @Description After 3 wrong codes the verification expires and the key must be registered again.
+// @Description The first account verifying the key takes it, the pending keys of the others are dropped.
+// @tags Pix keys
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param key path string true "Pix key"
+// @Param code body usecase.PixKeyVerifyInput true "Verification code"
+// @Success 200 {object} usecase.PixKeyOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /pix-keys/{key}/verify [post]
+func (keyCtrl pixKeyController) Verify(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		keyCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.PixKeyVerifyInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding pix key verify input")
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.Key = httprouter.ParamsFromContext(r.Context()).ByName("key")
+
+	result, err := keyCtrl.keyUC.Verify(logger.WithContext(r.Context()), principal, input)
+	if err != nil {
+		keyCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Fetch pix keys
+// @Description Fetch the keys of the current account, the oldest first.
+// @tags Pix keys
+// @Produce json
+// @Security Access token
+// @Success 200 {object} []usecase.PixKeyOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /pix-keys [get]
+func (keyCtrl pixKeyController) Fetch(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		keyCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	result, err := keyCtrl.keyUC.Fetch(logger.WithContext(r.Context()), principal)
+	if err != nil {
+		keyCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Delete pix key
+// @Description Removes a key of the current account, so it can be registered again.
+// @tags Pix keys
+// @Security Access token
+// @Param key path string true "Pix key"
+// @Success 204
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /pix-keys/{key} [delete]
+func (keyCtrl pixKeyController) Delete(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		keyCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	key := httprouter.ParamsFromContext(r.Context()).ByName("key")
+
+	err := keyCtrl.keyUC.Delete(logger.WithContext(r.Context()), principal, key)
+	if err != nil {
+		keyCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Look up pix key
+// @Description Gets the holder of the account an active key addresses, with the name and the CPF masked,
+// @Description so the sender can confirm the recipient before transferring.
+// @tags Pix keys
+// @Produce json
+// @Security Access token
+// @Param key path string true "Pix key"
+// @Success 200 {object} usecase.PixKeyLookupOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /pix-keys/{key} [get]
+func (keyCtrl pixKeyController) Lookup(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		keyCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	key := httprouter.ParamsFromContext(r.Context()).ByName("key")
+
+	result, err := keyCtrl.keyUC.Lookup(logger.WithContext(r.Context()), principal, key)
+	if err != nil {
+		keyCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Create pix key BR Code
+// @Description Builds the static BR Code of an active key of the current account, the payload of the Pix QR Code
+// @Description the payers scan or paste in `POST /transfers` as `br_code`. Without `amount` the payer chooses it.
+// @Description The merchant name is the holder's, and the city defaults to SPRINGFIELD.
+// @Description With `format=png` the QR Code image is returned instead.
+// @tags Pix keys
+// @Produce json,png
+// @Security Access token
+// @Param key path string true "Pix key"
+// @Param amount query string false "Amount" example(10.50)
+// @Param txid query string false "Identifier of the payment, up to 25 letters and digits" example(DONUTS42)
+// @Param description query string false "Description for the payer"
+// @Param city query string false "Merchant city" example(SPRINGFIELD)
+// @Param format query string false "Response format" Enums(json, png)
+// @Success 200 {object} usecase.BRCodeOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /pix-keys/{key}/brcode [get]
+func (keyCtrl pixKeyController) CreateBRCode(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		keyCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "png" {
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, errBRCodeFormatInvalid.Error())
+		return
+	}
+
+	input := usecase.BRCodeCreateInput{
+		Key:         httprouter.ParamsFromContext(r.Context()).ByName("key"),
+		TxID:        query.Get("txid"),
+		Description: query.Get("description"),
+		City:        query.Get("city"),
+	}
+	amount, err := parseAmountParam(query.Get("amount"))
+	if err != nil {
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+		return
+	}
+	if amount != nil {
+		input.Amount = *amount
+	}
+
+	result, err := keyCtrl.keyUC.CreateBRCode(logger.WithContext(r.Context()), principal, input)
+	if err != nil {
+		keyCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	if format != "png" {
+		io.WriteSuccess(w, r, logger, http.StatusOK, result)
+		return
+	}
+
+	png, err := qrcode.Encode(result.Payload, qrcode.Medium, brCodeQRCodeSize)
+	if err != nil {
+		logger.Error().Stack().Err(err).Msg("error encoding BR Code QR Code")
+		keyCtrl.writeError(w, logger, http.StatusInternalServerError, usecase.ErrBRCodeCreate)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(png); err != nil {
+		logger.Error().Stack().Err(err).Msg("error writing BR Code QR Code")
+	}
+}
+
+func (keyCtrl pixKeyController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
+	switch err {
+	case repository.ErrPixKeyNotFound:
+		statusCode = http.StatusNotFound
+	case repository.ErrPixKeyAlreadyExists,
+		usecase.ErrPixKeyAlreadyVerified:
+		statusCode = http.StatusConflict
+	case repository.ErrAccountNotFound,
+		repository.ErrPixKeyLimitReached,
+		usecase.ErrPixKeyAccountNotActive,
+		usecase.ErrPixKeyVerificationCodeWrong,
+		usecase.ErrPixKeyVerificationExpired:
+		statusCode = http.StatusUnprocessableEntity
+	case usecase.ErrPixKeyTypeInvalid,
+		usecase.ErrPixKeyValueInvalid,
+		usecase.ErrPixKeyRandomValue,
+		usecase.ErrPixKeyCPFNotOwn,
+		usecase.ErrPixKeyVerificationCodeRequired,
+		usecase.ErrBRCodeAmountNegative,
+		usecase.ErrBRCodeTxIDInvalid,
+		usecase.ErrBRCodeDescriptionTooLong,
+		usecase.ErrAmountInvalid:
+		statusCode = http.StatusBadRequest
+	case usecase.ErrAuthInvalidAccessToken:
+		statusCode = http.StatusUnauthorized
+	}
+
+	io.WriteErrorMsg(w, logger, statusCode, err.Error())
+}
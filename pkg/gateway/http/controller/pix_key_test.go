@@ -0,0 +1,520 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
+)
+
+func newTestPixKeyRequest(method string, path string, key string, body string) *http.Request {
+	req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "key", Value: key}})
+	ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "uuid-1"})
+
+	return req.WithContext(ctx)
+}
+
+func Test_pixKeyController_Create(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	expiresAt := time.Date(2022, time.March, 1, 10, 15, 0, 0, time.UTC)
+	createdAt := time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		keyUC      usecase.PixKeyUseCase
+		r          *http.Request
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			keyUC: mock.PixKeyUseCase{
+				OnCreate: func(ctx context.Context, caller model.Principal, createInput usecase.PixKeyCreateInput) (*usecase.PixKeyOutput, error) {
+					if caller.AccountID != "uuid-1" || createInput.Type != "email" || createInput.Key != "homer@springfield.com" {
+						return nil, errors.New("should pass the caller and the key")
+					}
+					return &usecase.PixKeyOutput{
+						Type:                  createInput.Type,
+						Key:                   createInput.Key,
+						Status:                "pending",
+						VerificationExpiresAt: &expiresAt,
+						CreatedAt:             createdAt,
+					}, nil
+				},
+			},
+			r:          newTestPixKeyRequest(http.MethodPost, "/pix-keys", "", `{"type":"email", "key":"homer@springfield.com"}`),
+			wantStatus: 201,
+			want:       `{"type":"email", "key":"homer@springfield.com", "status":"pending", "verification_expires_at":"2022-03-01T10:15:00Z", "created_at":"2022-03-01T10:00:00Z"}`,
+		},
+		{
+			name:       "should return 400 when input is invalid",
+			keyUC:      mock.PixKeyUseCase{},
+			r:          newTestPixKeyRequest(http.MethodPost, "/pix-keys", "", `{"type":`),
+			wantStatus: 400,
+			want:       `{"code": 400, "message": "error reading input"}`,
+		},
+		{
+			name: "should return 400 when type is invalid",
+			keyUC: mock.PixKeyUseCase{
+				OnCreate: func(ctx context.Context, caller model.Principal, createInput usecase.PixKeyCreateInput) (*usecase.PixKeyOutput, error) {
+					return nil, usecase.ErrPixKeyTypeInvalid
+				},
+			},
+			r:          newTestPixKeyRequest(http.MethodPost, "/pix-keys", "", `{"type":"iban"}`),
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrPixKeyTypeInvalid),
+		},
+		{
+			name: "should return 409 when key already exists",
+			keyUC: mock.PixKeyUseCase{
+				OnCreate: func(ctx context.Context, caller model.Principal, createInput usecase.PixKeyCreateInput) (*usecase.PixKeyOutput, error) {
+					return nil, repository.ErrPixKeyAlreadyExists
+				},
+			},
+			r:          newTestPixKeyRequest(http.MethodPost, "/pix-keys", "", `{"type":"phone", "key":"+5511987654321"}`),
+			wantStatus: 409,
+			want:       fmt.Sprintf(`{"code": 409, "message": %q}`, repository.ErrPixKeyAlreadyExists),
+		},
+		{
+			name: "should return 422 when limit reached",
+			keyUC: mock.PixKeyUseCase{
+				OnCreate: func(ctx context.Context, caller model.Principal, createInput usecase.PixKeyCreateInput) (*usecase.PixKeyOutput, error) {
+					return nil, repository.ErrPixKeyLimitReached
+				},
+			},
+			r:          newTestPixKeyRequest(http.MethodPost, "/pix-keys", "", `{"type":"random"}`),
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, repository.ErrPixKeyLimitReached),
+		},
+		{
+			name: "should return 500 when usecase error",
+			keyUC: mock.PixKeyUseCase{
+				OnCreate: func(ctx context.Context, caller model.Principal, createInput usecase.PixKeyCreateInput) (*usecase.PixKeyOutput, error) {
+					return nil, usecase.ErrPixKeyCreate
+				},
+			},
+			r:          newTestPixKeyRequest(http.MethodPost, "/pix-keys", "", `{"type":"random"}`),
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": %q}`, usecase.ErrPixKeyCreate),
+		},
+		{
+			name:       "should return 401 when not authenticated",
+			keyUC:      mock.PixKeyUseCase{},
+			r:          httptest.NewRequest(http.MethodPost, "/pix-keys", bytes.NewReader([]byte(`{"type":"random"}`))),
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			NewPixKeyController(tt.keyUC).Create(rec, tt.r)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Create() statusCode = %v, wantStatus %v", rec.Code, tt.wantStatus)
+			}
+			ja.Assertf(rec.Body.String(), tt.want)
+		})
+	}
+}
+
+func Test_pixKeyController_Verify(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	createdAt := time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		keyUC      usecase.PixKeyUseCase
+		r          *http.Request
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			keyUC: mock.PixKeyUseCase{
+				OnVerify: func(ctx context.Context, caller model.Principal, verifyInput usecase.PixKeyVerifyInput) (*usecase.PixKeyOutput, error) {
+					if caller.AccountID != "uuid-1" || verifyInput.Key != "homer@springfield.com" || verifyInput.Code != "123456" {
+						return nil, errors.New("should pass the caller, the key and the code")
+					}
+					return &usecase.PixKeyOutput{Type: "email", Key: verifyInput.Key, Status: "active", CreatedAt: createdAt}, nil
+				},
+			},
+			r:          newTestPixKeyRequest(http.MethodPost, "/pix-keys/homer@springfield.com/verify", "homer@springfield.com", `{"code":"123456"}`),
+			wantStatus: 200,
+			want:       `{"type":"email", "key":"homer@springfield.com", "status":"active", "created_at":"2022-03-01T10:00:00Z"}`,
+		},
+		{
+			name: "should return 404 when key not found",
+			keyUC: mock.PixKeyUseCase{
+				OnVerify: func(ctx context.Context, caller model.Principal, verifyInput usecase.PixKeyVerifyInput) (*usecase.PixKeyOutput, error) {
+					return nil, repository.ErrPixKeyNotFound
+				},
+			},
+			r:          newTestPixKeyRequest(http.MethodPost, "/pix-keys/homer@springfield.com/verify", "homer@springfield.com", `{"code":"123456"}`),
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrPixKeyNotFound),
+		},
+		{
+			name: "should return 409 when already verified",
+			keyUC: mock.PixKeyUseCase{
+				OnVerify: func(ctx context.Context, caller model.Principal, verifyInput usecase.PixKeyVerifyInput) (*usecase.PixKeyOutput, error) {
+					return nil, usecase.ErrPixKeyAlreadyVerified
+				},
+			},
+			r:          newTestPixKeyRequest(http.MethodPost, "/pix-keys/homer@springfield.com/verify", "homer@springfield.com", `{"code":"123456"}`),
+			wantStatus: 409,
+			want:       fmt.Sprintf(`{"code": 409, "message": %q}`, usecase.ErrPixKeyAlreadyVerified),
+		},
+		{
+			name: "should return 422 when code is wrong",
+			keyUC: mock.PixKeyUseCase{
+				OnVerify: func(ctx context.Context, caller model.Principal, verifyInput usecase.PixKeyVerifyInput) (*usecase.PixKeyOutput, error) {
+					return nil, usecase.ErrPixKeyVerificationCodeWrong
+				},
+			},
+			r:          newTestPixKeyRequest(http.MethodPost, "/pix-keys/homer@springfield.com/verify", "homer@springfield.com", `{"code":"654321"}`),
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, usecase.ErrPixKeyVerificationCodeWrong),
+		},
+		{
+			name: "should return 400 when code is blank",
+			keyUC: mock.PixKeyUseCase{
+				OnVerify: func(ctx context.Context, caller model.Principal, verifyInput usecase.PixKeyVerifyInput) (*usecase.PixKeyOutput, error) {
+					return nil, usecase.ErrPixKeyVerificationCodeRequired
+				},
+			},
+			r:          newTestPixKeyRequest(http.MethodPost, "/pix-keys/homer@springfield.com/verify", "homer@springfield.com", `{}`),
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrPixKeyVerificationCodeRequired),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			NewPixKeyController(tt.keyUC).Verify(rec, tt.r)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Verify() statusCode = %v, wantStatus %v", rec.Code, tt.wantStatus)
+			}
+			ja.Assertf(rec.Body.String(), tt.want)
+		})
+	}
+}
+
+func Test_pixKeyController_Fetch(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	createdAt := time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		keyUC      usecase.PixKeyUseCase
+		r          *http.Request
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			keyUC: mock.PixKeyUseCase{
+				OnFetch: func(ctx context.Context, caller model.Principal) ([]usecase.PixKeyOutput, error) {
+					if caller.AccountID != "uuid-1" {
+						return nil, errors.New("should pass the caller")
+					}
+					return []usecase.PixKeyOutput{{Type: "cpf", Key: "59951332099", Status: "active", CreatedAt: createdAt}}, nil
+				},
+			},
+			r:          newTestPixKeyRequest(http.MethodGet, "/pix-keys", "", ""),
+			wantStatus: 200,
+			want:       `[{"type":"cpf", "key":"59951332099", "status":"active", "created_at":"2022-03-01T10:00:00Z"}]`,
+		},
+		{
+			name: "should return 500 when usecase error",
+			keyUC: mock.PixKeyUseCase{
+				OnFetch: func(ctx context.Context, caller model.Principal) ([]usecase.PixKeyOutput, error) {
+					return nil, usecase.ErrPixKeyFetch
+				},
+			},
+			r:          newTestPixKeyRequest(http.MethodGet, "/pix-keys", "", ""),
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": %q}`, usecase.ErrPixKeyFetch),
+		},
+		{
+			name:       "should return 401 when not authenticated",
+			keyUC:      mock.PixKeyUseCase{},
+			r:          httptest.NewRequest(http.MethodGet, "/pix-keys", nil),
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			NewPixKeyController(tt.keyUC).Fetch(rec, tt.r)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Fetch() statusCode = %v, wantStatus %v", rec.Code, tt.wantStatus)
+			}
+			ja.Assertf(rec.Body.String(), tt.want)
+		})
+	}
+}
+
+func Test_pixKeyController_Delete(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	tests := []struct {
+		name       string
+		keyUC      usecase.PixKeyUseCase
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			keyUC: mock.PixKeyUseCase{
+				OnDelete: func(ctx context.Context, caller model.Principal, key string) error {
+					if caller.AccountID != "uuid-1" || key != "+5511987654321" {
+						return errors.New("should pass the caller and the key")
+					}
+					return nil
+				},
+			},
+			wantStatus: 204,
+		},
+		{
+			name: "should return 404 when key not found",
+			keyUC: mock.PixKeyUseCase{
+				OnDelete: func(ctx context.Context, caller model.Principal, key string) error {
+					return repository.ErrPixKeyNotFound
+				},
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrPixKeyNotFound),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			NewPixKeyController(tt.keyUC).Delete(rec, newTestPixKeyRequest(http.MethodDelete, "/pix-keys/+5511987654321", "+5511987654321", ""))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Delete() statusCode = %v, wantStatus %v", rec.Code, tt.wantStatus)
+			}
+			if tt.want != "" {
+				ja.Assertf(rec.Body.String(), tt.want)
+			} else if rec.Body.Len() != 0 {
+				t.Errorf("Delete() body = %v, want empty", rec.Body.String())
+			}
+		})
+	}
+}
+
+func Test_pixKeyController_Lookup(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	tests := []struct {
+		name       string
+		keyUC      usecase.PixKeyUseCase
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			keyUC: mock.PixKeyUseCase{
+				OnLookup: func(ctx context.Context, caller model.Principal, key string) (*usecase.PixKeyLookupOutput, error) {
+					if caller.AccountID != "uuid-1" || key != "+5511987654321" {
+						return nil, errors.New("should pass the caller and the key")
+					}
+					return &usecase.PixKeyLookupOutput{Type: "phone", Key: key, Name: "Homer J. S.", CPF: "***.513.320-**"}, nil
+				},
+			},
+			wantStatus: 200,
+			want:       `{"type":"phone", "key":"+5511987654321", "name":"Homer J. S.", "cpf":"***.513.320-**"}`,
+		},
+		{
+			name: "should return 404 when key not found",
+			keyUC: mock.PixKeyUseCase{
+				OnLookup: func(ctx context.Context, caller model.Principal, key string) (*usecase.PixKeyLookupOutput, error) {
+					return nil, repository.ErrPixKeyNotFound
+				},
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrPixKeyNotFound),
+		},
+		{
+			name: "should return 500 when usecase error",
+			keyUC: mock.PixKeyUseCase{
+				OnLookup: func(ctx context.Context, caller model.Principal, key string) (*usecase.PixKeyLookupOutput, error) {
+					return nil, usecase.ErrPixKeyLookup
+				},
+			},
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": %q}`, usecase.ErrPixKeyLookup),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			NewPixKeyController(tt.keyUC).Lookup(rec, newTestPixKeyRequest(http.MethodGet, "/pix-keys/+5511987654321", "+5511987654321", ""))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Lookup() statusCode = %v, wantStatus %v", rec.Code, tt.wantStatus)
+			}
+			ja.Assertf(rec.Body.String(), tt.want)
+		})
+	}
+}
+
+func Test_pixKeyController_CreateBRCode(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	onCreateBRCode := func(ctx context.Context, caller model.Principal, brCodeInput usecase.BRCodeCreateInput) (*usecase.BRCodeOutput, error) {
+		if caller.AccountID != "uuid-1" || brCodeInput.Key != "homer@springfield.com" {
+			return nil, errors.New("should pass the caller and the key")
+		}
+		output := &usecase.BRCodeOutput{Payload: "000201...6304ABCD", Key: brCodeInput.Key, TxID: brCodeInput.TxID}
+		if brCodeInput.Amount.Money > 0 {
+			output.Amount = &brCodeInput.Amount
+		}
+		return output, nil
+	}
+
+	tests := []struct {
+		name            string
+		keyUC           usecase.PixKeyUseCase
+		path            string
+		wantStatus      int
+		wantContentType string
+		want            string
+	}{
+		{
+			name:            "successful",
+			keyUC:           mock.PixKeyUseCase{OnCreateBRCode: onCreateBRCode},
+			path:            "/pix-keys/homer@springfield.com/brcode?amount=10.50&txid=DONUTS42",
+			wantStatus:      200,
+			wantContentType: "application/json",
+			want:            `{"payload":"000201...6304ABCD", "key":"homer@springfield.com", "amount":10.5, "txid":"DONUTS42"}`,
+		},
+		{
+			name:            "successful without amount",
+			keyUC:           mock.PixKeyUseCase{OnCreateBRCode: onCreateBRCode},
+			path:            "/pix-keys/homer@springfield.com/brcode?format=json",
+			wantStatus:      200,
+			wantContentType: "application/json",
+			want:            `{"payload":"000201...6304ABCD", "key":"homer@springfield.com"}`,
+		},
+		{
+			name:            "successful png",
+			keyUC:           mock.PixKeyUseCase{OnCreateBRCode: onCreateBRCode},
+			path:            "/pix-keys/homer@springfield.com/brcode?format=png",
+			wantStatus:      200,
+			wantContentType: "image/png",
+		},
+		{
+			name:            "should return 400 when format invalid",
+			keyUC:           mock.PixKeyUseCase{},
+			path:            "/pix-keys/homer@springfield.com/brcode?format=svg",
+			wantStatus:      400,
+			wantContentType: "application/json",
+			want:            fmt.Sprintf(`{"code": 400, "message": %q}`, errBRCodeFormatInvalid),
+		},
+		{
+			name:            "should return 400 when amount invalid",
+			keyUC:           mock.PixKeyUseCase{},
+			path:            "/pix-keys/homer@springfield.com/brcode?amount=ten",
+			wantStatus:      400,
+			wantContentType: "application/json",
+			want:            fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrAmountInvalid),
+		},
+		{
+			name: "should return 400 when txid invalid",
+			keyUC: mock.PixKeyUseCase{
+				OnCreateBRCode: func(ctx context.Context, caller model.Principal, brCodeInput usecase.BRCodeCreateInput) (*usecase.BRCodeOutput, error) {
+					return nil, usecase.ErrBRCodeTxIDInvalid
+				},
+			},
+			path:            "/pix-keys/homer@springfield.com/brcode?txid=donuts-42",
+			wantStatus:      400,
+			wantContentType: "application/json",
+			want:            fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrBRCodeTxIDInvalid),
+		},
+		{
+			name: "should return 404 when key not found",
+			keyUC: mock.PixKeyUseCase{
+				OnCreateBRCode: func(ctx context.Context, caller model.Principal, brCodeInput usecase.BRCodeCreateInput) (*usecase.BRCodeOutput, error) {
+					return nil, repository.ErrPixKeyNotFound
+				},
+			},
+			path:            "/pix-keys/homer@springfield.com/brcode",
+			wantStatus:      404,
+			wantContentType: "application/json",
+			want:            fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrPixKeyNotFound),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			NewPixKeyController(tt.keyUC).CreateBRCode(rec, newTestPixKeyRequest(http.MethodGet, tt.path, "homer@springfield.com", ""))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("CreateBRCode() statusCode = %v, wantStatus %v", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.wantContentType) {
+				t.Errorf("CreateBRCode() Content-Type = %v, want %v", got, tt.wantContentType)
+			}
+			if tt.wantContentType == "image/png" {
+				if !bytes.HasPrefix(rec.Body.Bytes(), []byte("\x89PNG")) {
+					t.Errorf("CreateBRCode() body is not a PNG image")
+				}
+				return
+			}
+			ja.Assertf(rec.Body.String(), tt.want)
+		})
+	}
+}
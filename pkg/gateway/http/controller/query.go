@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"errors"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+)
+
+var errPeriodTimeInvalid = errors.New("'from' and 'to' must be RFC 3339 timestamps or dates (YYYY-MM-DD)")
+
+// parsePeriodTime parses a RFC 3339 timestamp or a UTC date from the query string. As the period end excludes it,
+// a date ending the period is moved to the next day, so the whole day is included.
+func parsePeriodTime(value string, periodEnd bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err == nil {
+		if periodEnd {
+			date = date.AddDate(0, 0, 1)
+		}
+		return date, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+var errDateInvalid = errors.New("'from' and 'to' must be dates (YYYY-MM-DD)")
+
+// parseDateParam parses a date, like "2021-01-31", from the query string. It returns the zero time if the value is empty.
+func parseDateParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse("2006-01-02", value)
+}
+
+// parseAmountParam parses a decimal amount, like "1234.56", from the query string. It returns nil if the value is empty.
+func parseAmountParam(value string) (*usecase.Amount, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	amount, err := usecase.ParseAmount(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &amount, nil
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/hlog"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
+)
+
+// SavingsController is the interface that wraps http handle methods related to the savings interest.
+type SavingsController interface {
+	GetHistory(w http.ResponseWriter, r *http.Request)
+}
+
+type savingsController struct {
+	savUC usecase.SavingsUseCase
+}
+
+// NewSavingsController instantiates a new savings controller.
+func NewSavingsController(savUC usecase.SavingsUseCase) SavingsController {
+	return &savingsController{
+		savUC: savUC,
+	}
+}
+
+// @Summary Get savings interest history
+// @Description Get the interest accrued on the balance of the account on each business day of the period, and the interest paid for the months of the period. Only the account owner can get it.
+// @Description The interest of a day has all its decimal places, the payments are the whole cents accrued, and the fractions are carried to the next month.
+// @Description `from` and `to` are dates (YYYY-MM-DD) and the period includes both. By default, it's the current month so far.
+// @tags Accounts
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param id path string true "Account ID"
+// @Param from query string false "Period start" example(2021-01-01)
+// @Param to query string false "Period end" example(2021-01-31)
+// @Success 200 {object} usecase.SavingsHistoryOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /accounts/{id}/savings-interest [get]
+func (savCtrl savingsController) GetHistory(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	caller, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		savCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := parseDateParam(query.Get("from"))
+	if err != nil {
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, errDateInvalid.Error())
+		return
+	}
+	to, err := parseDateParam(query.Get("to"))
+	if err != nil {
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, errDateInvalid.Error())
+		return
+	}
+
+	input := usecase.SavingsHistoryInput{
+		AccountID: model.AccountID(httprouter.ParamsFromContext(r.Context()).ByName("id")),
+		From:      from,
+		To:        to,
+	}
+
+	result, err := savCtrl.savUC.GetHistory(logger.WithContext(r.Context()), caller, input)
+	if err != nil {
+		savCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+func (savCtrl savingsController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
+	switch err {
+	case repository.ErrAccountNotFound:
+		statusCode = http.StatusNotFound
+	case usecase.ErrSavingsHistoryPeriodInvalid:
+		statusCode = http.StatusBadRequest
+	case usecase.ErrAuthInvalidAccessToken:
+		statusCode = http.StatusUnauthorized
+	case usecase.ErrAuthForbidden:
+		statusCode = http.StatusForbidden
+	}
+
+	io.WriteErrorMsg(w, logger, statusCode, err.Error())
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
+)
+
+func newTestSavingsHistoryRequest(query string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/savings-interest"+query, nil)
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "uuid-1"}})
+	ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "uuid-1"})
+
+	return req.WithContext(ctx)
+}
+
+func Test_savingsController_GetHistory(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	paidAt := time.Date(2021, 2, 1, 3, 1, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		savUC      usecase.SavingsUseCase
+		r          *http.Request
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			savUC: mock.SavingsUseCase{
+				OnGetHistory: func(ctx context.Context, caller model.Principal, historyInput usecase.SavingsHistoryInput) (*usecase.SavingsHistoryOutput, error) {
+					wantFrom := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+					wantTo := time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC)
+					if caller.AccountID != "uuid-1" || historyInput.AccountID != "uuid-1" || !historyInput.From.Equal(wantFrom) || !historyInput.To.Equal(wantTo) {
+						return nil, fmt.Errorf("unexpected input %+v", historyInput)
+					}
+
+					return &usecase.SavingsHistoryOutput{
+						AccountID:       "uuid-1",
+						AnnualRate:      "13.65%",
+						From:            "2021-01-01",
+						To:              "2021-01-31",
+						AccruedInterest: "5.0788000000",
+						Accruals: []usecase.SavingsAccrualOutput{
+							{Day: "2021-01-04", Balance: usecase.NewAmount(1000000), DailyRate: "0.00050788", Interest: "5.0788000000"},
+						},
+						Payments: []usecase.SavingsPaymentOutput{
+							{Month: "2021-01", Amount: usecase.NewAmount(507), PaidAt: paidAt},
+						},
+					}, nil
+				},
+			},
+			r:          newTestSavingsHistoryRequest("?from=2021-01-01&to=2021-01-31"),
+			wantStatus: 200,
+			want: `{
+				"account_id":"uuid-1",
+				"annual_rate":"13.65%%",
+				"from":"2021-01-01",
+				"to":"2021-01-31",
+				"accrued_interest":"5.0788000000",
+				"accruals":[{"day":"2021-01-04", "balance":10000, "daily_rate":"0.00050788", "interest":"5.0788000000"}],
+				"payments":[{"month":"2021-01", "amount":5.07, "paid_at":"2021-02-01T03:01:00Z"}]
+			}`,
+		},
+		{
+			name: "should return 400 when date is invalid",
+			savUC: mock.SavingsUseCase{
+				OnGetHistory: nil,
+			},
+			r:          newTestSavingsHistoryRequest("?to=2021-01-31T09:00:00-03:00"),
+			wantStatus: 400,
+			want:       `{"code":400, "message":"'from' and 'to' must be dates (YYYY-MM-DD)"}`,
+		},
+		{
+			name: "should return 400 when period is invalid",
+			savUC: mock.SavingsUseCase{
+				OnGetHistory: func(ctx context.Context, caller model.Principal, historyInput usecase.SavingsHistoryInput) (*usecase.SavingsHistoryOutput, error) {
+					return nil, usecase.ErrSavingsHistoryPeriodInvalid
+				},
+			},
+			r:          newTestSavingsHistoryRequest("?from=2021-02-01&to=2021-01-01"),
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code":400, "message":%q}`, usecase.ErrSavingsHistoryPeriodInvalid),
+		},
+		{
+			name: "should return 403 when not the owner",
+			savUC: mock.SavingsUseCase{
+				OnGetHistory: func(ctx context.Context, caller model.Principal, historyInput usecase.SavingsHistoryInput) (*usecase.SavingsHistoryOutput, error) {
+					return nil, usecase.ErrAuthForbidden
+				},
+			},
+			r:          newTestSavingsHistoryRequest(""),
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code":403, "message":%q}`, usecase.ErrAuthForbidden),
+		},
+		{
+			name: "should return 404 when account not found",
+			savUC: mock.SavingsUseCase{
+				OnGetHistory: func(ctx context.Context, caller model.Principal, historyInput usecase.SavingsHistoryInput) (*usecase.SavingsHistoryOutput, error) {
+					return nil, repository.ErrAccountNotFound
+				},
+			},
+			r:          newTestSavingsHistoryRequest(""),
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code":404, "message":%q}`, repository.ErrAccountNotFound),
+		},
+		{
+			name: "should return 500 when get fails",
+			savUC: mock.SavingsUseCase{
+				OnGetHistory: func(ctx context.Context, caller model.Principal, historyInput usecase.SavingsHistoryInput) (*usecase.SavingsHistoryOutput, error) {
+					return nil, usecase.ErrSavingsGetHistory
+				},
+			},
+			r:          newTestSavingsHistoryRequest(""),
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code":500, "message":%q}`, usecase.ErrSavingsGetHistory),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			savUC: mock.SavingsUseCase{
+				OnGetHistory: nil,
+			},
+			r:          httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/savings-interest", nil),
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code":401, "message":%q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			savCtrl := NewSavingsController(tt.savUC)
+
+			rec := httptest.NewRecorder()
+			savCtrl.GetHistory(rec, tt.r)
+
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("GetHistory() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			ja.Assertf(rec.Body.String(), tt.want)
+		})
+	}
+}
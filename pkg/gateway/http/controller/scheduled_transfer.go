@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/hlog"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
+)
+
+// ScheduledTransferController is the interface that wraps http handle methods related to the scheduled transfers.
+type ScheduledTransferController interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Fetch(w http.ResponseWriter, r *http.Request)
+	Cancel(w http.ResponseWriter, r *http.Request)
+}
+
+type scheduledTransferController struct {
+	schUC usecase.ScheduledTransferUseCase
+}
+
+// NewScheduledTransferController instantiates a new scheduled transfer controller.
+func NewScheduledTransferController(schUC usecase.ScheduledTransferUseCase) ScheduledTransferController {
+	return &scheduledTransferController{
+		schUC: schUC,
+	}
+}
+
+// @Summary Schedule transfer
+// @Description Schedules a transfer from the current account to another, executed at `scheduled_for`, up to one year ahead.
+// @Description The balance is only checked at the execution: if it's insufficient, the scheduled transfer fails.
+// @tags Scheduled transfers
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param schedule body usecase.ScheduledTransferCreateInput true "Scheduled transfer"
+// @Param X-Idempotency-Key header string false "Idempotency key"
+// @Success 201 {object} usecase.ScheduledTransferOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /scheduled-transfers [post]
+func (schCtrl scheduledTransferController) Create(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		schCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.ScheduledTransferCreateInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding scheduled transfer create input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.AccountOriginID = string(principal.AccountID)
+
+	result, err := schCtrl.schUC.Create(logger.WithContext(r.Context()), input)
+	if err != nil {
+		schCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusCreated, result)
+}
+
+// @Summary Fetch scheduled transfers
+// @Description Fetch the scheduled transfers of the current account, the next ones first.
+// @tags Scheduled transfers
+// @Produce json
+// @Security Access token
+// @Param status query string false "Status of the scheduled transfers" Enums(scheduled, executed, failed, cancelled)
+// @Success 200 {object} []usecase.ScheduledTransferOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /scheduled-transfers [get]
+func (schCtrl scheduledTransferController) Fetch(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		schCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	result, err := schCtrl.schUC.Fetch(logger.WithContext(r.Context()), principal.AccountID, r.URL.Query().Get("status"))
+	if err != nil {
+		schCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Cancel scheduled transfer
+// @Description Cancels a scheduled transfer of the current account that was not executed yet.
+// @tags Scheduled transfers
+// @Produce json
+// @Security Access token
+// @Param id path string true "Scheduled transfer ID"
+// @Success 200 {object} usecase.ScheduledTransferOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /scheduled-transfers/{id}/cancel [post]
+func (schCtrl scheduledTransferController) Cancel(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		schCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	params := httprouter.ParamsFromContext(r.Context())
+
+	result, err := schCtrl.schUC.Cancel(logger.WithContext(r.Context()), principal, model.ScheduledTransferID(params.ByName("id")))
+	if err != nil {
+		schCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+func (schCtrl scheduledTransferController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
+	switch err {
+	case repository.ErrScheduledTransferNotFound:
+		statusCode = http.StatusNotFound
+	case usecase.ErrScheduledTransferNotPending:
+		statusCode = http.StatusConflict
+	case repository.ErrAccountNotFound,
+		usecase.ErrTransferOriginAccountNotActive,
+		usecase.ErrTransferDestinationAccountNotActive,
+		usecase.ErrTransferCurrencyMismatch:
+		statusCode = http.StatusUnprocessableEntity
+	case usecase.ErrTransferOriginAccountRequired,
+		usecase.ErrTransferDestinationAccountRequired,
+		usecase.ErrTransferAmountNotPositive,
+		usecase.ErrTransferSameAccount,
+		usecase.ErrScheduledTransferDateInvalid,
+		usecase.ErrScheduledTransferStatusInvalid,
+		usecase.ErrAmountInvalid:
+		statusCode = http.StatusBadRequest
+	case usecase.ErrAuthInvalidAccessToken:
+		statusCode = http.StatusUnauthorized
+	}
+
+	io.WriteErrorMsg(w, logger, statusCode, err.Error())
+}
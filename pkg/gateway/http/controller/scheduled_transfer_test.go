@@ -0,0 +1,408 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
+)
+
+func Test_scheduledTransferController_Create(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/scheduled-transfers", bytes.NewReader([]byte(body)))
+
+		return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+	}
+
+	type fields struct {
+		schUC usecase.ScheduledTransferUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnCreate: func(ctx context.Context, scheduleInput usecase.ScheduledTransferCreateInput) (*usecase.ScheduledTransferOutput, error) {
+						if scheduleInput.AccountOriginID != "uuid-1" || !scheduleInput.ScheduledFor.Equal(time.Date(2021, 1, 31, 12, 0, 0, 0, time.UTC)) {
+							return nil, errors.New("should pass the current account and the date")
+						}
+
+						return &usecase.ScheduledTransferOutput{
+							ID:                   "schedule-uuid",
+							AccountOriginID:      scheduleInput.AccountOriginID,
+							AccountDestinationID: scheduleInput.AccountDestinationID,
+							Amount:               scheduleInput.Amount,
+							ScheduledFor:         scheduleInput.ScheduledFor,
+							Status:               "scheduled",
+							CreatedAt:            time.Now(),
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"account_destination_id": "uuid-2", "amount": 10.5, "scheduled_for": "2021-01-31T09:00:00-03:00"}`),
+			},
+			wantStatus: 201,
+			want: `{"id": "schedule-uuid", "account_origin_id": "uuid-1", "account_destination_id": "uuid-2", "amount": 10.5,
+				"scheduled_for": "2021-01-31T09:00:00-03:00", "status": "scheduled", "created_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 400 when date is not valid",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnCreate: func(ctx context.Context, scheduleInput usecase.ScheduledTransferCreateInput) (*usecase.ScheduledTransferOutput, error) {
+						return nil, usecase.ErrScheduledTransferDateInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"account_destination_id": "uuid-2", "amount": 10.5, "scheduled_for": "2021-01-31T09:00:00-03:00"}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrScheduledTransferDateInvalid),
+		},
+		{
+			name: "should return 400 when body is not valid",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnCreate: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"account_destination_id": "uuid-2", "amount": 10.5, "scheduled_for": "tomorrow"}`),
+			},
+			wantStatus: 400,
+			want:       `{"code": 400, "message": "error reading input"}`,
+		},
+		{
+			name: "should return 422 when destination account not found",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnCreate: func(ctx context.Context, scheduleInput usecase.ScheduledTransferCreateInput) (*usecase.ScheduledTransferOutput, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"account_destination_id": "uuid-2", "amount": 10.5, "scheduled_for": "2021-01-31T09:00:00-03:00"}`),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, repository.ErrAccountNotFound),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnCreate: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/scheduled-transfers", bytes.NewReader([]byte(`{}`))),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schCtrl := NewScheduledTransferController(tt.fields.schUC)
+
+			schCtrl.Create(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Create() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_scheduledTransferController_Fetch(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func(query string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/scheduled-transfers"+query, nil)
+
+		return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+	}
+
+	type fields struct {
+		schUC usecase.ScheduledTransferUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "should pass the current account and the status to the usecase",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnFetch: func(ctx context.Context, originID model.AccountID, status string) ([]usecase.ScheduledTransferOutput, error) {
+						if originID != "uuid-1" || status != "failed" {
+							return nil, errors.New("unexpected filter")
+						}
+						return []usecase.ScheduledTransferOutput{
+							{
+								ID:                   "schedule-uuid",
+								AccountOriginID:      "uuid-1",
+								AccountDestinationID: "uuid-2",
+								Amount:               usecase.NewAmount(1050),
+								Status:               "failed",
+								FailureReason:        "current account balance is insufficient",
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest("?status=failed"),
+			},
+			wantStatus: 200,
+			want: `[{"id": "schedule-uuid", "account_origin_id": "uuid-1", "account_destination_id": "uuid-2", "amount": 10.5,
+				"scheduled_for": "<<PRESENCE>>", "status": "failed", "failure_reason": "current account balance is insufficient", "created_at": "<<PRESENCE>>"}]`,
+		},
+		{
+			name: "should return 400 when status is not valid",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnFetch: func(ctx context.Context, originID model.AccountID, status string) ([]usecase.ScheduledTransferOutput, error) {
+						return nil, usecase.ErrScheduledTransferStatusInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest("?status=pending"),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrScheduledTransferStatusInvalid),
+		},
+		{
+			name: "should return 500 when usecase error",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnFetch: func(ctx context.Context, originID model.AccountID, status string) ([]usecase.ScheduledTransferOutput, error) {
+						return nil, errors.New("any error")
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(""),
+			},
+			wantStatus: 500,
+			want:       `{"code": 500, "message": "any error"}`,
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnFetch: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/scheduled-transfers", nil),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schCtrl := NewScheduledTransferController(tt.fields.schUC)
+
+			schCtrl.Fetch(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Fetch() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_scheduledTransferController_Cancel(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/scheduled-transfers/schedule-uuid/cancel", nil)
+		ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "schedule-uuid"}})
+		ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "uuid-1"})
+
+		return req.WithContext(ctx)
+	}
+
+	type fields struct {
+		schUC usecase.ScheduledTransferUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnCancel: func(ctx context.Context, caller model.Principal, id model.ScheduledTransferID) (*usecase.ScheduledTransferOutput, error) {
+						if caller.AccountID != "uuid-1" || id != "schedule-uuid" {
+							return nil, errors.New("should pass the caller and the id")
+						}
+						processedAt := time.Now()
+						return &usecase.ScheduledTransferOutput{
+							ID:                   string(id),
+							AccountOriginID:      "uuid-1",
+							AccountDestinationID: "uuid-2",
+							Amount:               usecase.NewAmount(1050),
+							Status:               "cancelled",
+							ProcessedAt:          &processedAt,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 200,
+			want: `{"id": "schedule-uuid", "account_origin_id": "uuid-1", "account_destination_id": "uuid-2", "amount": 10.5,
+				"scheduled_for": "<<PRESENCE>>", "status": "cancelled", "created_at": "<<PRESENCE>>", "processed_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 404 when not found",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnCancel: func(ctx context.Context, caller model.Principal, id model.ScheduledTransferID) (*usecase.ScheduledTransferOutput, error) {
+						return nil, repository.ErrScheduledTransferNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrScheduledTransferNotFound),
+		},
+		{
+			name: "should return 409 when already processed",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnCancel: func(ctx context.Context, caller model.Principal, id model.ScheduledTransferID) (*usecase.ScheduledTransferOutput, error) {
+						return nil, usecase.ErrScheduledTransferNotPending
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 409,
+			want:       fmt.Sprintf(`{"code": 409, "message": %q}`, usecase.ErrScheduledTransferNotPending),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				schUC: mock.ScheduledTransferUseCase{
+					OnCancel: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/scheduled-transfers/schedule-uuid/cancel", nil),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schCtrl := NewScheduledTransferController(tt.fields.schUC)
+
+			schCtrl.Cancel(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Cancel() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/hlog"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
+)
+
+// StandingOrderController is the interface that wraps http handle methods related to the standing orders.
+type StandingOrderController interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Fetch(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Pause(w http.ResponseWriter, r *http.Request)
+	Resume(w http.ResponseWriter, r *http.Request)
+	Cancel(w http.ResponseWriter, r *http.Request)
+}
+
+type standingOrderController struct {
+	soUC usecase.StandingOrderUseCase
+}
+
+// NewStandingOrderController instantiates a new standing order controller.
+func NewStandingOrderController(soUC usecase.StandingOrderUseCase) StandingOrderController {
+	return &standingOrderController{
+		soUC: soUC,
+	}
+}
+
+// @Summary Create standing order
+// @Description Creates a standing order from the current account to another: a transfer repeated `weekly`,
+// @Description `monthly` on `day_of_month` (or the last day of shorter months) or on the `last_business_day` of the month.
+// @Description The occurrences keep the time of day and the UTC offset of `starts_at`, which must be up to one year ahead.
+// @Description It finishes after `ends_at` or `max_occurrences`, when informed.
+// @Description The balance is only checked at each occurrence: if it's insufficient, the occurrence is retried and the holder is notified.
+// @tags Standing orders
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param order body usecase.StandingOrderCreateInput true "Standing order"
+// @Param X-Idempotency-Key header string false "Idempotency key"
+// @Success 201 {object} usecase.StandingOrderOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /standing-orders [post]
+func (soCtrl standingOrderController) Create(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		soCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.StandingOrderCreateInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding standing order create input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.AccountOriginID = string(principal.AccountID)
+
+	result, err := soCtrl.soUC.Create(logger.WithContext(r.Context()), input)
+	if err != nil {
+		soCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusCreated, result)
+}
+
+// @Summary Fetch standing orders
+// @Description Fetch the standing orders of the current account, the newest first.
+// @tags Standing orders
+// @Produce json
+// @Security Access token
+// @Success 200 {object} []usecase.StandingOrderOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /standing-orders [get]
+func (soCtrl standingOrderController) Fetch(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		soCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	result, err := soCtrl.soUC.Fetch(logger.WithContext(r.Context()), principal.AccountID)
+	if err != nil {
+		soCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Edit standing order
+// @Description Changes the destination, the amount, `ends_at` or `max_occurrences` of an active or paused standing order
+// @Description of the current account. Only the informed fields are changed, from the next occurrence on.
+// @tags Standing orders
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param id path string true "Standing order ID"
+// @Param order body usecase.StandingOrderUpdateInput true "Standing order changes"
+// @Success 200 {object} usecase.StandingOrderOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /standing-orders/{id} [patch]
+func (soCtrl standingOrderController) Update(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		soCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.StandingOrderUpdateInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding standing order update input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.ID = httprouter.ParamsFromContext(r.Context()).ByName("id")
+
+	result, err := soCtrl.soUC.Update(logger.WithContext(r.Context()), principal, input)
+	if err != nil {
+		soCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Pause standing order
+// @Description Pauses an active standing order of the current account. No occurrence is executed until it's resumed.
+// @tags Standing orders
+// @Produce json
+// @Security Access token
+// @Param id path string true "Standing order ID"
+// @Success 200 {object} usecase.StandingOrderOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /standing-orders/{id}/pause [post]
+func (soCtrl standingOrderController) Pause(w http.ResponseWriter, r *http.Request) {
+	soCtrl.changeStatus(w, r, soCtrl.soUC.Pause)
+}
+
+// @Summary Resume standing order
+// @Description Resumes a paused standing order of the current account. The occurrences missed while paused are not executed.
+// @tags Standing orders
+// @Produce json
+// @Security Access token
+// @Param id path string true "Standing order ID"
+// @Success 200 {object} usecase.StandingOrderOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /standing-orders/{id}/resume [post]
+func (soCtrl standingOrderController) Resume(w http.ResponseWriter, r *http.Request) {
+	soCtrl.changeStatus(w, r, soCtrl.soUC.Resume)
+}
+
+// @Summary Cancel standing order
+// @Description Cancels an active or paused standing order of the current account for good.
+// @tags Standing orders
+// @Produce json
+// @Security Access token
+// @Param id path string true "Standing order ID"
+// @Success 200 {object} usecase.StandingOrderOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /standing-orders/{id}/cancel [post]
+func (soCtrl standingOrderController) Cancel(w http.ResponseWriter, r *http.Request) {
+	soCtrl.changeStatus(w, r, soCtrl.soUC.Cancel)
+}
+
+type standingOrderStatusFunc func(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*usecase.StandingOrderOutput, error)
+
+func (soCtrl standingOrderController) changeStatus(w http.ResponseWriter, r *http.Request, changeStatus standingOrderStatusFunc) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		soCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	params := httprouter.ParamsFromContext(r.Context())
+
+	result, err := changeStatus(logger.WithContext(r.Context()), principal, model.StandingOrderID(params.ByName("id")))
+	if err != nil {
+		soCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+func (soCtrl standingOrderController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
+	switch err {
+	case repository.ErrStandingOrderNotFound:
+		statusCode = http.StatusNotFound
+	case usecase.ErrStandingOrderStatusChangeInvalid,
+		usecase.ErrStandingOrderNotRunning:
+		statusCode = http.StatusConflict
+	case repository.ErrAccountNotFound,
+		usecase.ErrTransferOriginAccountNotActive,
+		usecase.ErrTransferDestinationAccountNotActive,
+		usecase.ErrTransferCurrencyMismatch:
+		statusCode = http.StatusUnprocessableEntity
+	case usecase.ErrTransferOriginAccountRequired,
+		usecase.ErrTransferDestinationAccountRequired,
+		usecase.ErrTransferAmountNotPositive,
+		usecase.ErrTransferSameAccount,
+		usecase.ErrStandingOrderFrequencyInvalid,
+		usecase.ErrStandingOrderDayOfMonthInvalid,
+		usecase.ErrStandingOrderStartInvalid,
+		usecase.ErrStandingOrderEndInvalid,
+		usecase.ErrAmountInvalid:
+		statusCode = http.StatusBadRequest
+	case usecase.ErrAuthInvalidAccessToken:
+		statusCode = http.StatusUnauthorized
+	}
+
+	io.WriteErrorMsg(w, logger, statusCode, err.Error())
+}
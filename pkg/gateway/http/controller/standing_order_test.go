@@ -0,0 +1,427 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
+)
+
+func Test_standingOrderController_Create(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/standing-orders", bytes.NewReader([]byte(body)))
+
+		return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+	}
+
+	type fields struct {
+		soUC usecase.StandingOrderUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				soUC: mock.StandingOrderUseCase{
+					OnCreate: func(ctx context.Context, orderInput usecase.StandingOrderCreateInput) (*usecase.StandingOrderOutput, error) {
+						if orderInput.AccountOriginID != "uuid-1" || orderInput.DayOfMonth != 5 || orderInput.MaxOccurrences != 12 {
+							return nil, errors.New("should pass the current account and the recurrence")
+						}
+
+						nextDueAt := time.Date(2021, 2, 5, 9, 0, 0, 0, time.UTC)
+						return &usecase.StandingOrderOutput{
+							ID:                   "order-uuid",
+							AccountOriginID:      orderInput.AccountOriginID,
+							AccountDestinationID: orderInput.AccountDestinationID,
+							Amount:               orderInput.Amount,
+							Frequency:            orderInput.Frequency,
+							DayOfMonth:           orderInput.DayOfMonth,
+							StartsAt:             orderInput.StartsAt,
+							MaxOccurrences:       orderInput.MaxOccurrences,
+							Status:               "active",
+							NextDueAt:            &nextDueAt,
+							NextRunAt:            &nextDueAt,
+							CreatedAt:            time.Now(),
+							UpdatedAt:            time.Now(),
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"account_destination_id": "uuid-2", "amount": 10.5, "frequency": "monthly", "day_of_month": 5,
+					"starts_at": "2021-01-10T09:00:00Z", "max_occurrences": 12}`),
+			},
+			wantStatus: 201,
+			want: `{"id": "order-uuid", "account_origin_id": "uuid-1", "account_destination_id": "uuid-2", "amount": 10.5,
+				"frequency": "monthly", "day_of_month": 5, "starts_at": "2021-01-10T09:00:00Z", "max_occurrences": 12,
+				"occurrences": 0, "status": "active", "next_due_at": "2021-02-05T09:00:00Z", "next_run_at": "2021-02-05T09:00:00Z",
+				"created_at": "<<PRESENCE>>", "updated_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 400 when frequency is not valid",
+			fields: fields{
+				soUC: mock.StandingOrderUseCase{
+					OnCreate: func(ctx context.Context, orderInput usecase.StandingOrderCreateInput) (*usecase.StandingOrderOutput, error) {
+						return nil, usecase.ErrStandingOrderFrequencyInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"account_destination_id": "uuid-2", "amount": 10.5, "frequency": "daily", "starts_at": "2021-01-10T09:00:00Z"}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrStandingOrderFrequencyInvalid),
+		},
+		{
+			name: "should return 400 when body is not valid",
+			fields: fields{
+				soUC: mock.StandingOrderUseCase{
+					OnCreate: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"account_destination_id": "uuid-2", "amount": 10.5, "frequency": "weekly", "starts_at": "tomorrow"}`),
+			},
+			wantStatus: 400,
+			want:       `{"code": 400, "message": "error reading input"}`,
+		},
+		{
+			name: "should return 422 when destination account is not active",
+			fields: fields{
+				soUC: mock.StandingOrderUseCase{
+					OnCreate: func(ctx context.Context, orderInput usecase.StandingOrderCreateInput) (*usecase.StandingOrderOutput, error) {
+						return nil, usecase.ErrTransferDestinationAccountNotActive
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"account_destination_id": "uuid-2", "amount": 10.5, "frequency": "weekly", "starts_at": "2021-01-10T09:00:00Z"}`),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, usecase.ErrTransferDestinationAccountNotActive),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				soUC: mock.StandingOrderUseCase{
+					OnCreate: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/standing-orders", bytes.NewReader([]byte(`{}`))),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			soCtrl := NewStandingOrderController(tt.fields.soUC)
+
+			soCtrl.Create(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Create() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_standingOrderController_Update(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPatch, "/standing-orders/order-uuid", bytes.NewReader([]byte(body)))
+		ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "order-uuid"}})
+		ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "uuid-1"})
+
+		return req.WithContext(ctx)
+	}
+
+	type fields struct {
+		soUC usecase.StandingOrderUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "should pass only the informed fields",
+			fields: fields{
+				soUC: mock.StandingOrderUseCase{
+					OnUpdate: func(ctx context.Context, caller model.Principal, orderInput usecase.StandingOrderUpdateInput) (*usecase.StandingOrderOutput, error) {
+						if caller.AccountID != "uuid-1" || orderInput.ID != "order-uuid" {
+							return nil, errors.New("should pass the caller and the id")
+						}
+						if orderInput.Amount == nil || orderInput.Amount.Money != 2000 || orderInput.AccountDestinationID != nil || orderInput.EndsAt != nil {
+							return nil, errors.New("should pass only the amount")
+						}
+
+						return &usecase.StandingOrderOutput{
+							ID:                   orderInput.ID,
+							AccountOriginID:      "uuid-1",
+							AccountDestinationID: "uuid-2",
+							Amount:               *orderInput.Amount,
+							Frequency:            "weekly",
+							Occurrences:          3,
+							Status:               "paused",
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 20}`),
+			},
+			wantStatus: 200,
+			want: `{"id": "order-uuid", "account_origin_id": "uuid-1", "account_destination_id": "uuid-2", "amount": 20,
+				"frequency": "weekly", "starts_at": "<<PRESENCE>>", "occurrences": 3, "status": "paused",
+				"created_at": "<<PRESENCE>>", "updated_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 404 when not found",
+			fields: fields{
+				soUC: mock.StandingOrderUseCase{
+					OnUpdate: func(ctx context.Context, caller model.Principal, orderInput usecase.StandingOrderUpdateInput) (*usecase.StandingOrderOutput, error) {
+						return nil, repository.ErrStandingOrderNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 20}`),
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrStandingOrderNotFound),
+		},
+		{
+			name: "should return 409 when not running",
+			fields: fields{
+				soUC: mock.StandingOrderUseCase{
+					OnUpdate: func(ctx context.Context, caller model.Principal, orderInput usecase.StandingOrderUpdateInput) (*usecase.StandingOrderOutput, error) {
+						return nil, usecase.ErrStandingOrderNotRunning
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 20}`),
+			},
+			wantStatus: 409,
+			want:       fmt.Sprintf(`{"code": 409, "message": %q}`, usecase.ErrStandingOrderNotRunning),
+		},
+		{
+			name: "should return 400 when amount is not valid",
+			fields: fields{
+				soUC: mock.StandingOrderUseCase{
+					OnUpdate: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 20.5555}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrAmountInvalid),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			soCtrl := NewStandingOrderController(tt.fields.soUC)
+
+			soCtrl.Update(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Update() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_standingOrderController_changeStatus(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func(action string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/standing-orders/order-uuid/"+action, nil)
+		ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "order-uuid"}})
+		ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "uuid-1"})
+
+		return req.WithContext(ctx)
+	}
+	withStatus := func(status string) func(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*usecase.StandingOrderOutput, error) {
+		return func(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*usecase.StandingOrderOutput, error) {
+			if caller.AccountID != "uuid-1" || id != "order-uuid" {
+				return nil, errors.New("should pass the caller and the id")
+			}
+			return &usecase.StandingOrderOutput{
+				ID:                   string(id),
+				AccountOriginID:      "uuid-1",
+				AccountDestinationID: "uuid-2",
+				Amount:               usecase.NewAmount(1050),
+				Frequency:            "last_business_day",
+				Status:               status,
+			}, nil
+		}
+	}
+	wantWithStatus := func(status string) string {
+		return fmt.Sprintf(`{"id": "order-uuid", "account_origin_id": "uuid-1", "account_destination_id": "uuid-2", "amount": 10.5,
+			"frequency": "last_business_day", "starts_at": "<<PRESENCE>>", "occurrences": 0, "status": %q,
+			"created_at": "<<PRESENCE>>", "updated_at": "<<PRESENCE>>"}`, status)
+	}
+
+	tests := []struct {
+		name       string
+		soUC       mock.StandingOrderUseCase
+		handle     func(soCtrl StandingOrderController) http.HandlerFunc
+		r          *http.Request
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "pause successful",
+			soUC: mock.StandingOrderUseCase{OnPause: withStatus("paused")},
+			handle: func(soCtrl StandingOrderController) http.HandlerFunc {
+				return soCtrl.Pause
+			},
+			r:          newRequest("pause"),
+			wantStatus: 200,
+			want:       wantWithStatus("paused"),
+		},
+		{
+			name: "resume successful",
+			soUC: mock.StandingOrderUseCase{OnResume: withStatus("active")},
+			handle: func(soCtrl StandingOrderController) http.HandlerFunc {
+				return soCtrl.Resume
+			},
+			r:          newRequest("resume"),
+			wantStatus: 200,
+			want:       wantWithStatus("active"),
+		},
+		{
+			name: "cancel successful",
+			soUC: mock.StandingOrderUseCase{OnCancel: withStatus("cancelled")},
+			handle: func(soCtrl StandingOrderController) http.HandlerFunc {
+				return soCtrl.Cancel
+			},
+			r:          newRequest("cancel"),
+			wantStatus: 200,
+			want:       wantWithStatus("cancelled"),
+		},
+		{
+			name: "should return 409 when status change is not allowed",
+			soUC: mock.StandingOrderUseCase{
+				OnResume: func(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*usecase.StandingOrderOutput, error) {
+					return nil, usecase.ErrStandingOrderStatusChangeInvalid
+				},
+			},
+			handle: func(soCtrl StandingOrderController) http.HandlerFunc {
+				return soCtrl.Resume
+			},
+			r:          newRequest("resume"),
+			wantStatus: 409,
+			want:       fmt.Sprintf(`{"code": 409, "message": %q}`, usecase.ErrStandingOrderStatusChangeInvalid),
+		},
+		{
+			name: "should return 404 when not found",
+			soUC: mock.StandingOrderUseCase{
+				OnPause: func(ctx context.Context, caller model.Principal, id model.StandingOrderID) (*usecase.StandingOrderOutput, error) {
+					return nil, repository.ErrStandingOrderNotFound
+				},
+			},
+			handle: func(soCtrl StandingOrderController) http.HandlerFunc {
+				return soCtrl.Pause
+			},
+			r:          newRequest("pause"),
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrStandingOrderNotFound),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			soUC: mock.StandingOrderUseCase{},
+			handle: func(soCtrl StandingOrderController) http.HandlerFunc {
+				return soCtrl.Cancel
+			},
+			r:          httptest.NewRequest(http.MethodPost, "/standing-orders/order-uuid/cancel", nil),
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			tt.handle(NewStandingOrderController(tt.soUC))(rec, tt.r)
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("changeStatus() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
 
+	"github.com/julienschmidt/httprouter"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/hlog"
 
@@ -17,6 +21,9 @@ import (
 type TransferController interface {
 	Create(w http.ResponseWriter, r *http.Request)
 	Fetch(w http.ResponseWriter, r *http.Request)
+	Refund(w http.ResponseWriter, r *http.Request)
+	Reverse(w http.ResponseWriter, r *http.Request)
+	QuoteFee(w http.ResponseWriter, r *http.Request)
 }
 
 type transferController struct {
@@ -34,6 +41,9 @@ func NewTransferController(trfUC usecase.TransferUseCase, authUC usecase.AuthUse
 
 // @Summary Create transfer
 // @Description Creates a new transfer from the current account to another. Debits the amount from origin account and credit it to the destination account.
+// @Description The amount must be within the transfer limits of the origin account, otherwise it returns 422 with the remaining allowance.
+// @Description The destination is either `account_destination_id`, a pix key in `destination_key` or a static Pix BR Code in `br_code`,
+// @Description as pasted from the QR Code. When the BR Code has an amount, `amount` can be omitted or must match it.
 // @tags Transfers
 // @Accept json
 // @Produce json
@@ -49,7 +59,7 @@ func NewTransferController(trfUC usecase.TransferUseCase, authUC usecase.AuthUse
 func (trfCtrl transferController) Create(w http.ResponseWriter, r *http.Request) {
 	logger := hlog.FromRequest(r)
 
-	accountID, ok := appcontext.GetAuthSubject(r.Context())
+	principal, ok := appcontext.GetPrincipal(r.Context())
 	if !ok {
 		trfCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
 		return
@@ -58,10 +68,14 @@ func (trfCtrl transferController) Create(w http.ResponseWriter, r *http.Request)
 	var input usecase.TransferCreateInput
 	if err := io.ReadInput(r, logger, &input); err != nil {
 		logger.Error().Stack().Err(err).Msg("error decoding transfer create input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
 		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
 		return
 	}
-	input.AccountOriginID = accountID
+	input.AccountOriginID = string(principal.AccountID)
 
 	result, err := trfCtrl.trfUC.Create(logger.WithContext(r.Context()), input)
 	if err != nil {
@@ -69,48 +83,266 @@ func (trfCtrl transferController) Create(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	io.WriteSuccess(w, logger, http.StatusCreated, result)
+	io.WriteSuccess(w, r, logger, http.StatusCreated, result)
 }
 
 // @Summary Fetch transfers
-// @Description Fetch the transfers the current account is related to
+// @Description Fetch a page of the transfers the current account is related to, newest first. To get the next page, send the `next_cursor` of the previous one as `cursor` with the same filters.
+// @Description `from` and `to` accept RFC 3339 timestamps or UTC dates (YYYY-MM-DD). The period includes `from` and excludes `to`, except for dates, which include the whole `to` day.
 // @tags Transfers
 // @Produce json
 // @Security Access token
-// @Success 200 {object} []usecase.TransferFetchOutput
+// @Param cursor query string false "Cursor of the page"
+// @Param limit query int false "Page size, up to 100" default(20)
+// @Param direction query string false "Transfers sent or received by the current account" Enums(sent, received)
+// @Param counterpart_id query string false "The other account of the transfers"
+// @Param from query string false "Period start" example(2020-12-01)
+// @Param to query string false "Period end" example(2020-12-31)
+// @Param min_amount query string false "Minimum amount" example(10.00)
+// @Param max_amount query string false "Maximum amount" example(99.99)
+// @Success 200 {object} usecase.TransferFetchPageOutput
+// @failure 400 {object} io.ErrorOutput
 // @failure 401 {object} io.ErrorOutput
 // @failure 500 {object} io.ErrorOutput
 // @Router /transfers [get]
 func (trfCtrl transferController) Fetch(w http.ResponseWriter, r *http.Request) {
 	logger := hlog.FromRequest(r)
 
-	accountID, ok := appcontext.GetAuthSubject(r.Context())
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		trfCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	input, err := readTransferFetchInput(r.URL.Query())
+	if err != nil {
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+		return
+	}
+	input.AccountID = principal.AccountID
+
+	result, err := trfCtrl.trfUC.Fetch(logger.WithContext(r.Context()), input)
+	if err != nil {
+		trfCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Refund transfer
+// @Description Gives back the `amount`, or what's left when not informed, of a transfer received by the current account.
+// @Description The refund is a new transfer of kind `refund` from the current account to the sender, linked to the original by `original_transfer_id`.
+// @Description The refunds and reversals of a transfer can't sum more than its amount, shown as its `refunded_amount`.
+// @tags Transfers
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param id path string true "Transfer ID"
+// @Param refund body usecase.TransferRefundInput false "Refund"
+// @Param X-Idempotency-Key header string false "Idempotency key"
+// @Success 201 {object} usecase.TransferCreateOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /transfers/{id}/refund [post]
+func (trfCtrl transferController) Refund(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		trfCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.TransferRefundInput
+	// the body is optional, without it the whole transfer is refunded
+	if r.ContentLength != 0 {
+		if err := io.ReadInput(r, logger, &input); err != nil {
+			logger.Error().Stack().Err(err).Msg("error decoding transfer refund input")
+			if errors.Is(err, usecase.ErrAmountInvalid) {
+				io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+				return
+			}
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+			return
+		}
+	}
+	input.TransferID = httprouter.ParamsFromContext(r.Context()).ByName("id")
+
+	result, err := trfCtrl.trfUC.Refund(logger.WithContext(r.Context()), principal, input)
+	if err != nil {
+		trfCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusCreated, result)
+}
+
+// @Summary Reverse transfer
+// @Description Undoes what's left of a mistaken transfer of any account, as a new transfer of kind `reversal` from the recipient to the sender.
+// @Description Only operators and admins (`transfers:reverse` scope) can reverse.
+// @tags Transfers
+// @Produce json
+// @Security Access token
+// @Param id path string true "Transfer ID"
+// @Param X-Idempotency-Key header string false "Idempotency key"
+// @Success 201 {object} usecase.TransferCreateOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /transfers/{id}/reverse [post]
+func (trfCtrl transferController) Reverse(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	caller, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		trfCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	params := httprouter.ParamsFromContext(r.Context())
+
+	result, err := trfCtrl.trfUC.Reverse(logger.WithContext(r.Context()), caller, model.TransferID(params.ByName("id")))
+	if err != nil {
+		trfCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusCreated, result)
+}
+
+// @Summary Quote transfer fee
+// @Description Returns the fee a transfer of the `amount` from the current account would be charged if sent now, on top of the amount.
+// @Description The quote is not binding: the fee is calculated again when the transfer is created.
+// @tags Transfers
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param quote body usecase.TransferFeeQuoteInput true "Transfer fee quote"
+// @Success 200 {object} usecase.TransferFeeQuoteOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 422 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /transfer-fee-quotes [post]
+func (trfCtrl transferController) QuoteFee(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
 	if !ok {
 		trfCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
 		return
 	}
 
-	result, err := trfCtrl.trfUC.Fetch(logger.WithContext(r.Context()), model.AccountID(accountID))
+	var input usecase.TransferFeeQuoteInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding transfer fee quote input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.AccountOriginID = string(principal.AccountID)
+
+	result, err := trfCtrl.trfUC.QuoteFee(logger.WithContext(r.Context()), input)
 	if err != nil {
 		trfCtrl.writeError(w, logger, http.StatusInternalServerError, err)
 		return
 	}
 
-	io.WriteSuccess(w, logger, http.StatusOK, result)
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+func readTransferFetchInput(query url.Values) (usecase.TransferFetchInput, error) {
+	input := usecase.TransferFetchInput{
+		Cursor:        query.Get("cursor"),
+		Direction:     query.Get("direction"),
+		CounterpartID: query.Get("counterpart_id"),
+	}
+
+	var err error
+	if limit := query.Get("limit"); limit != "" {
+		input.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return input, usecase.ErrTransferFetchLimitInvalid
+		}
+	}
+
+	input.From, err = parsePeriodTime(query.Get("from"), false)
+	if err != nil {
+		return input, errPeriodTimeInvalid
+	}
+	input.To, err = parsePeriodTime(query.Get("to"), true)
+	if err != nil {
+		return input, errPeriodTimeInvalid
+	}
+
+	input.MinAmount, err = parseAmountParam(query.Get("min_amount"))
+	if err != nil {
+		return input, err
+	}
+	input.MaxAmount, err = parseAmountParam(query.Get("max_amount"))
+	if err != nil {
+		return input, err
+	}
+
+	return input, nil
 }
 
 func (trfCtrl transferController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
 	switch err {
+	case repository.ErrTransferNotFound:
+		statusCode = http.StatusNotFound
+	case usecase.ErrTransferFullyRefunded:
+		statusCode = http.StatusConflict
 	case repository.ErrAccountNotFound,
-		usecase.ErrAccountCurrentBalanceInsufficient:
+		usecase.ErrAccountCurrentBalanceInsufficient,
+		usecase.ErrTransferOriginAccountNotActive,
+		usecase.ErrTransferDestinationAccountNotActive,
+		usecase.ErrTransferNotRefundable,
+		usecase.ErrTransferConversionNotRefundable,
+		usecase.ErrTransferRefundAmountExceeded,
+		usecase.ErrTransferDestinationKeyNotFound,
+		usecase.ErrTransferCurrencyMismatch,
+		usecase.ErrTransferFXQuoteCurrencyMismatch,
+		usecase.ErrTransferFXQuoteAmountMismatch,
+		usecase.ErrFXQuoteExpired,
+		repository.ErrFXQuoteNotFound,
+		repository.ErrFXQuoteUsed:
 		statusCode = http.StatusUnprocessableEntity
 	case usecase.ErrTransferOriginAccountRequired,
-		usecase.ErrTransferDestinationAccountRequired,
+		usecase.ErrTransferDestinationRequired,
+		usecase.ErrTransferDestinationAmbiguous,
+		usecase.ErrTransferBRCodeInvalid,
+		usecase.ErrTransferBRCodeDynamic,
+		usecase.ErrTransferBRCodeAmountMismatch,
 		usecase.ErrTransferAmountNotPositive,
-		usecase.ErrTransferSameAccount:
+		usecase.ErrTransferSameAccount,
+		usecase.ErrTransferFetchCursorInvalid,
+		usecase.ErrTransferFetchLimitInvalid,
+		usecase.ErrTransferFetchDirectionInvalid,
+		usecase.ErrTransferFetchCounterpartInvalid,
+		usecase.ErrTransferFetchPeriodInvalid,
+		usecase.ErrTransferFetchAmountRangeInvalid,
+		usecase.ErrAmountInvalid:
 		statusCode = http.StatusBadRequest
 	case usecase.ErrAuthInvalidAccessToken:
 		statusCode = http.StatusUnauthorized
+	case usecase.ErrAuthForbidden:
+		statusCode = http.StatusForbidden
+	}
+
+	if errors.Is(err, usecase.ErrTransferLimitExceeded) {
+		statusCode = http.StatusUnprocessableEntity
 	}
 
 	io.WriteErrorMsg(w, logger, statusCode, err.Error())
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/hlog"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
+)
+
+// TransferBatchController is the interface that wraps http handle methods related to the transfer batches.
+type TransferBatchController interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+}
+
+type transferBatchController struct {
+	batchUC usecase.TransferBatchUseCase
+}
+
+// NewTransferBatchController instantiates a new transfer batch controller.
+func NewTransferBatchController(batchUC usecase.TransferBatchUseCase) TransferBatchController {
+	return &transferBatchController{
+		batchUC: batchUC,
+	}
+}
+
+// @Summary Create transfer batch
+// @Description Makes up to 500 transfers from the current account, in the order of `items`, following the rules of `POST /transfers`.
+// @Description In the `atomic` mode, they're all made or none: the first rejected item fails the batch and the others are `skipped`.
+// @Description In the `best_effort` mode, each item is made on its own and the rejected ones are reported with the `failure_reason`.
+// @Description An `item_id` is executed only once per account: when sent again, in any batch, the item is reported as
+// @Description `duplicate` with the transfer made then. So a batch interrupted halfway can be safely sent again.
+// @Description The outcomes are returned with 201 whatever the batch status. When an item of an `atomic` batch is executed
+// @Description by another batch at the same time, the batch is rolled back with 409 and can be sent again.
+// @tags Transfer batches
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param batch body usecase.TransferBatchCreateInput true "Transfer batch"
+// @Param X-Idempotency-Key header string false "Idempotency key"
+// @Success 201 {object} usecase.TransferBatchOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 409 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /transfer-batches [post]
+func (batchCtrl transferBatchController) Create(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		batchCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.TransferBatchCreateInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding transfer batch create input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.AccountOriginID = string(principal.AccountID)
+
+	result, err := batchCtrl.batchUC.Create(logger.WithContext(r.Context()), input)
+	if err != nil {
+		batchCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusCreated, result)
+}
+
+// @Summary Get transfer batch
+// @Description Gets a transfer batch of the current account with the outcome of its items, in the requested order.
+// @tags Transfer batches
+// @Produce json
+// @Security Access token
+// @Param id path string true "Transfer batch ID"
+// @Success 200 {object} usecase.TransferBatchOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /transfer-batches/{id} [get]
+func (batchCtrl transferBatchController) Get(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	principal, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		batchCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	params := httprouter.ParamsFromContext(r.Context())
+
+	result, err := batchCtrl.batchUC.Get(logger.WithContext(r.Context()), principal, model.TransferBatchID(params.ByName("id")))
+	if err != nil {
+		batchCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+func (batchCtrl transferBatchController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
+	switch err {
+	case repository.ErrTransferBatchNotFound,
+		repository.ErrAccountNotFound:
+		statusCode = http.StatusNotFound
+	case usecase.ErrTransferOriginAccountRequired,
+		usecase.ErrTransferBatchModeInvalid,
+		usecase.ErrTransferBatchItemsInvalid:
+		statusCode = http.StatusBadRequest
+	case usecase.ErrTransferBatchItemExecutedConcurrently:
+		statusCode = http.StatusConflict
+	case usecase.ErrAuthInvalidAccessToken:
+		statusCode = http.StatusUnauthorized
+	}
+
+	if errors.Is(err, usecase.ErrTransferBatchItemInvalid) {
+		statusCode = http.StatusBadRequest
+	}
+
+	io.WriteErrorMsg(w, logger, statusCode, err.Error())
+}
@@ -0,0 +1,290 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
+)
+
+func Test_transferBatchController_Create(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/transfer-batches", bytes.NewReader([]byte(body)))
+
+		return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+	}
+
+	type fields struct {
+		batchUC usecase.TransferBatchUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				batchUC: mock.TransferBatchUseCase{
+					OnCreate: func(ctx context.Context, batchInput usecase.TransferBatchCreateInput) (*usecase.TransferBatchOutput, error) {
+						if batchInput.AccountOriginID != "uuid-1" || batchInput.Mode != "best_effort" || len(batchInput.Items) != 2 {
+							return nil, errors.New("should pass the current account and the items")
+						}
+
+						completedAt := time.Now()
+						return &usecase.TransferBatchOutput{
+							ID:              "batch-uuid",
+							AccountOriginID: batchInput.AccountOriginID,
+							Mode:            batchInput.Mode,
+							Status:          "partially_completed",
+							Items: []usecase.TransferBatchItemOutput{
+								{ItemID: "1", AccountDestinationID: "uuid-2", Amount: usecase.NewAmount(1050), Status: "executed", TransferID: "transfer-uuid"},
+								{ItemID: "2", AccountDestinationID: "uuid-3", Amount: usecase.NewAmount(500), Status: "rejected", FailureReason: "account not found"},
+							},
+							CreatedAt:   time.Now(),
+							CompletedAt: &completedAt,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"mode": "best_effort", "items": [{"item_id": "1", "account_destination_id": "uuid-2", "amount": 10.5},
+					{"item_id": "2", "account_destination_id": "uuid-3", "amount": 5}]}`),
+			},
+			wantStatus: 201,
+			want: `{"id": "batch-uuid", "account_origin_id": "uuid-1", "mode": "best_effort", "status": "partially_completed", "items": [
+				{"item_id": "1", "account_destination_id": "uuid-2", "amount": 10.5, "status": "executed", "transfer_id": "transfer-uuid"},
+				{"item_id": "2", "account_destination_id": "uuid-3", "amount": 5, "status": "rejected", "failure_reason": "account not found"}],
+				"created_at": "<<PRESENCE>>", "completed_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 400 when an item is not valid",
+			fields: fields{
+				batchUC: mock.TransferBatchUseCase{
+					OnCreate: func(ctx context.Context, batchInput usecase.TransferBatchCreateInput) (*usecase.TransferBatchOutput, error) {
+						return nil, &usecase.TransferBatchItemInvalidError{Index: 1, Err: usecase.ErrTransferAmountNotPositive}
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"mode": "atomic", "items": []}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, "items[1]: "+usecase.ErrTransferAmountNotPositive.Error()),
+		},
+		{
+			name: "should return 400 when mode is not valid",
+			fields: fields{
+				batchUC: mock.TransferBatchUseCase{
+					OnCreate: func(ctx context.Context, batchInput usecase.TransferBatchCreateInput) (*usecase.TransferBatchOutput, error) {
+						return nil, usecase.ErrTransferBatchModeInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"mode": "all", "items": []}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrTransferBatchModeInvalid),
+		},
+		{
+			name: "should return 400 when body is not valid",
+			fields: fields{
+				batchUC: mock.TransferBatchUseCase{
+					OnCreate: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"mode": "atomic", "items": {}}`),
+			},
+			wantStatus: 400,
+			want:       `{"code": 400, "message": "error reading input"}`,
+		},
+		{
+			name: "should return 409 when an item was executed concurrently",
+			fields: fields{
+				batchUC: mock.TransferBatchUseCase{
+					OnCreate: func(ctx context.Context, batchInput usecase.TransferBatchCreateInput) (*usecase.TransferBatchOutput, error) {
+						return nil, usecase.ErrTransferBatchItemExecutedConcurrently
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"mode": "atomic", "items": []}`),
+			},
+			wantStatus: 409,
+			want:       fmt.Sprintf(`{"code": 409, "message": %q}`, usecase.ErrTransferBatchItemExecutedConcurrently),
+		},
+		{
+			name: "should return 500 when usecase error",
+			fields: fields{
+				batchUC: mock.TransferBatchUseCase{
+					OnCreate: func(ctx context.Context, batchInput usecase.TransferBatchCreateInput) (*usecase.TransferBatchOutput, error) {
+						return nil, usecase.ErrTransferBatchCreate
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"mode": "atomic", "items": []}`),
+			},
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": %q}`, usecase.ErrTransferBatchCreate),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				batchUC: mock.TransferBatchUseCase{
+					OnCreate: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/transfer-batches", bytes.NewReader([]byte(`{}`))),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batchCtrl := NewTransferBatchController(tt.fields.batchUC)
+
+			batchCtrl.Create(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Create() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_transferBatchController_Get(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/transfer-batches/batch-uuid", nil)
+		ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "batch-uuid"}})
+		ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "uuid-1"})
+
+		return req.WithContext(ctx)
+	}
+
+	type fields struct {
+		batchUC usecase.TransferBatchUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				batchUC: mock.TransferBatchUseCase{
+					OnGet: func(ctx context.Context, caller model.Principal, id model.TransferBatchID) (*usecase.TransferBatchOutput, error) {
+						if caller.AccountID != "uuid-1" || id != "batch-uuid" {
+							return nil, errors.New("should pass the caller and the id")
+						}
+						return &usecase.TransferBatchOutput{
+							ID:              string(id),
+							AccountOriginID: "uuid-1",
+							Mode:            "atomic",
+							Status:          "processing",
+							Items:           []usecase.TransferBatchItemOutput{},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 200,
+			want:       `{"id": "batch-uuid", "account_origin_id": "uuid-1", "mode": "atomic", "status": "processing", "items": [], "created_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 404 when not found",
+			fields: fields{
+				batchUC: mock.TransferBatchUseCase{
+					OnGet: func(ctx context.Context, caller model.Principal, id model.TransferBatchID) (*usecase.TransferBatchOutput, error) {
+						return nil, repository.ErrTransferBatchNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrTransferBatchNotFound),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batchCtrl := NewTransferBatchController(tt.fields.batchUC)
+
+			batchCtrl.Get(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Get() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
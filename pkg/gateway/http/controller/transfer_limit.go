@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/hlog"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
+)
+
+// TransferLimitController is the interface that wraps http handle methods related to the transfer limits.
+type TransferLimitController interface {
+	Get(w http.ResponseWriter, r *http.Request)
+	Set(w http.ResponseWriter, r *http.Request)
+}
+
+type transferLimitController struct {
+	limitUC usecase.TransferLimitUseCase
+}
+
+// NewTransferLimitController instantiates a new transfer limit controller.
+func NewTransferLimitController(limitUC usecase.TransferLimitUseCase) TransferLimitController {
+	return &transferLimitController{
+		limitUC: limitUC,
+	}
+}
+
+// @Summary Get transfer limits
+// @Description Gets the transfer limits in force for the account and the allowance left by them for the next transfer. The limits left out are not limited. Only the account owner or operators and admins (`accounts:read` scope) can get them.
+// @tags Accounts
+// @Produce json
+// @Security Access token
+// @Param id path string true "Account ID"
+// @Success 200 {object} usecase.TransferLimitsOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /accounts/{id}/transfer-limits [get]
+func (limitCtrl transferLimitController) Get(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	caller, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		limitCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	accountID := model.AccountID(httprouter.ParamsFromContext(r.Context()).ByName("id"))
+
+	result, err := limitCtrl.limitUC.Get(logger.WithContext(r.Context()), caller, accountID)
+	if err != nil {
+		limitCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+// @Summary Set transfer limits
+// @Description Replaces the transfer limits set for the account. The limits left out, or null, go back to the defaults, and zero means no limit. Only admins (`accounts:write` scope) can set them.
+// @tags Accounts
+// @Accept json
+// @Produce json
+// @Security Access token
+// @Param id path string true "Account ID"
+// @Param input body usecase.TransferLimitsInput true "Limits"
+// @Success 200 {object} usecase.TransferLimitsOutput
+// @failure 400 {object} io.ErrorOutput
+// @failure 401 {object} io.ErrorOutput
+// @failure 403 {object} io.ErrorOutput
+// @failure 404 {object} io.ErrorOutput
+// @failure 500 {object} io.ErrorOutput
+// @Router /accounts/{id}/transfer-limits [put]
+func (limitCtrl transferLimitController) Set(w http.ResponseWriter, r *http.Request) {
+	logger := hlog.FromRequest(r)
+
+	caller, ok := appcontext.GetPrincipal(r.Context())
+	if !ok {
+		limitCtrl.writeError(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken)
+		return
+	}
+
+	var input usecase.TransferLimitsInput
+	if err := io.ReadInput(r, logger, &input); err != nil {
+		logger.Error().Stack().Err(err).Msg("error decoding transfer limits input")
+		if errors.Is(err, usecase.ErrAmountInvalid) {
+			io.WriteErrorMsg(w, logger, http.StatusBadRequest, err.Error())
+			return
+		}
+		io.WriteErrorMsg(w, logger, http.StatusBadRequest, "error reading input")
+		return
+	}
+	input.AccountID = httprouter.ParamsFromContext(r.Context()).ByName("id")
+
+	result, err := limitCtrl.limitUC.Set(logger.WithContext(r.Context()), caller, input)
+	if err != nil {
+		limitCtrl.writeError(w, logger, http.StatusInternalServerError, err)
+		return
+	}
+
+	io.WriteSuccess(w, r, logger, http.StatusOK, result)
+}
+
+func (limitCtrl transferLimitController) writeError(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, err error) {
+	switch err {
+	case repository.ErrAccountNotFound:
+		statusCode = http.StatusNotFound
+	case usecase.ErrTransferLimitNegative,
+		usecase.ErrAmountInvalid:
+		statusCode = http.StatusBadRequest
+	case usecase.ErrAuthInvalidAccessToken:
+		statusCode = http.StatusUnauthorized
+	case usecase.ErrAuthForbidden:
+		statusCode = http.StatusForbidden
+	}
+
+	io.WriteErrorMsg(w, logger, statusCode, err.Error())
+}
@@ -0,0 +1,341 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
+)
+
+func newTestTransferLimitsRequest(method string, body string, caller model.Principal) *http.Request {
+	req := httptest.NewRequest(method, "/accounts/uuid-1/transfer-limits", bytes.NewReader([]byte(body)))
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "uuid-1"}})
+	ctx = appcontext.WithPrincipal(ctx, caller)
+
+	return req.WithContext(ctx)
+}
+
+func Test_transferLimitController_Get(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	type fields struct {
+		limitUC usecase.TransferLimitUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnGet: func(ctx context.Context, caller model.Principal, accountID model.AccountID) (*usecase.TransferLimitsOutput, error) {
+						if caller.AccountID != "uuid-1" || accountID != "uuid-1" {
+							return nil, errors.New("should pass the caller and the account")
+						}
+
+						perTransaction := usecase.NewAmount(500000)
+						allowance := usecase.NewAmount(400000)
+						return &usecase.TransferLimitsOutput{
+							AccountID:      string(accountID),
+							PerTransaction: &perTransaction,
+							Allowance:      &allowance,
+							AllowanceLimit: "per_transaction",
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestTransferLimitsRequest(http.MethodGet, "", model.Principal{AccountID: "uuid-1"}),
+			},
+			wantStatus: 200,
+			want:       `{"account_id":"uuid-1", "per_transaction":5000, "night":false, "allowance":4000, "allowance_limit":"per_transaction"}`,
+		},
+		{
+			name: "should return 403 when forbidden",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnGet: func(ctx context.Context, caller model.Principal, accountID model.AccountID) (*usecase.TransferLimitsOutput, error) {
+						return nil, usecase.ErrAuthForbidden
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestTransferLimitsRequest(http.MethodGet, "", model.Principal{AccountID: "uuid-2"}),
+			},
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code": 403, "message": %q}`, usecase.ErrAuthForbidden),
+		},
+		{
+			name: "should return 404 when account not found",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnGet: func(ctx context.Context, caller model.Principal, accountID model.AccountID) (*usecase.TransferLimitsOutput, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestTransferLimitsRequest(http.MethodGet, "", model.Principal{AccountID: "uuid-1"}),
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrAccountNotFound),
+		},
+		{
+			name: "should return 500 when usecase error",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnGet: func(ctx context.Context, caller model.Principal, accountID model.AccountID) (*usecase.TransferLimitsOutput, error) {
+						return nil, usecase.ErrTransferLimitGet
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestTransferLimitsRequest(http.MethodGet, "", model.Principal{AccountID: "uuid-1"}),
+			},
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": %q}`, usecase.ErrTransferLimitGet),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnGet: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/accounts/uuid-1/transfer-limits", nil),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			limitCtrl := NewTransferLimitController(tt.fields.limitUC)
+
+			limitCtrl.Get(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Get() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_transferLimitController_Set(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	admin := model.Principal{AccountID: "admin-uuid", Scopes: []model.Scope{model.ScopeAccountsWrite}}
+
+	type fields struct {
+		limitUC usecase.TransferLimitUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnSet: func(ctx context.Context, caller model.Principal, limitsInput usecase.TransferLimitsInput) (*usecase.TransferLimitsOutput, error) {
+						if limitsInput.AccountID != "uuid-1" || limitsInput.Daily == nil || limitsInput.Daily.Money != 250050 ||
+							limitsInput.PerTransaction != nil || limitsInput.NightTotal == nil || limitsInput.NightTotal.Money != 0 {
+							return nil, errors.New("should pass the account and the limits")
+						}
+
+						return &usecase.TransferLimitsOutput{
+							AccountID: limitsInput.AccountID,
+							Daily:     limitsInput.Daily,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestTransferLimitsRequest(http.MethodPut, `{"daily":"2500.50", "night_total":0}`, admin),
+			},
+			wantStatus: 200,
+			want:       `{"account_id":"uuid-1", "daily":2500.5, "night":false}`,
+		},
+		{
+			name: "should return 400 when a limit is negative",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnSet: func(ctx context.Context, caller model.Principal, limitsInput usecase.TransferLimitsInput) (*usecase.TransferLimitsOutput, error) {
+						return nil, usecase.ErrTransferLimitNegative
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestTransferLimitsRequest(http.MethodPut, `{"daily":-1}`, admin),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrTransferLimitNegative),
+		},
+		{
+			name: "should return 400 when a limit has more than 3 decimal places",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnSet: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestTransferLimitsRequest(http.MethodPut, `{"daily":0.0001}`, admin),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrAmountInvalid),
+		},
+		{
+			name: "should return 400 when request body is missing",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnSet: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestTransferLimitsRequest(http.MethodPut, "", admin),
+			},
+			wantStatus: 400,
+			want:       `{"code": 400, "message": "error reading input"}`,
+		},
+		{
+			name: "should return 403 when forbidden",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnSet: func(ctx context.Context, caller model.Principal, limitsInput usecase.TransferLimitsInput) (*usecase.TransferLimitsOutput, error) {
+						return nil, usecase.ErrAuthForbidden
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestTransferLimitsRequest(http.MethodPut, `{}`, model.Principal{AccountID: "uuid-1"}),
+			},
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code": 403, "message": %q}`, usecase.ErrAuthForbidden),
+		},
+		{
+			name: "should return 404 when account not found",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnSet: func(ctx context.Context, caller model.Principal, limitsInput usecase.TransferLimitsInput) (*usecase.TransferLimitsOutput, error) {
+						return nil, repository.ErrAccountNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestTransferLimitsRequest(http.MethodPut, `{}`, admin),
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrAccountNotFound),
+		},
+		{
+			name: "should return 500 when usecase error",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnSet: func(ctx context.Context, caller model.Principal, limitsInput usecase.TransferLimitsInput) (*usecase.TransferLimitsOutput, error) {
+						return nil, usecase.ErrTransferLimitSave
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newTestTransferLimitsRequest(http.MethodPut, `{}`, admin),
+			},
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": %q}`, usecase.ErrTransferLimitSave),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				limitUC: mock.TransferLimitUseCase{
+					OnSet: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPut, "/accounts/uuid-1/transfer-limits", bytes.NewReader([]byte(`{}`))),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			limitCtrl := NewTransferLimitController(tt.fields.limitUC)
+
+			limitCtrl.Set(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Set() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
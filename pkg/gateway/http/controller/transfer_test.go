@@ -10,10 +10,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/julienschmidt/httprouter"
 	"github.com/kinbiko/jsonassert"
 
 	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
 )
@@ -45,9 +47,11 @@ func Test_transferController_Create(t *testing.T) {
 					OnCreate: func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error) {
 						ret := usecase.TransferCreateOutput{
 							ID:                   "trf-uuid-1",
+							Kind:                 "transfer",
 							AccountOriginID:      "uuid-1",
 							AccountDestinationID: "uuid-2",
-							Amount:               1,
+							Currency:             "BRL",
+							Amount:               usecase.NewAmount(100),
 							CreatedAt:            time.Time{},
 						}
 
@@ -61,11 +65,64 @@ func Test_transferController_Create(t *testing.T) {
 				r: func() *http.Request {
 					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"account_destination_id":"uuid-2", "amount": 1}`)))
 
-					return req.WithContext(appcontext.WithAuthSubject(req.Context(), "uuid-1"))
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 201,
-			want:       `{"id": "trf-uuid-1", "account_origin_id":"uuid-1", "account_destination_id":"uuid-2", "amount": 1, "created_at": "<<PRESENCE>>"}`,
+			want:       `{"id": "trf-uuid-1", "kind": "transfer", "account_origin_id":"uuid-1", "account_destination_id":"uuid-2", "currency": "BRL", "amount": 1, "created_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "decimal string amount should be exact and returned as string when accepting v2",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnCreate: func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error) {
+						if transferInput.Amount.Money != 29 {
+							return nil, errors.New("amount should be exact")
+						}
+
+						ret := usecase.TransferCreateOutput{
+							ID:                   "trf-uuid-1",
+							Kind:                 "transfer",
+							AccountOriginID:      "uuid-1",
+							AccountDestinationID: "uuid-2",
+							Currency:             "BRL",
+							Amount:               transferInput.Amount,
+							CreatedAt:            time.Time{},
+						}
+
+						return &ret, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"account_destination_id":"uuid-2", "amount": "0.29"}`)))
+					req.Header.Set("Accept", "application/vnd.springfield-bank.v2+json")
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 201,
+			want:       `{"id": "trf-uuid-1", "kind": "transfer", "account_origin_id":"uuid-1", "account_destination_id":"uuid-2", "currency": "BRL", "amount": "0.29", "created_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 400 when amount has more than 3 decimal places",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnCreate: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"account_destination_id":"uuid-2", "amount": 0.2911}`)))
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrAmountInvalid),
 		},
 		{
 			name: "should return 500 when usecase error",
@@ -81,12 +138,90 @@ func Test_transferController_Create(t *testing.T) {
 				r: func() *http.Request {
 					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"account_destination_id":"uuid-2", "amount": 1}`)))
 
-					return req.WithContext(appcontext.WithAuthSubject(req.Context(), "uuid-1"))
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 500,
 			want:       `{"code": 500, "message": "any error"}`,
 		},
+		{
+			name: "should return the conversion of cross-currency transfers",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnCreate: func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error) {
+						if transferInput.QuoteID != "quote-uuid-1" {
+							return nil, errors.New("should pass the quote")
+						}
+						return &usecase.TransferCreateOutput{
+							ID:                   "trf-uuid-1",
+							Kind:                 "transfer",
+							AccountOriginID:      transferInput.AccountOriginID,
+							AccountDestinationID: transferInput.AccountDestinationID,
+							Currency:             "BRL",
+							Amount:               transferInput.Amount,
+							Conversion: &usecase.TransferConversionOutput{
+								QuoteID:             transferInput.QuoteID,
+								Rate:                "0.19518289",
+								DestinationCurrency: "USD",
+								DestinationAmount:   usecase.NewAmount(10000),
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"account_destination_id":"uuid-2", "amount": 512.34, "quote_id": "quote-uuid-1"}`)))
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 201,
+			want: `{"id": "trf-uuid-1", "kind": "transfer", "account_origin_id":"uuid-1", "account_destination_id":"uuid-2", "currency": "BRL",
+				"amount": 512.34, "conversion": {"quote_id": "quote-uuid-1", "rate": "0.19518289", "destination_currency": "USD",
+				"destination_amount": 100}, "created_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 422 when the quote expired",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnCreate: func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error) {
+						return nil, usecase.ErrFXQuoteExpired
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"account_destination_id":"uuid-2", "amount": 1, "quote_id": "quote-uuid-1"}`)))
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, usecase.ErrFXQuoteExpired),
+		},
+		{
+			name: "should return 422 with the remaining allowance when a limit is exceeded",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnCreate: func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error) {
+						return nil, &usecase.TransferLimitExceededError{Limit: model.TransferLimitDaily, Remaining: 5050}
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"account_destination_id":"uuid-2", "amount": 100}`)))
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 422,
+			want:       `{"code": 422, "message": "'amount' exceeds the daily transfer limit, the remaining allowance is 50.50"}`,
+		},
 		{
 			name: "should return 400 with error msg when request body is missing",
 			fields: fields{
@@ -99,7 +234,7 @@ func Test_transferController_Create(t *testing.T) {
 				r: func() *http.Request {
 					req := httptest.NewRequest(http.MethodPost, "/transfers", nil)
 
-					return req.WithContext(appcontext.WithAuthSubject(req.Context(), "uuid-1"))
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 400,
@@ -110,7 +245,7 @@ func Test_transferController_Create(t *testing.T) {
 			fields: fields{
 				trfUC: mock.TransferUseCase{
 					OnCreate: func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error) {
-						return nil, usecase.ErrTransferDestinationAccountRequired
+						return nil, usecase.ErrTransferDestinationRequired
 					},
 				},
 			},
@@ -119,11 +254,117 @@ func Test_transferController_Create(t *testing.T) {
 				r: func() *http.Request {
 					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"account_destination_id":"", "amount": 1}`)))
 
-					return req.WithContext(appcontext.WithAuthSubject(req.Context(), "uuid-1"))
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 400,
-			want:       fmt.Sprintf(`{"code": 400, "message": "%s"}`, usecase.ErrTransferDestinationAccountRequired),
+			want:       fmt.Sprintf(`{"code": 400, "message": "%s"}`, usecase.ErrTransferDestinationRequired),
+		},
+		{
+			name: "should return 400 when both destination account and key are informed",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnCreate: func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error) {
+						return nil, usecase.ErrTransferDestinationAmbiguous
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"account_destination_id":"uuid-2", "destination_key":"homer@springfield.com", "amount": 1}`)))
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": "%s"}`, usecase.ErrTransferDestinationAmbiguous),
+		},
+		{
+			name: "should return 400 when BR Code is invalid",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnCreate: func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error) {
+						if transferInput.BRCode != "00020126" {
+							return nil, errors.New("should pass the BR Code")
+						}
+						return nil, usecase.ErrTransferBRCodeInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"br_code":"00020126"}`)))
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrTransferBRCodeInvalid),
+		},
+		{
+			name: "should return 422 when destination key is not found",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnCreate: func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error) {
+						if transferInput.DestinationKey != "homer@springfield.com" {
+							return nil, errors.New("should pass the destination key")
+						}
+						return nil, usecase.ErrTransferDestinationKeyNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"destination_key":"homer@springfield.com", "amount": 1}`)))
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": "%s"}`, usecase.ErrTransferDestinationKeyNotFound),
+		},
+		{
+			name: "should return 422 when origin account is not active",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnCreate: func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error) {
+						return nil, usecase.ErrTransferOriginAccountNotActive
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"account_destination_id":"uuid-2", "amount": 1}`)))
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": "%s"}`, usecase.ErrTransferOriginAccountNotActive),
+		},
+		{
+			name: "should return 422 when destination account is not active",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnCreate: func(ctx context.Context, transferInput usecase.TransferCreateInput) (*usecase.TransferCreateOutput, error) {
+						return nil, usecase.ErrTransferDestinationAccountNotActive
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(`{"account_destination_id":"uuid-2", "amount": 1}`)))
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": "%s"}`, usecase.ErrTransferDestinationAccountNotActive),
 		},
 		{
 			name: "should return 401 when invalid token",
@@ -189,8 +430,8 @@ func Test_transferController_Fetch(t *testing.T) {
 			name: "successful empty result",
 			fields: fields{
 				trfUC: mock.TransferUseCase{
-					OnFetch: func(ctx context.Context, accountID model.AccountID) ([]usecase.TransferFetchOutput, error) {
-						return []usecase.TransferFetchOutput{}, nil
+					OnFetch: func(ctx context.Context, fetchInput usecase.TransferFetchInput) (*usecase.TransferFetchPageOutput, error) {
+						return &usecase.TransferFetchPageOutput{Transfers: []usecase.TransferFetchOutput{}}, nil
 					},
 				},
 			},
@@ -199,27 +440,35 @@ func Test_transferController_Fetch(t *testing.T) {
 				r: func() *http.Request {
 					req := httptest.NewRequest(http.MethodGet, "/transfers", nil)
 
-					return req.WithContext(appcontext.WithAuthSubject(req.Context(), "uuid-1"))
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 200,
-			want:       `[]`,
+			want:       `{"transfers": [], "next_cursor": null}`,
 		},
 		{
 			name: "successful one result",
 			fields: fields{
 				trfUC: mock.TransferUseCase{
-					OnFetch: func(ctx context.Context, accountID model.AccountID) ([]usecase.TransferFetchOutput, error) {
-						return []usecase.TransferFetchOutput{
-							{
-								TransferCreateOutput: usecase.TransferCreateOutput{
-									ID:                   "trf-uuid-1",
-									AccountOriginID:      "uuid-1",
-									AccountDestinationID: "uuid-2",
-									Amount:               1,
-									CreatedAt:            time.Time{},
+					OnFetch: func(ctx context.Context, fetchInput usecase.TransferFetchInput) (*usecase.TransferFetchPageOutput, error) {
+						nextCursor := "any-cursor"
+						refundedAmount := usecase.NewAmount(25)
+						return &usecase.TransferFetchPageOutput{
+							Transfers: []usecase.TransferFetchOutput{
+								{
+									TransferCreateOutput: usecase.TransferCreateOutput{
+										ID:                   "trf-uuid-1",
+										Kind:                 "transfer",
+										AccountOriginID:      "uuid-1",
+										AccountDestinationID: "uuid-2",
+										Currency:             "BRL",
+										Amount:               usecase.NewAmount(100),
+										RefundedAmount:       &refundedAmount,
+										CreatedAt:            time.Time{},
+									},
 								},
 							},
+							NextCursor: &nextCursor,
 						}, nil
 					},
 				},
@@ -229,17 +478,100 @@ func Test_transferController_Fetch(t *testing.T) {
 				r: func() *http.Request {
 					req := httptest.NewRequest(http.MethodGet, "/transfers", nil)
 
-					return req.WithContext(appcontext.WithAuthSubject(req.Context(), "uuid-1"))
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 200,
-			want:       `[{"id": "trf-uuid-1", "account_origin_id": "uuid-1","account_destination_id": "uuid-2","amount": 1, "created_at": "<<PRESENCE>>"}]`,
+			want:       `{"transfers": [{"id": "trf-uuid-1", "kind": "transfer", "account_origin_id": "uuid-1","account_destination_id": "uuid-2","currency": "BRL","amount": 1, "refunded_amount": 0.25, "created_at": "<<PRESENCE>>"}], "next_cursor": "any-cursor"}`,
+		},
+		{
+			name: "should pass the query filters",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnFetch: func(ctx context.Context, fetchInput usecase.TransferFetchInput) (*usecase.TransferFetchPageOutput, error) {
+						if fetchInput.AccountID != "uuid-1" || fetchInput.Cursor != "any-cursor" || fetchInput.Limit != 10 ||
+							fetchInput.Direction != "received" || fetchInput.CounterpartID != "uuid-2" ||
+							!fetchInput.From.Equal(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)) ||
+							!fetchInput.To.Equal(time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)) ||
+							fetchInput.MinAmount.Money != 1000 || fetchInput.MaxAmount.Money != 9999 {
+							return nil, fmt.Errorf("unexpected input %+v", fetchInput)
+						}
+						return &usecase.TransferFetchPageOutput{Transfers: []usecase.TransferFetchOutput{}}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/transfers?cursor=any-cursor&limit=10&direction=received&counterpart_id=uuid-2&from=2021-01-01&to=2021-01-31&min_amount=10&max_amount=99.99", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 200,
+			want:       `{"transfers": [], "next_cursor": null}`,
+		},
+		{
+			name: "should return 400 when limit is not a number",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnFetch: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/transfers?limit=ten", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrTransferFetchLimitInvalid),
+		},
+		{
+			name: "should return 400 when amount is not valid",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnFetch: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/transfers?min_amount=1.0001", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrAmountInvalid),
+		},
+		{
+			name: "should return 400 when usecase returns invalid cursor error",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnFetch: func(ctx context.Context, fetchInput usecase.TransferFetchInput) (*usecase.TransferFetchPageOutput, error) {
+						return nil, usecase.ErrTransferFetchCursorInvalid
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/transfers?cursor=any-cursor", nil)
+
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+				}(),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrTransferFetchCursorInvalid),
 		},
 		{
 			name: "should return 500 when usecase error",
 			fields: fields{
 				trfUC: mock.TransferUseCase{
-					OnFetch: func(ctx context.Context, accountID model.AccountID) ([]usecase.TransferFetchOutput, error) {
+					OnFetch: func(ctx context.Context, fetchInput usecase.TransferFetchInput) (*usecase.TransferFetchPageOutput, error) {
 						return nil, errors.New("any error")
 					},
 				},
@@ -249,7 +581,7 @@ func Test_transferController_Fetch(t *testing.T) {
 				r: func() *http.Request {
 					req := httptest.NewRequest(http.MethodGet, "/transfers", nil)
 
-					return req.WithContext(appcontext.WithAuthSubject(req.Context(), "uuid-1"))
+					return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
 				}(),
 			},
 			wantStatus: 500,
@@ -294,3 +626,427 @@ func Test_transferController_Fetch(t *testing.T) {
 		})
 	}
 }
+
+func Test_transferController_Refund(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/transfers/trf-uuid-1/refund", bytes.NewReader([]byte(body)))
+		ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "trf-uuid-1"}})
+		ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "uuid-2"})
+
+		return req.WithContext(ctx)
+	}
+	refundOf := func(ctx context.Context, caller model.Principal, refundInput usecase.TransferRefundInput) (*usecase.TransferCreateOutput, error) {
+		if caller.AccountID != "uuid-2" || refundInput.TransferID != "trf-uuid-1" {
+			return nil, errors.New("should pass the caller and the transfer id")
+		}
+
+		amount := usecase.NewAmount(1000)
+		if refundInput.Amount != nil {
+			amount = *refundInput.Amount
+		}
+		return &usecase.TransferCreateOutput{
+			ID:                   "trf-uuid-2",
+			Kind:                 "refund",
+			OriginalTransferID:   refundInput.TransferID,
+			AccountOriginID:      "uuid-2",
+			AccountDestinationID: "uuid-1",
+			Currency:             "BRL",
+			Amount:               amount,
+		}, nil
+	}
+
+	type fields struct {
+		trfUC usecase.TransferUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "partial refund",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnRefund: refundOf,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 2.5}`),
+			},
+			wantStatus: 201,
+			want: `{"id": "trf-uuid-2", "kind": "refund", "original_transfer_id": "trf-uuid-1", "account_origin_id": "uuid-2",
+				"account_destination_id": "uuid-1", "currency": "BRL", "amount": 2.5, "created_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "refund without body should refund everything",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnRefund: refundOf,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(""),
+			},
+			wantStatus: 201,
+			want: `{"id": "trf-uuid-2", "kind": "refund", "original_transfer_id": "trf-uuid-1", "account_origin_id": "uuid-2",
+				"account_destination_id": "uuid-1", "currency": "BRL", "amount": 10, "created_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 404 when not found",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnRefund: func(ctx context.Context, caller model.Principal, refundInput usecase.TransferRefundInput) (*usecase.TransferCreateOutput, error) {
+						return nil, repository.ErrTransferNotFound
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(""),
+			},
+			wantStatus: 404,
+			want:       fmt.Sprintf(`{"code": 404, "message": %q}`, repository.ErrTransferNotFound),
+		},
+		{
+			name: "should return 409 when fully refunded",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnRefund: func(ctx context.Context, caller model.Principal, refundInput usecase.TransferRefundInput) (*usecase.TransferCreateOutput, error) {
+						return nil, usecase.ErrTransferFullyRefunded
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(""),
+			},
+			wantStatus: 409,
+			want:       fmt.Sprintf(`{"code": 409, "message": %q}`, usecase.ErrTransferFullyRefunded),
+		},
+		{
+			name: "should return 422 when amount exceeds the amount not refunded",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnRefund: func(ctx context.Context, caller model.Principal, refundInput usecase.TransferRefundInput) (*usecase.TransferCreateOutput, error) {
+						return nil, usecase.ErrTransferRefundAmountExceeded
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": 20}`),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, usecase.ErrTransferRefundAmountExceeded),
+		},
+		{
+			name: "should return 400 when body is not valid",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnRefund: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount": "ten"}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrAmountInvalid),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnRefund: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/transfers/trf-uuid-1/refund", nil),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trfCtrl := NewTransferController(tt.fields.trfUC, nil)
+
+			trfCtrl.Refund(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Refund() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_transferController_Reverse(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/transfers/trf-uuid-1/reverse", nil)
+		ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "trf-uuid-1"}})
+		ctx = appcontext.WithPrincipal(ctx, model.Principal{AccountID: "operator-uuid"})
+
+		return req.WithContext(ctx)
+	}
+
+	type fields struct {
+		trfUC usecase.TransferUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnReverse: func(ctx context.Context, caller model.Principal, id model.TransferID) (*usecase.TransferCreateOutput, error) {
+						if caller.AccountID != "operator-uuid" || id != "trf-uuid-1" {
+							return nil, errors.New("should pass the caller and the transfer id")
+						}
+						return &usecase.TransferCreateOutput{
+							ID:                   "trf-uuid-2",
+							Kind:                 "reversal",
+							OriginalTransferID:   string(id),
+							AccountOriginID:      "uuid-2",
+							AccountDestinationID: "uuid-1",
+							Currency:             "BRL",
+							Amount:               usecase.NewAmount(1000),
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 201,
+			want: `{"id": "trf-uuid-2", "kind": "reversal", "original_transfer_id": "trf-uuid-1", "account_origin_id": "uuid-2",
+				"account_destination_id": "uuid-1", "currency": "BRL", "amount": 10, "created_at": "<<PRESENCE>>"}`,
+		},
+		{
+			name: "should return 403 when forbidden",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnReverse: func(ctx context.Context, caller model.Principal, id model.TransferID) (*usecase.TransferCreateOutput, error) {
+						return nil, usecase.ErrAuthForbidden
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 403,
+			want:       fmt.Sprintf(`{"code": 403, "message": %q}`, usecase.ErrAuthForbidden),
+		},
+		{
+			name: "should return 422 when the recipient balance is insufficient",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnReverse: func(ctx context.Context, caller model.Principal, id model.TransferID) (*usecase.TransferCreateOutput, error) {
+						return nil, usecase.ErrAccountCurrentBalanceInsufficient
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			wantStatus: 422,
+			want:       fmt.Sprintf(`{"code": 422, "message": %q}`, usecase.ErrAccountCurrentBalanceInsufficient),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trfCtrl := NewTransferController(tt.fields.trfUC, nil)
+
+			trfCtrl.Reverse(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("Reverse() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
+
+func Test_transferController_QuoteFee(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/transfer-fee-quotes", bytes.NewReader([]byte(body)))
+		return req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+	}
+	freeTransfersLeft := 0
+
+	type fields struct {
+		trfUC usecase.TransferUseCase
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "successful",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnQuoteFee: func(ctx context.Context, quoteInput usecase.TransferFeeQuoteInput) (*usecase.TransferFeeQuoteOutput, error) {
+						if quoteInput.AccountOriginID != "uuid-1" || quoteInput.Amount.Money != 10000 {
+							return nil, errors.New("should pass the current account and the amount")
+						}
+						return &usecase.TransferFeeQuoteOutput{
+							Currency:          "BRL",
+							Amount:            quoteInput.Amount,
+							Fee:               usecase.NewAmount(200),
+							Total:             usecase.NewAmount(10200),
+							FreeTransfersLeft: &freeTransfersLeft,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount":"100.00"}`),
+			},
+			wantStatus: 200,
+			want:       `{"currency": "BRL", "amount": 100, "fee": 2, "total": 102, "free_transfers_left": 0}`,
+		},
+		{
+			name: "should return 400 when amount is not positive",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnQuoteFee: func(ctx context.Context, quoteInput usecase.TransferFeeQuoteInput) (*usecase.TransferFeeQuoteOutput, error) {
+						return nil, usecase.ErrTransferAmountNotPositive
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount":0}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrTransferAmountNotPositive),
+		},
+		{
+			name: "should return 400 when amount has more than 3 decimal places",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnQuoteFee: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount":0.0001}`),
+			},
+			wantStatus: 400,
+			want:       fmt.Sprintf(`{"code": 400, "message": %q}`, usecase.ErrAmountInvalid),
+		},
+		{
+			name: "should return 500 when usecase error",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnQuoteFee: func(ctx context.Context, quoteInput usecase.TransferFeeQuoteInput) (*usecase.TransferFeeQuoteOutput, error) {
+						return nil, usecase.ErrTransferFeeQuote
+					},
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"amount":100}`),
+			},
+			wantStatus: 500,
+			want:       fmt.Sprintf(`{"code": 500, "message": %q}`, usecase.ErrTransferFeeQuote),
+		},
+		{
+			name: "should return 401 when not authenticated",
+			fields: fields{
+				trfUC: mock.TransferUseCase{
+					OnQuoteFee: nil,
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/transfer-fee-quotes", bytes.NewReader([]byte(`{"amount":100}`))),
+			},
+			wantStatus: 401,
+			want:       fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trfCtrl := NewTransferController(tt.fields.trfUC, nil)
+
+			trfCtrl.QuoteFee(tt.args.w, tt.args.r)
+
+			rec, ok := tt.args.w.(*httptest.ResponseRecorder)
+			if !ok {
+				t.Errorf("Error getting ResponseRecorder")
+			}
+
+			// Check the response status code
+			if statusCode := rec.Code; statusCode != tt.wantStatus {
+				t.Errorf("QuoteFee() statusCode = %v, wantStatus %v", statusCode, tt.wantStatus)
+			}
+
+			// Check result response
+			bodyStr := rec.Body.String()
+			ja.Assertf(bodyStr, tt.want)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package http
+
+import (
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/memory"
+)
+
+// newFXRateProvider loads the static table of exchange rates from the file, when informed, or from the configuration.
+func newFXRateProvider(fxConf config.ConfFX) (repository.FXRateProvider, error) {
+	if fxConf.RatesFile != "" {
+		return memory.NewFXRateProviderFromFile(fxConf.RatesFile)
+	}
+
+	return memory.NewFXRateProvider(fxConf.Rates)
+}
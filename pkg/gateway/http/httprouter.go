@@ -7,15 +7,19 @@ import (
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/julienschmidt/httprouter"
 	"github.com/justinas/alice"
+	"github.com/rs/zerolog/log"
 	"github.com/swaggo/http-swagger"
 
 	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/memory"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
 	redisGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/redis"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/controller"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/middleware"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/sender"
 )
 
 // NewHTTPRouterHandler creates a new http router handler.
@@ -23,6 +27,16 @@ func NewHTTPRouterHandler(
 	accCtrl controller.AccountController,
 	authCtrl controller.AuthController,
 	trfCtrl controller.TransferController,
+	cashCtrl controller.CashController,
+	schCtrl controller.ScheduledTransferController,
+	soCtrl controller.StandingOrderController,
+	ntfCtrl controller.NotificationController,
+	limitCtrl controller.TransferLimitController,
+	keyCtrl controller.PixKeyController,
+	prCtrl controller.PaymentRequestController,
+	batchCtrl controller.TransferBatchController,
+	fxCtrl controller.FXQuoteController,
+	savCtrl controller.SavingsController,
 	authUC usecase.AuthUseCase,
 	idpRepo repository.IdempotencyRepository,
 ) http.Handler {
@@ -32,15 +46,72 @@ func NewHTTPRouterHandler(
 
 	// accounts
 	router.HandlerFunc(http.MethodPost, "/accounts", middleware.Idempotency(idpRepo, accCtrl.Create))
-	router.HandlerFunc(http.MethodGet, "/accounts", accCtrl.Fetch)
-	router.HandlerFunc(http.MethodGet, "/accounts/:id/balance", accCtrl.GetBalance)
+	router.HandlerFunc(http.MethodGet, "/accounts", middleware.BearerAuth(authUC, accCtrl.Fetch))
+	router.HandlerFunc(http.MethodGet, "/accounts/:id/balance", middleware.BearerAuth(authUC, accCtrl.GetBalance))
+	router.HandlerFunc(http.MethodGet, "/accounts/:id/statement", middleware.BearerAuth(authUC, accCtrl.GetStatement))
+	router.HandlerFunc(http.MethodGet, "/accounts/:id/savings-interest", middleware.BearerAuth(authUC, savCtrl.GetHistory))
+	router.HandlerFunc(http.MethodPost, "/accounts/:id/block", middleware.BearerAuth(authUC, middleware.RequireScope(model.ScopeAccountsWrite, accCtrl.Block)))
+	router.HandlerFunc(http.MethodPost, "/accounts/:id/unblock", middleware.BearerAuth(authUC, middleware.RequireScope(model.ScopeAccountsWrite, accCtrl.Unblock)))
+	router.HandlerFunc(http.MethodPost, "/accounts/:id/close", middleware.BearerAuth(authUC, middleware.RequireScope(model.ScopeAccountsWrite, accCtrl.Close)))
+	router.HandlerFunc(http.MethodPut, "/accounts/:id/credit-limit", middleware.BearerAuth(authUC, middleware.RequireScope(model.ScopeAccountsCredit, accCtrl.SetCreditLimit)))
+	router.HandlerFunc(http.MethodPut, "/accounts/:id/tier", middleware.BearerAuth(authUC, middleware.RequireScope(model.ScopeAccountsWrite, accCtrl.SetTier)))
+	router.HandlerFunc(http.MethodGet, "/accounts/:id/transfer-limits", middleware.BearerAuth(authUC, limitCtrl.Get))
+	router.HandlerFunc(http.MethodPut, "/accounts/:id/transfer-limits", middleware.BearerAuth(authUC, middleware.RequireScope(model.ScopeAccountsWrite, limitCtrl.Set)))
 
 	// auth
 	router.HandlerFunc(http.MethodPost, "/login", authCtrl.Login)
+	router.HandlerFunc(http.MethodPost, "/token/refresh", authCtrl.RefreshToken)
+	router.HandlerFunc(http.MethodPost, "/logout", middleware.BearerAuth(authUC, authCtrl.Logout))
+	router.HandlerFunc(http.MethodGet, "/.well-known/jwks.json", authCtrl.JWKS)
 
 	// transfer
 	router.HandlerFunc(http.MethodPost, "/transfers", middleware.BearerAuth(authUC, middleware.Idempotency(idpRepo, trfCtrl.Create)))
 	router.HandlerFunc(http.MethodGet, "/transfers", middleware.BearerAuth(authUC, trfCtrl.Fetch))
+	router.HandlerFunc(http.MethodPost, "/transfers/:id/refund", middleware.BearerAuth(authUC, middleware.Idempotency(idpRepo, trfCtrl.Refund)))
+	router.HandlerFunc(http.MethodPost, "/transfers/:id/reverse", middleware.BearerAuth(authUC, middleware.RequireScope(model.ScopeTransfersReverse, middleware.Idempotency(idpRepo, trfCtrl.Reverse))))
+	router.HandlerFunc(http.MethodPost, "/transfer-fee-quotes", middleware.BearerAuth(authUC, trfCtrl.QuoteFee))
+
+	// fx quotes
+	router.HandlerFunc(http.MethodPost, "/fx-quotes", middleware.BearerAuth(authUC, middleware.Idempotency(idpRepo, fxCtrl.Create)))
+
+	// pix keys
+	router.HandlerFunc(http.MethodPost, "/pix-keys", middleware.BearerAuth(authUC, keyCtrl.Create))
+	router.HandlerFunc(http.MethodGet, "/pix-keys", middleware.BearerAuth(authUC, keyCtrl.Fetch))
+	router.HandlerFunc(http.MethodGet, "/pix-keys/:key", middleware.BearerAuth(authUC, keyCtrl.Lookup))
+	router.HandlerFunc(http.MethodDelete, "/pix-keys/:key", middleware.BearerAuth(authUC, keyCtrl.Delete))
+	router.HandlerFunc(http.MethodPost, "/pix-keys/:key/verify", middleware.BearerAuth(authUC, keyCtrl.Verify))
+	router.HandlerFunc(http.MethodGet, "/pix-keys/:key/brcode", middleware.BearerAuth(authUC, keyCtrl.CreateBRCode))
+
+	// scheduled transfers
+	router.HandlerFunc(http.MethodPost, "/scheduled-transfers", middleware.BearerAuth(authUC, middleware.Idempotency(idpRepo, schCtrl.Create)))
+	router.HandlerFunc(http.MethodGet, "/scheduled-transfers", middleware.BearerAuth(authUC, schCtrl.Fetch))
+	router.HandlerFunc(http.MethodPost, "/scheduled-transfers/:id/cancel", middleware.BearerAuth(authUC, schCtrl.Cancel))
+
+	// transfer batches
+	router.HandlerFunc(http.MethodPost, "/transfer-batches", middleware.BearerAuth(authUC, middleware.Idempotency(idpRepo, batchCtrl.Create)))
+	router.HandlerFunc(http.MethodGet, "/transfer-batches/:id", middleware.BearerAuth(authUC, batchCtrl.Get))
+
+	// payment requests
+	router.HandlerFunc(http.MethodPost, "/payment-requests", middleware.BearerAuth(authUC, middleware.Idempotency(idpRepo, prCtrl.Create)))
+	router.HandlerFunc(http.MethodGet, "/payment-requests", middleware.BearerAuth(authUC, prCtrl.Fetch))
+	router.HandlerFunc(http.MethodGet, "/payment-requests/:id", middleware.BearerAuth(authUC, prCtrl.Get))
+	router.HandlerFunc(http.MethodPost, "/payment-requests/:id/approve", middleware.BearerAuth(authUC, middleware.Idempotency(idpRepo, prCtrl.Approve)))
+	router.HandlerFunc(http.MethodPost, "/payment-requests/:id/decline", middleware.BearerAuth(authUC, prCtrl.Decline))
+
+	// standing orders
+	router.HandlerFunc(http.MethodPost, "/standing-orders", middleware.BearerAuth(authUC, middleware.Idempotency(idpRepo, soCtrl.Create)))
+	router.HandlerFunc(http.MethodGet, "/standing-orders", middleware.BearerAuth(authUC, soCtrl.Fetch))
+	router.HandlerFunc(http.MethodPatch, "/standing-orders/:id", middleware.BearerAuth(authUC, soCtrl.Update))
+	router.HandlerFunc(http.MethodPost, "/standing-orders/:id/pause", middleware.BearerAuth(authUC, soCtrl.Pause))
+	router.HandlerFunc(http.MethodPost, "/standing-orders/:id/resume", middleware.BearerAuth(authUC, soCtrl.Resume))
+	router.HandlerFunc(http.MethodPost, "/standing-orders/:id/cancel", middleware.BearerAuth(authUC, soCtrl.Cancel))
+
+	// notifications
+	router.HandlerFunc(http.MethodGet, "/notifications", middleware.BearerAuth(authUC, ntfCtrl.Fetch))
+
+	// cash
+	router.HandlerFunc(http.MethodPost, "/accounts/:id/deposits", middleware.BearerAuth(authUC, middleware.RequireScope(model.ScopeCashDeposit, middleware.Idempotency(idpRepo, cashCtrl.Deposit))))
+	router.HandlerFunc(http.MethodPost, "/withdrawals", middleware.BearerAuth(authUC, middleware.Idempotency(idpRepo, cashCtrl.Withdraw)))
 
 	router.HandlerFunc(http.MethodGet, "/swagger/*any", httpSwagger.WrapHandler)
 	router.HandlerFunc(http.MethodGet, "/", func(w http.ResponseWriter, r *http.Request) {
@@ -53,20 +124,101 @@ func NewHTTPRouterHandler(
 	return c.Then(router)
 }
 
+// HandlerOptions holds the settings of the use cases behind the handler. The zero values disable the optional features.
+type HandlerOptions struct {
+	FX             config.ConfFX
+	TransferLimits usecase.TransferLimitPolicy
+	Fees           usecase.FeePolicy
+	Savings        usecase.SavingsPolicy
+}
+
 // GetHTTPHandler instantiates the repos, ucs and controllers and returns a handler.
-func GetHTTPHandler(dbPool *pgxpool.Pool, redisClient *redis.Client, authConf config.ConfAuth) http.Handler {
+func GetHTTPHandler(dbPool *pgxpool.Pool, redisClient *redis.Client, authConf config.ConfAuth, opts HandlerOptions) http.Handler {
 	accRepo := postgres.NewAccountRepository(dbPool)
-	accUC := usecase.NewAccountUseCase(accRepo)
+	ledgerRepo := postgres.NewLedgerRepository(dbPool)
+	accUC := usecase.NewAccountUseCase(accRepo, ledgerRepo)
 	accCtrl := controller.NewAccountController(accUC)
 
-	authUC := usecase.NewAuthUseCase(authConf.SecretKey, authConf.AccessTokenDur, accRepo)
+	tknRepo := redisGateway.NewTokenRepository(redisClient)
+	authKeySet, err := newAuthKeySet(authConf)
+	if err != nil {
+		log.Fatal().Stack().Err(err).Msg("error loading auth keys")
+	}
+	var attRepo repository.LoginAttemptRepository
+	if authConf.LoginAttemptsStore == "memory" {
+		attRepo = memory.NewLoginAttemptRepository()
+	} else {
+		attRepo = redisGateway.NewLoginAttemptRepository(redisClient)
+	}
+	lockoutPolicy := usecase.LoginLockoutPolicy{
+		MaxFailuresPerCPF: authConf.LoginMaxFailuresPerCPF,
+		MaxFailuresPerIP:  authConf.LoginMaxFailuresPerIP,
+		BaseLockout:       authConf.LoginBaseLockout,
+		MaxLockout:        authConf.LoginMaxLockout,
+		FailuresWindow:    authConf.LoginFailuresWindow,
+	}
+	authUC := usecase.NewAuthUseCase(
+		authKeySet,
+		authConf.AccessTokenDur,
+		authConf.RefreshTokenDur,
+		lockoutPolicy,
+		accRepo,
+		tknRepo,
+		attRepo,
+	)
 	authCtrl := controller.NewAuthController(authUC)
 
 	trfRepo := postgres.NewTransferRepository(dbPool)
-	trfUC := usecase.NewTransferUseCase(trfRepo, accRepo)
+	limitRepo := postgres.NewTransferLimitRepository(dbPool)
+	keyRepo := postgres.NewPixKeyRepository(dbPool)
+	quoteRepo := postgres.NewFXQuoteRepository(dbPool)
+	trfUC := usecase.NewTransferUseCase(trfRepo, accRepo, ledgerRepo, limitRepo, keyRepo, quoteRepo, opts.TransferLimits, opts.Fees)
 	trfCtrl := controller.NewTransferController(trfUC, authUC)
 
+	rateProvider, err := newFXRateProvider(opts.FX)
+	if err != nil {
+		log.Fatal().Stack().Err(err).Msg("error loading exchange rates")
+	}
+	quoteUC := usecase.NewFXQuoteUseCase(quoteRepo, accRepo, rateProvider, opts.FX.QuoteTTL)
+	fxCtrl := controller.NewFXQuoteController(quoteUC)
+
+	limitUC := usecase.NewTransferLimitUseCase(limitRepo, trfRepo, accRepo, opts.TransferLimits)
+	limitCtrl := controller.NewTransferLimitController(limitUC)
+
+	// there's no email provider yet, so the verification codes are only logged
+	keyUC := usecase.NewPixKeyUseCase(keyRepo, accRepo, sender.NewLogPixKeyVerificationSender())
+	keyCtrl := controller.NewPixKeyController(keyUC)
+
+	schRepo := postgres.NewScheduledTransferRepository(dbPool)
+	schUC := usecase.NewScheduledTransferUseCase(schRepo, trfRepo, accRepo, ledgerRepo, limitRepo, opts.TransferLimits, opts.Fees)
+	schCtrl := controller.NewScheduledTransferController(schUC)
+
+	batchRepo := postgres.NewTransferBatchRepository(dbPool)
+	batchUC := usecase.NewTransferBatchUseCase(batchRepo, trfRepo, accRepo, ledgerRepo, limitRepo, opts.TransferLimits, opts.Fees)
+	batchCtrl := controller.NewTransferBatchController(batchUC)
+
+	prRepo := postgres.NewPaymentRequestRepository(dbPool)
+	prUC := usecase.NewPaymentRequestUseCase(prRepo, trfRepo, accRepo, ledgerRepo, limitRepo, opts.TransferLimits, opts.Fees)
+	prCtrl := controller.NewPaymentRequestController(prUC)
+
+	ntfRepo := postgres.NewNotificationRepository(dbPool)
+	ntfUC := usecase.NewNotificationUseCase(ntfRepo)
+	ntfCtrl := controller.NewNotificationController(ntfUC)
+
+	// the occurrences are only executed by the worker, so the retry policy is not needed here
+	soRepo := postgres.NewStandingOrderRepository(dbPool)
+	soUC := usecase.NewStandingOrderUseCase(soRepo, trfRepo, accRepo, ledgerRepo, ntfRepo, limitRepo, opts.TransferLimits, opts.Fees, usecase.StandingOrderRetryPolicy{})
+	soCtrl := controller.NewStandingOrderController(soUC)
+
+	cashRepo := postgres.NewCashRepository(dbPool)
+	cashUC := usecase.NewCashUseCase(cashRepo, accRepo, ledgerRepo, trfRepo, limitRepo, opts.TransferLimits)
+	cashCtrl := controller.NewCashController(cashUC)
+
+	savRepo := postgres.NewSavingsRepository(dbPool)
+	savUC := usecase.NewSavingsUseCase(savRepo, accRepo, ledgerRepo, opts.Savings)
+	savCtrl := controller.NewSavingsController(savUC)
+
 	idpRepo := redisGateway.NewIdempotencyRepository(redisClient)
 
-	return NewHTTPRouterHandler(accCtrl, authCtrl, trfCtrl, authUC, idpRepo)
+	return NewHTTPRouterHandler(accCtrl, authCtrl, trfCtrl, cashCtrl, schCtrl, soCtrl, ntfCtrl, limitCtrl, keyCtrl, prCtrl, batchCtrl, fxCtrl, savCtrl, authUC, idpRepo)
 }
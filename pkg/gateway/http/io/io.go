@@ -2,7 +2,10 @@ package io
 
 import (
 	"encoding/json"
+	"mime"
 	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/rs/zerolog"
 )
@@ -10,6 +13,13 @@ import (
 const (
 	contentType     = "Content-Type"
 	jsonContentType = "application/json"
+
+	// V2JSONContentType is the media type that represents the amounts as decimal strings, like "1234.56".
+	// Clients opt in to it through the Accept header.
+	V2JSONContentType = "application/vnd.springfield-bank.v2+json"
+
+	headerAccept      = "Accept"
+	headerDeprecation = "Deprecation"
 )
 
 // ErrorOutput represents the output data in case of error.
@@ -18,6 +28,11 @@ type ErrorOutput struct {
 	Message string `json:"message" example:"something wrong happened"`
 }
 
+// decimalStringer is implemented by the amount types that can be written as decimal strings.
+type decimalStringer interface {
+	UseDecimalString()
+}
+
 // ReadInput reads the JSON-encoded value from request and stores it in the value pointed to by value.
 func ReadInput(r *http.Request, logger *zerolog.Logger, value interface{}) error {
 	if err := json.NewDecoder(r.Body).Decode(value); err != nil {
@@ -32,9 +47,26 @@ func ReadInput(r *http.Request, logger *zerolog.Logger, value interface{}) error
 	return nil
 }
 
-// WriteSuccess writes a success result to the http.ResponseWriter
-func WriteSuccess(w http.ResponseWriter, logger *zerolog.Logger, statusCode int, result interface{}) {
-	w.Header().Set(contentType, jsonContentType)
+// WriteSuccess writes a success result to the http.ResponseWriter.
+//
+// If the request accepts V2JSONContentType, the amounts are written as decimal strings.
+// Otherwise, they are written as JSON numbers and the response is flagged with the Deprecation header.
+func WriteSuccess(w http.ResponseWriter, r *http.Request, logger *zerolog.Logger, statusCode int, result interface{}) {
+	if AcceptsV2(r) {
+		visitAmounts(reflect.ValueOf(result), func(amount decimalStringer) {
+			amount.UseDecimalString()
+		})
+		w.Header().Set(contentType, V2JSONContentType)
+	} else {
+		hasAmounts := false
+		visitAmounts(reflect.ValueOf(result), func(decimalStringer) {
+			hasAmounts = true
+		})
+		if hasAmounts {
+			w.Header().Set(headerDeprecation, "true")
+		}
+		w.Header().Set(contentType, jsonContentType)
+	}
 	w.WriteHeader(statusCode)
 
 	if err := json.NewEncoder(w).Encode(result); err != nil {
@@ -56,3 +88,41 @@ func WriteErrorMsg(w http.ResponseWriter, logger *zerolog.Logger, statusCode int
 		logger.Error().Stack().Err(err).Msg("error encoding response")
 	}
 }
+
+// AcceptsV2 tells if the request Accept header asks for the V2JSONContentType representation.
+func AcceptsV2(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get(headerAccept), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == V2JSONContentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// visitAmounts calls fn for every addressable decimalStringer reachable from v.
+func visitAmounts(v reflect.Value, fn func(decimalStringer)) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			visitAmounts(v.Elem(), fn)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			visitAmounts(v.Index(i), fn)
+		}
+	case reflect.Struct:
+		if v.CanAddr() {
+			if amount, ok := v.Addr().Interface().(decimalStringer); ok {
+				fn(amount)
+				return
+			}
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				visitAmounts(v.Field(i), fn)
+			}
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/rs/zerolog/hlog"
 
 	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
 )
@@ -34,6 +35,31 @@ func BearerAuth(authUC usecase.AuthUseCase, next http.HandlerFunc) http.HandlerF
 			return
 		}
 
-		next(w, r.WithContext(appcontext.WithAuthSubject(r.Context(), tokenClaims.Subject)))
+		ctx := appcontext.WithPrincipal(r.Context(), tokenClaims.Principal())
+		ctx = appcontext.WithAuthTokenID(ctx, tokenClaims.ID)
+
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// RequireScope only calls next if the principal authenticated by BearerAuth was granted the scope.
+// It must be wrapped by BearerAuth.
+func RequireScope(scope model.Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := hlog.FromRequest(r)
+
+		principal, ok := appcontext.GetPrincipal(r.Context())
+		if !ok {
+			io.WriteErrorMsg(w, logger, http.StatusUnauthorized, usecase.ErrAuthInvalidAccessToken.Error())
+			return
+		}
+
+		if !principal.HasScope(scope) {
+			logger.Warn().Str("accountID", string(principal.AccountID)).Str("scope", string(scope)).Msg("missing required scope")
+			io.WriteErrorMsg(w, logger, http.StatusForbidden, usecase.ErrAuthForbidden.Error())
+			return
+		}
+
+		next(w, r)
 	}
 }
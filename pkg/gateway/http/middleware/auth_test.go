@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
+)
+
+func Test_BearerAuth_RequireScope(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	authUC := mock.AuthUseCase{
+		OnAuthorize: func(ctx context.Context, accessToken string) (*usecase.AuthClaims, error) {
+			switch accessToken {
+			case "operator-token":
+				return &usecase.AuthClaims{
+					RegisteredClaims: jwt.RegisteredClaims{Subject: "uuid-1", ID: "jti-1"},
+					Roles:            []model.Role{model.RoleOperator},
+					Scope:            "accounts:read",
+				}, nil
+			case "customer-token":
+				return &usecase.AuthClaims{
+					RegisteredClaims: jwt.RegisteredClaims{Subject: "uuid-2", ID: "jti-2"},
+				}, nil
+			}
+			return nil, usecase.ErrAuthInvalidAccessToken
+		},
+	}
+
+	next := func(w http.ResponseWriter, r *http.Request) {
+		principal, _ := appcontext.GetPrincipal(r.Context())
+		tokenID, _ := appcontext.GetAuthTokenID(r.Context())
+		_, _ = fmt.Fprintf(w, `{"account_id": %q, "token_id": %q}`, principal.AccountID, tokenID)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		want          string
+	}{
+		{
+			name:          "granted scope should call next with the principal",
+			authorization: "Bearer operator-token",
+			wantStatus:    200,
+			want:          `{"account_id": "uuid-1", "token_id": "jti-1"}`,
+		},
+		{
+			name:          "missing scope should return 403",
+			authorization: "Bearer customer-token",
+			wantStatus:    403,
+			want:          fmt.Sprintf(`{"code": 403, "message": %q}`, usecase.ErrAuthForbidden),
+		},
+		{
+			name:          "invalid token should return 401",
+			authorization: "Bearer any-token",
+			wantStatus:    401,
+			want:          fmt.Sprintf(`{"code": 401, "message": %q}`, usecase.ErrAuthInvalidAccessToken),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := BearerAuth(authUC, RequireScope(model.ScopeAccountsRead, next))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", tt.authorization)
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("statusCode = %v, wantStatus %v", rec.Code, tt.wantStatus)
+			}
+			ja.Assertf(rec.Body.String(), tt.want)
+		})
+	}
+}
+
+func Test_RequireScope_withoutBearerAuth(t *testing.T) {
+	t.Parallel()
+
+	handler := RequireScope(model.ScopeAccountsRead, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("next should not be called")
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("statusCode = %v, wantStatus %v", rec.Code, http.StatusUnauthorized)
+	}
+}
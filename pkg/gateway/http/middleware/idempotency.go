@@ -14,6 +14,7 @@ import (
 
 	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http/io"
 )
 
 const (
@@ -21,9 +22,13 @@ const (
 	headerIdempotencyKey   = "X-Idempotency-Key"
 	headerIdempotencyCache = "X-Idempotency-Cache"
 	cacheHit               = "HIT"
+
+	errIdempotencyAcceptMismatch = "the X-Idempotency-Key was already used with another Accept header"
 )
 
+// response is the cached response of a request, with the Accept header it was rendered for.
 type response struct {
+	Accept     string
 	StatusCode int
 	Headers    http.Header
 	Body       []byte
@@ -35,13 +40,15 @@ func generateHashKey(r *http.Request) string {
 		return ""
 	}
 
-	sub, _ := appcontext.GetAuthSubject(r.Context())
+	principal, _ := appcontext.GetPrincipal(r.Context())
+	sub := string(principal.AccountID)
 	hashKeyBytes := sha1.Sum([]byte(sub + "." + idempotencyKey + "." + r.Method + "." + r.RequestURI))
 	return hex.EncodeToString(hashKeyBytes[:])
 }
 
-func saveResponse(ctx context.Context, idpRepo repository.IdempotencyRepository, rec *httptest.ResponseRecorder, hashKey string) (*response, error) {
+func saveResponse(ctx context.Context, idpRepo repository.IdempotencyRepository, rec *httptest.ResponseRecorder, hashKey, accept string) (*response, error) {
 	resp := &response{
+		Accept:     accept,
 		StatusCode: rec.Code,
 		Headers:    rec.Header(),
 		Body:       rec.Body.Bytes(),
@@ -61,6 +68,8 @@ func saveResponse(ctx context.Context, idpRepo repository.IdempotencyRepository,
 }
 
 // Idempotency returns the same result for requests with the same uri, user and X-Idempotency-Key header.
+// The result is only rendered for the Accept header of the first request, so a retry with another one is rejected
+// with 409 Conflict instead of executed again.
 //
 // Fallbacks to original request processing in case of errors.
 func Idempotency(idpRepo repository.IdempotencyRepository, next http.HandlerFunc) http.HandlerFunc {
@@ -82,12 +91,16 @@ func Idempotency(idpRepo repository.IdempotencyRepository, next http.HandlerFunc
 				next(w, r)
 				return
 			}
+			if resp.Accept != r.Header.Get("Accept") {
+				io.WriteErrorMsg(w, logger, http.StatusConflict, errIdempotencyAcceptMismatch)
+				return
+			}
 			resp.Headers.Add(headerIdempotencyCache, cacheHit)
 		} else {
 			rec := httptest.NewRecorder()
 			next(rec, r)
 
-			resp, err = saveResponse(r.Context(), idpRepo, rec, hashKey)
+			resp, err = saveResponse(r.Context(), idpRepo, rec, hashKey, r.Header.Get("Accept"))
 			if err != nil {
 				logger.Error().Err(err).Interface("resp", resp).Msg("Could not cache response.")
 				next(w, r)
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/appcontext"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository/mock"
+)
+
+func Test_Idempotency(t *testing.T) {
+	t.Parallel()
+
+	ja := jsonassert.New(t)
+
+	cache := make(map[string][]byte)
+	idpRepo := mock.IdempotencyRepository{
+		OnGet: func(ctx context.Context, key string) ([]byte, error) {
+			value, ok := cache[key]
+			if !ok {
+				return nil, errors.New("key not found")
+			}
+			return value, nil
+		},
+		OnSet: func(ctx context.Context, key string, value []byte, duration time.Duration) error {
+			cache[key] = value
+			return nil
+		},
+	}
+
+	executions := 0
+	next := func(w http.ResponseWriter, r *http.Request) {
+		executions++
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"execution": %d, "accept": %q}`, executions, r.Header.Get("Accept"))
+	}
+
+	handler := Idempotency(idpRepo, next)
+
+	// the requests run in order, each one against the responses cached by the previous ones
+	tests := []struct {
+		name           string
+		idempotencyKey string
+		accept         string
+		wantStatus     int
+		wantCache      string
+		want           string
+		wantExecutions int
+	}{
+		{
+			name:           "first request should execute the handler",
+			idempotencyKey: "key-1",
+			accept:         "application/json",
+			wantStatus:     201,
+			want:           `{"execution": 1, "accept": "application/json"}`,
+			wantExecutions: 1,
+		},
+		{
+			name:           "replay with the same Accept should return the cached response",
+			idempotencyKey: "key-1",
+			accept:         "application/json",
+			wantStatus:     201,
+			wantCache:      cacheHit,
+			want:           `{"execution": 1, "accept": "application/json"}`,
+			wantExecutions: 1,
+		},
+		{
+			name:           "replay with another Accept should return 409 without executing the handler",
+			idempotencyKey: "key-1",
+			accept:         "application/vnd.springfield-bank.v2+json",
+			wantStatus:     409,
+			want:           fmt.Sprintf(`{"code": 409, "message": %q}`, errIdempotencyAcceptMismatch),
+			wantExecutions: 1,
+		},
+		{
+			name:           "request with another key should execute the handler",
+			idempotencyKey: "key-2",
+			accept:         "application/vnd.springfield-bank.v2+json",
+			wantStatus:     201,
+			want:           `{"execution": 2, "accept": "application/vnd.springfield-bank.v2+json"}`,
+			wantExecutions: 2,
+		},
+		{
+			name:           "request without key should always execute the handler",
+			accept:         "application/json",
+			wantStatus:     201,
+			want:           `{"execution": 3, "accept": "application/json"}`,
+			wantExecutions: 3,
+		},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/transfers", nil)
+		req = req.WithContext(appcontext.WithPrincipal(req.Context(), model.Principal{AccountID: "uuid-1"}))
+		req.Header.Set("Accept", tt.accept)
+		if tt.idempotencyKey != "" {
+			req.Header.Set(headerIdempotencyKey, tt.idempotencyKey)
+		}
+		rec := httptest.NewRecorder()
+
+		handler(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: status = %v, want %v", tt.name, rec.Code, tt.wantStatus)
+		}
+		if got := rec.Header().Get(headerIdempotencyCache); got != tt.wantCache {
+			t.Errorf("%s: %s = %q, want %q", tt.name, headerIdempotencyCache, got, tt.wantCache)
+		}
+		ja.Assertf(rec.Body.String(), tt.want)
+		if executions != tt.wantExecutions {
+			t.Errorf("%s: executions = %v, want %v", tt.name, executions, tt.wantExecutions)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package sender
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+)
+
+type logPixKeyVerificationSender struct{}
+
+// NewLogPixKeyVerificationSender instantiates a new PixKeyVerificationSender that writes the codes to the log
+// instead of sending them. There's no email provider yet, so it must only be used in development.
+func NewLogPixKeyVerificationSender() repository.PixKeyVerificationSender {
+	return &logPixKeyVerificationSender{}
+}
+
+func (keySender logPixKeyVerificationSender) SendVerificationCode(ctx context.Context, key *model.PixKey, code string) error {
+	log.Ctx(ctx).Info().Str("keyID", string(key.ID)).Str("to", key.Value).Str("code", code).
+		Time("expiresAt", key.VerificationExpiresAt).Msg("pix key verification code")
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+)
+
+func Test_logPixKeyVerificationSender_SendVerificationCode(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	ctx := logger.WithContext(context.Background())
+
+	key := model.NewPixKey("uuid-1", model.PixKeyTypeEmail, "homer@springfield.com")
+	err := NewLogPixKeyVerificationSender().SendVerificationCode(ctx, key, "123456")
+	if err != nil {
+		t.Fatalf("SendVerificationCode() error = %v, want nil", err)
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, `"to":"homer@springfield.com"`) || !strings.Contains(got, `"code":"123456"`) {
+		t.Errorf("SendVerificationCode() logged %s, want the address and the code", got)
+	}
+}
@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DueExecutor is implemented by the use cases with work due at a given time, like the scheduled transfers.
+type DueExecutor interface {
+	// ExecuteDue executes up to limit due items, returning how many were processed.
+	ExecuteDue(ctx context.Context, limit int) (int, error)
+}
+
+// Executor periodically executes the due items of a DueExecutor.
+type Executor struct {
+	name      string
+	due       DueExecutor
+	interval  time.Duration
+	batchSize int
+}
+
+// NewExecutor instantiates a new Executor. The name identifies it on the logs.
+func NewExecutor(name string, due DueExecutor, interval time.Duration, batchSize int) *Executor {
+	return &Executor{
+		name:      name,
+		due:       due,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run executes the due items right away and then on every interval, until ctx is done.
+func (e *Executor) Run(ctx context.Context) {
+	logger := log.With().Str("executor", e.name).Logger()
+	logger.Info().Dur("interval", e.interval).Int("batchSize", e.batchSize).Msg("Executor started")
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.executeDue(logger.WithContext(ctx))
+
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg("Executor stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// executeDue runs rounds of up to batchSize items while there may be more due ones.
+// On error, the remaining items wait for the next interval.
+func (e *Executor) executeDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := e.due.ExecuteDue(ctx, e.batchSize)
+		if err != nil || processed < e.batchSize {
+			return
+		}
+	}
+}
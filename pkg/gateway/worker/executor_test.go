@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase/mock"
+)
+
+func TestExecutor_executeDue(t *testing.T) {
+	tests := []struct {
+		name      string
+		batchSize int
+		processed []int
+		err       error
+		wantCalls int
+	}{
+		{
+			name:      "should stop when nothing is due",
+			batchSize: 10,
+			processed: []int{0},
+			wantCalls: 1,
+		},
+		{
+			name:      "should stop after a partial batch",
+			batchSize: 10,
+			processed: []int{3},
+			wantCalls: 1,
+		},
+		{
+			name:      "should keep going while the batches are full",
+			batchSize: 2,
+			processed: []int{2, 2, 1},
+			wantCalls: 3,
+		},
+		{
+			name:      "should stop on error",
+			batchSize: 2,
+			processed: []int{2, 0},
+			err:       errors.New("any error"),
+			wantCalls: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			schUC := mock.ScheduledTransferUseCase{
+				OnExecuteDue: func(ctx context.Context, limit int) (int, error) {
+					if limit != tt.batchSize {
+						t.Errorf("ExecuteDue() limit = %v, want %v", limit, tt.batchSize)
+					}
+					processed := tt.processed[calls]
+					calls++
+					if calls == len(tt.processed) {
+						return processed, tt.err
+					}
+					return processed, nil
+				},
+			}
+
+			e := NewExecutor("any", schUC, time.Minute, tt.batchSize)
+			e.executeDue(context.Background())
+
+			if calls != tt.wantCalls {
+				t.Errorf("executeDue() calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestExecutor_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	schUC := mock.ScheduledTransferUseCase{
+		OnExecuteDue: func(_ context.Context, _ int) (int, error) {
+			calls++
+			if calls == 2 {
+				cancel()
+			}
+			return 0, nil
+		},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		NewExecutor("any", schUC, time.Millisecond, 10).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not stop after the ctx was cancelled")
+	}
+
+	if calls != 2 {
+		t.Errorf("Run() calls = %v, want 2", calls)
+	}
+}
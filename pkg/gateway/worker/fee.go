@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+)
+
+// GetMaintenanceFeeExecutor instantiates the repos and the uc and returns the executor of the monthly maintenance fee charges.
+func GetMaintenanceFeeExecutor(dbPool *pgxpool.Pool, schedulerConf config.ConfScheduler, feePolicy usecase.FeePolicy) *Executor {
+	accRepo := postgres.NewAccountRepository(dbPool)
+	ledgerRepo := postgres.NewLedgerRepository(dbPool)
+	feeRepo := postgres.NewFeeRepository(dbPool)
+	feeUC := usecase.NewMaintenanceFeeUseCase(feeRepo, accRepo, ledgerRepo, feePolicy)
+
+	return NewExecutor("maintenance-fees", feeUC, schedulerConf.Interval, schedulerConf.BatchSize)
+}
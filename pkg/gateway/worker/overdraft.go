@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+)
+
+// GetOverdraftInterestExecutor instantiates the repos and the uc and returns the executor of the daily overdraft interest charges.
+func GetOverdraftInterestExecutor(dbPool *pgxpool.Pool, schedulerConf config.ConfScheduler, interestPolicy usecase.OverdraftInterestPolicy) *Executor {
+	accRepo := postgres.NewAccountRepository(dbPool)
+	ledgerRepo := postgres.NewLedgerRepository(dbPool)
+	odRepo := postgres.NewOverdraftRepository(dbPool)
+	odUC := usecase.NewOverdraftUseCase(odRepo, accRepo, ledgerRepo, interestPolicy)
+
+	return NewExecutor("overdraft-interest", odUC, schedulerConf.Interval, schedulerConf.BatchSize)
+}
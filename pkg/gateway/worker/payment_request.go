@@ -0,0 +1,21 @@
+package worker
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+)
+
+// GetPaymentRequestExecutor instantiates the repos and the uc and returns the executor closing the expired payment requests.
+func GetPaymentRequestExecutor(dbPool *pgxpool.Pool, schedulerConf config.ConfScheduler, limitPolicy usecase.TransferLimitPolicy, feePolicy usecase.FeePolicy) *Executor {
+	accRepo := postgres.NewAccountRepository(dbPool)
+	ledgerRepo := postgres.NewLedgerRepository(dbPool)
+	trfRepo := postgres.NewTransferRepository(dbPool)
+	prRepo := postgres.NewPaymentRequestRepository(dbPool)
+	limitRepo := postgres.NewTransferLimitRepository(dbPool)
+	prUC := usecase.NewPaymentRequestUseCase(prRepo, trfRepo, accRepo, ledgerRepo, limitRepo, limitPolicy, feePolicy)
+
+	return NewExecutor("payment-requests", prUC, schedulerConf.Interval, schedulerConf.BatchSize)
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+)
+
+// GetSavingsInterestExecutor instantiates the repos and the uc and returns the executor of the daily savings interest accruals and their monthly payments.
+func GetSavingsInterestExecutor(dbPool *pgxpool.Pool, schedulerConf config.ConfScheduler, savingsPolicy usecase.SavingsPolicy) *Executor {
+	accRepo := postgres.NewAccountRepository(dbPool)
+	ledgerRepo := postgres.NewLedgerRepository(dbPool)
+	savRepo := postgres.NewSavingsRepository(dbPool)
+	savUC := usecase.NewSavingsUseCase(savRepo, accRepo, ledgerRepo, savingsPolicy)
+
+	return NewExecutor("savings-interest", savUC, schedulerConf.Interval, schedulerConf.BatchSize)
+}
@@ -0,0 +1,21 @@
+package worker
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+)
+
+// GetScheduledTransferExecutor instantiates the repos and the uc and returns the executor of the due scheduled transfers.
+func GetScheduledTransferExecutor(dbPool *pgxpool.Pool, schedulerConf config.ConfScheduler, limitPolicy usecase.TransferLimitPolicy, feePolicy usecase.FeePolicy) *Executor {
+	accRepo := postgres.NewAccountRepository(dbPool)
+	ledgerRepo := postgres.NewLedgerRepository(dbPool)
+	trfRepo := postgres.NewTransferRepository(dbPool)
+	schRepo := postgres.NewScheduledTransferRepository(dbPool)
+	limitRepo := postgres.NewTransferLimitRepository(dbPool)
+	schUC := usecase.NewScheduledTransferUseCase(schRepo, trfRepo, accRepo, ledgerRepo, limitRepo, limitPolicy, feePolicy)
+
+	return NewExecutor("scheduled-transfers", schUC, schedulerConf.Interval, schedulerConf.BatchSize)
+}
@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+)
+
+// GetStandingOrderExecutor instantiates the repos and the uc and returns the executor of the due standing order occurrences.
+func GetStandingOrderExecutor(dbPool *pgxpool.Pool, schedulerConf config.ConfScheduler, limitPolicy usecase.TransferLimitPolicy, feePolicy usecase.FeePolicy) *Executor {
+	accRepo := postgres.NewAccountRepository(dbPool)
+	ledgerRepo := postgres.NewLedgerRepository(dbPool)
+	trfRepo := postgres.NewTransferRepository(dbPool)
+	ntfRepo := postgres.NewNotificationRepository(dbPool)
+	soRepo := postgres.NewStandingOrderRepository(dbPool)
+	limitRepo := postgres.NewTransferLimitRepository(dbPool)
+	retryPolicy := usecase.StandingOrderRetryPolicy{
+		MaxRetries: schedulerConf.StandingOrderRetry.MaxRetries,
+		Interval:   schedulerConf.StandingOrderRetry.Interval,
+	}
+	soUC := usecase.NewStandingOrderUseCase(soRepo, trfRepo, accRepo, ledgerRepo, ntfRepo, limitRepo, limitPolicy, feePolicy, retryPolicy)
+
+	return NewExecutor("standing-orders", soUC, schedulerConf.Interval, schedulerConf.BatchSize)
+}
@@ -0,0 +1,75 @@
+package monitoring
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// LoginFailures counts the failed login attempts.
+	LoginFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "springfield_bank",
+		Name:      "login_failures_total",
+		Help:      "The total number of failed login attempts.",
+	})
+	// LoginLockouts counts the CPFs or client IPs locked out after too many failed login attempts.
+	LoginLockouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "springfield_bank",
+		Name:      "login_lockouts_total",
+		Help:      "The total number of lockouts after too many failed login attempts, by scope (cpf or ip).",
+	}, []string{"scope"})
+	// LoginUnlocks counts the previously locked out CPFs or client IPs unlocked by a successful login.
+	LoginUnlocks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "springfield_bank",
+		Name:      "login_unlocks_total",
+		Help:      "The total number of previously locked out CPFs or client IPs unlocked by a successful login, by scope (cpf or ip).",
+	}, []string{"scope"})
+	// LoginLockedRejections counts the login attempts rejected because of a lockout.
+	LoginLockedRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "springfield_bank",
+		Name:      "login_locked_rejections_total",
+		Help:      "The total number of login attempts rejected while locked out, by scope (cpf or ip).",
+	}, []string{"scope"})
+	// ScheduledTransfersProcessed counts the scheduled transfers processed by the executor.
+	ScheduledTransfersProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "springfield_bank",
+		Name:      "scheduled_transfers_processed_total",
+		Help:      "The total number of scheduled transfers processed, by outcome (executed or failed).",
+	}, []string{"status"})
+	// StandingOrderOccurrencesProcessed counts the standing order occurrences processed by the executor.
+	StandingOrderOccurrencesProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "springfield_bank",
+		Name:      "standing_order_occurrences_processed_total",
+		Help:      "The total number of standing order occurrences processed, by outcome (executed, retrying or failed).",
+	}, []string{"outcome"})
+	// OverdraftInterestCharges counts the daily interest charges on the overdrawn accounts.
+	OverdraftInterestCharges = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "springfield_bank",
+		Name:      "overdraft_interest_charges_total",
+		Help:      "The total number of daily interest charges on the overdrawn accounts.",
+	})
+	// MaintenanceFeeCharges counts the monthly maintenance fees charged on the accounts.
+	MaintenanceFeeCharges = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "springfield_bank",
+		Name:      "maintenance_fee_charges_total",
+		Help:      "The total number of monthly maintenance fees charged on the accounts.",
+	})
+	// SavingsInterestAccruals counts the daily savings interest accruals on the positive balances.
+	SavingsInterestAccruals = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "springfield_bank",
+		Name:      "savings_interest_accruals_total",
+		Help:      "The total number of daily savings interest accruals on the positive balances.",
+	})
+	// SavingsInterestPayments counts the monthly savings interest payments to the accounts.
+	SavingsInterestPayments = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "springfield_bank",
+		Name:      "savings_interest_payments_total",
+		Help:      "The total number of monthly savings interest payments to the accounts.",
+	})
+	// PaymentRequestsExpired counts the payment requests closed as expired by the sweep.
+	PaymentRequestsExpired = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "springfield_bank",
+		Name:      "payment_requests_expired_total",
+		Help:      "The total number of payment requests closed as expired.",
+	})
+)
@@ -14,21 +14,26 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/kinbiko/jsonassert"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
 	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
 )
 
 func Test_accounts_Fetch(t *testing.T) {
 	ja := jsonassert.New(t)
 
+	authSecret := "any-secret"
+
 	type fields struct {
 		dbPool      *pgxpool.Pool
 		redisClient *redis.Client
 		authConf    config.ConfAuth
 	}
 	type args struct {
-		path string
+		path   string
+		header func() map[string][]string
 	}
 	tests := []struct {
 		name       string
@@ -38,40 +43,68 @@ func Test_accounts_Fetch(t *testing.T) {
 		want       string
 		runBefore  func(args)
 	}{
+		{
+			name: "no Authorization header should return error",
+			fields: fields{
+				dbPool:      testDbPool,
+				redisClient: testRedisClient,
+				authConf: config.ConfAuth{
+					SecretKey:      authSecret,
+					AccessTokenDur: 30 * time.Second,
+				},
+			},
+			args: args{
+				path: "/accounts",
+				header: func() map[string][]string {
+					return nil
+				},
+			},
+			wantStatus: 401,
+			want:       `{"code":401,"message":"malformed Token"}`,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
 		{
 			name: "empty database should return empty json",
 			fields: fields{
 				dbPool:      testDbPool,
 				redisClient: testRedisClient,
 				authConf: config.ConfAuth{
-					SecretKey:      "any-secret",
+					SecretKey:      authSecret,
 					AccessTokenDur: 30 * time.Second,
 				},
 			},
 			args: args{
 				path: "/accounts",
+				header: func() map[string][]string {
+					return newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleAdmin)
+				},
 			},
 			wantStatus: 200,
-			want:       "[]",
+			want:       `{"accounts": [], "next_cursor": null}`,
 			runBefore: func(args args) {
 				truncateDatabase(t)
 			},
 		},
 		{
-			name: "one result success",
+			name: "operator one result success",
 			fields: fields{
 				dbPool:      testDbPool,
 				redisClient: testRedisClient,
 				authConf: config.ConfAuth{
-					SecretKey:      "any-secret",
+					SecretKey:      authSecret,
 					AccessTokenDur: 30 * time.Second,
 				},
 			},
 			args: args{
 				path: "/accounts",
+				header: func() map[string][]string {
+					return newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleOperator)
+				},
 			},
 			wantStatus: 200,
-			want:       `[{"id": "<<PRESENCE>>", "name": "Bart Simpson", "cpf": "123.456.789-11", "balance": 5.96, "created_at": "<<PRESENCE>>"}]`,
+			want:       `{"accounts": [{"id": "<<PRESENCE>>", "name": "Bart Simpson", "cpf": "123.456.789-11", "currency": "BRL", "balance": 5.96, "status": "active", "created_at": "<<PRESENCE>>"}], "next_cursor": null}`,
 			runBefore: func(args args) {
 				truncateDatabase(t)
 
@@ -87,23 +120,26 @@ func Test_accounts_Fetch(t *testing.T) {
 			},
 		},
 		{
-			name: "two results sort order success",
+			name: "operator two results sort order success",
 			fields: fields{
 				dbPool:      testDbPool,
 				redisClient: testRedisClient,
 				authConf: config.ConfAuth{
-					SecretKey:      "any-secret",
+					SecretKey:      authSecret,
 					AccessTokenDur: 30 * time.Second,
 				},
 			},
 			args: args{
 				path: "/accounts",
+				header: func() map[string][]string {
+					return newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleOperator)
+				},
 			},
 			wantStatus: 200,
-			want: `[
-					{"id": "<<PRESENCE>>", "name": "Bart Simpson", "cpf": "123.456.789-11", "balance": 5.96, "created_at": "<<PRESENCE>>"},
-					{"id": "<<PRESENCE>>", "name": "Homer Simpson", "cpf": "123.456.789-12", "balance": 1234.5, "created_at": "<<PRESENCE>>"}
-				]`,
+			want: `{"accounts": [
+					{"id": "<<PRESENCE>>", "name": "Bart Simpson", "cpf": "123.456.789-11", "currency": "BRL", "balance": 5.96, "status": "active", "created_at": "<<PRESENCE>>"},
+					{"id": "<<PRESENCE>>", "name": "Homer Simpson", "cpf": "123.456.789-12", "currency": "BRL", "balance": 1234.5, "status": "active", "created_at": "<<PRESENCE>>"}
+				], "next_cursor": null}`,
 			runBefore: func(args args) {
 				truncateDatabase(t)
 
@@ -128,6 +164,251 @@ func Test_accounts_Fetch(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "customer should get the masked public projection",
+			fields: fields{
+				dbPool:      testDbPool,
+				redisClient: testRedisClient,
+				authConf: config.ConfAuth{
+					SecretKey:      authSecret,
+					AccessTokenDur: 30 * time.Second,
+				},
+			},
+			args: args{
+				path: "/accounts",
+				header: func() map[string][]string {
+					return newTestAuthHeader(t, authSecret, uuid.NewString())
+				},
+			},
+			wantStatus: 200,
+			want:       `{"accounts": [{"id": "<<PRESENCE>>", "name": "Bart Simpson", "cpf": "***.456.789-**", "currency": "BRL", "created_at": "<<PRESENCE>>"}], "next_cursor": null}`,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+
+				_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+					uuid.NewString(),
+					"Bart Simpson",
+					"12345678911",
+					"secret",
+					596)
+				if err != nil {
+					t.Errorf("GET %s, error on runBefore = %v", args.path, err)
+				}
+			},
+		},
+		{
+			name: "limit should return the first page and the next cursor",
+			fields: fields{
+				dbPool:      testDbPool,
+				redisClient: testRedisClient,
+				authConf: config.ConfAuth{
+					SecretKey:      authSecret,
+					AccessTokenDur: 30 * time.Second,
+				},
+			},
+			args: args{
+				path: "/accounts?limit=1",
+				header: func() map[string][]string {
+					return newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleOperator)
+				},
+			},
+			wantStatus: 200,
+			want:       `{"accounts": [{"id": "<<PRESENCE>>", "name": "Bart Simpson", "cpf": "123.456.789-11", "currency": "BRL", "balance": 5.96, "status": "active", "created_at": "<<PRESENCE>>"}], "next_cursor": "<<PRESENCE>>"}`,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+
+				_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+					uuid.NewString(),
+					"Bart Simpson",
+					"12345678911",
+					"secret",
+					596)
+				if err != nil {
+					t.Errorf("GET %s, error on runBefore = %v", args.path, err)
+				}
+
+				_, err = testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+					uuid.NewString(),
+					"Homer Simpson",
+					"59951332099",
+					"secret",
+					123450)
+				if err != nil {
+					t.Errorf("GET %s, error on runBefore = %v", args.path, err)
+				}
+			},
+		},
+		{
+			name: "name search and sort success",
+			fields: fields{
+				dbPool:      testDbPool,
+				redisClient: testRedisClient,
+				authConf: config.ConfAuth{
+					SecretKey:      authSecret,
+					AccessTokenDur: 30 * time.Second,
+				},
+			},
+			args: args{
+				path: "/accounts?name=simp&sort=-name",
+				header: func() map[string][]string {
+					return newTestAuthHeader(t, authSecret, uuid.NewString())
+				},
+			},
+			wantStatus: 200,
+			want: `{"accounts": [
+					{"id": "<<PRESENCE>>", "name": "Homer Simpson", "cpf": "***.513.320-**", "currency": "BRL", "created_at": "<<PRESENCE>>"},
+					{"id": "<<PRESENCE>>", "name": "Bart Simpson", "cpf": "***.456.789-**", "currency": "BRL", "created_at": "<<PRESENCE>>"}
+				], "next_cursor": null}`,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+
+				_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+					uuid.NewString(),
+					"Bart Simpson",
+					"12345678911",
+					"secret",
+					596)
+				if err != nil {
+					t.Errorf("GET %s, error on runBefore = %v", args.path, err)
+				}
+
+				_, err = testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+					uuid.NewString(),
+					"Homer Simpson",
+					"59951332099",
+					"secret",
+					123450)
+				if err != nil {
+					t.Errorf("GET %s, error on runBefore = %v", args.path, err)
+				}
+			},
+		},
+		{
+			name: "operator cpf search success",
+			fields: fields{
+				dbPool:      testDbPool,
+				redisClient: testRedisClient,
+				authConf: config.ConfAuth{
+					SecretKey:      authSecret,
+					AccessTokenDur: 30 * time.Second,
+				},
+			},
+			args: args{
+				path: "/accounts?cpf=599.513.320-99",
+				header: func() map[string][]string {
+					return newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleOperator)
+				},
+			},
+			wantStatus: 200,
+			want:       `{"accounts": [{"id": "<<PRESENCE>>", "name": "Homer Simpson", "cpf": "599.513.320-99", "currency": "BRL", "balance": 1234.5, "status": "active", "created_at": "<<PRESENCE>>"}], "next_cursor": null}`,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+
+				_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+					uuid.NewString(),
+					"Bart Simpson",
+					"12345678911",
+					"secret",
+					596)
+				if err != nil {
+					t.Errorf("GET %s, error on runBefore = %v", args.path, err)
+				}
+
+				_, err = testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+					uuid.NewString(),
+					"Homer Simpson",
+					"59951332099",
+					"secret",
+					123450)
+				if err != nil {
+					t.Errorf("GET %s, error on runBefore = %v", args.path, err)
+				}
+			},
+		},
+		{
+			name: "customer cpf search should return forbidden",
+			fields: fields{
+				dbPool:      testDbPool,
+				redisClient: testRedisClient,
+				authConf: config.ConfAuth{
+					SecretKey:      authSecret,
+					AccessTokenDur: 30 * time.Second,
+				},
+			},
+			args: args{
+				path: "/accounts?cpf=599.513.320-99",
+				header: func() map[string][]string {
+					return newTestAuthHeader(t, authSecret, uuid.NewString())
+				},
+			},
+			wantStatus: 403,
+			want:       `{"code":403,"message":"forbidden"}`,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+
+				_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+					uuid.NewString(),
+					"Bart Simpson",
+					"12345678911",
+					"secret",
+					596)
+				if err != nil {
+					t.Errorf("GET %s, error on runBefore = %v", args.path, err)
+				}
+
+				_, err = testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+					uuid.NewString(),
+					"Homer Simpson",
+					"59951332099",
+					"secret",
+					123450)
+				if err != nil {
+					t.Errorf("GET %s, error on runBefore = %v", args.path, err)
+				}
+			},
+		},
+		{
+			name: "invalid sort should return bad request",
+			fields: fields{
+				dbPool:      testDbPool,
+				redisClient: testRedisClient,
+				authConf: config.ConfAuth{
+					SecretKey:      authSecret,
+					AccessTokenDur: 30 * time.Second,
+				},
+			},
+			args: args{
+				path: "/accounts?sort=balance",
+				header: func() map[string][]string {
+					return newTestAuthHeader(t, authSecret, uuid.NewString())
+				},
+			},
+			wantStatus: 400,
+			want:       `{"code":400,"message":"'sort' must be 'created_at', '-created_at', 'name' or '-name'"}`,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+
+				_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+					uuid.NewString(),
+					"Bart Simpson",
+					"12345678911",
+					"secret",
+					596)
+				if err != nil {
+					t.Errorf("GET %s, error on runBefore = %v", args.path, err)
+				}
+
+				_, err = testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+					uuid.NewString(),
+					"Homer Simpson",
+					"59951332099",
+					"secret",
+					123450)
+				if err != nil {
+					t.Errorf("GET %s, error on runBefore = %v", args.path, err)
+				}
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -135,10 +416,16 @@ func Test_accounts_Fetch(t *testing.T) {
 				tt.runBefore(tt.args)
 			}
 
-			ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf))
+			ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf, httpGateway.HandlerOptions{}))
 			defer ts.Close()
 
-			res, err := http.Get(ts.URL + tt.args.path)
+			req, err := http.NewRequest(http.MethodGet, ts.URL+tt.args.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header = tt.args.header()
+
+			res, err := http.DefaultClient.Do(req)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -195,7 +482,7 @@ func Test_accounts_Create(t *testing.T) {
 				body: `{"name": " Bart Simpson ", "cpf": " 343.639.16206 ", "secret": "s3cr3t", "balance": 5678.96}`,
 			},
 			wantStatus: 201,
-			want:       `{"id": "<<PRESENCE>>", "name": "Bart Simpson", "cpf": "343.639.162-06", "balance": 5678.96, "created_at": "<<PRESENCE>>"}`,
+			want:       `{"id": "<<PRESENCE>>", "name": "Bart Simpson", "cpf": "343.639.162-06", "currency": "BRL", "balance": 5678.96, "created_at": "<<PRESENCE>>"}`,
 			runBefore: func(args args) {
 				truncateDatabase(t)
 			},
@@ -339,7 +626,7 @@ func Test_accounts_Create(t *testing.T) {
 				tt.runBefore(tt.args)
 			}
 
-			ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf))
+			ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf, httpGateway.HandlerOptions{}))
 			defer ts.Close()
 
 			res, err := http.Post(ts.URL+tt.args.path, jsonContentType, strings.NewReader(tt.args.body))
@@ -403,7 +690,7 @@ func Test_accounts_Create_Idempotent(t *testing.T) {
 				body: `{"name": "Bart Simpson", "cpf": "343.639.162-06", "secret": "s3cr3t", "balance": 5678.96}`,
 			},
 			wantStatus: 201,
-			want:       `{"id": "<<PRESENCE>>", "name": "Bart Simpson", "cpf": "343.639.162-06", "balance": 5678.96, "created_at": "<<PRESENCE>>"}`,
+			want:       `{"id": "<<PRESENCE>>", "name": "Bart Simpson", "cpf": "343.639.162-06", "currency": "BRL", "balance": 5678.96, "created_at": "<<PRESENCE>>"}`,
 			runBefore: func(args args) {
 				truncateDatabase(t)
 			},
@@ -416,7 +703,7 @@ func Test_accounts_Create_Idempotent(t *testing.T) {
 			}
 
 			testReq := func(check func(*http.Response)) {
-				ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf))
+				ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf, httpGateway.HandlerOptions{}))
 				defer ts.Close()
 
 				req, err := http.NewRequest(http.MethodPost, ts.URL+tt.args.path, strings.NewReader(tt.args.body))
@@ -467,13 +754,16 @@ func Test_accounts_Create_Idempotent(t *testing.T) {
 func Test_accounts_GetBalance(t *testing.T) {
 	ja := jsonassert.New(t)
 
+	authSecret := "any-secret"
+
 	type fields struct {
 		dbPool      *pgxpool.Pool
 		redisClient *redis.Client
 		authConf    config.ConfAuth
 	}
 	type args struct {
-		path func() string
+		// request returns the path and the headers
+		request func() (string, map[string][]string)
 	}
 	tests := []struct {
 		name       string
@@ -483,19 +773,41 @@ func Test_accounts_GetBalance(t *testing.T) {
 		want       string
 		runBefore  func(args)
 	}{
+		{
+			name: "no Authorization header should return error",
+			fields: fields{
+				dbPool:      testDbPool,
+				redisClient: testRedisClient,
+				authConf: config.ConfAuth{
+					SecretKey:      authSecret,
+					AccessTokenDur: 30 * time.Second,
+				},
+			},
+			args: args{
+				request: func() (string, map[string][]string) {
+					return fmt.Sprintf("/accounts/%s/balance", uuid.NewString()), nil
+				},
+			},
+			wantStatus: 401,
+			want:       `{"code":401,"message":"malformed Token"}`,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
 		{
 			name: "empty database should return error",
 			fields: fields{
 				dbPool:      testDbPool,
 				redisClient: testRedisClient,
 				authConf: config.ConfAuth{
-					SecretKey:      "any-secret",
+					SecretKey:      authSecret,
 					AccessTokenDur: 30 * time.Second,
 				},
 			},
 			args: args{
-				path: func() string {
-					return fmt.Sprintf("/accounts/%s/balance", uuid.NewString())
+				request: func() (string, map[string][]string) {
+					id := uuid.NewString()
+					return fmt.Sprintf("/accounts/%s/balance", id), newTestAuthHeader(t, authSecret, id)
 				},
 			},
 			wantStatus: 404,
@@ -504,18 +816,52 @@ func Test_accounts_GetBalance(t *testing.T) {
 				truncateDatabase(t)
 			},
 		},
+		{
+			name: "other account should return error",
+			fields: fields{
+				dbPool:      testDbPool,
+				redisClient: testRedisClient,
+				authConf: config.ConfAuth{
+					SecretKey:      authSecret,
+					AccessTokenDur: 30 * time.Second,
+				},
+			},
+			args: args{
+				request: func() (string, map[string][]string) {
+					id := uuid.NewString()
+					path := fmt.Sprintf("/accounts/%s/balance", id)
+
+					_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+						id,
+						"Bart Simpson",
+						"34363916206",
+						"secret",
+						596)
+					if err != nil {
+						t.Errorf("GET %s, error on runBefore = %v", path, err)
+					}
+
+					return path, newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleAdmin)
+				},
+			},
+			wantStatus: 403,
+			want:       `{"code": 403, "message": "forbidden"}`,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
 		{
 			name: "success",
 			fields: fields{
 				dbPool:      testDbPool,
 				redisClient: testRedisClient,
 				authConf: config.ConfAuth{
-					SecretKey:      "any-secret",
+					SecretKey:      authSecret,
 					AccessTokenDur: 30 * time.Second,
 				},
 			},
 			args: args{
-				path: func() string {
+				request: func() (string, map[string][]string) {
 					id := uuid.NewString()
 					path := fmt.Sprintf("/accounts/%s/balance", id)
 
@@ -526,14 +872,14 @@ func Test_accounts_GetBalance(t *testing.T) {
 						"secret",
 						596)
 					if err != nil {
-						t.Errorf("POST %s, error on runBefore = %v", path, err)
+						t.Errorf("GET %s, error on runBefore = %v", path, err)
 					}
 
-					return path
+					return path, newTestAuthHeader(t, authSecret, id)
 				},
 			},
 			wantStatus: 200,
-			want:       `{"id": "<<PRESENCE>>", "balance": 5.96}`,
+			want:       `{"id": "<<PRESENCE>>", "currency": "BRL", "balance": 5.96, "credit_limit": 0, "available_balance": 5.96}`,
 			runBefore: func(args args) {
 				truncateDatabase(t)
 			},
@@ -545,11 +891,17 @@ func Test_accounts_GetBalance(t *testing.T) {
 				tt.runBefore(tt.args)
 			}
 
-			ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf))
+			ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf, httpGateway.HandlerOptions{}))
 			defer ts.Close()
 
-			path := tt.args.path()
-			res, err := http.Get(ts.URL + path)
+			path, header := tt.args.request()
+			req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header = header
+
+			res, err := http.DefaultClient.Do(req)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -570,3 +922,286 @@ func Test_accounts_GetBalance(t *testing.T) {
 		})
 	}
 }
+
+func Test_accounts_Lifecycle(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	hashedSecret, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatalf("error on setup = %v", err)
+	}
+
+	holderID := uuid.NewString()
+	sweepID := uuid.NewString()
+	for i, id := range []string{holderID, sweepID} {
+		_, err = testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), string(hashedSecret), 1000*(1-i))
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{}))
+	defer ts.Close()
+
+	adminHeader := newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleAdmin)
+
+	// the steps run in order, each one depends on the previous ones
+	steps := []struct {
+		name       string
+		method     string
+		path       string
+		header     map[string][]string
+		body       string
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "operator should not block",
+			method:     http.MethodPost,
+			path:       "/accounts/" + holderID + "/block",
+			header:     newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleOperator),
+			body:       `{"reason":"suspected fraud"}`,
+			wantStatus: 403,
+			want:       `{"code":403,"message":"forbidden"}`,
+		},
+		{
+			name:       "admin should block",
+			method:     http.MethodPost,
+			path:       "/accounts/" + holderID + "/block",
+			header:     adminHeader,
+			body:       `{"reason":"suspected fraud"}`,
+			wantStatus: 200,
+			want:       fmt.Sprintf(`{"id":%q, "status":"blocked", "status_reason":"suspected fraud", "status_changed_at":"<<PRESENCE>>"}`, holderID),
+		},
+		{
+			name:       "blocked account should not transfer",
+			method:     http.MethodPost,
+			path:       "/transfers",
+			header:     newTestAuthHeader(t, authSecret, holderID),
+			body:       fmt.Sprintf(`{"account_destination_id":%q, "amount": 1}`, sweepID),
+			wantStatus: 422,
+			want:       `{"code":422,"message":"origin account is not active"}`,
+		},
+		{
+			name:       "blocked account should not receive transfers",
+			method:     http.MethodPost,
+			path:       "/transfers",
+			header:     newTestAuthHeader(t, authSecret, sweepID),
+			body:       fmt.Sprintf(`{"account_destination_id":%q, "amount": 1}`, holderID),
+			wantStatus: 422,
+			want:       `{"code":422,"message":"destination account is not active"}`,
+		},
+		{
+			name:       "blocked account should not be blocked again",
+			method:     http.MethodPost,
+			path:       "/accounts/" + holderID + "/block",
+			header:     adminHeader,
+			body:       `{"reason":"suspected fraud"}`,
+			wantStatus: 409,
+			want:       `{"code":409,"message":"account status can't be changed"}`,
+		},
+		{
+			name:       "positive balance without sweep account should not close",
+			method:     http.MethodPost,
+			path:       "/accounts/" + holderID + "/close",
+			header:     adminHeader,
+			body:       `{"reason":"fraud confirmed"}`,
+			wantStatus: 422,
+			want:       `{"code":422,"message":"account balance must be zero or 'sweep_account_id' must be informed"}`,
+		},
+		{
+			name:       "admin should close sweeping the balance",
+			method:     http.MethodPost,
+			path:       "/accounts/" + holderID + "/close",
+			header:     adminHeader,
+			body:       fmt.Sprintf(`{"reason":"fraud confirmed", "sweep_account_id":%q}`, sweepID),
+			wantStatus: 200,
+			want:       fmt.Sprintf(`{"id":%q, "status":"closed", "status_reason":"fraud confirmed", "status_changed_at":"<<PRESENCE>>", "swept_amount":10}`, holderID),
+		},
+		{
+			name:       "sweep account should have the swept balance",
+			method:     http.MethodGet,
+			path:       "/accounts/" + sweepID + "/balance",
+			header:     newTestAuthHeader(t, authSecret, sweepID),
+			wantStatus: 200,
+			want:       fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":10, "credit_limit":0, "available_balance":10}`, sweepID),
+		},
+		{
+			name:       "closed account should not receive transfers",
+			method:     http.MethodPost,
+			path:       "/transfers",
+			header:     newTestAuthHeader(t, authSecret, sweepID),
+			body:       fmt.Sprintf(`{"account_destination_id":%q, "amount": 1}`, holderID),
+			wantStatus: 422,
+			want:       `{"code":422,"message":"destination account is not active"}`,
+		},
+		{
+			name:       "closed account should not be unblocked",
+			method:     http.MethodPost,
+			path:       "/accounts/" + holderID + "/unblock",
+			header:     adminHeader,
+			body:       `{"reason":"fraud cleared"}`,
+			wantStatus: 409,
+			want:       `{"code":409,"message":"account status can't be changed"}`,
+		},
+		{
+			name:       "closed account should not log in",
+			method:     http.MethodPost,
+			path:       "/login",
+			body:       `{"cpf":"00000000000", "secret":"secret"}`,
+			wantStatus: 403,
+			want:       `{"code":403,"message":"account is closed"}`,
+		},
+	}
+	for _, step := range steps {
+		req, err := http.NewRequest(step.method, ts.URL+step.path, strings.NewReader(step.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = step.header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != step.wantStatus {
+			t.Fatalf("%s: %s %s, statusCode = %v, wantStatus %v, body %s", step.name, step.method, step.path, res.StatusCode, step.wantStatus, body)
+		}
+		ja.Assertf(string(body), step.want)
+	}
+}
+
+func Test_accounts_Statement(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	holderID := uuid.NewString()
+	otherID := uuid.NewString()
+	for i, id := range []string{holderID, otherID} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", 0)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{}))
+	defer ts.Close()
+
+	holderHeader := newTestAuthHeader(t, authSecret, holderID)
+	operatorHeader := newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleOperator)
+
+	// the steps run in order, each one depends on the previous ones
+	steps := []struct {
+		name       string
+		method     string
+		path       string
+		header     map[string][]string
+		body       string
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "operator should deposit",
+			method:     http.MethodPost,
+			path:       "/accounts/" + holderID + "/deposits",
+			header:     operatorHeader,
+			body:       `{"amount": 100}`,
+			wantStatus: 201,
+			want:       "",
+		},
+		{
+			name:       "holder should transfer",
+			method:     http.MethodPost,
+			path:       "/transfers",
+			header:     holderHeader,
+			body:       fmt.Sprintf(`{"account_destination_id":%q, "amount": 30}`, otherID),
+			wantStatus: 201,
+			want:       "",
+		},
+		{
+			name:       "holder should get the statement with the running balance",
+			method:     http.MethodGet,
+			path:       "/accounts/" + holderID + "/statement",
+			header:     holderHeader,
+			wantStatus: 200,
+			want: fmt.Sprintf(`{
+				"account_id":%q,
+				"from":"<<PRESENCE>>",
+				"to":"<<PRESENCE>>",
+				"opening_balance":0,
+				"closing_balance":70,
+				"entries":[
+					{"id":"<<PRESENCE>>", "direction":"credit", "kind":"deposit", "reference_id":"<<PRESENCE>>", "amount":100, "balance_after":100, "created_at":"<<PRESENCE>>"},
+					{"id":"<<PRESENCE>>", "direction":"debit", "kind":"transfer", "reference_id":"<<PRESENCE>>", "amount":30, "balance_after":70,
+						"counterpart":{"id":%q, "name":"Simpson 1"}, "created_at":"<<PRESENCE>>"}
+				]
+			}`, holderID, otherID),
+		},
+		{
+			name:       "past period should have no entries",
+			method:     http.MethodGet,
+			path:       "/accounts/" + holderID + "/statement?from=2020-01-01&to=2020-12-31",
+			header:     holderHeader,
+			wantStatus: 200,
+			want: fmt.Sprintf(`{"account_id":%q, "from":"2020-01-01T00:00:00Z", "to":"2021-01-01T00:00:00Z",
+				"opening_balance":0, "closing_balance":0, "entries":[]}`, holderID),
+		},
+		{
+			name:       "other account should not get the statement",
+			method:     http.MethodGet,
+			path:       "/accounts/" + holderID + "/statement",
+			header:     newTestAuthHeader(t, authSecret, otherID),
+			wantStatus: 403,
+			want:       `{"code":403,"message":"forbidden"}`,
+		},
+	}
+	for _, step := range steps {
+		req, err := http.NewRequest(step.method, ts.URL+step.path, strings.NewReader(step.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = step.header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != step.wantStatus {
+			t.Fatalf("%s: %s %s, statusCode = %v, wantStatus %v, body %s", step.name, step.method, step.path, res.StatusCode, step.wantStatus, body)
+		}
+		if step.want != "" {
+			ja.Assertf(string(body), step.want)
+		}
+	}
+}
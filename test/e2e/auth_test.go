@@ -2,6 +2,7 @@ package e2e
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -48,8 +49,9 @@ func Test_Login(t *testing.T) {
 				dbPool:      testDbPool,
 				redisClient: testRedisClient,
 				authConf: config.ConfAuth{
-					SecretKey:      authSecret,
-					AccessTokenDur: 30 * time.Second,
+					SecretKey:       authSecret,
+					AccessTokenDur:  30 * time.Second,
+					RefreshTokenDur: time.Minute,
 				},
 			},
 			args: args{
@@ -77,7 +79,7 @@ func Test_Login(t *testing.T) {
 				},
 			},
 			wantStatus: 200,
-			want:       `{"access_token": "<<PRESENCE>>"}`,
+			want:       `{"access_token": "<<PRESENCE>>", "refresh_token": "<<PRESENCE>>"}`,
 			runBefore: func(args args) {
 				truncateDatabase(t)
 			},
@@ -202,7 +204,7 @@ func Test_Login(t *testing.T) {
 				tt.runBefore(tt.args)
 			}
 
-			ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf))
+			ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf, httpGateway.HandlerOptions{}))
 			defer ts.Close()
 
 			reqHeader, reqBody := tt.args.headerAndBody()
@@ -237,3 +239,82 @@ func Test_Login(t *testing.T) {
 		})
 	}
 }
+
+func Test_RefreshToken_Logout(t *testing.T) {
+	truncateDatabase(t)
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, config.ConfAuth{
+		SecretKey:       "any-secret",
+		AccessTokenDur:  30 * time.Second,
+		RefreshTokenDur: time.Minute,
+	}, httpGateway.HandlerOptions{}))
+	defer ts.Close()
+
+	cpf := "34363916206"
+	secret := "super#s3cr3t!"
+	hashedSecret, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+		uuid.NewString(),
+		"Bart Simpson",
+		cpf,
+		string(hashedSecret),
+		10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	post := func(path string, accessToken string, body string) (int, map[string]string) {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Add(contentType, jsonContentType)
+		if accessToken != "" {
+			req.Header.Add("Authorization", "Bearer "+accessToken)
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		result := map[string]string{}
+		_ = json.NewDecoder(res.Body).Decode(&result)
+
+		return res.StatusCode, result
+	}
+
+	status, login := post("/login", "", fmt.Sprintf(`{"cpf":"%s", "secret": "%s"}`, cpf, secret))
+	if status != http.StatusOK {
+		t.Fatalf("POST /login statusCode = %v, wantStatus %v", status, http.StatusOK)
+	}
+
+	status, refreshed := post("/token/refresh", "", fmt.Sprintf(`{"refresh_token":"%s"}`, login["refresh_token"]))
+	if status != http.StatusOK || refreshed["refresh_token"] == "" || refreshed["refresh_token"] == login["refresh_token"] {
+		t.Fatalf("POST /token/refresh statusCode = %v, got = %v, want a rotated refresh token", status, refreshed)
+	}
+
+	// reusing the first refresh token revokes the whole family, including the rotated one
+	status, _ = post("/token/refresh", "", fmt.Sprintf(`{"refresh_token":"%s"}`, login["refresh_token"]))
+	if status != http.StatusUnauthorized {
+		t.Errorf("POST /token/refresh reused statusCode = %v, wantStatus %v", status, http.StatusUnauthorized)
+	}
+	status, _ = post("/token/refresh", "", fmt.Sprintf(`{"refresh_token":"%s"}`, refreshed["refresh_token"]))
+	if status != http.StatusUnauthorized {
+		t.Errorf("POST /token/refresh revoked family statusCode = %v, wantStatus %v", status, http.StatusUnauthorized)
+	}
+
+	status, _ = post("/logout", refreshed["access_token"], "")
+	if status != http.StatusNoContent {
+		t.Errorf("POST /logout statusCode = %v, wantStatus %v", status, http.StatusNoContent)
+	}
+
+	status, _ = post("/logout", refreshed["access_token"], "")
+	if status != http.StatusUnauthorized {
+		t.Errorf("POST /logout with denied access token statusCode = %v, wantStatus %v", status, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,135 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+)
+
+func Test_cash_DepositAndWithdraw(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	holderID := uuid.NewString()
+	operatorID := uuid.NewString()
+	for i, id := range []string{holderID, operatorID} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", 0)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{}))
+	defer ts.Close()
+
+	holderHeader := newTestAuthHeader(t, authSecret, holderID)
+	operatorHeader := newTestAuthHeader(t, authSecret, operatorID, model.RoleOperator)
+
+	// the steps run in order, each one depends on the previous ones
+	steps := []struct {
+		name       string
+		method     string
+		path       string
+		header     map[string][]string
+		body       string
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "customer should not deposit",
+			method:     http.MethodPost,
+			path:       "/accounts/" + holderID + "/deposits",
+			header:     holderHeader,
+			body:       `{"amount": 100}`,
+			wantStatus: 403,
+			want:       `{"code":403,"message":"forbidden"}`,
+		},
+		{
+			name:       "operator should deposit",
+			method:     http.MethodPost,
+			path:       "/accounts/" + holderID + "/deposits",
+			header:     operatorHeader,
+			body:       `{"amount": 100}`,
+			wantStatus: 201,
+			want:       fmt.Sprintf(`{"id":"<<PRESENCE>>", "account_id":%q, "operator_id":%q, "amount":100, "created_at":"<<PRESENCE>>"}`, holderID, operatorID),
+		},
+		{
+			name:       "deposit to unknown account should fail",
+			method:     http.MethodPost,
+			path:       "/accounts/" + uuid.NewString() + "/deposits",
+			header:     operatorHeader,
+			body:       `{"amount": 100}`,
+			wantStatus: 422,
+			want:       `{"code":422,"message":"account not found"}`,
+		},
+		{
+			name:       "holder should withdraw",
+			method:     http.MethodPost,
+			path:       "/withdrawals",
+			header:     holderHeader,
+			body:       `{"amount": 40}`,
+			wantStatus: 201,
+			want:       fmt.Sprintf(`{"id":"<<PRESENCE>>", "account_id":%q, "amount":40, "created_at":"<<PRESENCE>>"}`, holderID),
+		},
+		{
+			name:       "holder should not withdraw more than the balance",
+			method:     http.MethodPost,
+			path:       "/withdrawals",
+			header:     holderHeader,
+			body:       `{"amount": 60.01}`,
+			wantStatus: 422,
+			want:       `{"code":422,"message":"current account balance is insufficient"}`,
+		},
+		{
+			name:       "balance should reflect the deposit and the withdrawal",
+			method:     http.MethodGet,
+			path:       "/accounts/" + holderID + "/balance",
+			header:     holderHeader,
+			wantStatus: 200,
+			want:       fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":60, "credit_limit":0, "available_balance":60}`, holderID),
+		},
+	}
+	for _, step := range steps {
+		req, err := http.NewRequest(step.method, ts.URL+step.path, strings.NewReader(step.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = step.header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != step.wantStatus {
+			t.Fatalf("%s: %s %s, statusCode = %v, wantStatus %v, body %s", step.name, step.method, step.path, res.StatusCode, step.wantStatus, body)
+		}
+		ja.Assertf(string(body), step.want)
+	}
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-redis/redis"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/ory/dockertest/v3"
 	"github.com/rs/zerolog/log"
 
 	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
 	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
 	redisGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/redis"
 )
@@ -121,12 +125,106 @@ func getRedisConn(dockerPool *dockertest.Pool) *dockertest.Resource {
 func truncateDatabase(t *testing.T) {
 	backgroundCtx := context.Background()
 
-	_, err := testDbPool.Exec(backgroundCtx, "DELETE FROM transfers")
+	// ledger entries are immutable, so they can not be deleted, only truncated
+	_, err := testDbPool.Exec(backgroundCtx, "TRUNCATE ledger_entries")
+	if err != nil {
+		t.Errorf("Error truncating ledger_entries table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM notifications")
+	if err != nil {
+		t.Errorf("Error truncating notifications table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM standing_orders")
+	if err != nil {
+		t.Errorf("Error truncating standing_orders table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM scheduled_transfers")
+	if err != nil {
+		t.Errorf("Error truncating scheduled_transfers table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM payment_requests")
+	if err != nil {
+		t.Errorf("Error truncating payment_requests table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM transfer_batch_items")
+	if err != nil {
+		t.Errorf("Error truncating transfer_batch_items table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM transfer_batches")
+	if err != nil {
+		t.Errorf("Error truncating transfer_batches table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM transfers")
 	if err != nil {
 		t.Errorf("Error truncating transfers table: %v", err)
 	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM fx_quotes")
+	if err != nil {
+		t.Errorf("Error truncating fx_quotes table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM deposits")
+	if err != nil {
+		t.Errorf("Error truncating deposits table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM withdrawals")
+	if err != nil {
+		t.Errorf("Error truncating withdrawals table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM transfer_limits")
+	if err != nil {
+		t.Errorf("Error truncating transfer_limits table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM overdraft_interest_charges")
+	if err != nil {
+		t.Errorf("Error truncating overdraft_interest_charges table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM maintenance_fee_charges")
+	if err != nil {
+		t.Errorf("Error truncating maintenance_fee_charges table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM savings_interest_payments")
+	if err != nil {
+		t.Errorf("Error truncating savings_interest_payments table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM savings_interest_accruals")
+	if err != nil {
+		t.Errorf("Error truncating savings_interest_accruals table: %v", err)
+	}
+	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM pix_keys")
+	if err != nil {
+		t.Errorf("Error truncating pix_keys table: %v", err)
+	}
 	_, err = testDbPool.Exec(backgroundCtx, "DELETE FROM accounts")
 	if err != nil {
 		t.Errorf("Error truncating accounts table: %v", err)
 	}
 }
+
+// newTestAuthHeader returns the Authorization header with an HS256 access token for the subject,
+// granting the scopes of the roles.
+func newTestAuthHeader(t *testing.T, secretKey string, subject string, roles ...model.Role) map[string][]string {
+	var scopes []string
+	for _, scope := range model.ScopesOf(roles) {
+		scopes = append(scopes, string(scope))
+	}
+
+	now := time.Now()
+	accessTokenClaims := usecase.AuthClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(30 * time.Second)),
+		},
+		Roles: roles,
+		Scope: strings.Join(scopes, " "),
+	}
+
+	accessTokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims).SignedString([]byte(secretKey))
+	if err != nil {
+		t.Fatalf("error signing test access token: %v", err)
+	}
+
+	return map[string][]string{
+		"Authorization": {"Bearer " + accessTokenString},
+	}
+}
@@ -0,0 +1,141 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+)
+
+func Test_transfers_Fees(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	// the accounts were created before the last month ended, so they owe its maintenance fee
+	revenueID := uuid.NewString()
+	originID := uuid.NewString()
+	destinationID := uuid.NewString()
+	for i, account := range []struct {
+		id      string
+		balance model.Money
+	}{{revenueID, 0}, {originID, 100000}, {destinationID, 0}} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+			account.id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", account.balance, time.Now().AddDate(0, -2, 0))
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	feePolicy := usecase.FeePolicy{
+		RevenueAccountID: model.AccountID(revenueID),
+		Transfer: model.TransferFeeRule{
+			Flat:         100,
+			Rate:         100,
+			FreePerMonth: 1,
+			ExemptTiers:  []model.AccountTier{model.AccountTierPremium},
+		},
+		Maintenance: model.MaintenanceFeeRule{Monthly: 990},
+		Location:    time.UTC,
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{Fees: feePolicy}))
+	defer ts.Close()
+
+	originHeader := newTestAuthHeader(t, authSecret, originID)
+	adminHeader := newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleAdmin)
+
+	doRequest := func(method string, path string, header map[string][]string, body string, wantStatus int) string {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resBody, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != wantStatus {
+			t.Fatalf("%s %s, statusCode = %v, wantStatus %v, body %s", method, path, res.StatusCode, wantStatus, resBody)
+		}
+
+		return string(resBody)
+	}
+	transferBody := fmt.Sprintf(`{"account_destination_id":%q, "amount":100}`, destinationID)
+	// only the owner can read the balance of an account
+	balance := func(id string) string {
+		return doRequest(http.MethodGet, "/accounts/"+id+"/balance", newTestAuthHeader(t, authSecret, id), "", http.StatusOK)
+	}
+
+	// the first transfer of the month is free
+	body := doRequest(http.MethodPost, "/transfer-fee-quotes", originHeader, `{"amount":100}`, http.StatusOK)
+	ja.Assertf(body, `{"currency":"BRL", "amount":100, "fee":0, "total":100, "free_transfers_left":1}`)
+
+	body = doRequest(http.MethodPost, "/transfers", originHeader, transferBody, http.StatusCreated)
+	ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "kind":"transfer", "account_origin_id":%q, "account_destination_id":%q,
+		"currency":"BRL", "amount":100, "refunded_amount":0, "created_at":"<<PRESENCE>>"}`, originID, destinationID))
+
+	// then the flat part plus 1% of the amount
+	body = doRequest(http.MethodPost, "/transfer-fee-quotes", originHeader, `{"amount":100}`, http.StatusOK)
+	ja.Assertf(body, `{"currency":"BRL", "amount":100, "fee":2, "total":102, "free_transfers_left":0}`)
+
+	body = doRequest(http.MethodPost, "/transfers", originHeader, transferBody, http.StatusCreated)
+	ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "kind":"transfer", "account_origin_id":%q, "account_destination_id":%q,
+		"currency":"BRL", "amount":100, "refunded_amount":0, "fee":2, "created_at":"<<PRESENCE>>"}`, originID, destinationID))
+
+	ja.Assertf(balance(originID), fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":798, "credit_limit":0, "available_balance":798}`, originID))
+	ja.Assertf(balance(revenueID), fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":2, "credit_limit":0, "available_balance":2}`, revenueID))
+
+	// the premium accounts are exempt
+	doRequest(http.MethodPut, "/accounts/"+originID+"/tier", originHeader, `{"tier":"premium"}`, http.StatusForbidden)
+	body = doRequest(http.MethodPut, "/accounts/"+originID+"/tier", adminHeader, `{"tier":"premium"}`, http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "tier":"premium"}`, originID))
+
+	body = doRequest(http.MethodPost, "/transfers", originHeader, transferBody, http.StatusCreated)
+	ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "kind":"transfer", "account_origin_id":%q, "account_destination_id":%q,
+		"currency":"BRL", "amount":100, "refunded_amount":0, "created_at":"<<PRESENCE>>"}`, originID, destinationID))
+
+	// charges the maintenance fee of the month ended, only once, to all the accounts but the revenue one
+	feeUC := usecase.NewMaintenanceFeeUseCase(
+		postgres.NewFeeRepository(testDbPool),
+		postgres.NewAccountRepository(testDbPool),
+		postgres.NewLedgerRepository(testDbPool),
+		feePolicy)
+	for _, want := range []int{2, 0} {
+		processed, err := feeUC.ExecuteDue(context.Background(), 10)
+		if err != nil || processed != want {
+			t.Fatalf("ExecuteDue() processed = %v, error = %v, want %v processed", processed, err, want)
+		}
+	}
+
+	ja.Assertf(balance(originID), fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":688.1, "credit_limit":0, "available_balance":688.1}`, originID))
+	ja.Assertf(balance(destinationID), fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":290.1, "credit_limit":0, "available_balance":290.1}`, destinationID))
+	ja.Assertf(balance(revenueID), fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":21.8, "credit_limit":0, "available_balance":21.8}`, revenueID))
+}
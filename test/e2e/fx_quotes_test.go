@@ -0,0 +1,130 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+)
+
+func Test_fxQuotes_CrossCurrencyTransfer(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+	fxConf := config.ConfFX{
+		Rates:    map[string]string{"USD/BRL": "5.00"},
+		QuoteTTL: time.Minute,
+	}
+
+	truncateDatabase(t)
+
+	originID := uuid.NewString()
+	destinationID := uuid.NewString()
+	for i, account := range []struct {
+		id       string
+		currency string
+		balance  int
+	}{{originID, "BRL", 10000}, {destinationID, "USD", 0}} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, currency, balance) VALUES ($1, $2, $3, $4, $5, $6)",
+			account.id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", account.currency, account.balance)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{FX: fxConf}))
+	defer ts.Close()
+
+	originHeader := newTestAuthHeader(t, authSecret, originID)
+	destinationHeader := newTestAuthHeader(t, authSecret, destinationID)
+
+	doRequest := func(method string, path string, header map[string][]string, body string, wantStatus int) string {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resBody, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != wantStatus {
+			t.Fatalf("%s %s, statusCode = %v, wantStatus %v, body %s", method, path, res.StatusCode, wantStatus, resBody)
+		}
+
+		return string(resBody)
+	}
+
+	body := doRequest(http.MethodPost, "/transfers", originHeader, fmt.Sprintf(`{"account_destination_id":%q, "amount":"50"}`, destinationID),
+		http.StatusUnprocessableEntity)
+	ja.Assertf(body, fmt.Sprintf(`{"code":422,"message":%q}`, usecase.ErrTransferCurrencyMismatch))
+
+	body = doRequest(http.MethodPost, "/fx-quotes", originHeader, `{"target_currency":"EUR", "amount":"50"}`, http.StatusUnprocessableEntity)
+	ja.Assertf(body, fmt.Sprintf(`{"code":422,"message":%q}`, repository.ErrFXRateNotFound))
+
+	body = doRequest(http.MethodPost, "/fx-quotes", originHeader, `{"target_currency":"usd", "amount":"50"}`, http.StatusCreated)
+	ja.Assertf(body, `{"id":"<<PRESENCE>>", "source_currency":"BRL", "target_currency":"USD", "rate":"0.2", "source_amount":50,
+		"target_amount":10, "created_at":"<<PRESENCE>>", "expires_at":"<<PRESENCE>>"}`)
+
+	var quote struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(body), &quote); err != nil {
+		t.Fatal(err)
+	}
+	transferBy := func(amount string) string {
+		return fmt.Sprintf(`{"account_destination_id":%q, "amount":%q, "quote_id":%q}`, destinationID, amount, quote.ID)
+	}
+
+	body = doRequest(http.MethodPost, "/transfers", originHeader, transferBy("40"), http.StatusUnprocessableEntity)
+	ja.Assertf(body, fmt.Sprintf(`{"code":422,"message":%q}`, usecase.ErrTransferFXQuoteAmountMismatch))
+
+	body = doRequest(http.MethodPost, "/transfers", originHeader, transferBy("50"), http.StatusCreated)
+	ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "kind":"transfer", "account_origin_id":%q, "account_destination_id":%q, "currency":"BRL",
+		"amount":50, "refunded_amount":0, "conversion":{"quote_id":%q, "rate":"0.2", "destination_currency":"USD", "destination_amount":10},
+		"created_at":"<<PRESENCE>>"}`, originID, destinationID, quote.ID))
+
+	var transfer struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(body), &transfer); err != nil {
+		t.Fatal(err)
+	}
+
+	body = doRequest(http.MethodPost, "/transfers", originHeader, transferBy("50"), http.StatusUnprocessableEntity)
+	ja.Assertf(body, fmt.Sprintf(`{"code":422,"message":%q}`, repository.ErrFXQuoteUsed))
+
+	body = doRequest(http.MethodPost, "/transfers/"+transfer.ID+"/refund", destinationHeader, "", http.StatusUnprocessableEntity)
+	ja.Assertf(body, fmt.Sprintf(`{"code":422,"message":%q}`, usecase.ErrTransferConversionNotRefundable))
+
+	body = doRequest(http.MethodGet, "/accounts/"+originID+"/balance", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":50, "credit_limit":0, "available_balance":50}`, originID))
+
+	body = doRequest(http.MethodGet, "/accounts/"+destinationID+"/balance", destinationHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"USD", "balance":10, "credit_limit":0, "available_balance":10}`, destinationID))
+}
@@ -0,0 +1,150 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+)
+
+func Test_accounts_Overdraft(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	// the interest is charged on the balance rebuilt from the ledger, so the initial balances are posted to it
+	ledgerRepo := postgres.NewLedgerRepository(testDbPool)
+	originID := uuid.NewString()
+	destinationID := uuid.NewString()
+	for i, id := range []string{originID, destinationID} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", 0)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+
+		posting := model.NewLedgerPosting(model.LedgerPostingInitialBalance, id, model.LedgerExternalAccountID,
+			model.AccountID(id), 10000, model.CurrencyBRL)
+		if err := ledgerRepo.Post(context.Background(), posting); err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	// the interest is credited to the revenue account of the bank
+	revenueID := uuid.NewString()
+	_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+		revenueID, "Springfield Bank", "00000000002", "secret", 0)
+	if err != nil {
+		t.Fatalf("error on setup = %v", err)
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{}))
+	defer ts.Close()
+
+	originHeader := newTestAuthHeader(t, authSecret, originID)
+	operatorHeader := newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleOperator)
+
+	doRequest := func(method string, path string, header map[string][]string, body string, wantStatus int) string {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resBody, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != wantStatus {
+			t.Fatalf("%s %s, statusCode = %v, wantStatus %v, body %s", method, path, res.StatusCode, wantStatus, resBody)
+		}
+
+		return string(resBody)
+	}
+	transferBody := func(amount string) string {
+		return fmt.Sprintf(`{"account_destination_id":%q, "amount":%s}`, destinationID, amount)
+	}
+	creditLimitPath := "/accounts/" + originID + "/credit-limit"
+	balancePath := "/accounts/" + originID + "/balance"
+
+	body := doRequest(http.MethodPost, "/transfers", originHeader, transferBody("250"), http.StatusUnprocessableEntity)
+	ja.Assertf(body, fmt.Sprintf(`{"code":422, "message":%q}`, usecase.ErrAccountCurrentBalanceInsufficient))
+
+	doRequest(http.MethodPut, creditLimitPath, originHeader, `{"credit_limit":200}`, http.StatusForbidden)
+	doRequest(http.MethodPut, "/accounts/"+uuid.NewString()+"/credit-limit", operatorHeader, `{"credit_limit":200}`, http.StatusNotFound)
+
+	body = doRequest(http.MethodPut, creditLimitPath, operatorHeader, `{"credit_limit":200}`, http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":100, "credit_limit":200, "available_balance":300}`, originID))
+
+	doRequest(http.MethodPost, "/transfers", originHeader, transferBody("250"), http.StatusCreated)
+
+	body = doRequest(http.MethodGet, balancePath, originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":-150, "credit_limit":200, "available_balance":50}`, originID))
+
+	body = doRequest(http.MethodPost, "/transfers", originHeader, transferBody("60"), http.StatusUnprocessableEntity)
+	ja.Assertf(body, fmt.Sprintf(`{"code":422, "message":%q}`, usecase.ErrAccountCurrentBalanceInsufficient))
+
+	// the movements are moved to two days ago, so they're made before the end of the day charged
+	_, err = testDbPool.Exec(context.Background(), `
+		ALTER TABLE ledger_entries DISABLE TRIGGER ledger_entries_immutable;
+		UPDATE ledger_entries SET created_at = created_at - interval '2 days';
+		ALTER TABLE ledger_entries ENABLE TRIGGER ledger_entries_immutable;
+	`)
+	if err != nil {
+		t.Fatalf("error backdating the ledger entries = %v", err)
+	}
+
+	// charges the interest of the day ended, only once
+	odUC := usecase.NewOverdraftUseCase(
+		postgres.NewOverdraftRepository(testDbPool),
+		postgres.NewAccountRepository(testDbPool),
+		ledgerRepo,
+		usecase.OverdraftInterestPolicy{MonthlyRate: 800, RevenueAccountID: model.AccountID(revenueID)})
+	for _, want := range []int{1, 0} {
+		processed, err := odUC.ExecuteDue(context.Background(), 10)
+		if err != nil || processed != want {
+			t.Fatalf("ExecuteDue() processed = %v, error = %v, want %v processed", processed, err, want)
+		}
+	}
+
+	body = doRequest(http.MethodGet, balancePath, originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":-150.4, "credit_limit":200, "available_balance":49.6}`, originID))
+
+	var revenueBalance model.Money
+	err = testDbPool.QueryRow(context.Background(), "SELECT balance FROM accounts WHERE id = $1", revenueID).Scan(&revenueBalance)
+	if err != nil || revenueBalance != 40 {
+		t.Errorf("revenue account balance = %v, error = %v, want 40", revenueBalance, err)
+	}
+
+	// lowering the limit below what the account owes only stops new debits
+	body = doRequest(http.MethodPut, creditLimitPath, operatorHeader, `{"credit_limit":100}`, http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":-150.4, "credit_limit":100, "available_balance":-50.4}`, originID))
+
+	doRequest(http.MethodPost, "/transfers", originHeader, transferBody("1"), http.StatusUnprocessableEntity)
+}
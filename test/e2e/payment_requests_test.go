@@ -0,0 +1,163 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+)
+
+func Test_paymentRequests_ApproveDeclineAndExpire(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	requesterID := uuid.NewString()
+	payerID := uuid.NewString()
+	otherID := uuid.NewString()
+	for i, id := range []string{requesterID, payerID, otherID} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", 10000)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{}))
+	defer ts.Close()
+
+	requesterHeader := newTestAuthHeader(t, authSecret, requesterID)
+	payerHeader := newTestAuthHeader(t, authSecret, payerID)
+	otherHeader := newTestAuthHeader(t, authSecret, otherID)
+
+	doRequest := func(method string, path string, header map[string][]string, body string, wantStatus int) string {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resBody, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != wantStatus {
+			t.Fatalf("%s %s, statusCode = %v, wantStatus %v, body %s", method, path, res.StatusCode, wantStatus, resBody)
+		}
+
+		return string(resBody)
+	}
+
+	requestPayment := func(payer string, amount string) string {
+		input := fmt.Sprintf(`{"amount":%s}`, amount)
+		want := fmt.Sprintf(`{"id":"<<PRESENCE>>", "requester_account_id":%q, "amount":%s, "expires_at":"<<PRESENCE>>", "status":"pending", "link":"<<PRESENCE>>", "created_at":"<<PRESENCE>>"}`,
+			requesterID, amount)
+		if payer != "" {
+			input = fmt.Sprintf(`{"payer_account_id":%q, "amount":%s}`, payer, amount)
+			want = fmt.Sprintf(`{"id":"<<PRESENCE>>", "requester_account_id":%q, "payer_account_id":%q, "amount":%s, "expires_at":"<<PRESENCE>>", "status":"pending", "created_at":"<<PRESENCE>>"}`,
+				requesterID, payer, amount)
+		}
+
+		body := doRequest(http.MethodPost, "/payment-requests", requesterHeader, input, http.StatusCreated)
+		ja.Assertf(body, want)
+
+		var output struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(body), &output); err != nil {
+			t.Fatal(err)
+		}
+
+		return output.ID
+	}
+
+	body := doRequest(http.MethodPost, "/payment-requests", requesterHeader, fmt.Sprintf(`{"payer_account_id":%q, "amount":1}`, requesterID), http.StatusBadRequest)
+	ja.Assertf(body, `{"code":400,"message":"'payer_account_id' must be another account"}`)
+
+	toApproveID := requestPayment(payerID, "60")
+	tooHighID := requestPayment(payerID, "200")
+	toDeclineID := requestPayment(payerID, "5")
+	sharedID := requestPayment("", "30")
+
+	body = doRequest(http.MethodPost, "/payment-requests/"+toApproveID+"/approve", otherHeader, "", http.StatusNotFound)
+	ja.Assertf(body, `{"code":404,"message":"payment request not found"}`)
+
+	body = doRequest(http.MethodPost, "/payment-requests/"+tooHighID+"/approve", payerHeader, "", http.StatusUnprocessableEntity)
+	ja.Assertf(body, `{"code":422,"message":"current account balance is insufficient"}`)
+
+	body = doRequest(http.MethodPost, "/payment-requests/"+toApproveID+"/approve", payerHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "requester_account_id":%q, "payer_account_id":%q, "amount":60, "expires_at":"<<PRESENCE>>", "status":"paid", "transfer_id":"<<PRESENCE>>", "created_at":"<<PRESENCE>>", "closed_at":"<<PRESENCE>>"}`,
+		toApproveID, requesterID, payerID))
+
+	body = doRequest(http.MethodPost, "/payment-requests/"+toApproveID+"/approve", payerHeader, "", http.StatusConflict)
+	ja.Assertf(body, `{"code":409,"message":"payment request was already paid, declined or expired"}`)
+
+	body = doRequest(http.MethodPost, "/payment-requests/"+toDeclineID+"/decline", payerHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "requester_account_id":%q, "payer_account_id":%q, "amount":5, "expires_at":"<<PRESENCE>>", "status":"declined", "created_at":"<<PRESENCE>>", "closed_at":"<<PRESENCE>>"}`,
+		toDeclineID, requesterID, payerID))
+
+	body = doRequest(http.MethodPost, "/payment-requests/"+sharedID+"/decline", otherHeader, "", http.StatusUnprocessableEntity)
+	ja.Assertf(body, `{"code":422,"message":"shared payment requests can't be declined"}`)
+
+	body = doRequest(http.MethodPost, "/payment-requests/"+sharedID+"/approve", otherHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "requester_account_id":%q, "payer_account_id":%q, "amount":30, "expires_at":"<<PRESENCE>>", "status":"paid", "transfer_id":"<<PRESENCE>>", "link":"<<PRESENCE>>", "created_at":"<<PRESENCE>>", "closed_at":"<<PRESENCE>>"}`,
+		sharedID, requesterID, otherID))
+
+	// makes the remaining pending request expired
+	_, err := testDbPool.Exec(context.Background(), "UPDATE payment_requests SET expires_at = now() - interval '1 second' WHERE status = 'pending'")
+	if err != nil {
+		t.Fatalf("error expiring the requests = %v", err)
+	}
+
+	body = doRequest(http.MethodPost, "/payment-requests/"+tooHighID+"/decline", payerHeader, "", http.StatusConflict)
+	ja.Assertf(body, `{"code":409,"message":"payment request is expired"}`)
+
+	prUC := usecase.NewPaymentRequestUseCase(
+		postgres.NewPaymentRequestRepository(testDbPool),
+		postgres.NewTransferRepository(testDbPool),
+		postgres.NewAccountRepository(testDbPool),
+		postgres.NewLedgerRepository(testDbPool),
+		postgres.NewTransferLimitRepository(testDbPool),
+		usecase.TransferLimitPolicy{},
+		usecase.FeePolicy{})
+	expired, err := prUC.ExecuteDue(context.Background(), 10)
+	if err != nil || expired != 1 {
+		t.Fatalf("ExecuteDue() expired = %v, error = %v, want 1 expired", expired, err)
+	}
+
+	body = doRequest(http.MethodGet, "/payment-requests?status=expired", payerHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`[{"id":%q, "requester_account_id":%q, "payer_account_id":%q, "amount":200, "expires_at":"<<PRESENCE>>", "status":"expired", "created_at":"<<PRESENCE>>", "closed_at":"<<PRESENCE>>"}]`,
+		tooHighID, requesterID, payerID))
+
+	body = doRequest(http.MethodGet, "/accounts/"+requesterID+"/balance", requesterHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":190, "credit_limit":0, "available_balance":190}`, requesterID))
+
+	body = doRequest(http.MethodGet, "/accounts/"+payerID+"/balance", payerHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":40, "credit_limit":0, "available_balance":40}`, payerID))
+}
@@ -0,0 +1,162 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kinbiko/jsonassert"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/repository"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+)
+
+func Test_pixKeys(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	holderID := uuid.NewString()
+	senderID := uuid.NewString()
+	for _, account := range []struct{ id, name, cpf string }{
+		{holderID, "Homer Jay Simpson", "59951332099"},
+		{senderID, "Ned Flanders", "34363916206"},
+	} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			account.id, account.name, account.cpf, "secret", 10000)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{}))
+	defer ts.Close()
+
+	holderHeader := newTestAuthHeader(t, authSecret, holderID)
+	senderHeader := newTestAuthHeader(t, authSecret, senderID)
+
+	doRequest := func(method string, path string, header map[string][]string, body string, wantStatus int) string {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resBody, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != wantStatus {
+			t.Fatalf("%s %s, statusCode = %v, wantStatus %v, body %s", method, path, res.StatusCode, wantStatus, resBody)
+		}
+
+		return string(resBody)
+	}
+
+	body := doRequest(http.MethodPost, "/pix-keys", holderHeader, `{"type":"cpf"}`, http.StatusCreated)
+	ja.Assertf(body, `{"type":"cpf", "key":"59951332099", "status":"active", "created_at":"<<PRESENCE>>"}`)
+
+	body = doRequest(http.MethodPost, "/pix-keys", senderHeader, `{"type":"cpf", "key":"599.513.320-99"}`, http.StatusBadRequest)
+	ja.Assertf(body, fmt.Sprintf(`{"code":400, "message":%q}`, usecase.ErrPixKeyCPFNotOwn))
+
+	body = doRequest(http.MethodPost, "/pix-keys", holderHeader, `{"type":"email", "key":"Homer@Springfield.com"}`, http.StatusCreated)
+	ja.Assertf(body, `{"type":"email", "key":"homer@springfield.com", "status":"pending", "verification_expires_at":"<<PRESENCE>>", "created_at":"<<PRESENCE>>"}`)
+
+	// the pending keys can't be looked up
+	doRequest(http.MethodGet, "/pix-keys/homer@springfield.com", senderHeader, "", http.StatusNotFound)
+	body = doRequest(http.MethodPost, "/transfers", senderHeader, `{"destination_key":"homer@springfield.com", "amount":10}`, http.StatusUnprocessableEntity)
+	ja.Assertf(body, fmt.Sprintf(`{"code":422, "message":%q}`, usecase.ErrTransferDestinationKeyNotFound))
+
+	// the pending keys don't take the value, other accounts can claim it too
+	body = doRequest(http.MethodPost, "/pix-keys", senderHeader, `{"type":"email", "key":"homer@springfield.com"}`, http.StatusCreated)
+	ja.Assertf(body, `{"type":"email", "key":"homer@springfield.com", "status":"pending", "verification_expires_at":"<<PRESENCE>>", "created_at":"<<PRESENCE>>"}`)
+
+	// the code is only logged, so a known one is set
+	hashedCode, err := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = testDbPool.Exec(context.Background(), "UPDATE pix_keys SET verification_code = $1 WHERE value = 'homer@springfield.com' AND account_id = $2", string(hashedCode), holderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body = doRequest(http.MethodPost, "/pix-keys/homer@springfield.com/verify", holderHeader, `{"code":"654321"}`, http.StatusUnprocessableEntity)
+	ja.Assertf(body, fmt.Sprintf(`{"code":422, "message":%q}`, usecase.ErrPixKeyVerificationCodeWrong))
+
+	body = doRequest(http.MethodPost, "/pix-keys/homer@springfield.com/verify", holderHeader, `{"code":"123456"}`, http.StatusOK)
+	ja.Assertf(body, `{"type":"email", "key":"homer@springfield.com", "status":"active", "created_at":"<<PRESENCE>>"}`)
+
+	doRequest(http.MethodPost, "/pix-keys/homer@springfield.com/verify", holderHeader, `{"code":"123456"}`, http.StatusConflict)
+
+	// the first verified takes the value, the other claims are dropped
+	doRequest(http.MethodPost, "/pix-keys/homer@springfield.com/verify", senderHeader, `{"code":"123456"}`, http.StatusNotFound)
+
+	body = doRequest(http.MethodPost, "/pix-keys", senderHeader, `{"type":"email", "key":"homer@springfield.com"}`, http.StatusConflict)
+	ja.Assertf(body, fmt.Sprintf(`{"code":409, "message":%q}`, repository.ErrPixKeyAlreadyExists))
+
+	body = doRequest(http.MethodGet, "/pix-keys/HOMER@springfield.com", senderHeader, "", http.StatusOK)
+	ja.Assertf(body, `{"type":"email", "key":"homer@springfield.com", "name":"Homer J. S.", "cpf":"***.513.320-**"}`)
+
+	body = doRequest(http.MethodPost, "/transfers", senderHeader, `{"destination_key":"homer@springfield.com", "amount":10}`, http.StatusCreated)
+	ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "kind":"transfer", "account_origin_id":%q, "account_destination_id":%q, "currency":"BRL", "amount":10, "refunded_amount":0, "created_at":"<<PRESENCE>>"}`, senderID, holderID))
+
+	// the BR Codes are built for the keys of the caller, and paid by the others
+	doRequest(http.MethodGet, "/pix-keys/homer@springfield.com/brcode", senderHeader, "", http.StatusNotFound)
+	body = doRequest(http.MethodGet, "/pix-keys/homer@springfield.com/brcode?amount=10.50&txid=DONUTS42", holderHeader, "", http.StatusOK)
+	brCode := "00020126430014br.gov.bcb.pix0121homer@springfield.com520400005303986540510.505802BR5917Homer Jay Simpson6011SPRINGFIELD62120508DONUTS4263048175"
+	ja.Assertf(body, fmt.Sprintf(`{"payload":%q, "key":"homer@springfield.com", "amount":10.5, "txid":"DONUTS42"}`, brCode))
+
+	body = doRequest(http.MethodGet, "/pix-keys/homer@springfield.com/brcode?format=png", holderHeader, "", http.StatusOK)
+	if !strings.HasPrefix(body, "\x89PNG") {
+		t.Errorf("GET /pix-keys/homer@springfield.com/brcode?format=png, body is not a PNG image")
+	}
+
+	body = doRequest(http.MethodPost, "/transfers", senderHeader, fmt.Sprintf(`{"br_code":%q, "amount":10}`, brCode), http.StatusBadRequest)
+	ja.Assertf(body, fmt.Sprintf(`{"code":400, "message":%q}`, usecase.ErrTransferBRCodeAmountMismatch))
+
+	body = doRequest(http.MethodPost, "/transfers", senderHeader, fmt.Sprintf(`{"br_code":%q}`, brCode), http.StatusCreated)
+	ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "kind":"transfer", "account_origin_id":%q, "account_destination_id":%q, "currency":"BRL", "amount":10.5, "refunded_amount":0, "created_at":"<<PRESENCE>>"}`, senderID, holderID))
+
+	body = doRequest(http.MethodPost, "/transfers", holderHeader, `{"destination_key":"599.513.320-99", "amount":10}`, http.StatusBadRequest)
+	ja.Assertf(body, fmt.Sprintf(`{"code":400, "message":%q}`, usecase.ErrTransferSameAccount))
+
+	body = doRequest(http.MethodGet, "/pix-keys", holderHeader, "", http.StatusOK)
+	ja.Assertf(body, `[
+		{"type":"cpf", "key":"59951332099", "status":"active", "created_at":"<<PRESENCE>>"},
+		{"type":"email", "key":"homer@springfield.com", "status":"active", "created_at":"<<PRESENCE>>"}
+	]`)
+
+	doRequest(http.MethodDelete, "/pix-keys/homer@springfield.com", senderHeader, "", http.StatusNotFound)
+	doRequest(http.MethodDelete, "/pix-keys/homer@springfield.com", holderHeader, "", http.StatusNoContent)
+	doRequest(http.MethodGet, "/pix-keys/homer@springfield.com", senderHeader, "", http.StatusNotFound)
+
+	// up to 5 keys per account
+	for i := 0; i < 4; i++ {
+		doRequest(http.MethodPost, "/pix-keys", holderHeader, `{"type":"random"}`, http.StatusCreated)
+	}
+	body = doRequest(http.MethodPost, "/pix-keys", holderHeader, `{"type":"phone", "key":"+55 (11) 98765-4321"}`, http.StatusUnprocessableEntity)
+	ja.Assertf(body, fmt.Sprintf(`{"code":422, "message":%q}`, repository.ErrPixKeyLimitReached))
+}
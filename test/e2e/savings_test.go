@@ -0,0 +1,118 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+)
+
+func Test_accounts_SavingsInterest(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	treasuryID := uuid.NewString()
+	accountID := uuid.NewString()
+	for i, id := range []string{treasuryID, accountID} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", 1000000)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	savRepo := postgres.NewSavingsRepository(testDbPool)
+	for _, accrual := range []*model.SavingsInterestAccrual{
+		model.NewSavingsInterestAccrual(model.AccountID(accountID), time.Date(2021, 1, 29, 0, 0, 0, 0, time.UTC), 1000000, 50788),
+		model.NewSavingsInterestAccrual(model.AccountID(accountID), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), 1000001, 50788),
+	} {
+		if err := savRepo.CreateAccrual(context.Background(), accrual); err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+	payment := model.NewSavingsInterestPayment(model.AccountID(accountID), time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), 507)
+	payment.CreatedAt = time.Date(2021, 2, 1, 3, 1, 0, 0, time.UTC)
+	if err := savRepo.CreatePayment(context.Background(), payment); err != nil {
+		t.Fatalf("error on setup = %v", err)
+	}
+
+	savingsPolicy := usecase.SavingsPolicy{TreasuryAccountID: model.AccountID(treasuryID), AnnualRate: 1365}
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{Savings: savingsPolicy}))
+	defer ts.Close()
+
+	accountHeader := newTestAuthHeader(t, authSecret, accountID)
+
+	doRequest := func(path string, header map[string][]string, wantStatus int) string {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resBody, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != wantStatus {
+			t.Fatalf("GET %s, statusCode = %v, wantStatus %v, body %s", path, res.StatusCode, wantStatus, resBody)
+		}
+
+		return string(resBody)
+	}
+	historyPath := "/accounts/" + accountID + "/savings-interest"
+
+	doRequest(historyPath, newTestAuthHeader(t, authSecret, treasuryID), http.StatusForbidden)
+	doRequest(historyPath+"?from=2021-02-01&to=2021-01-01", accountHeader, http.StatusBadRequest)
+
+	body := doRequest(historyPath+"?from=2021-01-01&to=2021-01-31", accountHeader, http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{
+		"account_id":%q,
+		"annual_rate":"13.65%%",
+		"from":"2021-01-01",
+		"to":"2021-01-31",
+		"accrued_interest":"5.0788000000",
+		"accruals":[{"day":"2021-01-29", "balance":10000, "daily_rate":"0.00050788", "interest":"5.0788000000"}],
+		"payments":[{"month":"2021-01", "amount":5.07, "paid_at":"<<PRESENCE>>"}]
+	}`, accountID))
+
+	// the payments of the month of 'from' are included
+	body = doRequest(historyPath+"?from=2021-01-15&to=2021-02-01", accountHeader, http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{
+		"account_id":%q,
+		"annual_rate":"13.65%%",
+		"from":"2021-01-15",
+		"to":"2021-02-01",
+		"accrued_interest":"10.1576050788",
+		"accruals":[
+			{"day":"2021-01-29", "balance":10000, "daily_rate":"0.00050788", "interest":"5.0788000000"},
+			{"day":"2021-02-01", "balance":10000.01, "daily_rate":"0.00050788", "interest":"5.0788050788"}
+		],
+		"payments":[{"month":"2021-01", "amount":5.07, "paid_at":"<<PRESENCE>>"}]
+	}`, accountID))
+}
@@ -0,0 +1,141 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+)
+
+func Test_scheduledTransfers_CreateCancelAndExecute(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	originID := uuid.NewString()
+	destinationID := uuid.NewString()
+	for i, id := range []string{originID, destinationID} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", 10000)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{}))
+	defer ts.Close()
+
+	originHeader := newTestAuthHeader(t, authSecret, originID)
+	destinationHeader := newTestAuthHeader(t, authSecret, destinationID)
+
+	doRequest := func(method string, path string, header map[string][]string, body string, wantStatus int) string {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resBody, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != wantStatus {
+			t.Fatalf("%s %s, statusCode = %v, wantStatus %v, body %s", method, path, res.StatusCode, wantStatus, resBody)
+		}
+
+		return string(resBody)
+	}
+
+	createSchedule := func(amount string) string {
+		body := doRequest(http.MethodPost, "/scheduled-transfers", originHeader,
+			fmt.Sprintf(`{"account_destination_id":%q, "amount":%s, "scheduled_for":%q}`,
+				destinationID, amount, time.Now().Add(24*time.Hour).Format(time.RFC3339)),
+			http.StatusCreated)
+		ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "account_origin_id":%q, "account_destination_id":%q, "amount":%s, "scheduled_for":"<<PRESENCE>>", "status":"scheduled", "created_at":"<<PRESENCE>>"}`,
+			originID, destinationID, amount))
+
+		var output struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(body), &output); err != nil {
+			t.Fatal(err)
+		}
+
+		return output.ID
+	}
+
+	body := doRequest(http.MethodPost, "/scheduled-transfers", originHeader,
+		fmt.Sprintf(`{"account_destination_id":%q, "amount":1, "scheduled_for":%q}`, destinationID, time.Now().Add(-time.Hour).Format(time.RFC3339)),
+		http.StatusBadRequest)
+	ja.Assertf(body, `{"code":400,"message":"'scheduled_for' must be in the future, up to one year ahead"}`)
+
+	toExecuteID := createSchedule("60")
+	toFailID := createSchedule("50")
+	toCancelID := createSchedule("1")
+
+	body = doRequest(http.MethodPost, "/scheduled-transfers/"+toCancelID+"/cancel", destinationHeader, "", http.StatusNotFound)
+	ja.Assertf(body, `{"code":404,"message":"scheduled transfer not found"}`)
+
+	body = doRequest(http.MethodPost, "/scheduled-transfers/"+toCancelID+"/cancel", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "account_origin_id":%q, "account_destination_id":%q, "amount":1, "scheduled_for":"<<PRESENCE>>", "status":"cancelled", "created_at":"<<PRESENCE>>", "processed_at":"<<PRESENCE>>"}`,
+		toCancelID, originID, destinationID))
+
+	body = doRequest(http.MethodPost, "/scheduled-transfers/"+toCancelID+"/cancel", originHeader, "", http.StatusConflict)
+	ja.Assertf(body, `{"code":409,"message":"scheduled transfer was already processed or cancelled"}`)
+
+	// makes the pending schedules due, the first one before the second
+	_, err := testDbPool.Exec(context.Background(), "UPDATE scheduled_transfers SET scheduled_for = now() - (amount || ' seconds')::interval WHERE status = 'scheduled'")
+	if err != nil {
+		t.Fatalf("error making the schedules due = %v", err)
+	}
+
+	schUC := usecase.NewScheduledTransferUseCase(
+		postgres.NewScheduledTransferRepository(testDbPool),
+		postgres.NewTransferRepository(testDbPool),
+		postgres.NewAccountRepository(testDbPool),
+		postgres.NewLedgerRepository(testDbPool),
+		postgres.NewTransferLimitRepository(testDbPool),
+		usecase.TransferLimitPolicy{},
+		usecase.FeePolicy{})
+	processed, err := schUC.ExecuteDue(context.Background(), 10)
+	if err != nil || processed != 2 {
+		t.Fatalf("ExecuteDue() processed = %v, error = %v, want 2 processed", processed, err)
+	}
+
+	body = doRequest(http.MethodGet, "/scheduled-transfers?status=executed", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`[{"id":%q, "account_origin_id":%q, "account_destination_id":%q, "amount":60, "scheduled_for":"<<PRESENCE>>", "status":"executed", "transfer_id":"<<PRESENCE>>", "created_at":"<<PRESENCE>>", "processed_at":"<<PRESENCE>>"}]`,
+		toExecuteID, originID, destinationID))
+
+	body = doRequest(http.MethodGet, "/scheduled-transfers?status=failed", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`[{"id":%q, "account_origin_id":%q, "account_destination_id":%q, "amount":50, "scheduled_for":"<<PRESENCE>>", "status":"failed", "failure_reason":"current account balance is insufficient", "created_at":"<<PRESENCE>>", "processed_at":"<<PRESENCE>>"}]`,
+		toFailID, originID, destinationID))
+
+	body = doRequest(http.MethodGet, "/accounts/"+originID+"/balance", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":40, "credit_limit":0, "available_balance":40}`, originID))
+}
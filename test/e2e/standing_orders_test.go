@@ -0,0 +1,178 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/gateway/datasource/postgres"
+	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+)
+
+func Test_standingOrders_LifecycleAndExecute(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	originID := uuid.NewString()
+	destinationID := uuid.NewString()
+	for i, id := range []string{originID, destinationID} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", 10000)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{}))
+	defer ts.Close()
+
+	originHeader := newTestAuthHeader(t, authSecret, originID)
+	destinationHeader := newTestAuthHeader(t, authSecret, destinationID)
+
+	doRequest := func(method string, path string, header map[string][]string, body string, wantStatus int) string {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resBody, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != wantStatus {
+			t.Fatalf("%s %s, statusCode = %v, wantStatus %v, body %s", method, path, res.StatusCode, wantStatus, resBody)
+		}
+
+		return string(resBody)
+	}
+
+	startsAt := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	createOrder := func(amount string) string {
+		body := doRequest(http.MethodPost, "/standing-orders", originHeader,
+			fmt.Sprintf(`{"account_destination_id":%q, "amount":%s, "frequency":"weekly", "starts_at":%q, "max_occurrences":3}`,
+				destinationID, amount, startsAt.Format(time.RFC3339)),
+			http.StatusCreated)
+		ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "account_origin_id":%q, "account_destination_id":%q, "amount":%s, "frequency":"weekly",
+			"starts_at":"<<PRESENCE>>", "max_occurrences":3, "occurrences":0, "status":"active", "next_due_at":"<<PRESENCE>>", "next_run_at":"<<PRESENCE>>",
+			"created_at":"<<PRESENCE>>", "updated_at":"<<PRESENCE>>"}`,
+			originID, destinationID, amount))
+
+		var output struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(body), &output); err != nil {
+			t.Fatal(err)
+		}
+
+		return output.ID
+	}
+
+	body := doRequest(http.MethodPost, "/standing-orders", originHeader,
+		fmt.Sprintf(`{"account_destination_id":%q, "amount":1, "frequency":"monthly", "starts_at":%q}`, destinationID, startsAt.Format(time.RFC3339)),
+		http.StatusBadRequest)
+	ja.Assertf(body, `{"code":400,"message":"'day_of_month' must be between 1 and 31 for monthly standing orders"}`)
+
+	orderID := createOrder("60")
+	toCancelID := createOrder("1")
+
+	body = doRequest(http.MethodPatch, "/standing-orders/"+orderID, originHeader, `{"amount":70}`, http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "account_origin_id":%q, "account_destination_id":%q, "amount":70, "frequency":"weekly",
+		"starts_at":"<<PRESENCE>>", "max_occurrences":3, "occurrences":0, "status":"active", "next_due_at":"<<PRESENCE>>", "next_run_at":"<<PRESENCE>>",
+		"created_at":"<<PRESENCE>>", "updated_at":"<<PRESENCE>>"}`,
+		orderID, originID, destinationID))
+
+	body = doRequest(http.MethodPost, "/standing-orders/"+orderID+"/pause", destinationHeader, "", http.StatusNotFound)
+	ja.Assertf(body, `{"code":404,"message":"standing order not found"}`)
+
+	body = doRequest(http.MethodPost, "/standing-orders/"+orderID+"/pause", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "account_origin_id":%q, "account_destination_id":%q, "amount":70, "frequency":"weekly",
+		"starts_at":"<<PRESENCE>>", "max_occurrences":3, "occurrences":0, "status":"paused", "created_at":"<<PRESENCE>>", "updated_at":"<<PRESENCE>>"}`,
+		orderID, originID, destinationID))
+
+	body = doRequest(http.MethodPost, "/standing-orders/"+orderID+"/pause", originHeader, "", http.StatusConflict)
+	ja.Assertf(body, fmt.Sprintf(`{"code":409,"message":%q}`, usecase.ErrStandingOrderStatusChangeInvalid))
+
+	body = doRequest(http.MethodPost, "/standing-orders/"+orderID+"/resume", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "account_origin_id":%q, "account_destination_id":%q, "amount":70, "frequency":"weekly",
+		"starts_at":"<<PRESENCE>>", "max_occurrences":3, "occurrences":0, "status":"active", "next_due_at":"<<PRESENCE>>", "next_run_at":"<<PRESENCE>>",
+		"created_at":"<<PRESENCE>>", "updated_at":"<<PRESENCE>>"}`,
+		orderID, originID, destinationID))
+
+	body = doRequest(http.MethodPost, "/standing-orders/"+toCancelID+"/cancel", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "account_origin_id":%q, "account_destination_id":%q, "amount":1, "frequency":"weekly",
+		"starts_at":"<<PRESENCE>>", "max_occurrences":3, "occurrences":0, "status":"cancelled", "created_at":"<<PRESENCE>>", "updated_at":"<<PRESENCE>>"}`,
+		toCancelID, originID, destinationID))
+
+	body = doRequest(http.MethodPatch, "/standing-orders/"+toCancelID, originHeader, `{"amount":2}`, http.StatusConflict)
+	ja.Assertf(body, fmt.Sprintf(`{"code":409,"message":%q}`, usecase.ErrStandingOrderNotRunning))
+
+	soUC := usecase.NewStandingOrderUseCase(
+		postgres.NewStandingOrderRepository(testDbPool),
+		postgres.NewTransferRepository(testDbPool),
+		postgres.NewAccountRepository(testDbPool),
+		postgres.NewLedgerRepository(testDbPool),
+		postgres.NewNotificationRepository(testDbPool),
+		postgres.NewTransferLimitRepository(testDbPool),
+		usecase.TransferLimitPolicy{},
+		usecase.FeePolicy{},
+		usecase.StandingOrderRetryPolicy{MaxRetries: 1, Interval: time.Hour})
+
+	// makes the active standing order due and executes it, twice: the second time the balance is insufficient
+	for i := 0; i < 2; i++ {
+		_, err := testDbPool.Exec(context.Background(), "UPDATE standing_orders SET next_due_at = now() - interval '1 minute', next_run_at = now() - interval '1 minute' WHERE status = 'active'")
+		if err != nil {
+			t.Fatalf("error making the standing orders due = %v", err)
+		}
+
+		processed, err := soUC.ExecuteDue(context.Background(), 10)
+		if err != nil || processed != 1 {
+			t.Fatalf("ExecuteDue() processed = %v, error = %v, want 1 processed", processed, err)
+		}
+	}
+
+	body = doRequest(http.MethodGet, "/standing-orders", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`[
+		{"id":%q, "account_origin_id":%q, "account_destination_id":%q, "amount":1, "frequency":"weekly",
+			"starts_at":"<<PRESENCE>>", "max_occurrences":3, "occurrences":0, "status":"cancelled", "created_at":"<<PRESENCE>>", "updated_at":"<<PRESENCE>>"},
+		{"id":%q, "account_origin_id":%q, "account_destination_id":%q, "amount":70, "frequency":"weekly",
+			"starts_at":"<<PRESENCE>>", "max_occurrences":3, "occurrences":1, "status":"active", "next_due_at":"<<PRESENCE>>", "next_run_at":"<<PRESENCE>>",
+			"retries":1, "last_failure_reason":"current account balance is insufficient", "last_run_at":"<<PRESENCE>>",
+			"created_at":"<<PRESENCE>>", "updated_at":"<<PRESENCE>>"}
+	]`, toCancelID, originID, destinationID, orderID, originID, destinationID))
+
+	body = doRequest(http.MethodGet, "/notifications", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`[{"id":"<<PRESENCE>>", "type":"standing_order_retrying", "reference_id":%q, "message":"<<PRESENCE>>", "created_at":"<<PRESENCE>>"}]`,
+		orderID))
+
+	body = doRequest(http.MethodGet, "/notifications", destinationHeader, "", http.StatusOK)
+	ja.Assertf(body, `[]`)
+
+	body = doRequest(http.MethodGet, "/accounts/"+originID+"/balance", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":30, "credit_limit":0, "available_balance":30}`, originID))
+}
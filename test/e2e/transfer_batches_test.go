@@ -0,0 +1,205 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+)
+
+func Test_transferBatches_AtomicAndBestEffort(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	originID := uuid.NewString()
+	destinationID := uuid.NewString()
+	unknownID := uuid.NewString()
+	for i, id := range []string{originID, destinationID} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", 10000)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{}))
+	defer ts.Close()
+
+	originHeader := newTestAuthHeader(t, authSecret, originID)
+	destinationHeader := newTestAuthHeader(t, authSecret, destinationID)
+
+	doRequest := func(method string, path string, header map[string][]string, body string, wantStatus int) string {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resBody, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != wantStatus {
+			t.Fatalf("%s %s, statusCode = %v, wantStatus %v, body %s", method, path, res.StatusCode, wantStatus, resBody)
+		}
+
+		return string(resBody)
+	}
+	item := func(itemID string, destination string, amount int) string {
+		return fmt.Sprintf(`{"item_id":%q, "account_destination_id":%q, "amount":%d}`, itemID, destination, amount)
+	}
+	outcome := func(itemID string, destination string, amount int, status string) string {
+		switch status {
+		case "executed", "duplicate":
+			return fmt.Sprintf(`{"item_id":%q, "account_destination_id":%q, "amount":%d, "status":%q, "transfer_id":"<<PRESENCE>>"}`, itemID, destination, amount, status)
+		case "rejected":
+			return fmt.Sprintf(`{"item_id":%q, "account_destination_id":%q, "amount":%d, "status":%q, "failure_reason":"<<PRESENCE>>"}`, itemID, destination, amount, status)
+		default:
+			return fmt.Sprintf(`{"item_id":%q, "account_destination_id":%q, "amount":%d, "status":%q}`, itemID, destination, amount, status)
+		}
+	}
+	createBatch := func(mode string, items []string, wantStatus string, wantItems []string) string {
+		body := doRequest(http.MethodPost, "/transfer-batches", originHeader,
+			fmt.Sprintf(`{"mode":%q, "items":[%s]}`, mode, strings.Join(items, ",")), http.StatusCreated)
+		ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "account_origin_id":%q, "mode":%q, "status":%q, "items":[%s], "created_at":"<<PRESENCE>>", "completed_at":"<<PRESENCE>>"}`,
+			originID, mode, wantStatus, strings.Join(wantItems, ",")))
+
+		var output struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(body), &output); err != nil {
+			t.Fatal(err)
+		}
+
+		return output.ID
+	}
+
+	body := doRequest(http.MethodPost, "/transfer-batches", originHeader,
+		fmt.Sprintf(`{"mode":"atomic", "items":[%s, %s]}`, item("1", destinationID, 1), item("1", destinationID, 2)), http.StatusBadRequest)
+	ja.Assertf(body, `{"code":400,"message":"items[1]: 'item_id' must be unique in the batch"}`)
+
+	partialID := createBatch("best_effort",
+		[]string{item("1", destinationID, 60), item("2", unknownID, 10), item("3", destinationID, 50)},
+		"partially_completed",
+		[]string{outcome("1", destinationID, 60, "executed"), outcome("2", unknownID, 10, "rejected"), outcome("3", destinationID, 50, "rejected")})
+
+	createBatch("atomic",
+		[]string{item("1", destinationID, 60), item("4", destinationID, 10), item("5", unknownID, 10)},
+		"failed",
+		[]string{outcome("1", destinationID, 60, "duplicate"), outcome("4", destinationID, 10, "skipped"), outcome("5", unknownID, 10, "rejected")})
+
+	createBatch("atomic",
+		[]string{item("1", destinationID, 60), item("4", destinationID, 10), item("5", destinationID, 20)},
+		"completed",
+		[]string{outcome("1", destinationID, 60, "duplicate"), outcome("4", destinationID, 10, "executed"), outcome("5", destinationID, 20, "executed")})
+
+	body = doRequest(http.MethodGet, "/transfer-batches/"+partialID, destinationHeader, "", http.StatusNotFound)
+	ja.Assertf(body, `{"code":404,"message":"transfer batch not found"}`)
+
+	body = doRequest(http.MethodGet, "/transfer-batches/"+partialID, originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "account_origin_id":%q, "mode":"best_effort", "status":"partially_completed", "items":[%s, %s, %s], "created_at":"<<PRESENCE>>", "completed_at":"<<PRESENCE>>"}`,
+		partialID, originID, outcome("1", destinationID, 60, "executed"), outcome("2", unknownID, 10, "rejected"), outcome("3", destinationID, 50, "rejected")))
+
+	body = doRequest(http.MethodGet, "/accounts/"+originID+"/balance", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":10, "credit_limit":0, "available_balance":10}`, originID))
+}
+
+func Test_transferBatches_ConcurrentAtomic(t *testing.T) {
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	accountIDs := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+	for i, id := range accountIDs {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", 100000)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{}))
+	defer ts.Close()
+
+	headers := make(map[string]map[string][]string, len(accountIDs))
+	for _, id := range accountIDs {
+		headers[id] = newTestAuthHeader(t, authSecret, id)
+	}
+
+	// createBatch sends an atomic batch from the origin to both destinations, in the given order
+	createBatch := func(originID string, firstID, secondID string, round int) {
+		body := fmt.Sprintf(`{"mode":"atomic", "items":[{"item_id":"%d-1", "account_destination_id":%q, "amount":1}, {"item_id":"%d-2", "account_destination_id":%q, "amount":1}]}`,
+			round, firstID, round, secondID)
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/transfer-batches", strings.NewReader(body))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		req.Header = headers[originID]
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resBody, _ := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+
+		if res.StatusCode != http.StatusCreated || !strings.Contains(string(resBody), `"status":"completed"`) {
+			t.Errorf("POST /transfer-batches, statusCode = %v, body %s, want a completed batch", res.StatusCode, resBody)
+		}
+	}
+
+	// every account sends to the other two while they send to it, the opposing batches must not deadlock
+	rounds := 10
+	var wg sync.WaitGroup
+	for round := 0; round < rounds; round++ {
+		for i, originID := range accountIDs {
+			wg.Add(1)
+			go func(originID, firstID, secondID string, round int) {
+				defer wg.Done()
+				createBatch(originID, firstID, secondID, round)
+			}(originID, accountIDs[(i+1)%3], accountIDs[(i+2)%3], round)
+		}
+	}
+	wg.Wait()
+
+	// each account sent and received the same amount
+	for _, id := range accountIDs {
+		var balance int64
+		err := testDbPool.QueryRow(context.Background(), "SELECT balance FROM accounts WHERE id = $1", id).Scan(&balance)
+		if err != nil || balance != 100000 {
+			t.Errorf("account %s balance = %v, err = %v, want 100000", id, balance, err)
+		}
+	}
+}
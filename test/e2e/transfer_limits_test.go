@@ -0,0 +1,114 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+)
+
+func Test_transfers_Limits(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+	limitPolicy := usecase.TransferLimitPolicy{
+		Defaults: model.TransferLimits{PerTransaction: 5000, Daily: 8000},
+	}
+
+	truncateDatabase(t)
+
+	originID := uuid.NewString()
+	destinationID := uuid.NewString()
+	for i, id := range []string{originID, destinationID} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", 20000)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{TransferLimits: limitPolicy}))
+	defer ts.Close()
+
+	originHeader := newTestAuthHeader(t, authSecret, originID)
+	destinationHeader := newTestAuthHeader(t, authSecret, destinationID)
+	adminHeader := newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleAdmin)
+
+	doRequest := func(method string, path string, header map[string][]string, body string, wantStatus int) string {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resBody, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != wantStatus {
+			t.Fatalf("%s %s, statusCode = %v, wantStatus %v, body %s", method, path, res.StatusCode, wantStatus, resBody)
+		}
+
+		return string(resBody)
+	}
+	transferBody := func(amount string) string {
+		return fmt.Sprintf(`{"account_destination_id":%q, "amount":%s}`, destinationID, amount)
+	}
+	limitsPath := "/accounts/" + originID + "/transfer-limits"
+
+	body := doRequest(http.MethodGet, limitsPath, originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"account_id":%q, "per_transaction":50, "daily":80, "night":false, "allowance":50, "allowance_limit":"per_transaction"}`, originID))
+
+	doRequest(http.MethodGet, limitsPath, destinationHeader, "", http.StatusForbidden)
+
+	body = doRequest(http.MethodPost, "/transfers", originHeader, transferBody("60"), http.StatusUnprocessableEntity)
+	ja.Assertf(body, `{"code":422, "message":"'amount' exceeds the per transaction transfer limit, the remaining allowance is 50.00"}`)
+
+	body = doRequest(http.MethodPost, "/transfers", originHeader, transferBody("50"), http.StatusCreated)
+	ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "kind":"transfer", "account_origin_id":%q, "account_destination_id":%q, "currency":"BRL", "amount":50, "created_at":"<<PRESENCE>>"}`,
+		originID, destinationID))
+
+	body = doRequest(http.MethodPost, "/transfers", originHeader, transferBody("40"), http.StatusUnprocessableEntity)
+	ja.Assertf(body, `{"code":422, "message":"'amount' exceeds the daily transfer limit, the remaining allowance is 30.00"}`)
+
+	body = doRequest(http.MethodGet, limitsPath, originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"account_id":%q, "per_transaction":50, "daily":80, "night":false, "allowance":30, "allowance_limit":"daily"}`, originID))
+
+	doRequest(http.MethodPut, limitsPath, originHeader, `{"daily":0}`, http.StatusForbidden)
+	doRequest(http.MethodPut, "/accounts/"+uuid.NewString()+"/transfer-limits", adminHeader, `{"daily":0}`, http.StatusNotFound)
+
+	body = doRequest(http.MethodPut, limitsPath, adminHeader, `{"daily":0}`, http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"account_id":%q, "per_transaction":50, "night":false, "allowance":50, "allowance_limit":"per_transaction"}`, originID))
+
+	doRequest(http.MethodPost, "/transfers", originHeader, transferBody("40"), http.StatusCreated)
+
+	// going back to the defaults
+	body = doRequest(http.MethodPut, limitsPath, adminHeader, `{}`, http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"account_id":%q, "per_transaction":50, "daily":80, "night":false, "allowance":0, "allowance_limit":"daily"}`, originID))
+
+	body = doRequest(http.MethodGet, "/accounts/"+originID+"/balance", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":110, "credit_limit":0, "available_balance":110}`, originID))
+}
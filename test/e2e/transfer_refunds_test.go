@@ -0,0 +1,124 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kinbiko/jsonassert"
+
+	"github.com/helder-jaspion/go-springfield-bank/config"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/model"
+	"github.com/helder-jaspion/go-springfield-bank/pkg/domain/usecase"
+	httpGateway "github.com/helder-jaspion/go-springfield-bank/pkg/gateway/http"
+)
+
+func Test_transfers_RefundAndReverse(t *testing.T) {
+	ja := jsonassert.New(t)
+
+	authSecret := "any-secret"
+	authConf := config.ConfAuth{
+		SecretKey:      authSecret,
+		AccessTokenDur: 30 * time.Second,
+	}
+
+	truncateDatabase(t)
+
+	originID := uuid.NewString()
+	destinationID := uuid.NewString()
+	for i, id := range []string{originID, destinationID} {
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id, fmt.Sprintf("Simpson %d", i), fmt.Sprintf("0000000000%d", i), "secret", 10000)
+		if err != nil {
+			t.Fatalf("error on setup = %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(httpGateway.GetHTTPHandler(testDbPool, testRedisClient, authConf, httpGateway.HandlerOptions{}))
+	defer ts.Close()
+
+	originHeader := newTestAuthHeader(t, authSecret, originID)
+	destinationHeader := newTestAuthHeader(t, authSecret, destinationID)
+	operatorHeader := newTestAuthHeader(t, authSecret, uuid.NewString(), model.RoleOperator)
+
+	doRequest := func(method string, path string, header map[string][]string, body string, wantStatus int) string {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resBody, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != wantStatus {
+			t.Fatalf("%s %s, statusCode = %v, wantStatus %v, body %s", method, path, res.StatusCode, wantStatus, resBody)
+		}
+
+		return string(resBody)
+	}
+	createTransfer := func(amount string) string {
+		body := doRequest(http.MethodPost, "/transfers", originHeader, fmt.Sprintf(`{"account_destination_id":%q, "amount":%s}`, destinationID, amount),
+			http.StatusCreated)
+
+		var output struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(body), &output); err != nil {
+			t.Fatal(err)
+		}
+
+		return output.ID
+	}
+
+	transferID := createTransfer("30")
+	toReverseID := createTransfer("20")
+
+	body := doRequest(http.MethodPost, "/transfers/"+transferID+"/refund", originHeader, "", http.StatusNotFound)
+	ja.Assertf(body, `{"code":404,"message":"transfer not found"}`)
+
+	body = doRequest(http.MethodPost, "/transfers/"+transferID+"/refund", destinationHeader, `{"amount":10}`, http.StatusCreated)
+	ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "kind":"refund", "original_transfer_id":%q, "account_origin_id":%q, "account_destination_id":%q, "currency":"BRL",
+		"amount":10, "created_at":"<<PRESENCE>>"}`, transferID, destinationID, originID))
+
+	body = doRequest(http.MethodPost, "/transfers/"+transferID+"/refund", destinationHeader, `{"amount":25}`, http.StatusUnprocessableEntity)
+	ja.Assertf(body, fmt.Sprintf(`{"code":422,"message":%q}`, usecase.ErrTransferRefundAmountExceeded))
+
+	body = doRequest(http.MethodPost, "/transfers/"+transferID+"/refund", destinationHeader, "", http.StatusCreated)
+	ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "kind":"refund", "original_transfer_id":%q, "account_origin_id":%q, "account_destination_id":%q, "currency":"BRL",
+		"amount":20, "created_at":"<<PRESENCE>>"}`, transferID, destinationID, originID))
+
+	body = doRequest(http.MethodPost, "/transfers/"+transferID+"/refund", destinationHeader, "", http.StatusConflict)
+	ja.Assertf(body, fmt.Sprintf(`{"code":409,"message":%q}`, usecase.ErrTransferFullyRefunded))
+
+	body = doRequest(http.MethodPost, "/transfers/"+toReverseID+"/reverse", destinationHeader, "", http.StatusForbidden)
+	ja.Assertf(body, `{"code":403,"message":"<<PRESENCE>>"}`)
+
+	body = doRequest(http.MethodPost, "/transfers/"+toReverseID+"/reverse", operatorHeader, "", http.StatusCreated)
+	ja.Assertf(body, fmt.Sprintf(`{"id":"<<PRESENCE>>", "kind":"reversal", "original_transfer_id":%q, "account_origin_id":%q, "account_destination_id":%q, "currency":"BRL",
+		"amount":20, "created_at":"<<PRESENCE>>"}`, toReverseID, destinationID, originID))
+
+	body = doRequest(http.MethodGet, "/transfers?direction=sent", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"transfers":[
+		{"id":%q, "kind":"transfer", "account_origin_id":%q, "account_destination_id":%q, "currency":"BRL", "amount":20, "refunded_amount":20, "created_at":"<<PRESENCE>>"},
+		{"id":%q, "kind":"transfer", "account_origin_id":%q, "account_destination_id":%q, "currency":"BRL", "amount":30, "refunded_amount":30, "created_at":"<<PRESENCE>>"}
+	], "next_cursor":null}`, toReverseID, originID, destinationID, transferID, originID, destinationID))
+
+	body = doRequest(http.MethodGet, "/accounts/"+originID+"/balance", originHeader, "", http.StatusOK)
+	ja.Assertf(body, fmt.Sprintf(`{"id":%q, "currency":"BRL", "balance":100, "credit_limit":0, "available_balance":100}`, originID))
+}
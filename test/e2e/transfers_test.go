@@ -25,6 +25,69 @@ func Test_transfers_Fetch(t *testing.T) {
 
 	authSecret := "any-secret"
 
+	// twoTransfersHeader creates two accounts with one transfer to each other, the second one for 0.02,
+	// and returns the header of the first account.
+	twoTransfersHeader := func() map[string][]string {
+		id1 := uuid.NewString()
+		_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id1,
+			"Bart Simpson",
+			"34363916206",
+			"s3cr3t",
+			10000)
+		if err != nil {
+			t.Errorf("GET %s, error on building header = %v", "/transfers", err)
+		}
+
+		id2 := uuid.NewString()
+		_, err = testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
+			id2,
+			"Homer Simpson",
+			"62792172053",
+			"s3cr3t2",
+			10000)
+		if err != nil {
+			t.Errorf("GET %s, error on building header = %v", "/transfers", err)
+		}
+
+		_, err = testDbPool.Exec(context.Background(), "INSERT INTO transfers (id, account_origin_id, account_destination_id, amount, created_at) VALUES ($1, $2, $3, $4, $5)",
+			uuid.NewString(),
+			id1,
+			id2,
+			1,
+			time.Now())
+		if err != nil {
+			t.Errorf("GET %s, error on building header = %v", "/transfers", err)
+		}
+
+		_, err = testDbPool.Exec(context.Background(), "INSERT INTO transfers (id, account_origin_id, account_destination_id, amount, created_at) VALUES ($1, $2, $3, $4, $5)",
+			uuid.NewString(),
+			id2,
+			id1,
+			2,
+			time.Now())
+		if err != nil {
+			t.Errorf("GET %s, error on building header = %v", "/transfers", err)
+		}
+
+		now := time.Now()
+		accessTokenClaims := jwt.RegisteredClaims{
+			Subject:   id1,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(30 * time.Second)),
+		}
+
+		accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims)
+		accessTokenString, err := accessToken.SignedString([]byte(authSecret))
+		if err != nil {
+			t.Errorf("GET %s, error on building header = %v", "/transfers", err)
+		}
+
+		return map[string][]string{
+			"Authorization": {"Bearer " + accessTokenString},
+		}
+	}
+
 	type fields struct {
 		dbPool      *pgxpool.Pool
 		redisClient *redis.Client
@@ -131,7 +194,7 @@ func Test_transfers_Fetch(t *testing.T) {
 				},
 			},
 			wantStatus: 200,
-			want:       `[]`,
+			want:       `{"transfers":[], "next_cursor":null}`,
 			runBefore: func(args args) {
 				truncateDatabase(t)
 			},
@@ -147,73 +210,78 @@ func Test_transfers_Fetch(t *testing.T) {
 				},
 			},
 			args: args{
-				path: "/transfers",
-				header: func() map[string][]string {
-					id1 := uuid.NewString()
-					_, err := testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
-						id1,
-						"Bart Simpson",
-						"34363916206",
-						"s3cr3t",
-						10000)
-					if err != nil {
-						t.Errorf("GET %s, error on building header = %v", "/transfers", err)
-					}
-
-					id2 := uuid.NewString()
-					_, err = testDbPool.Exec(context.Background(), "INSERT INTO accounts (id, name, cpf, secret, balance) VALUES ($1, $2, $3, $4, $5)",
-						id2,
-						"Homer Simpson",
-						"62792172053",
-						"s3cr3t2",
-						10000)
-					if err != nil {
-						t.Errorf("GET %s, error on building header = %v", "/transfers", err)
-					}
-
-					_, err = testDbPool.Exec(context.Background(), "INSERT INTO transfers (id, account_origin_id, account_destination_id, amount, created_at) VALUES ($1, $2, $3, $4, $5)",
-						uuid.NewString(),
-						id1,
-						id2,
-						1,
-						time.Now())
-					if err != nil {
-						t.Errorf("GET %s, error on building header = %v", "/transfers", err)
-					}
-
-					_, err = testDbPool.Exec(context.Background(), "INSERT INTO transfers (id, account_origin_id, account_destination_id, amount, created_at) VALUES ($1, $2, $3, $4, $5)",
-						uuid.NewString(),
-						id2,
-						id1,
-						2,
-						time.Now())
-					if err != nil {
-						t.Errorf("GET %s, error on building header = %v", "/transfers", err)
-					}
-
-					now := time.Now()
-					accessTokenClaims := jwt.RegisteredClaims{
-						Subject:   id1,
-						IssuedAt:  jwt.NewNumericDate(now),
-						ExpiresAt: jwt.NewNumericDate(now.Add(30 * time.Second)),
-					}
-
-					accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims)
-					accessTokenString, err := accessToken.SignedString([]byte(authSecret))
-					if err != nil {
-						t.Errorf("GET %s, error on building header = %v", "/transfers", err)
-					}
-
-					return map[string][]string{
-						"Authorization": {"Bearer " + accessTokenString},
-					}
+				path:   "/transfers",
+				header: twoTransfersHeader,
+			},
+			wantStatus: 200,
+			want: `{"transfers":[
+					{"id": "<<PRESENCE>>", "kind": "transfer", "account_origin_id":"<<PRESENCE>>", "account_destination_id":"<<PRESENCE>>", "currency":"BRL", "amount": 0.02, "refunded_amount": 0, "created_at": "<<PRESENCE>>"},
+					{"id": "<<PRESENCE>>", "kind": "transfer", "account_origin_id":"<<PRESENCE>>", "account_destination_id":"<<PRESENCE>>", "currency":"BRL", "amount": 0.01, "refunded_amount": 0, "created_at": "<<PRESENCE>>"}
+				], "next_cursor":null}`,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
+		{
+			name: "should return the first page with the next cursor",
+			fields: fields{
+				dbPool:      testDbPool,
+				redisClient: testRedisClient,
+				authConf: config.ConfAuth{
+					SecretKey:      authSecret,
+					AccessTokenDur: 30 * time.Second,
+				},
+			},
+			args: args{
+				path:   "/transfers?limit=1",
+				header: twoTransfersHeader,
+			},
+			wantStatus: 200,
+			want: `{"transfers":[
+					{"id": "<<PRESENCE>>", "kind": "transfer", "account_origin_id":"<<PRESENCE>>", "account_destination_id":"<<PRESENCE>>", "currency":"BRL", "amount": 0.02, "refunded_amount": 0, "created_at": "<<PRESENCE>>"}
+				], "next_cursor":"<<PRESENCE>>"}`,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
+		{
+			name: "should filter the sent transfers",
+			fields: fields{
+				dbPool:      testDbPool,
+				redisClient: testRedisClient,
+				authConf: config.ConfAuth{
+					SecretKey:      authSecret,
+					AccessTokenDur: 30 * time.Second,
 				},
 			},
+			args: args{
+				path:   "/transfers?direction=sent&min_amount=0.01",
+				header: twoTransfersHeader,
+			},
 			wantStatus: 200,
-			want: `[
-					{"id": "<<PRESENCE>>", "account_origin_id":"<<PRESENCE>>", "account_destination_id":"<<PRESENCE>>", "amount": 0.02, "created_at": "<<PRESENCE>>"},
-					{"id": "<<PRESENCE>>", "account_origin_id":"<<PRESENCE>>", "account_destination_id":"<<PRESENCE>>", "amount": 0.01, "created_at": "<<PRESENCE>>"}
-				]`,
+			want: `{"transfers":[
+					{"id": "<<PRESENCE>>", "kind": "transfer", "account_origin_id":"<<PRESENCE>>", "account_destination_id":"<<PRESENCE>>", "currency":"BRL", "amount": 0.01, "refunded_amount": 0, "created_at": "<<PRESENCE>>"}
+				], "next_cursor":null}`,
+			runBefore: func(args args) {
+				truncateDatabase(t)
+			},
+		},
+		{
+			name: "invalid cursor should return error",
+			fields: fields{
+				dbPool:      testDbPool,
+				redisClient: testRedisClient,
+				authConf: config.ConfAuth{
+					SecretKey:      authSecret,
+					AccessTokenDur: 30 * time.Second,
+				},
+			},
+			args: args{
+				path:   "/transfers?cursor=any-cursor",
+				header: twoTransfersHeader,
+			},
+			wantStatus: 400,
+			want:       `{"code":400,"message":"'cursor' is not valid"}`,
 			runBefore: func(args args) {
 				truncateDatabase(t)
 			},
@@ -225,7 +293,7 @@ func Test_transfers_Fetch(t *testing.T) {
 				tt.runBefore(tt.args)
 			}
 
-			ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf))
+			ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf, httpGateway.HandlerOptions{}))
 			defer ts.Close()
 
 			req, err := http.NewRequest(http.MethodGet, ts.URL+tt.args.path, nil)
@@ -378,7 +446,7 @@ func Test_transfers_Create(t *testing.T) {
 				},
 			},
 			wantStatus: 201,
-			want:       `{"id": "<<PRESENCE>>", "account_origin_id":"<<PRESENCE>>", "account_destination_id":"<<PRESENCE>>", "amount": 0.25, "created_at": "<<PRESENCE>>"}`,
+			want:       `{"id": "<<PRESENCE>>", "kind": "transfer", "account_origin_id":"<<PRESENCE>>", "account_destination_id":"<<PRESENCE>>", "currency":"BRL", "amount": 0.25, "refunded_amount": 0, "created_at": "<<PRESENCE>>"}`,
 			runBefore: func(args args) {
 				truncateDatabase(t)
 			},
@@ -724,7 +792,7 @@ func Test_transfers_Create(t *testing.T) {
 				tt.runBefore(tt.args)
 			}
 
-			ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf))
+			ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf, httpGateway.HandlerOptions{}))
 			defer ts.Close()
 
 			reqHeader, reqBody := tt.args.headerAndBody()
@@ -837,7 +905,7 @@ func Test_transfers_Create_Idempotent(t *testing.T) {
 				},
 			},
 			wantStatus: 201,
-			want:       `{"id": "<<PRESENCE>>", "account_origin_id":"<<PRESENCE>>", "account_destination_id":"<<PRESENCE>>", "amount": 0.25, "created_at": "<<PRESENCE>>"}`,
+			want:       `{"id": "<<PRESENCE>>", "kind": "transfer", "account_origin_id":"<<PRESENCE>>", "account_destination_id":"<<PRESENCE>>", "currency":"BRL", "amount": 0.25, "refunded_amount": 0, "created_at": "<<PRESENCE>>"}`,
 			runBefore: func(args args) {
 				truncateDatabase(t)
 			},
@@ -852,7 +920,7 @@ func Test_transfers_Create_Idempotent(t *testing.T) {
 			reqHeader, reqBody := tt.args.headerAndBody()
 
 			testReq := func(check func(*http.Response)) {
-				ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf))
+				ts := httptest.NewServer(httpGateway.GetHTTPHandler(tt.fields.dbPool, tt.fields.redisClient, tt.fields.authConf, httpGateway.HandlerOptions{}))
 				defer ts.Close()
 
 				req, err := http.NewRequest(http.MethodPost, ts.URL+tt.args.path, strings.NewReader(reqBody))